@@ -0,0 +1,9 @@
+package main
+
+// Compiled-in extensions. Each extension package registers its middlewares,
+// validators and event handlers with the extensions package from init, so
+// enabling one only takes a blank import here, e.g.
+//
+//	import _ "example.com/acme/inventory-fraudcheck"
+//
+// See internal/extensions for the hook types and ordering rules.
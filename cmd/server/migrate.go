@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/config"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  status      list migrations and whether they are applied
+  up [N]      apply the next N pending migrations, all by default
+  down [N]    revert the last N applied migrations, 1 by default`
+
+// runMigrate runs the migrate subcommand and returns the exit code
+func runMigrate(ctx context.Context, migrator *repository.Migrator, args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	steps := 0
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 || args[0] == "status" {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		steps = n
+	}
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("failed to read migration status", "error", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			name, appliedAt := status.Name, "pending"
+			if name == "" {
+				name = "(unknown to this binary)"
+			}
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, name, appliedAt)
+		}
+		w.Flush()
+		return 0
+
+	case "up":
+		applied, err := migrator.Up(ctx, steps)
+		for _, m := range applied {
+			slog.Info("migration applied", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			slog.Error("migration failed", "error", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			slog.Info("database schema is up to date")
+		}
+		return 0
+
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			slog.Info("migration reverted", "version", m.Version, "name", m.Name)
+		}
+		if err != nil {
+			slog.Error("reverting migration failed", "error", err)
+			return 1
+		}
+		return 0
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+}
+
+// checkMigrations refuses to start while migrations are pending, unless
+// autoMigrate applies them first
+func checkMigrations(ctx context.Context, migrator *repository.Migrator, autoMigrate bool) {
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		fatal("failed to check migrations", "error", err)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	versions := make([]int64, len(pending))
+	for i, m := range pending {
+		versions[i] = m.Version
+	}
+	if !autoMigrate {
+		fatal("database has pending migrations: run \"server migrate up\" or set DB_AUTO_MIGRATE=true", "pending", versions)
+	}
+
+	slog.Info("applying pending migrations", "pending", versions)
+	applied, err := migrator.Up(ctx, 0)
+	for _, m := range applied {
+		slog.Info("migration applied", "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		fatal("migration failed", "error", err)
+	}
+}
+
+// checkSchemaDrift compares the live schema with the one the migrations
+// build. It refuses to start on drift in fail mode and reports whether the
+// server must start read-only.
+func checkSchemaDrift(ctx context.Context, migrator *repository.Migrator, mode config.SchemaDriftMode) bool {
+	if mode == config.SchemaDriftOff {
+		return false
+	}
+	drift, err := migrator.Drift(ctx)
+	if err != nil {
+		fatal("failed to check the database schema", "error", err)
+	}
+	if drift.Empty() {
+		return false
+	}
+
+	report := drift.Report()
+	for _, line := range report {
+		slog.Error("schema drift", "difference", line)
+	}
+	if mode == config.SchemaDriftFail {
+		fatal("database schema differs from the migrations: fix it or set DB_SCHEMA_DRIFT=read_only", "differences", len(report))
+	}
+	slog.Warn("database schema differs from the migrations, starting read-only", "differences", len(report))
+	return true
+}
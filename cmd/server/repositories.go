@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/config"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// transactionStore is the transaction ledger, which the retention job also
+// archives and partitions
+type transactionStore interface {
+	repository.TransactionRepository
+	repository.TransactionRetentionRepository
+}
+
+// repositories are the stores of the server on the database of the
+// configured driver
+type repositories struct {
+	product        repository.ProductRepository
+	inventory      repository.InventoryRepository
+	transaction    transactionStore
+	annotation     repository.TransactionAnnotationRepository
+	serial         repository.SerialUnitRepository
+	redaction      repository.RedactionRepository
+	translation    repository.ProductTranslationRepository
+	report         repository.ReportRepository
+	warehouse      repository.WarehouseRepository
+	reservation    repository.ReservationRepository
+	cartHold       repository.CartHoldRepository
+	idempotency    repository.IdempotencyRepository
+	payloadSample  repository.PayloadSampleRepository
+	productImport  repository.ProductImportRepository
+	productArchive repository.ProductArchiveRepository
+	stocktake      repository.StocktakeRepository
+	job            repository.JobRepository
+	bulkRead       repository.BulkReadRepository
+	purchaseOrder  repository.PurchaseOrderRepository
+	stockAlert     repository.StockAlertRepository
+	usage          repository.UsageRepository
+	billing        repository.BillingRepository
+	dataQuality    repository.DataQualityRepository
+	backorder      repository.BackorderRepository
+	order          repository.OrderRepository
+	sale           repository.SaleRepository
+	wms            repository.WMSRepository
+	intercompany   repository.IntercompanyTransferRepository
+	consignment    repository.ConsignmentRepository
+	lot            repository.LotRepository
+	channel        repository.SalesChannelRepository
+	variant        repository.VariantRepository
+	background     repository.BackgroundControlRepository
+	reconciliation repository.ReconciliationRepository
+	auditManifest  repository.AuditManifestRepository
+	consistency    repository.ConsistencyRepository
+	transactor     repository.Transactor
+
+	// productLocker creates the product locks, which wait at most timeout
+	productLocker func(timeout time.Duration) repository.ProductLocker
+
+	// database names the primary database in readiness checks; ping checks it
+	database string
+	ping     func(ctx context.Context) error
+
+	// replica is the PostgreSQL read replica, if one is configured; store is
+	// the memory store of the memory driver
+	replica *repository.ReadReplica
+	store   *repository.MemoryStore
+
+	// readOnly is set when the schema drifted and DB_SCHEMA_DRIFT allows
+	// starting anyway: the server then takes no writes
+	readOnly bool
+
+	close func()
+}
+
+// openRepositories opens the database of the configured driver and the
+// repositories on it. With the postgres driver it runs the migrate
+// subcommand, and otherwise checks the schema migrations and drift.
+func openRepositories(cfg *config.Config) *repositories {
+	if cfg.Database.Driver == config.DriverMemory {
+		return openMemoryRepositories(cfg.Database)
+	}
+	return openPostgresRepositories(cfg.Database)
+}
+
+// openPostgresRepositories connects to the primary database and, if one is
+// configured, the read replica
+func openPostgresRepositories(cfg config.DatabaseConfig) *repositories {
+	slog.Info("connecting to database")
+	pool := repository.PoolConfig{
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+	}
+	db, err := repository.NewDatabase(cfg.URL, pool)
+	if err != nil {
+		fatal("failed to connect to database", "error", err)
+	}
+
+	// Schema migrations: "server migrate ..." manages them and exits; the
+	// server itself refuses to start with pending ones unless auto-migrating
+	migrator, err := db.Migrator()
+	if err != nil {
+		fatal("failed to load migrations", "error", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(context.Background(), migrator, os.Args[2:]))
+	}
+	checkMigrations(context.Background(), migrator, cfg.AutoMigrate)
+	readOnly := checkSchemaDrift(context.Background(), migrator, cfg.SchemaDrift)
+
+	// Lookups and listings go to the read replica when one is configured
+	var replica *repository.ReadReplica
+	if cfg.ReplicaURL != "" {
+		replica, err = repository.NewReadReplica(cfg.ReplicaURL, pool)
+		if err != nil {
+			fatal("failed to open read replica", "error", err)
+		}
+		slog.Info("read replica configured", "healthy", replica.Healthy())
+	}
+
+	dbConn := db.GetConnection()
+	return &repositories{
+		product:        repository.NewPostgresProductRepository(dbConn).WithReadReplica(replica),
+		inventory:      repository.NewPostgresInventoryRepository(dbConn).WithReadReplica(replica),
+		transaction:    repository.NewPostgresTransactionRepository(dbConn).WithReadReplica(replica),
+		annotation:     repository.NewPostgresTransactionAnnotationRepository(dbConn),
+		serial:         repository.NewPostgresSerialUnitRepository(dbConn),
+		redaction:      repository.NewPostgresRedactionRepository(dbConn),
+		translation:    repository.NewPostgresProductTranslationRepository(dbConn),
+		report:         repository.NewPostgresReportRepository(dbConn),
+		warehouse:      repository.NewPostgresWarehouseRepository(dbConn),
+		reservation:    repository.NewPostgresReservationRepository(dbConn),
+		cartHold:       repository.NewPostgresCartHoldRepository(dbConn),
+		idempotency:    repository.NewPostgresIdempotencyRepository(dbConn),
+		payloadSample:  repository.NewPostgresPayloadSampleRepository(dbConn),
+		productImport:  repository.NewPostgresProductImportRepository(dbConn),
+		productArchive: repository.NewPostgresProductArchiveRepository(dbConn),
+		stocktake:      repository.NewPostgresStocktakeRepository(dbConn),
+		job:            repository.NewPostgresJobRepository(dbConn),
+		bulkRead:       repository.NewPostgresBulkReadRepository(dbConn),
+		purchaseOrder:  repository.NewPostgresPurchaseOrderRepository(dbConn),
+		stockAlert:     repository.NewPostgresStockAlertRepository(dbConn),
+		usage:          repository.NewPostgresUsageRepository(dbConn),
+		billing:        repository.NewPostgresBillingRepository(dbConn),
+		dataQuality:    repository.NewPostgresDataQualityRepository(dbConn),
+		backorder:      repository.NewPostgresBackorderRepository(dbConn),
+		order:          repository.NewPostgresOrderRepository(dbConn),
+		sale:           repository.NewPostgresSaleRepository(dbConn),
+		wms:            repository.NewPostgresWMSRepository(dbConn),
+		intercompany:   repository.NewPostgresIntercompanyTransferRepository(dbConn),
+		consignment:    repository.NewPostgresConsignmentRepository(dbConn),
+		lot:            repository.NewPostgresLotRepository(dbConn),
+		channel:        repository.NewPostgresSalesChannelRepository(dbConn),
+		variant:        repository.NewPostgresVariantRepository(dbConn),
+		background:     repository.NewPostgresBackgroundControlRepository(dbConn),
+		reconciliation: repository.NewPostgresReconciliationRepository(dbConn),
+		auditManifest:  repository.NewPostgresAuditManifestRepository(dbConn),
+		consistency:    repository.NewPostgresConsistencyRepository(dbConn),
+		transactor:     repository.NewPostgresTransactor(dbConn),
+		productLocker: func(timeout time.Duration) repository.ProductLocker {
+			return repository.NewPostgresProductLocker(dbConn, timeout)
+		},
+		database: "postgres",
+		ping:     dbConn.PingContext,
+		replica:  replica,
+		readOnly: readOnly,
+		close: func() {
+			if replica != nil {
+				replica.Close()
+			}
+			db.Close()
+		},
+	}
+}
+
+// openMemoryRepositories opens the memory store, loading the data file if
+// one is configured. There are no migrations to run or check.
+func openMemoryRepositories(cfg config.DatabaseConfig) *repositories {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		fatal("schema migrations require the postgres database driver")
+	}
+
+	store, err := repository.OpenMemoryStore(cfg.DataFile)
+	if err != nil {
+		fatal("failed to open memory store", "error", err)
+	}
+	slog.Warn("using the memory database driver, for development and tests only", "data_file", cfg.DataFile)
+
+	return &repositories{
+		product:        repository.NewMemoryProductRepository(store),
+		inventory:      repository.NewMemoryInventoryRepository(store),
+		transaction:    repository.NewMemoryTransactionRepository(store),
+		annotation:     repository.NewMemoryTransactionAnnotationRepository(store),
+		serial:         repository.NewMemorySerialUnitRepository(store),
+		redaction:      repository.NewMemoryRedactionRepository(store),
+		translation:    repository.NewMemoryProductTranslationRepository(store),
+		report:         repository.NewMemoryReportRepository(store),
+		warehouse:      repository.NewMemoryWarehouseRepository(store),
+		reservation:    repository.NewMemoryReservationRepository(store),
+		cartHold:       repository.NewMemoryCartHoldRepository(store),
+		idempotency:    repository.NewMemoryIdempotencyRepository(store),
+		payloadSample:  repository.NewMemoryPayloadSampleRepository(store),
+		productImport:  repository.NewMemoryProductImportRepository(store),
+		productArchive: repository.NewMemoryProductArchiveRepository(store),
+		stocktake:      repository.NewMemoryStocktakeRepository(store),
+		job:            repository.NewMemoryJobRepository(store),
+		bulkRead:       repository.NewMemoryBulkReadRepository(store),
+		purchaseOrder:  repository.NewMemoryPurchaseOrderRepository(store),
+		stockAlert:     repository.NewMemoryStockAlertRepository(store),
+		usage:          repository.NewMemoryUsageRepository(store),
+		billing:        repository.NewMemoryBillingRepository(store),
+		dataQuality:    repository.NewMemoryDataQualityRepository(store),
+		backorder:      repository.NewMemoryBackorderRepository(store),
+		order:          repository.NewMemoryOrderRepository(store),
+		sale:           repository.NewMemorySaleRepository(store),
+		wms:            repository.NewMemoryWMSRepository(store),
+		intercompany:   repository.NewMemoryIntercompanyTransferRepository(store),
+		consignment:    repository.NewMemoryConsignmentRepository(store),
+		lot:            repository.NewMemoryLotRepository(store),
+		channel:        repository.NewMemorySalesChannelRepository(store),
+		variant:        repository.NewMemoryVariantRepository(store),
+		background:     repository.NewMemoryBackgroundControlRepository(store),
+		reconciliation: repository.NewMemoryReconciliationRepository(store),
+		auditManifest:  repository.NewMemoryAuditManifestRepository(store),
+		consistency:    repository.NewMemoryConsistencyRepository(store),
+		transactor:     store,
+		productLocker: func(time.Duration) repository.ProductLocker {
+			return repository.NewMemoryProductLocker()
+		},
+		database: "memory",
+		ping:     store.Ping,
+		store:    store,
+		close: func() {
+			if err := store.Close(); err != nil {
+				slog.Error("failed to save memory store", "error", err)
+			}
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// AuditHandler handles audit export requests
+type AuditHandler struct {
+	auditService *service.AuditService
+	jobService   *service.JobService
+}
+
+// NewAuditHandler creates a new AuditHandler. Without a job service exports
+// always run synchronously.
+func NewAuditHandler(auditService *service.AuditService, jobService *service.JobService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		jobService:   jobService,
+	}
+}
+
+// ExportLedgerHandler streams the signed ledger export for a period as CSV.
+// The manifest covers the whole file, so its ID, digest, row count and
+// signature follow the body as HTTP trailers. With "Prefer: respond-async" the export
+// runs as a background job whose summary is the manifest.
+func (h *AuditHandler) ExportLedgerHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	if h.jobService != nil && wantsAsync(r) {
+		startJob(w, r, h.jobService, domain.JobAuditExport, h.auditService.ExportJob(from, to))
+		return
+	}
+
+	// Exports of long periods outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.WarnContext(r.Context(), "failed to lift write deadline for ledger export", "error", err)
+	}
+
+	filename := fmt.Sprintf("ledger_%s_%s.csv", from.UTC().Format("20060102T150405Z"), to.UTC().Format("20060102T150405Z"))
+	export := &ledgerExportWriter{w: w, filename: filename}
+	manifest, err := h.auditService.StreamLedger(r.Context(), from, to, export, nil)
+	if err == nil {
+		err = export.flush()
+	}
+	if err != nil {
+		if !export.started {
+			writeServiceError(w, err, http.StatusInternalServerError, "EXPORT_FAILED")
+			return
+		}
+		slog.ErrorContext(r.Context(), "ledger export failed", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	w.Header().Set("X-Audit-Export-ID", manifest.ID)
+	w.Header().Set("X-Audit-SHA256", manifest.SHA256)
+	w.Header().Set("X-Audit-Row-Count", fmt.Sprint(manifest.RowCount))
+	w.Header().Set("X-Audit-Generated-At", manifest.GeneratedAt.Format(time.RFC3339Nano))
+	w.Header().Set("X-Audit-Signature", manifest.Signature)
+}
+
+// ledgerExportWriter writes a ledger export to the response through a
+// buffer. The response is only started with the first write, so an export
+// refused or failing before then can still answer with an error.
+type ledgerExportWriter struct {
+	w        http.ResponseWriter
+	filename string
+	started  bool
+	buf      *bufio.Writer
+}
+
+// Write starts the response, declaring the manifest trailers, on first use
+func (e *ledgerExportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		header := e.w.Header()
+		header.Set("Content-Type", "text/csv")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename))
+		header.Set("Trailer", "X-Audit-Export-ID, X-Audit-SHA256, X-Audit-Row-Count, X-Audit-Generated-At, X-Audit-Signature")
+		e.w.WriteHeader(http.StatusOK)
+		e.buf = bufio.NewWriterSize(e.w, 64*1024)
+	}
+	return e.buf.Write(p)
+}
+
+// flush writes out what is buffered
+func (e *ledgerExportWriter) flush() error {
+	if e.buf == nil {
+		return nil
+	}
+	return e.buf.Flush()
+}
+
+// ExportManifestHandler returns the signed manifest stored when the export
+// with the ID of the path was made, as a detached document
+func (h *AuditHandler) ExportManifestHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	manifest, err := h.auditService.Manifest(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Export manifest retrieved successfully", manifest)
+}
+
+// PublicKeyHandler returns the public key used to verify export signatures
+func (h *AuditHandler) PublicKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Public key retrieved successfully", map[string]string{
+		"algorithm":  "ed25519",
+		"public_key": base64.StdEncoding.EncodeToString(h.auditService.PublicKey()),
+	})
+}
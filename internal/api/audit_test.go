@@ -0,0 +1,108 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestExportLedgerHandlerSendsManifestAsTrailers(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 1500 {
+		id := fmt.Sprintf("tx-%04d", i)
+		transactionRepo.transactions[id] = &domain.Transaction{
+			ID: id, InventoryID: "inv-1", ProductID: "prod-1", Type: "IN", Quantity: 1, CreatedAt: from.Add(time.Duration(i) * time.Second),
+		}
+	}
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	store, err := repository.OpenMemoryStore("")
+	if err != nil {
+		t.Fatalf("failed to open memory store: %v", err)
+	}
+	manifestRepo := repository.NewMemoryAuditManifestRepository(store)
+	auditService := service.NewAuditService(transactionRepo, manifestRepo, key, 2000)
+	handler := NewAuditHandler(auditService, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/audit/export", handler.ExportLedgerHandler)
+	mux.HandleFunc("GET /api/audit/export/{id}/manifest", handler.ExportManifestHandler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/audit/export?from=2024-01-01&to=2024-01-31")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/csv" {
+		t.Fatalf("Expected a CSV export, got %d %v: %v", resp.StatusCode, resp.Header, err)
+	}
+
+	rows, _ := strconv.Atoi(resp.Trailer.Get("X-Audit-Row-Count"))
+	manifest := &domain.AuditManifest{
+		From:        from,
+		To:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		RowCount:    rows,
+		SHA256:      resp.Trailer.Get("X-Audit-SHA256"),
+		Signature:   resp.Trailer.Get("X-Audit-Signature"),
+		GeneratedAt: parseTrailerTime(t, resp.Trailer.Get("X-Audit-Generated-At")),
+	}
+	if rows != 1500 {
+		t.Errorf("Expected 1500 rows in the trailers, got %v", resp.Trailer)
+	}
+	if err := service.VerifyExport(body, manifest, auditService.PublicKey()); err != nil {
+		t.Errorf("Expected the streamed export to verify against its trailers, got %v", err)
+	}
+
+	// The detached manifest is the one stored with the export, so the
+	// downloaded file verifies against it too
+	resp, err = http.Get(server.URL + "/api/audit/export/" + resp.Trailer.Get("X-Audit-Export-ID") + "/manifest")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var detached struct {
+		Data domain.AuditManifest `json:"data"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&detached)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the stored manifest, got %d: %v", resp.StatusCode, err)
+	}
+	if err := service.VerifyExport(body, &detached.Data, auditService.PublicKey()); err != nil {
+		t.Errorf("Expected the export to verify against the detached manifest, got %v", err)
+	}
+	if detached.Data.Signature != manifest.Signature {
+		t.Errorf("Expected the detached manifest to be the one sent with the export")
+	}
+
+	// Over the row limit the export is refused before it starts
+	limited := service.NewAuditService(transactionRepo, manifestRepo, key, 1000)
+	req := httptest.NewRequest(http.MethodGet, "/api/audit/export?from=2024-01-01&to=2024-01-31", nil)
+	w := httptest.NewRecorder()
+	NewAuditHandler(limited, nil).ExportLedgerHandler(w, req)
+	var errResp ErrorResponse
+	if w.Code != http.StatusBadRequest || json.Unmarshal(w.Body.Bytes(), &errResp) != nil || errResp.Error != "VALIDATION_FAILED" {
+		t.Errorf("Expected 400 VALIDATION_FAILED, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func parseTrailerTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatalf("Invalid X-Audit-Generated-At trailer %q: %v", value, err)
+	}
+	return parsed
+}
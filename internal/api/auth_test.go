@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestRequireRole(t *testing.T) {
+	auth, err := service.NewAuthService([]service.APIKey{
+		{Name: "dashboard", Role: domain.RoleReader, Key: "reader-key"},
+		{Name: "erp", Role: domain.RoleOperator, Key: "operator-key"},
+		{Name: "root", Role: domain.RoleAdmin, Key: "admin-key"},
+		{Name: "ACME", Role: domain.RoleSupplier, Key: "supplier-key"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		WriteSuccess(w, http.StatusOK, "ok", RequestPrincipal(r.Context()))
+	}
+	handler := AuthMiddleware(auth)(RequireRole(auth, domain.RoleOperator, ok))
+
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantStatus int
+	}{
+		{"No credentials", "", "", http.StatusUnauthorized},
+		{"Unknown key", "X-API-Key", "nope", http.StatusUnauthorized},
+		{"Insufficient role", "X-API-Key", "reader-key", http.StatusForbidden},
+		{"API key header", "X-API-Key", "operator-key", http.StatusOK},
+		{"API key as bearer token", "Authorization", "Bearer operator-key", http.StatusOK},
+		{"Supplier role", "X-API-Key", "supplier-key", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/sku/LAP001/stock/add", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireSupplierRole(t *testing.T) {
+	auth, err := service.NewAuthService([]service.APIKey{
+		{Name: "root", Role: domain.RoleAdmin, Key: "admin-key"},
+		{Name: "ACME", Role: domain.RoleSupplier, Key: "supplier-key"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+	handler := AuthMiddleware(auth)(RequireRole(auth, domain.RoleSupplier, func(w http.ResponseWriter, r *http.Request) {
+		supplier, ok := requestSupplier(w, r)
+		if ok {
+			WriteSuccess(w, http.StatusOK, "ok", supplier)
+		}
+	}))
+
+	// The supplier role is not part of the role order: admins do not get it
+	for key, want := range map[string]int{"admin-key": http.StatusForbidden, "supplier-key": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "/api/supplier/purchase-orders", nil)
+		req.Header.Set("X-API-Key", key)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != want {
+			t.Errorf("%s: expected status %d, got %d: %s", key, want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestRequireRoleDisabled(t *testing.T) {
+	auth, _ := service.NewAuthService(nil, nil)
+	handler := AuthMiddleware(auth)(RequireRole(auth, domain.RoleAdmin, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api/products/prod-1", nil))
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected requests to pass with authentication disabled, got %d", rr.Code)
+	}
+}
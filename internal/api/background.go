@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// BackgroundHandler handles pausing and resuming background processing
+type BackgroundHandler struct {
+	control *service.BackgroundControl
+}
+
+// NewBackgroundHandler creates a new BackgroundHandler
+func NewBackgroundHandler(control *service.BackgroundControl) *BackgroundHandler {
+	return &BackgroundHandler{
+		control: control,
+	}
+}
+
+// BackgroundRequest selects the background components to pause or resume.
+// No components select every component; the reason is recorded with a pause.
+type BackgroundRequest struct {
+	Components []string `json:"components"`
+	Reason     string   `json:"reason"`
+}
+
+// decodeBackgroundRequest decodes the request, an empty body selecting every
+// component
+func decodeBackgroundRequest(w http.ResponseWriter, r *http.Request) (*BackgroundRequest, bool) {
+	var req BackgroundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return nil, false
+	}
+	return &req, true
+}
+
+// StatusHandler handles reporting whether each background component is paused
+func (h *BackgroundHandler) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	statuses, err := h.control.Status(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Background status retrieved successfully", statuses)
+}
+
+// PauseHandler handles pausing background components
+func (h *BackgroundHandler) PauseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	req, ok := decodeBackgroundRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var pausedBy string
+	if principal := RequestPrincipal(r.Context()); principal != nil {
+		pausedBy = principal.Subject
+	}
+
+	statuses, err := h.control.Pause(r.Context(), req.Components, pausedBy, req.Reason)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "PAUSE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Background processing paused", statuses)
+}
+
+// ResumeHandler handles resuming background components
+func (h *BackgroundHandler) ResumeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	req, ok := decodeBackgroundRequest(w, r)
+	if !ok {
+		return
+	}
+
+	statuses, err := h.control.Resume(r.Context(), req.Components)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RESUME_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Background processing resumed", statuses)
+}
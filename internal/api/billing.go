@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// BillingHandler handles 3PL billing reports
+type BillingHandler struct {
+	billingService *service.BillingService
+}
+
+// NewBillingHandler creates a new BillingHandler
+func NewBillingHandler(billingService *service.BillingService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+// ReportHandler reports the receipts, pallet days, picks and shipments per
+// tenant over ?from=&to= (dates, UTC, inclusive), by default the current
+// month to date; ?tenant= restricts the report to one tenant
+func (h *BillingHandler) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, ok := parseReportDays(w, query)
+	if !ok {
+		return
+	}
+
+	report, err := h.billingService.Report(r.Context(), query.Get("tenant"), from, to)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Billing report retrieved successfully", report)
+}
+
+// parseReportDays parses the ?from=&to= dates (UTC, inclusive) of a report,
+// by default the current month to date. It writes the error response and
+// reports false when a date is invalid.
+func parseReportDays(w http.ResponseWriter, query url.Values) (from, to time.Time, ok bool) {
+	to = time.Now().UTC()
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "to must be a date (YYYY-MM-DD)")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "from must be a date (YYYY-MM-DD)")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	return from, to, true
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// maxBulkReadBodySize bounds the size of a bulk read request
+const maxBulkReadBodySize = 4 << 20
+
+// BulkReadHandler handles bulk reads of internal services
+type BulkReadHandler struct {
+	bulkReadService *service.BulkReadService
+}
+
+// NewBulkReadHandler creates a new BulkReadHandler
+func NewBulkReadHandler(bulkReadService *service.BulkReadService) *BulkReadHandler {
+	return &BulkReadHandler{
+		bulkReadService: bulkReadService,
+	}
+}
+
+// BulkAvailabilityRequest represents a request for the availability of many SKUs
+type BulkAvailabilityRequest struct {
+	SKUs []string `json:"skus"`
+}
+
+// AvailabilityHandler handles reading the availability of up to
+// domain.MaxBulkReadSKUs SKUs at once
+func (h *BulkReadHandler) AvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req BulkAvailabilityRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBulkReadBodySize)).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	availability, err := h.bulkReadService.Availability(r.Context(), req.SKUs)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Availability retrieved successfully", availability)
+}
+
+// productExportColumns is the header row of a CSV catalog export
+var productExportColumns = []string{
+	"product_id", "sku", "name", "category", "price", "archived", "updated_at",
+	"location", "condition", "quantity", "reserved", "available",
+}
+
+// ExportProductsHandler handles streaming the catalog with its stock levels
+// as CSV, one row per product and location, or as NDJSON, one product per
+// line. category, updated_since and include_archived filter the products;
+// the response is gzipped for clients that accept it. The export is written
+// as it is read, so a failure after the first rows aborts the response
+// instead of ending it early.
+func (h *BulkReadHandler) ExportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	format := strings.ToLower(query.Get("format"))
+	switch format {
+	case "":
+		format = "ndjson"
+	case "csv", "ndjson":
+	default:
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "format must be csv or ndjson")
+		return
+	}
+
+	filter := repository.ProductExportFilter{Category: query.Get("category")}
+	if value := query.Get("include_archived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "include_archived must be true or false")
+			return
+		}
+		filter.IncludeArchived = includeArchived
+	}
+	if value := query.Get("updated_since"); value != "" {
+		since, err := parseTimeParam(value, ReportingLocation(r.Context()), false)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		filter.UpdatedSince = since
+	}
+
+	// Exports of large catalogs outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.WarnContext(r.Context(), "failed to lift write deadline for product export", "error", err)
+	}
+
+	export := &productExportWriter{w: w, csv: format == "csv", gzip: acceptsGzip(r)}
+	err := h.bulkReadService.ExportProducts(r.Context(), filter, export.write)
+	if err == nil {
+		err = export.close()
+	}
+	if err != nil {
+		if !export.started {
+			writeServiceError(w, err, http.StatusInternalServerError, "EXPORT_FAILED")
+			return
+		}
+		slog.ErrorContext(r.Context(), "product export failed", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// productExportWriter writes the products of an export in its format. The
+// response is only started with the first product, so an export failing
+// before then can still answer with an error.
+type productExportWriter struct {
+	w       http.ResponseWriter
+	csv     bool
+	gzip    bool
+	started bool
+
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+	csvOut  *csv.Writer
+	jsonOut *json.Encoder
+}
+
+// start sets the response headers and, for CSV, writes the header row
+func (e *productExportWriter) start() error {
+	e.started = true
+	header := e.w.Header()
+	header.Add("Vary", "Accept-Encoding")
+
+	var out io.Writer = e.w
+	if e.gzip {
+		header.Set("Content-Encoding", "gzip")
+		e.gz = gzip.NewWriter(e.w)
+		out = e.gz
+	}
+	e.buf = bufio.NewWriter(out)
+
+	if !e.csv {
+		header.Set("Content-Type", "application/x-ndjson")
+		e.jsonOut = json.NewEncoder(e.buf)
+		return nil
+	}
+	header.Set("Content-Type", "text/csv")
+	header.Set("Content-Disposition", `attachment; filename="products.csv"`)
+	e.csvOut = csv.NewWriter(e.buf)
+	return e.csvOut.Write(productExportColumns)
+}
+
+// write writes one product: one CSV row per location, or one NDJSON line
+func (e *productExportWriter) write(product *domain.ProductExport) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if !e.csv {
+		return e.jsonOut.Encode(product)
+	}
+	for _, record := range productExportRecords(product) {
+		if err := e.csvOut.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close writes out everything still buffered. An export without products
+// is a CSV header row or an empty NDJSON body.
+func (e *productExportWriter) close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if e.csvOut != nil {
+		e.csvOut.Flush()
+		if err := e.csvOut.Error(); err != nil {
+			return err
+		}
+	}
+	if err := e.buf.Flush(); err != nil {
+		return err
+	}
+	if e.gz != nil {
+		return e.gz.Close()
+	}
+	return nil
+}
+
+// productExportRecords returns the CSV rows of an exported product, one per
+// location, or a single row with empty stock columns when it has no stock
+func productExportRecords(product *domain.ProductExport) [][]string {
+	base := []string{
+		product.ProductID, product.SKU, product.Name, product.Category,
+		strconv.FormatFloat(product.Price, 'f', -1, 64), strconv.FormatBool(product.Archived),
+		product.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(product.Locations) == 0 {
+		return [][]string{append(base, "", "", "", "", "")}
+	}
+
+	records := make([][]string, 0, len(product.Locations))
+	for _, stock := range product.Locations {
+		records = append(records, append(slices.Clone(base),
+			stock.Location, string(stock.Condition),
+			strconv.FormatInt(stock.Quantity, 10), strconv.FormatInt(stock.Reserved, 10), strconv.FormatInt(stock.Available, 10),
+		))
+	}
+	return records
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+				continue
+			}
+			if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				q, err := strconv.ParseFloat(value, 64)
+				return err == nil && q > 0
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// MockBulkReadRepository implements BulkReadRepository interface for testing
+type MockBulkReadRepository struct {
+	products []*domain.ProductExport
+	err      error
+}
+
+func (m *MockBulkReadRepository) AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error) {
+	return nil, m.err
+}
+
+func (m *MockBulkReadRepository) ExportProducts(ctx context.Context, filter repository.ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error) {
+	var page []*domain.ProductExport
+	for _, product := range m.products {
+		if product.ProductID > afterID && (filter.IncludeArchived || !product.Archived) && len(page) < limit {
+			page = append(page, product)
+		}
+	}
+	return page, m.err
+}
+
+func TestExportProductsHandler(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	laptop := &domain.ProductExport{ProductID: "prod-1", SKU: "LAP001", Name: "Laptop, 15\"", Price: 999.5, UpdatedAt: updatedAt}
+	laptop.AddStock(&domain.ProductExportStock{Location: "WH-A", Condition: domain.ConditionNew, Quantity: 10, Reserved: 4, Available: 6})
+	laptop.AddStock(&domain.ProductExportStock{Location: "WH-B", Condition: domain.ConditionNew, Quantity: 5, Available: 5})
+	repo := &MockBulkReadRepository{products: []*domain.ProductExport{
+		laptop,
+		{ProductID: "prod-2", SKU: "MOU001", Name: "Mouse", UpdatedAt: updatedAt, Locations: []*domain.ProductExportStock{}},
+		{ProductID: "prod-3", SKU: "OLD001", Name: "Retired", Archived: true, UpdatedAt: updatedAt, Locations: []*domain.ProductExportStock{}},
+	}}
+	handler := NewBulkReadHandler(service.NewBulkReadService(repo, 1))
+
+	t.Run("CSV gzipped", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/products/export?format=csv", nil)
+		req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.5")
+		w := httptest.NewRecorder()
+		handler.ExportProductsHandler(w, req)
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Content-Type") != "text/csv" {
+			t.Fatalf("Expected a gzipped CSV, got %d %v", w.Code, w.Header())
+		}
+		body, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip body: %v", err)
+		}
+		records, err := csv.NewReader(body).ReadAll()
+		if err != nil {
+			t.Fatalf("Invalid CSV body: %v", err)
+		}
+		if len(records) != 4 || len(records[0]) != len(productExportColumns) {
+			t.Fatalf("Expected a header and 3 rows, got %q", records)
+		}
+		if got := records[1]; got[2] != "Laptop, 15\"" || got[4] != "999.5" || got[6] != "2024-03-01T12:00:00Z" || got[7] != "WH-A" || got[11] != "6" {
+			t.Errorf("Unexpected first row: %q", got)
+		}
+		if got := records[3]; got[0] != "prod-2" || got[7] != "" || got[9] != "" {
+			t.Errorf("Expected an empty stock row for the product without stock, got %q", got)
+		}
+	})
+
+	t.Run("NDJSON with archived products", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/products/export?include_archived=true", nil)
+		w := httptest.NewRecorder()
+		handler.ExportProductsHandler(w, req)
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Encoding") != "" || w.Header().Get("Content-Type") != "application/x-ndjson" {
+			t.Fatalf("Expected plain NDJSON, got %d %v", w.Code, w.Header())
+		}
+		var lines []domain.ProductExport
+		for scanner := bufio.NewScanner(w.Body); scanner.Scan(); {
+			var product domain.ProductExport
+			if err := json.Unmarshal(scanner.Bytes(), &product); err != nil {
+				t.Fatalf("Invalid NDJSON line %q: %v", scanner.Text(), err)
+			}
+			lines = append(lines, product)
+		}
+		if len(lines) != 3 || lines[0].Available != 11 || len(lines[0].Locations) != 2 || !lines[2].Archived {
+			t.Errorf("Unexpected export: %+v", lines)
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ExportProductsHandler(w, httptest.NewRequest(http.MethodGet, "/api/products/export?format=xml", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Failure before the first product", func(t *testing.T) {
+		failing := NewBulkReadHandler(service.NewBulkReadService(&MockBulkReadRepository{err: errors.New("database down")}, 1))
+		w := httptest.NewRecorder()
+		failing.ExportProductsHandler(w, httptest.NewRequest(http.MethodGet, "/api/products/export", nil))
+		if w.Code != http.StatusInternalServerError || w.Header().Get("Content-Type") == "application/x-ndjson" {
+			t.Errorf("Expected a 500 error response, got %d %v", w.Code, w.Header())
+		}
+	})
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                   false,
+		"gzip":               true,
+		"deflate, GZIP":      true,
+		"gzip;q=0":           false,
+		"gzip; q=0.05, br":   true,
+		"br, identity;q=0.5": false,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("Accept-Encoding", header)
+		}
+		if got := acceptsGzip(req); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+// generatedCatalog serves products made up from the cursor, so that exports
+// of any size can run without holding the catalog in memory
+type generatedCatalog struct {
+	products int
+}
+
+func (g *generatedCatalog) AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error) {
+	return nil, nil
+}
+
+func (g *generatedCatalog) ExportProducts(ctx context.Context, filter repository.ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error) {
+	next := 0
+	if afterID != "" {
+		fmt.Sscanf(afterID, "prod-%d", &next)
+		next++
+	}
+	page := make([]*domain.ProductExport, 0, limit)
+	for i := next; i < g.products && len(page) < limit; i++ {
+		product := &domain.ProductExport{ProductID: fmt.Sprintf("prod-%09d", i), SKU: fmt.Sprintf("SKU%09d", i), Name: "Product", Price: 9.99}
+		product.AddStock(&domain.ProductExportStock{Location: "WH-A", Condition: domain.ConditionNew, Quantity: 10, Available: 10})
+		page = append(page, product)
+	}
+	return page, nil
+}
+
+// discardResponseWriter drops the response body, sampling the heap every
+// thousand writes
+type discardResponseWriter struct {
+	header http.Header
+	writes int
+	heap   *heapPeak
+}
+
+func (w *discardResponseWriter) Header() http.Header { return w.header }
+func (w *discardResponseWriter) WriteHeader(int)     {}
+func (w *discardResponseWriter) Write(p []byte) (int, error) {
+	if w.writes++; w.writes%1000 == 0 {
+		w.heap.sample()
+	}
+	return len(p), nil
+}
+
+// heapPeak samples the heap in use while a benchmark runs and reports the
+// most it grew over the heap at the start
+type heapPeak struct {
+	base, peak uint64
+}
+
+func newHeapPeak() *heapPeak {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return &heapPeak{base: stats.HeapInuse}
+}
+
+func (h *heapPeak) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	h.peak = max(h.peak, stats.HeapInuse)
+}
+
+func (h *heapPeak) report(b *testing.B) {
+	b.ReportMetric(float64(max(h.peak, h.base)-h.base)/(1<<20), "peak-heap-MiB")
+}
+
+// BenchmarkExportProductsMillionRows exports a million products in each
+// format. The peak heap stays at a few pages however large the catalog.
+func BenchmarkExportProductsMillionRows(b *testing.B) {
+	handler := NewBulkReadHandler(service.NewBulkReadService(&generatedCatalog{products: 1_000_000}, 1))
+	for _, format := range []struct{ name, query, encoding string }{
+		{"csv", "csv", ""},
+		{"ndjson", "ndjson", ""},
+		{"ndjson-gzip", "ndjson", "gzip"},
+	} {
+		b.Run(format.name, func(b *testing.B) {
+			heap := newHeapPeak()
+			for b.Loop() {
+				req := httptest.NewRequest(http.MethodGet, "/api/products/export?format="+format.query, nil)
+				req.Header.Set("Accept-Encoding", format.encoding)
+				handler.ExportProductsHandler(&discardResponseWriter{header: http.Header{}, heap: heap}, req)
+			}
+			heap.report(b)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// CartHoldHandler handles checkout cart hold requests
+type CartHoldHandler struct {
+	holdService *service.CartHoldService
+}
+
+// NewCartHoldHandler creates a new CartHoldHandler
+func NewCartHoldHandler(holdService *service.CartHoldService) *CartHoldHandler {
+	return &CartHoldHandler{
+		holdService: holdService,
+	}
+}
+
+// CreateCartHoldRequest represents a cart hold request
+type CreateCartHoldRequest struct {
+	Reference  string                `json:"reference"`
+	Lines      []domain.CartHoldLine `json:"lines"`
+	TTLSeconds int64                 `json:"ttl_seconds"`
+}
+
+// CreateCartHoldHandler handles holding the lines of a cart during checkout
+func (h *CartHoldHandler) CreateCartHoldHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateCartHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	hold, err := h.holdService.CreateHold(r.Context(), req.Reference, req.Lines, ttl)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Cart hold created successfully", hold)
+}
+
+// GetCartHoldHandler handles retrieving a cart hold
+func (h *CartHoldHandler) GetCartHoldHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	hold, err := h.holdService.GetHold(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Cart hold retrieved successfully", hold)
+}
+
+// ConvertCartHoldHandler handles turning a cart hold into reservations
+func (h *CartHoldHandler) ConvertCartHoldHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	hold, err := h.holdService.ConvertHold(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeCartHoldError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Cart hold converted successfully", hold)
+}
+
+// ReleaseCartHoldHandler handles releasing a cart hold
+func (h *CartHoldHandler) ReleaseCartHoldHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	hold, err := h.holdService.ReleaseHold(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeCartHoldError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Cart hold released successfully", hold)
+}
+
+// writeCartHoldError maps a failed cart hold transition to its response
+func writeCartHoldError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrCartHoldNotActive) {
+		WriteError(w, http.StatusConflict, "CART_HOLD_NOT_ACTIVE", err.Error())
+		return
+	}
+	writeStockOperationError(w, err)
+}
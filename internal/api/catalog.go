@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// CatalogHandler handles catalog sync requests
+type CatalogHandler struct {
+	catalogService *service.CatalogService
+	jobService     *service.JobService
+}
+
+// NewCatalogHandler creates a new CatalogHandler. Without a job service syncs
+// always run synchronously.
+func NewCatalogHandler(catalogService *service.CatalogService, jobService *service.JobService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+		jobService:     jobService,
+	}
+}
+
+// CatalogSyncRequest represents a catalog sync request: the full external
+// snapshot, whether to apply the diff and where created products are stocked
+type CatalogSyncRequest struct {
+	Products []domain.CatalogEntry `json:"products"`
+	Apply    bool                  `json:"apply"`
+	Location string                `json:"location"`
+}
+
+// SyncCatalogHandler handles diffing an external catalog snapshot against the
+// products and, on request, applying the diff. With "Prefer: respond-async"
+// the sync runs as a background job.
+func (h *CatalogHandler) SyncCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CatalogSyncRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	opts := service.CatalogSyncOptions{
+		Apply:    req.Apply,
+		Location: req.Location,
+	}
+	if h.jobService != nil && wantsAsync(r) {
+		run, err := h.catalogService.SyncJob(req.Products, opts)
+		if err != nil {
+			writeServiceError(w, err, http.StatusInternalServerError, "SYNC_FAILED")
+			return
+		}
+		startJob(w, r, h.jobService, domain.JobCatalogSync, run)
+		return
+	}
+
+	diff, err := h.catalogService.Sync(r.Context(), req.Products, opts)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "SYNC_FAILED")
+		return
+	}
+
+	message := "Catalog diff computed successfully"
+	if diff.Applied {
+		message = "Catalog synced successfully"
+	}
+	WriteSuccess(w, http.StatusOK, message, diff)
+}
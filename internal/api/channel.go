@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ChannelHandler handles sales channel requests
+type ChannelHandler struct {
+	channelService *service.ChannelService
+}
+
+// NewChannelHandler creates a new ChannelHandler
+func NewChannelHandler(channelService *service.ChannelService) *ChannelHandler {
+	return &ChannelHandler{
+		channelService: channelService,
+	}
+}
+
+// CreateChannelRequest represents a sales channel creation request
+type CreateChannelRequest struct {
+	Name string                  `json:"name"`
+	Type domain.SalesChannelType `json:"type"`
+}
+
+// ChannelAllocationRequest represents a channel allocation request; exactly
+// one of percent and quantity is set
+type ChannelAllocationRequest struct {
+	Percent  *float64 `json:"percent"`
+	Quantity *int64   `json:"quantity"`
+}
+
+// CreateChannelHandler handles creating a sales channel
+func (h *ChannelHandler) CreateChannelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateChannelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	channel := &domain.SalesChannel{Name: req.Name, Type: req.Type}
+	if err := h.channelService.CreateChannel(r.Context(), channel); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Sales channel created successfully", channel)
+}
+
+// GetChannelHandler handles retrieving a sales channel
+func (h *ChannelHandler) GetChannelHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	channel, err := h.channelService.GetChannel(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Sales channel retrieved successfully", channel)
+}
+
+// ListChannelsHandler handles listing sales channels
+func (h *ChannelHandler) ListChannelsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	channels, err := h.channelService.ListChannels(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Sales channels retrieved successfully", channels)
+}
+
+// SetAllocationHandler handles creating or replacing the allocation of a
+// product to a sales channel
+func (h *ChannelHandler) SetAllocationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PUT is allowed")
+		return
+	}
+
+	var req ChannelAllocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	allocation := &domain.ChannelAllocation{
+		ChannelID: r.PathValue("id"),
+		ProductID: r.PathValue("product_id"),
+		Percent:   req.Percent,
+		Quantity:  req.Quantity,
+	}
+	if err := h.channelService.SetAllocation(r.Context(), allocation); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel allocation saved successfully", allocation)
+}
+
+// ListAllocationsHandler handles listing the allocations of a sales channel
+func (h *ChannelHandler) ListAllocationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	allocations, err := h.channelService.ListAllocations(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel allocations retrieved successfully", allocations)
+}
+
+// DeleteAllocationHandler handles removing the allocation of a product to a
+// sales channel
+func (h *ChannelHandler) DeleteAllocationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only DELETE is allowed")
+		return
+	}
+
+	if err := h.channelService.DeleteAllocation(r.Context(), r.PathValue("id"), r.PathValue("product_id")); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "DELETE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel allocation deleted successfully", nil)
+}
+
+// ChannelAvailabilityHandler handles retrieving what a sales channel may
+// still reserve of a product
+func (h *ChannelHandler) ChannelAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	availability, err := h.channelService.Availability(r.Context(), r.PathValue("id"), r.PathValue("product_id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel availability retrieved successfully", availability)
+}
+
+// CreateChannelReservationHandler handles reserving stock for an order
+// placed through a sales channel
+func (h *ChannelHandler) CreateChannelReservationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	reservation, err := h.channelService.Reserve(r.Context(), r.PathValue("id"), req.ProductID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference, ttl)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Reservation created successfully", reservation)
+}
+
+// ListAllocationEventsHandler handles listing the allocation changes of a
+// sales channel
+func (h *ChannelHandler) ListAllocationEventsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	events, err := h.channelService.ListAllocationEvents(r.Context(), r.PathValue("id"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel allocation events retrieved successfully", events)
+}
+
+// RebalanceChannelsHandler handles running the channel rebalancing job now
+func (h *ChannelHandler) RebalanceChannelsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	result, err := h.channelService.Rebalance(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Channel buckets rebalanced successfully", result)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ConsignmentHandler handles consignment stock requests
+type ConsignmentHandler struct {
+	consignmentService *service.ConsignmentService
+}
+
+// NewConsignmentHandler creates a new ConsignmentHandler
+func NewConsignmentHandler(consignmentService *service.ConsignmentService) *ConsignmentHandler {
+	return &ConsignmentHandler{
+		consignmentService: consignmentService,
+	}
+}
+
+// ReceiveConsignmentHandler handles receiving a lot of consignment stock
+func (h *ConsignmentHandler) ReceiveConsignmentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req domain.ConsignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	consignment, err := h.consignmentService.Receive(r.Context(), req)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Consignment received successfully", consignment)
+}
+
+// GetConsignmentHandler handles retrieving a consignment lot
+func (h *ConsignmentHandler) GetConsignmentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	consignment, err := h.consignmentService.GetConsignment(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Consignment retrieved successfully", consignment)
+}
+
+// ListConsignmentsHandler handles listing consignment lots, filtered by
+// ?supplier=, ?product_id= and ?location=; ?open=true lists only the lots
+// the supplier still owns units of
+func (h *ConsignmentHandler) ListConsignmentsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := domain.ConsignmentFilter{
+		Supplier:  query.Get("supplier"),
+		ProductID: query.Get("product_id"),
+		Location:  query.Get("location"),
+	}
+	if value := query.Get("open"); value != "" {
+		open, err := strconv.ParseBool(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "open must be true or false")
+			return
+		}
+		filter.Open = open
+	}
+
+	limit, offset := parsePagination(r)
+	consignments, err := h.consignmentService.ListConsignments(r.Context(), filter, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Consignments retrieved successfully", consignments)
+}
+
+// ListTransfersHandler handles listing the ownership transfers of a
+// consignment lot
+func (h *ConsignmentHandler) ListTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	transfers, err := h.consignmentService.ListTransfers(r.Context(), r.PathValue("id"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Ownership transfers retrieved successfully", transfers)
+}
+
+// SettlementReportHandler reports what is owed to each supplier for the
+// consignment stock sold, by the payables recorded over ?from=&to= (dates,
+// UTC, inclusive), by default the current month to date; ?supplier=
+// restricts the report to one supplier
+func (h *ConsignmentHandler) SettlementReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, ok := parseReportDays(w, query)
+	if !ok {
+		return
+	}
+
+	report, err := h.consignmentService.Settlement(r.Context(), query.Get("supplier"), from, to)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Consignment settlement retrieved successfully", report)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ConsistencyTokenHeader carries read-your-writes tokens: responses to
+// successful writes include one, and reads presenting it see those writes
+const ConsistencyTokenHeader = "X-Consistency-Token"
+
+// ConsistencyMiddleware provides read-your-writes across read replicas and
+// in-process caches. Successful mutating requests (POST, PUT, PATCH, DELETE)
+// are answered with an X-Consistency-Token header; other requests presenting
+// that header are served from the primary, bypassing caches, unless the
+// replica has caught up with the token. Malformed tokens receive 400
+// INVALID_REQUEST.
+func ConsistencyMiddleware(consistency *service.ConsistencyService) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				handler.ServeHTTP(&tokenWriter{ResponseWriter: w, r: r, consistency: consistency}, r)
+				return
+			}
+
+			if token := r.Header.Get(ConsistencyTokenHeader); token != "" {
+				ctx, err := consistency.WithToken(r.Context(), token)
+				if err != nil {
+					WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+					return
+				}
+				r = r.WithContext(ctx)
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// tokenWriter adds a consistency token to a successful write response just
+// before its header is sent, once the write has committed
+type tokenWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	consistency *service.ConsistencyService
+	wroteHeader bool
+}
+
+func (w *tokenWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if status >= 200 && status < 300 {
+			w.addToken()
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *tokenWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *tokenWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// addToken sets the token header; a write whose token cannot be read is
+// still answered, without one
+func (w *tokenWriter) addToken() {
+	token, err := w.consistency.Token(w.r.Context())
+	if err != nil {
+		slog.WarnContext(w.r.Context(), "failed to issue consistency token", "error", err)
+		return
+	}
+	w.Header().Set(ConsistencyTokenHeader, token)
+}
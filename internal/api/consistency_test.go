@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// MockConsistencyRepository implements ConsistencyRepository interface for testing
+type MockConsistencyRepository struct {
+	lsn repository.LSN
+}
+
+func (m *MockConsistencyRepository) CurrentLSN(ctx context.Context) (repository.LSN, error) {
+	return m.lsn, nil
+}
+
+func TestConsistencyMiddleware(t *testing.T) {
+	status := http.StatusCreated
+	var readAfter repository.LSN
+	var hasToken bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readAfter, hasToken = repository.ReadAfter(r.Context())
+		w.WriteHeader(status)
+	})
+	handler := ConsistencyMiddleware(service.NewConsistencyService(&MockConsistencyRepository{lsn: 3<<32 | 0xA0}))(next)
+
+	// Successful writes return a token
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/inventory/prod-1/stock/add", nil))
+	token := w.Header().Get(ConsistencyTokenHeader)
+	if token != "3/A0" {
+		t.Fatalf("Expected token 3/A0, got %q", token)
+	}
+
+	// Failed writes and reads do not
+	status = http.StatusConflict
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/inventory/prod-1/stock/add", nil))
+	if got := w.Header().Get(ConsistencyTokenHeader); got != "" {
+		t.Errorf("Expected no token for a failed write, got %q", got)
+	}
+
+	status = http.StatusOK
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/inventory/prod-1", nil))
+	if got := w.Header().Get(ConsistencyTokenHeader); got != "" || hasToken {
+		t.Errorf("Expected a plain read, got token %q (read after: %v)", got, hasToken)
+	}
+
+	// Reads presenting the token must see the write
+	req := httptest.NewRequest(http.MethodGet, "/api/inventory/prod-1", nil)
+	req.Header.Set(ConsistencyTokenHeader, token)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !hasToken || readAfter != 3<<32|0xA0 {
+		t.Errorf("Expected the read to be after the token, got %d (read after %v: %v)", w.Code, readAfter, hasToken)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/inventory/prod-1", nil)
+	req.Header.Set(ConsistencyTokenHeader, "not-a-token")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed token, got %d", w.Code)
+	}
+}
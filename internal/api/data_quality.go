@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// DataQualityHandler handles data quality check requests
+type DataQualityHandler struct {
+	dataQualityService *service.DataQualityService
+}
+
+// NewDataQualityHandler creates a new DataQualityHandler
+func NewDataQualityHandler(dataQualityService *service.DataQualityService) *DataQualityHandler {
+	return &DataQualityHandler{
+		dataQualityService: dataQualityService,
+	}
+}
+
+// GetReportHandler handles retrieving the findings of the latest scheduled
+// check, running one first when none ran yet
+func (h *DataQualityHandler) GetReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	report := h.dataQualityService.Latest()
+	if report == nil {
+		var err error
+		if report, err = h.dataQualityService.Check(r.Context(), false); err != nil {
+			writeServiceError(w, err, http.StatusInternalServerError, "CHECK_FAILED")
+			return
+		}
+	}
+
+	WriteSuccess(w, http.StatusOK, "Data quality report retrieved successfully", report)
+}
+
+// CheckHandler handles running the checks now; ?fix=true also repairs the
+// fixable findings
+func (h *DataQualityHandler) CheckHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	fix := false
+	if value := r.URL.Query().Get("fix"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "fix must be true or false")
+			return
+		}
+		fix = parsed
+	}
+
+	report, err := h.dataQualityService.Check(r.Context(), fix)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CHECK_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Data quality checked successfully", report)
+}
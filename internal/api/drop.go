@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// ScheduleDropRequest represents a product drop scheduling request
+type ScheduleDropRequest struct {
+	ReleaseAt time.Time `json:"release_at"`
+}
+
+// ScheduleDropHandler handles scheduling the drop of a product
+func (h *Handler) ScheduleDropHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PUT is allowed")
+		return
+	}
+
+	var req ScheduleDropRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	product, err := h.inventoryService.ScheduleDrop(r.Context(), r.PathValue("id"), req.ReleaseAt)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product drop scheduled successfully", product)
+}
+
+// CancelDropHandler handles cancelling the drop of a product, which releases
+// its stock right away
+func (h *Handler) CancelDropHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only DELETE is allowed")
+		return
+	}
+
+	product, err := h.inventoryService.CancelDrop(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product drop cancelled successfully", product)
+}
+
+// ListDropsHandler handles listing the products with a scheduled drop
+func (h *Handler) ListDropsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	products, err := h.inventoryService.ListDrops(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product drops retrieved successfully", products)
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// writeServiceError maps an error returned by a service to its response.
+// Errors of a known category always get the same status and machine-readable
+// code; any other error is reported with the given fallback status and code.
+// Rejected quantity updates also report the stock level that rejected them,
+// and duplicate products the conflicting SKU.
+func writeServiceError(w http.ResponseWriter, err error, status int, code string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		status, code = http.StatusNotFound, "NOT_FOUND"
+	case errors.Is(err, domain.ErrValidation):
+		status, code = http.StatusBadRequest, "VALIDATION_FAILED"
+	case errors.Is(err, domain.ErrDuplicateSKU):
+		status, code = http.StatusConflict, "DUPLICATE_SKU"
+	case errors.Is(err, domain.ErrDuplicateOrder):
+		status, code = http.StatusConflict, "DUPLICATE_ORDER"
+	case errors.Is(err, domain.ErrDuplicateChannel):
+		status, code = http.StatusConflict, "DUPLICATE_CHANNEL"
+	case errors.Is(err, domain.ErrDuplicateVariant):
+		status, code = http.StatusConflict, "DUPLICATE_VARIANT"
+	case errors.Is(err, domain.ErrDuplicateShippingNotice):
+		status, code = http.StatusConflict, "DUPLICATE_SHIPPING_NOTICE"
+	case errors.Is(err, domain.ErrConflict):
+		status, code = http.StatusConflict, "CONFLICT"
+	case errors.Is(err, service.ErrNotReleased):
+		status, code = http.StatusConflict, "NOT_RELEASED"
+	case errors.Is(err, domain.ErrInsufficientStock):
+		status, code = http.StatusUnprocessableEntity, "INSUFFICIENT_STOCK"
+	case errors.Is(err, domain.ErrForbidden):
+		status, code = http.StatusForbidden, "FORBIDDEN"
+	case errors.Is(err, domain.ErrPatchTestFailed):
+		status, code = http.StatusConflict, "PATCH_TEST_FAILED"
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		status, code = http.StatusPreconditionFailed, "PRECONDITION_FAILED"
+	}
+
+	response := newErrorResponse(w.Header(), status, code, err.Error())
+	var stockErr *domain.StockUpdateError
+	if errors.As(err, &stockErr) {
+		response.Stock = stockErr
+	}
+	var skuErr *domain.DuplicateSKUError
+	if errors.As(err, &skuErr) {
+		response.SKU = skuErr.SKU
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// maxShapedErrorBody bounds how much of an error body ErrorShapingMiddleware
+// buffers; standard error responses are far smaller
+const maxShapedErrorBody = 64 << 10
+
+// ErrorShapingMiddleware is the final error layer: every non-2xx response
+// leaves as an ErrorResponse with the request ID and docsURL as
+// documentation_url. Bodies that already are one are completed; any other
+// body, such as the plain text of the router's 404 and 405 answers, is
+// replaced by one with a code derived from the status. Panics that escape
+// the inner middleware become 500 INTERNAL_ERROR, or abort the connection
+// when a success response was already under way.
+func ErrorShapingMiddleware(docsURL string) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			shaper := &errorShapingWriter{ResponseWriter: w}
+			defer func() {
+				if p := recover(); p != nil {
+					if p == http.ErrAbortHandler || shaper.passthrough {
+						panic(http.ErrAbortHandler)
+					}
+					slog.ErrorContext(r.Context(), "panic while serving request", "panic", p, "path", r.URL.Path)
+					shaper.status = http.StatusInternalServerError
+					shaper.body.Reset()
+					json.NewEncoder(&shaper.body).Encode(newErrorResponse(w.Header(), shaper.status, "INTERNAL_ERROR", "An unexpected error occurred"))
+				}
+				shaper.finish(docsURL)
+			}()
+			handler.ServeHTTP(shaper, r)
+		})
+	}
+}
+
+// errorShapingWriter passes success responses through and holds back the
+// body of error responses until the handler is done
+type errorShapingWriter struct {
+	http.ResponseWriter
+	status      int
+	passthrough bool
+	body        bytes.Buffer
+}
+
+func (s *errorShapingWriter) WriteHeader(status int) {
+	if status < 200 {
+		s.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if s.status != 0 {
+		return
+	}
+	s.status = status
+	if status < 300 || status == http.StatusNotModified {
+		s.passthrough = true
+		s.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (s *errorShapingWriter) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.WriteHeader(http.StatusOK)
+	}
+	if s.passthrough {
+		return s.ResponseWriter.Write(b)
+	}
+	if room := maxShapedErrorBody - s.body.Len(); room > 0 {
+		s.body.Write(b[:min(len(b), room)])
+	}
+	return len(b), nil
+}
+
+// Flush sends buffered data of a success response; held back error bodies
+// are only written once the handler is done
+func (s *errorShapingWriter) Flush() {
+	if s.status == 0 {
+		s.WriteHeader(http.StatusOK)
+	}
+	if s.passthrough {
+		http.NewResponseController(s.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (s *errorShapingWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// finish writes the held back error response in its standard shape
+func (s *errorShapingWriter) finish(docsURL string) {
+	if s.passthrough || s.status == 0 {
+		return
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(s.body.Bytes(), &response); err != nil || response.Error == "" {
+		message := strings.TrimSpace(s.body.String())
+		if message == "" || err == nil {
+			message = http.StatusText(s.status)
+		}
+		response = newErrorResponse(s.Header(), s.status, statusErrorCode(s.status), message)
+	}
+	response.Code = s.status
+	if response.Time == "" {
+		response.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	if response.RequestID == "" {
+		response.RequestID = s.Header().Get(RequestIDHeader)
+	}
+	response.DocumentationURL = docsURL
+
+	header := s.Header()
+	header.Set("Content-Type", "application/json")
+	header.Del("Content-Length")
+	s.ResponseWriter.WriteHeader(s.status)
+	json.NewEncoder(s.ResponseWriter).Encode(response)
+}
+
+// statusErrorCode derives an error code from an HTTP status, such as
+// METHOD_NOT_ALLOWED for 405
+func statusErrorCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "ERROR"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-':
+			return '_'
+		}
+		return -1
+	}, text)
+}
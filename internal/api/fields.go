@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// fieldSet is a sparse fieldset: the JSON fields selected at one level of a
+// response, each with the fields selected below it. A field without a
+// selection below it is returned whole.
+type fieldSet map[string]fieldSet
+
+// parseFields reads the fields query parameter, a comma-separated list of
+// JSON field paths such as product.name,inventory.quantity. It returns nil
+// when the parameter is absent.
+func parseFields(r *http.Request) (fieldSet, error) {
+	value := r.URL.Query().Get("fields")
+	if value == "" {
+		return nil, nil
+	}
+
+	fields := fieldSet{}
+	for _, path := range strings.Split(value, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		level := fields
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return nil, fmt.Errorf("invalid field path %q", path)
+			}
+			if level[name] == nil {
+				level[name] = fieldSet{}
+			}
+			level = level[name]
+		}
+	}
+	return fields, nil
+}
+
+// selectFields narrows value to the fields of the set. Structs and maps keep
+// the selected fields only, slices apply the set to every element; fields
+// are read by their JSON names and unselected fields are never encoded. path
+// is where value sits in the response, for error messages.
+func (f fieldSet) selectFields(value reflect.Value, path string) (any, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil, nil
+	}
+	if len(f) == 0 {
+		return value.Interface(), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		selected := make(map[string]any, len(f))
+		for name, below := range f {
+			field, ok := jsonField(value, name)
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q", path+name)
+			}
+			narrowed, err := below.selectFields(field, path+name+".")
+			if err != nil {
+				return nil, err
+			}
+			selected[name] = narrowed
+		}
+		return selected, nil
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		selected := make(map[string]any, len(f))
+		for name, below := range f {
+			entry := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+			if !entry.IsValid() {
+				continue
+			}
+			narrowed, err := below.selectFields(entry, path+name+".")
+			if err != nil {
+				return nil, err
+			}
+			selected[name] = narrowed
+		}
+		return selected, nil
+
+	case reflect.Slice, reflect.Array:
+		selected := make([]any, value.Len())
+		for i := range selected {
+			narrowed, err := f.selectFields(value.Index(i), path)
+			if err != nil {
+				return nil, err
+			}
+			selected[i] = narrowed
+		}
+		return selected, nil
+	}
+
+	return nil, fmt.Errorf("field %q has no fields to select", strings.TrimSuffix(path, "."))
+}
+
+// jsonField returns the exported struct field encoded under a JSON name
+func jsonField(value reflect.Value, name string) (reflect.Value, bool) {
+	typ := value.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if tag == name {
+			return value.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// writeSelected writes a success response narrowed to the fields the request
+// selects with the fields query parameter, or in full without one
+func writeSelected(w http.ResponseWriter, r *http.Request, statusCode int, message string, data any) {
+	fields, err := parseFields(r)
+	if err == nil && fields != nil {
+		data, err = fields.selectFields(reflect.ValueOf(data), "")
+	}
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_FIELDS", "Invalid fields parameter: "+err.Error())
+		return
+	}
+	WriteSuccess(w, statusCode, message, data)
+}
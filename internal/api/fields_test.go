@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestSparseFieldsets(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	invService := service.NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Laptop", SKU: "LAP001", Price: 1500}
+	if err := invService.CreateProduct(ctx, product, "WH-A", 20); err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-wh-b", ProductID: product.ID, Quantity: 5, Location: "WH-B", CreatedAt: time.Now().Add(time.Minute)})
+
+	get := func(h http.HandlerFunc, fields string) (int, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, "/?fields="+fields, nil)
+		req.SetPathValue("id", product.ID)
+		req.URL.Path = "/api/products/" + product.ID + "/inventory"
+		rr := httptest.NewRecorder()
+		h(rr, req)
+
+		var response map[string]any
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return rr.Code, response
+	}
+
+	status, response := get(handler.GetInventoryHandler, "available,locations.location")
+	if status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %v", status, response)
+	}
+	data := response["data"].(map[string]any)
+	if len(data) != 2 || data["available"] != float64(25) {
+		t.Errorf("Expected only available and locations, got %v", data)
+	}
+	locations := data["locations"].([]any)
+	if len(locations) != 2 {
+		t.Fatalf("Expected 2 locations, got %v", locations)
+	}
+	for _, location := range locations {
+		if fields := location.(map[string]any); len(fields) != 1 || fields["location"] == nil {
+			t.Errorf("Expected only the location of each inventory row, got %v", fields)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/products/"+product.ID+"?fields=product.name,inventory.quantity", nil)
+	rr := httptest.NewRecorder()
+	handler.GetProductHandler(rr, req)
+	var selected struct {
+		Data map[string]map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&selected); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(selected.Data["product"]) != 1 || selected.Data["product"]["name"] != "Laptop" || selected.Data["inventory"]["quantity"] != float64(20) {
+		t.Errorf("Unexpected product selection: %v", selected.Data)
+	}
+
+	for _, fields := range []string{"availble", "locations.bin", "available.units", "locations..location"} {
+		if status, response := get(handler.GetInventoryHandler, fields); status != http.StatusBadRequest || response["error"] != "INVALID_FIELDS" {
+			t.Errorf("fields=%s: expected 400 INVALID_FIELDS, got %d %v", fields, status, response)
+		}
+	}
+}
@@ -2,11 +2,17 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/i18n"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
 	"github.com/bhnrathore/distributed-inventory-system/internal/service"
 )
 
@@ -27,37 +33,89 @@ type CreateProductRequest struct {
 	Name            string  `json:"name"`
 	Description     string  `json:"description"`
 	SKU             string  `json:"sku"`
+	Category        string  `json:"category"`
 	Price           float64 `json:"price"`
 	Location        string  `json:"location"`
 	InitialQuantity int64   `json:"initial_quantity"`
 }
 
-// UpdateProductRequest represents a product update request
+// UpdateProductRequest represents a product update request. PUT replaces
+// every field, omitted ones included; PATCH only changes the fields given.
 type UpdateProductRequest struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Category    *string  `json:"category"`
+	Price       *float64 `json:"price"`
+}
+
+// patch returns the fields given in the request as a partial update
+func (r UpdateProductRequest) patch() domain.ProductPatch {
+	return domain.ProductPatch{Name: r.Name, Description: r.Description, Category: r.Category, Price: r.Price}
+}
+
+// ProductTranslationRequest represents a localized product name/description
+type ProductTranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AvailabilityCheckRequest represents a bulk availability check request
+type AvailabilityCheckRequest struct {
+	Items []service.AvailabilityCheck `json:"items"`
 }
 
 // StockOperationRequest represents a stock operation request
 type StockOperationRequest struct {
+	Location  string `json:"location"`
+	Condition string `json:"condition"`
 	Quantity  int64  `json:"quantity"`
 	Reference string `json:"reference"`
 	Notes     string `json:"notes"`
+
+	// UnitCost is the cost of one unit added, recorded for inventory
+	// valuation; only stock additions accept it
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+
+	// ExpectedAvailable makes removals and reservations conditional: they
+	// are only applied if at least this much stock remains available
+	// afterwards, and rejected with 412 otherwise
+	ExpectedAvailable *int64 `json:"expected_available,omitempty"`
 }
 
-// HealthHandler handles health check requests
-func (h *Handler) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
-		return
-	}
+// TransferStockRequest represents a stock transfer between two locations
+type TransferStockRequest struct {
+	FromLocation string `json:"from_location"`
+	ToLocation   string `json:"to_location"`
+	Quantity     int64  `json:"quantity"`
+	Reference    string `json:"reference"`
+}
 
-	WriteSuccess(w, http.StatusOK, "Service is healthy", map[string]string{
-		"status": "ok",
-	})
+// StockCountRequest sets the counted quantity of an inventory item; Version
+// must be the version the count was based on
+type StockCountRequest struct {
+	Quantity  int64  `json:"quantity"`
+	Version   int64  `json:"version"`
+	Reference string `json:"reference"`
+}
+
+// StockAdjustRequest sets the counted on-hand quantity of a product at a
+// location, the default location when empty
+type StockAdjustRequest struct {
+	Quantity   int64  `json:"quantity"`
+	Location   string `json:"location"`
+	ReasonCode string `json:"reason_code"`
+	Reference  string `json:"reference"`
 }
 
+// TransactionAnnotationRequest appends a note to a transaction
+type TransactionAnnotationRequest struct {
+	Note string `json:"note"`
+}
+
+// OperatorHeader names the operator adjusting stock when authentication is
+// disabled; otherwise the authenticated subject is recorded
+const OperatorHeader = "X-Operator-ID"
+
 // CreateProductHandler handles product creation
 func (h *Handler) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -75,11 +133,12 @@ func (h *Handler) CreateProductHandler(w http.ResponseWriter, r *http.Request) {
 		Name:        req.Name,
 		Description: req.Description,
 		SKU:         req.SKU,
+		Category:    req.Category,
 		Price:       req.Price,
 	}
 
 	if err := h.inventoryService.CreateProduct(r.Context(), product, req.Location, req.InitialQuantity); err != nil {
-		WriteError(w, http.StatusInternalServerError, "CREATION_FAILED", err.Error())
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
 		return
 	}
 
@@ -96,49 +155,79 @@ func (h *Handler) GetProductHandler(w http.ResponseWriter, r *http.Request) {
 	productID := strings.TrimPrefix(r.URL.Path, "/api/products/")
 	productID = strings.TrimSuffix(productID, "/")
 
+	includes, err := domain.ParseProductIncludes(r.URL.Query().Get("include"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
+		return
+	}
+
 	product, inventory, err := h.inventoryService.GetProduct(r.Context(), productID)
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+	if err := h.inventoryService.LoadIncludes(r.Context(), []*domain.Product{product}, includes); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
 		return
 	}
+	product.Localize(RequestLanguage(r.Context()))
 
 	response := map[string]interface{}{
 		"product":   product,
 		"inventory": inventory,
 	}
 
-	WriteSuccess(w, http.StatusOK, "Product retrieved successfully", response)
+	writeSelected(w, r, http.StatusOK, "Product retrieved successfully", response)
 }
 
-// ListProductsHandler handles listing products
+// ListProductsHandler handles listing products, optionally only those
+// matching a ?filter= expression over domain.ProductFilterFields
 func (h *Handler) ListProductsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
 		return
 	}
 
-	limit := 10
-	offset := 0
+	limit, offset := parsePagination(r)
 
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsedLimit, err := strconv.Atoi(l); err == nil {
-			limit = parsedLimit
+	includeArchived := false
+	if v := r.URL.Query().Get("include_archived"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "include_archived must be true or false")
+			return
 		}
+		includeArchived = parsed
 	}
 
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if parsedOffset, err := strconv.Atoi(o); err == nil {
-			offset = parsedOffset
-		}
+	includes, err := domain.ParseProductIncludes(r.URL.Query().Get("include"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
+		return
 	}
 
-	products, err := h.inventoryService.ListProducts(r.Context(), limit, offset)
+	filter, err := domain.ParseFilter(r.URL.Query().Get("filter"), domain.ProductFilterFields, ReportingLocation(r.Context()))
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "LIST_FAILED", err.Error())
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
 		return
 	}
 
-	WriteSuccess(w, http.StatusOK, "Products retrieved successfully", products)
+	products, err := h.inventoryService.ListProducts(r.Context(), limit, offset, includeArchived, filter)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+	if err := h.inventoryService.LoadIncludes(r.Context(), products.Items, includes); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	lang := RequestLanguage(r.Context())
+	for _, product := range products.Items {
+		product.Localize(lang)
+	}
+
+	writeSelected(w, r, http.StatusOK, "Products retrieved successfully", products)
 }
 
 // UpdateProductHandler handles product updates
@@ -160,17 +249,81 @@ func (h *Handler) UpdateProductHandler(w http.ResponseWriter, r *http.Request) {
 	// Get existing product
 	product, _, err := h.inventoryService.GetProduct(r.Context(), productID)
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
 		return
 	}
 
-	// Update fields
-	product.Name = req.Name
-	product.Description = req.Description
-	product.Price = req.Price
+	// Replace fields, clearing the omitted ones
+	product.Name, product.Description, product.Category, product.Price = "", "", "", 0
+	req.patch().Apply(product)
 
 	if err := h.inventoryService.UpdateProduct(r.Context(), product); err != nil {
-		WriteError(w, http.StatusInternalServerError, "UPDATE_FAILED", err.Error())
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product updated successfully", product)
+}
+
+// PatchProductHandler handles partial product updates with JSON Merge Patch
+// (RFC 7396) semantics: fields omitted from the body keep their value and
+// null clears the description or category. The name and price cannot be
+// cleared.
+func (h *Handler) PatchProductHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PATCH is allowed")
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		WriteError(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "Content-Type must be application/merge-patch+json or application/json")
+		return
+	}
+
+	productID := strings.TrimPrefix(r.URL.Path, "/api/products/")
+	productID = strings.TrimSuffix(productID, "/")
+
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	var req UpdateProductRequest
+	cleared := ""
+	for name, value := range fields {
+		var target any
+		switch name {
+		case "name":
+			target = &req.Name
+		case "description":
+			target = &req.Description
+		case "category":
+			target = &req.Category
+		case "price":
+			target = &req.Price
+		default:
+			WriteError(w, http.StatusBadRequest, "VALIDATION_FAILED", fmt.Sprintf("field %q cannot be patched (patchable: name, description, category, price)", name))
+			return
+		}
+
+		if string(value) == "null" {
+			if name == "name" || name == "price" {
+				WriteError(w, http.StatusBadRequest, "VALIDATION_FAILED", fmt.Sprintf("%s cannot be cleared", name))
+				return
+			}
+			*target.(**string) = &cleared
+			continue
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("Invalid value for %s", name))
+			return
+		}
+	}
+
+	product, err := h.inventoryService.PatchProduct(r.Context(), productID, req.patch())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
 		return
 	}
 
@@ -188,13 +341,103 @@ func (h *Handler) DeleteProductHandler(w http.ResponseWriter, r *http.Request) {
 	productID = strings.TrimSuffix(productID, "/")
 
 	if err := h.inventoryService.DeleteProduct(r.Context(), productID); err != nil {
-		WriteError(w, http.StatusInternalServerError, "DELETE_FAILED", err.Error())
+		writeServiceError(w, err, http.StatusInternalServerError, "DELETE_FAILED")
 		return
 	}
 
 	WriteSuccess(w, http.StatusOK, "Product deleted successfully", nil)
 }
 
+// RestoreProductHandler handles restoring an archived product
+func (h *Handler) RestoreProductHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	product, err := h.inventoryService.RestoreProduct(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RESTORE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product restored successfully", product)
+}
+
+// SetProductTranslationHandler handles creating or replacing a product translation
+func (h *Handler) SetProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PUT is allowed")
+		return
+	}
+
+	var req ProductTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	locale := i18n.NormalizeLocale(r.PathValue("locale"))
+	translation := domain.ProductTranslation{Name: req.Name, Description: req.Description}
+
+	if err := h.inventoryService.SetProductTranslation(r.Context(), r.PathValue("id"), locale, translation); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product translation saved successfully", map[string]interface{}{
+		"locale":      locale,
+		"translation": translation,
+	})
+}
+
+// DeleteProductTranslationHandler handles removing a product translation
+func (h *Handler) DeleteProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only DELETE is allowed")
+		return
+	}
+
+	locale := i18n.NormalizeLocale(r.PathValue("locale"))
+
+	if err := h.inventoryService.DeleteProductTranslation(r.Context(), r.PathValue("id"), locale); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "DELETE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product translation deleted successfully", nil)
+}
+
+// writeStockOperationError maps a failed stock operation to its response
+func writeStockOperationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrLocationFrozen) {
+		WriteError(w, http.StatusLocked, "LOCATION_FROZEN", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrMovementQueued) {
+		// Accepted for later: the movement is applied when the stocktake closes
+		WriteError(w, http.StatusAccepted, "LOCATION_FROZEN", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		WriteError(w, http.StatusTooManyRequests, "QUOTA_EXCEEDED", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrMovementRejected) {
+		WriteError(w, http.StatusUnprocessableEntity, "MOVEMENT_REJECTED", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrApprovalUnavailable) {
+		WriteError(w, http.StatusServiceUnavailable, "APPROVAL_UNAVAILABLE", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrLockTimeout) {
+		WriteError(w, http.StatusServiceUnavailable, "LOCK_TIMEOUT", err.Error())
+		return
+	}
+	writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+}
+
 // AddStockHandler handles adding stock
 func (h *Handler) AddStockHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -212,8 +455,8 @@ func (h *Handler) AddStockHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.inventoryService.AddStock(r.Context(), productID, req.Quantity, req.Reference); err != nil {
-		WriteError(w, http.StatusInternalServerError, "OPERATION_FAILED", err.Error())
+	if err := h.inventoryService.AddStockAtCost(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.UnitCost, req.Reference); err != nil {
+		writeStockOperationError(w, err)
 		return
 	}
 
@@ -237,8 +480,14 @@ func (h *Handler) RemoveStockHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.inventoryService.RemoveStock(r.Context(), productID, req.Quantity, req.Reference); err != nil {
-		WriteError(w, http.StatusInternalServerError, "OPERATION_FAILED", err.Error())
+	var err error
+	if req.ExpectedAvailable != nil {
+		err = h.inventoryService.RemoveStockIfAvailable(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, *req.ExpectedAvailable, req.Reference)
+	} else {
+		err = h.inventoryService.RemoveStockAt(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference)
+	}
+	if err != nil {
+		writeStockOperationError(w, err)
 		return
 	}
 
@@ -262,8 +511,14 @@ func (h *Handler) ReserveStockHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.inventoryService.ReserveStock(r.Context(), productID, req.Quantity, req.Reference); err != nil {
-		WriteError(w, http.StatusInternalServerError, "OPERATION_FAILED", err.Error())
+	var err error
+	if req.ExpectedAvailable != nil {
+		err = h.inventoryService.ReserveStockIfAvailable(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, *req.ExpectedAvailable, req.Reference)
+	} else {
+		err = h.inventoryService.ReserveStockAt(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference)
+	}
+	if err != nil {
+		writeStockOperationError(w, err)
 		return
 	}
 
@@ -287,15 +542,60 @@ func (h *Handler) UnreserveStockHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.inventoryService.UnreserveStock(r.Context(), productID, req.Quantity, req.Reference); err != nil {
-		WriteError(w, http.StatusInternalServerError, "OPERATION_FAILED", err.Error())
+	if err := h.inventoryService.UnreserveStockAt(r.Context(), productID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference); err != nil {
+		writeStockOperationError(w, err)
 		return
 	}
 
 	WriteSuccess(w, http.StatusOK, "Stock unreserved successfully", nil)
 }
 
-// GetInventoryHandler handles retrieving inventory details
+// TransferStockHandler handles moving stock between two locations
+func (h *Handler) TransferStockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req TransferStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	err := h.inventoryService.TransferStock(r.Context(), r.PathValue("id"), req.FromLocation, req.ToLocation, req.Quantity, req.Reference)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock transferred successfully", nil)
+}
+
+// CheckAvailabilityHandler handles bulk availability checks for checkout
+func (h *Handler) CheckAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req AvailabilityCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	results, err := h.inventoryService.CheckAvailability(r.Context(), req.Items)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Availability checked successfully", results)
+}
+
+// GetInventoryHandler handles retrieving the stock level of a product
+// aggregated over all locations, only in one condition with ?condition=
 func (h *Handler) GetInventoryHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
@@ -306,16 +606,237 @@ func (h *Handler) GetInventoryHandler(w http.ResponseWriter, r *http.Request) {
 	productID = strings.TrimSuffix(productID, "/inventory")
 	productID = strings.TrimSuffix(productID, "/")
 
-	inventory, err := h.inventoryService.GetInventory(r.Context(), productID)
+	level, err := h.inventoryService.GetStockLevel(r.Context(), productID, domain.StockCondition(r.URL.Query().Get("condition")))
 	if err != nil {
-		WriteError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
 		return
 	}
 
-	WriteSuccess(w, http.StatusOK, "Inventory retrieved successfully", inventory)
+	writeSelected(w, r, http.StatusOK, "Inventory retrieved successfully", level)
 }
 
-// GetTransactionsHandler handles retrieving transaction history
+// ListInventoryHandler handles listing the inventory rows of every product,
+// optionally only those matching a ?filter= expression over
+// domain.InventoryFilterFields, e.g. available < 10 AND location = "A"
+func (h *Handler) ListInventoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	filter, err := domain.ParseFilter(r.URL.Query().Get("filter"), domain.InventoryFilterFields, ReportingLocation(r.Context()))
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	inventory, err := h.inventoryService.ListInventory(r.Context(), limit, offset, filter)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	writeSelected(w, r, http.StatusOK, "Inventory retrieved successfully", inventory)
+}
+
+// GetLocationInventoryHandler handles retrieving the inventory of a product at
+// one warehouse in the ?condition= given, new stock by default
+func (h *Handler) GetLocationInventoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	inventory, err := h.inventoryService.GetInventoryAt(r.Context(), r.PathValue("id"), r.PathValue("warehouse"), domain.StockCondition(r.URL.Query().Get("condition")))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	writeSelected(w, r, http.StatusOK, "Inventory retrieved successfully", inventory)
+}
+
+// CreateLocationInventoryHandler handles stocking a product at a new warehouse,
+// or in a new condition at a warehouse
+func (h *Handler) CreateLocationInventoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req StockOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	inventory, err := h.inventoryService.CreateInventoryAt(r.Context(), r.PathValue("id"), r.PathValue("warehouse"), domain.StockCondition(req.Condition), req.Quantity)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Inventory created successfully", inventory)
+}
+
+// LocationStockHandler handles stock operations on a product at one warehouse;
+// the operation is one of add, remove, reserve or unreserve
+func (h *Handler) LocationStockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req StockOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	h.runStockOperation(w, r, r.PathValue("op"), r.PathValue("id"), r.PathValue("warehouse"), req)
+}
+
+// runStockOperation applies one of add, remove, reserve or unreserve to a
+// product at a location and writes the response
+func (h *Handler) runStockOperation(w http.ResponseWriter, r *http.Request, op, productID, location string, req StockOperationRequest) {
+	ctx := r.Context()
+	condition := domain.StockCondition(req.Condition)
+	if req.UnitCost != nil && op != "add" {
+		WriteError(w, http.StatusBadRequest, "VALIDATION_FAILED", "unit_cost can only be given when adding stock")
+		return
+	}
+	if req.ExpectedAvailable != nil && op != "remove" && op != "reserve" {
+		WriteError(w, http.StatusBadRequest, "VALIDATION_FAILED", "expected_available can only be given when removing or reserving stock")
+		return
+	}
+
+	var err error
+	var message string
+	switch op {
+	case "add":
+		message = "Stock added successfully"
+		err = h.inventoryService.AddStockAtCost(ctx, productID, location, condition, req.Quantity, req.UnitCost, req.Reference)
+	case "remove":
+		message = "Stock removed successfully"
+		if req.ExpectedAvailable != nil {
+			err = h.inventoryService.RemoveStockIfAvailable(ctx, productID, location, condition, req.Quantity, *req.ExpectedAvailable, req.Reference)
+		} else {
+			err = h.inventoryService.RemoveStockAt(ctx, productID, location, condition, req.Quantity, req.Reference)
+		}
+	case "reserve":
+		message = "Stock reserved successfully"
+		if req.ExpectedAvailable != nil {
+			err = h.inventoryService.ReserveStockIfAvailable(ctx, productID, location, condition, req.Quantity, *req.ExpectedAvailable, req.Reference)
+		} else {
+			err = h.inventoryService.ReserveStockAt(ctx, productID, location, condition, req.Quantity, req.Reference)
+		}
+	case "unreserve":
+		message = "Stock unreserved successfully"
+		err = h.inventoryService.UnreserveStockAt(ctx, productID, location, condition, req.Quantity, req.Reference)
+	default:
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "Unknown stock operation")
+		return
+	}
+
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, message, nil)
+}
+
+// SetStockCountHandler handles overwriting the counted quantity of a product at
+// one warehouse; a stale version is rejected with 409 Conflict
+func (h *Handler) SetStockCountHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PUT is allowed")
+		return
+	}
+
+	var req StockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	inventory, err := h.inventoryService.SetStockCount(r.Context(), r.PathValue("id"), r.PathValue("warehouse"), req.Quantity, req.Version, req.Reference)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock count updated successfully", inventory)
+}
+
+// AdjustStockHandler handles setting the absolute on-hand quantity of a
+// product after a physical count
+func (h *Handler) AdjustStockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req StockAdjustRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	operator := r.Header.Get(OperatorHeader)
+	if principal := RequestPrincipal(r.Context()); principal != nil {
+		operator = principal.Subject
+	}
+
+	transaction, err := h.inventoryService.AdjustStock(r.Context(), r.PathValue("id"), req.Location, req.Quantity,
+		domain.AdjustmentReason(req.ReasonCode), req.Reference, operator)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock adjusted successfully", transaction)
+}
+
+// PatchInventorySettingsHandler handles JSON Patch (RFC 6902) requests that
+// change the reorder level, safety stock or bin location of a product's
+// inventory at one warehouse. Each setting requires a role of its own; with
+// authentication disabled all of them may be changed.
+func (h *Handler) PatchInventorySettingsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PATCH is allowed")
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json-patch+json" {
+		WriteError(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "Content-Type must be application/json-patch+json")
+		return
+	}
+
+	var operations []domain.PatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	role := domain.RoleAdmin
+	if principal := RequestPrincipal(r.Context()); principal != nil {
+		role = principal.Role
+	}
+
+	inventory, err := h.inventoryService.PatchInventorySettings(r.Context(), r.PathValue("id"), r.PathValue("warehouse"), operations, role)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Inventory settings updated successfully", inventory)
+}
+
+// GetTransactionsHandler handles retrieving the transaction history of a
+// product, optionally filtered by type, reference and period
 func (h *Handler) GetTransactionsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
@@ -326,26 +847,195 @@ func (h *Handler) GetTransactionsHandler(w http.ResponseWriter, r *http.Request)
 	productID = strings.TrimSuffix(productID, "/transactions")
 	productID = strings.TrimSuffix(productID, "/")
 
+	h.listTransactions(w, r, productID)
+}
+
+// ListTransactionsHandler handles listing the transactions of all products,
+// e.g. every movement of an order reference or of a period
+func (h *Handler) ListTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	h.listTransactions(w, r, "")
+}
+
+// AnnotateTransactionHandler handles appending a note to a transaction, e.g.
+// the outcome of investigating it
+func (h *Handler) AnnotateTransactionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req TransactionAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	operator := r.Header.Get(OperatorHeader)
+	if principal := RequestPrincipal(r.Context()); principal != nil {
+		operator = principal.Subject
+	}
+
+	annotation, err := h.inventoryService.AnnotateTransaction(r.Context(), r.PathValue("id"), req.Note, operator)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "ANNOTATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Transaction annotated successfully", annotation)
+}
+
+// listTransactions writes one page of the transactions matching the type,
+// reference, from, to and filter query parameters, restricted to productID
+// if set
+func (h *Handler) listTransactions(w http.ResponseWriter, r *http.Request, productID string) {
+	filter, err := parseTransactionFilter(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		return
+	}
+	filter.ProductID = productID
+
+	limit, offset := parsePagination(r)
+
+	transactions, err := h.inventoryService.ListTransactions(r.Context(), filter, domain.PageRequest{
+		Limit:  limit,
+		Offset: offset,
+		Cursor: r.URL.Query().Get("cursor"),
+	})
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Transactions retrieved successfully", transactions)
+}
+
+// parseTransactionFilter reads the optional type, reference, from, to,
+// filter and include_archived query parameters. type, reference, from and
+// to are shorthands for comparisons joined to the filter expression with
+// AND. Plain dates are read in the request's reporting timezone, and to
+// includes its whole day.
+func parseTransactionFilter(r *http.Request) (repository.TransactionFilter, error) {
+	query := r.URL.Query()
+	loc := ReportingLocation(r.Context())
+	var filter repository.TransactionFilter
+
+	expression, err := domain.ParseFilter(query.Get("filter"), domain.TransactionFilterFields, loc)
+	if err != nil {
+		return filter, err
+	}
+
+	var shorthands []*domain.Filter
+	if value := strings.ToUpper(query.Get("type")); value != "" {
+		if !domain.IsTransactionType(value) {
+			return filter, fmt.Errorf("unknown transaction type %q", value)
+		}
+		shorthands = append(shorthands, &domain.Filter{Field: "type", Operator: domain.FilterEqual, Value: value})
+	}
+	if value := query.Get("reference"); value != "" {
+		shorthands = append(shorthands, &domain.Filter{Field: "reference", Operator: domain.FilterEqual, Value: value})
+	}
+
+	var from, to time.Time
+	if value := query.Get("from"); value != "" {
+		if from, err = parseTimeParam(value, loc, false); err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if to, err = parseTimeParam(value, loc, true); err != nil {
+			return filter, err
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return filter, errors.New("from must be before to")
+	}
+	shorthands = append(shorthands, domain.FilterPeriod("created_at", from, to), expression)
+	filter.Expression = domain.AllFilters(shorthands...)
+
+	if value := query.Get("include_archived"); value != "" {
+		includeArchived, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("include_archived must be true or false")
+		}
+		filter.IncludeArchived = includeArchived
+	}
+
+	return filter, nil
+}
+
+// maxPageSize bounds the limit of a listing, so that one request cannot read
+// a table into memory; larger sets are paged or exported
+const maxPageSize = 1000
+
+// parsePagination reads limit and offset query parameters, defaulting to 10
+// and 0. Limits are capped at maxPageSize, and limits below one and negative
+// offsets fall back to the defaults.
+func parsePagination(r *http.Request) (int, int) {
 	limit := 10
 	offset := 0
 
 	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsedLimit, err := strconv.Atoi(l); err == nil {
-			limit = parsedLimit
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, maxPageSize)
 		}
 	}
 
 	if o := r.URL.Query().Get("offset"); o != "" {
-		if parsedOffset, err := strconv.Atoi(o); err == nil {
+		if parsedOffset, err := strconv.Atoi(o); err == nil && parsedOffset >= 0 {
 			offset = parsedOffset
 		}
 	}
 
-	transactions, err := h.inventoryService.ListTransactions(r.Context(), productID, limit, offset)
+	return limit, offset
+}
+
+// parseTimeParam parses a query parameter given either as RFC3339 or as a
+// plain date (YYYY-MM-DD) in loc. endOfDay moves plain dates to the following
+// local midnight so the whole day is included in a half-open range.
+func parseTimeParam(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, "RETRIEVAL_FAILED", err.Error())
-		return
+		return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339 or YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parsePeriod reads the required from and to query parameters as a half-open
+// range, interpreting plain dates in the request's reporting timezone
+func parsePeriod(r *http.Request) (time.Time, time.Time, error) {
+	fromParam := r.URL.Query().Get("from")
+	toParam := r.URL.Query().Get("to")
+	if fromParam == "" || toParam == "" {
+		return time.Time{}, time.Time{}, errors.New("from and to query parameters are required")
 	}
 
-	WriteSuccess(w, http.StatusOK, "Transactions retrieved successfully", transactions)
+	loc := ReportingLocation(r.Context())
+
+	from, err := parseTimeParam(fromParam, loc, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := parseTimeParam(toParam, loc, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must be before to")
+	}
+	return from, to, nil
 }
@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
 	"github.com/bhnrathore/distributed-inventory-system/internal/service"
 )
 
@@ -47,10 +53,12 @@ func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*doma
 	return nil, nil
 }
 
-func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) ([]*domain.Product, error) {
 	var products []*domain.Product
 	for _, p := range m.products {
-		products = append(products, p)
+		if (includeArchived || !p.Archived()) && filter.Matches(p.FilterValue) {
+			products = append(products, p)
+		}
 	}
 	return products, nil
 }
@@ -60,18 +68,88 @@ func (m *MockProductRepository) Update(ctx context.Context, product *domain.Prod
 	return nil
 }
 
+func (m *MockProductRepository) Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	p, ok := m.products[id]
+	if !ok {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	patch.Apply(p)
+	return p, nil
+}
+
 func (m *MockProductRepository) Delete(ctx context.Context, id string) error {
 	delete(m.products, id)
 	return nil
 }
 
-func (m *MockProductRepository) Count(ctx context.Context) (int64, error) {
-	return int64(len(m.products)), nil
+func (m *MockProductRepository) Archive(ctx context.Context, id string) error {
+	p, ok := m.products[id]
+	if !ok {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	if p.DeletedAt == nil {
+		now := time.Now()
+		p.DeletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id string) error {
+	p, ok := m.products[id]
+	if !ok {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	p.DeletedAt = nil
+	return nil
+}
+
+func (m *MockProductRepository) Count(ctx context.Context, includeArchived bool, filter *domain.Filter) (int64, error) {
+	var count int64
+	for _, p := range m.products {
+		if (includeArchived || !p.Archived()) && filter.Matches(p.FilterValue) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockProductRepository) SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error {
+	p, ok := m.products[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	p.ReleaseAt = releaseAt
+	return nil
+}
+
+func (m *MockProductRepository) ListScheduledReleases(ctx context.Context) ([]*domain.Product, error) {
+	var products []*domain.Product
+	for _, p := range m.products {
+		if p.ReleaseAt != nil {
+			products = append(products, p)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ReleaseAt.Before(*products[j].ReleaseAt) })
+	return products, nil
+}
+
+func (m *MockProductRepository) ReleaseDue(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	for id, p := range m.products {
+		if p.ReleaseAt != nil && !now.Before(*p.ReleaseAt) {
+			p.ReleaseAt = nil
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
 }
 
 // MockInventoryRepository implements InventoryRepository interface for testing
 type MockInventoryRepository struct {
-	items map[string]*domain.InventoryItem
+	items     map[string]*domain.InventoryItem
+	transfers []*domain.Transaction
+	batched   []*domain.Transaction
 }
 
 func NewMockInventoryRepository() *MockInventoryRepository {
@@ -84,6 +162,15 @@ func (m *MockInventoryRepository) Create(ctx context.Context, item *domain.Inven
 	if item.ID == "" {
 		item.ID = "inv-" + item.ProductID
 	}
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	if item.Version == 0 {
+		item.Version = 1
+	}
+	if item.Condition == "" {
+		item.Condition = domain.ConditionNew
+	}
 	m.items[item.ID] = item
 	return nil
 }
@@ -95,25 +182,87 @@ func (m *MockInventoryRepository) GetByID(ctx context.Context, id string) (*doma
 	return nil, nil
 }
 
+// GetByProductID returns the oldest inventory row of the product, like the
+// repository's default location
 func (m *MockInventoryRepository) GetByProductID(ctx context.Context, productID string) (*domain.InventoryItem, error) {
+	var oldest *domain.InventoryItem
 	for _, i := range m.items {
-		if i.ProductID == productID {
+		if i.ProductID != productID {
+			continue
+		}
+		if oldest == nil || i.CreatedAt.Before(oldest.CreatedAt) || (i.CreatedAt.Equal(oldest.CreatedAt) && i.ID < oldest.ID) {
+			oldest = i
+		}
+	}
+	return oldest, nil
+}
+
+func (m *MockInventoryRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	for _, i := range m.items {
+		if i.ProductID == productID && i.Location == location && i.Condition == condition {
 			return i, nil
 		}
 	}
-	return nil, nil
+	return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+}
+
+func (m *MockInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	for _, i := range m.items {
+		if i.ProductID == productID {
+			items = append(items, i)
+		}
+	}
+	return items, nil
+}
+
+func (m *MockInventoryRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string][]*domain.InventoryItem, error) {
+	items := make(map[string][]*domain.InventoryItem, len(productIDs))
+	for _, productID := range productIDs {
+		if list, _ := m.ListByProductID(ctx, productID); len(list) > 0 {
+			items[productID] = list
+		}
+	}
+	return items, nil
 }
 
-func (m *MockInventoryRepository) List(ctx context.Context, limit, offset int) ([]*domain.InventoryItem, error) {
+func (m *MockInventoryRepository) List(ctx context.Context, limit, offset int, filter *domain.Filter) ([]*domain.InventoryItem, error) {
 	var items []*domain.InventoryItem
 	for _, i := range m.items {
-		items = append(items, i)
+		if filter.Matches(i.FilterValue) {
+			items = append(items, i)
+		}
 	}
 	return items, nil
 }
 
+func (m *MockInventoryRepository) Count(ctx context.Context, filter *domain.Filter) (int64, error) {
+	items, err := m.List(ctx, -1, 0, filter)
+	return int64(len(items)), err
+}
+
 func (m *MockInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
-	m.items[item.ID] = item
+	if stored, ok := m.items[item.ID]; ok && stored.Version != item.Version {
+		return domain.ErrConflict
+	}
+	updated := *item
+	updated.Version++
+	m.items[item.ID] = &updated
+	item.Version = updated.Version
+	return nil
+}
+
+func (m *MockInventoryRepository) UpdateSettings(ctx context.Context, item *domain.InventoryItem) error {
+	stored, ok := m.items[item.ID]
+	if !ok {
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	if stored.Version != item.Version {
+		return domain.ErrConflict
+	}
+	stored.ReorderLevel, stored.SafetyStock, stored.BinLocation = item.ReorderLevel, item.SafetyStock, item.BinLocation
+	stored.Version++
+	item.Version = stored.Version
 	return nil
 }
 
@@ -126,11 +275,55 @@ func (m *MockInventoryRepository) UpdateQuantity(ctx context.Context, inventoryI
 	if i, ok := m.items[inventoryID]; ok {
 		i.Quantity += quantityDelta
 		i.Reserved += reservedDelta
+		i.Version++
 		return nil
 	}
 	return nil
 }
 
+func (m *MockInventoryRepository) Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error {
+	from, ok := m.items[fromID]
+	if !ok || from.AvailableQuantity() < quantity {
+		return fmt.Errorf("transfer failed: %w available or item not found", domain.ErrInsufficientStock)
+	}
+	to, ok := m.items[toID]
+	if !ok {
+		return fmt.Errorf("transfer failed: destination item %w", domain.ErrNotFound)
+	}
+	from.Quantity -= quantity
+	to.Quantity += quantity
+	m.transfers = append(m.transfers, out, in)
+	return nil
+}
+
+func (m *MockInventoryRepository) ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error {
+	staged := make(map[string]domain.InventoryItem)
+	for i, change := range changes {
+		item, ok := staged[change.InventoryID]
+		if !ok {
+			current, found := m.items[change.InventoryID]
+			if !found {
+				return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+			}
+			item = *current
+		}
+		item.Quantity += change.QuantityDelta
+		item.Reserved += change.ReservedDelta
+		if item.Quantity < item.Reserved || item.Reserved < 0 {
+			return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+		}
+		staged[change.InventoryID] = item
+	}
+	for id, item := range staged {
+		m.items[id].Quantity = item.Quantity
+		m.items[id].Reserved = item.Reserved
+	}
+	for _, change := range changes {
+		m.batched = append(m.batched, change.Transaction)
+	}
+	return nil
+}
+
 // MockTransactionRepository implements TransactionRepository interface for testing
 type MockTransactionRepository struct {
 	transactions map[string]*domain.Transaction
@@ -144,7 +337,10 @@ func NewMockTransactionRepository() *MockTransactionRepository {
 
 func (m *MockTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
 	if transaction.ID == "" {
-		transaction.ID = "tx-" + transaction.Reference
+		transaction.ID = fmt.Sprintf("tx-%d", len(m.transactions)+1)
+	}
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
 	}
 	m.transactions[transaction.ID] = transaction
 	return nil
@@ -177,6 +373,32 @@ func (m *MockTransactionRepository) GetByProductID(ctx context.Context, productI
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) Search(ctx context.Context, filter repository.TransactionFilter, after *domain.TransactionCursor, limit, offset int) ([]*domain.Transaction, error) {
+	var txs []*domain.Transaction
+	for _, t := range m.transactions {
+		if (filter.ProductID == "" || t.ProductID == filter.ProductID) &&
+			filter.Expression.Matches(t.FilterValue) {
+			txs = append(txs, t)
+		}
+	}
+	return txs, nil
+}
+
+func (m *MockTransactionRepository) RecentByProductIDs(ctx context.Context, productIDs []string, perProduct int) (map[string][]*domain.Transaction, error) {
+	transactions := make(map[string][]*domain.Transaction, len(productIDs))
+	for _, productID := range productIDs {
+		if list, _ := m.GetByProductID(ctx, productID, perProduct, 0); len(list) > 0 {
+			transactions[productID] = list
+		}
+	}
+	return transactions, nil
+}
+
+func (m *MockTransactionRepository) CountMatching(ctx context.Context, filter repository.TransactionFilter) (int64, error) {
+	txs, _ := m.Search(ctx, filter, nil, 0, 0)
+	return int64(len(txs)), nil
+}
+
 func (m *MockTransactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Transaction, error) {
 	var txs []*domain.Transaction
 	for _, t := range m.transactions {
@@ -185,85 +407,605 @@ func (m *MockTransactionRepository) List(ctx context.Context, limit, offset int)
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) GetByDateRange(ctx context.Context, from, to time.Time, after *domain.TransactionCursor, limit int) ([]*domain.Transaction, error) {
+	var txs []*domain.Transaction
+	for _, t := range m.transactions {
+		if !t.CreatedAt.Before(from) && t.CreatedAt.Before(to) &&
+			(after == nil || t.CreatedAt.After(after.CreatedAt) || (t.CreatedAt.Equal(after.CreatedAt) && t.ID > after.ID)) {
+			txs = append(txs, t)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if !txs[i].CreatedAt.Equal(txs[j].CreatedAt) {
+			return txs[i].CreatedAt.Before(txs[j].CreatedAt)
+		}
+		return txs[i].ID < txs[j].ID
+	})
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
+func (m *MockTransactionRepository) ReservedByReference(ctx context.Context, productID, reference string) (int64, error) {
+	var reserved int64
+	for _, t := range m.transactions {
+		if t.ProductID != productID || t.Reference != reference {
+			continue
+		}
+		switch t.Type {
+		case "RESERVE":
+			reserved += t.Quantity
+		case "UNRESERVE":
+			reserved -= t.Quantity
+		}
+	}
+	return reserved, nil
+}
+
+func (m *MockTransactionRepository) OutboundByInventory(ctx context.Context, productID string, since time.Time) (map[string]int64, error) {
+	outbound := make(map[string]int64)
+	for _, t := range m.transactions {
+		if t.ProductID == productID && t.Type == "OUT" && !t.CreatedAt.Before(since) {
+			outbound[t.InventoryID] += t.Quantity
+		}
+	}
+	return outbound, nil
+}
+
 func (m *MockTransactionRepository) Count(ctx context.Context) (int64, error) {
 	return int64(len(m.transactions)), nil
 }
 
 // Tests
 
-func TestHealthHandler(t *testing.T) {
+func TestCreateProductHandler(t *testing.T) {
 	productRepo := NewMockProductRepository()
 	inventoryRepo := NewMockInventoryRepository()
 	transactionRepo := NewMockTransactionRepository()
 	invService := service.NewInventoryService(productRepo, inventoryRepo, transactionRepo)
 	handler := NewHandler(invService)
 
-	req, err := http.NewRequest("GET", "/health", nil)
+	reqBody := CreateProductRequest{
+		Name:            "Laptop",
+		Description:     "Gaming Laptop",
+		SKU:             "LAP001",
+		Price:           1500.00,
+		Location:        "Warehouse A",
+		InitialQuantity: 50,
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req, err := http.NewRequest("POST", "/products", bytes.NewBuffer(body))
 	if err != nil {
 		t.Fatal(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.HealthHandler(rr, req)
+	handler.CreateProductHandler(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+}
+
+func TestPatchProductHandler(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	invService := service.NewInventoryService(productRepo, NewMockInventoryRepository(), NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	productRepo.Create(context.Background(), &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Category: "Computers", Price: 1500})
 
-	if status := rr.Code; status != http.StatusOK {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{"Rename only", "application/merge-patch+json", `{"name":"Laptop Pro"}`, http.StatusOK, ""},
+		{"Clear the category", "application/json", `{"category":null,"description":"Thin"}`, http.StatusOK, ""},
+		{"Name cannot be cleared", "application/merge-patch+json", `{"name":null}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"SKU is not patchable", "application/merge-patch+json", `{"sku":"LAP002"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Negative price", "application/merge-patch+json", `{"price":-1}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Empty patch", "application/merge-patch+json", `{}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"JSON Patch", "application/json-patch+json", `[]`, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
 	}
 
-	var response map[string]interface{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/api/products/prod-1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			rr := httptest.NewRecorder()
+			handler.PatchProductHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Error != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, response.Error)
+			}
+		})
+	}
+
+	product := productRepo.products["prod-1"]
+	if product.Name != "Laptop Pro" || product.Description != "Thin" || product.Category != "" || product.SKU != "LAP001" || product.Price != 1500 {
+		t.Errorf("Expected only the patched fields to change, got %+v", product)
+	}
+}
+
+func TestCreateProductHandlerDuplicateSKU(t *testing.T) {
+	invService := service.NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	handler := NewHandler(invService)
+
+	body, _ := json.Marshal(CreateProductRequest{Name: "Laptop", SKU: "LAP001", Price: 1500, Location: "Warehouse A"})
+	for i, want := range []int{http.StatusCreated, http.StatusConflict} {
+		rr := httptest.NewRecorder()
+		handler.CreateProductHandler(rr, httptest.NewRequest(http.MethodPost, "/api/products", bytes.NewReader(body)))
+		if rr.Code != want {
+			t.Fatalf("Request %d: expected status %d, got %d: %s", i+1, want, rr.Code, rr.Body.String())
+		}
+		if want != http.StatusConflict {
+			continue
+		}
+
+		var response ErrorResponse
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Error != "DUPLICATE_SKU" || response.SKU != "LAP001" {
+			t.Errorf("Expected DUPLICATE_SKU naming LAP001, got %+v", response)
+		}
+	}
+}
+
+func TestServiceErrorStatusMapping(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	invService := service.NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	handler := NewHandler(invService)
+
+	inventoryRepo.Create(context.Background(), &domain.InventoryItem{ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	tests := []struct {
+		name       string
+		warehouse  string
+		op         string
+		quantity   int64
+		wantStatus int
+		wantCode   string
+	}{
+		{"Insufficient stock", "WH-A", "remove", 50, http.StatusUnprocessableEntity, "INSUFFICIENT_STOCK"},
+		{"Invalid quantity", "WH-A", "add", 0, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Unknown location", "WH-X", "add", 5, http.StatusNotFound, "NOT_FOUND"},
+		{"Success", "WH-A", "remove", 5, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(StockOperationRequest{Quantity: tt.quantity, Reference: "REF-1"})
+			req := httptest.NewRequest(http.MethodPost, "/api/products/prod-1/inventory/"+tt.warehouse+"/stock/"+tt.op, bytes.NewBuffer(body))
+			req.SetPathValue("id", "prod-1")
+			req.SetPathValue("warehouse", tt.warehouse)
+			req.SetPathValue("op", tt.op)
+
+			rr := httptest.NewRecorder()
+			handler.LocationStockHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Error != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, response.Error)
+			}
+		})
+	}
+}
+
+func TestConditionalStockOperationHandler(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	invService := service.NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	inventoryRepo.Create(context.Background(), &domain.InventoryItem{ProductID: "prod-1", Quantity: 30, Location: "WH-A"})
+
+	run := func(op string, expected int64) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(StockOperationRequest{Quantity: 5, Reference: "REF-1", ExpectedAvailable: &expected})
+		req := httptest.NewRequest(http.MethodPost, "/api/products/prod-1/inventory/WH-A/stock/"+op, bytes.NewBuffer(body))
+		req.SetPathValue("id", "prod-1")
+		req.SetPathValue("warehouse", "WH-A")
+		req.SetPathValue("op", op)
+		rr := httptest.NewRecorder()
+		handler.LocationStockHandler(rr, req)
+		return rr
+	}
+
+	if rr := run("remove", 20); rr.Code != http.StatusOK {
+		t.Fatalf("Expected the removal leaving 25 to succeed, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr := run("remove", 21)
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("Expected status 412, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var response ErrorResponse
 	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
-		t.Errorf("failed to decode response: %v", err)
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Error != "PRECONDITION_FAILED" {
+		t.Errorf("Expected error code PRECONDITION_FAILED, got %s", response.Error)
+	}
+	if rr := run("add", 0); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected expected_available to be rejected on additions, got %d", rr.Code)
 	}
 }
 
-func TestHealthHandlerMethodNotAllowed(t *testing.T) {
-	productRepo := NewMockProductRepository()
+func TestPatchInventorySettingsHandler(t *testing.T) {
 	inventoryRepo := NewMockInventoryRepository()
-	transactionRepo := NewMockTransactionRepository()
-	invService := service.NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	invService := service.NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
 	handler := NewHandler(invService)
 
-	req, err := http.NewRequest("POST", "/health", nil)
-	if err != nil {
-		t.Fatal(err)
+	inventoryRepo.Create(context.Background(), &domain.InventoryItem{ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+	operator := &domain.Principal{Subject: "picker", Role: domain.RoleOperator}
+
+	tests := []struct {
+		name        string
+		contentType string
+		principal   *domain.Principal
+		body        string
+		wantStatus  int
+		wantCode    string
+	}{
+		{"Operator moves the bin", "application/json-patch+json", operator, `[{"op":"replace","path":"/bin_location","value":"B-07"}]`, http.StatusOK, ""},
+		{"Operator cannot set the reorder level", "application/json-patch+json", operator, `[{"op":"replace","path":"/reorder_level","value":20}]`, http.StatusForbidden, "FORBIDDEN"},
+		{"Quantity is not patchable", "application/json-patch+json", nil, `[{"op":"replace","path":"/quantity","value":99}]`, http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"Failed test", "application/json-patch+json", nil, `[{"op":"test","path":"/bin_location","value":"A-01"}]`, http.StatusConflict, "PATCH_TEST_FAILED"},
+		{"Plain JSON", "application/json", nil, `[]`, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE"},
 	}
 
-	rr := httptest.NewRecorder()
-	handler.HealthHandler(rr, req)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/api/products/prod-1/inventory/WH-A", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.SetPathValue("id", "prod-1")
+			req.SetPathValue("warehouse", "WH-A")
+			if tt.principal != nil {
+				req = req.WithContext(context.WithValue(req.Context(), principalKey{}, tt.principal))
+			}
 
-	if status := rr.Code; status != http.StatusMethodNotAllowed {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusMethodNotAllowed)
+			rr := httptest.NewRecorder()
+			handler.PatchInventorySettingsHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Error != tt.wantCode {
+				t.Errorf("Expected error code %s, got %s", tt.wantCode, response.Error)
+			}
+		})
+	}
+
+	item, _ := inventoryRepo.GetByProductAndLocation(context.Background(), "prod-1", "WH-A", domain.ConditionNew)
+	if item.BinLocation != "B-07" || item.Quantity != 10 {
+		t.Errorf("Expected bin B-07 and quantity 10, got %s and %d", item.BinLocation, item.Quantity)
 	}
 }
 
-func TestCreateProductHandler(t *testing.T) {
-	productRepo := NewMockProductRepository()
+func TestSKUHandlers(t *testing.T) {
 	inventoryRepo := NewMockInventoryRepository()
-	transactionRepo := NewMockTransactionRepository()
-	invService := service.NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	invService := service.NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
 	handler := NewHandler(invService)
 
-	reqBody := CreateProductRequest{
-		Name:            "Laptop",
-		Description:     "Gaming Laptop",
-		SKU:             "LAP001",
-		Price:           1500.00,
-		Location:        "Warehouse A",
-		InitialQuantity: 50,
+	product := &domain.Product{Name: "Laptop", SKU: "LAP001", Price: 1500}
+	if err := invService.CreateProduct(context.Background(), product, "WH-A", 10); err != nil {
+		t.Fatalf("failed to create product: %v", err)
 	}
 
-	body, _ := json.Marshal(reqBody)
-	req, err := http.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	req := httptest.NewRequest(http.MethodGet, "/api/products/sku/LAP001", nil)
+	rr := httptest.NewRecorder()
+	handler.GetProductBySKUHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/products/sku/UNKNOWN", nil)
+	rr = httptest.NewRecorder()
+	handler.GetProductBySKUHandler(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for unknown SKU, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	tests := []struct {
+		name       string
+		sku        string
+		op         string
+		wantStatus int
+	}{
+		{"Add", "LAP001", "add", http.StatusOK},
+		{"Reserve", "LAP001", "reserve", http.StatusOK},
+		{"Unknown operation", "LAP001", "count", http.StatusNotFound},
+		{"Unknown SKU", "UNKNOWN", "add", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(StockOperationRequest{Quantity: 5, Reference: "ERP-1"})
+			req := httptest.NewRequest(http.MethodPost, "/api/sku/"+tt.sku+"/stock/"+tt.op, bytes.NewBuffer(body))
+			req.SetPathValue("sku", tt.sku)
+			req.SetPathValue("op", tt.op)
+
+			rr := httptest.NewRecorder()
+			handler.SKUStockHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	inventory, err := inventoryRepo.GetByProductAndLocation(context.Background(), product.ID, "WH-A", domain.ConditionNew)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("failed to get inventory: %v", err)
+	}
+	if inventory.Quantity != 15 || inventory.Reserved != 5 {
+		t.Errorf("Expected quantity 15 and reserved 5, got %d and %d", inventory.Quantity, inventory.Reserved)
+	}
+}
+
+func TestProductIncludes(t *testing.T) {
+	invService := service.NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	ctx := context.Background()
+
+	for _, sku := range []string{"LAP001", "MOU001"} {
+		product := &domain.Product{Name: "Product " + sku, SKU: sku, Price: 10}
+		if err := invService.CreateProduct(ctx, product, "WH-A", 0); err != nil {
+			t.Fatalf("failed to create product: %v", err)
+		}
+	}
+	if err := invService.AddStock(ctx, "test-id-LAP001", 5, "PO-1"); err != nil {
+		t.Fatalf("failed to add stock: %v", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
+	// Related resources are left out unless requested
+	req := httptest.NewRequest(http.MethodGet, "/api/products/test-id-LAP001", nil)
 	rr := httptest.NewRecorder()
-	handler.CreateProductHandler(rr, req)
+	handler.GetProductHandler(rr, req)
+	if strings.Contains(rr.Body.String(), `"transactions"`) {
+		t.Errorf("Expected no transactions without include, got %s", rr.Body.String())
+	}
 
-	if status := rr.Code; status != http.StatusCreated {
-		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	req = httptest.NewRequest(http.MethodGet, "/api/products/test-id-LAP001?include=transactions", nil)
+	rr = httptest.NewRecorder()
+	handler.GetProductHandler(rr, req)
+	var detail struct {
+		Data struct {
+			Product domain.Product `json:"product"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&detail); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(detail.Data.Product.Transactions) != 1 || detail.Data.Product.Inventory != nil {
+		t.Errorf("Expected only transactions to be included, got %+v", detail.Data.Product)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/products/sku/LAP001?include=reservations", nil)
+	rr = httptest.NewRecorder()
+	handler.GetProductBySKUHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown include, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/products?include=inventory,transactions", nil)
+	rr = httptest.NewRecorder()
+	handler.ListProductsHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var list struct {
+		Data domain.Page[*domain.Product] `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Data.Items) != 2 {
+		t.Fatalf("Expected 2 products, got %d", len(list.Data.Items))
+	}
+	for _, product := range list.Data.Items {
+		if len(product.Inventory) != 1 || product.Inventory[0].Location != "WH-A" {
+			t.Errorf("Expected the inventory of %s to be included, got %+v", product.SKU, product.Inventory)
+		}
+		if want := map[string]int{"LAP001": 1, "MOU001": 0}[product.SKU]; len(product.Transactions) != want {
+			t.Errorf("Expected %d transactions for %s, got %d", want, product.SKU, len(product.Transactions))
+		}
+	}
+}
+
+func TestListTransactionsHandler(t *testing.T) {
+	invService := service.NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	ctx := context.Background()
+
+	for _, sku := range []string{"LAP001", "MOU001"} {
+		product := &domain.Product{Name: "Product " + sku, SKU: sku, Price: 10}
+		if err := invService.CreateProduct(ctx, product, "WH-A", 0); err != nil {
+			t.Fatalf("failed to create product: %v", err)
+		}
+		if err := invService.AddStock(ctx, product.ID, 5, "PO-1"); err != nil {
+			t.Fatalf("failed to add stock: %v", err)
+		}
+	}
+	if err := invService.AddStock(ctx, "test-id-LAP001", 1, "PO-2"); err != nil {
+		t.Fatalf("failed to add stock: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/transactions?reference=PO-1&type=in", nil)
+	rr := httptest.NewRecorder()
+	handler.ListTransactionsHandler(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var list struct {
+		Data domain.Page[*domain.Transaction] `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Data.Total != 2 {
+		t.Errorf("Expected the PO-1 movements of both products, got %d", list.Data.Total)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/products/test-id-LAP001/transactions?from=tomorrow", nil)
+	rr = httptest.NewRecorder()
+	handler.GetTransactionsHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid from, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	for _, query := range []string{"type=SHIPPED", "from=2024-03-02&to=2024-03-01"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/transactions?"+query, nil)
+		rr = httptest.NewRecorder()
+		handler.ListTransactionsHandler(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, query, rr.Code)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/transactions?include_archived=maybe", nil)
+	rr = httptest.NewRecorder()
+	handler.ListTransactionsHandler(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid include_archived, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/transactions?filter="+url.QueryEscape(`quantity < 5 AND reference ~ "po"`), nil)
+	rr = httptest.NewRecorder()
+	handler.ListTransactionsHandler(rr, req)
+	list.Data = domain.Page[*domain.Transaction]{}
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Data.Total != 1 || list.Data.Items[0].Reference != "PO-2" {
+		t.Errorf("Expected the PO-2 movement to match the filter, got %+v", list.Data.Items)
+	}
+}
+
+func TestListFilterExpressions(t *testing.T) {
+	invService := service.NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	handler := NewHandler(invService)
+	ctx := context.Background()
+
+	for i, sku := range []string{"LAP001", "MOU001", "KEY001"} {
+		product := &domain.Product{Name: "Product " + sku, SKU: sku, Price: float64(50 * (i + 1))}
+		if err := invService.CreateProduct(ctx, product, []string{"A", "B", "A"}[i], int64(10*i)); err != nil {
+			t.Fatalf("failed to create product: %v", err)
+		}
+	}
+
+	list := func(handle http.HandlerFunc, path, filter string) (int, int64) {
+		req := httptest.NewRequest(http.MethodGet, path+"?filter="+url.QueryEscape(filter), nil)
+		rr := httptest.NewRecorder()
+		handle(rr, req)
+		var page struct {
+			Data struct {
+				Total int64 `json:"total"`
+			} `json:"data"`
+		}
+		json.NewDecoder(rr.Body).Decode(&page)
+		return rr.Code, page.Data.Total
+	}
+
+	tests := []struct {
+		name   string
+		handle http.HandlerFunc
+		path   string
+		filter string
+		status int
+		total  int64
+	}{
+		{"all products", handler.ListProductsHandler, "/api/products", "", http.StatusOK, 3},
+		{"products by price", handler.ListProductsHandler, "/api/products", "price > 60", http.StatusOK, 2},
+		{"products grouped", handler.ListProductsHandler, "/api/products", `sku = "LAP001" OR (price >= 100 AND name ~ mou)`, http.StatusOK, 2},
+		{"inventory by location and stock", handler.ListInventoryHandler, "/api/inventory", `location = A AND available > 0`, http.StatusOK, 1},
+		{"unknown field", handler.ListProductsHandler, "/api/products", "cost > 1", http.StatusBadRequest, 0},
+		{"text compared as number", handler.ListInventoryHandler, "/api/inventory", "quantity > ten", http.StatusBadRequest, 0},
+		{"unbalanced parentheses", handler.ListProductsHandler, "/api/products", "(price > 1", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, total := list(tt.handle, tt.path, tt.filter)
+			if status != tt.status || total != tt.total {
+				t.Errorf("Expected status %d with %d matches, got %d with %d", tt.status, tt.total, status, total)
+			}
+		})
+	}
+}
+
+func TestStockBatchHandler(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	invService := service.NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	handler := NewHandler(invService)
+
+	product := &domain.Product{Name: "Laptop", SKU: "LAP001", Price: 1500}
+	if err := invService.CreateProduct(context.Background(), product, "WH-A", 10); err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		items      []domain.StockBatchItem
+		wantStatus int
+	}{
+		{"Applied", []domain.StockBatchItem{
+			{ProductID: product.ID, Operation: domain.StockReserve, Quantity: 4, Reference: "ORD-1"},
+			{ProductID: product.ID, Operation: domain.StockRemove, Quantity: 2, Reference: "ORD-1"},
+		}, http.StatusOK},
+		{"Insufficient stock", []domain.StockBatchItem{
+			{ProductID: product.ID, Operation: domain.StockRemove, Quantity: 3, Reference: "ORD-2"},
+			{ProductID: product.ID, Operation: domain.StockReserve, Quantity: 5, Reference: "ORD-2"},
+		}, http.StatusUnprocessableEntity},
+		{"Invalid operation", []domain.StockBatchItem{
+			{ProductID: product.ID, Operation: "count", Quantity: 1},
+		}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(StockBatchRequest{Items: tt.items})
+			req := httptest.NewRequest(http.MethodPost, "/api/stock/batch", bytes.NewBuffer(body))
+			rr := httptest.NewRecorder()
+			handler.StockBatchHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	// Only the first batch was applied
+	item, _ := inventoryRepo.GetByProductID(context.Background(), product.ID)
+	if item.Quantity != 8 || item.Reserved != 4 {
+		t.Errorf("Expected inventory at 8/4, got %d/%d", item.Quantity, item.Reserved)
 	}
 }
 
@@ -307,3 +1049,22 @@ func TestCreateProductHandlerMethodNotAllowed(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusMethodNotAllowed)
 	}
 }
+
+func TestParsePaginationBoundsLimits(t *testing.T) {
+	tests := []struct {
+		query              string
+		wantLimit, wantOff int
+	}{
+		{"", 10, 0},
+		{"limit=50&offset=20", 50, 20},
+		{"limit=100000000", maxPageSize, 0},
+		{"limit=-1&offset=-5", 10, 0},
+		{"limit=abc", 10, 0},
+	}
+	for _, tt := range tests {
+		limit, offset := parsePagination(httptest.NewRequest(http.MethodGet, "/api/products?"+tt.query, nil))
+		if limit != tt.wantLimit || offset != tt.wantOff {
+			t.Errorf("%q: expected %d/%d, got %d/%d", tt.query, tt.wantLimit, tt.wantOff, limit, offset)
+		}
+	}
+}
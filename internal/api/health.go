@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// HealthHandler handles the liveness and readiness probes
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// infrastructurePath reports whether a request path serves probes or metric
+// scrapes, which are neither scoped to a tenant nor metered
+func infrastructurePath(path string) bool {
+	return path == "/healthz" || path == "/readyz" || path == "/metrics"
+}
+
+// LivenessHandler handles liveness probes. It checks no dependency: a
+// process that answers is alive, and restarting it would not bring a
+// dependency back.
+func (h *HealthHandler) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Service is alive", map[string]string{
+		"status": "ok",
+	})
+}
+
+// ReadinessHandler handles readiness probes, pinging every dependency and
+// reporting their status and latency. It answers 503 while a critical
+// dependency is down and 200 otherwise, degraded or not.
+func (h *HealthHandler) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	readiness := h.healthService.Ready(r.Context())
+	if readiness.Status == domain.ReadinessNotReady {
+		WriteSuccess(w, http.StatusServiceUnavailable, "Service is not ready", readiness)
+		return
+	}
+	WriteSuccess(w, http.StatusOK, "Service is ready", readiness)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	handler := NewHealthHandler(service.NewHealthService(time.Second))
+
+	rr := httptest.NewRecorder()
+	handler.LivenessHandler(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.LivenessHandler(rr, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name       string
+		checks     []service.DependencyCheck
+		wantCode   int
+		wantStatus string
+	}{
+		{"All up", []service.DependencyCheck{
+			{Name: "postgres", Critical: true, Check: up},
+			{Name: "kafka_rest_proxy", Check: up},
+		}, http.StatusOK, domain.ReadinessReady},
+		{"Optional dependency timing out", []service.DependencyCheck{
+			{Name: "postgres", Critical: true, Check: up},
+			{Name: "postgres_replica", Check: hang},
+		}, http.StatusOK, domain.ReadinessDegraded},
+		{"Critical dependency down", []service.DependencyCheck{
+			{Name: "postgres", Critical: true, Check: down},
+			{Name: "postgres_replica", Check: up},
+		}, http.StatusServiceUnavailable, domain.ReadinessNotReady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(service.NewHealthService(20*time.Millisecond, tt.checks...))
+
+			rr := httptest.NewRecorder()
+			handler.ReadinessHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("Expected %d, got %d: %s", tt.wantCode, rr.Code, rr.Body.String())
+			}
+
+			var response struct {
+				Data domain.Readiness `json:"data"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Data.Status != tt.wantStatus || len(response.Data.Dependencies) != len(tt.checks) {
+				t.Fatalf("Expected status %s for every dependency, got %+v", tt.wantStatus, response.Data)
+			}
+			for i, dependency := range response.Data.Dependencies {
+				if dependency.Name != tt.checks[i].Name {
+					t.Errorf("Expected dependency %d to be %s, got %s", i, tt.checks[i].Name, dependency.Name)
+				}
+				if !dependency.Up && dependency.Error == "" {
+					t.Errorf("Expected the error of %s to be reported", dependency.Name)
+				}
+			}
+		})
+	}
+}
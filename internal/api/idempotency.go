@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// maxIdempotentBodySize bounds the request body read for fingerprinting
+const maxIdempotentBodySize = 1 << 20
+
+// responseRecorder passes a response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// IdempotencyMiddleware honours the Idempotency-Key header on stock mutations
+// (POST requests under a /stock/ path). The first request with a key runs and
+// its response is stored; a retry with the same key and body receives the
+// stored response with an Idempotent-Replayed header instead of running again.
+func IdempotencyMiddleware(idempotency *service.IdempotencyService) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" || r.Method != http.MethodPost || !strings.Contains(r.URL.Path, "/stock/") {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize))
+			if err != nil {
+				WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Keys are shared by all tenants; hashing the tenant in makes
+			// another tenant's key a reused one instead of replaying it
+			hash := sha256.New()
+			if tenant := domain.TenantIDFromContext(r.Context()); tenant != "" {
+				io.WriteString(hash, tenant+"\n")
+			}
+			io.WriteString(hash, r.Method+" "+r.URL.Path+"\n")
+			hash.Write(body)
+
+			record, err := idempotency.Begin(r.Context(), key, hex.EncodeToString(hash.Sum(nil)))
+			switch {
+			case errors.Is(err, service.ErrInvalidIdempotencyKey):
+				WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+				return
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				WriteError(w, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", err.Error())
+				return
+			case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+				WriteError(w, http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS", err.Error())
+				return
+			case err != nil:
+				WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+				return
+			}
+
+			if record != nil {
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(record.StatusCode)
+				w.Write(record.Response)
+				return
+			}
+
+			// The operation runs once; store its outcome even if the client went
+			// away, and release the key if the handler panics
+			ctx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				if p := recover(); p != nil {
+					_ = idempotency.Complete(ctx, key, http.StatusInternalServerError, nil)
+					panic(p)
+				}
+			}()
+
+			handler.ServeHTTP(recorder, r)
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			if err := idempotency.Complete(ctx, key, recorder.status, recorder.body.Bytes()); err != nil {
+				slog.ErrorContext(ctx, "failed to store idempotent response", "key", key, "error", err)
+			}
+		})
+	}
+}
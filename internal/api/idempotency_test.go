@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// MockIdempotencyRepository implements IdempotencyRepository interface for testing
+type MockIdempotencyRepository struct {
+	records map[string]*domain.IdempotencyRecord
+}
+
+func NewMockIdempotencyRepository() *MockIdempotencyRepository {
+	return &MockIdempotencyRepository{
+		records: make(map[string]*domain.IdempotencyRecord),
+	}
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (bool, error) {
+	if existing, ok := m.records[record.Key]; ok && !existing.CreatedAt.Before(expiredBefore) {
+		return false, nil
+	}
+	record.CreatedAt = time.Now()
+	m.records[record.Key] = record
+	return true, nil
+}
+
+func (m *MockIdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	if record, ok := m.records[key]; ok {
+		return record, nil
+	}
+	return nil, errors.New("idempotency key not found")
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	now := time.Now()
+	m.records[key].StatusCode = statusCode
+	m.records[key].Response = response
+	m.records[key].CompletedAt = &now
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	delete(m.records, key)
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	calls := 0
+	status := http.StatusOK
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		WriteSuccess(w, status, "Stock added successfully", calls)
+	})
+
+	repo := NewMockIdempotencyRepository()
+	handler := IdempotencyMiddleware(service.NewIdempotencyService(repo, time.Hour))(next)
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/products/prod-1/stock/add", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("key-1", `{"quantity": 5}`)
+	replay := send("key-1", `{"quantity": 5}`)
+	if calls != 1 {
+		t.Fatalf("Expected the handler to run once, ran %d times", calls)
+	}
+	if replay.Code != first.Code || replay.Body.String() != first.Body.String() {
+		t.Errorf("Expected replay to return the stored response, got %d %s", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected Idempotent-Replayed header on replay")
+	}
+
+	if w := send("key-1", `{"quantity": 6}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a reused key with a different body, got %d", w.Code)
+	}
+
+	send("", `{"quantity": 5}`)
+	send("", `{"quantity": 5}`)
+	if calls != 3 {
+		t.Errorf("Expected requests without a key to always run, ran %d times", calls)
+	}
+
+	status = http.StatusInternalServerError
+	send("key-2", `{"quantity": 5}`)
+	status = http.StatusOK
+	if w := send("key-2", `{"quantity": 5}`); w.Code != http.StatusOK || calls != 5 {
+		t.Errorf("Expected a server error to release the key for a retry, got %d after %d calls", w.Code, calls)
+	}
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// IntercompanyHandler handles inter-company transfer requests
+type IntercompanyHandler struct {
+	intercompanyService *service.IntercompanyService
+}
+
+// NewIntercompanyHandler creates a new IntercompanyHandler
+func NewIntercompanyHandler(intercompanyService *service.IntercompanyService) *IntercompanyHandler {
+	return &IntercompanyHandler{
+		intercompanyService: intercompanyService,
+	}
+}
+
+// CreateTransferHandler handles transferring stock of the request's tenant
+// to another tenant
+func (h *IntercompanyHandler) CreateTransferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req domain.IntercompanyTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	transfer, err := h.intercompanyService.CreateTransfer(r.Context(), req)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Intercompany transfer recorded successfully", transfer)
+}
+
+// GetTransferHandler handles retrieving a transfer sent or received by the
+// request's tenant
+func (h *IntercompanyHandler) GetTransferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	transfer, err := h.intercompanyService.GetTransfer(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Intercompany transfer retrieved successfully", transfer)
+}
+
+// ListTransfersHandler handles listing the transfers sent or received by the
+// request's tenant
+func (h *IntercompanyHandler) ListTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	transfers, err := h.intercompanyService.ListTransfers(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Intercompany transfers retrieved successfully", transfers)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// JobHandler handles background job requests
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+// NewJobHandler creates a new JobHandler
+func NewJobHandler(jobService *service.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJobHandler handles retrieving the status and progress of a job, with
+// the location of its result once it succeeded
+func (h *JobHandler) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	job, err := h.jobService.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "JOB_FETCH_FAILED")
+		return
+	}
+
+	if job.Status == domain.JobSucceeded && job.HasResult {
+		job.ResultLocation = jobPath(job) + "/result"
+	}
+	WriteSuccess(w, http.StatusOK, "Job retrieved successfully", job)
+}
+
+// GetJobResultHandler handles downloading the document produced by a job
+func (h *JobHandler) GetJobResultHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	result, err := h.jobService.Result(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "JOB_FETCH_FAILED")
+		return
+	}
+
+	w.Header().Set("Content-Type", result.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(result.Data)
+}
+
+// wantsAsync reports whether the client asked to run the request as a
+// background job with "Prefer: respond-async"
+func wantsAsync(r *http.Request) bool {
+	for _, header := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// startJob starts a job on behalf of the caller and answers 202 Accepted
+// with the location to poll
+func startJob(w http.ResponseWriter, r *http.Request, jobs *service.JobService, jobType domain.JobType, run service.JobFunc) {
+	var createdBy string
+	if principal := RequestPrincipal(r.Context()); principal != nil {
+		createdBy = principal.Subject
+	}
+
+	job, err := jobs.Start(r.Context(), jobType, createdBy, run)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "JOB_START_FAILED")
+		return
+	}
+
+	w.Header().Set("Location", jobPath(job))
+	WriteSuccess(w, http.StatusAccepted, "Job accepted", job)
+}
+
+// jobPath is the URL path a job is polled at
+func jobPath(job *domain.Job) string {
+	return "/api/jobs/" + job.ID
+}
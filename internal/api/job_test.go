@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsAsync(t *testing.T) {
+	tests := []struct {
+		prefer string
+		want   bool
+	}{
+		{"", false},
+		{"respond-async", true},
+		{"return=minimal, Respond-Async", true},
+		{"wait=10", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/api/catalog/sync", nil)
+		if tt.prefer != "" {
+			req.Header.Set("Prefer", tt.prefer)
+		}
+		if got := wantsAsync(req); got != tt.want {
+			t.Errorf("wantsAsync(%q) = %v, want %v", tt.prefer, got, tt.want)
+		}
+	}
+}
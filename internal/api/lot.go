@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// defaultLotExpiryDays is the window of the expiring lots listing when days
+// is omitted
+const defaultLotExpiryDays = 30
+
+// LotHandler handles stock lot requests
+type LotHandler struct {
+	lotService *service.LotService
+}
+
+// NewLotHandler creates a new LotHandler
+func NewLotHandler(lotService *service.LotService) *LotHandler {
+	return &LotHandler{
+		lotService: lotService,
+	}
+}
+
+// ReceiveLotHandler handles receiving stock of a product into a lot
+func (h *LotHandler) ReceiveLotHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req domain.LotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	req.ProductID = r.PathValue("id")
+
+	lot, err := h.lotService.Receive(r.Context(), req)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Lot received successfully", lot)
+}
+
+// ListLotsHandler handles listing the lots of a product, first expiring
+// first, filtered by ?location=; ?open=true lists only the lots with units
+// in stock
+func (h *LotHandler) ListLotsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := domain.LotFilter{Location: query.Get("location")}
+	if value := query.Get("open"); value != "" {
+		open, err := strconv.ParseBool(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "open must be true or false")
+			return
+		}
+		filter.Open = open
+	}
+
+	limit, offset := parsePagination(r)
+	lots, err := h.lotService.ListLots(r.Context(), r.PathValue("id"), filter, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Lots retrieved successfully", lots)
+}
+
+// ExpiringLotsHandler handles listing the lots with units in stock that
+// expire within ?days= days (default 30), including the expired ones not
+// written off yet, optionally only those at ?location=
+func (h *LotHandler) ExpiringLotsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	days := defaultLotExpiryDays
+	if value := query.Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "days must be an integer")
+			return
+		}
+		days = parsed
+	}
+
+	limit, offset := parsePagination(r)
+	lots, err := h.lotService.Expiring(r.Context(), days, query.Get("location"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Expiring lots retrieved successfully", lots)
+}
+
+// WriteOffExpiredHandler handles writing off the expired lots now rather
+// than on the next scheduled run
+func (h *LotHandler) WriteOffExpiredHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	writeOffs, err := h.lotService.WriteOffExpired(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Expired lots written off successfully", writeOffs)
+}
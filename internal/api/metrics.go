@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName identifies the instruments of this package
+const meterName = "github.com/bhnrathore/distributed-inventory-system/internal/api"
+
+// unmatchedRoute labels requests that matched no registered route
+const unmatchedRoute = "unmatched"
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush event streams
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// MetricsMiddleware counts requests and records their latency per route,
+// method and status. The route is the ServeMux pattern that served the
+// request, which keeps label cardinality bounded; the middleware must
+// therefore wrap the mux directly, so it sees the pattern the mux records.
+func MetricsMiddleware(provider metric.MeterProvider) (func(http.Handler) http.Handler, error) {
+	meter := provider.Meter(meterName)
+
+	requests, err := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("HTTP requests served, by route, method and status"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request counter: %w", err)
+	}
+
+	duration, err := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("HTTP request latency, by route, method and status"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request duration histogram: %w", err)
+	}
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			handler.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			attrs := metric.WithAttributes(
+				attribute.String("route", routeLabel(r.Pattern)),
+				attribute.String("method", r.Method),
+				attribute.String("status", strconv.Itoa(status)),
+			)
+			requests.Add(r.Context(), 1, attrs)
+			duration.Record(r.Context(), time.Since(start).Seconds(), attrs)
+		})
+	}, nil
+}
+
+// routeLabel strips the method from a ServeMux pattern such as
+// "GET /api/products/{id}"
+func routeLabel(pattern string) string {
+	if pattern == "" {
+		return unmatchedRoute
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
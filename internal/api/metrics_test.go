@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricsMiddlewareLabelsRoutes(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/warehouses/{id}", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "Warehouse not found")
+	})
+
+	middleware, err := MetricsMiddleware(provider)
+	if err != nil {
+		t.Fatalf("Failed to create metrics middleware: %v", err)
+	}
+	handler := middleware(mux)
+
+	for _, path := range []string{"/api/warehouses/a", "/api/warehouses/b", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+
+	counts := make(map[string]int64)
+	var histogramPoints int
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					route, _ := point.Attributes.Value(attribute.Key("route"))
+					status, _ := point.Attributes.Value(attribute.Key("status"))
+					counts[route.AsString()+" "+status.AsString()] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				histogramPoints += len(data.DataPoints)
+			}
+		}
+	}
+
+	if counts["/api/warehouses/{id} 404"] != 2 {
+		t.Errorf("Expected 2 requests on the warehouse route, got %v", counts)
+	}
+	if counts[unmatchedRoute+" 404"] != 1 {
+		t.Errorf("Expected 1 unmatched request, got %v", counts)
+	}
+	if histogramPoints != 2 {
+		t.Errorf("Expected latency recorded for 2 label sets, got %d", histogramPoints)
+	}
+}
@@ -1,34 +1,102 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/i18n"
+	"github.com/bhnrathore/distributed-inventory-system/internal/logging"
+	"github.com/google/uuid"
 )
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
 	Code    int    `json:"code"`
 	Time    string `json:"timestamp"`
+
+	// RequestID identifies the request in server logs
+	RequestID string `json:"request_id,omitempty"`
+
+	// DocumentationURL points to the description of the error codes
+	DocumentationURL string `json:"documentation_url,omitempty"`
+
+	// Stock is the stock level that rejected an INSUFFICIENT_STOCK movement,
+	// when known, and the deltas attempted
+	Stock *domain.StockUpdateError `json:"stock,omitempty"`
+
+	// SKU is the SKU a DUPLICATE_SKU product conflicts on
+	SKU string `json:"sku,omitempty"`
 }
 
 // SuccessResponse wraps a successful response
 type SuccessResponse struct {
-	Data      interface{} `json:"data"`
-	Message   string      `json:"message"`
-	Time      string      `json:"timestamp"`
+	Data    interface{} `json:"data"`
+	Message string      `json:"message"`
+	Time    string      `json:"timestamp"`
+}
+
+// RequestIDHeader carries the ID that correlates a request with its logs
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware propagates the client's X-Request-ID, or generates one
+// when it is missing or unusable, into the request context and echoes it in
+// the response header. Error responses include it as request_id.
+func RequestIDMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := logging.WithRequestID(r.Context(), id)
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
-// LoggingMiddleware logs HTTP requests
+// validRequestID accepts IDs of printable ASCII without spaces, so that they
+// cannot break log lines or headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// LoggingMiddleware logs one record per HTTP request with its outcome
 func LoggingMiddleware(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		log.Printf("[%s] %s %s", r.Method, r.RequestURI, r.RemoteAddr)
-		handler.ServeHTTP(w, r)
-		log.Printf("Request completed in %v", time.Since(start))
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		handler.ServeHTTP(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		slog.InfoContext(r.Context(), "request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+		)
 	})
 }
 
@@ -46,16 +114,29 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
 	json.NewEncoder(w).Encode(data)
 }
 
-// WriteError writes a JSON error response
+// WriteError writes a JSON error response. When LanguageMiddleware negotiated
+// a non-default language, the message is translated by error code and the
+// original message is kept in details.
 func WriteError(w http.ResponseWriter, statusCode int, err string, message string) {
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(newErrorResponse(w.Header(), statusCode, err, message))
+}
+
+// newErrorResponse builds the error response for the given response headers,
+// which carry the request ID and the negotiated language
+func newErrorResponse(header http.Header, statusCode int, err string, message string) ErrorResponse {
 	response := ErrorResponse{
-		Error:   err,
-		Message: message,
-		Code:    statusCode,
-		Time:    time.Now().UTC().Format(time.RFC3339),
+		Error:     err,
+		Message:   message,
+		Code:      statusCode,
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		RequestID: header.Get(RequestIDHeader),
 	}
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	if localized, ok := i18n.Translate(header.Get("Content-Language"), err); ok {
+		response.Message = localized
+		response.Details = message
+	}
+	return response
 }
 
 // WriteSuccess writes a JSON success response
@@ -69,15 +150,80 @@ func WriteSuccess(w http.ResponseWriter, statusCode int, message string, data in
 	json.NewEncoder(w).Encode(response)
 }
 
-// RecoveryMiddleware recovers from panics
+// RecoveryMiddleware recovers from panics. http.ErrAbortHandler is passed on,
+// so a handler that fails mid-stream can abort the response.
 func RecoveryMiddleware(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Panic: %v", err)
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				slog.ErrorContext(r.Context(), "panic while serving request", "panic", err, "path", r.URL.Path)
 				WriteError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred")
 			}
 		}()
 		handler.ServeHTTP(w, r)
 	})
 }
+
+type reportingLocationKey struct{}
+
+// TimezoneMiddleware resolves the reporting timezone of a request from the tz
+// query parameter or the X-Timezone header (IANA names such as
+// "Europe/Berlin"), falling back to defaultLoc. Reports and period filters use
+// it so that day boundaries follow the warehouse's local day instead of UTC.
+func TimezoneMiddleware(defaultLoc *time.Location) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			name := r.URL.Query().Get("tz")
+			if name == "" {
+				name = r.Header.Get("X-Timezone")
+			}
+
+			loc := defaultLoc
+			if name != "" {
+				parsed, err := time.LoadLocation(name)
+				if err != nil {
+					WriteError(w, http.StatusBadRequest, "INVALID_TIMEZONE", "Unknown timezone: "+name)
+					return
+				}
+				loc = parsed
+			}
+
+			ctx := context.WithValue(r.Context(), reportingLocationKey{}, loc)
+			handler.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ReportingLocation returns the reporting timezone resolved for the request,
+// or UTC when none was resolved
+func ReportingLocation(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(reportingLocationKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+type languageKey struct{}
+
+// LanguageMiddleware negotiates the response language from Accept-Language,
+// announces it in the Content-Language header and stores it in the context
+func LanguageMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", lang)
+
+		ctx := context.WithValue(r.Context(), languageKey{}, lang)
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestLanguage returns the language negotiated for the request
+func RequestLanguage(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
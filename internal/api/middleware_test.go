@@ -0,0 +1,295 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/logging"
+)
+
+func TestTimezoneMiddleware(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		header     string
+		wantStatus int
+		wantLoc    string
+	}{
+		{"Default location", "/api/audit/export", "", http.StatusOK, "UTC"},
+		{"Query parameter", "/api/audit/export?tz=Europe/Berlin", "", http.StatusOK, "Europe/Berlin"},
+		{"Header", "/api/audit/export", "Asia/Kolkata", http.StatusOK, "Asia/Kolkata"},
+		{"Unknown timezone", "/api/audit/export?tz=Mars/Olympus", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLoc string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLoc = ReportingLocation(r.Context()).String()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Timezone", tt.header)
+			}
+			rr := httptest.NewRecorder()
+			TimezoneMiddleware(time.UTC)(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if gotLoc != tt.wantLoc {
+				t.Errorf("Expected location %q, got %q", tt.wantLoc, gotLoc)
+			}
+		})
+	}
+
+	// Plain dates resolve to local midnight in the reporting timezone
+	var from, to time.Time
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		from, to, err = parsePeriod(r)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/api/audit/export?from=2024-03-01&to=2024-03-01&tz=Europe/Berlin", nil)
+	TimezoneMiddleware(time.UTC)(next).ServeHTTP(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("Failed to parse period: %v", err)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, berlin); !from.Equal(want) {
+		t.Errorf("Expected from %v, got %v", want, from)
+	}
+	if want := time.Date(2024, 3, 2, 0, 0, 0, 0, berlin); !to.Equal(want) {
+		t.Errorf("Expected to %v, got %v", want, to)
+	}
+}
+
+func TestLanguageMiddlewareLocalizesErrors(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "product not found")
+	})
+
+	tests := []struct {
+		name         string
+		acceptLang   string
+		wantLanguage string
+		wantMessage  string
+		wantDetails  string
+	}{
+		{"Default language", "", "en", "product not found", ""},
+		{"German", "de-DE,de;q=0.9", "de", "Die angeforderte Ressource wurde nicht gefunden", "product not found"},
+		{"Unsupported language", "ja", "en", "product not found", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/products/missing", nil)
+			if tt.acceptLang != "" {
+				req.Header.Set("Accept-Language", tt.acceptLang)
+			}
+			rr := httptest.NewRecorder()
+			LanguageMiddleware(next).ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Content-Language"); got != tt.wantLanguage {
+				t.Errorf("Expected Content-Language %q, got %q", tt.wantLanguage, got)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, response.Message)
+			}
+			if response.Details != tt.wantDetails {
+				t.Errorf("Expected details %q, got %q", tt.wantDetails, response.Details)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{"Propagated", "client-req-42", true},
+		{"Generated when missing", "", false},
+		{"Replaced when unusable", "bad id\r\n", false},
+		{"Replaced when too long", strings.Repeat("x", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCtxID string
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotCtxID = logging.RequestID(r.Context())
+				WriteError(w, http.StatusNotFound, "NOT_FOUND", "missing")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/products/unknown", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			id := rr.Header().Get(RequestIDHeader)
+			if id == "" || id != gotCtxID {
+				t.Fatalf("Expected the response header %q to match the context ID %q", id, gotCtxID)
+			}
+			if (id == tt.header) != tt.wantSame {
+				t.Errorf("Got ID %q for header %q", id, tt.header)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Invalid error response: %v", err)
+			}
+			if response.RequestID != id {
+				t.Errorf("Expected request_id %q in the error response, got %q", id, response.RequestID)
+			}
+		})
+	}
+}
+
+func TestErrorShapingMiddleware(t *testing.T) {
+	const docsURL = "https://docs.example.com/errors"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("id") {
+		case "known":
+			WriteSuccess(w, http.StatusOK, "ok", nil)
+		case "missing":
+			WriteError(w, http.StatusNotFound, "NOT_FOUND", "product not found")
+		case "plain":
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		case "panic":
+			panic("boom")
+		}
+	})
+	handler := RequestIDMiddleware(ErrorShapingMiddleware(docsURL)(LanguageMiddleware(mux)))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		acceptLang  string
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{"Standard error completed", http.MethodGet, "/api/products/missing", "", http.StatusNotFound, "NOT_FOUND", "product not found"},
+		{"Plain text error", http.MethodGet, "/api/products/plain", "", http.StatusBadGateway, "BAD_GATEWAY", "upstream unavailable"},
+		{"Router fallthrough", http.MethodGet, "/nowhere", "", http.StatusNotFound, "NOT_FOUND", "404 page not found"},
+		{"Router method mismatch", http.MethodDelete, "/api/products/known", "", http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Method Not Allowed"},
+		{"Localized fallthrough", http.MethodGet, "/nowhere", "de", http.StatusNotFound, "NOT_FOUND", "Die angeforderte Ressource wurde nicht gefunden"},
+		{"Panic", http.MethodGet, "/api/products/panic", "", http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.acceptLang != "" {
+				req.Header.Set("Accept-Language", tt.acceptLang)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected a JSON content type, got %q", ct)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Invalid error response: %v", err)
+			}
+			if response.Error != tt.wantCode || response.Code != tt.wantStatus || response.Message != tt.wantMessage {
+				t.Errorf("Expected %s/%d %q, got %+v", tt.wantCode, tt.wantStatus, tt.wantMessage, response)
+			}
+			if response.RequestID == "" || response.RequestID != rr.Header().Get(RequestIDHeader) {
+				t.Errorf("Expected request_id %q, got %q", rr.Header().Get(RequestIDHeader), response.RequestID)
+			}
+			if response.DocumentationURL != docsURL {
+				t.Errorf("Expected documentation_url %q, got %q", docsURL, response.DocumentationURL)
+			}
+		})
+	}
+
+	// Success responses pass through untouched
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/products/known", nil))
+	if rr.Code != http.StatusOK || strings.Contains(rr.Body.String(), "documentation_url") {
+		t.Errorf("Expected the success response unchanged, got %d %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestWriteServiceErrorReportsStock(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/products/{id}/stock/remove", func(w http.ResponseWriter, r *http.Request) {
+		err := fmt.Errorf("failed to remove stock: %w", &domain.StockUpdateError{
+			InventoryID: "inv-1", Quantity: 10, Reserved: 8, QuantityDelta: -5,
+		})
+		writeServiceError(w, err, http.StatusInternalServerError, "REMOVE_STOCK_FAILED")
+	})
+	handler := ErrorShapingMiddleware("")(mux)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/products/prod-1/stock/remove", nil))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", rr.Code)
+	}
+	var response ErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Invalid error response: %v", err)
+	}
+	if response.Error != "INSUFFICIENT_STOCK" {
+		t.Errorf("Expected INSUFFICIENT_STOCK, got %s", response.Error)
+	}
+	want := domain.StockUpdateError{InventoryID: "inv-1", Quantity: 10, Reserved: 8, QuantityDelta: -5}
+	if response.Stock == nil || *response.Stock != want {
+		t.Errorf("Expected stock %+v, got %+v", want, response.Stock)
+	}
+	if !strings.Contains(response.Message, "quantity 10, reserved 8; attempted quantity -5, reserved +0") {
+		t.Errorf("Expected the stock in the message, got %q", response.Message)
+	}
+}
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := ReadOnlyMiddleware(next)
+
+	for method, want := range map[string]int{
+		http.MethodGet:    http.StatusOK,
+		http.MethodHead:   http.StatusOK,
+		http.MethodPost:   http.StatusServiceUnavailable,
+		http.MethodPut:    http.StatusServiceUnavailable,
+		http.MethodDelete: http.StatusServiceUnavailable,
+	} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(method, "/api/products", nil))
+		if rr.Code != want {
+			t.Errorf("%s: expected status %d, got %d", method, want, rr.Code)
+		}
+		if want != http.StatusOK && !strings.Contains(rr.Body.String(), "READ_ONLY") {
+			t.Errorf("%s: expected READ_ONLY error, got %s", method, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// OrderHandler handles order requests
+type OrderHandler struct {
+	orderService *service.OrderService
+}
+
+// NewOrderHandler creates a new OrderHandler
+func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+	}
+}
+
+// CreateOrderRequest represents an order creation request. ID is the
+// client's order ID.
+type CreateOrderRequest struct {
+	ID         string                        `json:"id"`
+	Lines      []domain.OrderReservationLine `json:"lines"`
+	TTLSeconds int64                         `json:"ttl_seconds"`
+}
+
+// CreateOrderHandler handles creating an order, reserving all of its lines
+func (h *OrderHandler) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	order, err := h.orderService.CreateOrder(r.Context(), req.ID, req.Lines, ttl)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Order reserved successfully", order)
+}
+
+// GetOrderHandler handles retrieving an order
+func (h *OrderHandler) GetOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	order, err := h.orderService.GetOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Order retrieved successfully", order)
+}
+
+// ListOrdersHandler handles listing orders, optionally filtered by the
+// status query parameter
+func (h *OrderHandler) ListOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	status := domain.OrderStatus(strings.ToUpper(r.URL.Query().Get("status")))
+
+	orders, err := h.orderService.ListOrders(r.Context(), status, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Orders retrieved successfully", orders)
+}
+
+// ConfirmOrderHandler handles confirming an order, shipping all of its lines
+func (h *OrderHandler) ConfirmOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	order, err := h.orderService.ConfirmOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Order confirmed successfully", order)
+}
+
+// CancelOrderHandler handles cancelling an order, releasing all of its lines
+func (h *OrderHandler) CancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Order cancelled successfully", order)
+}
+
+// writeOrderError maps a failed order transition to its response
+func writeOrderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrOrderNotPending) {
+		WriteError(w, http.StatusConflict, "ORDER_NOT_PENDING", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrReservationNotPending) {
+		WriteError(w, http.StatusConflict, "RESERVATION_NOT_PENDING", err.Error())
+		return
+	}
+	writeStockOperationError(w, err)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// OrderReservationHandler handles multi-line reservation and backorder requests
+type OrderReservationHandler struct {
+	orderReservationService *service.OrderReservationService
+}
+
+// NewOrderReservationHandler creates a new OrderReservationHandler
+func NewOrderReservationHandler(orderReservationService *service.OrderReservationService) *OrderReservationHandler {
+	return &OrderReservationHandler{
+		orderReservationService: orderReservationService,
+	}
+}
+
+// ReserveManyRequest represents a multi-line reservation request. Policy
+// defaults to all_or_nothing.
+type ReserveManyRequest struct {
+	Reference  string                        `json:"reference"`
+	Policy     domain.ReservationPolicy      `json:"policy"`
+	Lines      []domain.OrderReservationLine `json:"lines"`
+	TTLSeconds int64                         `json:"ttl_seconds"`
+}
+
+// ReserveManyHandler handles reserving the lines of an order
+func (h *OrderReservationHandler) ReserveManyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req ReserveManyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+	if req.Policy == "" {
+		req.Policy = domain.ReservationPolicyAllOrNothing
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	order, err := h.orderReservationService.ReserveMany(r.Context(), req.Reference, req.Lines, req.Policy, ttl)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	message := "Order reserved successfully"
+	if !order.Complete {
+		message = "Order partially reserved, shortfalls backordered"
+	}
+	WriteSuccess(w, http.StatusCreated, message, order)
+}
+
+// ListBackordersHandler handles listing backorders, optionally filtered by
+// the product_id and status query parameters
+func (h *OrderReservationHandler) ListBackordersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	status := domain.BackorderStatus(strings.ToUpper(r.URL.Query().Get("status")))
+
+	backorders, err := h.orderReservationService.ListBackorders(r.Context(), r.URL.Query().Get("product_id"), status, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Backorders retrieved successfully", backorders)
+}
+
+// CancelBackorderHandler handles cancelling an open backorder
+func (h *OrderReservationHandler) CancelBackorderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	backorder, err := h.orderReservationService.CancelBackorder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrBackorderNotOpen) {
+			WriteError(w, http.StatusConflict, "BACKORDER_NOT_OPEN", err.Error())
+			return
+		}
+		writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Backorder cancelled successfully", backorder)
+}
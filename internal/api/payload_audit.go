@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// PayloadAuditMiddleware records the full request and response of a sampled
+// share of mutating (POST, PUT, PATCH, DELETE) requests
+func PayloadAuditMiddleware(payloadAudit *service.PayloadAuditService) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				handler.ServeHTTP(w, r)
+				return
+			}
+			if !payloadAudit.Sampled() {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			// Keep one byte more than the sample size so truncation is detected
+			var requestBody bytes.Buffer
+			if r.Body != nil {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, &limitedBuffer{buf: &requestBody, limit: service.MaxPayloadSampleSize + 1}), r.Body}
+			}
+
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: w}
+			handler.ServeHTTP(recorder, r)
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			sample := &domain.PayloadSample{
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				Query:        r.URL.RawQuery,
+				StatusCode:   recorder.status,
+				RequestBody:  requestBody.String(),
+				ResponseBody: recorder.body.String(),
+				DurationMs:   time.Since(start).Milliseconds(),
+			}
+			if err := payloadAudit.Record(context.WithoutCancel(r.Context()), sample); err != nil {
+				slog.ErrorContext(r.Context(), "failed to record payload sample", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+		})
+	}
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// PayloadAuditHandler handles payload sample requests
+type PayloadAuditHandler struct {
+	payloadAuditService *service.PayloadAuditService
+}
+
+// NewPayloadAuditHandler creates a new PayloadAuditHandler
+func NewPayloadAuditHandler(payloadAuditService *service.PayloadAuditService) *PayloadAuditHandler {
+	return &PayloadAuditHandler{
+		payloadAuditService: payloadAuditService,
+	}
+}
+
+// ListSamplesHandler handles listing recorded payload samples; the optional
+// path query parameter filters by request path
+func (h *PayloadAuditHandler) ListSamplesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	samples, err := h.payloadAuditService.ListSamples(r.Context(), r.URL.Query().Get("path"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Payload samples retrieved successfully", samples)
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// MockPayloadSampleRepository implements PayloadSampleRepository interface for testing
+type MockPayloadSampleRepository struct {
+	samples []*domain.PayloadSample
+}
+
+func (m *MockPayloadSampleRepository) Create(ctx context.Context, sample *domain.PayloadSample) error {
+	sample.CreatedAt = time.Now()
+	m.samples = append(m.samples, sample)
+	return nil
+}
+
+func (m *MockPayloadSampleRepository) List(ctx context.Context, path string, limit, offset int) ([]*domain.PayloadSample, error) {
+	return m.samples, nil
+}
+
+func (m *MockPayloadSampleRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestPayloadAuditMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	})
+
+	tests := []struct {
+		name        string
+		percent     float64
+		method      string
+		body        string
+		wantSamples int
+		wantTrunc   bool
+	}{
+		{"Sampling disabled", 0, http.MethodPost, `{"quantity":5}`, 0, false},
+		{"Read requests are not sampled", 100, http.MethodGet, "", 0, false},
+		{"Mutating request sampled", 100, http.MethodPost, `{"quantity":5}`, 1, false},
+		{"Oversized body truncated", 100, http.MethodPut, `"` + strings.Repeat("x", service.MaxPayloadSampleSize) + `"`, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockPayloadSampleRepository{}
+			payloadAudit, err := service.NewPayloadAuditService(repo, tt.percent, time.Hour)
+			if err != nil {
+				t.Fatalf("Failed to create service: %v", err)
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/products/prod-1/stock/add?dry_run=1", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			PayloadAuditMiddleware(payloadAudit)(next).ServeHTTP(rr, req)
+
+			// The handler must see the full body regardless of sampling
+			if want := `{"echo":` + tt.body + `}`; rr.Body.String() != want {
+				t.Errorf("Expected response to echo the request body, got %d bytes", rr.Body.Len())
+			}
+			if len(repo.samples) != tt.wantSamples {
+				t.Fatalf("Expected %d samples, got %d", tt.wantSamples, len(repo.samples))
+			}
+			if tt.wantSamples == 0 {
+				return
+			}
+
+			sample := repo.samples[0]
+			if sample.Method != tt.method || sample.Path != "/api/products/prod-1/stock/add" || sample.Query != "dry_run=1" {
+				t.Errorf("Unexpected request metadata: %s %s?%s", sample.Method, sample.Path, sample.Query)
+			}
+			if sample.StatusCode != http.StatusCreated {
+				t.Errorf("Expected status %d, got %d", http.StatusCreated, sample.StatusCode)
+			}
+			if sample.Truncated != tt.wantTrunc {
+				t.Errorf("Expected truncated=%v, got %v", tt.wantTrunc, sample.Truncated)
+			}
+			if !tt.wantTrunc && sample.RequestBody != tt.body {
+				t.Errorf("Expected request body %q, got %q", tt.body, sample.RequestBody)
+			}
+			if len(sample.RequestBody) > service.MaxPayloadSampleSize || len(sample.ResponseBody) > service.MaxPayloadSampleSize {
+				t.Error("Expected bodies to be capped at MaxPayloadSampleSize")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+	"go.yaml.in/yaml/v3"
+)
+
+// PublicAccess marks policy entries whose routes need no authentication
+const PublicAccess = "public"
+
+// defaultPolicy is the built-in route authorization policy
+//
+//go:embed policy.yaml
+var defaultPolicy []byte
+
+// AuthorizationPolicy maps route patterns, as registered on the mux, to the
+// role they require. A pattern without a method may be split by method with
+// "METHOD pattern" entries.
+type AuthorizationPolicy struct {
+	rules map[string]policyRule
+}
+
+// policyRule is the access a policy entry grants
+type policyRule struct {
+	role   domain.Role
+	public bool
+}
+
+// DefaultAuthorizationPolicy returns the built-in policy of policy.yaml
+func DefaultAuthorizationPolicy() *AuthorizationPolicy {
+	policy, err := ParseAuthorizationPolicy(defaultPolicy)
+	if err != nil {
+		panic("invalid built-in authorization policy: " + err.Error())
+	}
+	return policy
+}
+
+// ParseAuthorizationPolicy parses a policy document: a routes mapping of
+// "[METHOD ]/path" patterns to reader, operator, admin, supplier or public.
+// Unknown keys and duplicate patterns are rejected.
+func ParseAuthorizationPolicy(data []byte) (*AuthorizationPolicy, error) {
+	var doc struct {
+		Routes map[string]string `yaml:"routes"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy: %w", err)
+	}
+
+	var errs []error
+	rules := make(map[string]policyRule, len(doc.Routes))
+	for pattern, access := range doc.Routes {
+		method, path := splitPattern(pattern)
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t") || (method != "" && strings.ToUpper(method) != method) {
+			errs = append(errs, fmt.Errorf("route %q: expected \"[METHOD ]/path\"", pattern))
+			continue
+		}
+		if access == PublicAccess {
+			rules[pattern] = policyRule{public: true}
+			continue
+		}
+		role, err := domain.ParseRole(access)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("route %q: %w", pattern, err))
+			continue
+		}
+		rules[pattern] = policyRule{role: role}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid authorization policy: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("invalid authorization policy: no routes")
+	}
+	return &AuthorizationPolicy{rules: rules}, nil
+}
+
+// Len returns the number of policy entries
+func (p *AuthorizationPolicy) Len() int {
+	return len(p.rules)
+}
+
+// lookup finds the entry for a request with the given method matched by
+// pattern. HEAD falls back to GET entries, as on the mux.
+func (p *AuthorizationPolicy) lookup(method, pattern string) (policyRule, bool) {
+	if rule, ok := p.rules[pattern]; ok {
+		return rule, true
+	}
+	if m, _ := splitPattern(pattern); m != "" {
+		return policyRule{}, false
+	}
+	if rule, ok := p.rules[method+" "+pattern]; ok {
+		return rule, true
+	}
+	if method == http.MethodHead {
+		rule, ok := p.rules[http.MethodGet+" "+pattern]
+		return rule, ok
+	}
+	return policyRule{}, false
+}
+
+// splitPattern splits a route pattern into its method, if any, and path
+func splitPattern(pattern string) (method, path string) {
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		return method, strings.TrimLeft(path, " ")
+	}
+	return "", pattern
+}
+
+// PolicyMux is a ServeMux that authorizes every request against an
+// AuthorizationPolicy before routing it, so that permissions are declared in
+// one table rather than at each handler. Requests matching a route without a
+// policy entry are refused with 403; requests matching no route go on to the
+// mux's 404 and 405 responses. With authentication disabled every request is
+// let through.
+type PolicyMux struct {
+	*http.ServeMux
+	auth     *service.AuthService
+	policy   *AuthorizationPolicy
+	patterns []string
+}
+
+// NewPolicyMux creates a new PolicyMux
+func NewPolicyMux(auth *service.AuthService, policy *AuthorizationPolicy) *PolicyMux {
+	return &PolicyMux{
+		ServeMux: http.NewServeMux(),
+		auth:     auth,
+		policy:   policy,
+	}
+}
+
+// Handle registers the handler for the given pattern
+func (m *PolicyMux) Handle(pattern string, handler http.Handler) {
+	m.ServeMux.Handle(pattern, handler)
+	m.patterns = append(m.patterns, pattern)
+}
+
+// HandleFunc registers the handler function for the given pattern
+func (m *PolicyMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.ServeMux.HandleFunc(pattern, handler)
+	m.patterns = append(m.patterns, pattern)
+}
+
+// Verify checks that the policy and the registered routes match: every route
+// has an entry and every entry names a route. It reports every mismatch.
+func (m *PolicyMux) Verify() error {
+	var errs []error
+	covered := make(map[string]bool, len(m.policy.rules))
+	for _, pattern := range m.patterns {
+		method, _ := splitPattern(pattern)
+		found := false
+		for key := range m.policy.rules {
+			if key == pattern || (method == "" && strings.HasSuffix(key, " "+pattern)) {
+				covered[key] = true
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("route %q has no authorization policy entry", pattern))
+		}
+	}
+
+	var stale []string
+	for key := range m.policy.rules {
+		if !covered[key] {
+			stale = append(stale, key)
+		}
+	}
+	slices.Sort(stale)
+	for _, key := range stale {
+		errs = append(errs, fmt.Errorf("authorization policy entry %q matches no route", key))
+	}
+	return errors.Join(errs...)
+}
+
+// ServeHTTP authorizes the request against the policy entry of the route it
+// matches and dispatches it
+func (m *PolicyMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !m.auth.Enabled() {
+		m.ServeMux.ServeHTTP(w, r)
+		return
+	}
+
+	_, pattern := m.ServeMux.Handler(r)
+	if pattern != "" {
+		rule, ok := m.policy.lookup(r.Method, pattern)
+		// Denied requests never reach the mux, which sets the matched
+		// pattern; set it here so request metrics keep the route
+		switch {
+		case !ok:
+			r.Pattern = pattern
+			slog.WarnContext(r.Context(), "request refused: route has no authorization policy entry", "method", r.Method, "route", pattern)
+			WriteError(w, http.StatusForbidden, "FORBIDDEN", "No authorization policy for this route")
+			return
+		case !rule.public && !authorize(m.auth, w, r, rule.role):
+			r.Pattern = pattern
+			return
+		}
+	}
+
+	m.ServeMux.ServeHTTP(w, r)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestParseAuthorizationPolicy(t *testing.T) {
+	if DefaultAuthorizationPolicy().Len() == 0 {
+		t.Error("Expected the built-in policy to have entries")
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"Unknown role", "routes:\n  \"GET /a\": owner\n", "unknown role"},
+		{"Malformed pattern", "routes:\n  \"GET a\": reader\n", "[METHOD ]/path"},
+		{"Lower-case method", "routes:\n  \"get /a\": reader\n", "[METHOD ]/path"},
+		{"Unknown key", "rules:\n  \"GET /a\": reader\n", "rules"},
+		{"Duplicate pattern", "routes:\n  \"GET /a\": reader\n  \"GET /a\": admin\n", "already defined"},
+		{"Empty", "routes: {}\n", "no routes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAuthorizationPolicy([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPolicyMux(t *testing.T) {
+	auth, err := service.NewAuthService([]service.APIKey{
+		{Name: "dashboard", Role: domain.RoleReader, Key: "reader-key"},
+		{Name: "erp", Role: domain.RoleOperator, Key: "operator-key"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+	policy, err := ParseAuthorizationPolicy([]byte(`
+routes:
+  "/healthz": public
+  "GET /api/warehouses": reader
+  "POST /api/warehouses": admin
+  "GET /api/products/": reader
+  "POST /api/products/": operator
+`))
+	if err != nil {
+		t.Fatalf("ParseAuthorizationPolicy() error = %v", err)
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+	mux := NewPolicyMux(auth, policy)
+	mux.HandleFunc("/healthz", ok)
+	mux.HandleFunc("GET /api/warehouses", ok)
+	mux.HandleFunc("POST /api/warehouses", ok)
+	mux.HandleFunc("/api/products/", ok)
+	mux.HandleFunc("GET /api/stocktakes", ok)
+	handler := AuthMiddleware(auth)(mux)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		key        string
+		wantStatus int
+	}{
+		{"Public route", http.MethodGet, "/healthz", "", http.StatusNoContent},
+		{"No credentials", http.MethodGet, "/api/warehouses", "", http.StatusUnauthorized},
+		{"Sufficient role", http.MethodGet, "/api/warehouses", "reader-key", http.StatusNoContent},
+		{"Insufficient role", http.MethodPost, "/api/warehouses", "operator-key", http.StatusForbidden},
+		{"Entry by method", http.MethodPost, "/api/products/prod-1/stock/add", "operator-key", http.StatusNoContent},
+		{"Entry by method denied", http.MethodPost, "/api/products/prod-1/stock/add", "reader-key", http.StatusForbidden},
+		{"HEAD uses the GET entry", http.MethodHead, "/api/products/prod-1", "reader-key", http.StatusNoContent},
+		{"Method without entry", http.MethodDelete, "/api/products/prod-1", "operator-key", http.StatusForbidden},
+		{"Route without entry", http.MethodGet, "/api/stocktakes", "operator-key", http.StatusForbidden},
+		{"No matching route", http.MethodGet, "/api/unknown", "", http.StatusNotFound},
+		{"Method not allowed", http.MethodDelete, "/api/warehouses", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	// Routes and entries must match one to one
+	err = mux.Verify()
+	if err == nil || !strings.Contains(err.Error(), `route "GET /api/stocktakes" has no authorization policy entry`) {
+		t.Errorf("Expected the route without entry to be reported, got %v", err)
+	}
+	stale := NewPolicyMux(auth, policy)
+	stale.HandleFunc("/healthz", ok)
+	if err := stale.Verify(); err == nil || !strings.Contains(err.Error(), `entry "POST /api/warehouses" matches no route`) {
+		t.Errorf("Expected entries without route to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ProductArchiveHandler handles bulk product archives
+type ProductArchiveHandler struct {
+	archiveService *service.ProductArchiveService
+	jobService     *service.JobService
+}
+
+// NewProductArchiveHandler creates a new ProductArchiveHandler. Without a job
+// service archives run synchronously.
+func NewProductArchiveHandler(archiveService *service.ProductArchiveService, jobService *service.JobService) *ProductArchiveHandler {
+	return &ProductArchiveHandler{
+		archiveService: archiveService,
+		jobService:     jobService,
+	}
+}
+
+// ProductArchiveRequest represents a bulk archive request: the filter, and
+// whether to archive the products it selects, which must be as many as
+// ExpectedCount, or only preview them
+type ProductArchiveRequest struct {
+	domain.ProductArchiveFilter
+	Apply         bool `json:"apply"`
+	ExpectedCount *int `json:"expected_count"`
+}
+
+// ArchiveProductsHandler handles previewing the products a filter selects
+// and, on request, archiving them as a background job
+func (h *ProductArchiveHandler) ArchiveProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req ProductArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if !req.Apply {
+		preview, err := h.archiveService.Preview(r.Context(), req.ProductArchiveFilter)
+		if err != nil {
+			writeServiceError(w, err, http.StatusInternalServerError, "ARCHIVE_FAILED")
+			return
+		}
+		WriteSuccess(w, http.StatusOK, fmt.Sprintf("%d products would be archived", preview.Total), preview)
+		return
+	}
+
+	if req.ExpectedCount == nil {
+		WriteError(w, http.StatusBadRequest, "VALIDATION_FAILED", "expected_count is required to archive; preview the filter first")
+		return
+	}
+
+	if h.jobService != nil {
+		run, err := h.archiveService.ArchiveJob(r.Context(), req.ProductArchiveFilter, *req.ExpectedCount)
+		if err != nil {
+			writeServiceError(w, err, http.StatusInternalServerError, "ARCHIVE_FAILED")
+			return
+		}
+		startJob(w, r, h.jobService, domain.JobProductArchive, run)
+		return
+	}
+
+	result, err := h.archiveService.Archive(r.Context(), req.ProductArchiveFilter, *req.ExpectedCount)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "ARCHIVE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, fmt.Sprintf("Archived %d of %d products", len(result.Archived), result.Matched), result)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// maxImportBodySize bounds the size of an uploaded catalog
+const maxImportBodySize = 16 << 20
+
+// importCSVColumns are the columns understood in CSV imports; name, sku and
+// location are required
+var importCSVColumns = map[string]bool{
+	"name": true, "description": true, "sku": true, "category": true,
+	"price": true, "location": true, "quantity": true,
+}
+
+// ProductImportHandler handles bulk product imports
+type ProductImportHandler struct {
+	importService *service.ProductImportService
+	jobService    *service.JobService
+}
+
+// NewProductImportHandler creates a new ProductImportHandler. Without a job
+// service imports always run synchronously.
+func NewProductImportHandler(importService *service.ProductImportService, jobService *service.JobService) *ProductImportHandler {
+	return &ProductImportHandler{
+		importService: importService,
+		jobService:    jobService,
+	}
+}
+
+// ImportProductsHandler handles importing a catalog sent either as a JSON
+// array of products or, with Content-Type text/csv, as CSV with a header row.
+// With "Prefer: respond-async" the import runs as a background job.
+func (h *ProductImportHandler) ImportProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+
+	var rows []domain.ProductImportRow
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+		var err error
+		if rows, err = parseImportCSV(body); err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+	} else if err := json.NewDecoder(body).Decode(&rows); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	if h.jobService != nil && wantsAsync(r) {
+		run, err := h.importService.ImportJob(rows)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		startJob(w, r, h.jobService, domain.JobProductImport, run)
+		return
+	}
+
+	report, err := h.importService.Import(r.Context(), rows)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		writeServiceError(w, err, http.StatusInternalServerError, "IMPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, fmt.Sprintf("Imported %d of %d products", report.Imported, report.Total), report)
+}
+
+// parseImportCSV reads import rows from CSV whose first record names the columns
+func parseImportCSV(r io.Reader) ([]domain.ProductImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV import must start with a header row")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !importCSVColumns[name] {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "sku", "location"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV column %q is required", required)
+		}
+	}
+
+	var rows []domain.ProductImportRow
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := domain.ProductImportRow{
+			Name:        field("name"),
+			Description: field("description"),
+			SKU:         field("sku"),
+			Category:    field("category"),
+			Location:    field("location"),
+		}
+		if value := field("price"); value != "" {
+			if row.Price, err = strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("row %d: invalid price %q", line, value)
+			}
+		}
+		if value := field("quantity"); value != "" {
+			if row.Quantity, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("row %d: invalid quantity %q", line, value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
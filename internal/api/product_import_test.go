@@ -0,0 +1,41 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		wantRows int
+		wantErr  bool
+	}{
+		{"Full columns", "name,sku,category,price,location,quantity\nLaptop,LAP-1,Electronics,1500.50,WH-A,10\nMouse,MOU-1,,25,WH-A,\n", 2, false},
+		{"Column order and case", "SKU, Location, Name\nLAP-1, WH-A, Laptop\n", 1, false},
+		{"Header only", "name,sku,location\n", 0, false},
+		{"Missing required column", "name,sku\nLaptop,LAP-1\n", 0, true},
+		{"Unknown column", "name,sku,location,supplier\nLaptop,LAP-1,WH-A,ACME\n", 0, true},
+		{"Invalid price", "name,sku,location,price\nLaptop,LAP-1,WH-A,cheap\n", 0, true},
+		{"Ragged row", "name,sku,location\nLaptop,LAP-1\n", 0, true},
+		{"Empty body", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseImportCSV(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseImportCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(rows) != tt.wantRows {
+				t.Errorf("Expected %d rows, got %d", tt.wantRows, len(rows))
+			}
+		})
+	}
+
+	rows, _ := parseImportCSV(strings.NewReader("name,sku,price,location,quantity\nLaptop,LAP-1,1500.50,WH-A,10\n"))
+	if row := rows[0]; row.Name != "Laptop" || row.SKU != "LAP-1" || row.Price != 1500.50 || row.Location != "WH-A" || row.Quantity != 10 {
+		t.Errorf("Unexpected row: %+v", row)
+	}
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ReconciliationHandler handles stock reconciliation report requests
+type ReconciliationHandler struct {
+	reconciliationService *service.ReconciliationService
+}
+
+// NewReconciliationHandler creates a new ReconciliationHandler
+func NewReconciliationHandler(reconciliationService *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+// ReportHandler handles retrieving the report of the latest scheduled
+// reconciliation
+func (h *ReconciliationHandler) ReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	report, err := h.reconciliationService.LatestReport(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reconciliation report retrieved successfully", report)
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// RedactionHandler handles personal data erasure requests
+type RedactionHandler struct {
+	redactionService *service.RedactionService
+}
+
+// NewRedactionHandler creates a new RedactionHandler
+func NewRedactionHandler(redactionService *service.RedactionService) *RedactionHandler {
+	return &RedactionHandler{
+		redactionService: redactionService,
+	}
+}
+
+// RedactionRequest represents a customer data erasure request
+type RedactionRequest struct {
+	CustomerIdentifier string `json:"customer_identifier"`
+	Reason             string `json:"reason"`
+}
+
+// CreateRedactionHandler handles redacting a customer identifier from the ledger
+func (h *RedactionHandler) CreateRedactionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req RedactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	redaction, err := h.redactionService.RedactCustomer(r.Context(), req.CustomerIdentifier, req.Reason)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REDACTION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Customer data redacted successfully", redaction)
+}
+
+// ListRedactionsHandler handles listing past redactions
+func (h *RedactionHandler) ListRedactionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	redactions, err := h.redactionService.ListRedactions(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Redactions retrieved successfully", redactions)
+}
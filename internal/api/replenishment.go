@@ -0,0 +1,203 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ReplenishmentHandler handles reorder suggestion and purchase order requests
+type ReplenishmentHandler struct {
+	replenishmentService *service.ReplenishmentService
+}
+
+// NewReplenishmentHandler creates a new ReplenishmentHandler
+func NewReplenishmentHandler(replenishmentService *service.ReplenishmentService) *ReplenishmentHandler {
+	return &ReplenishmentHandler{
+		replenishmentService: replenishmentService,
+	}
+}
+
+// CreatePurchaseOrderRequest represents a request to create a draft purchase
+// order, either of the given lines or of the current reorder suggestions
+type CreatePurchaseOrderRequest struct {
+	Supplier        string                     `json:"supplier"`
+	Notes           string                     `json:"notes"`
+	Lines           []domain.PurchaseOrderLine `json:"lines"`
+	FromSuggestions bool                       `json:"from_suggestions"`
+	LeadTimeDays    *int                       `json:"lead_time_days"`
+	CoverDays       *int                       `json:"cover_days"`
+}
+
+// SuggestionsHandler handles listing reorder suggestions. lead_time_days and
+// cover_days override the configured policy.
+func (h *ReplenishmentHandler) SuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	policy := h.replenishmentService.Defaults()
+	params := []struct {
+		name string
+		days *int
+	}{{"lead_time_days", &policy.LeadTimeDays}, {"cover_days", &policy.CoverDays}}
+	for _, param := range params {
+		if value := r.URL.Query().Get(param.name); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", param.name+" must be an integer")
+				return
+			}
+			*param.days = days
+		}
+	}
+
+	suggestions, err := h.replenishmentService.Suggestions(r.Context(), policy)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reorder suggestions retrieved successfully", suggestions)
+}
+
+// CreatePurchaseOrderHandler handles creating a draft purchase order
+func (h *ReplenishmentHandler) CreatePurchaseOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	var order *domain.PurchaseOrder
+	var err error
+	if req.FromSuggestions {
+		if len(req.Lines) > 0 {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "lines cannot be combined with from_suggestions")
+			return
+		}
+		policy := h.replenishmentService.Defaults()
+		if req.LeadTimeDays != nil {
+			policy.LeadTimeDays = *req.LeadTimeDays
+		}
+		if req.CoverDays != nil {
+			policy.CoverDays = *req.CoverDays
+		}
+		order, err = h.replenishmentService.DraftFromSuggestions(r.Context(), req.Supplier, req.Notes, policy)
+	} else {
+		order, err = h.replenishmentService.CreatePurchaseOrder(r.Context(), req.Supplier, req.Notes, req.Lines)
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Purchase order created successfully", order)
+}
+
+// ListPurchaseOrdersHandler handles listing purchase orders, optionally only
+// those with the status given in status
+func (h *ReplenishmentHandler) ListPurchaseOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	status := domain.PurchaseOrderStatus(strings.ToUpper(r.URL.Query().Get("status")))
+
+	orders, err := h.replenishmentService.ListPurchaseOrders(r.Context(), status, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase orders retrieved successfully", orders)
+}
+
+// GetPurchaseOrderHandler handles retrieving a purchase order
+func (h *ReplenishmentHandler) GetPurchaseOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	order, err := h.replenishmentService.GetPurchaseOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase order retrieved successfully", order)
+}
+
+// ShippingNoticesHandler handles listing the advance shipping notices the
+// supplier submitted for a purchase order
+func (h *ReplenishmentHandler) ShippingNoticesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	notices, err := h.replenishmentService.ShippingNotices(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Shipping notices retrieved successfully", notices)
+}
+
+// ReceivePurchaseOrderHandler handles booking the delivery of a purchase
+// order into stock
+func (h *ReplenishmentHandler) ReceivePurchaseOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	order, err := h.replenishmentService.ReceivePurchaseOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase order received successfully", order)
+}
+
+// CancelPurchaseOrderHandler handles cancelling a draft purchase order
+func (h *ReplenishmentHandler) CancelPurchaseOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	order, err := h.replenishmentService.CancelPurchaseOrder(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase order cancelled successfully", order)
+}
+
+// writePurchaseOrderError maps purchase order state errors to 409 and stock
+// errors of a receipt like the other stock operations
+func writePurchaseOrderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrPurchaseOrderNotDraft) {
+		WriteError(w, http.StatusConflict, "PURCHASE_ORDER_NOT_DRAFT", err.Error())
+		return
+	}
+	writeStockOperationError(w, err)
+}
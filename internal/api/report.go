@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ReportHandler handles reporting requests
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// StockSummaryHandler handles the aggregated stock summary report
+func (h *ReportHandler) StockSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	summaries, err := h.reportService.StockSummary(r.Context(), splitQueryList(r.URL.Query().Get("group_by")))
+	if errors.Is(err, service.ErrInvalidReportRequest) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock summary retrieved successfully", summaries)
+}
+
+// DashboardStatsHandler handles the dashboard's headline figures
+func (h *ReportHandler) DashboardStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	stats, err := h.reportService.DashboardStats(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Dashboard stats retrieved successfully", stats)
+}
+
+// splitQueryList splits a comma-separated query parameter, dropping empty entries
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// MovementSummaryHandler handles the per-period stock movement report
+func (h *ReportHandler) MovementSummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	query := repository.MovementQuery{
+		From:           from,
+		To:             to,
+		Granularity:    r.URL.Query().Get("granularity"),
+		Location:       ReportingLocation(r.Context()),
+		ProductID:      r.URL.Query().Get("product_id"),
+		GroupByProduct: r.URL.Query().Get("group_by") == "product",
+	}
+
+	summaries, err := h.reportService.MovementSummary(r.Context(), query)
+	if errors.Is(err, service.ErrInvalidReportRequest) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Movement summary retrieved successfully", summaries)
+}
+
+// defaultStockoutHorizonDays is the stockout report horizon when days is omitted
+const defaultStockoutHorizonDays = 14
+
+// StockoutReportHandler handles the report of products projected to run out
+// of stock within ?days= days (default 14)
+func (h *ReportHandler) StockoutReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	days := defaultStockoutHorizonDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "days must be an integer")
+			return
+		}
+		days = parsed
+	}
+
+	projections, err := h.reportService.StockoutReport(r.Context(), days)
+	if errors.Is(err, service.ErrInvalidReportRequest) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stockout report retrieved successfully", projections)
+}
+
+// ReservationAgingHandler handles the report of open reservations grouped by
+// the age of the oldest reservation of each reference
+func (h *ReportHandler) ReservationAgingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	buckets, err := h.reportService.ReservationAging(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservation aging report retrieved successfully", buckets)
+}
+
+// StockAsOfHandler handles the stock of every product at the moment given as
+// ?as_of=, an RFC3339 time or a date meaning the end of that day in the
+// reporting timezone
+func (h *ReportHandler) StockAsOfHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	value := r.URL.Query().Get("as_of")
+	if value == "" {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "as_of query parameter is required")
+		return
+	}
+	asOf, err := parseTimeParam(value, ReportingLocation(r.Context()), true)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+
+	report, err := h.reportService.StockAsOf(r.Context(), asOf)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Historic stock retrieved successfully", report)
+}
+
+// ValuationHandler handles the value of the stock on hand, by ?method=fifo
+// (default) or weighted_average, optionally of one ?product_id=
+func (h *ReportHandler) ValuationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.reportService.Valuation(r.Context(), query.Get("method"), query.Get("product_id"))
+	if errors.Is(err, service.ErrInvalidReportRequest) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock valuation retrieved successfully", report)
+}
+
+// defaultTrendDays is the number of days of a stock trend when from is omitted
+const defaultTrendDays = 30
+
+// ProductTrendHandler handles the daily on-hand and available series of a
+// product. The range is ?from=&to= as dates, with to defaulting to today and
+// from to 30 days earlier, or ?days= ending at to; ?fill= picks the gap filling.
+func (h *ReportHandler) ProductTrendHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	year, month, day := time.Now().In(ReportingLocation(r.Context())).Date()
+	to := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "to must be a date (YYYY-MM-DD)")
+			return
+		}
+		to = parsed
+	}
+
+	days := defaultTrendDays
+	if value := query.Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "days must be a positive integer")
+			return
+		}
+		days = parsed
+	}
+	from := to.AddDate(0, 0, 1-days)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "from must be a date (YYYY-MM-DD)")
+			return
+		}
+		from = parsed
+	}
+
+	trend, err := h.reportService.ProductTrend(r.Context(), r.PathValue("id"), from, to, query.Get("fill"))
+	if errors.Is(err, service.ErrInvalidReportRequest) {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "REPORT_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock trend retrieved successfully", trend)
+}
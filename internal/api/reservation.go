@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// ReservationHandler handles order reservation requests
+type ReservationHandler struct {
+	reservationService *service.ReservationService
+}
+
+// NewReservationHandler creates a new ReservationHandler
+func NewReservationHandler(reservationService *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{
+		reservationService: reservationService,
+	}
+}
+
+// CreateReservationRequest represents a reservation request. With
+// AllowSplit a quantity the location cannot cover is split across locations.
+type CreateReservationRequest struct {
+	ProductID  string `json:"product_id"`
+	Location   string `json:"location"`
+	Condition  string `json:"condition"`
+	Quantity   int64  `json:"quantity"`
+	Reference  string `json:"reference"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	AllowSplit bool   `json:"allow_split"`
+}
+
+// CreateReservationHandler handles creating a reservation
+func (h *ReservationHandler) CreateReservationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if req.AllowSplit {
+		split, err := h.reservationService.CreateSplitReservation(r.Context(), req.ProductID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference, ttl)
+		if err != nil {
+			writeStockOperationError(w, err)
+			return
+		}
+		WriteSuccess(w, http.StatusCreated, "Reservation created successfully", split)
+		return
+	}
+
+	reservation, err := h.reservationService.CreateReservation(r.Context(), req.ProductID, req.Location, domain.StockCondition(req.Condition), req.Quantity, req.Reference, ttl)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Reservation created successfully", reservation)
+}
+
+// GetReservationHandler handles retrieving a reservation
+func (h *ReservationHandler) GetReservationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	reservation, err := h.reservationService.GetReservation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservation retrieved successfully", reservation)
+}
+
+// GetReservationSplitHandler handles retrieving the children of a split
+// reservation
+func (h *ReservationHandler) GetReservationSplitHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	split, err := h.reservationService.GetReservationSplit(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservation split retrieved successfully", split)
+}
+
+// ListReservationsHandler handles listing the reservations of a product
+func (h *ReservationHandler) ListReservationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	reservations, err := h.reservationService.ListReservations(r.Context(), r.PathValue("id"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservations retrieved successfully", reservations)
+}
+
+// ConfirmReservationHandler handles confirming a reservation
+func (h *ReservationHandler) ConfirmReservationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	reservation, err := h.reservationService.ConfirmReservation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReservationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservation confirmed successfully", reservation)
+}
+
+// ReleaseReservationHandler handles releasing a reservation
+func (h *ReservationHandler) ReleaseReservationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	reservation, err := h.reservationService.ReleaseReservation(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeReservationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Reservation released successfully", reservation)
+}
+
+// writeReservationError maps a failed reservation transition to its response
+func writeReservationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrReservationNotPending) {
+		WriteError(w, http.StatusConflict, "RESERVATION_NOT_PENDING", err.Error())
+		return
+	}
+	writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// RoutingHandler handles transfer lane and fulfillment planning requests
+type RoutingHandler struct {
+	routingService *service.RoutingService
+}
+
+// NewRoutingHandler creates a new RoutingHandler
+func NewRoutingHandler(routingService *service.RoutingService) *RoutingHandler {
+	return &RoutingHandler{
+		routingService: routingService,
+	}
+}
+
+// FulfillmentPlanRequest represents a request to plan fulfilling an order of
+// one product from a destination warehouse
+type FulfillmentPlanRequest struct {
+	ProductID    string  `json:"product_id"`
+	Destination  string  `json:"destination"`
+	Quantity     int64   `json:"quantity"`
+	MaxLeadHours float64 `json:"max_lead_hours"`
+}
+
+// ListLanesHandler handles listing the configured transfer cost/time matrix
+func (h *RoutingHandler) ListLanesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Transfer lanes retrieved successfully", h.routingService.Lanes())
+}
+
+// PlanFulfillmentHandler handles choosing between a split shipment and a
+// transfer for an order; nothing is reserved or moved
+func (h *RoutingHandler) PlanFulfillmentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req FulfillmentPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	plan, err := h.routingService.PlanFulfillment(r.Context(), req.ProductID, req.Destination, req.Quantity, req.MaxLeadHours)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "ROUTING_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Fulfillment plan computed successfully", plan)
+}
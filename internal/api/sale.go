@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// SaleHandler handles point-of-sale requests
+type SaleHandler struct {
+	saleService *service.SaleService
+}
+
+// NewSaleHandler creates a new SaleHandler
+func NewSaleHandler(saleService *service.SaleService) *SaleHandler {
+	return &SaleHandler{
+		saleService: saleService,
+	}
+}
+
+// RecordSaleHandler handles a sale reported by a store till. A sale whose
+// event was recorded before is answered with the recorded sale and an
+// Idempotent-Replayed header.
+func (h *SaleHandler) RecordSaleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var sale domain.Sale
+	if err := json.NewDecoder(r.Body).Decode(&sale); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	recorded, replayed, err := h.saleService.RecordSale(r.Context(), &sale)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+		WriteSuccess(w, http.StatusOK, "Sale already recorded", recorded)
+		return
+	}
+	WriteSuccess(w, http.StatusCreated, "Sale recorded successfully", recorded)
+}
+
+// ListSalesHandler handles listing sales in the order they happened,
+// optionally of one store (location) and within from and to
+func (h *SaleHandler) ListSalesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := domain.SaleFilter{Location: query.Get("location")}
+	loc := ReportingLocation(r.Context())
+	if value := query.Get("from"); value != "" {
+		from, err := parseTimeParam(value, loc, false)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		filter.From = from
+	}
+	if value := query.Get("to"); value != "" {
+		to, err := parseTimeParam(value, loc, true)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+		filter.To = to
+	}
+
+	limit, offset := parsePagination(r)
+	sales, err := h.saleService.ListSales(r.Context(), filter, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Sales retrieved successfully", sales)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// SerialHandler handles serialized unit requests
+type SerialHandler struct {
+	serialService *service.SerialService
+}
+
+// NewSerialHandler creates a new SerialHandler
+func NewSerialHandler(serialService *service.SerialService) *SerialHandler {
+	return &SerialHandler{
+		serialService: serialService,
+	}
+}
+
+// RegisterSerialRequest represents a serial unit registration request
+type RegisterSerialRequest struct {
+	SerialNumber string `json:"serial_number"`
+}
+
+// SerialStatusRequest represents a serial unit status change request
+type SerialStatusRequest struct {
+	Status    domain.SerialStatus `json:"status"`
+	Reference string              `json:"reference"`
+	Notes     string              `json:"notes"`
+}
+
+// RegisterSerialHandler handles registering a serial unit for a product
+func (h *SerialHandler) RegisterSerialHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req RegisterSerialRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	unit, err := h.serialService.RegisterSerial(r.Context(), r.PathValue("id"), req.SerialNumber)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Serial unit registered successfully", unit)
+}
+
+// ListSerialsHandler handles listing the serial units of a product
+func (h *SerialHandler) ListSerialsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	units, err := h.serialService.ListSerials(r.Context(), r.PathValue("id"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Serial units retrieved successfully", units)
+}
+
+// GetSerialHandler handles retrieving a single serial unit
+func (h *SerialHandler) GetSerialHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	unit, err := h.serialService.GetSerial(r.Context(), r.PathValue("id"), r.PathValue("serial"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Serial unit retrieved successfully", unit)
+}
+
+// UpdateSerialStatusHandler handles moving a serial unit to a new status
+func (h *SerialHandler) UpdateSerialStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req SerialStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	unit, err := h.serialService.TransitionSerial(r.Context(), r.PathValue("id"), r.PathValue("serial"), req.Status, req.Reference, req.Notes)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "OPERATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Serial unit status updated successfully", unit)
+}
+
+// GetSerialHistoryHandler handles retrieving the status history of a serial unit
+func (h *SerialHandler) GetSerialHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	events, err := h.serialService.GetSerialHistory(r.Context(), r.PathValue("id"), r.PathValue("serial"), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Serial unit history retrieved successfully", events)
+}
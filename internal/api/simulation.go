@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// SimulationHandler handles what-if simulation requests
+type SimulationHandler struct {
+	simulationService *service.SimulationService
+}
+
+// NewSimulationHandler creates a new SimulationHandler
+func NewSimulationHandler(simulationService *service.SimulationService) *SimulationHandler {
+	return &SimulationHandler{
+		simulationService: simulationService,
+	}
+}
+
+// SimulateRequest represents a what-if simulation request
+type SimulateRequest struct {
+	Operations []domain.SimulationOperation `json:"operations"`
+}
+
+// SimulateHandler handles evaluating hypothetical orders and receipts against
+// current availability; nothing is committed
+func (h *SimulationHandler) SimulateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req SimulateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	result, err := h.simulationService.Simulate(r.Context(), req.Operations)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "SIMULATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Simulation completed successfully", result)
+}
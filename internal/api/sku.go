@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// GetProductBySKUHandler handles retrieving a product by SKU
+func (h *Handler) GetProductBySKUHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	sku := strings.TrimPrefix(r.URL.Path, "/api/products/sku/")
+	sku = strings.TrimSuffix(sku, "/")
+
+	includes, err := domain.ParseProductIncludes(r.URL.Query().Get("include"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
+		return
+	}
+
+	product, inventory, err := h.inventoryService.GetProductBySKU(r.Context(), sku)
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+	if err := h.inventoryService.LoadIncludes(r.Context(), []*domain.Product{product}, includes); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+	product.Localize(RequestLanguage(r.Context()))
+
+	response := map[string]interface{}{
+		"product":   product,
+		"inventory": inventory,
+	}
+
+	writeSelected(w, r, http.StatusOK, "Product retrieved successfully", response)
+}
+
+// SKUStockHandler handles stock operations on a product addressed by SKU
+// instead of ID; the operation is one of add, remove, reserve or unreserve
+// and an empty location targets the product's default location
+func (h *Handler) SKUStockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req StockOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	productID, err := h.inventoryService.ResolveSKU(r.Context(), r.PathValue("sku"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	h.runStockOperation(w, r, r.PathValue("op"), productID, req.Location, req)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// StockAlertHandler handles low-stock alert requests
+type StockAlertHandler struct {
+	stockAlertService *service.StockAlertService
+}
+
+// NewStockAlertHandler creates a new StockAlertHandler
+func NewStockAlertHandler(stockAlertService *service.StockAlertService) *StockAlertHandler {
+	return &StockAlertHandler{
+		stockAlertService: stockAlertService,
+	}
+}
+
+// ListHandler handles listing stock alerts, optionally only those with the
+// status given in status
+func (h *StockAlertHandler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	status := domain.StockAlertStatus(strings.ToUpper(r.URL.Query().Get("status")))
+
+	alerts, err := h.stockAlertService.ListAlerts(r.Context(), status, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock alerts retrieved successfully", alerts)
+}
+
+// EvaluateHandler handles evaluating the stock alerts now instead of waiting
+// for the next scheduled evaluation
+func (h *StockAlertHandler) EvaluateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	run, err := h.stockAlertService.Evaluate(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "EVALUATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock alerts evaluated successfully", run)
+}
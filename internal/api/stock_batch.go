@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// StockBatchRequest represents a batch of stock operations applied together
+type StockBatchRequest struct {
+	Items []domain.StockBatchItem `json:"items"`
+}
+
+// StockBatchHandler handles applying a batch of stock operations in one
+// transaction; if any item fails, none are applied
+func (h *Handler) StockBatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req StockBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	transactions, err := h.inventoryService.ApplyStockBatch(r.Context(), req.Items)
+	if err != nil {
+		writeStockOperationError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stock batch applied successfully", transactions)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// StocktakeHandler handles stocktake requests
+type StocktakeHandler struct {
+	stocktakeService *service.StocktakeService
+}
+
+// NewStocktakeHandler creates a new StocktakeHandler
+func NewStocktakeHandler(stocktakeService *service.StocktakeService) *StocktakeHandler {
+	return &StocktakeHandler{
+		stocktakeService: stocktakeService,
+	}
+}
+
+// OpenStocktakeRequest represents a request to start a stocktake
+type OpenStocktakeRequest struct {
+	Location string `json:"location"`
+	Mode     string `json:"mode"`
+}
+
+// OpenStocktakeHandler handles opening a stocktake at a location
+func (h *StocktakeHandler) OpenStocktakeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req OpenStocktakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	stocktake, err := h.stocktakeService.OpenStocktake(r.Context(), req.Location, req.Mode)
+	if err != nil {
+		if errors.Is(err, service.ErrStocktakeOpen) {
+			WriteError(w, http.StatusConflict, "CONFLICT", err.Error())
+			return
+		}
+		writeServiceError(w, err, http.StatusBadRequest, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Stocktake opened successfully", stocktake)
+}
+
+// CloseStocktakeHandler handles closing a stocktake; the response lists the
+// queued movements that were applied
+func (h *StocktakeHandler) CloseStocktakeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	stocktake, err := h.stocktakeService.CloseStocktake(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrStocktakeClosed) {
+			WriteError(w, http.StatusConflict, "CONFLICT", err.Error())
+			return
+		}
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stocktake closed successfully", stocktake)
+}
+
+// GetStocktakeHandler handles retrieving a stocktake
+func (h *StocktakeHandler) GetStocktakeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	stocktake, err := h.stocktakeService.GetStocktake(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stocktake retrieved successfully", stocktake)
+}
+
+// ListStocktakesHandler handles listing stocktakes
+func (h *StocktakeHandler) ListStocktakesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	stocktakes, err := h.stocktakeService.ListStocktakes(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Stocktakes retrieved successfully", stocktakes)
+}
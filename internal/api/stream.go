@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// streamHeartbeatInterval is how often an idle event stream sends a comment,
+// which keeps proxies from closing the connection
+const streamHeartbeatInterval = 15 * time.Second
+
+// StreamHandler handles live inventory event streams
+type StreamHandler struct {
+	stream *service.InventoryStream
+}
+
+// NewStreamHandler creates a new StreamHandler
+func NewStreamHandler(stream *service.InventoryStream) *StreamHandler {
+	return &StreamHandler{
+		stream: stream,
+	}
+}
+
+// InventoryStreamHandler streams the stock changes of the products listed in
+// product_ids as Server-Sent Events: a snapshot event per product first, then
+// a change event after every stock movement. The stream ends when the client
+// falls behind or the server shuts down; clients reconnect and get fresh
+// snapshots.
+func (h *StreamHandler) InventoryStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	sub, err := h.stream.Subscribe(splitQueryList(r.URL.Query().Get("product_ids")))
+	if errors.Is(err, service.ErrStreamFull) {
+		WriteError(w, http.StatusServiceUnavailable, "STREAM_FULL", err.Error())
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest, "INVALID_REQUEST")
+		return
+	}
+	defer h.stream.Unsubscribe(sub)
+
+	// Subscribe before reading the snapshots, so that no change between the
+	// two is missed
+	snapshots, err := h.stream.Snapshot(r.Context(), sub)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.WarnContext(r.Context(), "failed to lift write deadline for inventory stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range snapshots {
+		writeStreamEvent(w, "snapshot", event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			writeStreamEvent(w, "change", event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamEvent writes one Server-Sent Event with a JSON payload
+func writeStreamEvent(w http.ResponseWriter, name string, event *domain.InventoryEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
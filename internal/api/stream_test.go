@@ -0,0 +1,67 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func TestInventoryStreamHandler(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	inventoryRepo.Create(context.Background(), &domain.InventoryItem{ProductID: "prod-1", Quantity: 8, Reserved: 2, Location: "WH-A"})
+	stream := service.NewInventoryStream(inventoryRepo, 10)
+	runCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go stream.Run(runCtx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/stream/inventory", NewStreamHandler(stream).InventoryStreamHandler)
+	server := httptest.NewServer(ErrorShapingMiddleware("")(JSONResponseMiddleware(mux)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/stream/inventory")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 without product_ids, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/api/stream/inventory?product_ids=prod-1")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	name, _ := reader.ReadString('\n')
+	data, _ := reader.ReadString('\n')
+	if name != "event: snapshot\n" || !strings.HasPrefix(data, "data: ") {
+		t.Fatalf("Expected a snapshot event, got %q %q", name, data)
+	}
+	var event domain.InventoryEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &event); err != nil {
+		t.Fatalf("Invalid event payload: %v", err)
+	}
+	if event.ProductID != "prod-1" || event.Available != 6 {
+		t.Errorf("Expected 6 available for prod-1, got %+v", event)
+	}
+
+	// The stream ends when the server stops dispatching
+	stop()
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Errorf("Expected the stream to end cleanly, got %v", err)
+	}
+}
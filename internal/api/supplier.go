@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// SupplierPortalHandler handles the supplier portal: the routes callers with
+// the supplier role use to work on their own purchase orders
+type SupplierPortalHandler struct {
+	portalService *service.SupplierPortalService
+}
+
+// NewSupplierPortalHandler creates a new SupplierPortalHandler
+func NewSupplierPortalHandler(portalService *service.SupplierPortalService) *SupplierPortalHandler {
+	return &SupplierPortalHandler{
+		portalService: portalService,
+	}
+}
+
+// ConfirmPurchaseOrderRequest represents a supplier's confirmation of a
+// purchase order
+type ConfirmPurchaseOrderRequest struct {
+	PromisedAt time.Time             `json:"promised_delivery_at"`
+	Lines      []domain.QuantityLine `json:"lines"`
+}
+
+// ShippingNoticeRequest represents an advance shipping notice
+type ShippingNoticeRequest struct {
+	Reference      string                `json:"reference"`
+	Carrier        string                `json:"carrier"`
+	TrackingNumber string                `json:"tracking_number"`
+	ShippedAt      *time.Time            `json:"shipped_at"`
+	ExpectedAt     *time.Time            `json:"expected_at"`
+	Lines          []domain.QuantityLine `json:"lines"`
+}
+
+// ListOrdersHandler handles listing the supplier's open purchase orders
+func (h *SupplierPortalHandler) ListOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+	supplier, ok := requestSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	orders, err := h.portalService.OpenOrders(r.Context(), supplier, limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	views := make([]*domain.SupplierPurchaseOrder, 0, len(orders))
+	for _, order := range orders {
+		views = append(views, order.SupplierView())
+	}
+	WriteSuccess(w, http.StatusOK, "Purchase orders retrieved successfully", views)
+}
+
+// GetOrderHandler handles retrieving one of the supplier's purchase orders
+func (h *SupplierPortalHandler) GetOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+	supplier, ok := requestSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.portalService.Order(r.Context(), supplier, r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase order retrieved successfully", order.SupplierView())
+}
+
+// ConfirmOrderHandler handles a supplier confirming the delivery date and
+// quantities of an open purchase order
+func (h *SupplierPortalHandler) ConfirmOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+	supplier, ok := requestSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	var req ConfirmPurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	confirmation := &domain.SupplierConfirmation{PromisedAt: req.PromisedAt, Lines: req.Lines}
+	order, err := h.portalService.Confirm(r.Context(), supplier, r.PathValue("id"), confirmation)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Purchase order confirmed successfully", order.SupplierView())
+}
+
+// SubmitShippingNoticeHandler handles a supplier announcing a shipment
+// against an open purchase order
+func (h *SupplierPortalHandler) SubmitShippingNoticeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+	supplier, ok := requestSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	var req ShippingNoticeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	notice := &domain.AdvanceShippingNotice{
+		Reference:      req.Reference,
+		Carrier:        req.Carrier,
+		TrackingNumber: req.TrackingNumber,
+		ExpectedAt:     req.ExpectedAt,
+		Lines:          req.Lines,
+	}
+	if req.ShippedAt != nil {
+		notice.ShippedAt = *req.ShippedAt
+	}
+
+	notice, err := h.portalService.SubmitShippingNotice(r.Context(), supplier, r.PathValue("id"), notice)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Shipping notice submitted successfully", notice)
+}
+
+// ListShippingNoticesHandler handles listing the supplier's shipping notices
+// of one of its purchase orders
+func (h *SupplierPortalHandler) ListShippingNoticesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+	supplier, ok := requestSupplier(w, r)
+	if !ok {
+		return
+	}
+
+	notices, err := h.portalService.ShippingNotices(r.Context(), supplier, r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Shipping notices retrieved successfully", notices)
+}
+
+// requestSupplier returns the supplier a request acts for, the subject of a
+// caller authenticated with the supplier role. Any other caller, including
+// every caller while authentication is disabled, gets 403.
+func requestSupplier(w http.ResponseWriter, r *http.Request) (string, bool) {
+	principal := RequestPrincipal(r.Context())
+	if principal == nil || principal.Role != domain.RoleSupplier || principal.Subject == "" {
+		WriteError(w, http.StatusForbidden, "FORBIDDEN", "Requires a supplier credential")
+		return "", false
+	}
+	return principal.Subject, true
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// WriteLimitMiddleware admits mutating requests (POST, PUT, PATCH, DELETE)
+// through the write limiter. Requests that cannot be admitted receive 429
+// OVERLOADED with a Retry-After header.
+func WriteLimitMiddleware(limiter *service.WriteLimiter) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			release, err := limiter.Acquire(r.Context())
+			if errors.Is(err, service.ErrOverloaded) {
+				seconds := int(math.Ceil(limiter.RetryAfter().Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				WriteError(w, http.StatusTooManyRequests, "OVERLOADED", err.Error())
+				return
+			}
+			if err != nil {
+				// The client went away while queued
+				WriteError(w, http.StatusServiceUnavailable, "OVERLOADED", err.Error())
+				return
+			}
+			defer release()
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadOnlyMiddleware refuses mutating requests (POST, PUT, PATCH, DELETE)
+// with 503 READ_ONLY, for a server started on a database schema that differs
+// from the one it expects
+func ReadOnlyMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			WriteError(w, http.StatusServiceUnavailable, "READ_ONLY",
+				"The server is read-only because the database schema differs from the expected one")
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// SystemHandler handles system status requests
+type SystemHandler struct {
+	loadService *service.LoadService
+}
+
+// NewSystemHandler creates a new SystemHandler
+func NewSystemHandler(loadService *service.LoadService) *SystemHandler {
+	return &SystemHandler{
+		loadService: loadService,
+	}
+}
+
+// LoadHandler handles retrieving the current write load
+func (h *SystemHandler) LoadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "System load retrieved successfully", h.loadService.Load())
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// TenantMiddleware scopes every request to a tenant, so that the products,
+// inventory and transactions it reads and writes are those of the tenant.
+// The tenant is named by the header, or for requests to a subdomain of
+// baseDomain by the subdomain (acme.inventory.example.com is acme); callers
+// bound to a tenant default to theirs, everyone else to the default tenant.
+// Bound callers naming another tenant are refused with 403. It must run after
+// AuthMiddleware. Health checks and metric scrapes stay unscoped.
+func TenantMiddleware(header, baseDomain string) func(http.Handler) http.Handler {
+	baseDomain = strings.ToLower(baseDomain)
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if infrastructurePath(r.URL.Path) {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			principal := RequestPrincipal(r.Context())
+			tenant := ""
+			if header != "" {
+				tenant = strings.TrimSpace(r.Header.Get(header))
+			}
+			if tenant == "" && baseDomain != "" {
+				tenant = subdomainTenant(r.Host, baseDomain)
+			}
+			if tenant == "" && principal != nil {
+				tenant = principal.Tenant
+			}
+			if tenant == "" {
+				tenant = domain.DefaultTenantID
+			}
+
+			if err := domain.ValidateTenantID(tenant); err != nil {
+				WriteError(w, http.StatusBadRequest, "INVALID_TENANT", err.Error())
+				return
+			}
+			if principal != nil && principal.Tenant != "" && principal.Tenant != tenant {
+				WriteError(w, http.StatusForbidden, "TENANT_FORBIDDEN", "Not allowed to access tenant "+tenant)
+				return
+			}
+
+			handler.ServeHTTP(w, r.WithContext(domain.WithTenantID(r.Context(), tenant)))
+		})
+	}
+}
+
+// subdomainTenant returns the single label host adds in front of baseDomain,
+// or "" when host is not such a subdomain
+func subdomainTenant(host, baseDomain string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+baseDomain)
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
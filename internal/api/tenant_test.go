@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		header     string
+		boundTo    string
+		wantStatus int
+		wantTenant string
+	}{
+		{"Default tenant", "inventory.example.com", "", "", http.StatusOK, domain.DefaultTenantID},
+		{"Header", "inventory.example.com", "acme", "", http.StatusOK, "acme"},
+		{"Subdomain", "globex.inventory.example.com:8080", "", "", http.StatusOK, "globex"},
+		{"Header before subdomain", "globex.inventory.example.com", "acme", "", http.StatusOK, "acme"},
+		{"Nested subdomain", "a.b.inventory.example.com", "", "", http.StatusOK, domain.DefaultTenantID},
+		{"Bound caller", "inventory.example.com", "", "acme", http.StatusOK, "acme"},
+		{"Bound caller naming its tenant", "acme.inventory.example.com", "", "acme", http.StatusOK, "acme"},
+		{"Bound caller naming another tenant", "inventory.example.com", "globex", "acme", http.StatusForbidden, ""},
+		{"Invalid tenant", "inventory.example.com", "Acme Corp", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTenant := ""
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant = domain.TenantIDFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+			if tt.boundTo != "" {
+				principal := &domain.Principal{Subject: "shop", Role: domain.RoleOperator, Tenant: tt.boundTo}
+				req = req.WithContext(context.WithValue(req.Context(), principalKey{}, principal))
+			}
+			rr := httptest.NewRecorder()
+			TenantMiddleware("X-Tenant-ID", "inventory.example.com")(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("Expected tenant %q, got %q", tt.wantTenant, gotTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for every request, continuing the
+// caller's trace when the request carries trace context headers. It should
+// be the outermost middleware so that the whole request, including its log
+// records, belongs to the span. Request metrics are left to MetricsMiddleware.
+func TracingMiddleware(provider trace.TracerProvider, propagator propagation.TextMapPropagator) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return otelhttp.NewHandler(handler, "HTTP",
+			otelhttp.WithTracerProvider(provider),
+			otelhttp.WithPropagators(propagator),
+			otelhttp.WithMeterProvider(noop.NewMeterProvider()),
+			otelhttp.WithSpanNameFormatter(spanName),
+		)
+	}
+}
+
+// SpanRouteMiddleware names the request span after the route that served the
+// request, e.g. "POST /api/products/{id}/reserve". Like MetricsMiddleware it
+// must wrap the mux directly, so it sees the pattern the mux records.
+func SpanRouteMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+
+		span := trace.SpanFromContext(r.Context())
+		if r.Pattern == "" || !span.IsRecording() {
+			return
+		}
+		span.SetName(spanName("", r))
+		span.SetAttributes(semconv.HTTPRoute(routeLabel(r.Pattern)))
+	})
+}
+
+// spanName names a request span by method and route, or by method alone while
+// the route is not known yet
+func spanName(_ string, r *http.Request) string {
+	if r.Pattern == "" {
+		return r.Method
+	}
+	return r.Method + " " + routeLabel(r.Pattern)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddlewareNamesSpansByRoute(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	propagator := propagation.TraceContext{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/warehouses/{id}", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusNotFound, "NOT_FOUND", "Warehouse not found")
+	})
+	handler := TracingMiddleware(provider, propagator)(SpanRouteMiddleware(mux))
+
+	// The caller's trace context is continued
+	callerCtx, caller := provider.Tracer("test").Start(context.Background(), "caller")
+	req := httptest.NewRequest(http.MethodGet, "/api/warehouses/a", nil)
+	propagator.Inject(callerCtx, propagation.HeaderCarrier(req.Header))
+	caller.End()
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+		if span.Name == "GET /api/warehouses/{id}" && span.Parent.SpanID() != caller.SpanContext().SpanID() {
+			t.Error("Expected the request span to continue the caller's trace")
+		}
+	}
+	if len(names) != 3 || names[1] != "GET /api/warehouses/{id}" || names[2] != "GET" {
+		t.Errorf("Expected the caller, route and unmatched spans, got %v", names)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// UsageMiddleware meters every API call for its tenant, the authenticated
+// caller or domain.AnonymousTenant, and attributes the transactions the
+// request records to it. Health checks and metric scrapes are not metered.
+func UsageMiddleware(meter *service.UsageMeter) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if infrastructurePath(r.URL.Path) {
+				handler.ServeHTTP(w, r)
+				return
+			}
+
+			tenant := domain.AnonymousTenant
+			if principal := RequestPrincipal(r.Context()); principal != nil {
+				tenant = principal.Subject
+			}
+			meter.RecordCall(tenant)
+			handler.ServeHTTP(w, r.WithContext(service.WithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+// UsageHandler handles tenant usage reports
+type UsageHandler struct {
+	meter *service.UsageMeter
+}
+
+// NewUsageHandler creates a new UsageHandler
+func NewUsageHandler(meter *service.UsageMeter) *UsageHandler {
+	return &UsageHandler{
+		meter: meter,
+	}
+}
+
+// GetUsageHandler reports the usage per tenant over ?from=&to= (dates, UTC,
+// inclusive), by default the current month to date; ?tenant= restricts the
+// report to one tenant
+func (h *UsageHandler) GetUsageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	from, to, ok := parseReportDays(w, query)
+	if !ok {
+		return
+	}
+
+	report, err := h.meter.Report(r.Context(), query.Get("tenant"), from, to)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Usage retrieved successfully", report)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// emptyUsageRepository stores no usage, so reports show unflushed counts only
+type emptyUsageRepository struct{}
+
+func (emptyUsageRepository) AddUsage(ctx context.Context, day time.Time, usage []*domain.TenantUsage) error {
+	return nil
+}
+
+func (emptyUsageRepository) Usage(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantUsage, error) {
+	return nil, nil
+}
+
+func (emptyUsageRepository) CountStoredSKUs(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestUsageMiddleware(t *testing.T) {
+	auth, err := service.NewAuthService([]service.APIKey{{Name: "shop", Role: domain.RoleReader, Key: "shop-key"}}, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+	meter := service.NewUsageMeter(emptyUsageRepository{}, nil)
+
+	var tenants []string
+	handler := AuthMiddleware(auth)(UsageMiddleware(meter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, service.TenantFromContext(r.Context()))
+	})))
+
+	for _, key := range []string{"shop-key", "shop-key", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if len(tenants) != 4 || tenants[0] != "shop" || tenants[2] != domain.AnonymousTenant || tenants[3] != "" {
+		t.Errorf("Unexpected request tenants: %q", tenants)
+	}
+
+	rr := httptest.NewRecorder()
+	NewUsageHandler(meter).GetUsageHandler(rr, httptest.NewRequest(http.MethodGet, "/api/admin/usage", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	report, err := meter.Report(context.Background(), "", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(report.Tenants) != 2 || report.Tenants[0].Tenant != domain.AnonymousTenant || report.Tenants[0].APICalls != 1 ||
+		report.Tenants[1].Tenant != "shop" || report.Tenants[1].APICalls != 2 {
+		t.Errorf("Expected 1 anonymous and 2 shop calls, got %+v", report.Tenants)
+	}
+
+	rr = httptest.NewRecorder()
+	NewUsageHandler(meter).GetUsageHandler(rr, httptest.NewRequest(http.MethodGet, "/api/admin/usage?from=2026-13-01", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid date, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// VariantHandler handles product variant requests
+type VariantHandler struct {
+	variantService *service.VariantService
+}
+
+// NewVariantHandler creates a new VariantHandler
+func NewVariantHandler(variantService *service.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// CreateVariantRequest represents a product variant creation request. Name
+// and price default to the parent's.
+type CreateVariantRequest struct {
+	SKU             string            `json:"sku"`
+	Name            string            `json:"name"`
+	Price           *float64          `json:"price"`
+	Attributes      map[string]string `json:"attributes"`
+	Location        string            `json:"location"`
+	InitialQuantity int64             `json:"initial_quantity"`
+}
+
+// CreateVariantHandler handles creating a variant of a product
+func (h *VariantHandler) CreateVariantHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	variant, err := h.variantService.CreateVariant(r.Context(), r.PathValue("id"), service.NewVariant{
+		SKU:             req.SKU,
+		Name:            req.Name,
+		Price:           req.Price,
+		Attributes:      req.Attributes,
+		Location:        req.Location,
+		InitialQuantity: req.InitialQuantity,
+	})
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Product variant created successfully", variant)
+}
+
+// ListVariantsHandler handles listing the variants of a product with their
+// stock rolled up
+func (h *VariantHandler) ListVariantsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	rollup, err := h.variantService.ListVariants(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "RETRIEVAL_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Product variants retrieved successfully", rollup)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// WarehouseHandler handles warehouse requests
+type WarehouseHandler struct {
+	warehouseService *service.WarehouseService
+}
+
+// NewWarehouseHandler creates a new WarehouseHandler
+func NewWarehouseHandler(warehouseService *service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+	}
+}
+
+// CreateWarehouseRequest represents a warehouse creation request
+type CreateWarehouseRequest struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Address      string   `json:"address"`
+	Timezone     string   `json:"timezone"`
+	CutoffTime   string   `json:"cutoff_time"`
+	LeadTimeDays *int     `json:"lead_time_days"`
+	WorkingDays  []string `json:"working_days"`
+	Holidays     []string `json:"holidays"`
+}
+
+// UpdateWarehouseRequest represents a warehouse update request. Omitted
+// schedule and calendar fields fall back to the service defaults.
+type UpdateWarehouseRequest struct {
+	Name         string   `json:"name"`
+	Address      string   `json:"address"`
+	Timezone     string   `json:"timezone"`
+	CutoffTime   string   `json:"cutoff_time"`
+	LeadTimeDays *int     `json:"lead_time_days"`
+	WorkingDays  []string `json:"working_days"`
+	Holidays     []string `json:"holidays"`
+}
+
+// CreateWarehouseHandler handles warehouse creation
+func (h *WarehouseHandler) CreateWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only POST is allowed")
+		return
+	}
+
+	var req CreateWarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	warehouse := &domain.Warehouse{
+		Code:         req.Code,
+		Name:         req.Name,
+		Address:      req.Address,
+		Timezone:     req.Timezone,
+		CutoffTime:   req.CutoffTime,
+		LeadTimeDays: req.LeadTimeDays,
+		WorkingDays:  req.WorkingDays,
+		Holidays:     req.Holidays,
+	}
+
+	if err := h.warehouseService.CreateWarehouse(r.Context(), warehouse); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "CREATION_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusCreated, "Warehouse created successfully", warehouse)
+}
+
+// GetWarehouseHandler handles retrieving a warehouse
+func (h *WarehouseHandler) GetWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	warehouse, err := h.warehouseService.GetWarehouse(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeServiceError(w, err, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Warehouse retrieved successfully", warehouse)
+}
+
+// UpdateWarehouseHandler handles updating a warehouse
+func (h *WarehouseHandler) UpdateWarehouseHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only PUT is allowed")
+		return
+	}
+
+	var req UpdateWarehouseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body")
+		return
+	}
+
+	warehouse := &domain.Warehouse{
+		ID:           r.PathValue("id"),
+		Name:         req.Name,
+		Address:      req.Address,
+		Timezone:     req.Timezone,
+		CutoffTime:   req.CutoffTime,
+		LeadTimeDays: req.LeadTimeDays,
+		WorkingDays:  req.WorkingDays,
+		Holidays:     req.Holidays,
+	}
+
+	if err := h.warehouseService.UpdateWarehouse(r.Context(), warehouse); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "UPDATE_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Warehouse updated successfully", warehouse)
+}
+
+// ListWarehousesHandler handles listing warehouses
+func (h *WarehouseHandler) ListWarehousesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	warehouses, err := h.warehouseService.ListWarehouses(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Warehouses retrieved successfully", warehouses)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// WMSHandler handles requests about the events consumed from the WMS
+type WMSHandler struct {
+	wmsService *service.WMSService
+}
+
+// NewWMSHandler creates a new WMSHandler
+func NewWMSHandler(wmsService *service.WMSService) *WMSHandler {
+	return &WMSHandler{
+		wmsService: wmsService,
+	}
+}
+
+// ListDeadLettersHandler handles listing the WMS messages that could not be
+// applied, newest first
+func (h *WMSHandler) ListDeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "Only GET is allowed")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	letters, err := h.wmsService.ListDeadLetters(r.Context(), limit, offset)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError, "LIST_FAILED")
+		return
+	}
+
+	WriteSuccess(w, http.StatusOK, "Dead letters retrieved successfully", letters)
+}
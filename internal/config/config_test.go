@@ -0,0 +1,276 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envOf returns a getenv function serving the given variables
+func envOf(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+// writeConfig writes a config file into a temporary directory
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load("", envOf(nil))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.Addr() != ":8080" || cfg.Server.WriteTimeout != 15*time.Second || cfg.Server.TLSEnabled() {
+		t.Errorf("Unexpected server defaults: %+v", cfg.Server)
+	}
+	if cfg.Database.MaxOpenConns != 25 || cfg.Database.MaxIdleConns != 5 {
+		t.Errorf("Unexpected pool defaults: %+v", cfg.Database)
+	}
+	if cfg.Auth.Mode != AuthModeAuto || cfg.Cache.ReadTTL != 0 {
+		t.Errorf("Unexpected defaults: %+v %+v", cfg.Auth, cfg.Cache)
+	}
+}
+
+func TestLoadFileAndEnvironment(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: 9090
+  write_timeout: 30s
+database:
+  url: postgres://db/inventory
+  max_open_conns: 50
+  conn_max_lifetime: 30m
+log:
+  level: debug
+cache:
+  read_ttl: 5s
+auth:
+  api_keys:
+    - "shop:reader:secret-1"
+tenancy:
+  enabled: true
+  domain: inventory.example.com
+`)
+
+	cfg, err := Load(path, envOf(map[string]string{
+		"SERVER_PORT":          "9443",
+		"LOG_LEVEL":            "warn",
+		"READ_CACHE_SIZE":      "",
+		"AUTH_POLICY_FILE":     "policy.yaml",
+		"DB_AUTO_MIGRATE":      "true",
+		"TENANT_HEADER":        "X-Merchant",
+		"DATABASE_REPLICA_URL": "postgres://replica/inventory",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The environment wins over the file, the file over the defaults, and
+	// empty variables count as unset
+	if cfg.Server.Port != 9443 || cfg.Log.Level != "warn" {
+		t.Errorf("Expected environment overrides, got port %d and level %q", cfg.Server.Port, cfg.Log.Level)
+	}
+	if cfg.Server.WriteTimeout != 30*time.Second || cfg.Server.ReadTimeout != 15*time.Second {
+		t.Errorf("Expected the file's write timeout and the default read timeout, got %+v", cfg.Server)
+	}
+	if cfg.Database.URL != "postgres://db/inventory" || cfg.Database.MaxOpenConns != 50 || cfg.Database.ConnMaxLifetime != 30*time.Minute || !cfg.Database.AutoMigrate {
+		t.Errorf("Unexpected database settings: %+v", cfg.Database)
+	}
+	if cfg.Database.ReplicaURL != "postgres://replica/inventory" || cfg.Database.ReplicaCheckInterval != 5*time.Second {
+		t.Errorf("Unexpected replica settings: %+v", cfg.Database)
+	}
+	if cfg.Cache.ReadTTL != 5*time.Second || cfg.Cache.ReadSize != 10000 {
+		t.Errorf("Unexpected cache settings: %+v", cfg.Cache)
+	}
+	if !cfg.Auth.HasCredentials() || cfg.Auth.PolicyFile != "policy.yaml" {
+		t.Errorf("Expected the API keys of the file and the policy file of the environment, got %+v", cfg.Auth)
+	}
+	if !cfg.Tenancy.Enabled || cfg.Tenancy.Domain != "inventory.example.com" || cfg.Tenancy.Header != "X-Merchant" {
+		t.Errorf("Unexpected tenancy settings: %+v", cfg.Tenancy)
+	}
+}
+
+func TestLoadRejectsInvalidConfiguration(t *testing.T) {
+	if _, err := Load(writeConfig(t, "server:\n  prot: 9090\n"), envOf(nil)); err == nil || !strings.Contains(err.Error(), "prot") {
+		t.Errorf("Expected unknown keys to be rejected, got %v", err)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml"), envOf(nil)); err == nil {
+		t.Error("Expected an error for a missing config file")
+	}
+	if _, err := Load("", envOf(map[string]string{"SERVER_WRITE_TIMEOUT": "soon"})); err == nil || !strings.Contains(err.Error(), "SERVER_WRITE_TIMEOUT") {
+		t.Errorf("Expected the malformed variable to be named, got %v", err)
+	}
+	if _, err := Load("", envOf(map[string]string{"DB_AUTO_MIGRATE": "sometimes"})); err == nil || !strings.Contains(err.Error(), "DB_AUTO_MIGRATE") {
+		t.Errorf("Expected the malformed flag to be named, got %v", err)
+	}
+
+	// Every problem is reported at once
+	_, err := Load("", envOf(map[string]string{
+		"SERVER_PORT":               "70000",
+		"SERVER_TLS_CERT_FILE":      "cert.pem",
+		"DB_MAX_IDLE_CONNS":         "30",
+		"DATABASE_REPLICA_URL":      "postgres://replica/inventory",
+		"DB_REPLICA_CHECK_INTERVAL": "-1s",
+		"LOG_LEVEL":                 "verbose",
+		"AUTH_MODE":                 "required",
+		"TENANCY_ENABLED":           "true",
+		"TENANT_DOMAIN":             ".example.com",
+	}))
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, want := range []string{"server.port", "tls_key_file", "max_idle_conns", "replica_check_interval", "log level", "auth.mode required", "tenancy.domain"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := Load("", envOf(map[string]string{"AUTH_MODE": "open", "AUTH_JWT_SECRET": "s3cret"})); err == nil {
+		t.Error("Expected open mode with credentials to be rejected")
+	}
+}
+
+func TestLoadMemoryDriver(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "database:\n  driver: memory\n  url: \"\"\n  data_file: /tmp/inventory.gob\n"),
+		envOf(map[string]string{"DB_SAVE_INTERVAL": "30s"}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Database.Driver != DriverMemory || cfg.Database.DataFile != "/tmp/inventory.gob" || cfg.Database.SaveInterval != 30*time.Second {
+		t.Errorf("Unexpected database settings: %+v", cfg.Database)
+	}
+
+	_, err = Load("", envOf(map[string]string{
+		"DATABASE_DRIVER":      "memory",
+		"DATABASE_REPLICA_URL": "postgres://replica/inventory",
+		"DATABASE_DATA_FILE":   "inventory.gob",
+		"DB_SAVE_INTERVAL":     "0s",
+	}))
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, want := range []string{"replica_url requires the postgres driver", "save_interval"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+
+	if _, err := Load("", envOf(map[string]string{"DATABASE_DRIVER": "sqlite"})); err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Errorf("Expected an unknown driver to be rejected, got %v", err)
+	}
+}
+
+func TestLoadSchemaDriftMode(t *testing.T) {
+	cfg, err := Load("", envOf(map[string]string{"DB_SCHEMA_DRIFT": "read_only"}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Database.SchemaDrift != SchemaDriftReadOnly {
+		t.Errorf("Expected read_only, got %q", cfg.Database.SchemaDrift)
+	}
+
+	if _, err := Load("", envOf(map[string]string{"DB_SCHEMA_DRIFT": "ignore"})); err == nil || !strings.Contains(err.Error(), "database.schema_drift") {
+		t.Errorf("Expected an unknown schema drift mode to be rejected, got %v", err)
+	}
+}
+
+func TestLoadFeatureSettings(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+reservations:
+  quota_products:
+    prod-1: 5
+approval:
+  webhook_urls: ["https://approvals.example.com/check"]
+schedule:
+  billing: 30m
+`), envOf(map[string]string{
+		"RESERVATION_QUOTA":            "10",
+		"HOT_PRODUCT_IDS":              "prod-1, ,prod-2",
+		"STOCK_LOCK_TIMEOUT":           "500ms",
+		"APPROVAL_FAIL_MODE":           "open",
+		"DEFAULT_CUTOFF_TIME":          "14:30",
+		"DEFAULT_WORKING_DAYS":         "MON,TUE,WED,THU,FRI",
+		"PAYLOAD_AUDIT_SAMPLE_PERCENT": "2.5",
+		"TRANSFER_LANES":               "a:b:1.5:24,b:a:1.5:24",
+		"RESERVATION_EXPIRY_INTERVAL":  "30s",
+		"OTEL_EXPORTER_OTLP_ENDPOINT":  "http://collector:4318",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Reservations.Quota != 10 || cfg.Reservations.QuotaProducts["prod-1"] != 5 || cfg.Reservations.TTL != 15*time.Minute {
+		t.Errorf("Unexpected reservation settings: %+v", cfg.Reservations)
+	}
+	if len(cfg.Writes.HotProductIDs) != 2 || cfg.Writes.LockTimeout != 500*time.Millisecond || cfg.Writes.QueueLimit != 100 {
+		t.Errorf("Unexpected write settings: %+v", cfg.Writes)
+	}
+	if cfg.Approval.FailMode != ApprovalFailOpen || len(cfg.Approval.WebhookURLs) != 1 || cfg.Approval.Threshold != 100 {
+		t.Errorf("Unexpected approval settings: %+v", cfg.Approval)
+	}
+	schedule, err := cfg.Shipping.Schedule()
+	if err != nil || schedule.Cutoff != 14*time.Hour+30*time.Minute || !schedule.Calendar.Closed[time.Sunday] {
+		t.Errorf("Unexpected ship schedule %+v (%v)", schedule, err)
+	}
+	if cfg.Audit.PayloadSamplePercent != 2.5 || len(cfg.Routing.Lanes) != 2 || !cfg.Tracing.Enabled {
+		t.Errorf("Unexpected settings: %+v %+v %+v", cfg.Audit, cfg.Routing, cfg.Tracing)
+	}
+	if cfg.Schedule.Billing != 30*time.Minute || cfg.Schedule.ReservationExpiry != 30*time.Second || cfg.Schedule.Reconciliation != 24*time.Hour {
+		t.Errorf("Unexpected schedule: %+v", cfg.Schedule)
+	}
+	if loc, err := cfg.Reporting.Location(); err != nil || loc != time.UTC {
+		t.Errorf("Expected the UTC reporting timezone, got %v (%v)", loc, err)
+	}
+}
+
+func TestLoadRejectsInvalidFeatureSettings(t *testing.T) {
+	if _, err := Load("", envOf(map[string]string{"RESERVATION_QUOTA_PRODUCTS": "prod-1"})); err == nil || !strings.Contains(err.Error(), "RESERVATION_QUOTA_PRODUCTS") {
+		t.Errorf("Expected the malformed quota entry to be named, got %v", err)
+	}
+
+	_, err := Load("", envOf(map[string]string{
+		"STREAM_MAX_SUBSCRIBERS":       "0",
+		"ERROR_DOCS_URL":               "docs/errors",
+		"APPROVAL_FAIL_MODE":           "maybe",
+		"DEFAULT_CUTOFF_TIME":          "2pm",
+		"REPORTING_TIMEZONE":           "Mars/Olympus",
+		"AUDIT_SIGNING_KEY":            "c2hvcnQ=",
+		"STOCKTAKE_FREEZE_MODE":        "thaw",
+		"BILLING_UNITS_PER_PALLET":     "0",
+		"EVENT_KAFKA_REST_URL":         "http://kafka:8082",
+		"EVENT_NATS_URL":               "nats://nats:4222",
+		"DATA_QUALITY_INTERVAL":        "0s",
+		"PAYLOAD_AUDIT_SAMPLE_PERCENT": "150",
+	}))
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, want := range []string{
+		"api.stream_max_subscribers", "api.error_docs_url", "approval.fail_mode", "cutoff time", "reporting.timezone",
+		"audit.signing_key", "stocktake.freeze_mode", "billing.units_per_pallet", "events.kafka_rest_url",
+		"schedule.data_quality", "audit.payload_sample_percent",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestLoadExampleFile(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "config.example.yaml"), envOf(nil))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defaults := Default()
+	if cfg.Events != defaults.Events || cfg.Schedule != defaults.Schedule || cfg.API != defaults.API {
+		t.Errorf("Expected the example file to match the defaults, got %+v %+v %+v", cfg.Events, cfg.Schedule, cfg.API)
+	}
+}
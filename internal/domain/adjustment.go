@@ -0,0 +1,30 @@
+package domain
+
+// AdjustmentReason explains why a stock adjustment corrected the on-hand
+// quantity of an inventory item
+type AdjustmentReason string
+
+const (
+	// AdjustCycleCount corrects the quantity after a routine cycle count
+	AdjustCycleCount AdjustmentReason = "CYCLE_COUNT"
+	// AdjustPhysicalAudit corrects the quantity after a full physical audit
+	AdjustPhysicalAudit AdjustmentReason = "PHYSICAL_AUDIT"
+	// AdjustDamage writes off damaged units
+	AdjustDamage AdjustmentReason = "DAMAGE"
+	// AdjustLoss writes off lost or stolen units
+	AdjustLoss AdjustmentReason = "LOSS"
+	// AdjustFound books units found that were not on record
+	AdjustFound AdjustmentReason = "FOUND"
+	// AdjustDataCorrection fixes a booking error
+	AdjustDataCorrection AdjustmentReason = "DATA_CORRECTION"
+)
+
+// ParseAdjustmentReason parses an adjustment reason code
+func ParseAdjustmentReason(value string) (AdjustmentReason, error) {
+	switch reason := AdjustmentReason(value); reason {
+	case AdjustCycleCount, AdjustPhysicalAudit, AdjustDamage, AdjustLoss, AdjustFound, AdjustDataCorrection:
+		return reason, nil
+	}
+	return "", NewValidationError("unknown reason code %q (supported: %s, %s, %s, %s, %s, %s)", value,
+		AdjustCycleCount, AdjustPhysicalAudit, AdjustDamage, AdjustLoss, AdjustFound, AdjustDataCorrection)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxAnnotationLength is the longest note an annotation may carry
+const MaxAnnotationLength = 2000
+
+// TransactionAnnotation is a note appended to a transaction after the fact,
+// e.g. "investigated, shrinkage confirmed". Annotations are immutable: a
+// correction is another annotation.
+type TransactionAnnotation struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	Note          string    `json:"note"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Validate checks that the annotation has a note of at most
+// MaxAnnotationLength characters
+func (a *TransactionAnnotation) Validate() error {
+	if strings.TrimSpace(a.Note) == "" {
+		return NewValidationError("note is required")
+	}
+	if len([]rune(a.Note)) > MaxAnnotationLength {
+		return NewValidationError("note is at most %d characters", MaxAnnotationLength)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// AuditManifest describes an exported ledger file and carries a detached
+// Ed25519 signature over its contents. It is stored when the export is
+// made, under ID, so that the manifest of a delivered file can be fetched
+// again later.
+type AuditManifest struct {
+	ID          string    `json:"id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generated_at"`
+	RowCount    int       `json:"row_count"`
+	SHA256      string    `json:"sha256"`
+	Algorithm   string    `json:"algorithm"`
+	PublicKey   string    `json:"public_key"`
+	Signature   string    `json:"signature"`
+}
+
+// SigningPayload returns the canonical bytes covered by the manifest signature
+func (m *AuditManifest) SigningPayload() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%d|%s",
+		m.From.UTC().Format(time.RFC3339Nano),
+		m.To.UTC().Format(time.RFC3339Nano),
+		m.GeneratedAt.UTC().Format(time.RFC3339Nano),
+		m.RowCount,
+		m.SHA256,
+	))
+}
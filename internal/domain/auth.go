@@ -0,0 +1,54 @@
+package domain
+
+// Role is the access level of an authenticated caller. Roles are ordered:
+// each one includes the permissions of the ones before it.
+type Role string
+
+const (
+	// RoleReader may read products, inventory and reports
+	RoleReader Role = "reader"
+	// RoleOperator may additionally move, reserve and count stock
+	RoleOperator Role = "operator"
+	// RoleAdmin may additionally change the catalog and use admin endpoints
+	RoleAdmin Role = "admin"
+	// RoleSupplier may only use the supplier portal, for the purchase orders
+	// of the supplier named by the caller's subject. It stands outside the
+	// order of the other roles: it grants none of their permissions and none
+	// of them grants its.
+	RoleSupplier Role = "supplier"
+)
+
+// roleRanks orders the roles from least to most privileged
+var roleRanks = map[Role]int{
+	RoleReader:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// ParseRole parses a role name
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if _, ok := roleRanks[role]; !ok && role != RoleSupplier {
+		return "", NewValidationError("unknown role %q (supported: %s, %s, %s, %s)", value, RoleReader, RoleOperator, RoleAdmin, RoleSupplier)
+	}
+	return role, nil
+}
+
+// Allows reports whether the role grants the permissions of required
+func (r Role) Allows(required Role) bool {
+	if r == RoleSupplier || required == RoleSupplier {
+		return r == required
+	}
+	rank, ok := roleRanks[r]
+	return ok && rank >= roleRanks[required]
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+	// Method is how the caller authenticated: api_key or jwt
+	Method string `json:"method"`
+	// Tenant binds the caller to one tenant; empty lets it name any tenant
+	Tenant string `json:"tenant,omitempty"`
+}
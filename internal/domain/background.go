@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// BackgroundComponent names a part of the background processing that can be
+// paused
+type BackgroundComponent string
+
+const (
+	// BackgroundScheduler runs the periodic tasks: reservation and cart hold
+	// expiry, drop releases, stock snapshots and alerts, channel rebalancing,
+	// billing, data quality checks, stock reconciliation, expired lot
+	// write-offs and retention
+	BackgroundScheduler BackgroundComponent = "scheduler"
+	// BackgroundConsumers consume the event streams, such as the WMS scan
+	// events
+	BackgroundConsumers BackgroundComponent = "consumers"
+)
+
+// BackgroundComponents lists every component that can be paused
+var BackgroundComponents = []BackgroundComponent{BackgroundScheduler, BackgroundConsumers}
+
+// ParseBackgroundComponents validates the named components. No names select
+// every component.
+func ParseBackgroundComponents(names []string) ([]BackgroundComponent, error) {
+	if len(names) == 0 {
+		return BackgroundComponents, nil
+	}
+
+	components := make([]BackgroundComponent, 0, len(names))
+	for _, name := range names {
+		component := BackgroundComponent(name)
+		if !slices.Contains(BackgroundComponents, component) {
+			return nil, NewValidationError("unknown background component %q: must be one of %v", name, BackgroundComponents)
+		}
+		if !slices.Contains(components, component) {
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+// BackgroundPause records that an operator paused a component
+type BackgroundPause struct {
+	Component BackgroundComponent `json:"component"`
+	PausedBy  string              `json:"paused_by,omitempty"`
+	Reason    string              `json:"reason,omitempty"`
+	PausedAt  time.Time           `json:"paused_at"`
+}
+
+// BackgroundStatus is whether a component is paused and, if so, by whom
+type BackgroundStatus struct {
+	Component BackgroundComponent `json:"component"`
+	Paused    bool                `json:"paused"`
+	PausedBy  string              `json:"paused_by,omitempty"`
+	Reason    string              `json:"reason,omitempty"`
+	PausedAt  *time.Time          `json:"paused_at,omitempty"`
+}
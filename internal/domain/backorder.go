@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// BackorderStatus represents the lifecycle state of a backorder
+type BackorderStatus string
+
+const (
+	BackorderStatusOpen      BackorderStatus = "OPEN"
+	BackorderStatusCancelled BackorderStatus = "CANCELLED"
+)
+
+// Backorder is the part of an order line that could not be reserved when the
+// order was placed, kept so that purchasing can cover it
+type Backorder struct {
+	ID        string          `json:"id"`
+	ProductID string          `json:"product_id"`
+	Location  string          `json:"location"`
+	Quantity  int64           `json:"quantity"`
+	Reference string          `json:"reference"`
+	Status    BackorderStatus `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Validate checks if the backorder data is valid
+func (b *Backorder) Validate() error {
+	if b.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if b.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if b.Reference == "" {
+		return NewValidationError("reference cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// BillingEventType is a kind of work the 3PL operating the system bills the
+// tenant owning the stock for
+type BillingEventType string
+
+// Billing event types. Quantity counts units for receipts and picks, pallets
+// stored over one day for storage and one per shipment.
+const (
+	BillingEventReceipt  BillingEventType = "RECEIPT"
+	BillingEventStorage  BillingEventType = "STORAGE"
+	BillingEventPick     BillingEventType = "PICK"
+	BillingEventShipment BillingEventType = "SHIPMENT"
+)
+
+// TenantBilling totals the billing events of one tenant over a period
+type TenantBilling struct {
+	TenantID      string `json:"tenant_id"`
+	Receipts      int64  `json:"receipts"`
+	ReceivedUnits int64  `json:"received_units"`
+	PalletDays    int64  `json:"pallet_days"`
+	Picks         int64  `json:"picks"`
+	PickedUnits   int64  `json:"picked_units"`
+	Shipments     int64  `json:"shipments"`
+}
+
+// Add adds the number of events of a type and the sum of their quantities
+func (b *TenantBilling) Add(eventType BillingEventType, events, quantity int64) {
+	switch eventType {
+	case BillingEventReceipt:
+		b.Receipts += events
+		b.ReceivedUnits += quantity
+	case BillingEventStorage:
+		b.PalletDays += quantity
+	case BillingEventPick:
+		b.Picks += events
+		b.PickedUnits += quantity
+	case BillingEventShipment:
+		b.Shipments += events
+	}
+}
+
+// BillingReport is the billable work of every tenant over the days from From
+// to To, inclusive
+type BillingReport struct {
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	UnitsPerPallet int64            `json:"units_per_pallet"`
+	Tenants        []*TenantBilling `json:"tenants"`
+}
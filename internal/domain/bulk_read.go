@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// MaxBulkReadSKUs bounds the number of SKUs of one bulk read
+const MaxBulkReadSKUs = 10000
+
+// SKUAvailability is the stock of a SKU summed over all its locations
+type SKUAvailability struct {
+	SKU       string `json:"sku"`
+	ProductID string `json:"product_id"`
+	Quantity  int64  `json:"quantity"`
+	Reserved  int64  `json:"reserved"`
+	Available int64  `json:"available"`
+}
+
+// BulkAvailability is the availability of many SKUs, in the order they were
+// requested. SKUs without an active product are listed in Missing.
+type BulkAvailability struct {
+	Items   []*SKUAvailability `json:"items"`
+	Missing []string           `json:"missing"`
+}
+
+// ProductExport is one product of a catalog export with its stock at every
+// location it is stocked in and the totals over them
+type ProductExport struct {
+	ProductID string                `json:"product_id"`
+	SKU       string                `json:"sku"`
+	Name      string                `json:"name"`
+	Category  string                `json:"category"`
+	Price     float64               `json:"price"`
+	Archived  bool                  `json:"archived"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Quantity  int64                 `json:"quantity"`
+	Reserved  int64                 `json:"reserved"`
+	Available int64                 `json:"available"`
+	Locations []*ProductExportStock `json:"locations"`
+}
+
+// ProductExportStock is the stock of an exported product at one location in
+// one condition
+type ProductExportStock struct {
+	Location  string         `json:"location"`
+	Condition StockCondition `json:"condition"`
+	Quantity  int64          `json:"quantity"`
+	Reserved  int64          `json:"reserved"`
+	Available int64          `json:"available"`
+}
+
+// AddStock adds the stock of a location to the export and its totals
+func (e *ProductExport) AddStock(stock *ProductExportStock) {
+	e.Locations = append(e.Locations, stock)
+	e.Quantity += stock.Quantity
+	e.Reserved += stock.Reserved
+	e.Available += stock.Available
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// MaxCartHoldLines bounds the number of lines of one cart hold
+const MaxCartHoldLines = 100
+
+// CartHoldStatus represents the lifecycle state of a cart hold
+type CartHoldStatus string
+
+const (
+	CartHoldStatusHeld      CartHoldStatus = "HELD"
+	CartHoldStatusConverted CartHoldStatus = "CONVERTED"
+	CartHoldStatusReleased  CartHoldStatus = "RELEASED"
+	CartHoldStatusExpired   CartHoldStatus = "EXPIRED"
+)
+
+// CartHoldLine is the quantity of one SKU held at one location
+type CartHoldLine struct {
+	SKU         string `json:"sku"`
+	ProductID   string `json:"product_id"`
+	InventoryID string `json:"inventory_id"`
+	Location    string `json:"location"`
+	Quantity    int64  `json:"quantity"`
+}
+
+// CartHold keeps stock for a checkout in progress for a short time. Unlike a
+// reservation it does not change the inventory's reserved quantity or write
+// to the ledger; it only counts against the stock other holds can take. On
+// payment it is converted into firm reservations, otherwise it lapses.
+type CartHold struct {
+	ID           string         `json:"id"`
+	TenantID     string         `json:"tenant_id"`
+	Reference    string         `json:"reference"`
+	Status       CartHoldStatus `json:"status"`
+	Lines        []CartHoldLine `json:"lines"`
+	Reservations []*Reservation `json:"reservations,omitempty"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// Validate checks if the cart hold data is valid
+func (h *CartHold) Validate() error {
+	if h.Reference == "" {
+		return NewValidationError("reference cannot be empty")
+	}
+	if len(h.Lines) == 0 {
+		return NewValidationError("lines cannot be empty")
+	}
+	if len(h.Lines) > MaxCartHoldLines {
+		return NewValidationError("a cart hold has at most %d lines", MaxCartHoldLines)
+	}
+
+	seen := make(map[[2]string]bool, len(h.Lines))
+	for i, line := range h.Lines {
+		if line.SKU == "" {
+			return NewValidationError("line %d: sku cannot be empty", i+1)
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("line %d: quantity must be positive", i+1)
+		}
+		key := [2]string{line.SKU, line.Location}
+		if seen[key] {
+			return NewValidationError("line %d: duplicate sku %s", i+1, line.SKU)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// IsActive reports whether the hold still keeps its stock
+func (h *CartHold) IsActive(now time.Time) bool {
+	return h.Status == CartHoldStatusHeld && now.Before(h.ExpiresAt)
+}
@@ -0,0 +1,70 @@
+package domain
+
+// CatalogEntry is one product of an external catalog snapshot, e.g. from a
+// PIM. Entries are matched to products by SKU.
+type CatalogEntry struct {
+	SKU         string  `json:"sku"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Category    string  `json:"category"`
+	Price       float64 `json:"price"`
+}
+
+// Product returns the product described by the entry
+func (e *CatalogEntry) Product() *Product {
+	return &Product{
+		Name:        e.Name,
+		Description: e.Description,
+		SKU:         e.SKU,
+		Category:    e.Category,
+		Price:       e.Price,
+	}
+}
+
+// ChangedFields lists the catalog fields in which the entry differs from a product
+func (e *CatalogEntry) ChangedFields(product *Product) []string {
+	var fields []string
+	if e.Name != product.Name {
+		fields = append(fields, "name")
+	}
+	if e.Description != product.Description {
+		fields = append(fields, "description")
+	}
+	if e.Category != product.Category {
+		fields = append(fields, "category")
+	}
+	if e.Price != product.Price {
+		fields = append(fields, "price")
+	}
+	return fields
+}
+
+// CatalogAction is the kind of change a catalog sync makes to a product
+type CatalogAction string
+
+const (
+	CatalogActionCreate CatalogAction = "CREATE"
+	CatalogActionUpdate CatalogAction = "UPDATE"
+	CatalogActionDelete CatalogAction = "DELETE"
+)
+
+// CatalogChange is one difference between the snapshot and our catalog.
+// Fields lists the changed fields of an update; Error is set when applying
+// the change failed.
+type CatalogChange struct {
+	Action    CatalogAction `json:"action"`
+	SKU       string        `json:"sku"`
+	ProductID string        `json:"product_id,omitempty"`
+	Fields    []string      `json:"fields,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// CatalogDiff is the outcome of comparing a catalog snapshot with our
+// products. Applied is true when the changes were written.
+type CatalogDiff struct {
+	Creates   []*CatalogChange `json:"creates"`
+	Updates   []*CatalogChange `json:"updates"`
+	Deletes   []*CatalogChange `json:"deletes"`
+	Unchanged int              `json:"unchanged"`
+	Applied   bool             `json:"applied"`
+}
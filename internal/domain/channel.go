@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// SalesChannelType is the kind of outlet a sales channel sells through
+type SalesChannelType string
+
+const (
+	SalesChannelWeb         SalesChannelType = "WEB"
+	SalesChannelMarketplace SalesChannelType = "MARKETPLACE"
+	SalesChannelRetail      SalesChannelType = "RETAIL"
+)
+
+// SalesChannel is an outlet that reserves stock, such as the web shop, a
+// marketplace or a retail store
+type SalesChannel struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	Type      SalesChannelType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// Validate checks if the sales channel data is valid
+func (c *SalesChannel) Validate() error {
+	if c.Name == "" {
+		return NewValidationError("channel name cannot be empty")
+	}
+	switch c.Type {
+	case SalesChannelWeb, SalesChannelMarketplace, SalesChannelRetail:
+	default:
+		return NewValidationError("channel type must be %s, %s or %s", SalesChannelWeb, SalesChannelMarketplace, SalesChannelRetail)
+	}
+	return nil
+}
+
+// ChannelAllocation caps the stock of a product a channel may hold reserved
+// at once: either a percentage of the product's on-hand quantity or a fixed
+// bucket of units. Products without an allocation are not capped.
+type ChannelAllocation struct {
+	ChannelID string    `json:"channel_id"`
+	ProductID string    `json:"product_id"`
+	Percent   *float64  `json:"percent,omitempty"`
+	Quantity  *int64    `json:"quantity,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the allocation sets exactly one of a percentage
+// between 0 and 100 and a non-negative bucket
+func (a *ChannelAllocation) Validate() error {
+	if a.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if (a.Percent == nil) == (a.Quantity == nil) {
+		return NewValidationError("exactly one of percent and quantity must be set")
+	}
+	if a.Percent != nil && (*a.Percent < 0 || *a.Percent > 100) {
+		return NewValidationError("percent must be between 0 and 100")
+	}
+	if a.Quantity != nil && *a.Quantity < 0 {
+		return NewValidationError("quantity cannot be negative")
+	}
+	return nil
+}
+
+// Limit returns the units the allocation grants out of onHand; percentages
+// round down so channels never hold more than their share
+func (a *ChannelAllocation) Limit(onHand int64) int64 {
+	if a.Quantity != nil {
+		return *a.Quantity
+	}
+	return int64(math.Floor(float64(onHand) * *a.Percent / 100))
+}
+
+// ChannelAvailability is what a channel may still reserve of a product: the
+// smaller of the unused part of its allocation and the product's available
+// stock. Allocated is nil for products the channel has no allocation for.
+type ChannelAvailability struct {
+	ChannelID string `json:"channel_id"`
+	ProductID string `json:"product_id"`
+	Allocated *int64 `json:"allocated,omitempty"`
+	Reserved  int64  `json:"reserved"`
+	Available int64  `json:"available"`
+}
+
+// ChannelAllocationChange says why a channel allocation changed
+type ChannelAllocationChange string
+
+const (
+	ChannelAllocationManual    ChannelAllocationChange = "MANUAL"
+	ChannelAllocationRebalance ChannelAllocationChange = "REBALANCE"
+)
+
+// ChannelAllocationEvent records a single change of a channel allocation.
+// The previous values are empty for a new allocation, the new ones for a
+// removed allocation.
+type ChannelAllocationEvent struct {
+	ID               string                  `json:"id"`
+	ChannelID        string                  `json:"channel_id"`
+	ProductID        string                  `json:"product_id"`
+	Change           ChannelAllocationChange `json:"change"`
+	PreviousPercent  *float64                `json:"previous_percent,omitempty"`
+	PreviousQuantity *int64                  `json:"previous_quantity,omitempty"`
+	Percent          *float64                `json:"percent,omitempty"`
+	Quantity         *int64                  `json:"quantity,omitempty"`
+	Notes            string                  `json:"notes,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+}
+
+// NewChannelAllocationEvent describes the change from previous to next, either
+// of which is nil when the allocation is created or removed
+func NewChannelAllocationEvent(channelID, productID string, change ChannelAllocationChange, previous, next *ChannelAllocation, notes string) *ChannelAllocationEvent {
+	event := &ChannelAllocationEvent{ChannelID: channelID, ProductID: productID, Change: change, Notes: notes}
+	if previous != nil {
+		event.PreviousPercent, event.PreviousQuantity = previous.Percent, previous.Quantity
+	}
+	if next != nil {
+		event.Percent, event.Quantity = next.Percent, next.Quantity
+	}
+	return event
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// MaxSupplierLength bounds the name of a consigning supplier
+const MaxSupplierLength = 255
+
+// Consignment is a lot of stock received into inventory that remains the
+// property of its supplier until sold. Remaining counts the units the
+// supplier still owns; each sale of the product at the location transfers
+// the ownership of units from the oldest lots first.
+type Consignment struct {
+	ID         string    `json:"id"`
+	Supplier   string    `json:"supplier"`
+	ProductID  string    `json:"product_id"`
+	Location   string    `json:"location"`
+	UnitCost   float64   `json:"unit_cost"`
+	Quantity   int64     `json:"quantity"`
+	Remaining  int64     `json:"remaining"`
+	Reference  string    `json:"reference,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// ConsignmentRequest asks to receive a lot of consignment stock. UnitCost is
+// what the tenant owes the supplier for each unit sold.
+type ConsignmentRequest struct {
+	Supplier  string  `json:"supplier"`
+	ProductID string  `json:"product_id"`
+	Location  string  `json:"location"`
+	Quantity  int64   `json:"quantity"`
+	UnitCost  float64 `json:"unit_cost"`
+	Reference string  `json:"reference"`
+}
+
+// Validate checks if the consignment request is valid
+func (r *ConsignmentRequest) Validate() error {
+	if r.Supplier == "" {
+		return NewValidationError("supplier cannot be empty")
+	}
+	if len(r.Supplier) > MaxSupplierLength {
+		return NewValidationError("supplier cannot be longer than %d characters", MaxSupplierLength)
+	}
+	if r.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if r.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if r.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if r.UnitCost < 0 {
+		return NewValidationError("unit_cost cannot be negative")
+	}
+	return nil
+}
+
+// ConsignmentFilter selects consignments; zero fields do not filter
+type ConsignmentFilter struct {
+	Supplier  string
+	ProductID string
+	Location  string
+	// Open selects the lots the supplier still owns units of
+	Open bool
+}
+
+// OwnershipTransfer records the units of a consignment that passed to the
+// tenant when a sale sold them, and what the tenant owes the supplier
+type OwnershipTransfer struct {
+	ID            string    `json:"id"`
+	ConsignmentID string    `json:"consignment_id"`
+	Supplier      string    `json:"supplier"`
+	ProductID     string    `json:"product_id"`
+	Location      string    `json:"location"`
+	Quantity      int64     `json:"quantity"`
+	UnitCost      float64   `json:"unit_cost"`
+	Amount        float64   `json:"amount"`
+	SaleEventID   string    `json:"sale_event_id"`
+	SoldAt        time.Time `json:"sold_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SupplierPayable is the amount owed to a supplier for an ownership
+// transfer, for accounts payable to settle
+type SupplierPayable struct {
+	ID         string    `json:"id"`
+	TransferID string    `json:"transfer_id"`
+	Supplier   string    `json:"supplier"`
+	Amount     float64   `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TransferOwnership takes the units of a sale off the open lots, oldest
+// first, and returns a transfer for each lot it takes units from. Units sold
+// beyond the lots' remaining ones were the tenant's own.
+func TransferOwnership(lots []*Consignment, sale *Sale) []*OwnershipTransfer {
+	var transfers []*OwnershipTransfer
+	unassigned := sale.Quantity
+	for _, lot := range lots {
+		if unassigned == 0 {
+			break
+		}
+		quantity := min(unassigned, lot.Remaining)
+		if quantity <= 0 {
+			continue
+		}
+		lot.Remaining -= quantity
+		unassigned -= quantity
+		transfers = append(transfers, &OwnershipTransfer{
+			ConsignmentID: lot.ID,
+			Supplier:      lot.Supplier,
+			ProductID:     lot.ProductID,
+			Location:      lot.Location,
+			Quantity:      quantity,
+			UnitCost:      lot.UnitCost,
+			Amount:        math.Round(lot.UnitCost*float64(quantity)*100) / 100,
+			SaleEventID:   sale.EventID,
+			SoldAt:        sale.OccurredAt,
+		})
+	}
+	return transfers
+}
+
+// ConsignmentSettlement is what the tenant owes each supplier for the
+// payables recorded on the days from From to To, inclusive
+type ConsignmentSettlement struct {
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Suppliers []*SupplierSettlement `json:"suppliers"`
+}
+
+// SupplierSettlement totals the payables of one supplier, with a line per
+// product and location sold
+type SupplierSettlement struct {
+	Supplier string                       `json:"supplier"`
+	Payables int64                        `json:"payables"`
+	Units    int64                        `json:"units"`
+	Amount   float64                      `json:"amount"`
+	Lines    []*ConsignmentSettlementLine `json:"lines"`
+}
+
+// ConsignmentSettlementLine totals the payables of a supplier for one
+// product sold at one location
+type ConsignmentSettlementLine struct {
+	ProductID string  `json:"product_id"`
+	Location  string  `json:"location"`
+	Payables  int64   `json:"payables"`
+	Units     int64   `json:"units"`
+	Amount    float64 `json:"amount"`
+}
+
+// Add adds a line to the supplier's totals
+func (s *SupplierSettlement) Add(line *ConsignmentSettlementLine) {
+	s.Payables += line.Payables
+	s.Units += line.Units
+	s.Amount = math.Round((s.Amount+line.Amount)*100) / 100
+	s.Lines = append(s.Lines, line)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// DataQualityCheck is a kind of inconsistency the data quality checker looks
+// for
+type DataQualityCheck string
+
+// Data quality checks
+const (
+	// Active products without any inventory row cannot be stocked or sold
+	CheckProductWithoutInventory DataQualityCheck = "PRODUCT_WITHOUT_INVENTORY"
+	// Inventory rows whose product does not exist anymore
+	CheckInventoryWithoutProduct DataQualityCheck = "INVENTORY_WITHOUT_PRODUCT"
+	// Inventory rows reserving more than they have on hand
+	CheckReservedExceedsQuantity DataQualityCheck = "RESERVED_EXCEEDS_QUANTITY"
+	// Transactions, live or archived, whose inventory row or product does not
+	// exist
+	CheckOrphanedTransaction DataQualityCheck = "ORPHANED_TRANSACTION"
+)
+
+// DataQualityChecks lists every check in the order they run
+var DataQualityChecks = []DataQualityCheck{
+	CheckProductWithoutInventory,
+	CheckInventoryWithoutProduct,
+	CheckReservedExceedsQuantity,
+	CheckOrphanedTransaction,
+}
+
+// DataQualityFinding is one inconsistency found. EntityID is the product,
+// inventory row or transaction at fault. Fixable findings can be repaired
+// automatically: empty inventory rows without a product are deleted and
+// reservations beyond the stock on hand are released.
+type DataQualityFinding struct {
+	Check       DataQualityCheck `json:"check"`
+	EntityID    string           `json:"entity_id"`
+	ProductID   string           `json:"product_id,omitempty"`
+	InventoryID string           `json:"inventory_id,omitempty"`
+	Quantity    int64            `json:"quantity"`
+	Reserved    int64            `json:"reserved"`
+	Fixable     bool             `json:"fixable"`
+	Fixed       bool             `json:"fixed"`
+	FixError    string           `json:"fix_error,omitempty"`
+}
+
+// DataQualityReport is the outcome of one run of the checker. Totals counts
+// the findings of every check; Findings lists at most a bounded number of
+// each.
+type DataQualityReport struct {
+	CheckedAt time.Time                `json:"checked_at"`
+	Totals    map[DataQualityCheck]int `json:"totals"`
+	Findings  []*DataQualityFinding    `json:"findings"`
+	Fixed     int                      `json:"fixed"`
+}
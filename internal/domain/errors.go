@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error categories shared by all layers. Errors are matched with errors.Is;
+// the API maps each category to an HTTP status and a stable error code.
+var (
+	// ErrNotFound is wrapped by errors for missing entities
+	ErrNotFound = errors.New("not found")
+	// ErrInsufficientStock is wrapped by errors for movements exceeding the
+	// available or reserved stock
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrDuplicateSKU is returned when a product SKU is already in use
+	ErrDuplicateSKU = errors.New("product SKU already exists")
+	// ErrDuplicateOrder is returned when an order ID is already in use
+	ErrDuplicateOrder = errors.New("order already exists")
+	// ErrDuplicateShippingNotice is returned when a purchase order already has
+	// a shipping notice with the same reference
+	ErrDuplicateShippingNotice = errors.New("shipping notice already submitted")
+	// ErrDuplicateSale is returned when a sale event was already recorded
+	ErrDuplicateSale = errors.New("sale already recorded")
+	// ErrDuplicateWMSEvent is returned when a WMS event was already applied
+	ErrDuplicateWMSEvent = errors.New("WMS event already applied")
+	// ErrDuplicateChannel is returned when a sales channel name is already in use
+	ErrDuplicateChannel = errors.New("sales channel already exists")
+	// ErrDuplicateVariant is returned when a parent product already has a
+	// variant with the same attributes
+	ErrDuplicateVariant = errors.New("product variant already exists")
+	// ErrConflict is wrapped when an optimistic concurrency check fails
+	// because the record was modified after it was read
+	ErrConflict = errors.New("concurrent modification conflict")
+	// ErrValidation is matched by all ValidationErrors
+	ErrValidation = errors.New("validation failed")
+	// ErrForbidden is wrapped by errors for changes the caller's role does
+	// not permit
+	ErrForbidden = errors.New("forbidden")
+	// ErrPatchTestFailed is wrapped when a test operation of a JSON Patch
+	// does not match the current value
+	ErrPatchTestFailed = errors.New("patch test failed")
+	// ErrPreconditionFailed is wrapped when a conditional stock operation
+	// would leave less stock available than the caller required
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// ValidationError describes invalid input. Its message is shown to the client
+// unchanged; errors.Is reports it as ErrValidation.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is makes ValidationErrors match ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// NewValidationError returns a ValidationError with a formatted message
+func NewValidationError(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// DuplicateSKUError names the SKU a product could not be created or renamed
+// to because another product of the tenant uses it. errors.Is reports it as
+// ErrDuplicateSKU.
+type DuplicateSKUError struct {
+	SKU string `json:"sku"`
+}
+
+func (e *DuplicateSKUError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDuplicateSKU, e.SKU)
+}
+
+// Is makes DuplicateSKUErrors match ErrDuplicateSKU
+func (e *DuplicateSKUError) Is(target error) bool {
+	return target == ErrDuplicateSKU
+}
+
+// StockUpdateError explains why a quantity update of an inventory row was
+// rejected: the row's stock when it was read back and the deltas that would
+// have taken it below zero or reserved more than is on hand. errors.Is
+// reports it as ErrInsufficientStock.
+type StockUpdateError struct {
+	InventoryID   string `json:"inventory_id"`
+	Quantity      int64  `json:"quantity"`
+	Reserved      int64  `json:"reserved"`
+	QuantityDelta int64  `json:"quantity_delta"`
+	ReservedDelta int64  `json:"reserved_delta"`
+}
+
+func (e *StockUpdateError) Error() string {
+	return fmt.Sprintf("%s for inventory item %s: quantity %d, reserved %d; attempted quantity %+d, reserved %+d",
+		ErrInsufficientStock, e.InventoryID, e.Quantity, e.Reserved, e.QuantityDelta, e.ReservedDelta)
+}
+
+// Is makes StockUpdateErrors match ErrInsufficientStock
+func (e *StockUpdateError) Is(target error) bool {
+	return target == ErrInsufficientStock
+}
@@ -0,0 +1,526 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits of a filter expression, so that one request cannot make the
+// database evaluate an arbitrarily large condition
+const (
+	MaxFilterLength = 1000
+	MaxFilterTerms  = 20
+	MaxFilterDepth  = 5
+)
+
+// FilterKind is the type of the values a field is compared with
+type FilterKind int
+
+const (
+	FilterString FilterKind = iota
+	FilterNumber
+	FilterTime
+)
+
+// FilterFields are the fields a listing can be filtered on and their kinds
+type FilterFields map[string]FilterKind
+
+// ProductFilterFields are the fields products can be filtered on
+var ProductFilterFields = FilterFields{
+	"name":       FilterString,
+	"sku":        FilterString,
+	"category":   FilterString,
+	"price":      FilterNumber,
+	"created_at": FilterTime,
+	"updated_at": FilterTime,
+}
+
+// InventoryFilterFields are the fields inventory rows can be filtered on;
+// available is the quantity not reserved
+var InventoryFilterFields = FilterFields{
+	"product_id":    FilterString,
+	"warehouse_id":  FilterString,
+	"location":      FilterString,
+	"condition":     FilterString,
+	"bin_location":  FilterString,
+	"quantity":      FilterNumber,
+	"reserved":      FilterNumber,
+	"available":     FilterNumber,
+	"reorder_level": FilterNumber,
+	"safety_stock":  FilterNumber,
+	"updated_at":    FilterTime,
+}
+
+// TransactionFilterFields are the fields transactions can be filtered on
+var TransactionFilterFields = FilterFields{
+	"product_id":   FilterString,
+	"inventory_id": FilterString,
+	"type":         FilterString,
+	"reference":    FilterString,
+	"reason_code":  FilterString,
+	"performed_by": FilterString,
+	"quantity":     FilterNumber,
+	"unit_cost":    FilterNumber,
+	"created_at":   FilterTime,
+}
+
+// FilterOperator compares a field with a value. FilterContains matches
+// strings containing the value, ignoring case.
+type FilterOperator string
+
+const (
+	FilterEqual        FilterOperator = "="
+	FilterNotEqual     FilterOperator = "!="
+	FilterLess         FilterOperator = "<"
+	FilterLessEqual    FilterOperator = "<="
+	FilterGreater      FilterOperator = ">"
+	FilterGreaterEqual FilterOperator = ">="
+	FilterContains     FilterOperator = "~"
+)
+
+// Filter is a parsed filter expression: either a comparison of a field with
+// a value, or the terms joined by Logic, AND or OR. Values are strings,
+// float64 or time.Time by the kind of the field. A nil Filter matches
+// everything.
+type Filter struct {
+	Logic string
+	Terms []*Filter
+
+	Field    string
+	Operator FilterOperator
+	Value    any
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	price > 100 AND (location = "A" OR location = "B")
+//
+// over fields. A comparison is a field, an operator (= != < <= > >= ~) and
+// a value, quoted with " or ' unless it is a single word. AND binds tighter
+// than OR and parentheses group. Times are RFC 3339 or dates, which start at
+// midnight in loc (UTC if nil), the reporting timezone of the request. An
+// empty expression returns a nil Filter; invalid ones a validation error.
+func ParseFilter(expression string, fields FilterFields, loc *time.Location) (*Filter, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+	if len(expression) > MaxFilterLength {
+		return nil, NewValidationError("filter must be at most %d characters", MaxFilterLength)
+	}
+
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	p := &filterParser{tokens: tokens, fields: fields, loc: loc}
+	filter, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if token, ok := p.peek(); ok {
+		return nil, NewValidationError("filter: unexpected %q", token.text)
+	}
+	return filter, nil
+}
+
+// AllFilters joins the filters that are not nil with AND, returning nil if
+// there are none
+func AllFilters(filters ...*Filter) *Filter {
+	var terms []*Filter
+	for _, filter := range filters {
+		if filter != nil {
+			terms = append(terms, filter)
+		}
+	}
+	switch len(terms) {
+	case 0:
+		return nil
+	case 1:
+		return terms[0]
+	}
+	return &Filter{Logic: "AND", Terms: terms}
+}
+
+// FilterPeriod selects the records whose time field lies in the half-open
+// period [from, to); a zero bound leaves that side open
+func FilterPeriod(field string, from, to time.Time) *Filter {
+	var bounds []*Filter
+	if !from.IsZero() {
+		bounds = append(bounds, &Filter{Field: field, Operator: FilterGreaterEqual, Value: from.UTC()})
+	}
+	if !to.IsZero() {
+		bounds = append(bounds, &Filter{Field: field, Operator: FilterLess, Value: to.UTC()})
+	}
+	return AllFilters(bounds...)
+}
+
+type filterTokenKind int
+
+const (
+	filterWord filterTokenKind = iota
+	filterQuoted
+	filterOperator
+	filterOpen
+	filterClose
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// tokenizeFilter splits an expression into words, quoted strings, operators
+// and parentheses
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: filterOpen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: filterClose, text: ")"})
+			i++
+		case c == '"' || c == '\'':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(expression) && expression[j] != c; j++ {
+				if expression[j] == '\\' && j+1 < len(expression) {
+					j++
+				}
+				value.WriteByte(expression[j])
+			}
+			if j == len(expression) {
+				return nil, NewValidationError("filter: unterminated string")
+			}
+			tokens = append(tokens, filterToken{kind: filterQuoted, text: value.String()})
+			i = j + 1
+		case strings.IndexByte("=!<>~", c) >= 0:
+			operator := string(c)
+			if i+1 < len(expression) && expression[i+1] == '=' && c != '=' && c != '~' {
+				operator += "="
+			}
+			if operator == "!" {
+				return nil, NewValidationError("filter: unexpected \"!\"")
+			}
+			tokens = append(tokens, filterToken{kind: filterOperator, text: operator})
+			i += len(operator)
+		default:
+			j := i
+			for j < len(expression) && strings.IndexByte(" \t\n\r()\"'=!<>~", expression[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, filterToken{kind: filterWord, text: expression[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser over the tokens of an
+// expression
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	fields FilterFields
+	loc    *time.Location
+	terms  int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// keyword consumes the next token if it is the word keyword, in any case
+func (p *filterParser) keyword(keyword string) bool {
+	token, ok := p.peek()
+	if ok && token.kind == filterWord && strings.EqualFold(token.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) or(depth int) (*Filter, error) {
+	return p.joined("OR", depth, p.and)
+}
+
+func (p *filterParser) and(depth int) (*Filter, error) {
+	return p.joined("AND", depth, p.term)
+}
+
+// joined parses terms separated by the keyword logic
+func (p *filterParser) joined(logic string, depth int, next func(int) (*Filter, error)) (*Filter, error) {
+	first, err := next(depth)
+	if err != nil {
+		return nil, err
+	}
+	terms := []*Filter{first}
+	for p.keyword(logic) {
+		term, err := next(depth)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &Filter{Logic: logic, Terms: terms}, nil
+}
+
+// term parses a parenthesized expression or a comparison
+func (p *filterParser) term(depth int) (*Filter, error) {
+	token, ok := p.peek()
+	if !ok {
+		return nil, NewValidationError("filter: unexpected end of expression")
+	}
+	if token.kind == filterOpen {
+		if depth == MaxFilterDepth {
+			return nil, NewValidationError("filter must nest at most %d parentheses deep", MaxFilterDepth)
+		}
+		p.pos++
+		filter, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if token, ok := p.peek(); !ok || token.kind != filterClose {
+			return nil, NewValidationError("filter: missing \")\"")
+		}
+		p.pos++
+		return filter, nil
+	}
+	return p.comparison()
+}
+
+// comparison parses a field, an operator and a value of the field's kind
+func (p *filterParser) comparison() (*Filter, error) {
+	field, _ := p.peek()
+	kind, ok := p.fields[field.text]
+	if field.kind != filterWord || !ok {
+		return nil, NewValidationError("filter: unknown field %q, expected one of %s", field.text, p.fieldNames())
+	}
+	p.pos++
+
+	token, ok := p.peek()
+	if !ok || token.kind != filterOperator {
+		return nil, NewValidationError("filter: expected an operator after %s", field.text)
+	}
+	operator := FilterOperator(token.text)
+	if operator == FilterContains && kind != FilterString {
+		return nil, NewValidationError("filter: ~ only applies to text fields, not %s", field.text)
+	}
+	p.pos++
+
+	token, ok = p.peek()
+	if !ok || (token.kind != filterWord && token.kind != filterQuoted) {
+		return nil, NewValidationError("filter: expected a value after %s %s", field.text, operator)
+	}
+	p.pos++
+
+	var value any
+	switch kind {
+	case FilterNumber:
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, NewValidationError("filter: %s must be compared with a number, not %q", field.text, token.text)
+		}
+		value = number
+	case FilterTime:
+		at, err := parseFilterTime(token.text, p.loc)
+		if err != nil {
+			return nil, NewValidationError("filter: %s must be compared with an RFC 3339 time or a date, not %q", field.text, token.text)
+		}
+		value = at
+	default:
+		value = token.text
+	}
+
+	p.terms++
+	if p.terms > MaxFilterTerms {
+		return nil, NewValidationError("filter must have at most %d comparisons", MaxFilterTerms)
+	}
+	return &Filter{Field: field.text, Operator: operator, Value: value}, nil
+}
+
+// fieldNames lists the fields of the parser, sorted
+func (p *filterParser) fieldNames() string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// parseFilterTime reads an RFC 3339 time or a date, as midnight in loc
+func parseFilterTime(value string, loc *time.Location) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at.UTC(), nil
+	}
+	at, err := time.ParseInLocation(time.DateOnly, value, loc)
+	return at.UTC(), err
+}
+
+// Matches reports whether the filter selects a record, given the value of
+// each field of the record: a string, an integer, a float64, a *float64 or
+// a time.Time. As in SQL, nil values satisfy no comparison.
+func (f *Filter) Matches(value func(field string) any) bool {
+	if f == nil {
+		return true
+	}
+	switch f.Logic {
+	case "AND":
+		for _, term := range f.Terms {
+			if !term.Matches(value) {
+				return false
+			}
+		}
+		return true
+	case "OR":
+		for _, term := range f.Terms {
+			if term.Matches(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var order int
+	switch want := f.Value.(type) {
+	case float64:
+		var got float64
+		switch v := value(f.Field).(type) {
+		case int64:
+			got = float64(v)
+		case float64:
+			got = v
+		case *float64:
+			if v == nil {
+				return false
+			}
+			got = *v
+		default:
+			return false
+		}
+		order = cmp.Compare(got, want)
+	case time.Time:
+		got, ok := value(f.Field).(time.Time)
+		if !ok {
+			return false
+		}
+		order = got.Compare(want)
+	case string:
+		got, ok := value(f.Field).(string)
+		if !ok {
+			return false
+		}
+		if f.Operator == FilterContains {
+			return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+		}
+		order = strings.Compare(got, want)
+	default:
+		return false
+	}
+
+	switch f.Operator {
+	case FilterEqual:
+		return order == 0
+	case FilterNotEqual:
+		return order != 0
+	case FilterLess:
+		return order < 0
+	case FilterLessEqual:
+		return order <= 0
+	case FilterGreater:
+		return order > 0
+	case FilterGreaterEqual:
+		return order >= 0
+	}
+	return false
+}
+
+// FilterValue returns the value of a field of ProductFilterFields
+func (p *Product) FilterValue(field string) any {
+	switch field {
+	case "name":
+		return p.Name
+	case "sku":
+		return p.SKU
+	case "category":
+		return p.Category
+	case "price":
+		return p.Price
+	case "created_at":
+		return p.CreatedAt
+	case "updated_at":
+		return p.UpdatedAt
+	}
+	return nil
+}
+
+// FilterValue returns the value of a field of InventoryFilterFields
+func (i *InventoryItem) FilterValue(field string) any {
+	switch field {
+	case "product_id":
+		return i.ProductID
+	case "warehouse_id":
+		return i.WarehouseID
+	case "location":
+		return i.Location
+	case "condition":
+		return string(i.Condition)
+	case "bin_location":
+		return i.BinLocation
+	case "quantity":
+		return i.Quantity
+	case "reserved":
+		return i.Reserved
+	case "available":
+		return i.AvailableQuantity()
+	case "reorder_level":
+		return i.ReorderLevel
+	case "safety_stock":
+		return i.SafetyStock
+	case "updated_at":
+		return i.UpdatedAt
+	}
+	return nil
+}
+
+// FilterValue returns the value of a field of TransactionFilterFields
+func (t *Transaction) FilterValue(field string) any {
+	switch field {
+	case "product_id":
+		return t.ProductID
+	case "inventory_id":
+		return t.InventoryID
+	case "type":
+		return t.Type
+	case "reference":
+		return t.Reference
+	case "reason_code":
+		return string(t.ReasonCode)
+	case "performed_by":
+		return t.PerformedBy
+	case "quantity":
+		return t.Quantity
+	case "unit_cost":
+		return t.UnitCost
+	case "created_at":
+		return t.CreatedAt
+	}
+	return nil
+}
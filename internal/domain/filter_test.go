@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{name: "Empty", expression: "  "},
+		{name: "Comparison", expression: "price > 100"},
+		{name: "Quoted values", expression: `name = "Blue \"Desk\"" AND category != 'office chairs'`},
+		{name: "Lower case keywords and groups", expression: "(price <= 10 or price >= 100) and sku ~ lap"},
+		{name: "Times and dates", expression: "created_at >= 2026-01-01 AND updated_at < 2026-02-01T12:00:00+01:00"},
+		{name: "Unknown field", expression: "cost > 1", wantErr: true},
+		{name: "Missing value", expression: "price >", wantErr: true},
+		{name: "Missing operator", expression: "price 100", wantErr: true},
+		{name: "Not a number", expression: "price > cheap", wantErr: true},
+		{name: "Not a time", expression: "created_at > yesterday", wantErr: true},
+		{name: "Contains on a number", expression: "price ~ 1", wantErr: true},
+		{name: "Unterminated string", expression: `name = "desk`, wantErr: true},
+		{name: "Unbalanced parentheses", expression: "(price > 1", wantErr: true},
+		{name: "Trailing tokens", expression: "price > 1 price < 2", wantErr: true},
+		{name: "Dangling keyword", expression: "price > 1 AND", wantErr: true},
+		{name: "Bare bang", expression: "price ! 1", wantErr: true},
+		{name: "Too deep", expression: "((((((price > 1))))))", wantErr: true},
+		{name: "Too many comparisons", expression: strings.Repeat("price > 1 OR ", MaxFilterTerms) + "price > 1", wantErr: true},
+		{name: "Too long", expression: "name = " + strings.Repeat("a", MaxFilterLength), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.expression, ProductFilterFields, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	cost := 4.5
+	transaction := &Transaction{
+		Type:      "IN",
+		Quantity:  12,
+		Reference: "PO-1001",
+		UnitCost:  &cost,
+		CreatedAt: time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"type = IN", true},
+		{"type = in", false},
+		{"reference ~ po-10", true},
+		{"quantity >= 12 AND unit_cost < 5", true},
+		{"quantity > 12 OR type = OUT AND quantity > 0", false},
+		{"(quantity > 12 OR type = IN) AND quantity > 0", true},
+		{"type = OUT OR type = IN AND quantity = 12", true},
+		{"created_at >= 2026-03-15 AND created_at < 2026-03-16", true},
+		{"created_at < 2026-03-15T09:00:00Z", false},
+		{"reason_code != DAMAGE", true},
+	}
+	for _, tt := range tests {
+		filter, err := ParseFilter(tt.expression, TransactionFilterFields, time.UTC)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) error = %v", tt.expression, err)
+		}
+		if got := filter.Matches(transaction.FilterValue); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.expression, got, tt.want)
+		}
+	}
+
+	// As in SQL, a missing unit cost satisfies no comparison
+	transaction.UnitCost = nil
+	for _, expression := range []string{"unit_cost < 5", "unit_cost != 5"} {
+		filter, _ := ParseFilter(expression, TransactionFilterFields, nil)
+		if filter.Matches(transaction.FilterValue) {
+			t.Errorf("Expected %q not to match a transaction without a unit cost", expression)
+		}
+	}
+
+	var none *Filter
+	if !none.Matches(transaction.FilterValue) {
+		t.Error("Expected a nil filter to match everything")
+	}
+}
+
+func TestParseFilterDatesInLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+	// 23:30 UTC on March 14 is already March 15 in Berlin
+	transaction := &Transaction{CreatedAt: time.Date(2026, 3, 14, 23, 30, 0, 0, time.UTC)}
+
+	filter, err := ParseFilter("created_at >= 2026-03-15", TransactionFilterFields, berlin)
+	if err != nil {
+		t.Fatalf("ParseFilter() error = %v", err)
+	}
+	if !filter.Matches(transaction.FilterValue) {
+		t.Error("Expected the date to start at midnight in the reporting timezone")
+	}
+	if got, want := filter.Value, time.Date(2026, 3, 14, 23, 0, 0, 0, time.UTC); got != want {
+		t.Errorf("Value = %v, want %v", got, want)
+	}
+
+	filter, _ = ParseFilter("created_at >= 2026-03-15T00:00:00Z", TransactionFilterFields, berlin)
+	if filter.Matches(transaction.FilterValue) {
+		t.Error("Expected an RFC 3339 time to keep its own offset")
+	}
+}
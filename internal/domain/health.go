@@ -0,0 +1,28 @@
+package domain
+
+// Readiness statuses
+const (
+	// ReadinessReady means every dependency is up
+	ReadinessReady = "ready"
+	// ReadinessDegraded means only non-critical dependencies are down, e.g.
+	// the read replica, whose reads fall back to the primary
+	ReadinessDegraded = "degraded"
+	// ReadinessNotReady means a critical dependency is down and the instance
+	// should not receive traffic
+	ReadinessNotReady = "not_ready"
+)
+
+// DependencyStatus is the outcome of probing one dependency
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Up        bool    `json:"up"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Readiness is the outcome of probing every dependency of the instance
+type Readiness struct {
+	Status       string              `json:"status"`
+	Dependencies []*DependencyStatus `json:"dependencies"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a request made with an
+// Idempotency-Key so that retries of the same request can be answered without
+// executing it again
+type IdempotencyRecord struct {
+	Key         string     `json:"key"`
+	RequestHash string     `json:"request_hash"`
+	StatusCode  int        `json:"status_code"`
+	Response    []byte     `json:"response"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Completed reports whether the original request has finished and its
+// response was stored
+func (r *IdempotencyRecord) Completed() bool {
+	return r.CompletedAt != nil
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// IntercompanyTransfer is the document of stock sold by one tenant (legal
+// entity) to another that carries the product under the same SKU. The
+// sender's OUT and the receiver's IN transactions both have the transfer ID
+// as reference; the transfer records what the receiver owes the sender.
+type IntercompanyTransfer struct {
+	ID               string    `json:"id"`
+	FromTenant       string    `json:"from_tenant"`
+	ToTenant         string    `json:"to_tenant"`
+	SKU              string    `json:"sku"`
+	FromProductID    string    `json:"from_product_id"`
+	ToProductID      string    `json:"to_product_id"`
+	FromLocation     string    `json:"from_location"`
+	ToLocation       string    `json:"to_location"`
+	Quantity         int64     `json:"quantity"`
+	UnitCost         float64   `json:"unit_cost"`
+	TotalCost        float64   `json:"total_cost"`
+	Reference        string    `json:"reference,omitempty"`
+	OutTransactionID string    `json:"out_transaction_id"`
+	InTransactionID  string    `json:"in_transaction_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// IntercompanyTransferRequest asks to transfer stock of a product of the
+// requesting tenant to another tenant. An empty from_location selects the
+// product's default location, an empty to_location the same location as
+// the sender, and a missing unit_cost the sender's product price.
+type IntercompanyTransferRequest struct {
+	ToTenant     string   `json:"to_tenant"`
+	ProductID    string   `json:"product_id"`
+	FromLocation string   `json:"from_location"`
+	ToLocation   string   `json:"to_location"`
+	Quantity     int64    `json:"quantity"`
+	UnitCost     *float64 `json:"unit_cost"`
+	Reference    string   `json:"reference"`
+}
+
+// Validate checks if the transfer request is valid
+func (r *IntercompanyTransferRequest) Validate() error {
+	if r.ToTenant == "" {
+		return NewValidationError("to_tenant cannot be empty")
+	}
+	if err := ValidateTenantID(r.ToTenant); err != nil {
+		return err
+	}
+	if r.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if r.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if r.UnitCost != nil && *r.UnitCost < 0 {
+		return NewValidationError("unit_cost cannot be negative")
+	}
+	return nil
+}
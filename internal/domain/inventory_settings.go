@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PatchOperation is one operation of a JSON Patch (RFC 6902) document
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+	From  string          `json:"from,omitempty"`
+}
+
+// InventorySettingRoles lists the inventory settings a JSON Patch may change
+// and the role each one requires. Quantities are not settings; they only
+// change through stock operations.
+var InventorySettingRoles = map[string]Role{
+	"/bin_location":  RoleOperator,
+	"/reorder_level": RoleAdmin,
+	"/safety_stock":  RoleAdmin,
+}
+
+// ApplySettingsPatch applies a JSON Patch to the settings of the inventory
+// item as the given role. Operations apply in order and the patch is all or
+// nothing: on any error the item is left unchanged. Supported operations are
+// add and replace (which are the same for settings), remove (which resets a
+// setting) and test.
+func (i *InventoryItem) ApplySettingsPatch(operations []PatchOperation, role Role) error {
+	if len(operations) == 0 {
+		return NewValidationError("patch must contain at least one operation")
+	}
+
+	patched := *i
+	for n, operation := range operations {
+		required, ok := InventorySettingRoles[operation.Path]
+		if !ok {
+			return NewValidationError("operation %d: path %q is not a patchable setting", n+1, operation.Path)
+		}
+		if operation.Op != "test" && !role.Allows(required) {
+			return fmt.Errorf("%w: operation %d: %s requires the %s role", ErrForbidden, n+1, operation.Path, required)
+		}
+
+		if (operation.Op == "add" || operation.Op == "replace" || operation.Op == "test") && operation.Value == nil {
+			return NewValidationError("operation %d: %s requires a value", n+1, operation.Op)
+		}
+
+		switch operation.Op {
+		case "add", "replace":
+			if err := patched.setSetting(operation.Path, operation.Value); err != nil {
+				return NewValidationError("operation %d: %v", n+1, err)
+			}
+		case "remove":
+			patched.setSetting(operation.Path, nil)
+		case "test":
+			expected := patched
+			if err := expected.setSetting(operation.Path, operation.Value); err != nil {
+				return NewValidationError("operation %d: %v", n+1, err)
+			}
+			if expected != patched {
+				return fmt.Errorf("%w: operation %d: %s does not match", ErrPatchTestFailed, n+1, operation.Path)
+			}
+		default:
+			return NewValidationError("operation %d: unsupported op %q (supported: add, replace, remove, test)", n+1, operation.Op)
+		}
+	}
+
+	if err := patched.Validate(); err != nil {
+		return err
+	}
+	*i = patched
+	return nil
+}
+
+// setSetting sets a setting from its JSON value, or resets it for a nil value
+func (i *InventoryItem) setSetting(path string, value json.RawMessage) error {
+	if value == nil {
+		switch path {
+		case "/bin_location":
+			i.BinLocation = ""
+		case "/reorder_level":
+			i.ReorderLevel = 0
+		case "/safety_stock":
+			i.SafetyStock = 0
+		}
+		return nil
+	}
+
+	if string(value) == "null" {
+		return fmt.Errorf("%s cannot be null", path)
+	}
+
+	var err error
+	switch path {
+	case "/bin_location":
+		err = json.Unmarshal(value, &i.BinLocation)
+	case "/reorder_level":
+		err = json.Unmarshal(value, &i.ReorderLevel)
+	case "/safety_stock":
+		err = json.Unmarshal(value, &i.SafetyStock)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s", path)
+	}
+	return nil
+}
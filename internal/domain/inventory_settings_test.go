@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestApplySettingsPatch(t *testing.T) {
+	base := InventoryItem{ProductID: "prod-1", Quantity: 10, Location: "WH-A", ReorderLevel: 5, BinLocation: "A-01"}
+
+	tests := []struct {
+		name       string
+		operations []PatchOperation
+		role       Role
+		want       InventoryItem
+		wantErr    error
+	}{
+		{
+			name: "Replace and remove",
+			operations: []PatchOperation{
+				{Op: "replace", Path: "/reorder_level", Value: json.RawMessage(`8`)},
+				{Op: "add", Path: "/safety_stock", Value: json.RawMessage(`3`)},
+				{Op: "remove", Path: "/bin_location"},
+			},
+			role: RoleAdmin,
+			want: InventoryItem{ProductID: "prod-1", Quantity: 10, Location: "WH-A", ReorderLevel: 8, SafetyStock: 3},
+		},
+		{
+			name: "Passing test guards the change",
+			operations: []PatchOperation{
+				{Op: "test", Path: "/bin_location", Value: json.RawMessage(`"A-01"`)},
+				{Op: "replace", Path: "/bin_location", Value: json.RawMessage(`"B-07"`)},
+			},
+			role: RoleOperator,
+			want: InventoryItem{ProductID: "prod-1", Quantity: 10, Location: "WH-A", ReorderLevel: 5, BinLocation: "B-07"},
+		},
+		{
+			name: "Failing test",
+			operations: []PatchOperation{
+				{Op: "replace", Path: "/bin_location", Value: json.RawMessage(`"B-07"`)},
+				{Op: "test", Path: "/reorder_level", Value: json.RawMessage(`6`)},
+			},
+			role:    RoleOperator,
+			wantErr: ErrPatchTestFailed,
+		},
+		{
+			name:       "Operator cannot change planning settings",
+			operations: []PatchOperation{{Op: "replace", Path: "/reorder_level", Value: json.RawMessage(`8`)}},
+			role:       RoleOperator,
+			wantErr:    ErrForbidden,
+		},
+		{
+			name:       "Quantities are not settings",
+			operations: []PatchOperation{{Op: "replace", Path: "/quantity", Value: json.RawMessage(`100`)}},
+			role:       RoleAdmin,
+			wantErr:    ErrValidation,
+		},
+		{
+			name:       "Negative safety stock",
+			operations: []PatchOperation{{Op: "replace", Path: "/safety_stock", Value: json.RawMessage(`-1`)}},
+			role:       RoleAdmin,
+			wantErr:    ErrValidation,
+		},
+		{
+			name:       "Unsupported op",
+			operations: []PatchOperation{{Op: "move", Path: "/bin_location", From: "/location"}},
+			role:       RoleAdmin,
+			wantErr:    ErrValidation,
+		},
+		{
+			name:       "Missing value",
+			operations: []PatchOperation{{Op: "replace", Path: "/bin_location"}},
+			role:       RoleAdmin,
+			wantErr:    ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := base
+			err := item.ApplySettingsPatch(tt.operations, tt.role)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ApplySettingsPatch() error = %v, want %v", err, tt.wantErr)
+				}
+				if item != base {
+					t.Errorf("Expected the item unchanged after a failed patch, got %+v", item)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplySettingsPatch() error = %v", err)
+			}
+			if item != tt.want {
+				t.Errorf("ApplySettingsPatch() = %+v, want %+v", item, tt.want)
+			}
+		})
+	}
+}
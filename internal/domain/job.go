@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobType names the operation a job runs
+type JobType string
+
+const (
+	JobProductImport  JobType = "product_import"
+	JobCatalogSync    JobType = "catalog_sync"
+	JobAuditExport    JobType = "audit_export"
+	JobProductArchive JobType = "product_archive"
+)
+
+// JobStatus is the lifecycle state of a job
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "PENDING"
+	JobRunning   JobStatus = "RUNNING"
+	JobSucceeded JobStatus = "SUCCEEDED"
+	JobFailed    JobStatus = "FAILED"
+)
+
+// Finished reports whether a job in this status will not change anymore
+func (s JobStatus) Finished() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// JobProgress counts the units of work a job completed out of its total.
+// Total is 0 while unknown.
+type JobProgress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
+
+// Job is a long-running operation run in the background. Its result
+// document, if any, is downloaded from ResultLocation once it succeeded.
+type Job struct {
+	ID             string          `json:"id"`
+	Type           JobType         `json:"type"`
+	Status         JobStatus       `json:"status"`
+	Progress       JobProgress     `json:"progress"`
+	Summary        json.RawMessage `json:"summary,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	ResultLocation string          `json:"result_location,omitempty"`
+	HasResult      bool            `json:"-"`
+	CreatedBy      string          `json:"created_by,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+}
+
+// JobResult is what a job produces: a short summary shown with the job and
+// an optional document to download
+type JobResult struct {
+	Summary     any
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxLotNumberLength bounds the number of a lot
+const MaxLotNumberLength = 100
+
+// Lot is a batch of a product received into the new stock of a location,
+// identified by its lot number there, with the day its units expire. A lot
+// is expired on the days after ExpiresOn. Remaining counts the units of the
+// lot still in stock; removals take them from the lots expiring first.
+type Lot struct {
+	ID          string    `json:"id"`
+	InventoryID string    `json:"inventory_id"`
+	ProductID   string    `json:"product_id"`
+	Location    string    `json:"location"`
+	LotNumber   string    `json:"lot_number"`
+	ExpiresOn   time.Time `json:"expires_on"`
+	Quantity    int64     `json:"quantity"`
+	Remaining   int64     `json:"remaining"`
+	ReceivedAt  time.Time `json:"received_at"`
+}
+
+// Expired reports whether the lot expired before today, a UTC day
+func (l *Lot) Expired(today time.Time) bool {
+	return l.ExpiresOn.Before(today)
+}
+
+// LotRequest asks to receive stock into a lot at a location. Receiving into
+// a lot number the location already has adds the units to that lot, which
+// must then have the same expiry date.
+type LotRequest struct {
+	ProductID string   `json:"product_id"`
+	Location  string   `json:"location"`
+	LotNumber string   `json:"lot_number"`
+	ExpiresOn string   `json:"expires_on"` // YYYY-MM-DD
+	Quantity  int64    `json:"quantity"`
+	UnitCost  *float64 `json:"unit_cost,omitempty"`
+	Reference string   `json:"reference"`
+}
+
+// Validate checks if the lot request is valid
+func (r *LotRequest) Validate() error {
+	if r.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if r.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if strings.TrimSpace(r.LotNumber) == "" {
+		return NewValidationError("lot_number cannot be empty")
+	}
+	if len(r.LotNumber) > MaxLotNumberLength {
+		return NewValidationError("lot_number cannot be longer than %d characters", MaxLotNumberLength)
+	}
+	if _, err := r.Expiry(); err != nil {
+		return err
+	}
+	if r.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if r.UnitCost != nil && *r.UnitCost < 0 {
+		return NewValidationError("unit_cost cannot be negative")
+	}
+	return nil
+}
+
+// Expiry parses the expiry date of the request as a UTC day
+func (r *LotRequest) Expiry() (time.Time, error) {
+	if r.ExpiresOn == "" {
+		return time.Time{}, NewValidationError("expires_on cannot be empty")
+	}
+	expiresOn, err := time.Parse(time.DateOnly, r.ExpiresOn)
+	if err != nil {
+		return time.Time{}, NewValidationError("expires_on must be a date (YYYY-MM-DD)")
+	}
+	return expiresOn, nil
+}
+
+// LotFilter selects the lots of a product; zero fields do not filter
+type LotFilter struct {
+	Location string
+	// Open selects the lots with units still in stock
+	Open bool
+}
+
+// LotWriteOff is the units of an expired lot written off with an EXPIRED
+// transaction. Units of the lot that are reserved stay until released.
+type LotWriteOff struct {
+	LotID     string    `json:"lot_id"`
+	LotNumber string    `json:"lot_number"`
+	ProductID string    `json:"product_id"`
+	Location  string    `json:"location"`
+	ExpiresOn time.Time `json:"expires_on"`
+	Quantity  int64     `json:"quantity"`
+}
+
+// LotConsumption is the units a removal took from a lot
+type LotConsumption struct {
+	Lot      *Lot
+	Quantity int64
+}
+
+// ConsumeLots takes the units of a removal off the open lots of an inventory
+// row, first expiring first (FEFO). onHand is the quantity of the row before
+// the removal: the units it holds beyond the lots' remaining ones are not in
+// any lot and are taken after the unexpired lots. Units of lots that expired
+// before today are never taken, so a removal that would need them fails
+// with ErrInsufficientStock; they leave stock by being written off.
+func ConsumeLots(lots []*Lot, quantity, onHand int64, today time.Time) ([]LotConsumption, error) {
+	lots = slices.Clone(lots)
+	slices.SortFunc(lots, func(a, b *Lot) int {
+		return cmp.Or(a.ExpiresOn.Compare(b.ExpiresOn), a.ReceivedAt.Compare(b.ReceivedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	var expired int64
+	for _, lot := range lots {
+		if lot.Expired(today) {
+			expired += lot.Remaining
+		}
+	}
+	if onHand-expired < quantity {
+		return nil, fmt.Errorf("%w: %d units are in expired lots awaiting write-off", ErrInsufficientStock, expired)
+	}
+
+	// Units beyond the unexpired lots come out of the untracked ones, which
+	// the check above leaves enough of
+	var consumptions []LotConsumption
+	unassigned := quantity
+	for _, lot := range lots {
+		if unassigned == 0 {
+			break
+		}
+		if lot.Expired(today) || lot.Remaining <= 0 {
+			continue
+		}
+		taken := min(unassigned, lot.Remaining)
+		lot.Remaining -= taken
+		unassigned -= taken
+		consumptions = append(consumptions, LotConsumption{Lot: lot, Quantity: taken})
+	}
+	return consumptions, nil
+}
+
+// LotNotes describes the lots a movement took units from, for the notes of
+// its transaction
+func LotNotes(consumptions []LotConsumption) string {
+	parts := make([]string, len(consumptions))
+	for i, consumption := range consumptions {
+		parts[i] = fmt.Sprintf("%s (%d)", consumption.Lot.LotNumber, consumption.Quantity)
+	}
+	return "lots " + strings.Join(parts, ", ")
+}
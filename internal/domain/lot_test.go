@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLotRequestValidation(t *testing.T) {
+	valid := LotRequest{ProductID: "prod-1", Location: "WH-1", LotNumber: "L1", ExpiresOn: "2026-12-31", Quantity: 10}
+	tests := []struct {
+		name    string
+		modify  func(r *LotRequest)
+		wantErr bool
+	}{
+		{name: "Valid lot request", modify: func(r *LotRequest) {}},
+		{name: "Missing lot number", modify: func(r *LotRequest) { r.LotNumber = " " }, wantErr: true},
+		{name: "Missing expiry", modify: func(r *LotRequest) { r.ExpiresOn = "" }, wantErr: true},
+		{name: "Expiry with a time", modify: func(r *LotRequest) { r.ExpiresOn = "2026-12-31T00:00:00Z" }, wantErr: true},
+		{name: "Zero quantity", modify: func(r *LotRequest) { r.Quantity = 0 }, wantErr: true},
+		{name: "Negative unit cost", modify: func(r *LotRequest) { cost := -1.0; r.UnitCost = &cost }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConsumeLots(t *testing.T) {
+	today := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	lots := func() []*Lot {
+		return []*Lot{
+			{ID: "late", LotNumber: "L3", ExpiresOn: today.AddDate(0, 0, 30), Remaining: 5},
+			{ID: "soon", LotNumber: "L2", ExpiresOn: today, Remaining: 2},
+			{ID: "expired", LotNumber: "L1", ExpiresOn: today.AddDate(0, 0, -1), Remaining: 3},
+		}
+	}
+
+	// 10 on hand: 3 expired, 7 in open lots, none untracked
+	consumptions, err := ConsumeLots(lots(), 4, 10, today)
+	if err != nil {
+		t.Fatalf("ConsumeLots() error = %v", err)
+	}
+	if len(consumptions) != 2 || consumptions[0].Lot.ID != "soon" || consumptions[0].Quantity != 2 ||
+		consumptions[1].Lot.ID != "late" || consumptions[1].Quantity != 2 {
+		t.Errorf("Expected the lot expiring today taken first, got %+v", consumptions)
+	}
+	if consumptions[1].Lot.Remaining != 3 {
+		t.Errorf("Expected 3 units left in the late lot, got %d", consumptions[1].Lot.Remaining)
+	}
+	if notes := LotNotes(consumptions); notes != "lots L2 (2), L3 (2)" {
+		t.Errorf("Unexpected notes %q", notes)
+	}
+
+	// Units in no lot come after the unexpired lots
+	consumptions, err = ConsumeLots(lots(), 9, 12, today)
+	if err != nil {
+		t.Fatalf("ConsumeLots() error = %v", err)
+	}
+	if len(consumptions) != 2 || consumptions[0].Quantity+consumptions[1].Quantity != 7 {
+		t.Errorf("Expected both open lots emptied, got %+v", consumptions)
+	}
+
+	// The expired units are not taken
+	if _, err := ConsumeLots(lots(), 8, 10, today); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock when only expired units are left, got %v", err)
+	}
+	if consumptions, err := ConsumeLots(nil, 4, 4, today); err != nil || len(consumptions) != 0 {
+		t.Errorf("Expected removals without lots to take nothing from lots, got %+v, %v", consumptions, err)
+	}
+}
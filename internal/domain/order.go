@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// MaxOrderIDLength bounds the length of client-assigned order IDs
+const MaxOrderIDLength = 255
+
+// OrderStatus represents the lifecycle state of an order
+type OrderStatus string
+
+const (
+	// OrderStatusPending orders hold a reservation for each line
+	OrderStatusPending   OrderStatus = "PENDING"
+	OrderStatusConfirmed OrderStatus = "CONFIRMED"
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+)
+
+// OrderLine is the quantity of one product an order holds, and the
+// reservation holding it
+type OrderLine struct {
+	ProductID     string `json:"product_id"`
+	Location      string `json:"location"`
+	Quantity      int64  `json:"quantity"`
+	ReservationID string `json:"reservation_id"`
+}
+
+// Order reserves its lines together and is confirmed or cancelled as a
+// whole. Its ID, assigned by the client, is the reference of all its
+// reservations and transactions.
+type Order struct {
+	ID        string      `json:"id"`
+	TenantID  string      `json:"tenant_id"`
+	Status    OrderStatus `json:"status"`
+	Lines     []OrderLine `json:"lines"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// ValidateOrder checks an order creation request, whose lines are reserved
+// like those of an all-or-nothing multi-line reservation
+func ValidateOrder(id string, lines []OrderReservationLine) error {
+	if len(id) > MaxOrderIDLength {
+		return NewValidationError("id must be at most %d characters", MaxOrderIDLength)
+	}
+	if id == "" {
+		return NewValidationError("id cannot be empty")
+	}
+	return ValidateOrderReservation(id, lines, ReservationPolicyAllOrNothing)
+}
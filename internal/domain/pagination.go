@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// PageRequest selects one page of a listing. A non-empty Cursor continues a
+// keyset listing after the cursor and takes precedence over Offset.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+// Page is one page of a listing with the metadata to fetch the next one.
+// NextOffset and NextCursor are empty on the last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextOffset *int   `json:"next_offset,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TransactionCursor is the keyset position of a transaction in a listing
+// ordered by creation time and ID, newest first
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// NewTransactionCursor returns the cursor positioned at a transaction
+func NewTransactionCursor(transaction *Transaction) TransactionCursor {
+	return TransactionCursor{CreatedAt: transaction.CreatedAt, ID: transaction.ID}
+}
+
+// Encode returns the opaque string form of the cursor
+func (c TransactionCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTransactionCursor parses a cursor produced by Encode
+func DecodeTransactionCursor(value string) (TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return TransactionCursor{}, NewValidationError("invalid cursor")
+	}
+
+	timestamp, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return TransactionCursor{}, NewValidationError("invalid cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return TransactionCursor{}, NewValidationError("invalid cursor")
+	}
+
+	return TransactionCursor{CreatedAt: createdAt, ID: id}, nil
+}
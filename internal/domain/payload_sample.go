@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// PayloadSample is a full copy of a sampled mutating request and the response
+// it received, kept to investigate disputed stock changes
+type PayloadSample struct {
+	ID           string    `json:"id"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Query        string    `json:"query,omitempty"`
+	StatusCode   int       `json:"status_code"`
+	RequestBody  string    `json:"request_body"`
+	ResponseBody string    `json:"response_body"`
+	Truncated    bool      `json:"truncated"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// MaxSaleEventIDLength bounds the length of client-assigned sale event IDs
+const MaxSaleEventIDLength = 255
+
+// MaxSaleClockSkew is how far in the future of the server clock the event
+// time of a sale may lie
+const MaxSaleClockSkew = 5 * time.Minute
+
+// SaleStatus is the outcome of a store sale
+type SaleStatus string
+
+const (
+	// SaleStatusApplied sales were taken off the store's stock
+	SaleStatusApplied SaleStatus = "APPLIED"
+	// SaleStatusQueued sales arrived during a stocktake of the store and are
+	// taken off its stock when the stocktake closes
+	SaleStatusQueued SaleStatus = "QUEUED"
+	// SaleStatusShort sales found less stock on record than they sold, e.g.
+	// because a delivery was not booked yet; the stock is left unchanged
+	// for the store to reconcile
+	SaleStatusShort SaleStatus = "SHORT"
+)
+
+// Sale is a sale at a store's point of sale, decrementing the store's stock.
+// Tills queue sales while offline and deliver them late, possibly more than
+// once and out of order, so each carries the ID and time of the event: a
+// sale is applied once per event ID, and reports order sales by the time
+// they happened rather than by when they arrived.
+type Sale struct {
+	EventID    string     `json:"event_id"`
+	ProductID  string     `json:"product_id"`
+	SKU        string     `json:"sku,omitempty"`
+	Location   string     `json:"location"`
+	Quantity   int64      `json:"quantity"`
+	Status     SaleStatus `json:"status"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	ReceivedAt time.Time  `json:"received_at"`
+}
+
+// Validate checks a sale as delivered by a till at now. A missing quantity
+// is a single unit.
+func (s *Sale) Validate(now time.Time) error {
+	if s.EventID == "" {
+		return NewValidationError("event_id cannot be empty")
+	}
+	if len(s.EventID) > MaxSaleEventIDLength {
+		return NewValidationError("event_id must be at most %d characters", MaxSaleEventIDLength)
+	}
+	if s.ProductID == "" && s.SKU == "" {
+		return NewValidationError("product_id or sku is required")
+	}
+	if s.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if s.Quantity < 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if s.OccurredAt.IsZero() {
+		return NewValidationError("occurred_at is required")
+	}
+	if s.OccurredAt.After(now.Add(MaxSaleClockSkew)) {
+		return NewValidationError("occurred_at lies in the future")
+	}
+	return nil
+}
+
+// SaleFilter selects sales of a store within a half-open range of event
+// times; zero fields do not filter
+type SaleFilter struct {
+	Location string
+	From     time.Time
+	To       time.Time
+}
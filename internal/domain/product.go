@@ -1,44 +1,198 @@
 package domain
 
 import (
-	"errors"
+	"strings"
 	"time"
 )
 
 // Product represents a product in the inventory system
 type Product struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	SKU         string    `json:"sku"`
-	Price       float64   `json:"price"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID           string                        `json:"id"`
+	TenantID     string                        `json:"tenant_id"`
+	Name         string                        `json:"name"`
+	Description  string                        `json:"description"`
+	SKU          string                        `json:"sku"`
+	Category     string                        `json:"category"`
+	Price        float64                       `json:"price"`
+	Translations map[string]ProductTranslation `json:"translations,omitempty"`
+	CreatedAt    time.Time                     `json:"created_at"`
+	UpdatedAt    time.Time                     `json:"updated_at"`
+	// DeletedAt is when the product was archived. Archived products keep
+	// their inventory and transaction history but are left out of listings.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// ReleaseAt is when a scheduled drop of the product goes live. Until
+	// then its stock is hidden from availability and cannot be reserved.
+	ReleaseAt *time.Time `json:"release_at,omitempty"`
+
+	// Related resources, loaded only when requested with ProductIncludes
+	Inventory    []*InventoryItem `json:"inventory,omitempty"`
+	Transactions []*Transaction   `json:"transactions,omitempty"`
+}
+
+// IncludedTransactions is how many of its newest transactions are loaded
+// with each product when transactions are included
+const IncludedTransactions = 10
+
+// ProductIncludes selects the related resources loaded with products
+type ProductIncludes struct {
+	Inventory    bool
+	Transactions bool
+}
+
+// Any reports whether any related resource is included
+func (i ProductIncludes) Any() bool {
+	return i.Inventory || i.Transactions
+}
+
+// ParseProductIncludes parses a comma-separated list of related resources,
+// such as "inventory,transactions"
+func ParseProductIncludes(value string) (ProductIncludes, error) {
+	var includes ProductIncludes
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "inventory":
+			includes.Inventory = true
+		case "transactions":
+			includes.Transactions = true
+		default:
+			return ProductIncludes{}, NewValidationError("unknown include %q, expected inventory or transactions", strings.TrimSpace(name))
+		}
+	}
+	return includes, nil
+}
+
+// Archived reports whether the product was deleted
+func (p *Product) Archived() bool {
+	return p.DeletedAt != nil
+}
+
+// Released reports whether the product's stock is on sale at now, i.e. it
+// has no drop scheduled after now
+func (p *Product) Released(now time.Time) bool {
+	return p.ReleaseAt == nil || !now.Before(*p.ReleaseAt)
+}
+
+// ProductTranslation holds a localized name and description of a product
+type ProductTranslation struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Validate checks if the translation data is valid
+func (t *ProductTranslation) Validate() error {
+	if t.Name == "" && t.Description == "" {
+		return NewValidationError("translation must set a name or description")
+	}
+	return nil
+}
+
+// Localize replaces the name and description with the translation for locale,
+// if one exists. Fields missing from the translation keep their default value.
+func (p *Product) Localize(locale string) {
+	translation, ok := p.Translations[locale]
+	if !ok {
+		return
+	}
+	if translation.Name != "" {
+		p.Name = translation.Name
+	}
+	if translation.Description != "" {
+		p.Description = translation.Description
+	}
 }
 
 // Validate checks if the product data is valid
 func (p *Product) Validate() error {
 	if p.Name == "" {
-		return errors.New("product name cannot be empty")
+		return NewValidationError("product name cannot be empty")
 	}
 	if p.SKU == "" {
-		return errors.New("product SKU cannot be empty")
+		return NewValidationError("product SKU cannot be empty")
 	}
 	if p.Price < 0 {
-		return errors.New("product price cannot be negative")
+		return NewValidationError("product price cannot be negative")
 	}
 	return nil
 }
 
-// InventoryItem represents the stock level for a product
+// ProductPatch is a partial update of a product: nil fields are left
+// unchanged
+type ProductPatch struct {
+	Name        *string
+	Description *string
+	Category    *string
+	Price       *float64
+}
+
+// Empty reports whether the patch changes nothing
+func (p ProductPatch) Empty() bool {
+	return p.Name == nil && p.Description == nil && p.Category == nil && p.Price == nil
+}
+
+// Apply sets the fields of the patch on the product
+func (p ProductPatch) Apply(product *Product) {
+	if p.Name != nil {
+		product.Name = *p.Name
+	}
+	if p.Description != nil {
+		product.Description = *p.Description
+	}
+	if p.Category != nil {
+		product.Category = *p.Category
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+}
+
+// StockCondition is the condition of the units of an inventory item
+type StockCondition string
+
+const (
+	ConditionNew         StockCondition = "NEW"
+	ConditionRefurbished StockCondition = "REFURBISHED"
+	ConditionGradeB      StockCondition = "GRADE_B"
+)
+
+// ParseStockCondition parses a condition case-insensitively. An empty value
+// is new stock.
+func ParseStockCondition(value string) (StockCondition, error) {
+	switch condition := StockCondition(strings.ToUpper(strings.TrimSpace(value))); condition {
+	case "":
+		return ConditionNew, nil
+	case ConditionNew, ConditionRefurbished, ConditionGradeB:
+		return condition, nil
+	default:
+		return "", NewValidationError("unknown condition %q (supported: NEW, REFURBISHED, GRADE_B)", value)
+	}
+}
+
+// InventoryItem represents the stock level for a product at one location in
+// one condition. Location is the code of the warehouse identified by
+// WarehouseID.
 type InventoryItem struct {
-	ID        string    `json:"id"`
-	ProductID string    `json:"product_id"`
-	Quantity  int64     `json:"quantity"`
-	Reserved  int64     `json:"reserved"`
-	Location  string    `json:"location"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	ProductID   string    `json:"product_id"`
+	WarehouseID string    `json:"warehouse_id,omitempty"`
+	Quantity    int64     `json:"quantity"`
+	Reserved    int64     `json:"reserved"`
+	Location    string    `json:"location"`
+	Version     int64     `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Condition defaults to ConditionNew
+	Condition StockCondition `json:"condition"`
+
+	// Settings changed with a JSON Patch, see InventorySettingRoles
+	ReorderLevel int64  `json:"reorder_level"`
+	SafetyStock  int64  `json:"safety_stock"`
+	BinLocation  string `json:"bin_location,omitempty"`
+
+	// ProjectedStockoutAt is computed from recent sales, not stored
+	ProjectedStockoutAt *time.Time `json:"projected_stockout_at,omitempty"`
 }
 
 // AvailableQuantity returns the available (non-reserved) quantity
@@ -53,19 +207,30 @@ func (i *InventoryItem) AvailableQuantity() int64 {
 // Validate checks if the inventory item data is valid
 func (i *InventoryItem) Validate() error {
 	if i.ProductID == "" {
-		return errors.New("product_id cannot be empty")
+		return NewValidationError("product_id cannot be empty")
 	}
 	if i.Quantity < 0 {
-		return errors.New("quantity cannot be negative")
+		return NewValidationError("quantity cannot be negative")
 	}
 	if i.Reserved < 0 {
-		return errors.New("reserved quantity cannot be negative")
+		return NewValidationError("reserved quantity cannot be negative")
 	}
 	if i.Reserved > i.Quantity {
-		return errors.New("reserved quantity cannot exceed total quantity")
+		return NewValidationError("reserved quantity cannot exceed total quantity")
 	}
 	if i.Location == "" {
-		return errors.New("location cannot be empty")
+		return NewValidationError("location cannot be empty")
+	}
+	switch i.Condition {
+	case "", ConditionNew, ConditionRefurbished, ConditionGradeB:
+	default:
+		return NewValidationError("unknown condition %q", i.Condition)
+	}
+	if i.ReorderLevel < 0 {
+		return NewValidationError("reorder level cannot be negative")
+	}
+	if i.SafetyStock < 0 {
+		return NewValidationError("safety stock cannot be negative")
 	}
 	return nil
 }
@@ -75,33 +240,95 @@ type Transaction struct {
 	ID          string    `json:"id"`
 	InventoryID string    `json:"inventory_id"`
 	ProductID   string    `json:"product_id"`
-	Type        string    `json:"type"` // "IN", "OUT", "RETURN", "RESERVE", "UNRESERVE"
+	Type        string    `json:"type"` // "IN", "OUT", "RETURN", "RESERVE", "UNRESERVE", "TRANSFER_OUT", "TRANSFER_IN", "ADJUSTMENT", "EXPIRED"
 	Quantity    int64     `json:"quantity"`
 	Reference   string    `json:"reference"` // e.g., order ID, return ID
 	Notes       string    `json:"notes"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	// Sequence numbers the product's transactions 1, 2, 3, ... in the order
+	// they were committed, without gaps
+	Sequence int64 `json:"sequence"`
+
+	// Set on ADJUSTMENT transactions only, whose Quantity is the size of the
+	// correction in either direction
+	QuantityBefore *int64           `json:"quantity_before,omitempty"`
+	QuantityAfter  *int64           `json:"quantity_after,omitempty"`
+	ReasonCode     AdjustmentReason `json:"reason_code,omitempty"`
+	PerformedBy    string           `json:"performed_by,omitempty"`
+
+	// UnitCost is the cost of one unit received, when known. It is set on IN
+	// and RETURN transactions only and values the stock they brought in.
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+
+	// Annotations are the notes appended after the fact, oldest first. They
+	// are listed with the ledger and never change the transaction itself.
+	Annotations []*TransactionAnnotation `json:"annotations,omitempty"`
 }
 
 // Validate checks if the transaction data is valid
 func (t *Transaction) Validate() error {
 	if t.InventoryID == "" {
-		return errors.New("inventory_id cannot be empty")
+		return NewValidationError("inventory_id cannot be empty")
 	}
 	if t.ProductID == "" {
-		return errors.New("product_id cannot be empty")
+		return NewValidationError("product_id cannot be empty")
+	}
+	if t.Type == "ADJUSTMENT" {
+		return t.validateAdjustment()
 	}
 	if t.Quantity <= 0 {
-		return errors.New("quantity must be positive")
+		return NewValidationError("quantity must be positive")
+	}
+	if !IsTransactionType(t.Type) {
+		return NewValidationError("invalid transaction type")
+	}
+	if t.UnitCost != nil {
+		if t.Type != "IN" && t.Type != "RETURN" {
+			return NewValidationError("unit cost can only be recorded on received stock")
+		}
+		if *t.UnitCost < 0 {
+			return NewValidationError("unit cost cannot be negative")
+		}
+	}
+	return nil
+}
+
+// transactionTypes lists the types of the transaction ledger
+var transactionTypes = map[string]bool{
+	"IN":           true,
+	"OUT":          true,
+	"RETURN":       true,
+	"RESERVE":      true,
+	"UNRESERVE":    true,
+	"TRANSFER_OUT": true,
+	"TRANSFER_IN":  true,
+	"ADJUSTMENT":   true,
+	"EXPIRED":      true,
+}
+
+// IsTransactionType reports whether t is a known transaction type
+func IsTransactionType(t string) bool {
+	return transactionTypes[t]
+}
+
+// validateAdjustment checks an ADJUSTMENT transaction. A count that confirms
+// the quantity on record is recorded with a zero quantity.
+func (t *Transaction) validateAdjustment() error {
+	if t.QuantityBefore == nil || t.QuantityAfter == nil {
+		return NewValidationError("adjustment must record the quantity before and after")
+	}
+	if *t.QuantityAfter < 0 {
+		return NewValidationError("quantity cannot be negative")
+	}
+	if delta := *t.QuantityAfter - *t.QuantityBefore; t.Quantity != max(delta, -delta) {
+		return NewValidationError("adjustment quantity must be the difference between before and after")
 	}
-	validTypes := map[string]bool{
-		"IN":        true,
-		"OUT":       true,
-		"RETURN":    true,
-		"RESERVE":   true,
-		"UNRESERVE": true,
+	if _, err := ParseAdjustmentReason(string(t.ReasonCode)); err != nil {
+		return err
 	}
-	if !validTypes[t.Type] {
-		return errors.New("invalid transaction type")
+	if t.PerformedBy == "" {
+		return NewValidationError("adjustment must record who performed it")
 	}
 	return nil
 }
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// MaxArchiveInactiveDays bounds the inactivity window of a bulk archive
+const MaxArchiveInactiveDays = 3650
+
+// ProductArchiveFilter selects the active products a bulk archive applies
+// to. Every criterion set must hold, and at least one must be set.
+// InactiveDays selects products without any stock movement in that many days
+// that were created before them; ZeroStock products without stock on hand.
+// Products holding reservations are never selected.
+type ProductArchiveFilter struct {
+	Category     string `json:"category,omitempty"`
+	InactiveDays int    `json:"inactive_days,omitempty"`
+	ZeroStock    bool   `json:"zero_stock,omitempty"`
+}
+
+// Validate validates the filter
+func (f *ProductArchiveFilter) Validate() error {
+	if f.Category == "" && f.InactiveDays == 0 && !f.ZeroStock {
+		return NewValidationError("at least one of category, inactive_days and zero_stock is required")
+	}
+	if f.InactiveDays < 0 || f.InactiveDays > MaxArchiveInactiveDays {
+		return NewValidationError("inactive_days must be between 1 and %d", MaxArchiveInactiveDays)
+	}
+	return nil
+}
+
+// ArchiveCandidate is an active product a bulk archive filter selects
+type ArchiveCandidate struct {
+	ProductID      string     `json:"product_id"`
+	SKU            string     `json:"sku"`
+	Name           string     `json:"name"`
+	Category       string     `json:"category"`
+	Quantity       int64      `json:"quantity"`
+	LastMovementAt *time.Time `json:"last_movement_at,omitempty"`
+}
+
+// ProductArchivePreview lists the products a bulk archive would archive.
+// Archiving them requires Total as the expected count.
+type ProductArchivePreview struct {
+	Filter   ProductArchiveFilter `json:"filter"`
+	Total    int                  `json:"total"`
+	Products []*ArchiveCandidate  `json:"products"`
+}
+
+// ProductArchiveResult is the outcome of a bulk archive. Skipped products
+// stopped matching the filter between the preview and their batch, e.g.
+// because they were stocked or reserved meanwhile, and were left active.
+type ProductArchiveResult struct {
+	Matched  int      `json:"matched"`
+	Archived []string `json:"archived"`
+	Skipped  []string `json:"skipped"`
+}
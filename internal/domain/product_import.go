@@ -0,0 +1,63 @@
+package domain
+
+// ProductImportRow is one product of a bulk catalog import together with its
+// initial stock at a location
+type ProductImportRow struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	SKU         string  `json:"sku"`
+	Category    string  `json:"category"`
+	Price       float64 `json:"price"`
+	Location    string  `json:"location"`
+	Quantity    int64   `json:"quantity"`
+}
+
+// Product returns the product described by the row
+func (r *ProductImportRow) Product() *Product {
+	return &Product{
+		Name:        r.Name,
+		Description: r.Description,
+		SKU:         r.SKU,
+		Category:    r.Category,
+		Price:       r.Price,
+	}
+}
+
+// Validate checks if the import row is valid
+func (r *ProductImportRow) Validate() error {
+	if err := r.Product().Validate(); err != nil {
+		return err
+	}
+	if r.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if r.Quantity < 0 {
+		return NewValidationError("quantity cannot be negative")
+	}
+	return nil
+}
+
+// ProductImport is a validated row ready to be written: the product, its
+// inventory item and, for non-zero stock, the initial stock transaction
+type ProductImport struct {
+	Product     *Product
+	Inventory   *InventoryItem
+	Transaction *Transaction
+}
+
+// ProductImportResult reports the outcome of one import row. Row numbers start
+// at 1 for the first product.
+type ProductImportResult struct {
+	Row       int    `json:"row"`
+	SKU       string `json:"sku"`
+	ProductID string `json:"product_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ProductImportReport summarizes a bulk import
+type ProductImportReport struct {
+	Total    int                   `json:"total"`
+	Imported int                   `json:"imported"`
+	Failed   int                   `json:"failed"`
+	Results  []ProductImportResult `json:"results"`
+}
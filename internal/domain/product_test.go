@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -219,6 +221,27 @@ func TestInventoryItemValidation(t *testing.T) {
 	}
 }
 
+func TestParseStockCondition(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    StockCondition
+		wantErr bool
+	}{
+		{"", ConditionNew, false},
+		{"NEW", ConditionNew, false},
+		{"refurbished", ConditionRefurbished, false},
+		{" Grade_B ", ConditionGradeB, false},
+		{"used", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStockCondition(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseStockCondition(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
 func TestTransactionValidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -351,3 +374,102 @@ func TestTransactionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestProductLocalize(t *testing.T) {
+	product := &Product{
+		Name:        "Laptop",
+		Description: "Gaming Laptop",
+		Translations: map[string]ProductTranslation{
+			"de": {Name: "Notebook", Description: "Gaming-Notebook"},
+			"fr": {Name: "Ordinateur portable"},
+		},
+	}
+
+	product.Localize("es")
+	if product.Name != "Laptop" {
+		t.Errorf("Expected untranslated name, got %q", product.Name)
+	}
+
+	product.Localize("fr")
+	if product.Name != "Ordinateur portable" || product.Description != "Gaming Laptop" {
+		t.Errorf("Expected partial translation, got %q / %q", product.Name, product.Description)
+	}
+
+	product.Localize("de")
+	if product.Name != "Notebook" || product.Description != "Gaming-Notebook" {
+		t.Errorf("Expected German translation, got %q / %q", product.Name, product.Description)
+	}
+}
+
+func TestNewStockLevel(t *testing.T) {
+	items := []*InventoryItem{
+		{ProductID: "prod-1", Location: "WH-A", Quantity: 10, Reserved: 4},
+		{ProductID: "prod-1", Location: "WH-B", Quantity: 5, Reserved: 0},
+		{ProductID: "prod-1", Location: "WH-C", Quantity: 2, Reserved: 3},
+	}
+
+	level := NewStockLevel("prod-1", items)
+
+	if level.Quantity != 17 {
+		t.Errorf("Expected quantity 17, got %d", level.Quantity)
+	}
+	if level.Reserved != 7 {
+		t.Errorf("Expected reserved 7, got %d", level.Reserved)
+	}
+	// Over-reserved locations contribute nothing rather than a negative amount
+	if level.Available != 11 {
+		t.Errorf("Expected available 11, got %d", level.Available)
+	}
+
+	if empty := NewStockLevel("prod-2", nil); empty.Locations == nil || empty.Available != 0 {
+		t.Errorf("Expected empty stock level, got %+v", empty)
+	}
+}
+
+func TestValidationErrorsMatchErrValidation(t *testing.T) {
+	product := &Product{SKU: "LAP001"}
+	err := product.Validate()
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected validation error to match ErrValidation, got %v", err)
+	}
+	if err.Error() != "product name cannot be empty" {
+		t.Errorf("Expected message to be kept, got %q", err.Error())
+	}
+
+	wrapped := fmt.Errorf("invalid product: %w", err)
+	if !errors.Is(wrapped, ErrValidation) || errors.Is(wrapped, ErrNotFound) {
+		t.Errorf("Expected wrapped error to match only ErrValidation")
+	}
+}
+
+func TestProjectStockout(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		available int64
+		outbound  int64
+		want      *time.Time
+	}{
+		{"Half the window", 15, 30, ptrTime(now.AddDate(0, 0, 15))},
+		{"Already out", 0, 30, ptrTime(now)},
+		{"No sales", 15, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ProjectStockout(tt.available, tt.outbound, window, now)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ProjectStockout() = %v, want %v", got, tt.want)
+			}
+			if got != nil && !got.Equal(*tt.want) {
+				t.Errorf("ProjectStockout() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ReconciliationDiscrepancy is an inventory row whose stored quantities
+// differ from what its transaction log adds up to
+type ReconciliationDiscrepancy struct {
+	InventoryID      string `json:"inventory_id"`
+	ProductID        string `json:"product_id"`
+	Location         string `json:"location"`
+	Condition        string `json:"condition"`
+	Quantity         int64  `json:"quantity"`
+	ExpectedQuantity int64  `json:"expected_quantity"`
+	Reserved         int64  `json:"reserved"`
+	ExpectedReserved int64  `json:"expected_reserved"`
+}
+
+// Matches reports whether the discrepancy is the same as another one of the
+// same row: both differ from their log by as much
+func (d *ReconciliationDiscrepancy) Matches(other *ReconciliationDiscrepancy) bool {
+	return d.InventoryID == other.InventoryID &&
+		d.Quantity-d.ExpectedQuantity == other.Quantity-other.ExpectedQuantity &&
+		d.Reserved-d.ExpectedReserved == other.Reserved-other.ExpectedReserved
+}
+
+// ReconciliationReport is the outcome of one stock reconciliation run.
+// DiscrepancyCount counts the discrepancies found; Discrepancies lists at
+// most a bounded number of them.
+type ReconciliationReport struct {
+	ID               string                       `json:"id"`
+	StartedAt        time.Time                    `json:"started_at"`
+	CompletedAt      time.Time                    `json:"completed_at"`
+	DiscrepancyCount int                          `json:"discrepancy_count"`
+	Discrepancies    []*ReconciliationDiscrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// MinRedactionIdentifierLength guards against identifiers so short that
+// redacting them would rewrite unrelated references
+const MinRedactionIdentifierLength = 4
+
+// Redaction records an erasure of personal data from the transaction ledger.
+// The erased identifier itself is never stored; only the token that replaced it.
+type Redaction struct {
+	ID                   string    `json:"id"`
+	Token                string    `json:"token"`
+	Reason               string    `json:"reason"`
+	TransactionsAffected int64     `json:"transactions_affected"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// Validate checks if the redaction data is valid
+func (r *Redaction) Validate() error {
+	if r.Token == "" {
+		return NewValidationError("redaction token cannot be empty")
+	}
+	if r.Reason == "" {
+		return NewValidationError("redaction reason cannot be empty")
+	}
+	return nil
+}
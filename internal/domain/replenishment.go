@@ -0,0 +1,116 @@
+package domain
+
+import "time"
+
+// MaxPurchaseOrderLines bounds the number of lines of one purchase order; a
+// receipt books all lines in one stock batch
+const MaxPurchaseOrderLines = 500
+
+// PurchaseOrderStatus represents the lifecycle state of a purchase order
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft     PurchaseOrderStatus = "DRAFT"
+	PurchaseOrderStatusReceived  PurchaseOrderStatus = "RECEIVED"
+	PurchaseOrderStatusCancelled PurchaseOrderStatus = "CANCELLED"
+)
+
+// PurchaseOrderLine is the quantity of one product ordered for one location.
+// Line numbers the lines of an order from 1; the supplier may confirm less
+// than was ordered and reports what it shipped in shipping notices.
+type PurchaseOrderLine struct {
+	Line              int    `json:"line,omitempty"`
+	ProductID         string `json:"product_id"`
+	InventoryID       string `json:"inventory_id"`
+	Location          string `json:"location"`
+	Quantity          int64  `json:"quantity"`
+	ConfirmedQuantity *int64 `json:"confirmed_quantity,omitempty"`
+	ShippedQuantity   int64  `json:"shipped_quantity"`
+}
+
+// Shippable returns how many units of the line may still be shipped: the
+// confirmed quantity, or the ordered one until the supplier confirmed, less
+// what was already shipped
+func (l PurchaseOrderLine) Shippable() int64 {
+	quantity := l.Quantity
+	if l.ConfirmedQuantity != nil {
+		quantity = *l.ConfirmedQuantity
+	}
+	return max(quantity-l.ShippedQuantity, 0)
+}
+
+// PurchaseOrder is stock ordered from a supplier. Drafts count as on order
+// in reorder suggestions and are open to the supplier, who may confirm them
+// with a delivery date and announce shipments; receiving one adds its lines
+// to stock with the order ID as transaction reference.
+type PurchaseOrder struct {
+	ID          string              `json:"id"`
+	Supplier    string              `json:"supplier"`
+	Notes       string              `json:"notes,omitempty"`
+	Status      PurchaseOrderStatus `json:"status"`
+	Lines       []PurchaseOrderLine `json:"lines"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	ReceivedAt  *time.Time          `json:"received_at,omitempty"`
+	ConfirmedAt *time.Time          `json:"confirmed_at,omitempty"`
+	PromisedAt  *time.Time          `json:"promised_delivery_at,omitempty"`
+}
+
+// line returns the line with the given number, or nil
+func (o *PurchaseOrder) line(number int) *PurchaseOrderLine {
+	if number < 1 || number > len(o.Lines) {
+		return nil
+	}
+	return &o.Lines[number-1]
+}
+
+// Validate checks if the purchase order data is valid
+func (o *PurchaseOrder) Validate() error {
+	if len(o.Lines) == 0 {
+		return NewValidationError("lines cannot be empty")
+	}
+	if len(o.Lines) > MaxPurchaseOrderLines {
+		return NewValidationError("a purchase order has at most %d lines", MaxPurchaseOrderLines)
+	}
+	for i, line := range o.Lines {
+		if line.ProductID == "" {
+			return NewValidationError("line %d: product_id cannot be empty", i+1)
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("line %d: quantity must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+// ReorderCandidate is an inventory row that may need replenishing: one with
+// a reorder level or with recent sales
+type ReorderCandidate struct {
+	ProductID    string
+	SKU          string
+	Name         string
+	InventoryID  string
+	Location     string
+	Available    int64
+	ReorderLevel int64
+	SafetyStock  int64
+	Outbound     int64
+	OnOrder      int64
+}
+
+// ReorderSuggestion is a suggested purchase for an inventory row whose stock
+// position, available plus on order, is at or below its reorder point
+type ReorderSuggestion struct {
+	ProductID     string  `json:"product_id"`
+	SKU           string  `json:"sku"`
+	Name          string  `json:"name"`
+	Location      string  `json:"location"`
+	Available     int64   `json:"available"`
+	OnOrder       int64   `json:"on_order"`
+	DailyVelocity float64 `json:"daily_velocity"`
+	// LeadTimeDays is the calendar days until stock ordered now arrives,
+	// the policy lead time counted in operating days of the location
+	LeadTimeDays int   `json:"lead_time_days"`
+	ReorderPoint int64 `json:"reorder_point"`
+	Quantity     int64 `json:"suggested_quantity"`
+}
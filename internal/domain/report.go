@@ -0,0 +1,120 @@
+package domain
+
+import "time"
+
+// StockSummary aggregates stock levels and value for one group of inventory
+type StockSummary struct {
+	Group        map[string]string `json:"group"`
+	ProductCount int64             `json:"product_count"`
+	OnHand       int64             `json:"on_hand"`
+	Reserved     int64             `json:"reserved"`
+	Available    int64             `json:"available"`
+	Value        float64           `json:"value"`
+}
+
+// MovementTotals sums the quantity and number of transactions of one type
+type MovementTotals struct {
+	Quantity int64 `json:"quantity"`
+	Count    int64 `json:"count"`
+}
+
+// MovementSummary holds the stock movements of one period, optionally for one product
+type MovementSummary struct {
+	Period    string                    `json:"period"`
+	ProductID string                    `json:"product_id,omitempty"`
+	Movements map[string]MovementTotals `json:"movements"`
+}
+
+// InventoryKPIs holds point-in-time business indicators across all products
+type InventoryKPIs struct {
+	Products         int64 `json:"products"`
+	ReservedUnits    int64 `json:"reserved_units"`
+	LowStockProducts int64 `json:"low_stock_products"`
+}
+
+// DashboardStats holds the headline figures of the dashboard. Stock counts
+// cover the products that are not archived; a product is out of stock when
+// nothing of it is available over all locations, and low on stock when some
+// but fewer than LowStockThreshold units are.
+type DashboardStats struct {
+	TotalProducts     int64          `json:"total_products"`
+	TotalUnits        int64          `json:"total_units"`
+	TotalReserved     int64          `json:"total_reserved"`
+	OutOfStock        int64          `json:"out_of_stock"`
+	LowStock          int64          `json:"low_stock"`
+	LowStockThreshold int64          `json:"low_stock_threshold"`
+	Transactions24h   MovementTotals `json:"transactions_24h"`
+	Transactions7d    MovementTotals `json:"transactions_7d"`
+	GeneratedAt       time.Time      `json:"generated_at"`
+}
+
+// ReservationHold sums the open reservations held under one reference
+type ReservationHold struct {
+	Reference    string    `json:"reference"`
+	Reservations int64     `json:"reservations"`
+	Quantity     int64     `json:"quantity"`
+	OldestAt     time.Time `json:"oldest_at"`
+}
+
+// ReservationAgeBucket groups the references whose oldest open reservation
+// falls into one age range of [MinAgeHours, MaxAgeHours). MaxAgeHours is 0 for
+// the last, open-ended bucket.
+type ReservationAgeBucket struct {
+	Bucket       string             `json:"bucket"`
+	MinAgeHours  int64              `json:"min_age_hours"`
+	MaxAgeHours  int64              `json:"max_age_hours,omitempty"`
+	Reservations int64              `json:"reservations"`
+	Quantity     int64              `json:"quantity"`
+	References   []*ReservationHold `json:"references"`
+}
+
+// StockSnapshot is the stock of a product as last captured on a day. Day is
+// the calendar date at midnight UTC.
+type StockSnapshot struct {
+	ProductID string    `json:"product_id"`
+	Day       time.Time `json:"day"`
+	OnHand    int64     `json:"on_hand"`
+	Available int64     `json:"available"`
+}
+
+// HistoricStock is the stock of a product at a past moment, reconstructed
+// from the ledger. SnapshotAt is the capture the transactions after it were
+// replayed onto; it is nil when no earlier snapshot existed and the
+// transactions since the moment were rewound from the current stock instead.
+type HistoricStock struct {
+	ProductID  string     `json:"product_id"`
+	SKU        string     `json:"sku"`
+	Name       string     `json:"name"`
+	Quantity   int64      `json:"quantity"`
+	Reserved   int64      `json:"reserved"`
+	Available  int64      `json:"available"`
+	SnapshotAt *time.Time `json:"snapshot_at,omitempty"`
+}
+
+// StockAsOfReport is the stock of every product that existed at AsOf, with
+// the totals over all of them
+type StockAsOfReport struct {
+	AsOf      time.Time        `json:"as_of"`
+	Quantity  int64            `json:"quantity"`
+	Reserved  int64            `json:"reserved"`
+	Available int64            `json:"available"`
+	Products  []*HistoricStock `json:"products"`
+}
+
+// StockTrendPoint is one day of a stock trend. Filled points had no snapshot
+// of their own and were derived by the trend's gap filling.
+type StockTrendPoint struct {
+	Date      string `json:"date"`
+	OnHand    int64  `json:"on_hand"`
+	Available int64  `json:"available"`
+	Filled    bool   `json:"filled,omitempty"`
+}
+
+// StockTrend is the daily on-hand and available series of a product
+type StockTrend struct {
+	ProductID string            `json:"product_id"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Fill      string            `json:"fill"`
+	Points    []StockTrendPoint `json:"points"`
+}
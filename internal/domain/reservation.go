@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReservationStatus represents the lifecycle state of a reservation
+type ReservationStatus string
+
+const (
+	ReservationStatusPending   ReservationStatus = "PENDING"
+	ReservationStatusConfirmed ReservationStatus = "CONFIRMED"
+	ReservationStatusReleased  ReservationStatus = "RELEASED"
+	ReservationStatusExpired   ReservationStatus = "EXPIRED"
+)
+
+// IsFinal reports whether no further transitions are possible from the status
+func (s ReservationStatus) IsFinal() bool {
+	return s != ReservationStatusPending
+}
+
+// Reservation is stock held for one order until it is confirmed, released or
+// expires. While pending, its quantity is counted in the inventory item's
+// reserved quantity. ParentID links the children of a reservation split
+// across locations.
+type Reservation struct {
+	ID          string            `json:"id"`
+	TenantID    string            `json:"tenant_id"`
+	ParentID    string            `json:"parent_id,omitempty"`
+	ProductID   string            `json:"product_id"`
+	InventoryID string            `json:"inventory_id"`
+	Location    string            `json:"location"`
+	Quantity    int64             `json:"quantity"`
+	Reference   string            `json:"reference"`
+	ChannelID   string            `json:"channel_id,omitempty"`
+	Status      ReservationStatus `json:"status"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Validate checks if the reservation data is valid
+func (r *Reservation) Validate() error {
+	if r.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if r.InventoryID == "" {
+		return NewValidationError("inventory_id cannot be empty")
+	}
+	if r.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if r.Reference == "" {
+		return NewValidationError("reference cannot be empty")
+	}
+	if r.ExpiresAt.IsZero() {
+		return NewValidationError("expires_at cannot be empty")
+	}
+	return nil
+}
+
+// IsExpired reports whether a pending reservation has passed its expiry time
+func (r *Reservation) IsExpired(now time.Time) bool {
+	return r.Status == ReservationStatusPending && !now.Before(r.ExpiresAt)
+}
+
+// ReservationAllocation is the quantity of a reservation held at one location
+type ReservationAllocation struct {
+	Location string `json:"location"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ReservationSplit is a reservation that may span locations. When the
+// requested location covers it, it holds a single reservation and ID is
+// empty; otherwise Reservations are its children, each linked to it by
+// ParentID = ID, and Allocations the quantity held per location.
+type ReservationSplit struct {
+	ID           string                  `json:"id,omitempty"`
+	ProductID    string                  `json:"product_id"`
+	Reference    string                  `json:"reference"`
+	Quantity     int64                   `json:"quantity"`
+	Split        bool                    `json:"split"`
+	Allocations  []ReservationAllocation `json:"allocations"`
+	Reservations []*Reservation          `json:"reservations"`
+}
+
+// NewReservationSplit assembles the split a parent ID's children make up.
+// An empty id makes a split of a single, unsplit reservation.
+func NewReservationSplit(id string, reservations []*Reservation) *ReservationSplit {
+	split := &ReservationSplit{
+		ID:           id,
+		Split:        id != "",
+		Allocations:  make([]ReservationAllocation, 0, len(reservations)),
+		Reservations: reservations,
+	}
+	for _, reservation := range reservations {
+		split.ProductID, split.Reference = reservation.ProductID, reservation.Reference
+		split.Quantity += reservation.Quantity
+		split.Allocations = append(split.Allocations, ReservationAllocation{Location: reservation.Location, Quantity: reservation.Quantity})
+	}
+	return split
+}
+
+// AllocateAcrossLocations divides quantity over the available stock of
+// inventory rows. The preferred location is drawn on first, then the others
+// by most available stock, ties by location. It returns ErrInsufficientStock
+// when all of them together hold too little.
+func AllocateAcrossLocations(items []*InventoryItem, preferred string, quantity int64) ([]ReservationAllocation, error) {
+	candidates := make([]*InventoryItem, 0, len(items))
+	for _, item := range items {
+		if item.AvailableQuantity() > 0 {
+			candidates = append(candidates, item)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (a.Location == preferred) != (b.Location == preferred) {
+			return a.Location == preferred
+		}
+		if a.AvailableQuantity() != b.AvailableQuantity() {
+			return a.AvailableQuantity() > b.AvailableQuantity()
+		}
+		return a.Location < b.Location
+	})
+
+	var allocations []ReservationAllocation
+	remaining := quantity
+	for _, item := range candidates {
+		if remaining <= 0 {
+			break
+		}
+		take := min(item.AvailableQuantity(), remaining)
+		allocations = append(allocations, ReservationAllocation{Location: item.Location, Quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("%w across all locations: %d of %d available", ErrInsufficientStock, quantity-remaining, quantity)
+	}
+	return allocations, nil
+}
+
+// ReservationPolicy decides what a multi-line reservation does when some
+// lines cannot be reserved in full
+type ReservationPolicy string
+
+const (
+	// ReservationPolicyAllOrNothing reserves every line in full or none
+	ReservationPolicyAllOrNothing ReservationPolicy = "all_or_nothing"
+	// ReservationPolicyPartial reserves what is available of each line and
+	// backorders the shortfall
+	ReservationPolicyPartial ReservationPolicy = "partial"
+)
+
+// MaxOrderReservationLines bounds the number of lines of one multi-line
+// reservation
+const MaxOrderReservationLines = 100
+
+// OrderReservationLine is the quantity of one product to reserve for an
+// order; an empty location reserves at the product's default location
+type OrderReservationLine struct {
+	ProductID string `json:"product_id"`
+	Location  string `json:"location,omitempty"`
+	Quantity  int64  `json:"quantity"`
+}
+
+// OrderReservationLineResult is the outcome of one line: the units reserved,
+// the reservation holding them and the backorder of the shortfall, if any.
+// Error explains a line that could not be reserved or backordered.
+type OrderReservationLineResult struct {
+	ProductID   string       `json:"product_id"`
+	Location    string       `json:"location,omitempty"`
+	Requested   int64        `json:"requested"`
+	Reserved    int64        `json:"reserved"`
+	Backordered int64        `json:"backordered"`
+	Reservation *Reservation `json:"reservation,omitempty"`
+	Backorder   *Backorder   `json:"backorder,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// OrderReservation is the outcome of a multi-line reservation. Complete is
+// true when every line was reserved in full.
+type OrderReservation struct {
+	Reference string                       `json:"reference"`
+	Policy    ReservationPolicy            `json:"policy"`
+	Complete  bool                         `json:"complete"`
+	Lines     []OrderReservationLineResult `json:"lines"`
+}
+
+// ValidateOrderReservation checks a multi-line reservation request
+func ValidateOrderReservation(reference string, lines []OrderReservationLine, policy ReservationPolicy) error {
+	if reference == "" {
+		return NewValidationError("reference cannot be empty")
+	}
+	if policy != ReservationPolicyAllOrNothing && policy != ReservationPolicyPartial {
+		return NewValidationError("policy must be %s or %s", ReservationPolicyAllOrNothing, ReservationPolicyPartial)
+	}
+	if len(lines) == 0 {
+		return NewValidationError("lines cannot be empty")
+	}
+	if len(lines) > MaxOrderReservationLines {
+		return NewValidationError("a reservation has at most %d lines", MaxOrderReservationLines)
+	}
+
+	seen := make(map[[2]string]bool, len(lines))
+	for i, line := range lines {
+		if line.ProductID == "" {
+			return NewValidationError("line %d: product_id cannot be empty", i+1)
+		}
+		if line.Quantity <= 0 {
+			return NewValidationError("line %d: quantity must be positive", i+1)
+		}
+		key := [2]string{line.ProductID, line.Location}
+		if seen[key] {
+			return NewValidationError("line %d: duplicate product %s", i+1, line.ProductID)
+		}
+		seen[key] = true
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package domain
+
+import "sort"
+
+// TransferLane is the cost and transit time of moving stock from one
+// warehouse to another. Lanes are directional.
+type TransferLane struct {
+	From         string  `json:"from"`
+	To           string  `json:"to"`
+	UnitCost     float64 `json:"unit_cost"`
+	TransitHours float64 `json:"transit_hours"`
+}
+
+// Validate checks if the lane data is valid
+func (l *TransferLane) Validate() error {
+	if l.From == "" || l.To == "" {
+		return NewValidationError("lane from and to cannot be empty")
+	}
+	if l.From == l.To {
+		return NewValidationError("lane %s must connect two different warehouses", l.From)
+	}
+	if l.UnitCost < 0 {
+		return NewValidationError("lane %s>%s unit cost cannot be negative", l.From, l.To)
+	}
+	if l.TransitHours < 0 {
+		return NewValidationError("lane %s>%s transit time cannot be negative", l.From, l.To)
+	}
+	return nil
+}
+
+// TransferMatrix holds the transfer lanes between warehouses. Pairs without a
+// lane cannot transfer stock to each other.
+type TransferMatrix struct {
+	lanes map[[2]string]TransferLane
+}
+
+// NewTransferMatrix validates the lanes and builds a matrix from them; each
+// direction between two warehouses may only be given once
+func NewTransferMatrix(lanes []TransferLane) (*TransferMatrix, error) {
+	matrix := &TransferMatrix{lanes: make(map[[2]string]TransferLane, len(lanes))}
+	for i := range lanes {
+		if err := lanes[i].Validate(); err != nil {
+			return nil, err
+		}
+		key := [2]string{lanes[i].From, lanes[i].To}
+		if _, ok := matrix.lanes[key]; ok {
+			return nil, NewValidationError("lane %s>%s is defined more than once", lanes[i].From, lanes[i].To)
+		}
+		matrix.lanes[key] = lanes[i]
+	}
+	return matrix, nil
+}
+
+// Lane returns the lane from one warehouse to another, if there is one
+func (m *TransferMatrix) Lane(from, to string) (TransferLane, bool) {
+	if m == nil {
+		return TransferLane{}, false
+	}
+	lane, ok := m.lanes[[2]string{from, to}]
+	return lane, ok
+}
+
+// Lanes returns all lanes ordered by source and destination
+func (m *TransferMatrix) Lanes() []TransferLane {
+	lanes := []TransferLane{}
+	if m == nil {
+		return lanes
+	}
+	for _, lane := range m.lanes {
+		lanes = append(lanes, lane)
+	}
+	sort.Slice(lanes, func(i, j int) bool {
+		if lanes[i].From != lanes[j].From {
+			return lanes[i].From < lanes[j].From
+		}
+		return lanes[i].To < lanes[j].To
+	})
+	return lanes
+}
+
+// FulfillmentStrategy is how an order is fulfilled from a destination warehouse
+type FulfillmentStrategy string
+
+const (
+	// FulfillLocal ships the whole order from the destination warehouse
+	FulfillLocal FulfillmentStrategy = "LOCAL"
+	// FulfillSplit ships the order in several parts, one per warehouse
+	FulfillSplit FulfillmentStrategy = "SPLIT"
+	// FulfillTransfer transfers the shortfall to the destination warehouse
+	// first, then ships the order in one part
+	FulfillTransfer FulfillmentStrategy = "TRANSFER"
+)
+
+// FulfillmentLeg is the part of an order taken from one warehouse: shipped
+// directly, or transferred to the destination when TransitHours is set
+type FulfillmentLeg struct {
+	Location     string  `json:"location"`
+	Quantity     int64   `json:"quantity"`
+	Cost         float64 `json:"cost"`
+	TransitHours float64 `json:"transit_hours,omitempty"`
+}
+
+// FulfillmentOption is one way of fulfilling an order with its total cost
+// and the hours until it can ship in full
+type FulfillmentOption struct {
+	Strategy  FulfillmentStrategy `json:"strategy"`
+	Shipments int                 `json:"shipments"`
+	Cost      float64             `json:"cost"`
+	LeadHours float64             `json:"lead_hours"`
+	Legs      []FulfillmentLeg    `json:"legs"`
+}
+
+// FulfillmentPlan is the chosen strategy for an order. Options lists every
+// feasible option, the chosen one first.
+type FulfillmentPlan struct {
+	ProductID   string               `json:"product_id"`
+	Destination string               `json:"destination"`
+	Quantity    int64                `json:"quantity"`
+	Strategy    FulfillmentStrategy  `json:"strategy"`
+	Options     []*FulfillmentOption `json:"options"`
+}
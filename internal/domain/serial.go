@@ -0,0 +1,95 @@
+package domain
+
+import "time"
+
+// SerialStatus represents the lifecycle state of a single serialized unit
+type SerialStatus string
+
+const (
+	SerialStatusInStock  SerialStatus = "IN_STOCK"
+	SerialStatusReserved SerialStatus = "RESERVED"
+	SerialStatusShipped  SerialStatus = "SHIPPED"
+	SerialStatusReturned SerialStatus = "RETURNED"
+	SerialStatusScrapped SerialStatus = "SCRAPPED"
+)
+
+// serialTransitions lists the statuses each status may move to
+var serialTransitions = map[SerialStatus][]SerialStatus{
+	SerialStatusInStock:  {SerialStatusReserved, SerialStatusShipped, SerialStatusScrapped},
+	SerialStatusReserved: {SerialStatusInStock, SerialStatusShipped},
+	SerialStatusShipped:  {SerialStatusReturned},
+	SerialStatusReturned: {SerialStatusInStock, SerialStatusScrapped},
+	SerialStatusScrapped: {},
+}
+
+// IsValid reports whether the status is a known serial status
+func (s SerialStatus) IsValid() bool {
+	_, ok := serialTransitions[s]
+	return ok
+}
+
+// SerialUnit represents a single, individually tracked unit of a product
+type SerialUnit struct {
+	ID           string       `json:"id"`
+	TenantID     string       `json:"tenant_id"`
+	ProductID    string       `json:"product_id"`
+	SerialNumber string       `json:"serial_number"`
+	Status       SerialStatus `json:"status"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// Validate checks if the serial unit data is valid
+func (u *SerialUnit) Validate() error {
+	if u.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if u.SerialNumber == "" {
+		return NewValidationError("serial number cannot be empty")
+	}
+	if !u.Status.IsValid() {
+		return NewValidationError("invalid serial status")
+	}
+	return nil
+}
+
+// CanTransitionTo reports whether the unit may move to the given status
+func (u *SerialUnit) CanTransitionTo(next SerialStatus) bool {
+	for _, allowed := range serialTransitions[u.Status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo moves the unit to the given status and returns the history event
+func (u *SerialUnit) TransitionTo(next SerialStatus, reference, notes string) (*SerialUnitEvent, error) {
+	if !next.IsValid() {
+		return nil, NewValidationError("invalid serial status")
+	}
+	if !u.CanTransitionTo(next) {
+		return nil, NewValidationError("cannot transition serial unit from %s to %s", u.Status, next)
+	}
+
+	event := &SerialUnitEvent{
+		SerialUnitID: u.ID,
+		FromStatus:   u.Status,
+		ToStatus:     next,
+		Reference:    reference,
+		Notes:        notes,
+	}
+	u.Status = next
+	return event, nil
+}
+
+// SerialUnitEvent records a single status change of a serial unit
+type SerialUnitEvent struct {
+	ID           string       `json:"id"`
+	SerialUnitID string       `json:"serial_unit_id"`
+	FromStatus   SerialStatus `json:"from_status"`
+	ToStatus     SerialStatus `json:"to_status"`
+	Reference    string       `json:"reference"`
+	Notes        string       `json:"notes"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
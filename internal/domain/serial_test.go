@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSerialUnitValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		unit    *SerialUnit
+		wantErr bool
+	}{
+		{
+			name:    "Valid serial unit",
+			unit:    &SerialUnit{ProductID: "prod-1", SerialNumber: "SN-001", Status: SerialStatusInStock},
+			wantErr: false,
+		},
+		{
+			name:    "Missing product ID",
+			unit:    &SerialUnit{SerialNumber: "SN-001", Status: SerialStatusInStock},
+			wantErr: true,
+		},
+		{
+			name:    "Missing serial number",
+			unit:    &SerialUnit{ProductID: "prod-1", Status: SerialStatusInStock},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown status",
+			unit:    &SerialUnit{ProductID: "prod-1", SerialNumber: "SN-001", Status: "LOST"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.unit.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSerialUnitTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    SerialStatus
+		to      SerialStatus
+		wantErr bool
+	}{
+		{"Reserve in-stock unit", SerialStatusInStock, SerialStatusReserved, false},
+		{"Ship reserved unit", SerialStatusReserved, SerialStatusShipped, false},
+		{"Release reserved unit", SerialStatusReserved, SerialStatusInStock, false},
+		{"Return shipped unit", SerialStatusShipped, SerialStatusReturned, false},
+		{"Restock returned unit", SerialStatusReturned, SerialStatusInStock, false},
+		{"Scrap returned unit", SerialStatusReturned, SerialStatusScrapped, false},
+		{"Return unit never shipped", SerialStatusInStock, SerialStatusReturned, true},
+		{"Revive scrapped unit", SerialStatusScrapped, SerialStatusInStock, true},
+		{"Reserve shipped unit", SerialStatusShipped, SerialStatusReserved, true},
+		{"Unknown target status", SerialStatusInStock, "LOST", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit := &SerialUnit{ID: "unit-1", ProductID: "prod-1", SerialNumber: "SN-001", Status: tt.from}
+			event, err := unit.TransitionTo(tt.to, "REF-1", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TransitionTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Expected a validation error, got %v", err)
+				}
+				if unit.Status != tt.from {
+					t.Errorf("Expected status to stay %s, got %s", tt.from, unit.Status)
+				}
+				return
+			}
+			if unit.Status != tt.to {
+				t.Errorf("Expected status %s, got %s", tt.to, unit.Status)
+			}
+			if event.FromStatus != tt.from || event.ToStatus != tt.to {
+				t.Errorf("Unexpected event transition %s -> %s", event.FromStatus, event.ToStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+// SimulationOperationType is the kind of hypothetical stock movement
+type SimulationOperationType string
+
+const (
+	// SimulationOrder takes units out of available stock, as a sale would
+	SimulationOrder SimulationOperationType = "ORDER"
+	// SimulationReceipt adds units to stock, as a goods receipt would
+	SimulationReceipt SimulationOperationType = "RECEIPT"
+)
+
+// SimulationOperation is one hypothetical movement of a what-if simulation.
+// The product is identified by ID or SKU; an empty location targets the
+// product's default location.
+type SimulationOperation struct {
+	Type      SimulationOperationType `json:"type"`
+	ProductID string                  `json:"product_id,omitempty"`
+	SKU       string                  `json:"sku,omitempty"`
+	Location  string                  `json:"location,omitempty"`
+	Quantity  int64                   `json:"quantity"`
+}
+
+// Validate checks if the simulation operation is valid
+func (o *SimulationOperation) Validate() error {
+	if o.Type != SimulationOrder && o.Type != SimulationReceipt {
+		return NewValidationError("type must be %s or %s", SimulationOrder, SimulationReceipt)
+	}
+	if o.ProductID == "" && o.SKU == "" {
+		return NewValidationError("product_id or sku is required")
+	}
+	if o.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	return nil
+}
+
+// ProductSimulation is the simulated availability of one product. Orders
+// are filled from the available stock of their location; what cannot be
+// filled is counted as shortfall.
+type ProductSimulation struct {
+	ProductID          string `json:"product_id"`
+	CurrentAvailable   int64  `json:"current_available"`
+	Ordered            int64  `json:"ordered"`
+	Received           int64  `json:"received"`
+	Shortfall          int64  `json:"shortfall"`
+	ProjectedAvailable int64  `json:"projected_available"`
+}
+
+// SimulationResult is the outcome of a what-if simulation. Feasible is true
+// when every order could be filled in full.
+type SimulationResult struct {
+	Feasible bool                 `json:"feasible"`
+	Products []*ProductSimulation `json:"products"`
+}
@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// StockAlertKind says why an inventory row is low on stock
+type StockAlertKind string
+
+const (
+	// StockAlertPredictedStockout is raised when the row, selling at its
+	// recent velocity, runs out before stock ordered now could arrive
+	StockAlertPredictedStockout StockAlertKind = "PREDICTED_STOCKOUT"
+	// StockAlertBelowReorderLevel is raised for rows without recent sales
+	// whose stock is at or below their reorder level
+	StockAlertBelowReorderLevel StockAlertKind = "BELOW_REORDER_LEVEL"
+)
+
+// StockAlertStatus represents the lifecycle state of a stock alert
+type StockAlertStatus string
+
+const (
+	StockAlertStatusOpen     StockAlertStatus = "OPEN"
+	StockAlertStatusResolved StockAlertStatus = "RESOLVED"
+)
+
+// StockAlert is a low-stock alert for one inventory row. It stays open, with
+// its figures refreshed on every evaluation, until the row no longer needs
+// an alert.
+type StockAlert struct {
+	ID                  string           `json:"id"`
+	ProductID           string           `json:"product_id"`
+	SKU                 string           `json:"sku"`
+	InventoryID         string           `json:"inventory_id"`
+	Location            string           `json:"location"`
+	Kind                StockAlertKind   `json:"kind"`
+	Status              StockAlertStatus `json:"status"`
+	Available           int64            `json:"available"`
+	OnOrder             int64            `json:"on_order"`
+	ReorderLevel        int64            `json:"reorder_level"`
+	DailyVelocity       float64          `json:"daily_velocity"`
+	LeadTimeDays        int              `json:"lead_time_days"`
+	ProjectedStockoutAt *time.Time       `json:"projected_stockout_at,omitempty"`
+	RaisedAt            time.Time        `json:"raised_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+	ResolvedAt          *time.Time       `json:"resolved_at,omitempty"`
+}
+
+// EvaluateStockAlert decides whether a reorder candidate needs a low-stock
+// alert. A row that sold over window is projected to run out when its stock
+// position, available plus on order, is used up at the same rate; it alerts
+// when that falls within the lead time, however far above its reorder level
+// it still is. Rows without sales cannot be projected and fall back to their
+// reorder level. It returns nil when no alert is needed.
+func EvaluateStockAlert(c *ReorderCandidate, window time.Duration, leadTimeDays int, now time.Time) *StockAlert {
+	alert := &StockAlert{
+		ProductID:     c.ProductID,
+		SKU:           c.SKU,
+		InventoryID:   c.InventoryID,
+		Location:      c.Location,
+		Status:        StockAlertStatusOpen,
+		Available:     c.Available,
+		OnOrder:       c.OnOrder,
+		ReorderLevel:  c.ReorderLevel,
+		DailyVelocity: float64(c.Outbound) / window.Hours() * 24,
+		LeadTimeDays:  leadTimeDays,
+	}
+
+	position := c.Available + c.OnOrder
+	if at := ProjectStockout(position, c.Outbound, window, now); at != nil {
+		if at.After(now.AddDate(0, 0, leadTimeDays)) {
+			return nil
+		}
+		alert.Kind = StockAlertPredictedStockout
+		alert.ProjectedStockoutAt = at
+		return alert
+	}
+
+	if c.ReorderLevel > 0 && position <= c.ReorderLevel {
+		alert.Kind = StockAlertBelowReorderLevel
+		return alert
+	}
+	return nil
+}
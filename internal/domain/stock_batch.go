@@ -0,0 +1,67 @@
+package domain
+
+// StockOperation is a single-item stock operation
+type StockOperation string
+
+const (
+	// StockAdd adds units to stock
+	StockAdd StockOperation = "add"
+	// StockRemove takes available units out of stock
+	StockRemove StockOperation = "remove"
+	// StockReserve reserves available units
+	StockReserve StockOperation = "reserve"
+	// StockUnreserve releases reserved units
+	StockUnreserve StockOperation = "unreserve"
+)
+
+// TransactionType returns the type of the transaction the operation records
+func (o StockOperation) TransactionType() string {
+	switch o {
+	case StockAdd:
+		return "IN"
+	case StockRemove:
+		return "OUT"
+	case StockReserve:
+		return "RESERVE"
+	case StockUnreserve:
+		return "UNRESERVE"
+	}
+	return ""
+}
+
+// StockBatchItem is one line of a batch of stock operations. An empty
+// location targets the product's default location.
+type StockBatchItem struct {
+	ProductID string         `json:"product_id"`
+	Location  string         `json:"location,omitempty"`
+	Operation StockOperation `json:"operation"`
+	Quantity  int64          `json:"quantity"`
+	Reference string         `json:"reference"`
+	UnitCost  *float64       `json:"unit_cost,omitempty"`
+}
+
+// Validate checks if the batch item is valid
+func (i *StockBatchItem) Validate() error {
+	if i.ProductID == "" {
+		return NewValidationError("product_id cannot be empty")
+	}
+	if i.Operation.TransactionType() == "" {
+		return NewValidationError("operation must be one of %s, %s, %s or %s", StockAdd, StockRemove, StockReserve, StockUnreserve)
+	}
+	if i.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	if i.UnitCost != nil && (i.Operation != StockAdd || *i.UnitCost < 0) {
+		return NewValidationError("unit_cost must be a non-negative cost of added stock")
+	}
+	return nil
+}
+
+// StockBatchChange is a batch item resolved to an inventory row: the deltas
+// to apply to it and the transaction that records them
+type StockBatchChange struct {
+	InventoryID   string
+	QuantityDelta int64
+	ReservedDelta int64
+	Transaction   *Transaction
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// StockVelocity holds the available stock of a product and the units that
+// left it through sales over a recent window
+type StockVelocity struct {
+	ProductID string
+	SKU       string
+	Name      string
+	Available int64
+	Outbound  int64
+}
+
+// StockoutProjection estimates when a product runs out of available stock at
+// its recent sales velocity
+type StockoutProjection struct {
+	ProductID           string    `json:"product_id"`
+	SKU                 string    `json:"sku"`
+	Name                string    `json:"name"`
+	Available           int64     `json:"available"`
+	DailyVelocity       float64   `json:"daily_velocity"`
+	DaysRemaining       float64   `json:"days_remaining"`
+	ProjectedStockoutAt time.Time `json:"projected_stockout_at"`
+}
+
+// ProjectStockout extrapolates the time available stock is used up when
+// outbound units left over window keep leaving at the same rate. It returns
+// nil when nothing left during the window, since no date can be projected.
+func ProjectStockout(available, outbound int64, window time.Duration, now time.Time) *time.Time {
+	if outbound <= 0 || window <= 0 {
+		return nil
+	}
+	if available < 0 {
+		available = 0
+	}
+
+	remaining := time.Duration(float64(window) * float64(available) / float64(outbound))
+	at := now.Add(remaining)
+	return &at
+}
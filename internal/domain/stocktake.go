@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// StocktakeStatus represents the lifecycle state of a stocktake
+type StocktakeStatus string
+
+const (
+	StocktakeStatusOpen   StocktakeStatus = "OPEN"
+	StocktakeStatusClosed StocktakeStatus = "CLOSED"
+)
+
+// FreezeMode controls what happens to stock mutations at a location while a
+// stocktake is open there
+type FreezeMode string
+
+const (
+	// FreezeModeNone lets mutations through unchanged
+	FreezeModeNone FreezeMode = "NONE"
+	// FreezeModeBlock rejects mutations
+	FreezeModeBlock FreezeMode = "BLOCK"
+	// FreezeModeQueue stores mutations and applies them when the stocktake closes
+	FreezeModeQueue FreezeMode = "QUEUE"
+)
+
+// ParseFreezeMode parses a freeze mode case-insensitively
+func ParseFreezeMode(value string) (FreezeMode, error) {
+	mode := FreezeMode(strings.ToUpper(strings.TrimSpace(value)))
+	switch mode {
+	case FreezeModeNone, FreezeModeBlock, FreezeModeQueue:
+		return mode, nil
+	}
+	return "", NewValidationError("unknown freeze mode %q: must be none, block or queue", value)
+}
+
+// Stocktake is a physical count of a location. While it is open, stock
+// mutations at the location are handled according to its freeze mode so the
+// count is not invalidated mid-count.
+type Stocktake struct {
+	ID        string          `json:"id"`
+	Location  string          `json:"location"`
+	Mode      FreezeMode      `json:"mode"`
+	Status    StocktakeStatus `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	ClosedAt  *time.Time      `json:"closed_at,omitempty"`
+
+	// Replayed lists the queued movements applied when the stocktake closed
+	Replayed []*QueuedMovement `json:"replayed,omitempty"`
+}
+
+// Validate checks if the stocktake data is valid
+func (s *Stocktake) Validate() error {
+	if s.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if _, err := ParseFreezeMode(string(s.Mode)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// QueuedMovementStatus represents the state of a queued stock movement
+type QueuedMovementStatus string
+
+const (
+	QueuedMovementPending QueuedMovementStatus = "PENDING"
+	QueuedMovementApplied QueuedMovementStatus = "APPLIED"
+	QueuedMovementFailed  QueuedMovementStatus = "FAILED"
+)
+
+// QueuedMovement is a stock addition or removal received while its location
+// was frozen in queue mode
+type QueuedMovement struct {
+	ID          string               `json:"id"`
+	StocktakeID string               `json:"stocktake_id"`
+	ProductID   string               `json:"product_id"`
+	Location    string               `json:"location"`
+	Condition   StockCondition       `json:"condition"`
+	Type        string               `json:"type"`
+	Quantity    int64                `json:"quantity"`
+	Reference   string               `json:"reference"`
+	UnitCost    *float64             `json:"unit_cost,omitempty"`
+	Status      QueuedMovementStatus `json:"status"`
+	Error       string               `json:"error,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
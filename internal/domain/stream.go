@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// MaxStreamProducts bounds the products one inventory stream subscribes to
+const MaxStreamProducts = 100
+
+// InventoryEvent is a live update of a product's stock level, summed over all
+// locations. Snapshots, sent when a stream opens, carry no transaction and
+// no sequence; other events carry the transaction's per-product sequence, by
+// which clients order them and detect missed ones.
+type InventoryEvent struct {
+	ProductID           string    `json:"product_id"`
+	Sequence            int64     `json:"sequence,omitempty"`
+	TransactionType     string    `json:"transaction_type,omitempty"`
+	TransactionQuantity int64     `json:"transaction_quantity,omitempty"`
+	Location            string    `json:"location,omitempty"`
+	Quantity            int64     `json:"quantity"`
+	Reserved            int64     `json:"reserved"`
+	Available           int64     `json:"available"`
+	OccurredAt          time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,153 @@
+package domain
+
+import "time"
+
+// MaxShippingNoticeReferenceLength bounds the supplier's reference of a
+// shipping notice
+const MaxShippingNoticeReferenceLength = 100
+
+// QuantityLine is a quantity for one line of a purchase order, by its number
+type QuantityLine struct {
+	Line     int   `json:"line"`
+	Quantity int64 `json:"quantity"`
+}
+
+// SupplierConfirmation is a supplier's confirmation of an open purchase
+// order: the delivery date it promises and, for the lines it cannot deliver
+// in full, the quantity it will. Lines left out are confirmed as ordered.
+type SupplierConfirmation struct {
+	PromisedAt time.Time      `json:"promised_delivery_at"`
+	Lines      []QuantityLine `json:"lines"`
+}
+
+// Quantities validates the confirmation against the order and returns the
+// confirmed quantity of every line by line number
+func (c *SupplierConfirmation) Quantities(order *PurchaseOrder, now time.Time) (map[int]int64, error) {
+	if c.PromisedAt.IsZero() {
+		return nil, NewValidationError("promised_delivery_at is required")
+	}
+	if c.PromisedAt.Before(now.Truncate(24 * time.Hour)) {
+		return nil, NewValidationError("promised_delivery_at cannot be in the past")
+	}
+
+	quantities := make(map[int]int64, len(order.Lines))
+	for _, line := range order.Lines {
+		quantities[line.Line] = line.Quantity
+	}
+	seen := make(map[int]bool, len(c.Lines))
+	for i, confirmed := range c.Lines {
+		line := order.line(confirmed.Line)
+		if line == nil {
+			return nil, NewValidationError("line %d: the order has no line %d", i+1, confirmed.Line)
+		}
+		if seen[confirmed.Line] {
+			return nil, NewValidationError("line %d: line %d is confirmed twice", i+1, confirmed.Line)
+		}
+		seen[confirmed.Line] = true
+		if confirmed.Quantity < 0 || confirmed.Quantity > line.Quantity {
+			return nil, NewValidationError("line %d: quantity must be between 0 and the %d ordered", i+1, line.Quantity)
+		}
+		if confirmed.Quantity < line.ShippedQuantity {
+			return nil, NewValidationError("line %d: %d units were already shipped", i+1, line.ShippedQuantity)
+		}
+		quantities[confirmed.Line] = confirmed.Quantity
+	}
+	return quantities, nil
+}
+
+// AdvanceShippingNotice (ASN) is a supplier's notice of a shipment against a
+// purchase order, sent before the goods arrive. Reference is the supplier's
+// own shipment number and identifies the notice within the order.
+type AdvanceShippingNotice struct {
+	ID              string         `json:"id"`
+	PurchaseOrderID string         `json:"purchase_order_id"`
+	Reference       string         `json:"reference"`
+	Carrier         string         `json:"carrier,omitempty"`
+	TrackingNumber  string         `json:"tracking_number,omitempty"`
+	ShippedAt       time.Time      `json:"shipped_at"`
+	ExpectedAt      *time.Time     `json:"expected_at,omitempty"`
+	Lines           []QuantityLine `json:"lines"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+// Validate checks the notice against the order: every line must name a line
+// of the order, at most once, and ship no more than is still shippable on it
+func (n *AdvanceShippingNotice) Validate(order *PurchaseOrder) error {
+	if n.Reference == "" {
+		return NewValidationError("reference is required")
+	}
+	if len(n.Reference) > MaxShippingNoticeReferenceLength {
+		return NewValidationError("reference cannot be longer than %d characters", MaxShippingNoticeReferenceLength)
+	}
+	if n.ExpectedAt != nil && n.ExpectedAt.Before(n.ShippedAt) {
+		return NewValidationError("expected_at cannot be before shipped_at")
+	}
+	if len(n.Lines) == 0 {
+		return NewValidationError("lines cannot be empty")
+	}
+
+	seen := make(map[int]bool, len(n.Lines))
+	for i, shipped := range n.Lines {
+		line := order.line(shipped.Line)
+		if line == nil {
+			return NewValidationError("line %d: the order has no line %d", i+1, shipped.Line)
+		}
+		if seen[shipped.Line] {
+			return NewValidationError("line %d: line %d is listed twice", i+1, shipped.Line)
+		}
+		seen[shipped.Line] = true
+		if shipped.Quantity <= 0 {
+			return NewValidationError("line %d: quantity must be positive", i+1)
+		}
+		if shippable := line.Shippable(); shipped.Quantity > shippable {
+			return NewValidationError("line %d: only %d units are left to ship", i+1, shippable)
+		}
+	}
+	return nil
+}
+
+// SupplierPurchaseOrder is a purchase order as its supplier sees it, without
+// the internal inventory rows its lines are booked to
+type SupplierPurchaseOrder struct {
+	ID          string              `json:"id"`
+	Notes       string              `json:"notes,omitempty"`
+	Status      PurchaseOrderStatus `json:"status"`
+	Lines       []SupplierOrderLine `json:"lines"`
+	CreatedAt   time.Time           `json:"created_at"`
+	ConfirmedAt *time.Time          `json:"confirmed_at,omitempty"`
+	PromisedAt  *time.Time          `json:"promised_delivery_at,omitempty"`
+}
+
+// SupplierOrderLine is a purchase order line as its supplier sees it
+type SupplierOrderLine struct {
+	Line              int    `json:"line"`
+	ProductID         string `json:"product_id"`
+	Location          string `json:"location"`
+	Quantity          int64  `json:"quantity"`
+	ConfirmedQuantity *int64 `json:"confirmed_quantity,omitempty"`
+	ShippedQuantity   int64  `json:"shipped_quantity"`
+}
+
+// SupplierView returns the order as its supplier sees it
+func (o *PurchaseOrder) SupplierView() *SupplierPurchaseOrder {
+	view := &SupplierPurchaseOrder{
+		ID:          o.ID,
+		Notes:       o.Notes,
+		Status:      o.Status,
+		Lines:       make([]SupplierOrderLine, 0, len(o.Lines)),
+		CreatedAt:   o.CreatedAt,
+		ConfirmedAt: o.ConfirmedAt,
+		PromisedAt:  o.PromisedAt,
+	}
+	for _, line := range o.Lines {
+		view.Lines = append(view.Lines, SupplierOrderLine{
+			Line:              line.Line,
+			ProductID:         line.ProductID,
+			Location:          line.Location,
+			Quantity:          line.Quantity,
+			ConfirmedQuantity: line.ConfirmedQuantity,
+			ShippedQuantity:   line.ShippedQuantity,
+		})
+	}
+	return view
+}
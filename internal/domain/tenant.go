@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+)
+
+// DefaultTenantID owns the data of single-tenant deployments and of requests
+// that name no tenant
+const DefaultTenantID = "default"
+
+// tenantIDPattern is the shape of tenant IDs, which double as subdomains
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ValidateTenantID checks that id can name a tenant
+func ValidateTenantID(id string) error {
+	if !tenantIDPattern.MatchString(id) {
+		return NewValidationError("invalid tenant %q: expected up to 63 lowercase letters, digits and dashes", id)
+	}
+	return nil
+}
+
+type tenantIDKey struct{}
+
+// WithTenantID scopes the products, inventory and transactions read and
+// written with ctx to the tenant
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, id)
+}
+
+// TenantIDFromContext returns the tenant ctx is scoped to, or "" when it is
+// not scoped, as with background work that spans all tenants
+func TenantIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(tenantIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+const (
+	// AnonymousTenant meters requests without an authenticated caller
+	AnonymousTenant = "anonymous"
+	// SystemTenant meters transactions of background work outside requests,
+	// e.g. expired reservations being released
+	SystemTenant = "system"
+)
+
+// Usage metrics sent to billing
+const (
+	UsageMetricAPICalls         = "api_calls"
+	UsageMetricTransactions     = "transactions"
+	UsageMetricTransactionUnits = "transaction_units"
+)
+
+// TenantUsage is the metered usage of one tenant, the authenticated caller
+// (API key name or JWT subject)
+type TenantUsage struct {
+	Tenant           string `json:"tenant"`
+	APICalls         int64  `json:"api_calls"`
+	Transactions     int64  `json:"transactions"`
+	TransactionUnits int64  `json:"transaction_units"`
+}
+
+// Add adds the counts of other
+func (u *TenantUsage) Add(other *TenantUsage) {
+	u.APICalls += other.APICalls
+	u.Transactions += other.Transactions
+	u.TransactionUnits += other.TransactionUnits
+}
+
+// UsageReport is the usage of every tenant over the days from From to To,
+// inclusive. Products are not owned by tenants, so StoredSKUs counts the
+// active products of the whole catalog.
+type UsageReport struct {
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	StoredSKUs int64          `json:"stored_skus"`
+	Tenants    []*TenantUsage `json:"tenants"`
+}
+
+// UsageEvent is a metered quantity of one tenant over a period, sent to a
+// billing integration
+type UsageEvent struct {
+	Tenant      string    `json:"tenant"`
+	Metric      string    `json:"metric"`
+	Quantity    int64     `json:"quantity"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// ValuationMethod is how stock on hand is assigned a cost
+type ValuationMethod string
+
+const (
+	// ValuationFIFO values stock at the cost of the latest receipts, the
+	// earlier ones having left first
+	ValuationFIFO ValuationMethod = "fifo"
+	// ValuationWeightedAverage values stock at the average unit cost of every
+	// costed receipt, weighted by quantity
+	ValuationWeightedAverage ValuationMethod = "weighted_average"
+)
+
+// ParseValuationMethod parses a valuation method, case-insensitively; empty
+// means FIFO
+func ParseValuationMethod(value string) (ValuationMethod, error) {
+	switch method := ValuationMethod(strings.ToLower(value)); method {
+	case "":
+		return ValuationFIFO, nil
+	case ValuationFIFO, ValuationWeightedAverage:
+		return method, nil
+	}
+	return "", NewValidationError("unknown valuation method %q (supported: %s, %s)", value, ValuationFIFO, ValuationWeightedAverage)
+}
+
+// CostLayer is the quantity and unit cost of one costed receipt
+type CostLayer struct {
+	Quantity int64
+	UnitCost float64
+}
+
+// ValuationInput is what the stock of a product is valued from: its quantity
+// on hand, the totals of every costed receipt and the latest costed receipts,
+// newest first, as far as they cover the quantity on hand
+type ValuationInput struct {
+	ProductID     string
+	SKU           string
+	Name          string
+	Quantity      int64
+	ReceivedUnits int64
+	ReceivedCost  float64
+	Layers        []CostLayer
+}
+
+// ProductValuation is the value of the stock on hand of one product.
+// UncostedQuantity is the part of Quantity no costed receipt accounts for,
+// e.g. stock received without a unit cost; it is left out of Value.
+type ProductValuation struct {
+	ProductID        string  `json:"product_id"`
+	SKU              string  `json:"sku"`
+	Name             string  `json:"name"`
+	Quantity         int64   `json:"quantity"`
+	UncostedQuantity int64   `json:"uncosted_quantity"`
+	UnitCost         float64 `json:"unit_cost"`
+	Value            float64 `json:"value"`
+}
+
+// Value values the stock on hand with the given method
+func (in *ValuationInput) Value(method ValuationMethod) *ProductValuation {
+	valuation := &ProductValuation{ProductID: in.ProductID, SKU: in.SKU, Name: in.Name, Quantity: in.Quantity}
+	if in.Quantity <= 0 {
+		return valuation
+	}
+
+	var valued int64
+	var value float64
+	switch method {
+	case ValuationWeightedAverage:
+		if in.ReceivedUnits > 0 {
+			valued = min(in.Quantity, in.ReceivedUnits)
+			value = float64(valued) * in.ReceivedCost / float64(in.ReceivedUnits)
+		}
+	default:
+		for _, layer := range in.Layers {
+			take := min(layer.Quantity, in.Quantity-valued)
+			if take <= 0 {
+				break
+			}
+			valued += take
+			value += float64(take) * layer.UnitCost
+		}
+	}
+
+	valuation.UncostedQuantity = in.Quantity - valued
+	valuation.Value = roundCents(value)
+	if valued > 0 {
+		valuation.UnitCost = math.Round(value/float64(valued)*10000) / 10000
+	}
+	return valuation
+}
+
+// ValuationReport is the value of the stock on hand of every product, with
+// the totals over all of them
+type ValuationReport struct {
+	Method           ValuationMethod     `json:"method"`
+	Quantity         int64               `json:"quantity"`
+	UncostedQuantity int64               `json:"uncosted_quantity"`
+	Value            float64             `json:"value"`
+	Products         []*ProductValuation `json:"products"`
+}
+
+// Add adds the valuation of a product to the report and its totals
+func (r *ValuationReport) Add(valuation *ProductValuation) {
+	r.Quantity += valuation.Quantity
+	r.UncostedQuantity += valuation.UncostedQuantity
+	r.Value = roundCents(r.Value + valuation.Value)
+	r.Products = append(r.Products, valuation)
+}
+
+// roundCents rounds an amount to two decimals
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxVariantAttributes is the most attributes a variant is told apart by
+	MaxVariantAttributes = 10
+	// maxVariantAttributeLength bounds attribute names and values
+	maxVariantAttributeLength = 100
+)
+
+// ProductVariant is a variant of a parent product, such as a size or color.
+// A variant is a product of its own, with its own SKU and inventory that
+// every stock operation addresses by its ProductID; Attributes tell it apart
+// from the other variants of the parent. Quantity, Reserved and Available
+// are its stock in every location and condition.
+type ProductVariant struct {
+	ProductID  string            `json:"product_id"`
+	ParentID   string            `json:"parent_id"`
+	SKU        string            `json:"sku"`
+	Name       string            `json:"name"`
+	Price      float64           `json:"price"`
+	Attributes map[string]string `json:"attributes"`
+	Quantity   int64             `json:"quantity"`
+	Reserved   int64             `json:"reserved"`
+	Available  int64             `json:"available"`
+	Archived   bool              `json:"archived"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// ValidateVariantAttributes checks the attributes of a variant: at least one
+// and at most MaxVariantAttributes, with non-empty names and values
+func ValidateVariantAttributes(attributes map[string]string) error {
+	if len(attributes) == 0 {
+		return NewValidationError("a variant needs at least one attribute, such as size or color")
+	}
+	if len(attributes) > MaxVariantAttributes {
+		return NewValidationError("a variant has at most %d attributes", MaxVariantAttributes)
+	}
+	for name, value := range attributes {
+		if strings.TrimSpace(name) == "" || strings.TrimSpace(value) == "" {
+			return NewValidationError("variant attribute names and values cannot be empty")
+		}
+		if len(name) > maxVariantAttributeLength || len(value) > maxVariantAttributeLength {
+			return NewValidationError("variant attribute names and values are at most %d characters", maxVariantAttributeLength)
+		}
+	}
+	return nil
+}
+
+// VariantLabel joins the attribute values of a variant in the order of
+// their names, e.g. "Red / M" for color Red and size M
+func VariantLabel(attributes map[string]string) string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = attributes[name]
+	}
+	return strings.Join(values, " / ")
+}
+
+// VariantRollup is the stock of a parent product across its variants:
+// totals over all of them and the available stock by attribute value, e.g.
+// how many units of size M are available in any color. Archived variants
+// are listed but left out of the totals.
+type VariantRollup struct {
+	ParentID    string                      `json:"parent_id"`
+	Quantity    int64                       `json:"quantity"`
+	Reserved    int64                       `json:"reserved"`
+	Available   int64                       `json:"available"`
+	ByAttribute map[string]map[string]int64 `json:"available_by_attribute"`
+	Variants    []*ProductVariant           `json:"variants"`
+}
+
+// RollupVariants sums the stock of the variants of a parent product
+func RollupVariants(parentID string, variants []*ProductVariant) *VariantRollup {
+	rollup := &VariantRollup{
+		ParentID:    parentID,
+		ByAttribute: map[string]map[string]int64{},
+		Variants:    variants,
+	}
+	if rollup.Variants == nil {
+		rollup.Variants = []*ProductVariant{}
+	}
+
+	for _, variant := range variants {
+		if variant.Archived {
+			continue
+		}
+		rollup.Quantity += variant.Quantity
+		rollup.Reserved += variant.Reserved
+		rollup.Available += variant.Available
+		for name, value := range variant.Attributes {
+			if rollup.ByAttribute[name] == nil {
+				rollup.ByAttribute[name] = map[string]int64{}
+			}
+			rollup.ByAttribute[name][value] += variant.Available
+		}
+	}
+	return rollup
+}
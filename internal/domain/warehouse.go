@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Warehouse represents a physical stock location. Its code is what inventory
+// items reference as their location.
+type Warehouse struct {
+	ID        string    `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Timezone is the IANA zone of the warehouse; the service default, UTC
+	// unless configured, applies when empty
+	Timezone string `json:"timezone,omitempty"`
+	// CutoffTime (HH:MM, local) is when the warehouse stops taking orders
+	// for the day; the service default applies when empty
+	CutoffTime string `json:"cutoff_time,omitempty"`
+	// LeadTimeDays is how many operating days orders take to process
+	// before they ship; the service default applies when nil
+	LeadTimeDays *int `json:"lead_time_days,omitempty"`
+	// WorkingDays are the days of the week the warehouse operates, MON to
+	// SUN; the service default applies when empty
+	WorkingDays []string `json:"working_days,omitempty"`
+	// Holidays are the local dates (YYYY-MM-DD) the warehouse is closed on
+	// working days; the service default applies when empty
+	Holidays []string `json:"holidays,omitempty"`
+}
+
+// MaxWarehouseHolidays bounds the holidays of a warehouse
+const MaxWarehouseHolidays = 500
+
+// Validate checks if the warehouse data is valid
+func (w *Warehouse) Validate() error {
+	if w.Code == "" {
+		return NewValidationError("warehouse code cannot be empty")
+	}
+	if w.Name == "" {
+		return NewValidationError("warehouse name cannot be empty")
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return NewValidationError("unknown warehouse timezone %s", w.Timezone)
+	}
+	if w.CutoffTime != "" {
+		if _, err := ParseCutoffTime(w.CutoffTime); err != nil {
+			return err
+		}
+	}
+	if w.LeadTimeDays != nil && *w.LeadTimeDays < 0 {
+		return NewValidationError("warehouse lead time cannot be negative")
+	}
+	if len(w.WorkingDays) > 0 {
+		if _, err := ParseWorkingDays(w.WorkingDays); err != nil {
+			return err
+		}
+	}
+	if len(w.Holidays) > MaxWarehouseHolidays {
+		return NewValidationError("a warehouse can have at most %d holidays", MaxWarehouseHolidays)
+	}
+	if _, err := ParseHolidays(w.Holidays); err != nil {
+		return err
+	}
+	return nil
+}
+
+// weekdayNames are the names of the days of the week in working days,
+// indexed by time.Weekday
+var weekdayNames = [7]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// OperatingCalendar is the days a warehouse operates on. The zero value
+// operates every day.
+type OperatingCalendar struct {
+	// Closed marks the days of the week, indexed by time.Weekday, the
+	// warehouse does not work
+	Closed [7]bool
+	// Holidays are the local dates, as YYYY-MM-DD, the warehouse is closed
+	Holidays map[string]bool
+}
+
+// ParseWorkingDays parses the days of the week a warehouse works, such as
+// ["MON", "TUE", "WED", "THU", "FRI"], in any case, into the days it is
+// closed
+func ParseWorkingDays(days []string) ([7]bool, error) {
+	closed := [7]bool{true, true, true, true, true, true, true}
+	if len(days) == 0 {
+		return closed, NewValidationError("a warehouse must work at least one day of the week")
+	}
+	for _, day := range days {
+		i := -1
+		for weekday, name := range weekdayNames {
+			if strings.EqualFold(strings.TrimSpace(day), name) {
+				i = weekday
+			}
+		}
+		if i < 0 {
+			return closed, NewValidationError("unknown working day %q, expected MON to SUN", day)
+		}
+		closed[i] = false
+	}
+	return closed, nil
+}
+
+// ParseHolidays parses the dates, as YYYY-MM-DD, a warehouse is closed on
+func ParseHolidays(days []string) (map[string]bool, error) {
+	holidays := make(map[string]bool, len(days))
+	for _, day := range days {
+		if _, err := time.Parse(time.DateOnly, day); err != nil {
+			return nil, NewValidationError("invalid holiday %q: expected YYYY-MM-DD", day)
+		}
+		holidays[day] = true
+	}
+	return holidays, nil
+}
+
+// Operates reports whether the warehouse works on the day of t, in the
+// location of t
+func (c OperatingCalendar) Operates(t time.Time) bool {
+	return !c.Closed[t.Weekday()] && !c.Holidays[t.Format(time.DateOnly)]
+}
+
+// NextOperatingDay returns day if the warehouse works on it, otherwise the
+// first day after it that it does. A calendar closed on every day of the
+// week returns day.
+func (c OperatingCalendar) NextOperatingDay(day time.Time) time.Time {
+	for next, i := day, 0; i <= 7+len(c.Holidays); next, i = next.AddDate(0, 0, 1), i+1 {
+		if c.Operates(next) {
+			return next
+		}
+	}
+	return day
+}
+
+// AddOperatingDays returns the days'th operating day after day, counting
+// from the next operating day on or after day
+func (c OperatingCalendar) AddOperatingDays(day time.Time, days int) time.Time {
+	day = c.NextOperatingDay(day)
+	for ; days > 0; days-- {
+		day = c.NextOperatingDay(day.AddDate(0, 0, 1))
+	}
+	return day
+}
+
+// ShipSchedule is when a warehouse ships orders. Orders placed on an
+// operating day before the cutoff start processing the same day, others on
+// the next operating day, and ship LeadTimeDays operating days after
+// processing starts.
+type ShipSchedule struct {
+	// Location is the timezone of the cutoff and the calendar; UTC when nil
+	Location *time.Location
+	// Cutoff is the time of day since local midnight; zero means orders
+	// start processing the day they are placed whatever the time
+	Cutoff       time.Duration
+	LeadTimeDays int
+	Calendar     OperatingCalendar
+}
+
+// ParseCutoffTime parses a cutoff time of day given as HH:MM
+func ParseCutoffTime(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, NewValidationError("invalid cutoff time %s: expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// ShipSchedule returns the warehouse's schedule, taking the cutoff, lead
+// time, working days and holidays it does not set from defaults
+func (w *Warehouse) ShipSchedule(defaults ShipSchedule) (ShipSchedule, error) {
+	schedule := defaults
+	if w.Timezone != "" {
+		loc, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return ShipSchedule{}, NewValidationError("unknown warehouse timezone %s", w.Timezone)
+		}
+		schedule.Location = loc
+	}
+	if w.CutoffTime != "" {
+		cutoff, err := ParseCutoffTime(w.CutoffTime)
+		if err != nil {
+			return ShipSchedule{}, err
+		}
+		schedule.Cutoff = cutoff
+	}
+	if w.LeadTimeDays != nil {
+		schedule.LeadTimeDays = *w.LeadTimeDays
+	}
+	if len(w.WorkingDays) > 0 {
+		closed, err := ParseWorkingDays(w.WorkingDays)
+		if err != nil {
+			return ShipSchedule{}, err
+		}
+		schedule.Calendar.Closed = closed
+	}
+	if len(w.Holidays) > 0 {
+		holidays, err := ParseHolidays(w.Holidays)
+		if err != nil {
+			return ShipSchedule{}, err
+		}
+		schedule.Calendar.Holidays = holidays
+	}
+	return schedule, nil
+}
+
+// localDay returns the local date of t, at midnight
+func (s ShipSchedule) localDay(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// ShipDate returns the local date, at midnight, an order placed at orderedAt
+// ships on
+func (s ShipSchedule) ShipDate(orderedAt time.Time) time.Time {
+	day := s.localDay(orderedAt)
+	if !s.Calendar.Operates(day) || (s.Cutoff > 0 && orderedAt.Sub(day) >= s.Cutoff) {
+		day = s.Calendar.NextOperatingDay(day.AddDate(0, 0, 1))
+	}
+	return s.Calendar.AddOperatingDays(day, s.LeadTimeDays)
+}
+
+// LeadTime returns how many calendar days after the local day of now stock
+// ordered now arrives, when it takes operatingDays operating days of the
+// warehouse to arrive. Stock only arrives on operating days.
+func (s ShipSchedule) LeadTime(now time.Time, operatingDays int) int {
+	today := s.localDay(now)
+	arrival := s.Calendar.AddOperatingDays(today, operatingDays)
+	civil := func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+	return int(civil(arrival).Sub(civil(today)).Hours() / 24)
+}
+
+// StockLevel aggregates a product's inventory across all locations, in one
+// condition when Condition is set
+type StockLevel struct {
+	ProductID string           `json:"product_id"`
+	Condition StockCondition   `json:"condition,omitempty"`
+	Quantity  int64            `json:"quantity"`
+	Reserved  int64            `json:"reserved"`
+	Available int64            `json:"available"`
+	Locations []*InventoryItem `json:"locations"`
+
+	// ProjectedStockoutAt is computed from recent sales over all locations
+	ProjectedStockoutAt *time.Time `json:"projected_stockout_at,omitempty"`
+}
+
+// NewStockLevel sums the inventory items of a product
+func NewStockLevel(productID string, items []*InventoryItem) *StockLevel {
+	level := &StockLevel{ProductID: productID, Locations: items}
+	for _, item := range items {
+		level.Quantity += item.Quantity
+		level.Reserved += item.Reserved
+		level.Available += item.AvailableQuantity()
+	}
+	if level.Locations == nil {
+		level.Locations = []*InventoryItem{}
+	}
+	return level
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShipScheduleShipDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	closed, err := ParseWorkingDays([]string{"MON", "TUE", "WED", "THU", "FRI"})
+	if err != nil {
+		t.Fatalf("ParseWorkingDays() error = %v", err)
+	}
+	weekdays := OperatingCalendar{Closed: closed}
+
+	tests := []struct {
+		name      string
+		schedule  ShipSchedule
+		orderedAt time.Time
+		want      string
+	}{
+		{
+			name:      "No cutoff ships after the lead time",
+			schedule:  ShipSchedule{LeadTimeDays: 2},
+			orderedAt: time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC),
+			want:      "2026-03-12",
+		},
+		{
+			name:      "Before the cutoff",
+			schedule:  ShipSchedule{Cutoff: 14 * time.Hour},
+			orderedAt: time.Date(2026, 3, 10, 13, 59, 0, 0, time.UTC),
+			want:      "2026-03-10",
+		},
+		{
+			name:      "At the cutoff moves to the next day",
+			schedule:  ShipSchedule{Cutoff: 14 * time.Hour, LeadTimeDays: 1},
+			orderedAt: time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC),
+			want:      "2026-03-12",
+		},
+		{
+			name:      "Cutoff is local to the warehouse",
+			schedule:  ShipSchedule{Location: berlin, Cutoff: 14 * time.Hour},
+			orderedAt: time.Date(2026, 3, 10, 13, 30, 0, 0, time.UTC),
+			want:      "2026-03-11",
+		},
+		{
+			name:      "Local date differs from UTC",
+			schedule:  ShipSchedule{Location: berlin},
+			orderedAt: time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC),
+			want:      "2026-03-11",
+		},
+		{
+			name:      "Lead time skips the weekend",
+			schedule:  ShipSchedule{LeadTimeDays: 2, Calendar: weekdays},
+			orderedAt: time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC),
+			want:      "2026-03-16",
+		},
+		{
+			name:      "After the cutoff on a Friday moves to Monday",
+			schedule:  ShipSchedule{Cutoff: 14 * time.Hour, LeadTimeDays: 1, Calendar: weekdays},
+			orderedAt: time.Date(2026, 3, 13, 15, 0, 0, 0, time.UTC),
+			want:      "2026-03-17",
+		},
+		{
+			name:      "Orders on a closed day start on the next operating day",
+			schedule:  ShipSchedule{Cutoff: 14 * time.Hour, Calendar: weekdays},
+			orderedAt: time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC),
+			want:      "2026-03-16",
+		},
+		{
+			name:      "Holidays are skipped",
+			schedule:  ShipSchedule{LeadTimeDays: 1, Calendar: OperatingCalendar{Closed: weekdays.Closed, Holidays: map[string]bool{"2026-03-16": true}}},
+			orderedAt: time.Date(2026, 3, 13, 9, 0, 0, 0, time.UTC),
+			want:      "2026-03-17",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.ShipDate(tt.orderedAt).Format(time.DateOnly); got != tt.want {
+				t.Errorf("ShipDate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarehouseShipSchedule(t *testing.T) {
+	defaults := ShipSchedule{Cutoff: 15 * time.Hour, LeadTimeDays: 1}
+
+	schedule, err := (&Warehouse{Code: "WH-A", Name: "A"}).ShipSchedule(defaults)
+	if err != nil || !reflect.DeepEqual(schedule, defaults) {
+		t.Errorf("Expected the defaults, got %+v (%v)", schedule, err)
+	}
+
+	sameDay := 0
+	schedule, err = (&Warehouse{Code: "WH-B", Name: "B", CutoffTime: "11:30", LeadTimeDays: &sameDay}).ShipSchedule(defaults)
+	if err != nil {
+		t.Fatalf("ShipSchedule() error = %v", err)
+	}
+	if schedule.Cutoff != 11*time.Hour+30*time.Minute || schedule.LeadTimeDays != 0 {
+		t.Errorf("Expected the warehouse overrides, got %+v", schedule)
+	}
+
+	schedule, err = (&Warehouse{Code: "WH-B", Name: "B", WorkingDays: []string{"mon", "Tue"}, Holidays: []string{"2026-12-25"}}).ShipSchedule(defaults)
+	if err != nil {
+		t.Fatalf("ShipSchedule() error = %v", err)
+	}
+	if !schedule.Calendar.Operates(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) ||
+		schedule.Calendar.Operates(time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)) ||
+		!schedule.Calendar.Holidays["2026-12-25"] {
+		t.Errorf("Expected the warehouse calendar, got %+v", schedule.Calendar)
+	}
+
+	invalid := []*Warehouse{
+		{Code: "WH-C", Name: "C", CutoffTime: "25:00"},
+		{Code: "WH-C", Name: "C", Timezone: "Mars/Olympus"},
+		{Code: "WH-C", Name: "C", LeadTimeDays: new(int)},
+		{Code: "WH-C", Name: "C", WorkingDays: []string{"MON", "FUNDAY"}},
+		{Code: "WH-C", Name: "C", Holidays: []string{"25/12/2026"}},
+	}
+	*invalid[2].LeadTimeDays = -1
+	for _, warehouse := range invalid {
+		if err := warehouse.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", warehouse)
+		}
+	}
+}
+
+func TestShipScheduleLeadTime(t *testing.T) {
+	closed, _ := ParseWorkingDays([]string{"MON", "TUE", "WED", "THU", "FRI"})
+	schedule := ShipSchedule{Calendar: OperatingCalendar{Closed: closed, Holidays: map[string]bool{"2026-03-20": true}}}
+	thursday := time.Date(2026, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		schedule      ShipSchedule
+		now           time.Time
+		operatingDays int
+		want          int
+	}{
+		{name: "Every day operates", now: thursday, operatingDays: 3, want: 3},
+		{name: "Over a weekend", schedule: schedule, now: thursday, operatingDays: 2, want: 4},
+		{name: "Over a weekend and a holiday", schedule: schedule, now: thursday, operatingDays: 6, want: 11},
+		{name: "Ordered on a closed day", schedule: schedule, now: thursday.AddDate(0, 0, 2), operatingDays: 0, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.LeadTime(tt.now, tt.operatingDays); got != tt.want {
+				t.Errorf("LeadTime() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
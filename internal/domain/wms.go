@@ -0,0 +1,89 @@
+package domain
+
+import "time"
+
+// MaxWMSEventIDLength bounds the length of WMS-assigned event IDs
+const MaxWMSEventIDLength = 255
+
+// WMSEventType is the kind of warehouse scan a WMS event reports
+type WMSEventType string
+
+const (
+	// WMSEventReceive scans stock in, e.g. at a receiving dock; it is added
+	WMSEventReceive WMSEventType = "RECEIVE"
+	// WMSEventPick scans stock out, e.g. when picked for shipping; it is
+	// removed
+	WMSEventPick WMSEventType = "PICK"
+)
+
+// WMSEventStatus is the outcome of a WMS event that was applied
+type WMSEventStatus string
+
+const (
+	// WMSEventApplied events changed the stock of their location
+	WMSEventApplied WMSEventStatus = "APPLIED"
+	// WMSEventQueued events arrived during a stocktake of their location and
+	// are applied when the stocktake closes
+	WMSEventQueued WMSEventStatus = "QUEUED"
+)
+
+// WMSEvent is a warehouse scan event consumed from the WMS topic. The WMS
+// may deliver an event more than once; each event ID changes stock only
+// once per tenant. Events without a tenant belong to the default tenant.
+type WMSEvent struct {
+	EventID     string         `json:"event_id"`
+	TenantID    string         `json:"tenant_id,omitempty"`
+	Type        WMSEventType   `json:"type"`
+	ProductID   string         `json:"product_id,omitempty"`
+	SKU         string         `json:"sku,omitempty"`
+	Location    string         `json:"location"`
+	Quantity    int64          `json:"quantity"`
+	Reference   string         `json:"reference,omitempty"`
+	ScannedAt   time.Time      `json:"scanned_at"`
+	Status      WMSEventStatus `json:"status,omitempty"`
+	ProcessedAt time.Time      `json:"processed_at,omitempty"`
+}
+
+// Validate checks a WMS event
+func (e *WMSEvent) Validate() error {
+	if e.EventID == "" {
+		return NewValidationError("event_id cannot be empty")
+	}
+	if len(e.EventID) > MaxWMSEventIDLength {
+		return NewValidationError("event_id must be at most %d characters", MaxWMSEventIDLength)
+	}
+	if e.TenantID != "" {
+		if err := ValidateTenantID(e.TenantID); err != nil {
+			return err
+		}
+	}
+	if e.Type != WMSEventReceive && e.Type != WMSEventPick {
+		return NewValidationError("unknown event type %q (supported: %s, %s)", e.Type, WMSEventReceive, WMSEventPick)
+	}
+	if e.ProductID == "" && e.SKU == "" {
+		return NewValidationError("product_id or sku is required")
+	}
+	if e.Location == "" {
+		return NewValidationError("location cannot be empty")
+	}
+	if e.Quantity <= 0 {
+		return NewValidationError("quantity must be positive")
+	}
+	return nil
+}
+
+// WMSDeadLetter is a message from the WMS topic that could not be applied
+// and will not be retried: it is malformed, invalid, or names an unknown
+// product or more stock than is on record. It is kept with its position in
+// the topic for the operators to correct and resend.
+type WMSDeadLetter struct {
+	ID        string    `json:"id"`
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Offset    int64     `json:"offset"`
+	Key       string    `json:"key,omitempty"`
+	Payload   string    `json:"payload"`
+	EventID   string    `json:"event_id,omitempty"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
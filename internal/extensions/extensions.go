@@ -0,0 +1,180 @@
+// Package extensions lets deployments compile custom behaviour into the server
+// without changing the built-in handlers. An extension package registers its
+// hooks from an init function and is enabled with a blank import in
+// cmd/server/extensions.go:
+//
+//	func init() {
+//		extensions.RegisterMiddleware(extensions.Middleware{
+//			Name:  "tenant-header",
+//			Order: 10,
+//			Wrap:  requireTenantHeader,
+//		})
+//	}
+//
+// Hooks of each kind run in ascending Order; hooks with the same Order run in
+// registration order. Names must be unique per kind.
+package extensions
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+// Middleware wraps the HTTP handler chain. Lower Order middlewares are outer,
+// so they see requests first and responses last.
+type Middleware struct {
+	Name  string
+	Order int
+	Wrap  func(http.Handler) http.Handler
+}
+
+// Validator approves or rejects stock removals before they are committed,
+// alongside any configured approval webhooks
+type Validator struct {
+	Name      string
+	Order     int
+	Validator service.MovementValidator
+}
+
+// EventHandler is notified of every recorded inventory transaction
+type EventHandler struct {
+	Name    string
+	Order   int
+	Handler service.TransactionHandler
+}
+
+// Registry holds registered hooks. Most code uses the package-level
+// functions, which operate on the default registry.
+type Registry struct {
+	mu            sync.RWMutex
+	middlewares   []Middleware
+	validators    []Validator
+	eventHandlers []EventHandler
+	names         map[string]bool
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// claim reserves a hook name for a kind, panicking on duplicates the way
+// database/sql does for drivers. Callers must hold the write lock.
+func (r *Registry) claim(kind, name string) {
+	if name == "" {
+		panic(fmt.Sprintf("extensions: %s registered without a name", kind))
+	}
+	key := kind + "/" + name
+	if r.names[key] {
+		panic(fmt.Sprintf("extensions: %s %q registered twice", kind, name))
+	}
+	r.names[key] = true
+}
+
+// RegisterMiddleware adds an HTTP middleware
+func (r *Registry) RegisterMiddleware(m Middleware) {
+	if m.Wrap == nil {
+		panic(fmt.Sprintf("extensions: middleware %q has no Wrap function", m.Name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claim("middleware", m.Name)
+	r.middlewares = append(r.middlewares, m)
+	sort.SliceStable(r.middlewares, func(i, j int) bool { return r.middlewares[i].Order < r.middlewares[j].Order })
+}
+
+// RegisterValidator adds a stock movement validator
+func (r *Registry) RegisterValidator(v Validator) {
+	if v.Validator == nil {
+		panic(fmt.Sprintf("extensions: validator %q is nil", v.Name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claim("validator", v.Name)
+	r.validators = append(r.validators, v)
+	sort.SliceStable(r.validators, func(i, j int) bool { return r.validators[i].Order < r.validators[j].Order })
+}
+
+// RegisterEventHandler adds a transaction event handler
+func (r *Registry) RegisterEventHandler(h EventHandler) {
+	if h.Handler == nil {
+		panic(fmt.Sprintf("extensions: event handler %q is nil", h.Name))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claim("event handler", h.Name)
+	r.eventHandlers = append(r.eventHandlers, h)
+	sort.SliceStable(r.eventHandlers, func(i, j int) bool { return r.eventHandlers[i].Order < r.eventHandlers[j].Order })
+}
+
+// WrapHandler applies the registered middlewares to handler, lowest Order outermost
+func (r *Registry) WrapHandler(handler http.Handler) http.Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i].Wrap(handler)
+	}
+	return handler
+}
+
+// Validators returns the registered validators in order
+func (r *Registry) Validators() []service.MovementValidator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	validators := make([]service.MovementValidator, 0, len(r.validators))
+	for _, v := range r.validators {
+		validators = append(validators, v.Validator)
+	}
+	return validators
+}
+
+// EventHandlers returns the registered event handlers in order
+func (r *Registry) EventHandlers() []service.TransactionHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handlers := make([]service.TransactionHandler, 0, len(r.eventHandlers))
+	for _, h := range r.eventHandlers {
+		handlers = append(handlers, h.Handler)
+	}
+	return handlers
+}
+
+// Names lists the registered hooks as "kind/name", in hook order per kind
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for _, m := range r.middlewares {
+		names = append(names, "middleware/"+m.Name)
+	}
+	for _, v := range r.validators {
+		names = append(names, "validator/"+v.Name)
+	}
+	for _, h := range r.eventHandlers {
+		names = append(names, "event handler/"+h.Name)
+	}
+	return names
+}
+
+// Default is the registry the server is built from
+var Default = NewRegistry()
+
+// RegisterMiddleware adds an HTTP middleware to the default registry
+func RegisterMiddleware(m Middleware) { Default.RegisterMiddleware(m) }
+
+// RegisterValidator adds a stock movement validator to the default registry
+func RegisterValidator(v Validator) { Default.RegisterValidator(v) }
+
+// RegisterEventHandler adds a transaction event handler to the default registry
+func RegisterEventHandler(h EventHandler) { Default.RegisterEventHandler(h) }
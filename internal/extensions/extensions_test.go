@@ -0,0 +1,84 @@
+package extensions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/service"
+)
+
+func tagMiddleware(tag string, seen *[]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*seen = append(*seen, tag)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareOrdering(t *testing.T) {
+	registry := NewRegistry()
+	var seen []string
+
+	registry.RegisterMiddleware(Middleware{Name: "late", Order: 20, Wrap: tagMiddleware("late", &seen)})
+	registry.RegisterMiddleware(Middleware{Name: "early", Order: 10, Wrap: tagMiddleware("early", &seen)})
+	registry.RegisterMiddleware(Middleware{Name: "early-2", Order: 10, Wrap: tagMiddleware("early-2", &seen)})
+
+	handler := registry.WrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, "handler")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"early", "early-2", "late", "handler"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("Expected order %v, got %v", want, seen)
+	}
+}
+
+type namedValidator string
+
+func (v namedValidator) ValidateMovement(ctx context.Context, movement service.StockMovement) error {
+	return nil
+}
+
+type recordingHandler struct{ count int }
+
+func (h *recordingHandler) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	h.count++
+}
+
+func TestValidatorAndEventHandlerOrdering(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterValidator(Validator{Name: "b", Order: 2, Validator: namedValidator("b")})
+	registry.RegisterValidator(Validator{Name: "a", Order: 1, Validator: namedValidator("a")})
+	registry.RegisterEventHandler(EventHandler{Name: "audit", Handler: &recordingHandler{}})
+
+	validators := registry.Validators()
+	if len(validators) != 2 || validators[0] != namedValidator("a") || validators[1] != namedValidator("b") {
+		t.Errorf("Expected validators ordered a, b; got %v", validators)
+	}
+	if len(registry.EventHandlers()) != 1 {
+		t.Errorf("Expected 1 event handler, got %d", len(registry.EventHandlers()))
+	}
+
+	want := []string{"validator/a", "validator/b", "event handler/audit"}
+	if names := registry.Names(); !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected names %v, got %v", want, names)
+	}
+}
+
+func TestDuplicateRegistrationPanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterValidator(Validator{Name: "fraud", Validator: namedValidator("fraud")})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate registration to panic")
+		}
+	}()
+	registry.RegisterValidator(Validator{Name: "fraud", Validator: namedValidator("fraud")})
+}
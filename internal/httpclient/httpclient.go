@@ -0,0 +1,262 @@
+// Package httpclient is the outbound HTTP client of webhooks and connectors.
+// It bounds every attempt with a timeout, retries transient failures with
+// jittered exponential backoff and stops calling a host that keeps failing
+// (circuit breaking), so that one slow or broken receiver cannot stall its
+// callers. Requests go through the configured proxy, or the one named by the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables. Every attempt is
+// traced as a client span and carries the trace context of its request.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// ErrCircuitOpen is returned without calling the host while its circuit is open
+var ErrCircuitOpen = errors.New("circuit open: host is failing")
+
+// maxDrainedBody bounds how much of a retried response is read so that its
+// connection can be reused
+const maxDrainedBody = 64 << 10
+
+// Doer sends HTTP requests; *http.Client and *Client implement it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config configures a Client
+type Config struct {
+	// Timeout bounds each attempt, including reading the response body; zero
+	// means no timeout beyond the request context
+	Timeout time.Duration
+	// MaxRetries is the number of attempts after the first one
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry, doubling with every
+	// further retry up to MaxDelay; each delay is jittered by up to half
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// BreakerThreshold is the number of consecutive failed attempts after
+	// which a host's circuit opens for BreakerCooldown; zero disables circuit
+	// breaking. Once the cooldown passed, one trial request decides whether
+	// the circuit closes again.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// Proxy is the proxy URL; empty uses the proxy environment variables
+	Proxy string
+}
+
+// DefaultConfig returns the configuration of connectors without own settings
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          10 * time.Second,
+		MaxRetries:       2,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Client sends requests with timeouts, retries and circuit breaking
+type Client struct {
+	http   *http.Client
+	config Config
+	now    func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// breaker is the circuit state of one host
+type breaker struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// New creates a new Client
+func New(config Config) (*Client, error) {
+	if config.Timeout < 0 || config.MaxRetries < 0 || config.BaseDelay < 0 || config.MaxDelay < 0 ||
+		config.BreakerThreshold < 0 || config.BreakerCooldown < 0 {
+		return nil, errors.New("http client timeouts, retries, delays and breaker settings cannot be negative")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxy, err := url.Parse(config.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &Client{
+		http:     &http.Client{Transport: otelhttp.NewTransport(transport, otelhttp.WithMeterProvider(noop.NewMeterProvider()))},
+		config:   config,
+		now:      time.Now,
+		breakers: make(map[string]*breaker),
+	}, nil
+}
+
+// Do sends the request, retrying network errors, 429 and 5xx responses
+// except 501 while attempts and the request context allow. Requests with a
+// body are only retried when it can be replayed (req.GetBody, set by
+// http.NewRequest for in-memory bodies). The last response is returned as
+// is; callers interpret its status.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	for attempt := 0; ; attempt++ {
+		if !c.allow(host) {
+			return nil, fmt.Errorf("%s: %w", host, ErrCircuitOpen)
+		}
+
+		resp, err := c.attempt(req, attempt)
+		retryable := err != nil || retryableStatus(resp.StatusCode)
+		c.record(host, !retryable)
+
+		canReplay := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+		if !retryable || attempt >= c.config.MaxRetries || !canReplay || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainedBody))
+			resp.Body.Close()
+		}
+		slog.DebugContext(req.Context(), "retrying outbound request",
+			"host", host, "attempt", attempt+1, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once within the per-attempt timeout, which stays
+// in force until the response body is closed
+func (c *Client) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if c.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+	}
+
+	try := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to replay request body: %w", err)
+		}
+		try.Body = body
+	}
+
+	resp, err := c.http.Do(try)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the delay before the retry following attempt: the
+// response's Retry-After when given in seconds, otherwise jittered
+// exponential backoff; both capped at MaxDelay
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.config.MaxDelay)
+		}
+	}
+
+	delay := c.config.BaseDelay << attempt
+	if delay <= 0 || delay > c.config.MaxDelay {
+		delay = c.config.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// allow reports whether a request to host may be sent: always with a closed
+// circuit, never while open, and a single trial once the cooldown passed
+func (c *Client) allow(host string) bool {
+	if c.config.BreakerThreshold == 0 {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := c.breakers[host]
+	if b == nil || b.failures < c.config.BreakerThreshold {
+		return true
+	}
+	if b.probing || c.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the circuit of host with the outcome of an attempt
+func (c *Client) record(host string, ok bool) {
+	if c.config.BreakerThreshold == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := c.breakers[host]
+	if b == nil {
+		b = &breaker{}
+		c.breakers[host] = b
+	}
+	probe := b.probing
+	b.probing = false
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures == c.config.BreakerThreshold || probe {
+		slog.Warn("outbound circuit opened", "host", host, "cooldown", c.config.BreakerCooldown)
+		b.openUntil = c.now().Add(c.config.BreakerCooldown)
+	}
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= 500 && status != http.StatusNotImplemented)
+}
+
+// cancelOnClose releases the attempt's timeout once the body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
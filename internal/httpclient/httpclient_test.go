@@ -0,0 +1,166 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig retries quickly and never opens circuits
+func testConfig() Config {
+	return Config{Timeout: time.Second, MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestClientRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("Expected the body to be replayed, got %q", body)
+		}
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || calls.Load() != 3 {
+		t.Errorf("Expected 202 after 3 attempts, got %d after %d", resp.StatusCode, calls.Load())
+	}
+
+	// Client errors are final, and retries stop after MaxRetries
+	for _, tt := range []struct {
+		status    int
+		wantCalls int32
+	}{
+		{http.StatusUnprocessableEntity, 1},
+		{http.StatusNotImplemented, 1},
+		{http.StatusBadGateway, 3},
+	} {
+		calls.Store(0)
+		status := tt.status
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(status)
+		})
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || calls.Load() != tt.wantCalls {
+			t.Errorf("Expected %d after %d attempts, got %d after %d", tt.status, tt.wantCalls, resp.StatusCode, calls.Load())
+		}
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := testConfig()
+	config.Timeout = 20 * time.Millisecond
+	config.MaxRetries = 1
+	client, _ := New(config)
+
+	start := time.Now()
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("Expected a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected both attempts to time out quickly, took %v", elapsed)
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.MaxRetries = 0
+	config.BreakerThreshold = 2
+	config.BreakerCooldown = time.Minute
+	client, _ := New(config)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	get := func() (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	get()
+	get()
+	if _, err := get(); !errors.Is(err, ErrCircuitOpen) || calls.Load() != 2 {
+		t.Fatalf("Expected the open circuit to refuse the third call, got %v after %d calls", err, calls.Load())
+	}
+
+	// After the cooldown a failing trial reopens the circuit...
+	now = now.Add(time.Minute)
+	get()
+	if _, err := get(); !errors.Is(err, ErrCircuitOpen) || calls.Load() != 3 {
+		t.Fatalf("Expected a single trial call, got %v after %d calls", err, calls.Load())
+	}
+
+	// ...and a successful one closes it
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	for range 3 {
+		if resp, err := get(); err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected the closed circuit to pass calls, got %v", err)
+		}
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	if _, err := New(Config{Proxy: "not a url"}); err == nil {
+		t.Error("Expected an invalid proxy URL to be rejected")
+	}
+	if _, err := New(Config{MaxRetries: -1}); err == nil {
+		t.Error("Expected negative retries to be rejected")
+	}
+	if _, err := New(Config{Proxy: "http://proxy.internal:3128"}); err != nil {
+		t.Errorf("New() error = %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+// Package i18n provides language negotiation and translated API messages.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when a request does not ask for a supported language
+const DefaultLanguage = "en"
+
+// catalogs maps language -> error code -> translated message. English is the
+// source language and needs no catalog: untranslated codes keep their message.
+var catalogs = map[string]map[string]string{
+	"de": {
+		"METHOD_NOT_ALLOWED":          "Diese Methode ist für diese Ressource nicht erlaubt",
+		"INVALID_REQUEST":             "Ungültige Anfrage",
+		"INVALID_TIMEZONE":            "Unbekannte Zeitzone",
+		"NOT_FOUND":                   "Die angeforderte Ressource wurde nicht gefunden",
+		"VALIDATION_FAILED":           "Die Eingabe ist ungültig",
+		"INSUFFICIENT_STOCK":          "Der verfügbare Bestand reicht nicht aus",
+		"DUPLICATE_SKU":               "Diese Artikelnummer (SKU) ist bereits vergeben",
+		"CREATION_FAILED":             "Die Ressource konnte nicht angelegt werden",
+		"UPDATE_FAILED":               "Die Ressource konnte nicht aktualisiert werden",
+		"DELETE_FAILED":               "Die Ressource konnte nicht gelöscht werden",
+		"RESTORE_FAILED":              "Das Produkt konnte nicht wiederhergestellt werden",
+		"JOB_FETCH_FAILED":            "Der Auftrag konnte nicht abgerufen werden",
+		"JOB_START_FAILED":            "Der Auftrag konnte nicht gestartet werden",
+		"LIST_FAILED":                 "Die Liste konnte nicht abgerufen werden",
+		"RETRIEVAL_FAILED":            "Die Daten konnten nicht abgerufen werden",
+		"OPERATION_FAILED":            "Die Lagerbuchung konnte nicht ausgeführt werden",
+		"QUOTA_EXCEEDED":              "Das Reservierungskontingent ist ausgeschöpft",
+		"OVERLOADED":                  "Der Server ist ausgelastet, bitte später erneut versuchen",
+		"READ_ONLY":                   "Der Server nimmt derzeit nur Lesezugriffe an",
+		"MOVEMENT_REJECTED":           "Die Lagerbewegung wurde abgelehnt",
+		"LOCATION_FROZEN":             "Der Lagerort ist wegen einer Inventur gesperrt",
+		"CONFLICT":                    "Die Ressource wurde zwischenzeitlich geändert",
+		"APPROVAL_UNAVAILABLE":        "Die Freigabe der Lagerbewegung ist derzeit nicht verfügbar",
+		"LOCK_TIMEOUT":                "Das Produkt wird gerade anderweitig gebucht, bitte erneut versuchen",
+		"NOT_RELEASED":                "Das Produkt ist noch nicht freigegeben",
+		"RESERVATION_NOT_PENDING":     "Die Reservierung ist nicht mehr offen",
+		"DUPLICATE_ORDER":             "Diese Auftragsnummer ist bereits vergeben",
+		"DUPLICATE_CHANNEL":           "Ein Vertriebskanal mit diesem Namen existiert bereits",
+		"DUPLICATE_VARIANT":           "Eine Variante mit diesen Merkmalen existiert bereits",
+		"ORDER_NOT_PENDING":           "Der Auftrag ist nicht mehr offen",
+		"CART_HOLD_NOT_ACTIVE":        "Die Warenkorbreservierung ist nicht mehr aktiv",
+		"PURCHASE_ORDER_NOT_DRAFT":    "Die Bestellung ist kein Entwurf mehr",
+		"STREAM_FULL":                 "Zu viele Live-Bestandsabonnements, bitte später erneut versuchen",
+		"IDEMPOTENCY_KEY_REUSED":      "Der Idempotenzschlüssel wurde für eine andere Anfrage verwendet",
+		"IDEMPOTENCY_KEY_IN_PROGRESS": "Eine Anfrage mit diesem Idempotenzschlüssel wird noch bearbeitet",
+		"EXPORT_FAILED":               "Der Export konnte nicht erstellt werden",
+		"REDACTION_FAILED":            "Die Schwärzung konnte nicht durchgeführt werden",
+		"REPORT_FAILED":               "Der Bericht konnte nicht erstellt werden",
+		"IMPORT_FAILED":               "Der Import konnte nicht durchgeführt werden",
+		"SIMULATION_FAILED":           "Die Simulation konnte nicht durchgeführt werden",
+		"ROUTING_FAILED":              "Die Versandplanung ist fehlgeschlagen",
+		"PATCH_TEST_FAILED":           "Eine Testoperation des Patches stimmt nicht mit dem aktuellen Wert überein",
+		"PRECONDITION_FAILED":         "Nach der Buchung bliebe weniger Bestand verfügbar als gefordert",
+		"UNSUPPORTED_MEDIA_TYPE":      "Der Inhaltstyp der Anfrage wird nicht unterstützt",
+		"INVALID_FIELDS":              "Ungültige Feldauswahl",
+		"SYNC_FAILED":                 "Der Katalogabgleich ist fehlgeschlagen",
+		"UNAUTHORIZED":                "Anmeldung erforderlich",
+		"FORBIDDEN":                   "Keine Berechtigung für diese Aktion",
+		"INVALID_TENANT":              "Ungültiger Mandant",
+		"TENANT_FORBIDDEN":            "Kein Zugriff auf diesen Mandanten",
+		"INTERNAL_ERROR":              "Ein unerwarteter Fehler ist aufgetreten",
+	},
+	"es": {
+		"METHOD_NOT_ALLOWED":          "Método no permitido para este recurso",
+		"INVALID_REQUEST":             "Solicitud no válida",
+		"INVALID_TIMEZONE":            "Zona horaria desconocida",
+		"NOT_FOUND":                   "No se encontró el recurso solicitado",
+		"VALIDATION_FAILED":           "Los datos enviados no son válidos",
+		"INSUFFICIENT_STOCK":          "No hay suficiente stock disponible",
+		"DUPLICATE_SKU":               "Ya existe un producto con este SKU",
+		"CREATION_FAILED":             "No se pudo crear el recurso",
+		"UPDATE_FAILED":               "No se pudo actualizar el recurso",
+		"DELETE_FAILED":               "No se pudo eliminar el recurso",
+		"RESTORE_FAILED":              "No se pudo restaurar el producto",
+		"JOB_FETCH_FAILED":            "No se pudo obtener el trabajo",
+		"JOB_START_FAILED":            "No se pudo iniciar el trabajo",
+		"LIST_FAILED":                 "No se pudo obtener el listado",
+		"RETRIEVAL_FAILED":            "No se pudieron obtener los datos",
+		"OPERATION_FAILED":            "No se pudo realizar la operación de stock",
+		"QUOTA_EXCEEDED":              "Se ha superado el cupo de reservas",
+		"OVERLOADED":                  "El servidor está sobrecargado, inténtelo más tarde",
+		"READ_ONLY":                   "El servidor solo admite lecturas en este momento",
+		"MOVEMENT_REJECTED":           "El movimiento de stock fue rechazado",
+		"LOCATION_FROZEN":             "La ubicación está bloqueada por un inventario",
+		"CONFLICT":                    "El recurso fue modificado mientras tanto",
+		"APPROVAL_UNAVAILABLE":        "La aprobación del movimiento de stock no está disponible",
+		"LOCK_TIMEOUT":                "El producto está ocupado por otra operación, inténtelo de nuevo",
+		"NOT_RELEASED":                "El producto aún no está disponible",
+		"RESERVATION_NOT_PENDING":     "La reserva ya no está pendiente",
+		"DUPLICATE_ORDER":             "Ya existe un pedido con este identificador",
+		"DUPLICATE_CHANNEL":           "Ya existe un canal de venta con este nombre",
+		"DUPLICATE_VARIANT":           "Ya existe una variante con estos atributos",
+		"ORDER_NOT_PENDING":           "El pedido ya no está pendiente",
+		"CART_HOLD_NOT_ACTIVE":        "La retención del carrito ya no está activa",
+		"PURCHASE_ORDER_NOT_DRAFT":    "El pedido de compra ya no es un borrador",
+		"STREAM_FULL":                 "Demasiadas suscripciones de inventario en vivo, inténtelo más tarde",
+		"IDEMPOTENCY_KEY_REUSED":      "La clave de idempotencia se usó para otra solicitud",
+		"IDEMPOTENCY_KEY_IN_PROGRESS": "Una solicitud con esta clave de idempotencia sigue en curso",
+		"EXPORT_FAILED":               "No se pudo generar la exportación",
+		"REDACTION_FAILED":            "No se pudo realizar la anonimización",
+		"REPORT_FAILED":               "No se pudo generar el informe",
+		"IMPORT_FAILED":               "No se pudo realizar la importación",
+		"SIMULATION_FAILED":           "No se pudo realizar la simulación",
+		"ROUTING_FAILED":              "No se pudo planificar el envío",
+		"PATCH_TEST_FAILED":           "Una operación de prueba del parche no coincide con el valor actual",
+		"PRECONDITION_FAILED":         "Tras la operación quedaría menos stock disponible del requerido",
+		"UNSUPPORTED_MEDIA_TYPE":      "El tipo de contenido de la solicitud no es compatible",
+		"INVALID_FIELDS":              "Selección de campos no válida",
+		"SYNC_FAILED":                 "No se pudo sincronizar el catálogo",
+		"UNAUTHORIZED":                "Se requiere autenticación",
+		"FORBIDDEN":                   "No tiene permiso para esta acción",
+		"INVALID_TENANT":              "Inquilino no válido",
+		"TENANT_FORBIDDEN":            "No tiene acceso a este inquilino",
+		"INTERNAL_ERROR":              "Se produjo un error inesperado",
+	},
+	"fr": {
+		"METHOD_NOT_ALLOWED":          "Méthode non autorisée pour cette ressource",
+		"INVALID_REQUEST":             "Requête invalide",
+		"INVALID_TIMEZONE":            "Fuseau horaire inconnu",
+		"NOT_FOUND":                   "La ressource demandée est introuvable",
+		"VALIDATION_FAILED":           "Les données envoyées ne sont pas valides",
+		"INSUFFICIENT_STOCK":          "Le stock disponible est insuffisant",
+		"DUPLICATE_SKU":               "Un produit avec ce SKU existe déjà",
+		"CREATION_FAILED":             "Impossible de créer la ressource",
+		"UPDATE_FAILED":               "Impossible de mettre à jour la ressource",
+		"DELETE_FAILED":               "Impossible de supprimer la ressource",
+		"RESTORE_FAILED":              "Impossible de restaurer le produit",
+		"JOB_FETCH_FAILED":            "Impossible de récupérer la tâche",
+		"JOB_START_FAILED":            "Impossible de démarrer la tâche",
+		"LIST_FAILED":                 "Impossible de récupérer la liste",
+		"RETRIEVAL_FAILED":            "Impossible de récupérer les données",
+		"OPERATION_FAILED":            "Impossible d'effectuer l'opération de stock",
+		"QUOTA_EXCEEDED":              "Le quota de réservation est dépassé",
+		"OVERLOADED":                  "Le serveur est surchargé, veuillez réessayer plus tard",
+		"READ_ONLY":                   "Le serveur n'accepte que les lectures pour le moment",
+		"MOVEMENT_REJECTED":           "Le mouvement de stock a été refusé",
+		"LOCATION_FROZEN":             "L'emplacement est gelé pour un inventaire",
+		"CONFLICT":                    "La ressource a été modifiée entre-temps",
+		"APPROVAL_UNAVAILABLE":        "L'approbation du mouvement de stock est indisponible",
+		"LOCK_TIMEOUT":                "Le produit est occupé par une autre opération, veuillez réessayer",
+		"NOT_RELEASED":                "Le produit n'est pas encore disponible",
+		"RESERVATION_NOT_PENDING":     "La réservation n'est plus en attente",
+		"DUPLICATE_ORDER":             "Une commande avec cet identifiant existe déjà",
+		"DUPLICATE_CHANNEL":           "Un canal de vente portant ce nom existe déjà",
+		"DUPLICATE_VARIANT":           "Une variante avec ces attributs existe déjà",
+		"ORDER_NOT_PENDING":           "La commande n'est plus en attente",
+		"CART_HOLD_NOT_ACTIVE":        "La retenue du panier n'est plus active",
+		"PURCHASE_ORDER_NOT_DRAFT":    "Le bon de commande n'est plus un brouillon",
+		"STREAM_FULL":                 "Trop d'abonnements au stock en direct, réessayez plus tard",
+		"IDEMPOTENCY_KEY_REUSED":      "La clé d'idempotence a été utilisée pour une autre requête",
+		"IDEMPOTENCY_KEY_IN_PROGRESS": "Une requête avec cette clé d'idempotence est encore en cours",
+		"EXPORT_FAILED":               "Impossible de générer l'export",
+		"REDACTION_FAILED":            "Impossible d'effectuer l'anonymisation",
+		"REPORT_FAILED":               "Impossible de générer le rapport",
+		"IMPORT_FAILED":               "Impossible d'effectuer l'importation",
+		"SIMULATION_FAILED":           "Impossible d'effectuer la simulation",
+		"ROUTING_FAILED":              "Impossible de planifier l'expédition",
+		"PATCH_TEST_FAILED":           "Une opération de test du correctif ne correspond pas à la valeur actuelle",
+		"PRECONDITION_FAILED":         "Après l'opération, le stock disponible serait inférieur au minimum requis",
+		"UNSUPPORTED_MEDIA_TYPE":      "Le type de contenu de la requête n'est pas pris en charge",
+		"INVALID_FIELDS":              "Sélection de champs invalide",
+		"SYNC_FAILED":                 "Impossible de synchroniser le catalogue",
+		"UNAUTHORIZED":                "Authentification requise",
+		"FORBIDDEN":                   "Vous n'avez pas l'autorisation pour cette action",
+		"INVALID_TENANT":              "Locataire invalide",
+		"TENANT_FORBIDDEN":            "Vous n'avez pas accès à ce locataire",
+		"INTERNAL_ERROR":              "Une erreur inattendue s'est produite",
+	},
+}
+
+// Supported reports whether lang has a message catalog or is the default language
+func Supported(lang string) bool {
+	if lang == DefaultLanguage {
+		return true
+	}
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Translate returns the message for an error code in lang. The second result
+// is false for the default language and for codes without a translation.
+func Translate(lang, code string) (string, bool) {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		return "", false
+	}
+	message, ok := catalog[code]
+	return message, ok
+}
+
+// NormalizeLocale reduces a language tag such as "de-AT" to its lower-case
+// primary subtag ("de"), which is how translations are keyed
+func NormalizeLocale(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
+
+// Negotiate picks the best supported language from an Accept-Language header,
+// honouring q-values, and falls back to DefaultLanguage
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := NormalizeLocale(fields[0])
+		if lang == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if Supported(c.lang) {
+			return c.lang
+		}
+	}
+	return DefaultLanguage
+}
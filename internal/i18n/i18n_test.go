@@ -0,0 +1,38 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT,de;q=0.9,en;q=0.8", "de"},
+		{"fr-CA", "fr"},
+		{"ja,es;q=0.5", "es"},
+		{"en;q=0.4,es;q=0.9", "es"},
+		{"ja,zh", "en"},
+		{"de;q=0,fr;q=0.1", "fr"},
+		{"*", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if msg, ok := Translate("de", "NOT_FOUND"); !ok || msg == "" {
+		t.Error("Expected German translation for NOT_FOUND")
+	}
+	if _, ok := Translate("en", "NOT_FOUND"); ok {
+		t.Error("Default language should not be translated")
+	}
+	if _, ok := Translate("de", "SOME_UNKNOWN_CODE"); ok {
+		t.Error("Unknown codes should not be translated")
+	}
+}
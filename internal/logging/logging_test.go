@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestIDAttribute(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "info")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.With("component", "test").InfoContext(ctx, "hello", "n", 1)
+	logger.Info("no context")
+	logger.DebugContext(ctx, "below level")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %s", len(lines), buf.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(lines[0], &record); err != nil {
+		t.Fatalf("Invalid JSON record: %v", err)
+	}
+	if record["request_id"] != "req-1" || record["component"] != "test" || record["msg"] != "hello" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+
+	record = nil
+	json.Unmarshal(lines[1], &record)
+	if _, ok := record["request_id"]; ok {
+		t.Errorf("Expected no request_id without a request context, got %v", record)
+	}
+}
+
+func TestTraceAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", "info")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3},
+		SpanID:  trace.SpanID{4, 5, 6},
+	})
+	logger.InfoContext(trace.ContextWithSpanContext(context.Background(), span), "traced")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid JSON record: %v", err)
+	}
+	if record["trace_id"] != span.TraceID().String() || record["span_id"] != span.SpanID().String() {
+		t.Errorf("Expected the trace of the context, got %v", record)
+	}
+}
+
+func TestNewRejectsInvalidConfiguration(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if _, err := New(&bytes.Buffer{}, "", "verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+	if _, err := New(&bytes.Buffer{}, "text", "DEBUG"); err != nil {
+		t.Errorf("Expected upper-case levels to be accepted, got %v", err)
+	}
+}
+
+func TestNewWithLevelFollowsLevelChanges(t *testing.T) {
+	var buf bytes.Buffer
+	level := new(slog.LevelVar)
+	logger, err := NewWithLevel(&buf, "text", level)
+	if err != nil {
+		t.Fatalf("NewWithLevel() error = %v", err)
+	}
+
+	logger.Debug("hidden")
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown")
+
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.Contains(out, "shown") {
+		t.Errorf("Expected only the record after the level change, got %q", out)
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresTransactionAnnotationRepository implements
+// TransactionAnnotationRepository using PostgreSQL
+type PostgresTransactionAnnotationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTransactionAnnotationRepository creates a new
+// PostgresTransactionAnnotationRepository
+func NewPostgresTransactionAnnotationRepository(db *sql.DB) *PostgresTransactionAnnotationRepository {
+	return &PostgresTransactionAnnotationRepository{db: db}
+}
+
+// Create appends an annotation to a transaction
+func (r *PostgresTransactionAnnotationRepository) Create(ctx context.Context, annotation *domain.TransactionAnnotation) error {
+	if err := annotation.Validate(); err != nil {
+		return err
+	}
+	annotation.ID = uuid.New().String()
+	annotation.CreatedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO transaction_annotations (id, transaction_id, tenant_id, note, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, annotation.ID, annotation.TransactionID, tenantOwner(ctx), annotation.Note, annotation.CreatedBy, annotation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create transaction annotation: %w", err)
+	}
+	return nil
+}
+
+// ListByTransactionIDs retrieves the annotations of the transactions, oldest
+// first, keyed by transaction ID. Transactions without annotations are
+// absent from the map.
+func (r *PostgresTransactionAnnotationRepository) ListByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*domain.TransactionAnnotation, error) {
+	annotations := make(map[string][]*domain.TransactionAnnotation)
+	if len(transactionIDs) == 0 {
+		return annotations, nil
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, transaction_id, note, created_by, created_at
+		FROM transaction_annotations
+		WHERE transaction_id = ANY($1) AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at, id
+	`, pq.Array(transactionIDs), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transaction annotations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		annotation := &domain.TransactionAnnotation{}
+		if err := rows.Scan(&annotation.ID, &annotation.TransactionID, &annotation.Note, &annotation.CreatedBy, &annotation.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction annotation: %w", err)
+		}
+		annotations[annotation.TransactionID] = append(annotations[annotation.TransactionID], annotation)
+	}
+	return annotations, rows.Err()
+}
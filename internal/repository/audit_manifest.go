@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresAuditManifestRepository implements AuditManifestRepository using
+// PostgreSQL
+type PostgresAuditManifestRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditManifestRepository creates a new
+// PostgresAuditManifestRepository
+func NewPostgresAuditManifestRepository(db *sql.DB) *PostgresAuditManifestRepository {
+	return &PostgresAuditManifestRepository{db: db}
+}
+
+// Create records the manifest of an export made in the tenant scope of ctx
+func (r *PostgresAuditManifestRepository) Create(ctx context.Context, manifest *domain.AuditManifest) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO audit_manifests (id, tenant_id, period_from, period_to, generated_at, row_count, sha256,
+			algorithm, public_key, signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, manifest.ID, tenantScope(ctx), manifest.From.UTC(), manifest.To.UTC(), manifest.GeneratedAt.UTC(),
+		manifest.RowCount, manifest.SHA256, manifest.Algorithm, manifest.PublicKey, manifest.Signature)
+	if err != nil {
+		return fmt.Errorf("failed to create audit manifest: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves the manifest of an export. Manifests of exports of all
+// tenants are only visible without a tenant scope.
+func (r *PostgresAuditManifestRepository) GetByID(ctx context.Context, id string) (*domain.AuditManifest, error) {
+	manifest := &domain.AuditManifest{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, period_from, period_to, generated_at, row_count, sha256, algorithm, public_key, signature
+		FROM audit_manifests
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, id, tenantScope(ctx)).Scan(&manifest.ID, &manifest.From, &manifest.To, &manifest.GeneratedAt, &manifest.RowCount,
+		&manifest.SHA256, &manifest.Algorithm, &manifest.PublicKey, &manifest.Signature)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("audit manifest %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit manifest: %w", err)
+	}
+
+	manifest.From, manifest.To, manifest.GeneratedAt = manifest.From.UTC(), manifest.To.UTC(), manifest.GeneratedAt.UTC()
+	return manifest, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresBackgroundControlRepository implements BackgroundControlRepository
+// using PostgreSQL
+type PostgresBackgroundControlRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresBackgroundControlRepository creates a new
+// PostgresBackgroundControlRepository
+func NewPostgresBackgroundControlRepository(db *sql.DB) *PostgresBackgroundControlRepository {
+	return &PostgresBackgroundControlRepository{db: db}
+}
+
+// Pause records a pause of a component. Pausing a paused component keeps the
+// pause recorded first and fills in the pause with it.
+func (r *PostgresBackgroundControlRepository) Pause(ctx context.Context, pause *domain.BackgroundPause) error {
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO background_pauses (component, paused_by, reason, paused_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (component) DO UPDATE SET component = EXCLUDED.component
+		RETURNING paused_by, reason, paused_at
+	`, pause.Component, pause.PausedBy, pause.Reason, time.Now()).Scan(&pause.PausedBy, &pause.Reason, &pause.PausedAt)
+	if err != nil {
+		return fmt.Errorf("failed to pause %s: %w", pause.Component, err)
+	}
+	return nil
+}
+
+// Resume removes the pause of a component, if any
+func (r *PostgresBackgroundControlRepository) Resume(ctx context.Context, component domain.BackgroundComponent) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM background_pauses WHERE component = $1`, component); err != nil {
+		return fmt.Errorf("failed to resume %s: %w", component, err)
+	}
+	return nil
+}
+
+// ListPauses lists the paused components
+func (r *PostgresBackgroundControlRepository) ListPauses(ctx context.Context) ([]*domain.BackgroundPause, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT component, paused_by, reason, paused_at FROM background_pauses ORDER BY component
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list background pauses: %w", err)
+	}
+	defer rows.Close()
+
+	var pauses []*domain.BackgroundPause
+	for rows.Next() {
+		pause := &domain.BackgroundPause{}
+		if err := rows.Scan(&pause.Component, &pause.PausedBy, &pause.Reason, &pause.PausedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan background pause: %w", err)
+		}
+		pauses = append(pauses, pause)
+	}
+	return pauses, rows.Err()
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresBackorderRepository implements BackorderRepository using PostgreSQL
+type PostgresBackorderRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresBackorderRepository creates a new PostgresBackorderRepository
+func NewPostgresBackorderRepository(db *sql.DB) *PostgresBackorderRepository {
+	return &PostgresBackorderRepository{db: db}
+}
+
+// Create inserts a new backorder
+func (r *PostgresBackorderRepository) Create(ctx context.Context, backorder *domain.Backorder) error {
+	if err := backorder.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	backorder.ID = uuid.New().String()
+	now := time.Now()
+	backorder.CreatedAt = now
+	backorder.UpdatedAt = now
+
+	query := `
+		INSERT INTO backorders (id, product_id, location, quantity, reference, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		backorder.ID, backorder.ProductID, backorder.Location, backorder.Quantity,
+		backorder.Reference, backorder.Status, backorder.CreatedAt, backorder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create backorder: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a backorder by ID
+func (r *PostgresBackorderRepository) GetByID(ctx context.Context, id string) (*domain.Backorder, error) {
+	query := `
+		SELECT id, product_id, location, quantity, reference, status, created_at, updated_at
+		FROM backorders WHERE id = $1
+	`
+
+	backorder := &domain.Backorder{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&backorder.ID, &backorder.ProductID, &backorder.Location, &backorder.Quantity,
+		&backorder.Reference, &backorder.Status, &backorder.CreatedAt, &backorder.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("backorder %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backorder: %w", err)
+	}
+
+	return backorder, nil
+}
+
+// List retrieves backorders newest first, optionally restricted to a product
+// and a status
+func (r *PostgresBackorderRepository) List(ctx context.Context, productID string, status domain.BackorderStatus, limit, offset int) ([]*domain.Backorder, error) {
+	query := `
+		SELECT id, product_id, location, quantity, reference, status, created_at, updated_at
+		FROM backorders
+		WHERE ($1 = '' OR product_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, productID, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backorders: %w", err)
+	}
+	defer rows.Close()
+
+	var backorders []*domain.Backorder
+	for rows.Next() {
+		backorder := &domain.Backorder{}
+		if err := rows.Scan(
+			&backorder.ID, &backorder.ProductID, &backorder.Location, &backorder.Quantity,
+			&backorder.Reference, &backorder.Status, &backorder.CreatedAt, &backorder.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan backorder: %w", err)
+		}
+		backorders = append(backorders, backorder)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating backorders: %w", err)
+	}
+
+	return backorders, nil
+}
+
+// UpdateStatus moves a backorder from one status to another. It reports false
+// when the backorder was no longer in the expected status.
+func (r *PostgresBackorderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.BackorderStatus) (bool, error) {
+	query := `
+		UPDATE backorders
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, to, time.Now(), id, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update backorder status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
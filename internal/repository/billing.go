@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresBillingRepository implements BillingRepository using PostgreSQL
+type PostgresBillingRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresBillingRepository creates a new PostgresBillingRepository
+func NewPostgresBillingRepository(db *sql.DB) *PostgresBillingRepository {
+	return &PostgresBillingRepository{db: db}
+}
+
+// LatestMovementAt returns when the latest receipt, pick or shipment recorded
+// occurred, or the zero time when none was recorded yet
+func (r *PostgresBillingRepository) LatestMovementAt(ctx context.Context) (time.Time, error) {
+	var latest sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT MAX(occurred_at) FROM billing_events WHERE type <> $1
+	`, domain.BillingEventStorage).Scan(&latest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest billing event: %w", err)
+	}
+	return latest.Time, nil
+}
+
+// RecordMovements records the receipts (IN and RETURN transactions) and picks
+// (OUT transactions) of the ledger created at or after since, archived ones
+// included, and a shipment per tenant and reference of the picks. Events
+// recorded before are skipped, so overlapping calls are harmless. It returns
+// the number of events recorded.
+func (r *PostgresBillingRepository) RecordMovements(ctx context.Context, since time.Time) (int64, error) {
+	var recorded int64
+	err := withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO billing_events (tenant_id, type, source_id, quantity, reference, occurred_at)
+			SELECT tenant_id, CASE WHEN type = 'OUT' THEN $2 ELSE $3 END, id, quantity, COALESCE(reference, ''), created_at
+			FROM transactions_all
+			WHERE type IN ('IN', 'RETURN', 'OUT') AND created_at >= $1
+			ON CONFLICT DO NOTHING
+		`, since, domain.BillingEventPick, domain.BillingEventReceipt)
+		if err != nil {
+			return fmt.Errorf("failed to record receipts and picks: %w", err)
+		}
+		if recorded, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO billing_events (tenant_id, type, source_id, quantity, reference, occurred_at)
+			SELECT tenant_id, $2, reference, 1, reference, MIN(created_at)
+			FROM transactions_all
+			WHERE type = 'OUT' AND created_at >= $1 AND COALESCE(reference, '') <> ''
+			GROUP BY tenant_id, reference
+			ON CONFLICT DO NOTHING
+		`, since, domain.BillingEventShipment)
+		if err != nil {
+			return fmt.Errorf("failed to record shipments: %w", err)
+		}
+		shipments, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		recorded += shipments
+		return nil
+	})
+	return recorded, err
+}
+
+// RecordStorage records the pallets every tenant stores on the day, unless
+// they were recorded for the day already. Each inventory row with stock
+// takes up its quantity divided by unitsPerPallet, rounded up, as products
+// and locations do not share pallets. It returns the number of tenants
+// recorded.
+func (r *PostgresBillingRepository) RecordStorage(ctx context.Context, day time.Time, unitsPerPallet int64) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO billing_events (tenant_id, type, source_id, quantity, occurred_at)
+		SELECT tenant_id, $1, $2, SUM(CEIL(quantity::NUMERIC / $3))::BIGINT, $4
+		FROM inventory
+		WHERE quantity > 0
+		GROUP BY tenant_id
+		ON CONFLICT DO NOTHING
+	`, domain.BillingEventStorage, day.Format(time.DateOnly), unitsPerPallet, day)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record storage: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// Billing totals the events that occurred on the days from from to to,
+// inclusive, per tenant. A non-empty tenant restricts the result to it.
+func (r *PostgresBillingRepository) Billing(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantBilling, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT tenant_id, type, COUNT(*), SUM(quantity)
+		FROM billing_events
+		WHERE occurred_at >= $1 AND occurred_at < $2
+			AND ($3 = '' OR tenant_id = $3) AND ($4 = '' OR tenant_id = $4)
+		GROUP BY tenant_id, type
+		ORDER BY tenant_id, type
+	`, from, to.AddDate(0, 0, 1), tenant, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query billing: %w", err)
+	}
+	defer rows.Close()
+
+	var billing []*domain.TenantBilling
+	for rows.Next() {
+		var (
+			tenantID         string
+			eventType        domain.BillingEventType
+			events, quantity int64
+		)
+		if err := rows.Scan(&tenantID, &eventType, &events, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan billing: %w", err)
+		}
+		if len(billing) == 0 || billing[len(billing)-1].TenantID != tenantID {
+			billing = append(billing, &domain.TenantBilling{TenantID: tenantID})
+		}
+		billing[len(billing)-1].Add(eventType, events, quantity)
+	}
+	return billing, rows.Err()
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/lib/pq"
+)
+
+// PostgresBulkReadRepository implements BulkReadRepository using PostgreSQL
+type PostgresBulkReadRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresBulkReadRepository creates a new PostgresBulkReadRepository
+func NewPostgresBulkReadRepository(db *sql.DB) *PostgresBulkReadRepository {
+	return &PostgresBulkReadRepository{db: db}
+}
+
+// AvailabilityBySKUs sums the stock of the active products with the given
+// SKUs over all their locations. However many SKUs are asked for, this is a
+// single query on a single connection. Unknown and archived SKUs are left out.
+func (r *PostgresBulkReadRepository) AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error) {
+	query := `
+		SELECT p.sku, p.id,
+			COALESCE(SUM(i.quantity), 0),
+			COALESCE(SUM(i.reserved), 0),
+			COALESCE(SUM(GREATEST(i.quantity - i.reserved, 0)), 0)
+		FROM products p
+		LEFT JOIN inventory i ON i.product_id = p.id
+		WHERE p.sku = ANY($1) AND p.deleted_at IS NULL AND ($2 = '' OR p.tenant_id = $2)
+		GROUP BY p.id, p.sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(skus), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read availability: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*domain.SKUAvailability
+	for rows.Next() {
+		item := &domain.SKUAvailability{}
+		if err := rows.Scan(&item.SKU, &item.ProductID, &item.Quantity, &item.Reserved, &item.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan availability: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating availability: %w", err)
+	}
+
+	return items, nil
+}
+
+// ExportProducts reads the next page of a catalog export: up to limit
+// products matching filter with IDs after afterID, in ID order, each with its
+// stock at every location. Paging by ID keeps every page a short index range
+// scan however far into the catalog the export is.
+func (r *PostgresBulkReadRepository) ExportProducts(ctx context.Context, filter ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error) {
+	query := `
+		WITH page AS (
+			SELECT p.id, p.sku, p.name, COALESCE(p.category, '') AS category, p.price, p.deleted_at IS NOT NULL AS archived,
+				p.updated_at
+			FROM products p
+			WHERE p.id > $1 AND ($2 = '' OR p.category = $2) AND ($3::timestamp IS NULL OR p.updated_at >= $3)
+				AND ($4 OR p.deleted_at IS NULL) AND ($5 = '' OR p.tenant_id = $5)
+			ORDER BY p.id
+			LIMIT $6
+		)
+		SELECT page.*, i.location, i.condition, i.quantity, i.reserved, GREATEST(i.quantity - i.reserved, 0)
+		FROM page
+		LEFT JOIN inventory i ON i.product_id = page.id
+		ORDER BY page.id, i.location, i.condition
+	`
+
+	var updatedSince *time.Time
+	if !filter.UpdatedSince.IsZero() {
+		updatedSince = &filter.UpdatedSince
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, filter.Category, updatedSince, filter.IncludeArchived, tenantScope(ctx), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.ProductExport
+	for rows.Next() {
+		product := &domain.ProductExport{Locations: []*domain.ProductExportStock{}}
+		var location, condition sql.NullString
+		var quantity, reserved, available sql.NullInt64
+		if err := rows.Scan(
+			&product.ProductID, &product.SKU, &product.Name, &product.Category, &product.Price, &product.Archived,
+			&product.UpdatedAt, &location, &condition, &quantity, &reserved, &available,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan product export: %w", err)
+		}
+
+		if n := len(products); n > 0 && products[n-1].ProductID == product.ProductID {
+			product = products[n-1]
+		} else {
+			products = append(products, product)
+		}
+		if location.Valid {
+			product.AddStock(&domain.ProductExportStock{
+				Location:  location.String,
+				Condition: domain.StockCondition(condition.String),
+				Quantity:  quantity.Int64,
+				Reserved:  reserved.Int64,
+				Available: available.Int64,
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product export: %w", err)
+	}
+
+	return products, nil
+}
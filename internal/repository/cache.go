@@ -0,0 +1,424 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// CacheConfig configures the in-process read cache of the cached
+// repositories: at most Size entries, each served for at most TTL
+type CacheConfig struct {
+	Size int
+	TTL  time.Duration
+}
+
+// lruCache is a size-bounded least-recently-used cache whose entries expire
+// after a TTL. It is safe for concurrent use.
+//
+// Every invalidation bumps a generation. A reader takes the generation
+// before querying the database and only stores the result if no
+// invalidation happened meanwhile, so a slow read cannot put back a value
+// that a concurrent write just invalidated.
+type lruCache[V any] struct {
+	size int
+	ttl  time.Duration
+	// aliasesOf, if set, returns further keys a value can be invalidated by
+	aliasesOf func(V) []string
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	aliases    map[string]string
+	order      *list.List
+	generation uint64
+}
+
+type lruEntry[V any] struct {
+	key     string
+	value   V
+	aliases []string
+	expires time.Time
+}
+
+func newLRUCache[V any](config CacheConfig) *lruCache[V] {
+	return &lruCache[V]{
+		size:    config.Size,
+		ttl:     config.TTL,
+		entries: make(map[string]*list.Element),
+		aliases: make(map[string]string),
+		order:   list.New(),
+	}
+}
+
+// get returns the live value of key
+func (c *lruCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[V])
+	if time.Now().After(entry.expires) {
+		c.remove(elem)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// begin returns the generation to pass to set for a read starting now
+func (c *lruCache[V]) begin() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// set stores value under key unless the cache was invalidated since the
+// read of value began
+func (c *lruCache[V]) set(key string, value V, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	entry := &lruEntry[V]{key: key, value: value, expires: time.Now().Add(c.ttl)}
+	if c.aliasesOf != nil {
+		entry.aliases = c.aliasesOf(value)
+		for _, alias := range entry.aliases {
+			c.aliases[alias] = key
+		}
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidate drops keys, or every entry without keys
+func (c *lruCache[V]) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if len(keys) == 0 {
+		for c.order.Len() > 0 {
+			c.remove(c.order.Back())
+		}
+		return
+	}
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// invalidateAliases drops the entries of aliases
+func (c *lruCache[V]) invalidateAliases(aliases ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, alias := range aliases {
+		if key, ok := c.aliases[alias]; ok {
+			c.remove(c.entries[key])
+		}
+	}
+}
+
+// remove drops an entry and its aliases; c.mu must be held
+func (c *lruCache[V]) remove(elem *list.Element) {
+	entry := elem.Value.(*lruEntry[V])
+	c.order.Remove(elem)
+	delete(c.entries, entry.key)
+	for _, alias := range entry.aliases {
+		if c.aliases[alias] == entry.key {
+			delete(c.aliases, alias)
+		}
+	}
+}
+
+// invalidateWrite drops keys right after a write and again once the unit of
+// work of ctx committed, so that reads between the write and the commit
+// cannot keep the value from before the write cached
+func (c *lruCache[V]) invalidateWrite(ctx context.Context, keys ...string) {
+	c.invalidate(keys...)
+	afterCommit(ctx, func() { c.invalidate(keys...) })
+}
+
+// invalidateAliasesWrite is invalidateWrite for aliases
+func (c *lruCache[V]) invalidateAliasesWrite(ctx context.Context, aliases ...string) {
+	c.invalidateAliases(aliases...)
+	afterCommit(ctx, func() { c.invalidateAliases(aliases...) })
+}
+
+// CachedProductRepository is a ProductRepository that serves product reads
+// by ID and SKU from an in-process cache. Every product write through it
+// invalidates the cache; writes by other instances are seen after the TTL.
+// Cached products of other tenants than the one of the read are not found.
+type CachedProductRepository struct {
+	ProductRepository
+	byID  *lruCache[*domain.Product]
+	bySKU *lruCache[*domain.Product]
+}
+
+// NewCachedProductRepository wraps repo with a read cache
+func NewCachedProductRepository(repo ProductRepository, config CacheConfig) *CachedProductRepository {
+	return &CachedProductRepository{
+		ProductRepository: repo,
+		byID:              newLRUCache[*domain.Product](config),
+		bySKU:             newLRUCache[*domain.Product](config),
+	}
+}
+
+// GetByID retrieves a product by ID, from the cache outside of a unit of work
+func (r *CachedProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	product, err := cachedRead(ctx, r.byID, id, cloneProduct, func() (*domain.Product, bool, error) {
+		product, err := r.ProductRepository.GetByID(ctx, id)
+		return product, product != nil, err
+	})
+	if err == nil && !inTenantScope(ctx, product.TenantID) {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	return product, err
+}
+
+// GetBySKU retrieves a product by SKU, from the cache outside of a unit of work
+func (r *CachedProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	// SKUs are unique per tenant only
+	key := tenantScope(ctx) + "/" + sku
+	return cachedRead(ctx, r.bySKU, key, cloneProduct, func() (*domain.Product, bool, error) {
+		product, err := r.ProductRepository.GetBySKU(ctx, sku)
+		return product, product != nil, err
+	})
+}
+
+// Update updates a product and invalidates its cached reads
+func (r *CachedProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	defer r.invalidate(ctx, product.ID)
+	return r.ProductRepository.Update(ctx, product)
+}
+
+// Patch partially updates a product and invalidates its cached reads
+func (r *CachedProductRepository) Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	defer r.invalidate(ctx, id)
+	return r.ProductRepository.Patch(ctx, id, patch)
+}
+
+// Delete deletes a product and invalidates its cached reads
+func (r *CachedProductRepository) Delete(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.ProductRepository.Delete(ctx, id)
+}
+
+// Archive archives a product and invalidates its cached reads
+func (r *CachedProductRepository) Archive(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.ProductRepository.Archive(ctx, id)
+}
+
+// Restore restores a product and invalidates its cached reads
+func (r *CachedProductRepository) Restore(ctx context.Context, id string) error {
+	defer r.invalidate(ctx, id)
+	return r.ProductRepository.Restore(ctx, id)
+}
+
+// SetReleaseAt schedules or cancels the drop of a product and invalidates
+// its cached reads
+func (r *CachedProductRepository) SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error {
+	defer r.invalidate(ctx, id)
+	return r.ProductRepository.SetReleaseAt(ctx, id, releaseAt)
+}
+
+// ReleaseDue releases the drops due by now and invalidates the cached reads
+// of their products
+func (r *CachedProductRepository) ReleaseDue(ctx context.Context, now time.Time) ([]string, error) {
+	ids, err := r.ProductRepository.ReleaseDue(ctx, now)
+	for _, id := range ids {
+		r.invalidate(ctx, id)
+	}
+	return ids, err
+}
+
+// invalidate drops the product by ID and all products by SKU, as the SKU the
+// product was cached under before the write is not known
+func (r *CachedProductRepository) invalidate(ctx context.Context, id string) {
+	r.byID.invalidateWrite(ctx, id)
+	r.bySKU.invalidateWrite(ctx)
+}
+
+// CachedInventoryRepository is an InventoryRepository that serves the
+// inventory reads of a product from an in-process cache of its inventory
+// items. Every inventory write through it invalidates the product's items;
+// writes by other instances are seen after the TTL. Cached items of other
+// tenants than the one of the read are not found.
+type CachedInventoryRepository struct {
+	InventoryRepository
+	// byProduct caches the items of a product, aliased by item ID for
+	// writes that only know the item
+	byProduct *lruCache[[]*domain.InventoryItem]
+}
+
+// NewCachedInventoryRepository wraps repo with a read cache
+func NewCachedInventoryRepository(repo InventoryRepository, config CacheConfig) *CachedInventoryRepository {
+	byProduct := newLRUCache[[]*domain.InventoryItem](config)
+	byProduct.aliasesOf = func(items []*domain.InventoryItem) []string {
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		return ids
+	}
+	return &CachedInventoryRepository{InventoryRepository: repo, byProduct: byProduct}
+}
+
+// ListByProductID retrieves the inventory items of a product at all
+// locations, from the cache outside of a unit of work
+func (r *CachedInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
+	items, err := cachedRead(ctx, r.byProduct, productID, cloneInventoryItems, func() ([]*domain.InventoryItem, bool, error) {
+		items, err := r.InventoryRepository.ListByProductID(ctx, productID)
+		// Products without inventory are not cached, so that stock created
+		// for them by other repositories is seen at once
+		return items, len(items) > 0, err
+	})
+	// The items of a product all belong to its tenant
+	if err == nil && len(items) > 0 && !inTenantScope(ctx, items[0].TenantID) {
+		return nil, nil
+	}
+	return items, err
+}
+
+// GetByProductID retrieves the default inventory item of a product
+func (r *CachedInventoryRepository) GetByProductID(ctx context.Context, productID string) (*domain.InventoryItem, error) {
+	if InTransaction(ctx) {
+		return r.InventoryRepository.GetByProductID(ctx, productID)
+	}
+	items, err := r.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	return items[0], nil
+}
+
+// GetByProductAndLocation retrieves the inventory item of a product at a
+// location in a condition
+func (r *CachedInventoryRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	if InTransaction(ctx) {
+		return r.InventoryRepository.GetByProductAndLocation(ctx, productID, location, condition)
+	}
+	items, err := r.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.Location == location && item.Condition == condition {
+			return item, nil
+		}
+	}
+	return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+}
+
+// Create creates an inventory item and invalidates its product's items
+func (r *CachedInventoryRepository) Create(ctx context.Context, item *domain.InventoryItem) error {
+	defer r.byProduct.invalidateWrite(ctx, item.ProductID)
+	return r.InventoryRepository.Create(ctx, item)
+}
+
+// Update updates an inventory item and invalidates its product's items
+func (r *CachedInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
+	defer r.byProduct.invalidateWrite(ctx, item.ProductID)
+	return r.InventoryRepository.Update(ctx, item)
+}
+
+// UpdateSettings updates the settings of an inventory item and invalidates
+// its product's items
+func (r *CachedInventoryRepository) UpdateSettings(ctx context.Context, item *domain.InventoryItem) error {
+	defer r.byProduct.invalidateWrite(ctx, item.ProductID)
+	return r.InventoryRepository.UpdateSettings(ctx, item)
+}
+
+// Delete deletes an inventory item and invalidates its product's items
+func (r *CachedInventoryRepository) Delete(ctx context.Context, id string) error {
+	defer r.byProduct.invalidateAliasesWrite(ctx, id)
+	return r.InventoryRepository.Delete(ctx, id)
+}
+
+// UpdateQuantity updates the quantities of an inventory item and
+// invalidates its product's items
+func (r *CachedInventoryRepository) UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
+	defer r.byProduct.invalidateAliasesWrite(ctx, inventoryID)
+	return r.InventoryRepository.UpdateQuantity(ctx, inventoryID, quantityDelta, reservedDelta)
+}
+
+// Transfer moves quantity between inventory items and invalidates their
+// products' items
+func (r *CachedInventoryRepository) Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error {
+	defer r.byProduct.invalidateAliasesWrite(ctx, fromID, toID)
+	return r.InventoryRepository.Transfer(ctx, fromID, toID, quantity, out, in)
+}
+
+// ApplyBatch applies a stock batch and invalidates the items of every
+// product it touches
+func (r *CachedInventoryRepository) ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error {
+	ids := make([]string, len(changes))
+	for i, change := range changes {
+		ids[i] = change.InventoryID
+	}
+	defer r.byProduct.invalidateAliasesWrite(ctx, ids...)
+	return r.InventoryRepository.ApplyBatch(ctx, changes)
+}
+
+// cachedRead returns a copy of the cached value of key, or reads it and
+// caches it if read reports it as cacheable. Reads in a unit of work bypass
+// the cache, so that they see the unit's own writes and the latest committed
+// state, and so do reads that must see a given write, which may have been
+// made through another instance.
+func cachedRead[V any](ctx context.Context, cache *lruCache[V], key string, clone func(V) V, read func() (V, bool, error)) (V, error) {
+	if _, ok := ReadAfter(ctx); InTransaction(ctx) || ok {
+		value, _, err := read()
+		return value, err
+	}
+
+	if value, ok := cache.get(key); ok {
+		return clone(value), nil
+	}
+
+	generation := cache.begin()
+	value, cacheable, err := read()
+	if err == nil && cacheable {
+		cache.set(key, clone(value), generation)
+	}
+	return value, err
+}
+
+func cloneProduct(product *domain.Product) *domain.Product {
+	clone := *product
+	if product.DeletedAt != nil {
+		deletedAt := *product.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
+	return &clone
+}
+
+func cloneInventoryItems(items []*domain.InventoryItem) []*domain.InventoryItem {
+	clones := make([]*domain.InventoryItem, len(items))
+	for i, item := range items {
+		clone := *item
+		clones[i] = &clone
+	}
+	return clones
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresCartHoldRepository implements CartHoldRepository using PostgreSQL
+type PostgresCartHoldRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCartHoldRepository creates a new PostgresCartHoldRepository
+func NewPostgresCartHoldRepository(db *sql.DB) *PostgresCartHoldRepository {
+	return &PostgresCartHoldRepository{db: db}
+}
+
+// Create records a hold if every line fits the stock that is neither
+// reserved nor kept by other active holds, and returns ErrInsufficientStock
+// otherwise. The inventory rows of the lines are locked while checking so
+// that concurrent holds cannot take the same units.
+func (r *PostgresCartHoldRepository) Create(ctx context.Context, hold *domain.CartHold) error {
+	if err := hold.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	ids := make([]string, 0, len(hold.Lines))
+	needed := make(map[string]int64, len(hold.Lines))
+	for _, line := range hold.Lines {
+		if _, ok := needed[line.InventoryID]; !ok {
+			ids = append(ids, line.InventoryID)
+		}
+		needed[line.InventoryID] += line.Quantity
+	}
+
+	hold.ID = uuid.New().String()
+	hold.TenantID = tenantOwner(ctx)
+	now := time.Now()
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		// Lock in a fixed order so that concurrent holds cannot deadlock
+		available, err := queryQuantities(ctx, tx, `
+			SELECT id, quantity - reserved FROM inventory
+			WHERE id = ANY($1) AND ($2 = '' OR tenant_id = $2)
+			ORDER BY id FOR UPDATE
+		`, pq.Array(ids), tenantScope(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to lock inventory items: %w", err)
+		}
+
+		held, err := queryQuantities(ctx, tx, `
+			SELECT l.inventory_id, SUM(l.quantity)
+			FROM cart_hold_lines l
+			JOIN cart_holds h ON h.id = l.hold_id
+			WHERE l.inventory_id = ANY($1) AND h.status = $2 AND h.expires_at > $3
+			GROUP BY l.inventory_id
+		`, pq.Array(ids), domain.CartHoldStatusHeld, now)
+		if err != nil {
+			return fmt.Errorf("failed to sum held stock: %w", err)
+		}
+
+		for _, line := range hold.Lines {
+			if available[line.InventoryID]-held[line.InventoryID] < needed[line.InventoryID] {
+				return fmt.Errorf("%w to hold %s", domain.ErrInsufficientStock, line.SKU)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO cart_holds (id, reference, status, expires_at, created_at, updated_at, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, hold.ID, hold.Reference, hold.Status, hold.ExpiresAt, hold.CreatedAt, hold.UpdatedAt, hold.TenantID)
+		if err != nil {
+			return fmt.Errorf("failed to create cart hold: %w", err)
+		}
+
+		for i, line := range hold.Lines {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO cart_hold_lines (hold_id, line_no, sku, product_id, inventory_id, location, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`, hold.ID, i+1, line.SKU, line.ProductID, line.InventoryID, line.Location, line.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to create cart hold line %d: %w", i+1, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves a cart hold with its lines
+func (r *PostgresCartHoldRepository) GetByID(ctx context.Context, id string) (*domain.CartHold, error) {
+	query := `
+		SELECT id, tenant_id, reference, status, expires_at, created_at, updated_at
+		FROM cart_holds WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`
+
+	hold := &domain.CartHold{}
+	err := r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)).Scan(
+		&hold.ID, &hold.TenantID, &hold.Reference, &hold.Status, &hold.ExpiresAt, &hold.CreatedAt, &hold.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cart hold %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart hold: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT sku, product_id, inventory_id, location, quantity
+		FROM cart_hold_lines WHERE hold_id = $1 ORDER BY line_no
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart hold lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line domain.CartHoldLine
+		if err := rows.Scan(&line.SKU, &line.ProductID, &line.InventoryID, &line.Location, &line.Quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan cart hold line: %w", err)
+		}
+		hold.Lines = append(hold.Lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cart hold lines: %w", err)
+	}
+
+	return hold, nil
+}
+
+// Claim moves a hold that is still active at now to the given status. It
+// reports false when the hold was no longer held or had expired.
+func (r *PostgresCartHoldRepository) Claim(ctx context.Context, id string, to domain.CartHoldStatus, now time.Time) (bool, error) {
+	query := `
+		UPDATE cart_holds
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND expires_at > $2 AND ($5 = '' OR tenant_id = $5)
+	`
+
+	return r.update(ctx, query, to, now, id, domain.CartHoldStatusHeld, tenantScope(ctx))
+}
+
+// UpdateStatus moves a hold from one status to another. It reports false
+// when the hold was no longer in the expected status.
+func (r *PostgresCartHoldRepository) UpdateStatus(ctx context.Context, id string, from, to domain.CartHoldStatus) (bool, error) {
+	query := `
+		UPDATE cart_holds
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND ($5 = '' OR tenant_id = $5)
+	`
+
+	return r.update(ctx, query, to, time.Now(), id, from, tenantScope(ctx))
+}
+
+// ExpireHeld marks the holds whose expiry time has passed as expired and
+// returns how many there were
+func (r *PostgresCartHoldRepository) ExpireHeld(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		UPDATE cart_holds
+		SET status = $1, updated_at = $2
+		WHERE status = $3 AND expires_at <= $2 AND ($4 = '' OR tenant_id = $4)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, domain.CartHoldStatusExpired, now, domain.CartHoldStatusHeld, tenantScope(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire cart holds: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// update runs a status update and reports whether it matched the hold
+func (r *PostgresCartHoldRepository) update(ctx context.Context, query string, args ...any) (bool, error) {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update cart hold status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// queryQuantities runs a query returning inventory IDs with a quantity each
+func queryQuantities(ctx context.Context, tx dbtx, query string, args ...any) (map[string]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quantities := make(map[string]int64)
+	for rows.Next() {
+		var id string
+		var quantity int64
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, err
+		}
+		quantities[id] = quantity
+	}
+
+	return quantities, rows.Err()
+}
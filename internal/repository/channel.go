@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresSalesChannelRepository implements SalesChannelRepository using PostgreSQL
+type PostgresSalesChannelRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSalesChannelRepository creates a new PostgresSalesChannelRepository
+func NewPostgresSalesChannelRepository(db *sql.DB) *PostgresSalesChannelRepository {
+	return &PostgresSalesChannelRepository{db: db}
+}
+
+// Create inserts a new sales channel. It returns ErrDuplicateChannel when the
+// tenant already has a channel of the name.
+func (r *PostgresSalesChannelRepository) Create(ctx context.Context, channel *domain.SalesChannel) error {
+	if err := channel.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	channel.ID = uuid.New().String()
+	channel.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sales_channels (id, tenant_id, name, type, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, channel.ID, tenantOwner(ctx), channel.Name, channel.Type, channel.CreatedAt)
+	if isUniqueViolation(err, "sales_channels_tenant_id_name_key") {
+		return domain.ErrDuplicateChannel
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create sales channel: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a sales channel by ID
+func (r *PostgresSalesChannelRepository) GetByID(ctx context.Context, id string) (*domain.SalesChannel, error) {
+	channel := &domain.SalesChannel{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, name, type, created_at
+		FROM sales_channels WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, id, tenantScope(ctx)).Scan(&channel.ID, &channel.Name, &channel.Type, &channel.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sales channel %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales channel: %w", err)
+	}
+	return channel, nil
+}
+
+// List retrieves all sales channels ordered by name
+func (r *PostgresSalesChannelRepository) List(ctx context.Context) ([]*domain.SalesChannel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, type, created_at
+		FROM sales_channels
+		WHERE ($1 = '' OR tenant_id = $1)
+		ORDER BY name, id
+	`, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []*domain.SalesChannel
+	for rows.Next() {
+		channel := &domain.SalesChannel{}
+		if err := rows.Scan(&channel.ID, &channel.Name, &channel.Type, &channel.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sales channel: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sales channels: %w", err)
+	}
+
+	return channels, nil
+}
+
+// SetAllocation creates or replaces the allocation of a product to a channel
+func (r *PostgresSalesChannelRepository) SetAllocation(ctx context.Context, allocation *domain.ChannelAllocation) error {
+	if err := allocation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	allocation.UpdatedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO channel_allocations (channel_id, product_id, percent, quantity, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (channel_id, product_id)
+		DO UPDATE SET percent = EXCLUDED.percent, quantity = EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+	`, allocation.ChannelID, allocation.ProductID, allocation.Percent, allocation.Quantity, allocation.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set channel allocation: %w", err)
+	}
+	return nil
+}
+
+// GetAllocation retrieves the allocation of a product to a channel, or nil
+// when the product is not allocated
+func (r *PostgresSalesChannelRepository) GetAllocation(ctx context.Context, channelID, productID string) (*domain.ChannelAllocation, error) {
+	allocation, err := scanChannelAllocation(conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT channel_id, product_id, percent, quantity, updated_at
+		FROM channel_allocations WHERE channel_id = $1 AND product_id = $2
+	`, channelID, productID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel allocation: %w", err)
+	}
+	return allocation, nil
+}
+
+// ListAllocations retrieves the allocations of a channel ordered by product
+func (r *PostgresSalesChannelRepository) ListAllocations(ctx context.Context, channelID string) ([]*domain.ChannelAllocation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT channel_id, product_id, percent, quantity, updated_at
+		FROM channel_allocations WHERE channel_id = $1
+		ORDER BY product_id
+	`, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channel allocations: %w", err)
+	}
+	defer rows.Close()
+
+	var allocations []*domain.ChannelAllocation
+	for rows.Next() {
+		allocation, err := scanChannelAllocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan channel allocation: %w", err)
+		}
+		allocations = append(allocations, allocation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating channel allocations: %w", err)
+	}
+
+	return allocations, nil
+}
+
+// DeleteAllocation removes the allocation of a product to a channel
+func (r *PostgresSalesChannelRepository) DeleteAllocation(ctx context.Context, channelID, productID string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM channel_allocations WHERE channel_id = $1 AND product_id = $2
+	`, channelID, productID)
+	if err != nil {
+		return fmt.Errorf("failed to delete channel allocation: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("channel allocation %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+// scanChannelAllocation scans one channel allocation row
+func scanChannelAllocation(row interface{ Scan(...any) error }) (*domain.ChannelAllocation, error) {
+	allocation := &domain.ChannelAllocation{}
+	var percent sql.NullFloat64
+	var quantity sql.NullInt64
+	if err := row.Scan(&allocation.ChannelID, &allocation.ProductID, &percent, &quantity, &allocation.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if percent.Valid {
+		allocation.Percent = &percent.Float64
+	}
+	if quantity.Valid {
+		allocation.Quantity = &quantity.Int64
+	}
+	return allocation, nil
+}
+
+// RecordEvent stores a change of a channel allocation
+func (r *PostgresSalesChannelRepository) RecordEvent(ctx context.Context, event *domain.ChannelAllocationEvent) error {
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO channel_allocation_events
+			(id, channel_id, product_id, change, previous_percent, previous_quantity, percent, quantity, notes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, event.ID, event.ChannelID, event.ProductID, event.Change, event.PreviousPercent, event.PreviousQuantity,
+		event.Percent, event.Quantity, event.Notes, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record channel allocation event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents retrieves a page of the allocation changes of a channel, newest first
+func (r *PostgresSalesChannelRepository) ListEvents(ctx context.Context, channelID string, limit, offset int) ([]*domain.ChannelAllocationEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, channel_id, product_id, change, previous_percent, previous_quantity, percent, quantity, notes, created_at
+		FROM channel_allocation_events
+		WHERE channel_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, channelID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channel allocation events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.ChannelAllocationEvent
+	for rows.Next() {
+		event := &domain.ChannelAllocationEvent{}
+		var previousPercent, percent sql.NullFloat64
+		var previousQuantity, quantity sql.NullInt64
+		if err := rows.Scan(
+			&event.ID, &event.ChannelID, &event.ProductID, &event.Change, &previousPercent, &previousQuantity,
+			&percent, &quantity, &event.Notes, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan channel allocation event: %w", err)
+		}
+		if previousPercent.Valid {
+			event.PreviousPercent = &previousPercent.Float64
+		}
+		if previousQuantity.Valid {
+			event.PreviousQuantity = &previousQuantity.Int64
+		}
+		if percent.Valid {
+			event.Percent = &percent.Float64
+		}
+		if quantity.Valid {
+			event.Quantity = &quantity.Int64
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating channel allocation events: %w", err)
+	}
+
+	return events, nil
+}
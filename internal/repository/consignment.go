@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresConsignmentRepository implements ConsignmentRepository using
+// PostgreSQL
+type PostgresConsignmentRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresConsignmentRepository creates a new PostgresConsignmentRepository
+func NewPostgresConsignmentRepository(db *sql.DB) *PostgresConsignmentRepository {
+	return &PostgresConsignmentRepository{db: db}
+}
+
+// consignmentColumns are the columns scanned by scanConsignment
+const consignmentColumns = `id, supplier, product_id, location, unit_cost, quantity, remaining, reference, received_at`
+
+// ownershipTransferColumns are the columns scanned by scanOwnershipTransfer
+const ownershipTransferColumns = `id, consignment_id, supplier, product_id, location, quantity, unit_cost, amount,
+	sale_event_id, sold_at, created_at`
+
+// Create records a consignment lot
+func (r *PostgresConsignmentRepository) Create(ctx context.Context, consignment *domain.Consignment) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO consignments (id, tenant_id, supplier, product_id, location, unit_cost, quantity, remaining, reference, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, consignment.ID, tenantOwner(ctx), consignment.Supplier, consignment.ProductID, consignment.Location, consignment.UnitCost,
+		consignment.Quantity, consignment.Remaining, consignment.Reference, consignment.ReceivedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create consignment: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a consignment lot
+func (r *PostgresConsignmentRepository) GetByID(ctx context.Context, id string) (*domain.Consignment, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+consignmentColumns+`
+		FROM consignments
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, id, tenantScope(ctx))
+
+	consignment, err := scanConsignment(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("consignment %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consignment: %w", err)
+	}
+	return consignment, nil
+}
+
+// List retrieves the consignment lots matching a filter, newest first
+func (r *PostgresConsignmentRepository) List(ctx context.Context, filter domain.ConsignmentFilter, limit, offset int) ([]*domain.Consignment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+consignmentColumns+`
+		FROM consignments
+		WHERE ($1 = '' OR tenant_id = $1)
+			AND ($2 = '' OR supplier = $2)
+			AND ($3 = '' OR product_id = $3)
+			AND ($4 = '' OR location = $4)
+			AND (NOT $5 OR remaining > 0)
+		ORDER BY received_at DESC, id DESC
+		LIMIT $6 OFFSET $7
+	`, tenantScope(ctx), filter.Supplier, filter.ProductID, filter.Location, filter.Open, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consignments: %w", err)
+	}
+	return scanConsignments(rows)
+}
+
+// LockOpen retrieves the lots of a product at a location the supplier still
+// owns units of, oldest first, locking them until the unit of work of ctx
+// ends so that concurrent sales take each unit once
+func (r *PostgresConsignmentRepository) LockOpen(ctx context.Context, productID, location string) ([]*domain.Consignment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+consignmentColumns+`
+		FROM consignments
+		WHERE product_id = $1 AND location = $2 AND remaining > 0 AND ($3 = '' OR tenant_id = $3)
+		ORDER BY received_at, id
+		FOR UPDATE
+	`, productID, location, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock consignments: %w", err)
+	}
+	return scanConsignments(rows)
+}
+
+// RecordTransfer takes the units of an ownership transfer off its lot and
+// records the transfer with the payable it creates
+func (r *PostgresConsignmentRepository) RecordTransfer(ctx context.Context, transfer *domain.OwnershipTransfer, payable *domain.SupplierPayable) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE consignments SET remaining = remaining - $2 WHERE id = $1 AND remaining >= $2
+		`, transfer.ConsignmentID, transfer.Quantity)
+		if err != nil {
+			return fmt.Errorf("failed to update consignment: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("consignment %s has fewer than %d units left: %w", transfer.ConsignmentID, transfer.Quantity, domain.ErrConflict)
+		}
+
+		tenant := tenantOwner(ctx)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO consignment_ownership_transfers (id, consignment_id, tenant_id, supplier, product_id, location,
+				quantity, unit_cost, amount, sale_event_id, sold_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		`, transfer.ID, transfer.ConsignmentID, tenant, transfer.Supplier, transfer.ProductID, transfer.Location,
+			transfer.Quantity, transfer.UnitCost, transfer.Amount, transfer.SaleEventID, transfer.SoldAt.UTC(), transfer.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create ownership transfer: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO supplier_payables (id, transfer_id, tenant_id, supplier, amount, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, payable.ID, payable.TransferID, tenant, payable.Supplier, payable.Amount, payable.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create supplier payable: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListTransfers retrieves the ownership transfers of a lot, oldest first
+func (r *PostgresConsignmentRepository) ListTransfers(ctx context.Context, consignmentID string, limit, offset int) ([]*domain.OwnershipTransfer, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+ownershipTransferColumns+`
+		FROM consignment_ownership_transfers
+		WHERE consignment_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at, id
+		LIMIT $3 OFFSET $4
+	`, consignmentID, tenantScope(ctx), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ownership transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []*domain.OwnershipTransfer
+	for rows.Next() {
+		transfer := &domain.OwnershipTransfer{}
+		err := rows.Scan(
+			&transfer.ID, &transfer.ConsignmentID, &transfer.Supplier, &transfer.ProductID, &transfer.Location,
+			&transfer.Quantity, &transfer.UnitCost, &transfer.Amount, &transfer.SaleEventID, &transfer.SoldAt, &transfer.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ownership transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ownership transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// Settlement totals the payables recorded on the days from from to to,
+// inclusive, per supplier, product and location. A non-empty supplier
+// restricts the result to it.
+func (r *PostgresConsignmentRepository) Settlement(ctx context.Context, supplier string, from, to time.Time) ([]*domain.SupplierSettlement, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT p.supplier, t.product_id, t.location, COUNT(*), SUM(t.quantity), SUM(p.amount)
+		FROM supplier_payables p
+		JOIN consignment_ownership_transfers t ON t.id = p.transfer_id
+		WHERE p.created_at >= $1 AND p.created_at < $2
+			AND ($3 = '' OR p.supplier = $3) AND ($4 = '' OR p.tenant_id = $4)
+		GROUP BY p.supplier, t.product_id, t.location
+		ORDER BY p.supplier, t.product_id, t.location
+	`, from, to.AddDate(0, 0, 1), supplier, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consignment settlement: %w", err)
+	}
+	defer rows.Close()
+
+	var settlements []*domain.SupplierSettlement
+	for rows.Next() {
+		var name string
+		line := &domain.ConsignmentSettlementLine{}
+		if err := rows.Scan(&name, &line.ProductID, &line.Location, &line.Payables, &line.Units, &line.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan consignment settlement: %w", err)
+		}
+		if len(settlements) == 0 || settlements[len(settlements)-1].Supplier != name {
+			settlements = append(settlements, &domain.SupplierSettlement{Supplier: name})
+		}
+		settlements[len(settlements)-1].Add(line)
+	}
+	return settlements, rows.Err()
+}
+
+// scanConsignment reads a row of consignmentColumns
+func scanConsignment(row interface{ Scan(...any) error }) (*domain.Consignment, error) {
+	consignment := &domain.Consignment{}
+	err := row.Scan(
+		&consignment.ID, &consignment.Supplier, &consignment.ProductID, &consignment.Location, &consignment.UnitCost,
+		&consignment.Quantity, &consignment.Remaining, &consignment.Reference, &consignment.ReceivedAt,
+	)
+	return consignment, err
+}
+
+// scanConsignments reads and closes rows of consignmentColumns
+func scanConsignments(rows *sql.Rows) ([]*domain.Consignment, error) {
+	defer rows.Close()
+
+	var consignments []*domain.Consignment
+	for rows.Next() {
+		consignment, err := scanConsignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consignment: %w", err)
+		}
+		consignments = append(consignments, consignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consignments: %w", err)
+	}
+
+	return consignments, nil
+}
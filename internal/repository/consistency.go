@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LSN is a position in the write-ahead log of the primary database. A replica
+// that replayed the log up to an LSN sees every write committed before it.
+type LSN uint64
+
+// ParseLSN parses an LSN in the textual form of PostgreSQL, e.g. "16/B374D848"
+func ParseLSN(value string) (LSN, error) {
+	high, low, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN %q", value)
+	}
+	hi, err := strconv.ParseUint(high, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q", value)
+	}
+	lo, err := strconv.ParseUint(low, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q", value)
+	}
+	return LSN(hi<<32 | lo), nil
+}
+
+// String formats the LSN the way PostgreSQL does
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint32(l))
+}
+
+type readAfterKey struct{}
+
+// WithReadAfter makes the reads of ctx see at least every write committed
+// before lsn: replicas that have not replayed it yet are skipped for the
+// primary, and in-process caches are bypassed
+func WithReadAfter(ctx context.Context, lsn LSN) context.Context {
+	return context.WithValue(ctx, readAfterKey{}, lsn)
+}
+
+// ReadAfter returns the LSN the reads of ctx must see, if any
+func ReadAfter(ctx context.Context) (LSN, bool) {
+	lsn, ok := ctx.Value(readAfterKey{}).(LSN)
+	return lsn, ok
+}
+
+// PostgresConsistencyRepository implements ConsistencyRepository using
+// PostgreSQL
+type PostgresConsistencyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresConsistencyRepository creates a new PostgresConsistencyRepository
+func NewPostgresConsistencyRepository(db *sql.DB) *PostgresConsistencyRepository {
+	return &PostgresConsistencyRepository{db: db}
+}
+
+// CurrentLSN returns the current write position of the primary, which every
+// write committed so far precedes
+func (r *PostgresConsistencyRepository) CurrentLSN(ctx context.Context) (LSN, error) {
+	var value string
+	if err := r.db.QueryRowContext(ctx, "SELECT pg_current_wal_lsn()::text").Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to read current LSN: %w", err)
+	}
+	return ParseLSN(value)
+}
@@ -3,9 +3,15 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/XSAM/otelsql"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 )
 
 // Database handles database connection and initialization
@@ -13,9 +19,18 @@ type Database struct {
 	conn *sql.DB
 }
 
+// PoolConfig sizes the connection pool. A zero lifetime or idle time keeps
+// connections open indefinitely.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
 // NewDatabase creates a new database connection
-func NewDatabase(dsn string) (*Database, error) {
-	conn, err := sql.Open("postgres", dsn)
+func NewDatabase(dsn string, pool PoolConfig) (*Database, error) {
+	conn, err := openTraced(dsn, "primary")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -26,12 +41,37 @@ func NewDatabase(dsn string) (*Database, error) {
 	}
 
 	// Set connection pool settings
-	conn.SetMaxOpenConns(25)
-	conn.SetMaxIdleConns(5)
+	conn.SetMaxOpenConns(pool.MaxOpenConns)
+	conn.SetMaxIdleConns(pool.MaxIdleConns)
+	conn.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
 	return &Database{conn: conn}, nil
 }
 
+// openTraced opens a PostgreSQL pool whose statements are traced as child
+// spans of the caller's context with the global OpenTelemetry tracer provider.
+// Pings, row iteration and session resets are left out to keep traces small;
+// the pool label tells primary and replica statements apart.
+func openTraced(dsn, label string) (*sql.DB, error) {
+	return otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL, attribute.String("db.pool", label)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+		otelsql.WithMeterProvider(noop.NewMeterProvider()),
+	)
+}
+
+// nullIfEmpty maps an empty optional reference to SQL NULL
+func nullIfEmpty(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// isUniqueViolation reports whether err was caused by the named unique constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
 // GetConnection returns the database connection
 func (d *Database) GetConnection() *sql.DB {
 	return d.conn
@@ -41,55 +81,3 @@ func (d *Database) GetConnection() *sql.DB {
 func (d *Database) Close() error {
 	return d.conn.Close()
 }
-
-// InitSchema creates the database schema
-func (d *Database) InitSchema(ctx context.Context) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS products (
-		id VARCHAR(36) PRIMARY KEY,
-		name VARCHAR(255) NOT NULL,
-		description TEXT,
-		sku VARCHAR(100) UNIQUE NOT NULL,
-		price NUMERIC(10, 2) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS inventory (
-		id VARCHAR(36) PRIMARY KEY,
-		product_id VARCHAR(36) NOT NULL UNIQUE,
-		quantity BIGINT NOT NULL DEFAULT 0,
-		reserved BIGINT NOT NULL DEFAULT 0,
-		location VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
-	);
-
-	CREATE TABLE IF NOT EXISTS transactions (
-		id VARCHAR(36) PRIMARY KEY,
-		inventory_id VARCHAR(36) NOT NULL,
-		product_id VARCHAR(36) NOT NULL,
-		type VARCHAR(20) NOT NULL,
-		quantity BIGINT NOT NULL,
-		reference VARCHAR(255),
-		notes TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE,
-		FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_products_sku ON products(sku);
-	CREATE INDEX IF NOT EXISTS idx_inventory_product_id ON inventory(product_id);
-	CREATE INDEX IF NOT EXISTS idx_transactions_inventory_id ON transactions(inventory_id);
-	CREATE INDEX IF NOT EXISTS idx_transactions_product_id ON transactions(product_id);
-	CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at DESC);
-	`
-
-	_, err := d.conn.ExecContext(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// productFilterColumns, inventoryFilterColumns and transactionFilterColumns
+// map the fields of domain.ProductFilterFields, InventoryFilterFields and
+// TransactionFilterFields to SQL expressions. Only these are ever written
+// into a query; the values of a filter are always bound as arguments.
+var (
+	productFilterColumns = map[string]string{
+		"name":       "name",
+		"sku":        "sku",
+		"category":   "category",
+		"price":      "price",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	inventoryFilterColumns = map[string]string{
+		"product_id":    "product_id",
+		"warehouse_id":  "COALESCE(warehouse_id, '')",
+		"location":      "location",
+		"condition":     "condition",
+		"bin_location":  "COALESCE(bin_location, '')",
+		"quantity":      "quantity",
+		"reserved":      "reserved",
+		"available":     "(quantity - reserved)",
+		"reorder_level": "reorder_level",
+		"safety_stock":  "safety_stock",
+		"updated_at":    "updated_at",
+	}
+	transactionFilterColumns = map[string]string{
+		"product_id":   "product_id",
+		"inventory_id": "inventory_id",
+		"type":         "type",
+		"reference":    "reference",
+		"reason_code":  "COALESCE(reason_code, '')",
+		"performed_by": "COALESCE(performed_by, '')",
+		"quantity":     "quantity",
+		"unit_cost":    "unit_cost",
+		"created_at":   "created_at",
+	}
+)
+
+// filterCondition appends the values of filter to args and returns the SQL
+// condition selecting the rows it matches, its placeholders numbered after
+// the arguments already in args. A nil filter is the condition TRUE.
+func filterCondition(filter *domain.Filter, columns map[string]string, args []any) (string, []any, error) {
+	if filter == nil {
+		return "TRUE", args, nil
+	}
+
+	if filter.Logic != "" {
+		conditions := make([]string, 0, len(filter.Terms))
+		for _, term := range filter.Terms {
+			condition, extended, err := filterCondition(term, columns, args)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, condition)
+			args = extended
+		}
+		return "(" + strings.Join(conditions, " "+filter.Logic+" ") + ")", args, nil
+	}
+
+	column, ok := columns[filter.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("filter field %q has no column", filter.Field)
+	}
+	args = append(args, filter.Value)
+	placeholder := fmt.Sprintf("$%d", len(args))
+	switch filter.Value.(type) {
+	case float64:
+		placeholder += "::double precision"
+	case string:
+		placeholder += "::text"
+	default:
+		placeholder += "::timestamptz"
+	}
+
+	switch filter.Operator {
+	case domain.FilterContains:
+		return fmt.Sprintf("position(lower(%s) in lower(%s)) > 0", placeholder, column), args, nil
+	case domain.FilterNotEqual:
+		return fmt.Sprintf("%s <> %s", column, placeholder), args, nil
+	case domain.FilterEqual, domain.FilterLess, domain.FilterLessEqual, domain.FilterGreater, domain.FilterGreaterEqual:
+		return fmt.Sprintf("%s %s %s", column, filter.Operator, placeholder), args, nil
+	}
+	return "", nil, fmt.Errorf("unknown filter operator %q", filter.Operator)
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresIdempotencyRepository implements IdempotencyRepository using PostgreSQL
+type PostgresIdempotencyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresIdempotencyRepository creates a new PostgresIdempotencyRepository
+func NewPostgresIdempotencyRepository(db *sql.DB) *PostgresIdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+// Reserve claims a key for a new request. It reports false when the key is
+// already held by a record created after expiredBefore; older records are
+// taken over, so keys can be reused once they expire.
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (bool, error) {
+	record.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO idempotency_keys (key, request_hash, status_code, response, created_at, completed_at)
+		VALUES ($1, $2, 0, NULL, $3, NULL)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = 0, response = NULL,
+			created_at = EXCLUDED.created_at, completed_at = NULL
+		WHERE idempotency_keys.created_at < $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, record.Key, record.RequestHash, record.CreatedAt, expiredBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Get retrieves the record of a key
+func (r *PostgresIdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		SELECT key, request_hash, status_code, response, created_at, completed_at
+		FROM idempotency_keys WHERE key = $1
+	`
+
+	record := &domain.IdempotencyRecord{}
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key, &record.RequestHash, &record.StatusCode, &record.Response,
+		&record.CreatedAt, &completedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("idempotency key %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if completedAt.Valid {
+		record.CompletedAt = &completedAt.Time
+	}
+
+	return record, nil
+}
+
+// Complete stores the response of the request that holds a key
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, response = $2, completed_at = $3
+		WHERE key = $4
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, statusCode, response, time.Now(), key); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Delete releases a key so that the request can be retried
+func (r *PostgresIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
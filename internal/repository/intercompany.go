@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresIntercompanyTransferRepository implements
+// IntercompanyTransferRepository using PostgreSQL
+type PostgresIntercompanyTransferRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresIntercompanyTransferRepository creates a new
+// PostgresIntercompanyTransferRepository
+func NewPostgresIntercompanyTransferRepository(db *sql.DB) *PostgresIntercompanyTransferRepository {
+	return &PostgresIntercompanyTransferRepository{db: db}
+}
+
+// intercompanyTransferColumns are the columns scanned by scanIntercompanyTransfer
+const intercompanyTransferColumns = `id, from_tenant, to_tenant, sku, from_product_id, to_product_id,
+	from_location, to_location, quantity, unit_cost, total_cost, reference,
+	out_transaction_id, in_transaction_id, created_at`
+
+// Create records a transfer document
+func (r *PostgresIntercompanyTransferRepository) Create(ctx context.Context, transfer *domain.IntercompanyTransfer) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO intercompany_transfers (`+intercompanyTransferColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`, transfer.ID, transfer.FromTenant, transfer.ToTenant, transfer.SKU, transfer.FromProductID, transfer.ToProductID,
+		transfer.FromLocation, transfer.ToLocation, transfer.Quantity, transfer.UnitCost, transfer.TotalCost, transfer.Reference,
+		transfer.OutTransactionID, transfer.InTransactionID, transfer.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create intercompany transfer: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a transfer sent or received by the tenant of ctx
+func (r *PostgresIntercompanyTransferRepository) GetByID(ctx context.Context, id string) (*domain.IntercompanyTransfer, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+intercompanyTransferColumns+`
+		FROM intercompany_transfers
+		WHERE id = $1 AND ($2 = '' OR from_tenant = $2 OR to_tenant = $2)
+	`, id, tenantScope(ctx))
+
+	transfer, err := scanIntercompanyTransfer(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("intercompany transfer %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intercompany transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// List retrieves the transfers sent or received by the tenant of ctx,
+// newest first
+func (r *PostgresIntercompanyTransferRepository) List(ctx context.Context, limit, offset int) ([]*domain.IntercompanyTransfer, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+intercompanyTransferColumns+`
+		FROM intercompany_transfers
+		WHERE $1 = '' OR from_tenant = $1 OR to_tenant = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, tenantScope(ctx), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intercompany transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []*domain.IntercompanyTransfer
+	for rows.Next() {
+		transfer, err := scanIntercompanyTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan intercompany transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating intercompany transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// scanIntercompanyTransfer reads a row of intercompanyTransferColumns
+func scanIntercompanyTransfer(row interface{ Scan(...any) error }) (*domain.IntercompanyTransfer, error) {
+	transfer := &domain.IntercompanyTransfer{}
+	err := row.Scan(
+		&transfer.ID, &transfer.FromTenant, &transfer.ToTenant, &transfer.SKU, &transfer.FromProductID, &transfer.ToProductID,
+		&transfer.FromLocation, &transfer.ToLocation, &transfer.Quantity, &transfer.UnitCost, &transfer.TotalCost, &transfer.Reference,
+		&transfer.OutTransactionID, &transfer.InTransactionID, &transfer.CreatedAt,
+	)
+	return transfer, err
+}
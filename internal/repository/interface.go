@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
 )
 
@@ -10,10 +12,35 @@ type ProductRepository interface {
 	Create(ctx context.Context, product *domain.Product) error
 	GetByID(ctx context.Context, id string) (*domain.Product, error)
 	GetBySKU(ctx context.Context, sku string) (*domain.Product, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.Product, error)
+	List(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) ([]*domain.Product, error)
 	Update(ctx context.Context, product *domain.Product) error
+	Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error)
 	Delete(ctx context.Context, id string) error
-	Count(ctx context.Context) (int64, error)
+	Archive(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Count(ctx context.Context, includeArchived bool, filter *domain.Filter) (int64, error)
+	SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error
+	ListScheduledReleases(ctx context.Context) ([]*domain.Product, error)
+	ReleaseDue(ctx context.Context, now time.Time) ([]string, error)
+}
+
+// ProductImportRepository defines the interface for bulk product imports
+type ProductImportRepository interface {
+	ExistingSKUs(ctx context.Context, skus []string) (map[string]bool, error)
+	ImportBatch(ctx context.Context, batch []*domain.ProductImport) error
+}
+
+// ProductArchiveRepository defines the interface for bulk product archives.
+// Filters with InactiveDays look for stock movements since cutoff.
+type ProductArchiveRepository interface {
+	Candidates(ctx context.Context, filter domain.ProductArchiveFilter, cutoff time.Time, limit int) ([]*domain.ArchiveCandidate, error)
+	ArchiveMatching(ctx context.Context, ids []string, filter domain.ProductArchiveFilter, cutoff time.Time) ([]string, error)
+}
+
+// ProductLocker serializes stock operations on a product across replicas.
+// LockProduct holds the lock until the unit of work of ctx ends.
+type ProductLocker interface {
+	LockProduct(ctx context.Context, productID string) error
 }
 
 // InventoryRepository defines the interface for inventory data operations
@@ -21,10 +48,26 @@ type InventoryRepository interface {
 	Create(ctx context.Context, item *domain.InventoryItem) error
 	GetByID(ctx context.Context, id string) (*domain.InventoryItem, error)
 	GetByProductID(ctx context.Context, productID string) (*domain.InventoryItem, error)
-	List(ctx context.Context, limit, offset int) ([]*domain.InventoryItem, error)
+	GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error)
+	ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error)
+	GetByProductIDs(ctx context.Context, productIDs []string) (map[string][]*domain.InventoryItem, error)
+	List(ctx context.Context, limit, offset int, filter *domain.Filter) ([]*domain.InventoryItem, error)
+	Count(ctx context.Context, filter *domain.Filter) (int64, error)
 	Update(ctx context.Context, item *domain.InventoryItem) error
+	UpdateSettings(ctx context.Context, item *domain.InventoryItem) error
 	Delete(ctx context.Context, id string) error
 	UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error
+	Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error
+	ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error
+}
+
+// WarehouseRepository defines the interface for warehouse data operations
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *domain.Warehouse) error
+	GetByID(ctx context.Context, id string) (*domain.Warehouse, error)
+	GetByCode(ctx context.Context, code string) (*domain.Warehouse, error)
+	Update(ctx context.Context, warehouse *domain.Warehouse) error
+	List(ctx context.Context, limit, offset int) ([]*domain.Warehouse, error)
 }
 
 // TransactionRepository defines the interface for transaction data operations
@@ -33,6 +76,311 @@ type TransactionRepository interface {
 	GetByID(ctx context.Context, id string) (*domain.Transaction, error)
 	GetByInventoryID(ctx context.Context, inventoryID string, limit, offset int) ([]*domain.Transaction, error)
 	GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Transaction, error)
+	Search(ctx context.Context, filter TransactionFilter, after *domain.TransactionCursor, limit, offset int) ([]*domain.Transaction, error)
+	RecentByProductIDs(ctx context.Context, productIDs []string, perProduct int) (map[string][]*domain.Transaction, error)
+	CountMatching(ctx context.Context, filter TransactionFilter) (int64, error)
 	List(ctx context.Context, limit, offset int) ([]*domain.Transaction, error)
+	GetByDateRange(ctx context.Context, from, to time.Time, after *domain.TransactionCursor, limit int) ([]*domain.Transaction, error)
+	ReservedByReference(ctx context.Context, productID, reference string) (int64, error)
+	OutboundByInventory(ctx context.Context, productID string, since time.Time) (map[string]int64, error)
 	Count(ctx context.Context) (int64, error)
 }
+
+// TransactionFilter selects transactions by product and a filter expression
+// over domain.TransactionFilterFields. Empty fields do not restrict the
+// selection. Archived transactions are only included when IncludeArchived
+// is set.
+type TransactionFilter struct {
+	ProductID       string
+	Expression      *domain.Filter
+	IncludeArchived bool
+}
+
+// TransactionRetentionRepository maintains the monthly partitions of the
+// transactions table and moves transactions past retention to the archive
+type TransactionRetentionRepository interface {
+	EnsurePartitions(ctx context.Context, from, through time.Time) (int, error)
+	ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// ReservationRepository defines the interface for reservation data operations
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *domain.Reservation) error
+	GetByID(ctx context.Context, id string) (*domain.Reservation, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.ReservationStatus) (bool, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error)
+	ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Reservation, error)
+	ListByParentID(ctx context.Context, parentID string) ([]*domain.Reservation, error)
+	PendingByChannel(ctx context.Context, channelID, productID string) (int64, error)
+	ConfirmedByChannel(ctx context.Context, channelID, productID string, since time.Time) (int64, error)
+}
+
+// BackorderRepository defines the interface for backorder data operations
+type BackorderRepository interface {
+	Create(ctx context.Context, backorder *domain.Backorder) error
+	GetByID(ctx context.Context, id string) (*domain.Backorder, error)
+	List(ctx context.Context, productID string, status domain.BackorderStatus, limit, offset int) ([]*domain.Backorder, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.BackorderStatus) (bool, error)
+}
+
+// CartHoldRepository defines the interface for cart hold data operations
+type CartHoldRepository interface {
+	Create(ctx context.Context, hold *domain.CartHold) error
+	GetByID(ctx context.Context, id string) (*domain.CartHold, error)
+	Claim(ctx context.Context, id string, to domain.CartHoldStatus, now time.Time) (bool, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.CartHoldStatus) (bool, error)
+	ExpireHeld(ctx context.Context, now time.Time) (int64, error)
+}
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (bool, error)
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, response []byte) error
+	Delete(ctx context.Context, key string) error
+}
+
+// JobRepository defines the interface for background job storage
+type JobRepository interface {
+	Create(ctx context.Context, job *domain.Job) error
+	GetByID(ctx context.Context, id string) (*domain.Job, error)
+	UpdateProgress(ctx context.Context, id string, status domain.JobStatus, progress domain.JobProgress) error
+	Finish(ctx context.Context, job *domain.Job, result []byte, contentType string) error
+	FailStale(ctx context.Context, id string, updatedBefore time.Time, message string) (bool, error)
+	GetResult(ctx context.Context, id string) (*domain.JobResult, error)
+}
+
+// PayloadSampleRepository defines the interface for sampled request/response storage
+type PayloadSampleRepository interface {
+	Create(ctx context.Context, sample *domain.PayloadSample) error
+	List(ctx context.Context, path string, limit, offset int) ([]*domain.PayloadSample, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// PurchaseOrderRepository defines the interface for purchase order data
+// operations and the stock figures reorder suggestions are computed from
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, order *domain.PurchaseOrder) error
+	GetByID(ctx context.Context, id string) (*domain.PurchaseOrder, error)
+	List(ctx context.Context, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.PurchaseOrderStatus) (bool, error)
+	ReorderCandidates(ctx context.Context, since time.Time) ([]*domain.ReorderCandidate, error)
+	ListForSupplier(ctx context.Context, supplier string, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error)
+	Lock(ctx context.Context, id string) error
+	Confirm(ctx context.Context, id string, promisedAt time.Time, quantities map[int]int64) (bool, error)
+	CreateShippingNotice(ctx context.Context, notice *domain.AdvanceShippingNotice) error
+	ListShippingNotices(ctx context.Context, orderID string) ([]*domain.AdvanceShippingNotice, error)
+}
+
+// StockAlertRepository defines the interface for low-stock alert data operations
+type StockAlertRepository interface {
+	Raise(ctx context.Context, alert *domain.StockAlert) (bool, error)
+	Refresh(ctx context.Context, alert *domain.StockAlert) error
+	Resolve(ctx context.Context, id string, at time.Time) (bool, error)
+	ListOpen(ctx context.Context) ([]*domain.StockAlert, error)
+	List(ctx context.Context, status domain.StockAlertStatus, limit, offset int) ([]*domain.StockAlert, error)
+}
+
+// BillingRepository defines the interface for 3PL billing event operations
+type BillingRepository interface {
+	LatestMovementAt(ctx context.Context) (time.Time, error)
+	RecordMovements(ctx context.Context, since time.Time) (int64, error)
+	RecordStorage(ctx context.Context, day time.Time, unitsPerPallet int64) (int64, error)
+	Billing(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantBilling, error)
+}
+
+// DataQualityRepository defines the interface for data quality checks
+type DataQualityRepository interface {
+	Check(ctx context.Context, check domain.DataQualityCheck, limit int) ([]*domain.DataQualityFinding, int, error)
+	DeleteOrphanedInventory(ctx context.Context, id string) (bool, error)
+}
+
+// OrderRepository defines the interface for order data operations
+type OrderRepository interface {
+	Create(ctx context.Context, order *domain.Order) error
+	GetByID(ctx context.Context, id string) (*domain.Order, error)
+	List(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error)
+	UpdateStatus(ctx context.Context, id string, from, to domain.OrderStatus) (bool, error)
+}
+
+// SaleRepository defines the interface for point-of-sale sale data operations
+type SaleRepository interface {
+	Create(ctx context.Context, sale *domain.Sale) error
+	GetByEventID(ctx context.Context, eventID string) (*domain.Sale, error)
+	List(ctx context.Context, filter domain.SaleFilter, limit, offset int) ([]*domain.Sale, error)
+}
+
+// WMSRepository defines the interface for the events consumed from the WMS
+type WMSRepository interface {
+	RecordEvent(ctx context.Context, event *domain.WMSEvent) error
+	GetEvent(ctx context.Context, eventID string) (*domain.WMSEvent, error)
+	DeadLetter(ctx context.Context, letter *domain.WMSDeadLetter) error
+	ListDeadLetters(ctx context.Context, limit, offset int) ([]*domain.WMSDeadLetter, error)
+}
+
+// SalesChannelRepository defines the interface for sales channel and
+// channel allocation data operations
+type SalesChannelRepository interface {
+	Create(ctx context.Context, channel *domain.SalesChannel) error
+	GetByID(ctx context.Context, id string) (*domain.SalesChannel, error)
+	List(ctx context.Context) ([]*domain.SalesChannel, error)
+	SetAllocation(ctx context.Context, allocation *domain.ChannelAllocation) error
+	GetAllocation(ctx context.Context, channelID, productID string) (*domain.ChannelAllocation, error)
+	ListAllocations(ctx context.Context, channelID string) ([]*domain.ChannelAllocation, error)
+	DeleteAllocation(ctx context.Context, channelID, productID string) error
+	RecordEvent(ctx context.Context, event *domain.ChannelAllocationEvent) error
+	ListEvents(ctx context.Context, channelID string, limit, offset int) ([]*domain.ChannelAllocationEvent, error)
+}
+
+// IntercompanyTransferRepository defines the interface for inter-company
+// transfer documents
+type IntercompanyTransferRepository interface {
+	Create(ctx context.Context, transfer *domain.IntercompanyTransfer) error
+	GetByID(ctx context.Context, id string) (*domain.IntercompanyTransfer, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.IntercompanyTransfer, error)
+}
+
+// ConsignmentRepository defines the interface for consignment lots and the
+// ownership transfers and supplier payables of their sales
+type ConsignmentRepository interface {
+	Create(ctx context.Context, consignment *domain.Consignment) error
+	GetByID(ctx context.Context, id string) (*domain.Consignment, error)
+	List(ctx context.Context, filter domain.ConsignmentFilter, limit, offset int) ([]*domain.Consignment, error)
+	LockOpen(ctx context.Context, productID, location string) ([]*domain.Consignment, error)
+	RecordTransfer(ctx context.Context, transfer *domain.OwnershipTransfer, payable *domain.SupplierPayable) error
+	ListTransfers(ctx context.Context, consignmentID string, limit, offset int) ([]*domain.OwnershipTransfer, error)
+	Settlement(ctx context.Context, supplier string, from, to time.Time) ([]*domain.SupplierSettlement, error)
+}
+
+// LotRepository defines the interface for the stock lots of inventory rows
+type LotRepository interface {
+	Receive(ctx context.Context, lot *domain.Lot) (*domain.Lot, error)
+	GetByID(ctx context.Context, id string) (*domain.Lot, error)
+	ListByProduct(ctx context.Context, productID string, filter domain.LotFilter, limit, offset int) ([]*domain.Lot, error)
+	LockOpen(ctx context.Context, inventoryID string) ([]*domain.Lot, error)
+	Consume(ctx context.Context, id string, quantity int64) error
+	ListExpiring(ctx context.Context, before time.Time, location string, limit, offset int) ([]*domain.Lot, error)
+}
+
+// StocktakeRepository defines the interface for stocktake and queued movement storage
+type StocktakeRepository interface {
+	Create(ctx context.Context, stocktake *domain.Stocktake) error
+	GetByID(ctx context.Context, id string) (*domain.Stocktake, error)
+	FindOpen(ctx context.Context, location string) (*domain.Stocktake, error)
+	List(ctx context.Context, limit, offset int) ([]*domain.Stocktake, error)
+	Close(ctx context.Context, id string) (bool, error)
+	QueueMovement(ctx context.Context, movement *domain.QueuedMovement) error
+	ListPendingMovements(ctx context.Context, stocktakeID string) ([]*domain.QueuedMovement, error)
+	UpdateMovementStatus(ctx context.Context, id string, status domain.QueuedMovementStatus, errorMessage string) error
+}
+
+// SerialUnitRepository defines the interface for serialized unit data operations
+type SerialUnitRepository interface {
+	Create(ctx context.Context, unit *domain.SerialUnit) error
+	GetBySerial(ctx context.Context, productID, serialNumber string) (*domain.SerialUnit, error)
+	ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.SerialUnit, error)
+	UpdateStatus(ctx context.Context, unit *domain.SerialUnit, event *domain.SerialUnitEvent) error
+	GetHistory(ctx context.Context, unitID string, limit, offset int) ([]*domain.SerialUnitEvent, error)
+}
+
+// RedactionRepository defines the interface for personal data erasure operations
+type RedactionRepository interface {
+	Redact(ctx context.Context, identifier string, redaction *domain.Redaction) error
+	List(ctx context.Context, limit, offset int) ([]*domain.Redaction, error)
+}
+
+// ProductTranslationRepository defines the interface for localized product data operations
+type ProductTranslationRepository interface {
+	Upsert(ctx context.Context, productID, locale string, translation domain.ProductTranslation) error
+	Delete(ctx context.Context, productID, locale string) error
+	GetByProductIDs(ctx context.Context, productIDs []string) (map[string]map[string]domain.ProductTranslation, error)
+}
+
+// ReportRepository defines the interface for aggregate reporting queries
+type ReportRepository interface {
+	StockSummary(ctx context.Context, groupBy []string) ([]*domain.StockSummary, error)
+	MovementSummary(ctx context.Context, query MovementQuery) ([]*domain.MovementSummary, error)
+	InventoryKPIs(ctx context.Context, lowStockThreshold int64) (*domain.InventoryKPIs, error)
+	DashboardStats(ctx context.Context, lowStockThreshold int64, now time.Time) (*domain.DashboardStats, error)
+	StockVelocities(ctx context.Context, since time.Time) ([]*domain.StockVelocity, error)
+	OpenReservationHolds(ctx context.Context) ([]*domain.ReservationHold, error)
+	CaptureStockSnapshots(ctx context.Context, day time.Time) (int64, error)
+	StockSnapshots(ctx context.Context, productID string, from, to time.Time) ([]*domain.StockSnapshot, error)
+	StockAsOf(ctx context.Context, asOf time.Time) ([]*domain.HistoricStock, error)
+	ValuationInputs(ctx context.Context, productID string) ([]*domain.ValuationInput, error)
+}
+
+// MovementQuery describes a movement summary over a half-open period. Buckets
+// are aligned to midnight in Location.
+type MovementQuery struct {
+	From           time.Time
+	To             time.Time
+	Granularity    string
+	Location       *time.Location
+	ProductID      string
+	GroupByProduct bool
+}
+
+// BulkReadRepository defines the interface for large reads by internal services
+type BulkReadRepository interface {
+	AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error)
+	ExportProducts(ctx context.Context, filter ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error)
+}
+
+// ProductExportFilter selects the products of a catalog export by category
+// and last update. Empty fields do not restrict the selection. Archived
+// products are only included when IncludeArchived is set.
+type ProductExportFilter struct {
+	Category        string
+	UpdatedSince    time.Time
+	IncludeArchived bool
+}
+
+// UsageRepository defines the interface for tenant usage metering
+type UsageRepository interface {
+	AddUsage(ctx context.Context, day time.Time, usage []*domain.TenantUsage) error
+	Usage(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantUsage, error)
+	CountStoredSKUs(ctx context.Context) (int64, error)
+}
+
+// ConsistencyRepository defines the interface for read-your-writes tokens
+type ConsistencyRepository interface {
+	CurrentLSN(ctx context.Context) (LSN, error)
+}
+
+// VariantRepository defines the interface for product variants
+type VariantRepository interface {
+	Create(ctx context.Context, variant *domain.ProductVariant) error
+	GetByProductID(ctx context.Context, productID string) (*domain.ProductVariant, error)
+	ListByParent(ctx context.Context, parentID string) ([]*domain.ProductVariant, error)
+}
+
+// BackgroundControlRepository defines the interface for pausing background
+// processing
+type BackgroundControlRepository interface {
+	Pause(ctx context.Context, pause *domain.BackgroundPause) error
+	Resume(ctx context.Context, component domain.BackgroundComponent) error
+	ListPauses(ctx context.Context) ([]*domain.BackgroundPause, error)
+}
+
+// ReconciliationRepository defines the interface for stock reconciliation
+type ReconciliationRepository interface {
+	FindDiscrepancies(ctx context.Context, inventoryIDs []string, limit int) ([]*domain.ReconciliationDiscrepancy, int, error)
+	CreateReport(ctx context.Context, report *domain.ReconciliationReport) error
+	LatestReport(ctx context.Context) (*domain.ReconciliationReport, error)
+}
+
+// AuditManifestRepository defines the interface for the manifests of ledger
+// exports
+type AuditManifestRepository interface {
+	Create(ctx context.Context, manifest *domain.AuditManifest) error
+	GetByID(ctx context.Context, id string) (*domain.AuditManifest, error)
+}
+
+// TransactionAnnotationRepository defines the interface for notes appended to
+// transactions
+type TransactionAnnotationRepository interface {
+	Create(ctx context.Context, annotation *domain.TransactionAnnotation) error
+	ListByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*domain.TransactionAnnotation, error)
+}
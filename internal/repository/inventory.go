@@ -3,17 +3,19 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // PostgresInventoryRepository implements InventoryRepository using PostgreSQL
 type PostgresInventoryRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	replica *ReadReplica
 }
 
 // NewPostgresInventoryRepository creates a new PostgresInventoryRepository
@@ -21,6 +23,13 @@ func NewPostgresInventoryRepository(db *sql.DB) *PostgresInventoryRepository {
 	return &PostgresInventoryRepository{db: db}
 }
 
+// WithReadReplica routes the inventory item lookups and listings to replica while it
+// is healthy; a nil replica keeps every read on the primary
+func (r *PostgresInventoryRepository) WithReadReplica(replica *ReadReplica) *PostgresInventoryRepository {
+	r.replica = replica
+	return r
+}
+
 // Create inserts a new inventory item
 func (r *PostgresInventoryRepository) Create(ctx context.Context, item *domain.InventoryItem) error {
 	if err := item.Validate(); err != nil {
@@ -28,19 +37,30 @@ func (r *PostgresInventoryRepository) Create(ctx context.Context, item *domain.I
 	}
 
 	item.ID = uuid.New().String()
+	if item.Condition == "" {
+		item.Condition = domain.ConditionNew
+	}
 	now := time.Now()
 	item.CreatedAt = now
 	item.UpdatedAt = now
 
 	query := `
-		INSERT INTO inventory (id, product_id, quantity, reserved, location, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO inventory (id, product_id, warehouse_id, quantity, reserved, location, version, created_at, updated_at,
+			reorder_level, safety_stock, bin_location, condition, tenant_id)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $14, tenant_id
+		FROM products WHERE id = $2 AND ($13 = '' OR tenant_id = $13)
+		RETURNING tenant_id
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		item.ID, item.ProductID, item.Quantity, item.Reserved, item.Location,
-		item.CreatedAt, item.UpdatedAt,
-	)
+	item.Version = 1
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		item.ID, item.ProductID, nullIfEmpty(item.WarehouseID), item.Quantity, item.Reserved, item.Location,
+		item.Version, item.CreatedAt, item.UpdatedAt, item.ReorderLevel, item.SafetyStock, nullIfEmpty(item.BinLocation),
+		tenantScope(ctx), item.Condition,
+	).Scan(&item.TenantID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create inventory item: %w", err)
 	}
@@ -51,18 +71,20 @@ func (r *PostgresInventoryRepository) Create(ctx context.Context, item *domain.I
 // GetByID retrieves an inventory item by ID
 func (r *PostgresInventoryRepository) GetByID(ctx context.Context, id string) (*domain.InventoryItem, error) {
 	query := `
-		SELECT id, product_id, quantity, reserved, location, created_at, updated_at
-		FROM inventory WHERE id = $1
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
+		FROM inventory WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
 	`
 
 	item := &domain.InventoryItem{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&item.ID, &item.ProductID, &item.Quantity, &item.Reserved, &item.Location,
-		&item.CreatedAt, &item.UpdatedAt,
+	err := reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, id, tenantScope(ctx)).Scan(
+		&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+		&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+		&item.Condition,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("inventory item not found")
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get inventory item: %w", err)
@@ -71,21 +93,52 @@ func (r *PostgresInventoryRepository) GetByID(ctx context.Context, id string) (*
 	return item, nil
 }
 
-// GetByProductID retrieves inventory for a specific product
+// GetByProductID retrieves the default inventory item of a product, which is
+// the first location it was stocked at
 func (r *PostgresInventoryRepository) GetByProductID(ctx context.Context, productID string) (*domain.InventoryItem, error) {
 	query := `
-		SELECT id, product_id, quantity, reserved, location, created_at, updated_at
-		FROM inventory WHERE product_id = $1
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
+		FROM inventory WHERE product_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT 1
+	`
+
+	item := &domain.InventoryItem{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, productID, tenantScope(ctx)).Scan(
+		&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+		&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+		&item.Condition,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetByProductAndLocation retrieves the inventory item of a product at a
+// location in a condition
+func (r *PostgresInventoryRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	query := `
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
+		FROM inventory WHERE product_id = $1 AND location = $2 AND condition = $4 AND ($3 = '' OR tenant_id = $3)
 	`
 
 	item := &domain.InventoryItem{}
-	err := r.db.QueryRowContext(ctx, query, productID).Scan(
-		&item.ID, &item.ProductID, &item.Quantity, &item.Reserved, &item.Location,
-		&item.CreatedAt, &item.UpdatedAt,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, productID, location, tenantScope(ctx), condition).Scan(
+		&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+		&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+		&item.Condition,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("inventory item not found")
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get inventory item: %w", err)
@@ -94,16 +147,100 @@ func (r *PostgresInventoryRepository) GetByProductID(ctx context.Context, produc
 	return item, nil
 }
 
-// List retrieves a paginated list of inventory items
-func (r *PostgresInventoryRepository) List(ctx context.Context, limit, offset int) ([]*domain.InventoryItem, error) {
+// ListByProductID retrieves the inventory items of a product at all locations
+func (r *PostgresInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
 	query := `
-		SELECT id, product_id, quantity, reserved, location, created_at, updated_at
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
 		FROM inventory
+		WHERE product_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, productID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*domain.InventoryItem
+	for rows.Next() {
+		item := &domain.InventoryItem{}
+		if err := rows.Scan(
+			&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+			&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+			&item.Condition,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory items: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetByProductIDs retrieves the inventory items of many products with a
+// single query, keyed by product ID
+func (r *PostgresInventoryRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string][]*domain.InventoryItem, error) {
+	items := make(map[string][]*domain.InventoryItem, len(productIDs))
+	if len(productIDs) == 0 {
+		return items, nil
+	}
+
+	query := `
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
+		FROM inventory
+		WHERE product_id = ANY($1) AND ($2 = '' OR tenant_id = $2)
+		ORDER BY product_id, created_at ASC, id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(productIDs), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &domain.InventoryItem{}
+		if err := rows.Scan(
+			&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+			&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+			&item.Condition,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
+		}
+		items[item.ProductID] = append(items[item.ProductID], item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating inventory items: %w", err)
+	}
+
+	return items, nil
+}
+
+// List retrieves a paginated list of the inventory items matching filter,
+// newest first
+func (r *PostgresInventoryRepository) List(ctx context.Context, limit, offset int, filter *domain.Filter) ([]*domain.InventoryItem, error) {
+	condition, args, err := filterCondition(filter, inventoryFilterColumns, []any{limit, offset, tenantScope(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, tenant_id, product_id, COALESCE(warehouse_id, ''), quantity, reserved, location, version, created_at,
+			updated_at, reorder_level, safety_stock, COALESCE(bin_location, ''), condition
+		FROM inventory
+		WHERE ($3 = '' OR tenant_id = $3) AND ` + condition + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := reader(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list inventory items: %w", err)
 	}
@@ -113,8 +250,9 @@ func (r *PostgresInventoryRepository) List(ctx context.Context, limit, offset in
 	for rows.Next() {
 		item := &domain.InventoryItem{}
 		if err := rows.Scan(
-			&item.ID, &item.ProductID, &item.Quantity, &item.Reserved, &item.Location,
-			&item.CreatedAt, &item.UpdatedAt,
+			&item.ID, &item.TenantID, &item.ProductID, &item.WarehouseID, &item.Quantity, &item.Reserved, &item.Location,
+			&item.Version, &item.CreatedAt, &item.UpdatedAt, &item.ReorderLevel, &item.SafetyStock, &item.BinLocation,
+			&item.Condition,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan inventory item: %w", err)
 		}
@@ -128,7 +266,25 @@ func (r *PostgresInventoryRepository) List(ctx context.Context, limit, offset in
 	return items, nil
 }
 
-// Update updates an existing inventory item
+// Count returns the number of inventory items matching filter
+func (r *PostgresInventoryRepository) Count(ctx context.Context, filter *domain.Filter) (int64, error) {
+	condition, args, err := filterCondition(filter, inventoryFilterColumns, []any{tenantScope(ctx)})
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT COUNT(*) FROM inventory WHERE ($1 = '' OR tenant_id = $1) AND ` + condition
+
+	var count int64
+	if err := reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count inventory items: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update updates an existing inventory item using optimistic concurrency: the
+// write only applies while the stored version still equals item.Version and
+// fails with domain.ErrConflict when another writer changed the row first
 func (r *PostgresInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
 	if err := item.Validate(); err != nil {
 		return fmt.Errorf("validation error: %w", err)
@@ -138,12 +294,14 @@ func (r *PostgresInventoryRepository) Update(ctx context.Context, item *domain.I
 
 	query := `
 		UPDATE inventory
-		SET quantity = $1, reserved = $2, location = $3, updated_at = $4
-		WHERE id = $5
+		SET quantity = $1, reserved = $2, location = $3, warehouse_id = $4, updated_at = $5, version = version + 1,
+			reorder_level = $8, safety_stock = $9, bin_location = $10
+		WHERE id = $6 AND version = $7 AND ($11 = '' OR tenant_id = $11)
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
-		item.Quantity, item.Reserved, item.Location, item.UpdatedAt, item.ID,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		item.Quantity, item.Reserved, item.Location, nullIfEmpty(item.WarehouseID), item.UpdatedAt, item.ID, item.Version,
+		item.ReorderLevel, item.SafetyStock, nullIfEmpty(item.BinLocation), tenantScope(ctx),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update inventory item: %w", err)
@@ -155,17 +313,70 @@ func (r *PostgresInventoryRepository) Update(ctx context.Context, item *domain.I
 	}
 
 	if rows == 0 {
-		return errors.New("inventory item not found")
+		var exists bool
+		if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM inventory WHERE id = $1 AND ($2 = '' OR tenant_id = $2))`,
+			item.ID, tenantScope(ctx)).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check inventory item: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("%w: inventory item %s was modified concurrently", domain.ErrConflict, item.ID)
+		}
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+
+	item.Version++
+	return nil
+}
+
+// UpdateSettings saves the reorder level, safety stock and bin location of an
+// inventory item while the stored version still equals item.Version, leaving
+// quantities alone; it fails with domain.ErrConflict when the row changed first
+func (r *PostgresInventoryRepository) UpdateSettings(ctx context.Context, item *domain.InventoryItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	item.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE inventory
+		SET reorder_level = $1, safety_stock = $2, bin_location = $3, updated_at = $4, version = version + 1
+		WHERE id = $5 AND version = $6 AND ($7 = '' OR tenant_id = $7)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		item.ReorderLevel, item.SafetyStock, nullIfEmpty(item.BinLocation), item.UpdatedAt, item.ID, item.Version,
+		tenantScope(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update inventory settings: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		var exists bool
+		if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM inventory WHERE id = $1 AND ($2 = '' OR tenant_id = $2))`,
+			item.ID, tenantScope(ctx)).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check inventory item: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("%w: inventory item %s was modified concurrently", domain.ErrConflict, item.ID)
+		}
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
 	}
 
+	item.Version++
 	return nil
 }
 
 // Delete deletes an inventory item
 func (r *PostgresInventoryRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM inventory WHERE id = $1`
+	query := `DELETE FROM inventory WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, tenantScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete inventory item: %w", err)
 	}
@@ -176,7 +387,7 @@ func (r *PostgresInventoryRepository) Delete(ctx context.Context, id string) err
 	}
 
 	if rows == 0 {
-		return errors.New("inventory item not found")
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
 	}
 
 	return nil
@@ -186,11 +397,12 @@ func (r *PostgresInventoryRepository) Delete(ctx context.Context, id string) err
 func (r *PostgresInventoryRepository) UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
 	query := `
 		UPDATE inventory
-		SET quantity = quantity + $1, reserved = reserved + $2, updated_at = $3
+		SET quantity = quantity + $1, reserved = reserved + $2, updated_at = $3, version = version + 1
 		WHERE id = $4 AND (quantity + $1) >= 0 AND (reserved + $2) >= 0 AND (quantity + $1 - reserved - $2) >= 0
+			AND ($5 = '' OR tenant_id = $5)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, quantityDelta, reservedDelta, time.Now(), inventoryID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, quantityDelta, reservedDelta, time.Now(), inventoryID, tenantScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update quantity: %w", err)
 	}
@@ -201,8 +413,184 @@ func (r *PostgresInventoryRepository) UpdateQuantity(ctx context.Context, invent
 	}
 
 	if rows == 0 {
-		return errors.New("quantity update failed: invalid operation or item not found")
+		return r.rejectedUpdate(ctx, inventoryID, quantityDelta, reservedDelta)
+	}
+
+	return nil
+}
+
+// rejectedUpdate reads back the row a quantity update did not change, to
+// report why: a StockUpdateError with its current stock and the deltas, or
+// ErrNotFound when the row does not exist
+func (r *PostgresInventoryRepository) rejectedUpdate(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
+	rejected := &domain.StockUpdateError{InventoryID: inventoryID, QuantityDelta: quantityDelta, ReservedDelta: reservedDelta}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT quantity, reserved FROM inventory WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, inventoryID, tenantScope(ctx)).Scan(&rejected.Quantity, &rejected.Reserved)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("quantity update failed: inventory item %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("quantity update failed: %w (failed to read current stock: %v)", domain.ErrInsufficientStock, err)
+	}
+
+	slog.InfoContext(ctx, "quantity update rejected", "inventory_id", inventoryID,
+		"quantity", rejected.Quantity, "reserved", rejected.Reserved,
+		"quantity_delta", quantityDelta, "reserved_delta", reservedDelta)
+	return rejected
+}
+
+// Transfer moves quantity from one inventory item to another and records the
+// paired transactions in one database transaction. Both rows are locked in id
+// order so that opposite transfers between the same rows cannot deadlock.
+func (r *PostgresInventoryRepository) Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error {
+	if err := out.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	err := withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id FROM inventory WHERE id IN ($1, $2) AND ($3 = '' OR tenant_id = $3) ORDER BY id FOR UPDATE
+		`, fromID, toID, tenantScope(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to lock inventory items: %w", err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to lock inventory items: %w", err)
+		}
+
+		now := time.Now()
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE inventory
+			SET quantity = quantity - $1, updated_at = $2, version = version + 1
+			WHERE id = $3 AND (quantity - reserved) >= $1 AND ($4 = '' OR tenant_id = $4)
+		`, quantity, now, fromID, tenantScope(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to update source inventory: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		} else if affected == 0 {
+			slog.DebugContext(ctx, "transfer rejected", "from_inventory_id", fromID, "quantity", quantity)
+			return fmt.Errorf("transfer failed: %w available or item not found", domain.ErrInsufficientStock)
+		}
+
+		result, err = tx.ExecContext(ctx, `
+			UPDATE inventory
+			SET quantity = quantity + $1, updated_at = $2, version = version + 1
+			WHERE id = $3 AND ($4 = '' OR tenant_id = $4)
+		`, quantity, now, toID, tenantScope(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to update destination inventory: %w", err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		} else if affected == 0 {
+			return fmt.Errorf("transfer failed: destination item %w", domain.ErrNotFound)
+		}
+
+		for _, transaction := range []*domain.Transaction{out, in} {
+			transaction.ID = uuid.New().String()
+			transaction.CreatedAt = now
+
+			if err := insertTransaction(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("failed to record transaction: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	slog.DebugContext(ctx, "transfer applied", "from_inventory_id", fromID, "to_inventory_id", toID, "quantity", quantity)
+
+	return nil
+}
+
+// ApplyBatch applies the quantity changes and records their transactions in
+// one database transaction. Changes are applied in order; if any of them would
+// leave its row with less stock than reserved or a negative reservation, the
+// whole batch is rolled back and ErrInsufficientStock is returned.
+func (r *PostgresInventoryRepository) ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error {
+	ids := make([]string, 0, len(changes))
+	for i, change := range changes {
+		if err := change.Transaction.Validate(); err != nil {
+			return fmt.Errorf("item %d: validation error: %w", i+1, err)
+		}
+		ids = append(ids, change.InventoryID)
+	}
+
+	err := withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		// Lock in a fixed order so that concurrent batches cannot deadlock
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id FROM inventory WHERE id = ANY($1) AND ($2 = '' OR tenant_id = $2) ORDER BY id FOR UPDATE
+		`, pq.Array(ids), tenantScope(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to lock inventory items: %w", err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to lock inventory items: %w", err)
+		}
+
+		// Numbering the transactions locks their products; lock them in a
+		// fixed order as well
+		productIDs := make([]string, 0, len(changes))
+		for _, change := range changes {
+			productIDs = append(productIDs, change.Transaction.ProductID)
+		}
+		rows, err = tx.QueryContext(ctx, `
+			SELECT id FROM products WHERE id = ANY($1) ORDER BY id FOR UPDATE
+		`, pq.Array(productIDs))
+		if err != nil {
+			return fmt.Errorf("failed to lock products: %w", err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to lock products: %w", err)
+		}
+
+		now := time.Now()
+
+		for i, change := range changes {
+			result, err := tx.ExecContext(ctx, `
+				UPDATE inventory
+				SET quantity = quantity + $1, reserved = reserved + $2, updated_at = $3, version = version + 1
+				WHERE id = $4 AND quantity + $1 >= reserved + $2 AND reserved + $2 >= 0 AND ($5 = '' OR tenant_id = $5)
+			`, change.QuantityDelta, change.ReservedDelta, now, change.InventoryID, tenantScope(ctx))
+			if err != nil {
+				return fmt.Errorf("item %d: failed to update inventory: %w", i+1, err)
+			}
+			if affected, err := result.RowsAffected(); err != nil {
+				return fmt.Errorf("failed to get affected rows: %w", err)
+			} else if affected == 0 {
+				slog.DebugContext(ctx, "stock batch rejected", "item", i+1, "inventory_id", change.InventoryID)
+				return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+			}
+
+			transaction := change.Transaction
+			transaction.ID = uuid.New().String()
+			transaction.CreatedAt = now
+
+			if err := insertTransaction(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("item %d: failed to record transaction: %w", i+1, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "stock batch applied", "items", len(changes))
+
 	return nil
 }
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresJobRepository implements JobRepository using PostgreSQL
+type PostgresJobRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresJobRepository creates a new PostgresJobRepository
+func NewPostgresJobRepository(db *sql.DB) *PostgresJobRepository {
+	return &PostgresJobRepository{db: db}
+}
+
+// Create inserts a new job
+func (r *PostgresJobRepository) Create(ctx context.Context, job *domain.Job) error {
+	job.ID = uuid.New().String()
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	query := `
+		INSERT INTO jobs (id, type, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, job.ID, job.Type, job.Status, job.CreatedBy, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job by ID, without its result document
+func (r *PostgresJobRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
+	query := `
+		SELECT id, type, status, progress_done, progress_total, summary, COALESCE(error, ''),
+			result IS NOT NULL, COALESCE(created_by, ''), created_at, updated_at, finished_at
+		FROM jobs WHERE id = $1
+	`
+
+	job := &domain.Job{}
+	var summary []byte
+	var finishedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&job.ID, &job.Type, &job.Status, &job.Progress.Done, &job.Progress.Total, &summary, &job.Error,
+		&job.HasResult, &job.CreatedBy, &job.CreatedAt, &job.UpdatedAt, &finishedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	job.Summary = summary
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return job, nil
+}
+
+// UpdateProgress records the status and progress of an unfinished job,
+// which also serves as its heartbeat
+func (r *PostgresJobRepository) UpdateProgress(ctx context.Context, id string, status domain.JobStatus, progress domain.JobProgress) error {
+	query := `
+		UPDATE jobs
+		SET status = $1, progress_done = $2, progress_total = $3, updated_at = $4
+		WHERE id = $5 AND status IN ('PENDING', 'RUNNING')
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, status, progress.Done, progress.Total, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	return nil
+}
+
+// Finish records the outcome of a job together with its result document
+func (r *PostgresJobRepository) Finish(ctx context.Context, job *domain.Job, result []byte, contentType string) error {
+	now := time.Now()
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+	job.HasResult = result != nil
+
+	query := `
+		UPDATE jobs
+		SET status = $1, progress_done = $2, progress_total = $3, summary = $4, error = NULLIF($5, ''),
+			result = $6, result_type = NULLIF($7, ''), updated_at = $8, finished_at = $8
+		WHERE id = $9
+	`
+
+	var summary any
+	if len(job.Summary) > 0 {
+		summary = []byte(job.Summary)
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		job.Status, job.Progress.Done, job.Progress.Total, summary, job.Error,
+		result, contentType, now, job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish job: %w", err)
+	}
+
+	return nil
+}
+
+// FailStale fails an unfinished job that reported no progress since
+// updatedBefore. It reports whether the job was failed.
+func (r *PostgresJobRepository) FailStale(ctx context.Context, id string, updatedBefore time.Time, message string) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'FAILED', error = $1, updated_at = $2, finished_at = $2
+		WHERE id = $3 AND status IN ('PENDING', 'RUNNING') AND updated_at < $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, message, time.Now(), id, updatedBefore)
+	if err != nil {
+		return false, fmt.Errorf("failed to fail stale job: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// GetResult retrieves the result document of a job
+func (r *PostgresJobRepository) GetResult(ctx context.Context, id string) (*domain.JobResult, error) {
+	query := `SELECT result, COALESCE(result_type, '') FROM jobs WHERE id = $1 AND result IS NOT NULL`
+
+	result := &domain.JobResult{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&result.Data, &result.ContentType)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job result %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job result: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrLockTimeout is returned when a product lock was not acquired in time
+var ErrLockTimeout = errors.New("timed out waiting for product lock")
+
+// productLockClass is the first key of the two-key advisory locks held on
+// products; the second is a hash of the product ID. Two-key locks never
+// collide with the single-key migration lock.
+const productLockClass = 4535
+
+// PostgresProductLocker implements ProductLocker with transaction-level
+// PostgreSQL advisory locks, which every replica sharing the database sees
+type PostgresProductLocker struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresProductLocker creates a new PostgresProductLocker that waits at
+// most timeout for a lock
+func NewPostgresProductLocker(db *sql.DB, timeout time.Duration) *PostgresProductLocker {
+	return &PostgresProductLocker{db: db, timeout: timeout}
+}
+
+// LockProduct takes the advisory lock of a product until the unit of work of
+// ctx ends, and fails outside of one. It returns ErrLockTimeout when the lock
+// is held elsewhere for longer than the timeout. The timeout is set as the
+// lock_timeout of the transaction, so it also bounds the row lock waits of
+// the rest of the unit of work.
+func (l *PostgresProductLocker) LockProduct(ctx context.Context, productID string) error {
+	if !InTransaction(ctx) {
+		return errors.New("product lock requires a unit of work")
+	}
+	tx := conn(ctx, l.db)
+
+	if l.timeout > 0 {
+		timeout := fmt.Sprintf("%dms", max(l.timeout.Milliseconds(), 1))
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('lock_timeout', $1, true)`, timeout); err != nil {
+			return fmt.Errorf("failed to set lock timeout: %w", err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, productLockClass, productID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "55P03" {
+		return ErrLockTimeout
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock product: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresLotRepository implements LotRepository using PostgreSQL
+type PostgresLotRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresLotRepository creates a new PostgresLotRepository
+func NewPostgresLotRepository(db *sql.DB) *PostgresLotRepository {
+	return &PostgresLotRepository{db: db}
+}
+
+// lotColumns are the columns scanned by scanLot
+const lotColumns = `id, inventory_id, product_id, location, lot_number, expires_on, quantity, remaining, received_at`
+
+// Receive adds the units of lot to the lot of its number in its inventory
+// row, creating it if the row has none. The lot inherits the tenant, product
+// and location of the row. It returns the lot as stored, or a validation
+// error if the existing lot expires on another day.
+func (r *PostgresLotRepository) Receive(ctx context.Context, lot *domain.Lot) (*domain.Lot, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO lots (id, tenant_id, inventory_id, product_id, location, lot_number, expires_on, quantity, remaining, received_at)
+		SELECT $1, i.tenant_id, i.id, i.product_id, i.location, $3, $4, $5, $5, $6
+		FROM inventory i
+		WHERE i.id = $2 AND ($7 = '' OR i.tenant_id = $7)
+		ON CONFLICT (inventory_id, lot_number) DO UPDATE
+			SET quantity = lots.quantity + EXCLUDED.quantity, remaining = lots.remaining + EXCLUDED.remaining
+			WHERE lots.expires_on = EXCLUDED.expires_on
+		RETURNING `+lotColumns,
+		lot.ID, lot.InventoryID, lot.LotNumber, lot.ExpiresOn.Format(time.DateOnly), lot.Quantity, lot.ReceivedAt, tenantScope(ctx))
+
+	stored, err := scanLot(row)
+	if err == nil {
+		return stored, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to receive lot: %w", err)
+	}
+
+	// Nothing was written: either the row does not exist or the lot does
+	// with another expiry date
+	var expiresOn time.Time
+	err = conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT expires_on FROM lots WHERE inventory_id = $1 AND lot_number = $2 AND ($3 = '' OR tenant_id = $3)
+	`, lot.InventoryID, lot.LotNumber, tenantScope(ctx)).Scan(&expiresOn)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lot: %w", err)
+	}
+	return nil, domain.NewValidationError("lot %s expires on %s, not %s",
+		lot.LotNumber, expiresOn.Format(time.DateOnly), lot.ExpiresOn.Format(time.DateOnly))
+}
+
+// GetByID retrieves a lot
+func (r *PostgresLotRepository) GetByID(ctx context.Context, id string) (*domain.Lot, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+lotColumns+`
+		FROM lots
+		WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, id, tenantScope(ctx))
+
+	lot, err := scanLot(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("lot %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lot: %w", err)
+	}
+	return lot, nil
+}
+
+// ListByProduct retrieves the lots of a product matching a filter, first
+// expiring first
+func (r *PostgresLotRepository) ListByProduct(ctx context.Context, productID string, filter domain.LotFilter, limit, offset int) ([]*domain.Lot, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+lotColumns+`
+		FROM lots
+		WHERE product_id = $1 AND ($2 = '' OR tenant_id = $2)
+			AND ($3 = '' OR location = $3)
+			AND (NOT $4 OR remaining > 0)
+		ORDER BY expires_on, received_at, id
+		LIMIT $5 OFFSET $6
+	`, productID, tenantScope(ctx), filter.Location, filter.Open, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	return scanLots(rows)
+}
+
+// LockOpen retrieves the lots of an inventory row with units in stock, first
+// expiring first, locking them until the unit of work of ctx ends so that
+// concurrent removals take each unit once
+func (r *PostgresLotRepository) LockOpen(ctx context.Context, inventoryID string) ([]*domain.Lot, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+lotColumns+`
+		FROM lots
+		WHERE inventory_id = $1 AND remaining > 0 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY expires_on, received_at, id
+		FOR UPDATE
+	`, inventoryID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock lots: %w", err)
+	}
+	return scanLots(rows)
+}
+
+// Consume takes units off the remaining ones of a lot
+func (r *PostgresLotRepository) Consume(ctx context.Context, id string, quantity int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE lots SET remaining = remaining - $2 WHERE id = $1 AND remaining >= $2
+	`, id, quantity)
+	if err != nil {
+		return fmt.Errorf("failed to update lot: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("lot %s has fewer than %d units left: %w", id, quantity, domain.ErrConflict)
+	}
+	return nil
+}
+
+// ListExpiring retrieves the lots with units in stock that expire before the
+// day before, first expiring first, optionally only those at a location
+func (r *PostgresLotRepository) ListExpiring(ctx context.Context, before time.Time, location string, limit, offset int) ([]*domain.Lot, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+lotColumns+`
+		FROM lots
+		WHERE remaining > 0 AND expires_on < $1
+			AND ($2 = '' OR tenant_id = $2)
+			AND ($3 = '' OR location = $3)
+		ORDER BY expires_on, id
+		LIMIT $4 OFFSET $5
+	`, before.Format(time.DateOnly), tenantScope(ctx), location, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring lots: %w", err)
+	}
+	return scanLots(rows)
+}
+
+// scanLot reads a row of lotColumns
+func scanLot(row interface{ Scan(...any) error }) (*domain.Lot, error) {
+	lot := &domain.Lot{}
+	err := row.Scan(
+		&lot.ID, &lot.InventoryID, &lot.ProductID, &lot.Location, &lot.LotNumber, &lot.ExpiresOn,
+		&lot.Quantity, &lot.Remaining, &lot.ReceivedAt,
+	)
+	lot.ExpiresOn = lot.ExpiresOn.UTC()
+	return lot, err
+}
+
+// scanLots reads and closes rows of lotColumns
+func scanLots(rows *sql.Rows) ([]*domain.Lot, error) {
+	defer rows.Close()
+
+	var lots []*domain.Lot
+	for rows.Next() {
+		lot, err := scanLot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating lots: %w", err)
+	}
+
+	return lots, nil
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryStore keeps the tables of the memory repositories in process memory,
+// for running the server without a PostgreSQL database in development and
+// CI. A unit of work holds the store's lock from start to end, so units of
+// work are serializable; one that fails is rolled back by undoing its writes
+// in reverse order. With a data file the tables are loaded when the store is
+// opened and saved by Save, Run and Close.
+type MemoryStore struct {
+	mu     sync.Mutex
+	tables *memoryTables
+	lsn    LSN
+	dirty  bool
+	path   string
+	saveMu sync.Mutex
+}
+
+// memoryTables are the tables of a MemoryStore, one map per table of the
+// PostgreSQL schema keyed by its primary key. The fields are exported for
+// encoding/gob.
+type memoryTables struct {
+	Products                map[string]*memoryProduct
+	Inventory               map[string]*domain.InventoryItem
+	Transactions            map[string]*memoryTransaction
+	Annotations             map[string]*memoryRow[domain.TransactionAnnotation]
+	Translations            map[memoryTranslationKey]domain.ProductTranslation
+	Variants                map[string]*memoryRow[domain.ProductVariant]
+	Warehouses              map[string]*domain.Warehouse
+	Reservations            map[string]*domain.Reservation
+	Backorders              map[string]*domain.Backorder
+	CartHolds               map[string]*domain.CartHold
+	Orders                  map[string]*domain.Order
+	Sales                   map[memoryEventKey]*domain.Sale
+	WMSEvents               map[memoryEventKey]*domain.WMSEvent
+	WMSDeadLetters          map[string]*domain.WMSDeadLetter
+	Channels                map[string]*memoryRow[domain.SalesChannel]
+	ChannelAllocations      map[memoryAllocationKey]*domain.ChannelAllocation
+	ChannelAllocationEvents map[string]*domain.ChannelAllocationEvent
+	PurchaseOrders          map[string]*domain.PurchaseOrder
+	ShippingNotices         map[string]*domain.AdvanceShippingNotice
+	StockAlerts             map[string]*memoryRow[domain.StockAlert]
+	Stocktakes              map[string]*domain.Stocktake
+	QueuedMovements         map[string]*memoryQueuedMovement
+	QueuedMovementSeq       int64
+	SerialUnits             map[string]*domain.SerialUnit
+	SerialUnitEvents        map[string]*domain.SerialUnitEvent
+	IntercompanyTransfers   map[string]*domain.IntercompanyTransfer
+	Consignments            map[string]*memoryRow[domain.Consignment]
+	OwnershipTransfers      map[string]*memoryRow[domain.OwnershipTransfer]
+	SupplierPayables        map[string]*memoryRow[domain.SupplierPayable]
+	Lots                    map[string]*memoryRow[domain.Lot]
+	StockSnapshots          map[memorySnapshotKey]*memoryStockSnapshot
+	ReconciliationReports   map[string]*memoryReconciliationReport
+	AuditManifests          map[string]*memoryRow[domain.AuditManifest]
+	Redactions              map[string]*memoryRow[domain.Redaction]
+	BillingEvents           map[memoryBillingKey]memoryBillingEvent
+	Usage                   map[memoryUsageKey]*domain.TenantUsage
+	IdempotencyKeys         map[string]*domain.IdempotencyRecord
+	Jobs                    map[string]*memoryJob
+	PayloadSamples          map[string]*domain.PayloadSample
+	BackgroundPauses        map[domain.BackgroundComponent]*domain.BackgroundPause
+}
+
+// memoryTx is the unit of work of a MemoryStore: the undo functions of its
+// writes
+type memoryTx struct {
+	undo []func()
+}
+
+type memoryTxKey struct{}
+
+// OpenMemoryStore opens a MemoryStore saved to path, loading the tables saved
+// there before if the file exists. An empty path keeps the data in memory
+// only, losing it when the process exits.
+func OpenMemoryStore(path string) (*MemoryStore, error) {
+	s := &MemoryStore{tables: &memoryTables{}, path: path}
+	if path != "" {
+		contents, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read data file: %w", err)
+		}
+		if err == nil {
+			if err := gob.NewDecoder(bytes.NewReader(contents)).Decode(s.tables); err != nil {
+				return nil, fmt.Errorf("failed to load data file %s: %w", path, err)
+			}
+			s.tables.assignTenants()
+		}
+	}
+	s.tables.init()
+	return s, nil
+}
+
+// Ping reports the store as available, as it always is
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Save writes the tables to the data file if they changed since they were
+// last saved. The file is replaced atomically, so a crash while saving
+// leaves the previous version.
+func (s *MemoryStore) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	var contents bytes.Buffer
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	err := gob.NewEncoder(&contents).Encode(s.tables)
+	s.dirty = false
+	s.mu.Unlock()
+	if err != nil {
+		s.markDirty()
+		return fmt.Errorf("failed to encode tables: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err == nil {
+		_, err = tmp.Write(contents.Bytes())
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), s.path)
+		}
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}
+	if err != nil {
+		s.markDirty()
+		return fmt.Errorf("failed to save data file: %w", err)
+	}
+	return nil
+}
+
+func (s *MemoryStore) markDirty() {
+	s.mu.Lock()
+	s.dirty = true
+	s.mu.Unlock()
+}
+
+// Run saves the tables every interval until ctx is cancelled
+func (s *MemoryStore) Run(ctx context.Context, interval time.Duration) {
+	if s.path == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				slog.Error("failed to save memory store", "path", s.path, "error", err)
+			}
+		}
+	}
+}
+
+// Close saves the tables a last time
+func (s *MemoryStore) Close() error {
+	return s.Save()
+}
+
+// WithinTransaction runs fn in one unit of work of the store
+func (s *MemoryStore) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.write(ctx, func(ctx context.Context, _ *memoryTables, _ *memoryTx) error {
+		return fn(ctx)
+	})
+}
+
+// write runs fn in the unit of work of ctx, or in a unit of work of its own
+// outside of one, with the tables to change and the unit of work recording
+// how to undo the changes
+func (s *MemoryStore) write(ctx context.Context, fn func(ctx context.Context, t *memoryTables, tx *memoryTx) error) error {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		return fn(ctx, s.tables, tx)
+	}
+
+	tx := &memoryTx{}
+	ctx, committed := WithCommitHooks(context.WithValue(ctx, memoryTxKey{}, tx))
+	if err := s.commit(ctx, tx, fn); err != nil {
+		return err
+	}
+	committed()
+	return nil
+}
+
+// commit runs fn holding the lock of the store and commits its writes, or
+// undoes them when fn fails or panics
+func (s *MemoryStore) commit(ctx context.Context, tx *memoryTx, fn func(ctx context.Context, t *memoryTables, tx *memoryTx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			for i := len(tx.undo) - 1; i >= 0; i-- {
+				tx.undo[i]()
+			}
+		}
+	}()
+
+	if err := fn(ctx, s.tables, tx); err != nil {
+		return err
+	}
+	committed = true
+	if len(tx.undo) > 0 {
+		s.lsn++
+		s.dirty = true
+	}
+	return nil
+}
+
+// read runs fn with the tables, in the unit of work of ctx if there is one
+func (s *MemoryStore) read(ctx context.Context, fn func(t *memoryTables) error) error {
+	if _, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		return fn(s.tables)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.tables)
+}
+
+// put stores row under key in table until the unit of work rolls back.
+// Stored rows are never changed in place; updates put a changed copy.
+func put[K comparable, V any](tx *memoryTx, table map[K]V, key K, row V) {
+	previous, existed := table[key]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			table[key] = previous
+		} else {
+			delete(table, key)
+		}
+	})
+	table[key] = row
+}
+
+// remove deletes the row under key from table until the unit of work rolls
+// back
+func remove[K comparable, V any](tx *memoryTx, table map[K]V, key K) {
+	previous, existed := table[key]
+	if !existed {
+		return
+	}
+	tx.undo = append(tx.undo, func() { table[key] = previous })
+	delete(table, key)
+}
+
+// set assigns value to a variable of the tables until the unit of work
+// rolls back
+func set[V any](tx *memoryTx, variable *V, value V) {
+	previous := *variable
+	tx.undo = append(tx.undo, func() { *variable = previous })
+	*variable = value
+}
+
+// init makes the tables that are nil, as empty tables are after loading
+func (t *memoryTables) init() {
+	value := reflect.ValueOf(t).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if field := value.Field(i); field.Kind() == reflect.Map && field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+	}
+}
+
+// assignTenants gives the reservations, serial units, orders and cart holds
+// of data files saved before they had a tenant the tenant of the stock they
+// refer to, as migration 0027 does for the PostgreSQL schema
+func (t *memoryTables) assignTenants() {
+	tenantOf := func(productID string) string {
+		if product, ok := t.Products[productID]; ok {
+			return product.Product.TenantID
+		}
+		return domain.DefaultTenantID
+	}
+
+	for _, reservation := range t.Reservations {
+		if reservation.TenantID == "" {
+			reservation.TenantID = tenantOf(reservation.ProductID)
+		}
+	}
+	for _, unit := range t.SerialUnits {
+		if unit.TenantID == "" {
+			unit.TenantID = tenantOf(unit.ProductID)
+		}
+	}
+	for _, order := range t.Orders {
+		if order.TenantID == "" {
+			order.TenantID = domain.DefaultTenantID
+			if len(order.Lines) > 0 {
+				order.TenantID = tenantOf(order.Lines[0].ProductID)
+			}
+		}
+	}
+	for _, hold := range t.CartHolds {
+		if hold.TenantID == "" {
+			hold.TenantID = domain.DefaultTenantID
+			if len(hold.Lines) > 0 {
+				hold.TenantID = tenantOf(hold.Lines[0].ProductID)
+			}
+		}
+	}
+}
+
+// page returns the rows of a listing from offset, at most limit of them
+func page[T any](rows []T, limit, offset int) []T {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// MemoryProductLocker implements ProductLocker for a MemoryStore, whose units
+// of work already exclude each other
+type MemoryProductLocker struct{}
+
+// NewMemoryProductLocker creates a new MemoryProductLocker
+func NewMemoryProductLocker() *MemoryProductLocker {
+	return &MemoryProductLocker{}
+}
+
+// LockProduct fails outside of a unit of work, like the PostgreSQL locker;
+// inside one the product is already locked
+func (l *MemoryProductLocker) LockProduct(ctx context.Context, productID string) error {
+	if !InTransaction(ctx) {
+		return errors.New("product lock requires a unit of work")
+	}
+	return nil
+}
+
+// MemoryConsistencyRepository implements ConsistencyRepository with the
+// number of units of work a MemoryStore committed
+type MemoryConsistencyRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryConsistencyRepository creates a new MemoryConsistencyRepository
+func NewMemoryConsistencyRepository(store *MemoryStore) *MemoryConsistencyRepository {
+	return &MemoryConsistencyRepository{store: store}
+}
+
+// CurrentLSN returns the number of units of work committed so far
+func (r *MemoryConsistencyRepository) CurrentLSN(ctx context.Context) (LSN, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return r.store.lsn, nil
+}
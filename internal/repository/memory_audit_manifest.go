@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryAuditManifestRepository implements AuditManifestRepository with a
+// MemoryStore
+type MemoryAuditManifestRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryAuditManifestRepository creates a new
+// MemoryAuditManifestRepository
+func NewMemoryAuditManifestRepository(store *MemoryStore) *MemoryAuditManifestRepository {
+	return &MemoryAuditManifestRepository{store: store}
+}
+
+// Create records the manifest of an export made in the tenant scope of ctx
+func (r *MemoryAuditManifestRepository) Create(ctx context.Context, manifest *domain.AuditManifest) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.AuditManifests[manifest.ID]; ok {
+			return fmt.Errorf("audit manifest %s already exists", manifest.ID)
+		}
+		put(tx, t.AuditManifests, manifest.ID, &memoryRow[domain.AuditManifest]{Row: *manifest, TenantID: tenantScope(ctx)})
+		return nil
+	})
+}
+
+// GetByID retrieves the manifest of an export. Manifests of exports of all
+// tenants are only visible without a tenant scope.
+func (r *MemoryAuditManifestRepository) GetByID(ctx context.Context, id string) (*domain.AuditManifest, error) {
+	var manifest *domain.AuditManifest
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.AuditManifests[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("audit manifest %w", domain.ErrNotFound)
+		}
+		copied := stored.Row
+		manifest = &copied
+		return nil
+	})
+	return manifest, err
+}
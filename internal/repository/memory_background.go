@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryBackgroundControlRepository implements BackgroundControlRepository
+// with a MemoryStore
+type MemoryBackgroundControlRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryBackgroundControlRepository creates a new
+// MemoryBackgroundControlRepository
+func NewMemoryBackgroundControlRepository(store *MemoryStore) *MemoryBackgroundControlRepository {
+	return &MemoryBackgroundControlRepository{store: store}
+}
+
+// Pause records a pause of a component. Pausing a paused component keeps the
+// pause recorded first and fills in the pause with it.
+func (r *MemoryBackgroundControlRepository) Pause(ctx context.Context, pause *domain.BackgroundPause) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if stored, ok := t.BackgroundPauses[pause.Component]; ok {
+			*pause = *stored
+			return nil
+		}
+		pause.PausedAt = time.Now()
+		stored := *pause
+		put(tx, t.BackgroundPauses, pause.Component, &stored)
+		return nil
+	})
+}
+
+// Resume removes the pause of a component, if any
+func (r *MemoryBackgroundControlRepository) Resume(ctx context.Context, component domain.BackgroundComponent) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		remove(tx, t.BackgroundPauses, component)
+		return nil
+	})
+}
+
+// ListPauses lists the paused components
+func (r *MemoryBackgroundControlRepository) ListPauses(ctx context.Context) ([]*domain.BackgroundPause, error) {
+	var pauses []*domain.BackgroundPause
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.BackgroundPauses {
+			copied := *stored
+			pauses = append(pauses, &copied)
+		}
+		return nil
+	})
+	slices.SortFunc(pauses, func(a, b *domain.BackgroundPause) int {
+		return cmp.Compare(a.Component, b.Component)
+	})
+	return pauses, err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryBackorderRepository implements BackorderRepository with a MemoryStore
+type MemoryBackorderRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryBackorderRepository creates a new MemoryBackorderRepository
+func NewMemoryBackorderRepository(store *MemoryStore) *MemoryBackorderRepository {
+	return &MemoryBackorderRepository{store: store}
+}
+
+// Create inserts a new backorder
+func (r *MemoryBackorderRepository) Create(ctx context.Context, backorder *domain.Backorder) error {
+	if err := backorder.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	backorder.ID = uuid.New().String()
+	now := time.Now()
+	backorder.CreatedAt = now
+	backorder.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Products[backorder.ProductID]; !ok {
+			return fmt.Errorf("failed to create backorder: product %s does not exist", backorder.ProductID)
+		}
+		stored := *backorder
+		put(tx, t.Backorders, backorder.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves a backorder by ID
+func (r *MemoryBackorderRepository) GetByID(ctx context.Context, id string) (*domain.Backorder, error) {
+	var backorder *domain.Backorder
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Backorders[id]
+		if !ok {
+			return fmt.Errorf("backorder %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		backorder = &copied
+		return nil
+	})
+	return backorder, err
+}
+
+// List retrieves backorders newest first, optionally restricted to a product
+// and a status
+func (r *MemoryBackorderRepository) List(ctx context.Context, productID string, status domain.BackorderStatus, limit, offset int) ([]*domain.Backorder, error) {
+	var backorders []*domain.Backorder
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Backorders {
+			if (productID == "" || stored.ProductID == productID) && (status == "" || stored.Status == status) {
+				copied := *stored
+				backorders = append(backorders, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(backorders, func(a, b *domain.Backorder) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return page(backorders, limit, offset), err
+}
+
+// UpdateStatus moves a backorder from one status to another. It reports false
+// when the backorder was no longer in the expected status.
+func (r *MemoryBackorderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.BackorderStatus) (bool, error) {
+	updated := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Backorders[id]
+		if !ok || stored.Status != from {
+			return nil
+		}
+		changed := *stored
+		changed.Status = to
+		changed.UpdatedAt = time.Now()
+		put(tx, t.Backorders, id, &changed)
+		updated = true
+		return nil
+	})
+	return updated, err
+}
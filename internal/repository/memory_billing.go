@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// memoryBillingKey is the primary key of the billing_events table, which
+// makes recording billing events idempotent
+type memoryBillingKey struct {
+	TenantID string
+	Type     domain.BillingEventType
+	SourceID string
+}
+
+// memoryBillingEvent is a row of the billing_events table
+type memoryBillingEvent struct {
+	Quantity   int64
+	Reference  string
+	OccurredAt time.Time
+}
+
+// recordBillingEvent stores a billing event unless its key was recorded
+// before, and reports whether it did
+func (t *memoryTables) recordBillingEvent(tx *memoryTx, key memoryBillingKey, event memoryBillingEvent) bool {
+	if _, ok := t.BillingEvents[key]; ok {
+		return false
+	}
+	put(tx, t.BillingEvents, key, event)
+	return true
+}
+
+// MemoryBillingRepository implements BillingRepository with a MemoryStore
+type MemoryBillingRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryBillingRepository creates a new MemoryBillingRepository
+func NewMemoryBillingRepository(store *MemoryStore) *MemoryBillingRepository {
+	return &MemoryBillingRepository{store: store}
+}
+
+// LatestMovementAt returns when the latest receipt, pick or shipment recorded
+// occurred, or the zero time when none was recorded yet
+func (r *MemoryBillingRepository) LatestMovementAt(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, event := range t.BillingEvents {
+			if key.Type != domain.BillingEventStorage && event.OccurredAt.After(latest) {
+				latest = event.OccurredAt
+			}
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// RecordMovements records the receipts (IN and RETURN transactions) and picks
+// (OUT transactions) of the ledger created at or after since, archived ones
+// included, and a shipment per tenant and reference of the picks. Events
+// recorded before are skipped, so overlapping calls are harmless. It returns
+// the number of events recorded.
+func (r *MemoryBillingRepository) RecordMovements(ctx context.Context, since time.Time) (int64, error) {
+	var recorded int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		shipments := make(map[memoryBillingKey]time.Time)
+		for _, row := range t.Transactions {
+			transaction := &row.Transaction
+			if transaction.CreatedAt.Before(since) {
+				continue
+			}
+
+			var eventType domain.BillingEventType
+			switch transaction.Type {
+			case "IN", "RETURN":
+				eventType = domain.BillingEventReceipt
+			case "OUT":
+				eventType = domain.BillingEventPick
+				if transaction.Reference != "" {
+					key := memoryBillingKey{TenantID: row.TenantID, Type: domain.BillingEventShipment, SourceID: transaction.Reference}
+					if first, ok := shipments[key]; !ok || transaction.CreatedAt.Before(first) {
+						shipments[key] = transaction.CreatedAt
+					}
+				}
+			default:
+				continue
+			}
+
+			key := memoryBillingKey{TenantID: row.TenantID, Type: eventType, SourceID: transaction.ID}
+			event := memoryBillingEvent{Quantity: transaction.Quantity, Reference: transaction.Reference, OccurredAt: transaction.CreatedAt}
+			if t.recordBillingEvent(tx, key, event) {
+				recorded++
+			}
+		}
+
+		for key, occurredAt := range shipments {
+			if t.recordBillingEvent(tx, key, memoryBillingEvent{Quantity: 1, Reference: key.SourceID, OccurredAt: occurredAt}) {
+				recorded++
+			}
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// RecordStorage records the pallets every tenant stores on the day, unless
+// they were recorded for the day already. Each inventory row with stock
+// takes up its quantity divided by unitsPerPallet, rounded up. It returns
+// the number of tenants recorded.
+func (r *MemoryBillingRepository) RecordStorage(ctx context.Context, day time.Time, unitsPerPallet int64) (int64, error) {
+	var recorded int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		pallets := make(map[string]int64)
+		for _, item := range t.Inventory {
+			if item.Quantity > 0 {
+				pallets[item.TenantID] += (item.Quantity + unitsPerPallet - 1) / unitsPerPallet
+			}
+		}
+		for tenantID, quantity := range pallets {
+			key := memoryBillingKey{TenantID: tenantID, Type: domain.BillingEventStorage, SourceID: day.Format(time.DateOnly)}
+			if t.recordBillingEvent(tx, key, memoryBillingEvent{Quantity: quantity, OccurredAt: day}) {
+				recorded++
+			}
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// Billing totals the events that occurred on the days from from to to,
+// inclusive, per tenant. A non-empty tenant restricts the result to it.
+func (r *MemoryBillingRepository) Billing(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantBilling, error) {
+	byTenant := make(map[string]*domain.TenantBilling)
+	end := to.AddDate(0, 0, 1)
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, event := range t.BillingEvents {
+			if event.OccurredAt.Before(from) || !event.OccurredAt.Before(end) ||
+				(tenant != "" && key.TenantID != tenant) || !inTenantScope(ctx, key.TenantID) {
+				continue
+			}
+			if byTenant[key.TenantID] == nil {
+				byTenant[key.TenantID] = &domain.TenantBilling{TenantID: key.TenantID}
+			}
+			byTenant[key.TenantID].Add(key.Type, 1, event.Quantity)
+		}
+		return nil
+	})
+
+	var billing []*domain.TenantBilling
+	for _, tenantBilling := range byTenant {
+		billing = append(billing, tenantBilling)
+	}
+	slices.SortFunc(billing, func(a, b *domain.TenantBilling) int { return cmp.Compare(a.TenantID, b.TenantID) })
+	return billing, err
+}
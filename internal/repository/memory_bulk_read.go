@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryBulkReadRepository implements BulkReadRepository with a MemoryStore
+type MemoryBulkReadRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryBulkReadRepository creates a new MemoryBulkReadRepository
+func NewMemoryBulkReadRepository(store *MemoryStore) *MemoryBulkReadRepository {
+	return &MemoryBulkReadRepository{store: store}
+}
+
+// AvailabilityBySKUs sums the stock of the active products with the given
+// SKUs over all their locations. Unknown and archived SKUs are left out.
+func (r *MemoryBulkReadRepository) AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error) {
+	var items []*domain.SKUAvailability
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for id, row := range t.Products {
+			product := row.Product
+			if product.DeletedAt != nil || !slices.Contains(skus, product.SKU) || !inTenantScope(ctx, product.TenantID) {
+				continue
+			}
+			item := &domain.SKUAvailability{SKU: product.SKU, ProductID: id}
+			for _, stock := range t.Inventory {
+				if stock.ProductID == id {
+					item.Quantity += stock.Quantity
+					item.Reserved += stock.Reserved
+					item.Available += max(stock.Quantity-stock.Reserved, 0)
+				}
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// ExportProducts reads the next page of a catalog export: up to limit
+// products matching filter with IDs after afterID, in ID order, each with its
+// stock at every location
+func (r *MemoryBulkReadRepository) ExportProducts(ctx context.Context, filter ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error) {
+	var products []*domain.ProductExport
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for id, row := range t.Products {
+			product := row.Product
+			if id <= afterID || (filter.Category != "" && product.Category != filter.Category) ||
+				(!filter.UpdatedSince.IsZero() && product.UpdatedAt.Before(filter.UpdatedSince)) ||
+				(!filter.IncludeArchived && product.DeletedAt != nil) || !inTenantScope(ctx, product.TenantID) {
+				continue
+			}
+			products = append(products, &domain.ProductExport{
+				ProductID: id,
+				SKU:       product.SKU,
+				Name:      product.Name,
+				Category:  product.Category,
+				Price:     product.Price,
+				Archived:  product.DeletedAt != nil,
+				UpdatedAt: product.UpdatedAt,
+				Locations: []*domain.ProductExportStock{},
+			})
+		}
+		slices.SortFunc(products, func(a, b *domain.ProductExport) int {
+			return cmp.Compare(a.ProductID, b.ProductID)
+		})
+		products = page(products, limit, 0)
+
+		for _, product := range products {
+			stock := t.items(ctx, func(item *domain.InventoryItem) bool { return item.ProductID == product.ProductID })
+			slices.SortFunc(stock, func(a, b *domain.InventoryItem) int {
+				return cmp.Or(cmp.Compare(a.Location, b.Location), cmp.Compare(a.Condition, b.Condition))
+			})
+			for _, item := range stock {
+				product.AddStock(&domain.ProductExportStock{
+					Location:  item.Location,
+					Condition: item.Condition,
+					Quantity:  item.Quantity,
+					Reserved:  item.Reserved,
+					Available: max(item.Quantity-item.Reserved, 0),
+				})
+			}
+		}
+		return nil
+	})
+	return products, err
+}
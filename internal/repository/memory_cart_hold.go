@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryCartHoldRepository implements CartHoldRepository with a MemoryStore
+type MemoryCartHoldRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryCartHoldRepository creates a new MemoryCartHoldRepository
+func NewMemoryCartHoldRepository(store *MemoryStore) *MemoryCartHoldRepository {
+	return &MemoryCartHoldRepository{store: store}
+}
+
+// Create records a hold if every line fits the stock that is neither
+// reserved nor kept by other active holds, and returns ErrInsufficientStock
+// otherwise
+func (r *MemoryCartHoldRepository) Create(ctx context.Context, hold *domain.CartHold) error {
+	if err := hold.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	needed := make(map[string]int64, len(hold.Lines))
+	for _, line := range hold.Lines {
+		needed[line.InventoryID] += line.Quantity
+	}
+
+	hold.ID = uuid.New().String()
+	hold.TenantID = tenantOwner(ctx)
+	now := time.Now()
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		held := make(map[string]int64)
+		for _, other := range t.CartHolds {
+			if other.Status != domain.CartHoldStatusHeld || !other.ExpiresAt.After(now) {
+				continue
+			}
+			for _, line := range other.Lines {
+				held[line.InventoryID] += line.Quantity
+			}
+		}
+
+		for _, line := range hold.Lines {
+			var available int64
+			if item, ok := t.itemInScope(ctx, line.InventoryID); ok {
+				available = item.Quantity - item.Reserved
+			}
+			if available-held[line.InventoryID] < needed[line.InventoryID] {
+				return fmt.Errorf("%w to hold %s", domain.ErrInsufficientStock, line.SKU)
+			}
+		}
+
+		stored := *hold
+		stored.Lines = slices.Clone(hold.Lines)
+		stored.Reservations = nil
+		put(tx, t.CartHolds, hold.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves a cart hold with its lines
+func (r *MemoryCartHoldRepository) GetByID(ctx context.Context, id string) (*domain.CartHold, error) {
+	var hold *domain.CartHold
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.CartHolds[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("cart hold %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		copied.Lines = slices.Clone(stored.Lines)
+		hold = &copied
+		return nil
+	})
+	return hold, err
+}
+
+// Claim moves a hold that is still active at now to the given status. It
+// reports false when the hold was no longer held or had expired.
+func (r *MemoryCartHoldRepository) Claim(ctx context.Context, id string, to domain.CartHoldStatus, now time.Time) (bool, error) {
+	return r.update(ctx, id, to, now, func(hold *domain.CartHold) bool {
+		return hold.Status == domain.CartHoldStatusHeld && hold.ExpiresAt.After(now)
+	})
+}
+
+// UpdateStatus moves a hold from one status to another. It reports false
+// when the hold was no longer in the expected status.
+func (r *MemoryCartHoldRepository) UpdateStatus(ctx context.Context, id string, from, to domain.CartHoldStatus) (bool, error) {
+	return r.update(ctx, id, to, time.Now(), func(hold *domain.CartHold) bool { return hold.Status == from })
+}
+
+// ExpireHeld marks the holds whose expiry time has passed as expired and
+// returns how many there were
+func (r *MemoryCartHoldRepository) ExpireHeld(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for id, hold := range t.CartHolds {
+			if hold.Status == domain.CartHoldStatusHeld && !hold.ExpiresAt.After(now) && inTenantScope(ctx, hold.TenantID) {
+				changed := *hold
+				changed.Status = domain.CartHoldStatusExpired
+				changed.UpdatedAt = now
+				put(tx, t.CartHolds, id, &changed)
+				expired++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire cart holds: %w", err)
+	}
+	return expired, nil
+}
+
+// update moves the hold to a status if allowed accepts it, and reports
+// whether it did
+func (r *MemoryCartHoldRepository) update(ctx context.Context, id string, to domain.CartHoldStatus, now time.Time, allowed func(hold *domain.CartHold) bool) (bool, error) {
+	updated := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		hold, ok := t.CartHolds[id]
+		if !ok || !inTenantScope(ctx, hold.TenantID) || !allowed(hold) {
+			return nil
+		}
+		changed := *hold
+		changed.Status = to
+		changed.UpdatedAt = now
+		put(tx, t.CartHolds, id, &changed)
+		updated = true
+		return nil
+	})
+	return updated, err
+}
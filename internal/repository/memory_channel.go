@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryAllocationKey is the primary key of a channel allocation
+type memoryAllocationKey struct {
+	ChannelID string
+	ProductID string
+}
+
+// MemorySalesChannelRepository implements SalesChannelRepository with a MemoryStore
+type MemorySalesChannelRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySalesChannelRepository creates a new MemorySalesChannelRepository
+func NewMemorySalesChannelRepository(store *MemoryStore) *MemorySalesChannelRepository {
+	return &MemorySalesChannelRepository{store: store}
+}
+
+// Create inserts a new sales channel. It returns ErrDuplicateChannel when the
+// tenant already has a channel of the name.
+func (r *MemorySalesChannelRepository) Create(ctx context.Context, channel *domain.SalesChannel) error {
+	if err := channel.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	channel.ID = uuid.New().String()
+	channel.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		owner := tenantOwner(ctx)
+		for _, other := range t.Channels {
+			if other.TenantID == owner && other.Row.Name == channel.Name {
+				return domain.ErrDuplicateChannel
+			}
+		}
+		put(tx, t.Channels, channel.ID, &memoryRow[domain.SalesChannel]{Row: *channel, TenantID: owner})
+		return nil
+	})
+}
+
+// GetByID retrieves a sales channel by ID
+func (r *MemorySalesChannelRepository) GetByID(ctx context.Context, id string) (*domain.SalesChannel, error) {
+	var channel *domain.SalesChannel
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Channels[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("sales channel %w", domain.ErrNotFound)
+		}
+		copied := stored.Row
+		channel = &copied
+		return nil
+	})
+	return channel, err
+}
+
+// List retrieves all sales channels ordered by name
+func (r *MemorySalesChannelRepository) List(ctx context.Context) ([]*domain.SalesChannel, error) {
+	var channels []*domain.SalesChannel
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Channels {
+			if inTenantScope(ctx, stored.TenantID) {
+				copied := stored.Row
+				channels = append(channels, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(channels, func(a, b *domain.SalesChannel) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return channels, err
+}
+
+// SetAllocation creates or replaces the allocation of a product to a channel
+func (r *MemorySalesChannelRepository) SetAllocation(ctx context.Context, allocation *domain.ChannelAllocation) error {
+	if err := allocation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	allocation.UpdatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Channels[allocation.ChannelID]; !ok {
+			return fmt.Errorf("failed to set channel allocation: channel %s does not exist", allocation.ChannelID)
+		}
+		if _, ok := t.Products[allocation.ProductID]; !ok {
+			return fmt.Errorf("failed to set channel allocation: product %s does not exist", allocation.ProductID)
+		}
+		stored := *allocation
+		put(tx, t.ChannelAllocations, memoryAllocationKey{ChannelID: allocation.ChannelID, ProductID: allocation.ProductID}, &stored)
+		return nil
+	})
+}
+
+// GetAllocation retrieves the allocation of a product to a channel, or nil
+// when the product is not allocated
+func (r *MemorySalesChannelRepository) GetAllocation(ctx context.Context, channelID, productID string) (*domain.ChannelAllocation, error) {
+	var allocation *domain.ChannelAllocation
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		if stored, ok := t.ChannelAllocations[memoryAllocationKey{ChannelID: channelID, ProductID: productID}]; ok {
+			copied := *stored
+			allocation = &copied
+		}
+		return nil
+	})
+	return allocation, err
+}
+
+// ListAllocations retrieves the allocations of a channel ordered by product
+func (r *MemorySalesChannelRepository) ListAllocations(ctx context.Context, channelID string) ([]*domain.ChannelAllocation, error) {
+	var allocations []*domain.ChannelAllocation
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, stored := range t.ChannelAllocations {
+			if key.ChannelID == channelID {
+				copied := *stored
+				allocations = append(allocations, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(allocations, func(a, b *domain.ChannelAllocation) int {
+		return cmp.Compare(a.ProductID, b.ProductID)
+	})
+	return allocations, err
+}
+
+// DeleteAllocation removes the allocation of a product to a channel
+func (r *MemorySalesChannelRepository) DeleteAllocation(ctx context.Context, channelID, productID string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		key := memoryAllocationKey{ChannelID: channelID, ProductID: productID}
+		if _, ok := t.ChannelAllocations[key]; !ok {
+			return fmt.Errorf("channel allocation %w", domain.ErrNotFound)
+		}
+		remove(tx, t.ChannelAllocations, key)
+		return nil
+	})
+}
+
+// RecordEvent stores a change of a channel allocation
+func (r *MemorySalesChannelRepository) RecordEvent(ctx context.Context, event *domain.ChannelAllocationEvent) error {
+	event.ID = uuid.New().String()
+	event.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored := *event
+		put(tx, t.ChannelAllocationEvents, event.ID, &stored)
+		return nil
+	})
+}
+
+// ListEvents retrieves a page of the allocation changes of a channel, newest first
+func (r *MemorySalesChannelRepository) ListEvents(ctx context.Context, channelID string, limit, offset int) ([]*domain.ChannelAllocationEvent, error) {
+	var events []*domain.ChannelAllocationEvent
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.ChannelAllocationEvents {
+			if stored.ChannelID == channelID {
+				copied := *stored
+				events = append(events, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(events, func(a, b *domain.ChannelAllocationEvent) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(events, limit, offset), err
+}
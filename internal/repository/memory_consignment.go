@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryConsignmentRepository implements ConsignmentRepository with a
+// MemoryStore
+type MemoryConsignmentRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryConsignmentRepository creates a new MemoryConsignmentRepository
+func NewMemoryConsignmentRepository(store *MemoryStore) *MemoryConsignmentRepository {
+	return &MemoryConsignmentRepository{store: store}
+}
+
+// Create records a consignment lot
+func (r *MemoryConsignmentRepository) Create(ctx context.Context, consignment *domain.Consignment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Products[consignment.ProductID]; !ok {
+			return fmt.Errorf("failed to create consignment: product %s does not exist", consignment.ProductID)
+		}
+		put(tx, t.Consignments, consignment.ID, &memoryRow[domain.Consignment]{Row: *consignment, TenantID: tenantOwner(ctx)})
+		return nil
+	})
+}
+
+// GetByID retrieves a consignment lot
+func (r *MemoryConsignmentRepository) GetByID(ctx context.Context, id string) (*domain.Consignment, error) {
+	var consignment *domain.Consignment
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Consignments[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("consignment %w", domain.ErrNotFound)
+		}
+		copied := stored.Row
+		consignment = &copied
+		return nil
+	})
+	return consignment, err
+}
+
+// consignments returns copies of the lots in the tenant scope of ctx
+// matching match, oldest first
+func (t *memoryTables) consignments(ctx context.Context, match func(*domain.Consignment) bool) []*domain.Consignment {
+	var consignments []*domain.Consignment
+	for _, stored := range t.Consignments {
+		if inTenantScope(ctx, stored.TenantID) && match(&stored.Row) {
+			copied := stored.Row
+			consignments = append(consignments, &copied)
+		}
+	}
+	slices.SortFunc(consignments, func(a, b *domain.Consignment) int {
+		return cmp.Or(a.ReceivedAt.Compare(b.ReceivedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return consignments
+}
+
+// List retrieves the consignment lots matching a filter, newest first
+func (r *MemoryConsignmentRepository) List(ctx context.Context, filter domain.ConsignmentFilter, limit, offset int) ([]*domain.Consignment, error) {
+	var consignments []*domain.Consignment
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		consignments = t.consignments(ctx, func(c *domain.Consignment) bool {
+			return (filter.Supplier == "" || c.Supplier == filter.Supplier) &&
+				(filter.ProductID == "" || c.ProductID == filter.ProductID) &&
+				(filter.Location == "" || c.Location == filter.Location) &&
+				(!filter.Open || c.Remaining > 0)
+		})
+		return nil
+	})
+	slices.Reverse(consignments)
+	return page(consignments, limit, offset), err
+}
+
+// LockOpen retrieves the lots of a product at a location the supplier still
+// owns units of, oldest first. The unit of work of ctx holds the store, so
+// concurrent sales take each unit once.
+func (r *MemoryConsignmentRepository) LockOpen(ctx context.Context, productID, location string) ([]*domain.Consignment, error) {
+	var consignments []*domain.Consignment
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		consignments = t.consignments(ctx, func(c *domain.Consignment) bool {
+			return c.ProductID == productID && c.Location == location && c.Remaining > 0
+		})
+		return nil
+	})
+	return consignments, err
+}
+
+// RecordTransfer takes the units of an ownership transfer off its lot and
+// records the transfer with the payable it creates
+func (r *MemoryConsignmentRepository) RecordTransfer(ctx context.Context, transfer *domain.OwnershipTransfer, payable *domain.SupplierPayable) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Consignments[transfer.ConsignmentID]
+		if !ok || stored.Row.Remaining < transfer.Quantity {
+			return fmt.Errorf("consignment %s has fewer than %d units left: %w", transfer.ConsignmentID, transfer.Quantity, domain.ErrConflict)
+		}
+		updated := *stored
+		updated.Row.Remaining -= transfer.Quantity
+		put(tx, t.Consignments, transfer.ConsignmentID, &updated)
+
+		tenant := tenantOwner(ctx)
+		recorded := *transfer
+		recorded.SoldAt = recorded.SoldAt.UTC()
+		put(tx, t.OwnershipTransfers, transfer.ID, &memoryRow[domain.OwnershipTransfer]{Row: recorded, TenantID: tenant})
+		put(tx, t.SupplierPayables, payable.ID, &memoryRow[domain.SupplierPayable]{Row: *payable, TenantID: tenant})
+		return nil
+	})
+}
+
+// ListTransfers retrieves the ownership transfers of a lot, oldest first
+func (r *MemoryConsignmentRepository) ListTransfers(ctx context.Context, consignmentID string, limit, offset int) ([]*domain.OwnershipTransfer, error) {
+	var transfers []*domain.OwnershipTransfer
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.OwnershipTransfers {
+			if stored.Row.ConsignmentID == consignmentID && inTenantScope(ctx, stored.TenantID) {
+				copied := stored.Row
+				transfers = append(transfers, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(transfers, func(a, b *domain.OwnershipTransfer) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(transfers, limit, offset), err
+}
+
+// Settlement totals the payables recorded on the days from from to to,
+// inclusive, per supplier, product and location. A non-empty supplier
+// restricts the result to it.
+func (r *MemoryConsignmentRepository) Settlement(ctx context.Context, supplier string, from, to time.Time) ([]*domain.SupplierSettlement, error) {
+	type group struct{ supplier, productID, location string }
+	totals := map[group]*domain.ConsignmentSettlementLine{}
+	end := to.AddDate(0, 0, 1)
+
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.SupplierPayables {
+			payable := stored.Row
+			if payable.CreatedAt.Before(from) || !payable.CreatedAt.Before(end) ||
+				(supplier != "" && payable.Supplier != supplier) || !inTenantScope(ctx, stored.TenantID) {
+				continue
+			}
+			transfer, ok := t.OwnershipTransfers[payable.TransferID]
+			if !ok {
+				continue
+			}
+			key := group{payable.Supplier, transfer.Row.ProductID, transfer.Row.Location}
+			line, ok := totals[key]
+			if !ok {
+				line = &domain.ConsignmentSettlementLine{ProductID: key.productID, Location: key.location}
+				totals[key] = line
+			}
+			line.Payables++
+			line.Units += transfer.Row.Quantity
+			line.Amount += payable.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := slices.Collect(maps.Keys(totals))
+	slices.SortFunc(keys, func(a, b group) int {
+		return cmp.Or(cmp.Compare(a.supplier, b.supplier), cmp.Compare(a.productID, b.productID), cmp.Compare(a.location, b.location))
+	})
+	var settlements []*domain.SupplierSettlement
+	for _, key := range keys {
+		if len(settlements) == 0 || settlements[len(settlements)-1].Supplier != key.supplier {
+			settlements = append(settlements, &domain.SupplierSettlement{Supplier: key.supplier})
+		}
+		settlements[len(settlements)-1].Add(totals[key])
+	}
+	return settlements, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// dataQualityFindings returns, per check, the findings in the tables. The
+// cascades of the memory store rule most of these out, like the foreign keys
+// of the PostgreSQL schema do, but a data file written by an older version
+// may still hold them.
+var dataQualityFindings = map[domain.DataQualityCheck]func(t *memoryTables) []*domain.DataQualityFinding{
+	domain.CheckProductWithoutInventory: func(t *memoryTables) []*domain.DataQualityFinding {
+		stocked := make(map[string]bool)
+		for _, item := range t.Inventory {
+			stocked[item.ProductID] = true
+		}
+		var findings []*domain.DataQualityFinding
+		for id, row := range t.Products {
+			if row.Product.DeletedAt == nil && !stocked[id] {
+				findings = append(findings, &domain.DataQualityFinding{EntityID: id, ProductID: id})
+			}
+		}
+		return findings
+	},
+	domain.CheckInventoryWithoutProduct: func(t *memoryTables) []*domain.DataQualityFinding {
+		var findings []*domain.DataQualityFinding
+		for id, item := range t.Inventory {
+			if _, ok := t.Products[item.ProductID]; !ok {
+				findings = append(findings, &domain.DataQualityFinding{
+					EntityID: id, ProductID: item.ProductID, InventoryID: id, Quantity: item.Quantity, Reserved: item.Reserved,
+				})
+			}
+		}
+		return findings
+	},
+	domain.CheckReservedExceedsQuantity: func(t *memoryTables) []*domain.DataQualityFinding {
+		var findings []*domain.DataQualityFinding
+		for id, item := range t.Inventory {
+			if item.Reserved > item.Quantity {
+				findings = append(findings, &domain.DataQualityFinding{
+					EntityID: id, ProductID: item.ProductID, InventoryID: id, Quantity: item.Quantity, Reserved: item.Reserved,
+				})
+			}
+		}
+		return findings
+	},
+	domain.CheckOrphanedTransaction: func(t *memoryTables) []*domain.DataQualityFinding {
+		var findings []*domain.DataQualityFinding
+		for id, row := range t.Transactions {
+			_, item := t.Inventory[row.Transaction.InventoryID]
+			_, product := t.Products[row.Transaction.ProductID]
+			if !item || !product {
+				findings = append(findings, &domain.DataQualityFinding{
+					EntityID: id, ProductID: row.Transaction.ProductID, InventoryID: row.Transaction.InventoryID,
+					Quantity: row.Transaction.Quantity,
+				})
+			}
+		}
+		return findings
+	},
+}
+
+// MemoryDataQualityRepository implements DataQualityRepository with a
+// MemoryStore
+type MemoryDataQualityRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryDataQualityRepository creates a new MemoryDataQualityRepository
+func NewMemoryDataQualityRepository(store *MemoryStore) *MemoryDataQualityRepository {
+	return &MemoryDataQualityRepository{store: store}
+}
+
+// Check runs a data quality check across all tenants. It returns at most
+// limit findings and the total number found.
+func (r *MemoryDataQualityRepository) Check(ctx context.Context, check domain.DataQualityCheck, limit int) ([]*domain.DataQualityFinding, int, error) {
+	find, ok := dataQualityFindings[check]
+	if !ok {
+		return nil, 0, domain.NewValidationError("unknown data quality check %q", check)
+	}
+
+	var findings []*domain.DataQualityFinding
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		findings = find(t)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, finding := range findings {
+		finding.Check = check
+	}
+	slices.SortFunc(findings, func(a, b *domain.DataQualityFinding) int { return cmp.Compare(a.EntityID, b.EntityID) })
+	return page(findings, limit, 0), len(findings), nil
+}
+
+// DeleteOrphanedInventory deletes an inventory row without a product, as
+// long as it holds no stock. It returns false when the row was not deleted.
+func (r *MemoryDataQualityRepository) DeleteOrphanedInventory(ctx context.Context, id string) (bool, error) {
+	deleted := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		item, ok := t.Inventory[id]
+		if !ok || item.Quantity != 0 || item.Reserved != 0 {
+			return nil
+		}
+		if _, ok := t.Products[item.ProductID]; ok {
+			return nil
+		}
+		t.deleteItem(tx, id)
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
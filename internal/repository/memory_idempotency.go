@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryIdempotencyRepository implements IdempotencyRepository with a
+// MemoryStore
+type MemoryIdempotencyRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryIdempotencyRepository creates a new MemoryIdempotencyRepository
+func NewMemoryIdempotencyRepository(store *MemoryStore) *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{store: store}
+}
+
+// Reserve claims a key for a new request. It reports false when the key is
+// already held by a record created after expiredBefore; older records are
+// taken over, so keys can be reused once they expire.
+func (r *MemoryIdempotencyRepository) Reserve(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore time.Time) (bool, error) {
+	record.CreatedAt = time.Now()
+
+	reserved := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if held, ok := t.IdempotencyKeys[record.Key]; ok && !held.CreatedAt.Before(expiredBefore) {
+			return nil
+		}
+		put(tx, t.IdempotencyKeys, record.Key, &domain.IdempotencyRecord{
+			Key: record.Key, RequestHash: record.RequestHash, CreatedAt: record.CreatedAt,
+		})
+		reserved = true
+		return nil
+	})
+	return reserved, err
+}
+
+// Get retrieves the record of a key
+func (r *MemoryIdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	var record *domain.IdempotencyRecord
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.IdempotencyKeys[key]
+		if !ok {
+			return fmt.Errorf("idempotency key %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		copied.Response = bytes.Clone(stored.Response)
+		record = &copied
+		return nil
+	})
+	return record, err
+}
+
+// Complete stores the response of the request that holds a key
+func (r *MemoryIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.IdempotencyKeys[key]
+		if !ok {
+			return nil
+		}
+		completed := *stored
+		completed.StatusCode = statusCode
+		completed.Response = bytes.Clone(response)
+		now := time.Now()
+		completed.CompletedAt = &now
+		put(tx, t.IdempotencyKeys, key, &completed)
+		return nil
+	})
+}
+
+// Delete releases a key so that the request can be retried
+func (r *MemoryIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		remove(tx, t.IdempotencyKeys, key)
+		return nil
+	})
+}
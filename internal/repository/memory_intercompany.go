@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryIntercompanyTransferRepository implements
+// IntercompanyTransferRepository with a MemoryStore
+type MemoryIntercompanyTransferRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryIntercompanyTransferRepository creates a new
+// MemoryIntercompanyTransferRepository
+func NewMemoryIntercompanyTransferRepository(store *MemoryStore) *MemoryIntercompanyTransferRepository {
+	return &MemoryIntercompanyTransferRepository{store: store}
+}
+
+// Create records a transfer document
+func (r *MemoryIntercompanyTransferRepository) Create(ctx context.Context, transfer *domain.IntercompanyTransfer) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.IntercompanyTransfers[transfer.ID]; ok {
+			return fmt.Errorf("failed to create intercompany transfer: %s already exists", transfer.ID)
+		}
+		stored := *transfer
+		put(tx, t.IntercompanyTransfers, transfer.ID, &stored)
+		return nil
+	})
+}
+
+// intercompanyParty reports whether the tenant of ctx sent or received a transfer
+func intercompanyParty(ctx context.Context, transfer *domain.IntercompanyTransfer) bool {
+	scope := tenantScope(ctx)
+	return scope == "" || transfer.FromTenant == scope || transfer.ToTenant == scope
+}
+
+// GetByID retrieves a transfer sent or received by the tenant of ctx
+func (r *MemoryIntercompanyTransferRepository) GetByID(ctx context.Context, id string) (*domain.IntercompanyTransfer, error) {
+	var transfer *domain.IntercompanyTransfer
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.IntercompanyTransfers[id]
+		if !ok || !intercompanyParty(ctx, stored) {
+			return fmt.Errorf("intercompany transfer %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		transfer = &copied
+		return nil
+	})
+	return transfer, err
+}
+
+// List retrieves the transfers sent or received by the tenant of ctx,
+// newest first
+func (r *MemoryIntercompanyTransferRepository) List(ctx context.Context, limit, offset int) ([]*domain.IntercompanyTransfer, error) {
+	var transfers []*domain.IntercompanyTransfer
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.IntercompanyTransfers {
+			if intercompanyParty(ctx, stored) {
+				copied := *stored
+				transfers = append(transfers, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(transfers, func(a, b *domain.IntercompanyTransfer) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return page(transfers, limit, offset), err
+}
@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// itemInScope returns the inventory item with the given ID if ctx may read it
+func (t *memoryTables) itemInScope(ctx context.Context, id string) (*domain.InventoryItem, bool) {
+	item, ok := t.Inventory[id]
+	if !ok || !inTenantScope(ctx, item.TenantID) {
+		return nil, false
+	}
+	return item, true
+}
+
+// items returns copies of the inventory items in scope that match, oldest
+// first
+func (t *memoryTables) items(ctx context.Context, match func(item *domain.InventoryItem) bool) []*domain.InventoryItem {
+	var items []*domain.InventoryItem
+	for _, item := range t.Inventory {
+		if inTenantScope(ctx, item.TenantID) && match(item) {
+			copied := *item
+			items = append(items, &copied)
+		}
+	}
+	slices.SortFunc(items, func(a, b *domain.InventoryItem) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return items
+}
+
+// changeStock adds the deltas to the stock of an inventory item in scope if
+// accept allows the result, and reports whether it did
+func (t *memoryTables) changeStock(ctx context.Context, tx *memoryTx, id string, quantityDelta, reservedDelta int64, now time.Time, accept func(quantity, reserved int64) bool) bool {
+	item, ok := t.itemInScope(ctx, id)
+	if !ok || !accept(item.Quantity+quantityDelta, item.Reserved+reservedDelta) {
+		return false
+	}
+	updated := *item
+	updated.Quantity += quantityDelta
+	updated.Reserved += reservedDelta
+	updated.UpdatedAt = now
+	updated.Version++
+	put(tx, t.Inventory, id, &updated)
+	return true
+}
+
+// deleteItem deletes an inventory item with its transactions, reservations,
+// stock alerts and lots, as the foreign keys of the PostgreSQL schema cascade
+func (t *memoryTables) deleteItem(tx *memoryTx, id string) {
+	remove(tx, t.Inventory, id)
+	for transactionID, row := range t.Transactions {
+		if row.Transaction.InventoryID == id {
+			remove(tx, t.Transactions, transactionID)
+		}
+	}
+	for reservationID, reservation := range t.Reservations {
+		if reservation.InventoryID == id {
+			remove(tx, t.Reservations, reservationID)
+		}
+	}
+	for alertID, row := range t.StockAlerts {
+		if row.Row.InventoryID == id {
+			remove(tx, t.StockAlerts, alertID)
+		}
+	}
+	for lotID, row := range t.Lots {
+		if row.Row.InventoryID == id {
+			remove(tx, t.Lots, lotID)
+		}
+	}
+}
+
+// MemoryInventoryRepository implements InventoryRepository with a MemoryStore
+type MemoryInventoryRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryInventoryRepository creates a new MemoryInventoryRepository
+func NewMemoryInventoryRepository(store *MemoryStore) *MemoryInventoryRepository {
+	return &MemoryInventoryRepository{store: store}
+}
+
+// Create inserts a new inventory item, of the tenant of its product
+func (r *MemoryInventoryRepository) Create(ctx context.Context, item *domain.InventoryItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		product, ok := t.productInScope(ctx, item.ProductID)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		if item.Condition == "" {
+			item.Condition = domain.ConditionNew
+		}
+		for _, other := range t.Inventory {
+			if other.ProductID == item.ProductID && other.Location == item.Location && other.Condition == item.Condition {
+				return fmt.Errorf("failed to create inventory item: product %s is already stocked at %s in condition %s",
+					item.ProductID, item.Location, item.Condition)
+			}
+		}
+
+		item.ID = uuid.New().String()
+		item.TenantID = product.Product.TenantID
+		item.Version = 1
+		now := time.Now()
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		stored := *item
+		stored.ProjectedStockoutAt = nil
+		put(tx, t.Inventory, item.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves an inventory item by ID
+func (r *MemoryInventoryRepository) GetByID(ctx context.Context, id string) (*domain.InventoryItem, error) {
+	var item *domain.InventoryItem
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.itemInScope(ctx, id)
+		if !ok {
+			return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		item = &copied
+		return nil
+	})
+	return item, err
+}
+
+// GetByProductID retrieves the default inventory item of a product, which is
+// the first location it was stocked at
+func (r *MemoryInventoryRepository) GetByProductID(ctx context.Context, productID string) (*domain.InventoryItem, error) {
+	items, err := r.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	return items[0], nil
+}
+
+// GetByProductAndLocation retrieves the inventory item of a product at a
+// location in a condition
+func (r *MemoryInventoryRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		items = t.items(ctx, func(item *domain.InventoryItem) bool {
+			return item.ProductID == productID && item.Location == location && item.Condition == condition
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	return items[0], nil
+}
+
+// ListByProductID retrieves the inventory items of a product at all locations
+func (r *MemoryInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		items = t.items(ctx, func(item *domain.InventoryItem) bool { return item.ProductID == productID })
+		return nil
+	})
+	return items, err
+}
+
+// GetByProductIDs retrieves the inventory items of many products, keyed by
+// product ID
+func (r *MemoryInventoryRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string][]*domain.InventoryItem, error) {
+	items := make(map[string][]*domain.InventoryItem, len(productIDs))
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, item := range t.items(ctx, func(item *domain.InventoryItem) bool { return slices.Contains(productIDs, item.ProductID) }) {
+			items[item.ProductID] = append(items[item.ProductID], item)
+		}
+		return nil
+	})
+	return items, err
+}
+
+// List retrieves a paginated list of the inventory items matching filter,
+// newest first
+func (r *MemoryInventoryRepository) List(ctx context.Context, limit, offset int, filter *domain.Filter) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		items = t.items(ctx, func(item *domain.InventoryItem) bool { return filter.Matches(item.FilterValue) })
+		return nil
+	})
+	slices.Reverse(items)
+	return page(items, limit, offset), err
+}
+
+// Count returns the number of inventory items matching filter
+func (r *MemoryInventoryRepository) Count(ctx context.Context, filter *domain.Filter) (int64, error) {
+	var count int64
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		count = int64(len(t.items(ctx, func(item *domain.InventoryItem) bool { return filter.Matches(item.FilterValue) })))
+		return nil
+	})
+	return count, err
+}
+
+// Update updates an existing inventory item using optimistic concurrency: the
+// write only applies while the stored version still equals item.Version and
+// fails with domain.ErrConflict when another writer changed the row first
+func (r *MemoryInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.updateVersioned(ctx, item, func(updated *domain.InventoryItem) {
+		updated.Quantity = item.Quantity
+		updated.Reserved = item.Reserved
+		updated.Location = item.Location
+		updated.WarehouseID = item.WarehouseID
+		updated.ReorderLevel = item.ReorderLevel
+		updated.SafetyStock = item.SafetyStock
+		updated.BinLocation = item.BinLocation
+	})
+}
+
+// UpdateSettings saves the reorder level, safety stock and bin location of an
+// inventory item while the stored version still equals item.Version, leaving
+// quantities alone; it fails with domain.ErrConflict when the row changed first
+func (r *MemoryInventoryRepository) UpdateSettings(ctx context.Context, item *domain.InventoryItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.updateVersioned(ctx, item, func(updated *domain.InventoryItem) {
+		updated.ReorderLevel = item.ReorderLevel
+		updated.SafetyStock = item.SafetyStock
+		updated.BinLocation = item.BinLocation
+	})
+}
+
+// updateVersioned applies change to the stored copy of item if its version
+// still equals item.Version, and advances the version of both
+func (r *MemoryInventoryRepository) updateVersioned(ctx context.Context, item *domain.InventoryItem, change func(updated *domain.InventoryItem)) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.itemInScope(ctx, item.ID)
+		if !ok {
+			return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+		}
+		if stored.Version != item.Version {
+			return fmt.Errorf("%w: inventory item %s was modified concurrently", domain.ErrConflict, item.ID)
+		}
+
+		item.UpdatedAt = time.Now()
+		updated := *stored
+		change(&updated)
+		updated.UpdatedAt = item.UpdatedAt
+		updated.Version++
+		put(tx, t.Inventory, item.ID, &updated)
+		item.Version++
+		return nil
+	})
+}
+
+// Delete deletes an inventory item with its transactions
+func (r *MemoryInventoryRepository) Delete(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.itemInScope(ctx, id); !ok {
+			return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+		}
+		t.deleteItem(tx, id)
+		return nil
+	})
+}
+
+// UpdateQuantity updates the quantity and reserved quantities atomically
+func (r *MemoryInventoryRepository) UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if t.changeStock(ctx, tx, inventoryID, quantityDelta, reservedDelta, time.Now(), func(quantity, reserved int64) bool {
+			return quantity >= 0 && reserved >= 0 && quantity-reserved >= 0
+		}) {
+			return nil
+		}
+
+		item, ok := t.itemInScope(ctx, inventoryID)
+		if !ok {
+			return fmt.Errorf("quantity update failed: inventory item %w", domain.ErrNotFound)
+		}
+		slog.InfoContext(ctx, "quantity update rejected", "inventory_id", inventoryID,
+			"quantity", item.Quantity, "reserved", item.Reserved,
+			"quantity_delta", quantityDelta, "reserved_delta", reservedDelta)
+		return &domain.StockUpdateError{
+			InventoryID: inventoryID, QuantityDelta: quantityDelta, ReservedDelta: reservedDelta,
+			Quantity: item.Quantity, Reserved: item.Reserved,
+		}
+	})
+}
+
+// Transfer moves quantity from one inventory item to another and records the
+// paired transactions in one unit of work
+func (r *MemoryInventoryRepository) Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error {
+	if err := out.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		now := time.Now()
+		if !t.changeStock(ctx, tx, fromID, -quantity, 0, now, func(quantity, reserved int64) bool { return quantity-reserved >= 0 }) {
+			slog.DebugContext(ctx, "transfer rejected", "from_inventory_id", fromID, "quantity", quantity)
+			return fmt.Errorf("transfer failed: %w available or item not found", domain.ErrInsufficientStock)
+		}
+		if !t.changeStock(ctx, tx, toID, quantity, 0, now, func(quantity, reserved int64) bool { return true }) {
+			return fmt.Errorf("transfer failed: destination item %w", domain.ErrNotFound)
+		}
+
+		for _, transaction := range []*domain.Transaction{out, in} {
+			transaction.ID = uuid.New().String()
+			transaction.CreatedAt = now
+
+			if err := t.insertTransaction(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("failed to record transaction: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "transfer applied", "from_inventory_id", fromID, "to_inventory_id", toID, "quantity", quantity)
+
+	return nil
+}
+
+// ApplyBatch applies the quantity changes and records their transactions in
+// one unit of work. Changes are applied in order; if any of them would leave
+// its row with less stock than reserved or a negative reservation, the whole
+// batch is rolled back and ErrInsufficientStock is returned.
+func (r *MemoryInventoryRepository) ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error {
+	for i, change := range changes {
+		if err := change.Transaction.Validate(); err != nil {
+			return fmt.Errorf("item %d: validation error: %w", i+1, err)
+		}
+	}
+
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		now := time.Now()
+		for i, change := range changes {
+			if !t.changeStock(ctx, tx, change.InventoryID, change.QuantityDelta, change.ReservedDelta, now, func(quantity, reserved int64) bool {
+				return quantity >= reserved && reserved >= 0
+			}) {
+				slog.DebugContext(ctx, "stock batch rejected", "item", i+1, "inventory_id", change.InventoryID)
+				return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+			}
+
+			transaction := change.Transaction
+			transaction.ID = uuid.New().String()
+			transaction.CreatedAt = now
+
+			if err := t.insertTransaction(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("item %d: failed to record transaction: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "stock batch applied", "items", len(changes))
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryJob is a row of the jobs table, with the result document kept apart
+// from the job
+type memoryJob struct {
+	Job        domain.Job
+	Result     []byte
+	ResultType string
+}
+
+// unfinished reports whether the job is still pending or running
+func (row *memoryJob) unfinished() bool {
+	return row.Job.Status == domain.JobPending || row.Job.Status == domain.JobRunning
+}
+
+// MemoryJobRepository implements JobRepository with a MemoryStore
+type MemoryJobRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryJobRepository creates a new MemoryJobRepository
+func NewMemoryJobRepository(store *MemoryStore) *MemoryJobRepository {
+	return &MemoryJobRepository{store: store}
+}
+
+// Create inserts a new job
+func (r *MemoryJobRepository) Create(ctx context.Context, job *domain.Job) error {
+	job.ID = uuid.New().String()
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		put(tx, t.Jobs, job.ID, &memoryJob{Job: domain.Job{
+			ID: job.ID, Type: job.Type, Status: job.Status, CreatedBy: job.CreatedBy,
+			CreatedAt: job.CreatedAt, UpdatedAt: job.UpdatedAt,
+		}})
+		return nil
+	})
+}
+
+// GetByID retrieves a job by ID, without its result document
+func (r *MemoryJobRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
+	var job *domain.Job
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		row, ok := t.Jobs[id]
+		if !ok {
+			return fmt.Errorf("job %w", domain.ErrNotFound)
+		}
+		copied := row.Job
+		copied.Summary = bytes.Clone(row.Job.Summary)
+		copied.HasResult = row.Result != nil
+		job = &copied
+		return nil
+	})
+	return job, err
+}
+
+// UpdateProgress records the status and progress of an unfinished job,
+// which also serves as its heartbeat
+func (r *MemoryJobRepository) UpdateProgress(ctx context.Context, id string, status domain.JobStatus, progress domain.JobProgress) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.Jobs[id]
+		if !ok || !row.unfinished() {
+			return nil
+		}
+		updated := *row
+		updated.Job.Status = status
+		updated.Job.Progress = progress
+		updated.Job.UpdatedAt = time.Now()
+		put(tx, t.Jobs, id, &updated)
+		return nil
+	})
+}
+
+// Finish records the outcome of a job together with its result document
+func (r *MemoryJobRepository) Finish(ctx context.Context, job *domain.Job, result []byte, contentType string) error {
+	now := time.Now()
+	job.UpdatedAt = now
+	job.FinishedAt = &now
+	job.HasResult = result != nil
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.Jobs[job.ID]
+		if !ok {
+			return nil
+		}
+		updated := *row
+		updated.Job.Status = job.Status
+		updated.Job.Progress = job.Progress
+		updated.Job.Summary = nil
+		if len(job.Summary) > 0 {
+			updated.Job.Summary = bytes.Clone(job.Summary)
+		}
+		updated.Job.Error = job.Error
+		updated.Job.UpdatedAt = now
+		updated.Job.FinishedAt = &now
+		updated.Result = bytes.Clone(result)
+		updated.ResultType = contentType
+		put(tx, t.Jobs, job.ID, &updated)
+		return nil
+	})
+}
+
+// FailStale fails an unfinished job that reported no progress since
+// updatedBefore. It reports whether the job was failed.
+func (r *MemoryJobRepository) FailStale(ctx context.Context, id string, updatedBefore time.Time, message string) (bool, error) {
+	failed := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.Jobs[id]
+		if !ok || !row.unfinished() || !row.Job.UpdatedAt.Before(updatedBefore) {
+			return nil
+		}
+		now := time.Now()
+		updated := *row
+		updated.Job.Status = domain.JobFailed
+		updated.Job.Error = message
+		updated.Job.UpdatedAt = now
+		updated.Job.FinishedAt = &now
+		put(tx, t.Jobs, id, &updated)
+		failed = true
+		return nil
+	})
+	return failed, err
+}
+
+// GetResult retrieves the result document of a job
+func (r *MemoryJobRepository) GetResult(ctx context.Context, id string) (*domain.JobResult, error) {
+	var result *domain.JobResult
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		row, ok := t.Jobs[id]
+		if !ok || row.Result == nil {
+			return fmt.Errorf("job result %w", domain.ErrNotFound)
+		}
+		result = &domain.JobResult{Data: bytes.Clone(row.Result), ContentType: row.ResultType}
+		return nil
+	})
+	return result, err
+}
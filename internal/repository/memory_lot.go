@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MemoryLotRepository implements LotRepository with a MemoryStore
+type MemoryLotRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryLotRepository creates a new MemoryLotRepository
+func NewMemoryLotRepository(store *MemoryStore) *MemoryLotRepository {
+	return &MemoryLotRepository{store: store}
+}
+
+// Receive adds the units of lot to the lot of its number in its inventory
+// row, creating it if the row has none. The lot inherits the tenant, product
+// and location of the row. It returns the lot as stored, or a validation
+// error if the existing lot expires on another day.
+func (r *MemoryLotRepository) Receive(ctx context.Context, lot *domain.Lot) (*domain.Lot, error) {
+	var received domain.Lot
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		item, ok := t.itemInScope(ctx, lot.InventoryID)
+		if !ok {
+			return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+		}
+		for id, stored := range t.Lots {
+			if stored.Row.InventoryID != lot.InventoryID || stored.Row.LotNumber != lot.LotNumber {
+				continue
+			}
+			if !stored.Row.ExpiresOn.Equal(lot.ExpiresOn) {
+				return domain.NewValidationError("lot %s expires on %s, not %s",
+					lot.LotNumber, stored.Row.ExpiresOn.Format(time.DateOnly), lot.ExpiresOn.Format(time.DateOnly))
+			}
+			updated := *stored
+			updated.Row.Quantity += lot.Quantity
+			updated.Row.Remaining += lot.Quantity
+			put(tx, t.Lots, id, &updated)
+			received = updated.Row
+			return nil
+		}
+
+		received = *lot
+		received.ProductID = item.ProductID
+		received.Location = item.Location
+		received.ExpiresOn = lot.ExpiresOn.UTC()
+		received.Remaining = lot.Quantity
+		put(tx, t.Lots, lot.ID, &memoryRow[domain.Lot]{Row: received, TenantID: item.TenantID})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &received, nil
+}
+
+// GetByID retrieves a lot
+func (r *MemoryLotRepository) GetByID(ctx context.Context, id string) (*domain.Lot, error) {
+	var lot *domain.Lot
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Lots[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("lot %w", domain.ErrNotFound)
+		}
+		copied := stored.Row
+		lot = &copied
+		return nil
+	})
+	return lot, err
+}
+
+// lots returns copies of the lots in the tenant scope of ctx matching
+// match, first expiring first
+func (t *memoryTables) lots(ctx context.Context, match func(*domain.Lot) bool) []*domain.Lot {
+	var lots []*domain.Lot
+	for _, stored := range t.Lots {
+		if inTenantScope(ctx, stored.TenantID) && match(&stored.Row) {
+			copied := stored.Row
+			lots = append(lots, &copied)
+		}
+	}
+	slices.SortFunc(lots, func(a, b *domain.Lot) int {
+		return cmp.Or(a.ExpiresOn.Compare(b.ExpiresOn), a.ReceivedAt.Compare(b.ReceivedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return lots
+}
+
+// ListByProduct retrieves the lots of a product matching a filter, first
+// expiring first
+func (r *MemoryLotRepository) ListByProduct(ctx context.Context, productID string, filter domain.LotFilter, limit, offset int) ([]*domain.Lot, error) {
+	var lots []*domain.Lot
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		lots = t.lots(ctx, func(lot *domain.Lot) bool {
+			return lot.ProductID == productID &&
+				(filter.Location == "" || lot.Location == filter.Location) &&
+				(!filter.Open || lot.Remaining > 0)
+		})
+		return nil
+	})
+	return page(lots, limit, offset), err
+}
+
+// LockOpen retrieves the lots of an inventory row with units in stock, first
+// expiring first. The unit of work of ctx holds the store, so concurrent
+// removals take each unit once.
+func (r *MemoryLotRepository) LockOpen(ctx context.Context, inventoryID string) ([]*domain.Lot, error) {
+	var lots []*domain.Lot
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		lots = t.lots(ctx, func(lot *domain.Lot) bool {
+			return lot.InventoryID == inventoryID && lot.Remaining > 0
+		})
+		return nil
+	})
+	return lots, err
+}
+
+// Consume takes units off the remaining ones of a lot
+func (r *MemoryLotRepository) Consume(ctx context.Context, id string, quantity int64) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Lots[id]
+		if !ok || stored.Row.Remaining < quantity {
+			return fmt.Errorf("lot %s has fewer than %d units left: %w", id, quantity, domain.ErrConflict)
+		}
+		updated := *stored
+		updated.Row.Remaining -= quantity
+		put(tx, t.Lots, id, &updated)
+		return nil
+	})
+}
+
+// ListExpiring retrieves the lots with units in stock that expire before the
+// day before, first expiring first, optionally only those at a location
+func (r *MemoryLotRepository) ListExpiring(ctx context.Context, before time.Time, location string, limit, offset int) ([]*domain.Lot, error) {
+	var lots []*domain.Lot
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		lots = t.lots(ctx, func(lot *domain.Lot) bool {
+			return lot.Remaining > 0 && lot.ExpiresOn.Before(before) &&
+				(location == "" || lot.Location == location)
+		})
+		return nil
+	})
+	slices.SortStableFunc(lots, func(a, b *domain.Lot) int {
+		return cmp.Or(a.ExpiresOn.Compare(b.ExpiresOn), cmp.Compare(a.ID, b.ID))
+	})
+	return page(lots, limit, offset), err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// copyOrder returns a copy of a stored order for the caller to own
+func copyOrder(stored *domain.Order) *domain.Order {
+	order := *stored
+	order.Lines = slices.Clone(stored.Lines)
+	if order.Lines == nil {
+		order.Lines = []domain.OrderLine{}
+	}
+	return &order
+}
+
+// MemoryOrderRepository implements OrderRepository with a MemoryStore
+type MemoryOrderRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryOrderRepository creates a new MemoryOrderRepository
+func NewMemoryOrderRepository(store *MemoryStore) *MemoryOrderRepository {
+	return &MemoryOrderRepository{store: store}
+}
+
+// Create inserts an order with its lines, for the tenant of ctx. It returns
+// ErrDuplicateOrder when the order ID is already in use.
+func (r *MemoryOrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	order.TenantID = tenantOwner(ctx)
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Orders[order.ID]; ok {
+			return domain.ErrDuplicateOrder
+		}
+		put(tx, t.Orders, order.ID, copyOrder(order))
+		return nil
+	})
+}
+
+// GetByID retrieves an order with its lines
+func (r *MemoryOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	var order *domain.Order
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Orders[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("order %w", domain.ErrNotFound)
+		}
+		order = copyOrder(stored)
+		return nil
+	})
+	return order, err
+}
+
+// List retrieves orders with their lines, newest first, optionally only those
+// in the given status
+func (r *MemoryOrderRepository) List(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Orders {
+			if (status == "" || stored.Status == status) && inTenantScope(ctx, stored.TenantID) {
+				orders = append(orders, copyOrder(stored))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(orders, func(a, b *domain.Order) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return page(orders, limit, offset), err
+}
+
+// UpdateStatus moves an order from one status to another. It reports false
+// when the order was no longer in the expected status.
+func (r *MemoryOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.OrderStatus) (bool, error) {
+	updated := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Orders[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) || stored.Status != from {
+			return nil
+		}
+		changed := copyOrder(stored)
+		changed.Status = to
+		changed.UpdatedAt = time.Now()
+		put(tx, t.Orders, id, changed)
+		updated = true
+		return nil
+	})
+	return updated, err
+}
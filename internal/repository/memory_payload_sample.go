@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryPayloadSampleRepository implements PayloadSampleRepository with a
+// MemoryStore
+type MemoryPayloadSampleRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryPayloadSampleRepository creates a new MemoryPayloadSampleRepository
+func NewMemoryPayloadSampleRepository(store *MemoryStore) *MemoryPayloadSampleRepository {
+	return &MemoryPayloadSampleRepository{store: store}
+}
+
+// Create stores a payload sample
+func (r *MemoryPayloadSampleRepository) Create(ctx context.Context, sample *domain.PayloadSample) error {
+	sample.ID = uuid.New().String()
+	sample.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored := *sample
+		put(tx, t.PayloadSamples, sample.ID, &stored)
+		return nil
+	})
+}
+
+// List retrieves a paginated list of payload samples, newest first. A
+// non-empty path restricts the result to requests containing it.
+func (r *MemoryPayloadSampleRepository) List(ctx context.Context, path string, limit, offset int) ([]*domain.PayloadSample, error) {
+	var samples []*domain.PayloadSample
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.PayloadSamples {
+			if strings.Contains(stored.Path, path) {
+				copied := *stored
+				samples = append(samples, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(samples, func(a, b *domain.PayloadSample) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return page(samples, limit, offset), err
+}
+
+// DeleteBefore removes samples created before the cutoff and returns how many were deleted
+func (r *MemoryPayloadSampleRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for id, sample := range t.PayloadSamples {
+			if sample.CreatedAt.Before(cutoff) {
+				remove(tx, t.PayloadSamples, id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
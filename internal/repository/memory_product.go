@@ -0,0 +1,506 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryProduct is a row of the products table
+type memoryProduct struct {
+	Product       domain.Product
+	EventSequence int64
+}
+
+// copyProduct returns a copy of the product of a row for the caller to own
+func (row *memoryProduct) copyProduct() *domain.Product {
+	product := row.Product
+	return &product
+}
+
+// productInScope returns the product with the given ID if ctx may read it
+func (t *memoryTables) productInScope(ctx context.Context, id string) (*memoryProduct, bool) {
+	row, ok := t.Products[id]
+	if !ok || !inTenantScope(ctx, row.Product.TenantID) {
+		return nil, false
+	}
+	return row, true
+}
+
+// skuTaken reports whether a product of the tenant other than id uses sku
+func (t *memoryTables) skuTaken(tenantID, sku, id string) bool {
+	for _, row := range t.Products {
+		if row.Product.TenantID == tenantID && row.Product.SKU == sku && row.Product.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// insertProduct stores a new product of the tenant of ctx
+func (t *memoryTables) insertProduct(ctx context.Context, tx *memoryTx, product *domain.Product, now time.Time) error {
+	product.ID = uuid.New().String()
+	product.TenantID = tenantOwner(ctx)
+	product.CreatedAt = now
+	product.UpdatedAt = now
+	if t.skuTaken(product.TenantID, product.SKU, product.ID) {
+		return &domain.DuplicateSKUError{SKU: product.SKU}
+	}
+
+	row := &memoryProduct{Product: *product}
+	row.Product.Translations = nil
+	put(tx, t.Products, product.ID, row)
+	return nil
+}
+
+// deleteProduct deletes a product with the rows that reference it, as the
+// foreign keys of the PostgreSQL schema cascade. Products are only deleted to
+// undo their creation, so only the tables filled in while creating one are
+// cascaded to.
+func (t *memoryTables) deleteProduct(tx *memoryTx, id string) {
+	remove(tx, t.Products, id)
+	for itemID, item := range t.Inventory {
+		if item.ProductID == id {
+			t.deleteItem(tx, itemID)
+		}
+	}
+	for transactionID, row := range t.Transactions {
+		if row.Transaction.ProductID == id {
+			remove(tx, t.Transactions, transactionID)
+		}
+	}
+	for key := range t.Translations {
+		if key.ProductID == id {
+			remove(tx, t.Translations, key)
+		}
+	}
+	for variantID, row := range t.Variants {
+		if variantID == id || row.Row.ParentID == id {
+			remove(tx, t.Variants, variantID)
+		}
+	}
+}
+
+// sortProductsNewestFirst orders products by creation, newest first
+func sortProductsNewestFirst(products []*domain.Product) {
+	slices.SortFunc(products, func(a, b *domain.Product) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+}
+
+// MemoryProductRepository implements ProductRepository with a MemoryStore
+type MemoryProductRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryProductRepository creates a new MemoryProductRepository
+func NewMemoryProductRepository(store *MemoryStore) *MemoryProductRepository {
+	return &MemoryProductRepository{store: store}
+}
+
+// Create inserts a new product
+func (r *MemoryProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	if err := product.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		return t.insertProduct(ctx, tx, product, time.Now())
+	})
+}
+
+// GetByID retrieves a product by ID, archived or not
+func (r *MemoryProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	var product *domain.Product
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		row, ok := t.productInScope(ctx, id)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		product = row.copyProduct()
+		return nil
+	})
+	return product, err
+}
+
+// GetBySKU retrieves a product by SKU, archived or not. Unscoped, the SKU may
+// be used by several tenants and the oldest product with it is returned.
+func (r *MemoryProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	var product *domain.Product
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Products {
+			if row.Product.SKU != sku || !inTenantScope(ctx, row.Product.TenantID) {
+				continue
+			}
+			if product == nil || cmp.Or(row.Product.CreatedAt.Compare(product.CreatedAt), cmp.Compare(row.Product.ID, product.ID)) < 0 {
+				product = row.copyProduct()
+			}
+		}
+		if product == nil {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		return nil
+	})
+	return product, err
+}
+
+// List retrieves a paginated list of the products matching filter, leaving
+// out archived products unless includeArchived is set
+func (r *MemoryProductRepository) List(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Products {
+			if (includeArchived || row.Product.DeletedAt == nil) && inTenantScope(ctx, row.Product.TenantID) &&
+				filter.Matches(row.Product.FilterValue) {
+				products = append(products, row.copyProduct())
+			}
+		}
+		return nil
+	})
+	sortProductsNewestFirst(products)
+	return page(products, limit, offset), err
+}
+
+// Update updates an existing product
+func (r *MemoryProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	if err := product.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.productInScope(ctx, product.ID)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		if t.skuTaken(row.Product.TenantID, product.SKU, product.ID) {
+			return &domain.DuplicateSKUError{SKU: product.SKU}
+		}
+
+		product.UpdatedAt = time.Now()
+		updated := *row
+		updated.Product.Name = product.Name
+		updated.Product.Description = product.Description
+		updated.Product.SKU = product.SKU
+		updated.Product.Category = product.Category
+		updated.Product.Price = product.Price
+		updated.Product.UpdatedAt = product.UpdatedAt
+		put(tx, t.Products, product.ID, &updated)
+		return nil
+	})
+}
+
+// Patch updates the fields set in the patch, leaving the others as they are,
+// and returns the product as patched
+func (r *MemoryProductRepository) Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	var product *domain.Product
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.productInScope(ctx, id)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+
+		updated := *row
+		if patch.Name != nil {
+			updated.Product.Name = *patch.Name
+		}
+		if patch.Description != nil {
+			updated.Product.Description = *patch.Description
+		}
+		if patch.Category != nil {
+			updated.Product.Category = *patch.Category
+		}
+		if patch.Price != nil {
+			updated.Product.Price = *patch.Price
+		}
+		updated.Product.UpdatedAt = time.Now()
+		put(tx, t.Products, id, &updated)
+		product = updated.copyProduct()
+		return nil
+	})
+	return product, err
+}
+
+// Delete permanently deletes a product together with its inventory and
+// transactions. Products are archived instead once they have been created;
+// Delete only undoes a creation that could not be completed.
+func (r *MemoryProductRepository) Delete(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.productInScope(ctx, id); !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		t.deleteProduct(tx, id)
+		return nil
+	})
+}
+
+// Archive soft-deletes a product. Archiving an archived product keeps its
+// original deletion time.
+func (r *MemoryProductRepository) Archive(ctx context.Context, id string) error {
+	return r.update(ctx, id, func(product *domain.Product, now time.Time) {
+		if product.DeletedAt == nil {
+			product.DeletedAt = &now
+		}
+	})
+}
+
+// Restore brings an archived product back
+func (r *MemoryProductRepository) Restore(ctx context.Context, id string) error {
+	return r.update(ctx, id, func(product *domain.Product, now time.Time) {
+		product.DeletedAt = nil
+	})
+}
+
+// SetReleaseAt schedules the drop of a product, or cancels it when releaseAt
+// is nil
+func (r *MemoryProductRepository) SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error {
+	if releaseAt != nil {
+		at := *releaseAt
+		releaseAt = &at
+	}
+	return r.update(ctx, id, func(product *domain.Product, now time.Time) {
+		product.ReleaseAt = releaseAt
+	})
+}
+
+// update changes a product in scope and sets its update time
+func (r *MemoryProductRepository) update(ctx context.Context, id string, change func(product *domain.Product, now time.Time)) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.productInScope(ctx, id)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		updated := *row
+		now := time.Now()
+		change(&updated.Product, now)
+		updated.Product.UpdatedAt = now
+		put(tx, t.Products, id, &updated)
+		return nil
+	})
+}
+
+// Count returns the number of products matching filter, leaving out
+// archived products unless includeArchived is set
+func (r *MemoryProductRepository) Count(ctx context.Context, includeArchived bool, filter *domain.Filter) (int64, error) {
+	var count int64
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Products {
+			if (includeArchived || row.Product.DeletedAt == nil) && inTenantScope(ctx, row.Product.TenantID) &&
+				filter.Matches(row.Product.FilterValue) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// ListScheduledReleases retrieves the products with a drop scheduled, the
+// next release first
+func (r *MemoryProductRepository) ListScheduledReleases(ctx context.Context) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Products {
+			if row.Product.ReleaseAt != nil && inTenantScope(ctx, row.Product.TenantID) {
+				products = append(products, row.copyProduct())
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(products, func(a, b *domain.Product) int {
+		return cmp.Or(a.ReleaseAt.Compare(*b.ReleaseAt), cmp.Compare(a.ID, b.ID))
+	})
+	return products, err
+}
+
+// ReleaseDue clears the drops whose release time has come by now and returns
+// the IDs of their products
+func (r *MemoryProductRepository) ReleaseDue(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for id, row := range t.Products {
+			if row.Product.ReleaseAt == nil || row.Product.ReleaseAt.After(now) || !inTenantScope(ctx, row.Product.TenantID) {
+				continue
+			}
+			updated := *row
+			updated.Product.ReleaseAt = nil
+			updated.Product.UpdatedAt = now
+			put(tx, t.Products, id, &updated)
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// MemoryProductImportRepository implements ProductImportRepository with a
+// MemoryStore
+type MemoryProductImportRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryProductImportRepository creates a new MemoryProductImportRepository
+func NewMemoryProductImportRepository(store *MemoryStore) *MemoryProductImportRepository {
+	return &MemoryProductImportRepository{store: store}
+}
+
+// ExistingSKUs returns which of the given SKUs are already used by a product
+// of the tenant the import is for
+func (r *MemoryProductImportRepository) ExistingSKUs(ctx context.Context, skus []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		tenantID := tenantOwner(ctx)
+		for _, row := range t.Products {
+			if row.Product.TenantID == tenantID && slices.Contains(skus, row.Product.SKU) {
+				existing[row.Product.SKU] = true
+			}
+		}
+		return nil
+	})
+	return existing, err
+}
+
+// ImportBatch inserts the products, inventory items and initial stock
+// transactions of a batch in one unit of work; either the whole batch is
+// written or none of it
+func (r *MemoryProductImportRepository) ImportBatch(ctx context.Context, batch []*domain.ProductImport) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		now := time.Now()
+		for _, entry := range batch {
+			product, item := entry.Product, entry.Inventory
+			if err := t.insertProduct(ctx, tx, product, now); err != nil {
+				return err
+			}
+
+			item.ID = uuid.New().String()
+			item.TenantID = product.TenantID
+			item.ProductID = product.ID
+			item.Version = 1
+			item.Condition = domain.ConditionNew
+			item.CreatedAt = now
+			item.UpdatedAt = now
+			stored := *item
+			put(tx, t.Inventory, item.ID, &stored)
+
+			if transaction := entry.Transaction; transaction != nil {
+				transaction.ID = uuid.New().String()
+				transaction.InventoryID = item.ID
+				transaction.ProductID = product.ID
+				transaction.CreatedAt = now
+				if err := t.insertTransaction(ctx, tx, transaction); err != nil {
+					return fmt.Errorf("failed to record initial stock for %s: %w", product.SKU, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// MemoryProductArchiveRepository implements ProductArchiveRepository with a
+// MemoryStore
+type MemoryProductArchiveRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryProductArchiveRepository creates a new MemoryProductArchiveRepository
+func NewMemoryProductArchiveRepository(store *MemoryStore) *MemoryProductArchiveRepository {
+	return &MemoryProductArchiveRepository{store: store}
+}
+
+// archiveCandidate returns the archive candidate a product is if the filter
+// selects it: active, in the category, without reservations, without stock
+// when ZeroStock is set and, with InactiveDays, created before the cutoff
+// without any stock movement since
+func (t *memoryTables) archiveCandidate(ctx context.Context, row *memoryProduct, filter domain.ProductArchiveFilter, cutoff time.Time) (*domain.ArchiveCandidate, bool) {
+	product := &row.Product
+	if product.DeletedAt != nil || !inTenantScope(ctx, product.TenantID) {
+		return nil, false
+	}
+	if filter.Category != "" && product.Category != filter.Category {
+		return nil, false
+	}
+
+	candidate := &domain.ArchiveCandidate{ProductID: product.ID, SKU: product.SKU, Name: product.Name, Category: product.Category}
+	stocked := false
+	for _, item := range t.Inventory {
+		if item.ProductID != product.ID {
+			continue
+		}
+		if item.Reserved > 0 {
+			return nil, false
+		}
+		stocked = stocked || item.Quantity != 0
+		candidate.Quantity += item.Quantity
+	}
+	if filter.ZeroStock && stocked {
+		return nil, false
+	}
+
+	for _, transaction := range t.Transactions {
+		if transaction.Transaction.ProductID != product.ID {
+			continue
+		}
+		if at := transaction.Transaction.CreatedAt; candidate.LastMovementAt == nil || at.After(*candidate.LastMovementAt) {
+			candidate.LastMovementAt = &at
+		}
+	}
+	if filter.InactiveDays > 0 {
+		if !product.CreatedAt.Before(cutoff) || (candidate.LastMovementAt != nil && !candidate.LastMovementAt.Before(cutoff)) {
+			return nil, false
+		}
+	}
+	return candidate, true
+}
+
+// Candidates lists at most limit active products the filter selects, by SKU,
+// with their stock on hand and latest movement
+func (r *MemoryProductArchiveRepository) Candidates(ctx context.Context, filter domain.ProductArchiveFilter, cutoff time.Time, limit int) ([]*domain.ArchiveCandidate, error) {
+	var candidates []*domain.ArchiveCandidate
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Products {
+			if candidate, ok := t.archiveCandidate(ctx, row, filter, cutoff); ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(candidates, func(a, b *domain.ArchiveCandidate) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+	return page(candidates, limit, 0), err
+}
+
+// ArchiveMatching archives those of the given products the filter still
+// selects and returns their IDs
+func (r *MemoryProductArchiveRepository) ArchiveMatching(ctx context.Context, ids []string, filter domain.ProductArchiveFilter, cutoff time.Time) ([]string, error) {
+	var archived []string
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		now := time.Now()
+		for _, id := range ids {
+			row, ok := t.Products[id]
+			if !ok {
+				continue
+			}
+			if _, ok := t.archiveCandidate(ctx, row, filter, cutoff); !ok {
+				continue
+			}
+			updated := *row
+			updated.Product.DeletedAt = &now
+			updated.Product.UpdatedAt = now
+			put(tx, t.Products, id, &updated)
+			archived = append(archived, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
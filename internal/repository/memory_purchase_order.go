@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// copyPurchaseOrder returns a copy of a stored purchase order with the units
+// shipped on each line by its shipping notices
+func (t *memoryTables) copyPurchaseOrder(stored *domain.PurchaseOrder) *domain.PurchaseOrder {
+	order := *stored
+	order.Lines = slices.Clone(stored.Lines)
+	if order.Lines == nil {
+		order.Lines = []domain.PurchaseOrderLine{}
+	}
+	for _, notice := range t.ShippingNotices {
+		if notice.PurchaseOrderID != order.ID {
+			continue
+		}
+		for _, line := range notice.Lines {
+			if line.Line >= 1 && line.Line <= len(order.Lines) {
+				order.Lines[line.Line-1].ShippedQuantity += line.Quantity
+			}
+		}
+	}
+	return &order
+}
+
+// MemoryPurchaseOrderRepository implements PurchaseOrderRepository with a
+// MemoryStore
+type MemoryPurchaseOrderRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryPurchaseOrderRepository creates a new MemoryPurchaseOrderRepository
+func NewMemoryPurchaseOrderRepository(store *MemoryStore) *MemoryPurchaseOrderRepository {
+	return &MemoryPurchaseOrderRepository{store: store}
+}
+
+// Create inserts a purchase order with its lines
+func (r *MemoryPurchaseOrderRepository) Create(ctx context.Context, order *domain.PurchaseOrder) error {
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	order.ID = uuid.New().String()
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	for i := range order.Lines {
+		order.Lines[i].Line = i + 1
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored := *order
+		stored.Lines = slices.Clone(order.Lines)
+		for i := range stored.Lines {
+			stored.Lines[i].ConfirmedQuantity = nil
+			stored.Lines[i].ShippedQuantity = 0
+		}
+		put(tx, t.PurchaseOrders, order.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves a purchase order with its lines
+func (r *MemoryPurchaseOrderRepository) GetByID(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	var order *domain.PurchaseOrder
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.PurchaseOrders[id]
+		if !ok {
+			return fmt.Errorf("purchase order %w", domain.ErrNotFound)
+		}
+		order = t.copyPurchaseOrder(stored)
+		return nil
+	})
+	return order, err
+}
+
+// List retrieves purchase orders with their lines, newest first, optionally
+// only those in the given status
+func (r *MemoryPurchaseOrderRepository) List(ctx context.Context, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	return r.list(ctx, limit, offset, func(order *domain.PurchaseOrder) bool {
+		return status == "" || order.Status == status
+	})
+}
+
+// ListForSupplier retrieves the purchase orders of one supplier with their
+// lines, newest first, optionally only those in the given status
+func (r *MemoryPurchaseOrderRepository) ListForSupplier(ctx context.Context, supplier string, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	return r.list(ctx, limit, offset, func(order *domain.PurchaseOrder) bool {
+		return order.Supplier == supplier && (status == "" || order.Status == status)
+	})
+}
+
+// list returns a page of the purchase orders that match, newest first
+func (r *MemoryPurchaseOrderRepository) list(ctx context.Context, limit, offset int, match func(order *domain.PurchaseOrder) bool) ([]*domain.PurchaseOrder, error) {
+	var orders []*domain.PurchaseOrder
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.PurchaseOrders {
+			if match(stored) {
+				orders = append(orders, t.copyPurchaseOrder(stored))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(orders, func(a, b *domain.PurchaseOrder) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return page(orders, limit, offset), err
+}
+
+// UpdateStatus moves a purchase order from one status to another, recording
+// the receipt time when it is received. It reports false when the order was
+// no longer in the expected status.
+func (r *MemoryPurchaseOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.PurchaseOrderStatus) (bool, error) {
+	updated := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.PurchaseOrders[id]
+		if !ok || stored.Status != from {
+			return nil
+		}
+		now := time.Now()
+		changed := *stored
+		changed.Status = to
+		changed.UpdatedAt = now
+		changed.ReceivedAt = nil
+		if to == domain.PurchaseOrderStatusReceived {
+			changed.ReceivedAt = &now
+		}
+		put(tx, t.PurchaseOrders, id, &changed)
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// Lock checks that the purchase order exists; the unit of work of ctx
+// already excludes every other
+func (r *MemoryPurchaseOrderRepository) Lock(ctx context.Context, id string) error {
+	return r.store.read(ctx, func(t *memoryTables) error {
+		if _, ok := t.PurchaseOrders[id]; !ok {
+			return fmt.Errorf("purchase order %w", domain.ErrNotFound)
+		}
+		return nil
+	})
+}
+
+// Confirm records the supplier's confirmation of a draft purchase order: the
+// promised delivery date and the confirmed quantity of every line by line
+// number. It reports false when the order was no longer a draft.
+func (r *MemoryPurchaseOrderRepository) Confirm(ctx context.Context, id string, promisedAt time.Time, quantities map[int]int64) (bool, error) {
+	confirmed := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.PurchaseOrders[id]
+		if !ok || stored.Status != domain.PurchaseOrderStatusDraft {
+			return nil
+		}
+		now := time.Now()
+		changed := *stored
+		changed.ConfirmedAt = &now
+		changed.PromisedAt = &promisedAt
+		changed.UpdatedAt = now
+		changed.Lines = slices.Clone(stored.Lines)
+		for line, quantity := range quantities {
+			if line >= 1 && line <= len(changed.Lines) {
+				changed.Lines[line-1].ConfirmedQuantity = &quantity
+			}
+		}
+		put(tx, t.PurchaseOrders, id, &changed)
+		confirmed = true
+		return nil
+	})
+	return confirmed, err
+}
+
+// CreateShippingNotice stores a shipping notice with its lines. A notice
+// whose reference the order already has is rejected with
+// domain.ErrDuplicateShippingNotice.
+func (r *MemoryPurchaseOrderRepository) CreateShippingNotice(ctx context.Context, notice *domain.AdvanceShippingNotice) error {
+	notice.ID = uuid.New().String()
+	notice.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.PurchaseOrders[notice.PurchaseOrderID]; !ok {
+			return fmt.Errorf("failed to create shipping notice: purchase order %s does not exist", notice.PurchaseOrderID)
+		}
+		for _, other := range t.ShippingNotices {
+			if other.PurchaseOrderID == notice.PurchaseOrderID && other.Reference == notice.Reference {
+				return domain.ErrDuplicateShippingNotice
+			}
+		}
+		stored := *notice
+		stored.Lines = slices.Clone(notice.Lines)
+		put(tx, t.ShippingNotices, notice.ID, &stored)
+		return nil
+	})
+}
+
+// ListShippingNotices retrieves the shipping notices of a purchase order
+// with their lines, oldest first
+func (r *MemoryPurchaseOrderRepository) ListShippingNotices(ctx context.Context, orderID string) ([]*domain.AdvanceShippingNotice, error) {
+	var notices []*domain.AdvanceShippingNotice
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.ShippingNotices {
+			if stored.PurchaseOrderID == orderID && len(stored.Lines) > 0 {
+				notice := *stored
+				notice.Lines = slices.Clone(stored.Lines)
+				slices.SortFunc(notice.Lines, func(a, b domain.QuantityLine) int { return cmp.Compare(a.Line, b.Line) })
+				notices = append(notices, &notice)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(notices, func(a, b *domain.AdvanceShippingNotice) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return notices, err
+}
+
+// ReorderCandidates returns the inventory rows of active products that have
+// a reorder level or sold since the given time, with their units sold since
+// then and the units on draft purchase orders
+func (r *MemoryPurchaseOrderRepository) ReorderCandidates(ctx context.Context, since time.Time) ([]*domain.ReorderCandidate, error) {
+	var candidates []*domain.ReorderCandidate
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		outbound := make(map[string]int64)
+		for _, row := range t.Transactions {
+			if !row.Archived && row.Transaction.Type == "OUT" && !row.Transaction.CreatedAt.Before(since) && inTenantScope(ctx, row.TenantID) {
+				outbound[row.Transaction.InventoryID] += row.Transaction.Quantity
+			}
+		}
+		onOrder := make(map[string]int64)
+		for _, order := range t.PurchaseOrders {
+			if order.Status != domain.PurchaseOrderStatusDraft {
+				continue
+			}
+			for _, line := range order.Lines {
+				onOrder[line.InventoryID] += line.Quantity
+			}
+		}
+
+		for _, item := range t.Inventory {
+			product, ok := t.productInScope(ctx, item.ProductID)
+			if !ok || product.Product.DeletedAt != nil || (item.ReorderLevel <= 0 && outbound[item.ID] <= 0) {
+				continue
+			}
+			candidates = append(candidates, &domain.ReorderCandidate{
+				ProductID: product.Product.ID, SKU: product.Product.SKU, Name: product.Product.Name,
+				InventoryID: item.ID, Location: item.Location,
+				Available: max(item.Quantity-item.Reserved, 0), ReorderLevel: item.ReorderLevel, SafetyStock: item.SafetyStock,
+				Outbound: outbound[item.ID], OnOrder: onOrder[item.ID],
+			})
+		}
+		return nil
+	})
+	slices.SortFunc(candidates, func(a, b *domain.ReorderCandidate) int {
+		return cmp.Or(cmp.Compare(a.SKU, b.SKU), cmp.Compare(a.Location, b.Location))
+	})
+	return candidates, err
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryReconciliationReport is a reconciliation report with its
+// discrepancies, each owned by the tenant of its inventory row
+type memoryReconciliationReport struct {
+	Report        domain.ReconciliationReport
+	Discrepancies []memoryRow[domain.ReconciliationDiscrepancy]
+}
+
+// MemoryReconciliationRepository implements ReconciliationRepository with a
+// MemoryStore
+type MemoryReconciliationRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryReconciliationRepository creates a new
+// MemoryReconciliationRepository
+func NewMemoryReconciliationRepository(store *MemoryStore) *MemoryReconciliationRepository {
+	return &MemoryReconciliationRepository{store: store}
+}
+
+// FindDiscrepancies replays the transaction log, live and archived, of every
+// inventory row of all tenants, or only of the given rows, and returns at
+// most limit rows whose stored quantity or reserved units differ from it,
+// with the number found
+func (r *MemoryReconciliationRepository) FindDiscrepancies(ctx context.Context, inventoryIDs []string, limit int) ([]*domain.ReconciliationDiscrepancy, int, error) {
+	var discrepancies []*domain.ReconciliationDiscrepancy
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		type expected struct{ quantity, reserved int64 }
+		replayed := map[string]*expected{}
+		for _, row := range t.Transactions {
+			e, ok := replayed[row.Transaction.InventoryID]
+			if !ok {
+				e = &expected{}
+				replayed[row.Transaction.InventoryID] = e
+			}
+			quantity, reserved := transactionDeltas(&row.Transaction)
+			e.quantity += quantity
+			e.reserved += reserved
+		}
+
+		for id, item := range t.Inventory {
+			if inventoryIDs != nil && !slices.Contains(inventoryIDs, id) {
+				continue
+			}
+			e := replayed[id]
+			if e == nil {
+				e = &expected{}
+			}
+			if item.Quantity == e.quantity && item.Reserved == e.reserved {
+				continue
+			}
+			discrepancies = append(discrepancies, &domain.ReconciliationDiscrepancy{
+				InventoryID:      id,
+				ProductID:        item.ProductID,
+				Location:         item.Location,
+				Condition:        string(item.Condition),
+				Quantity:         item.Quantity,
+				ExpectedQuantity: e.quantity,
+				Reserved:         item.Reserved,
+				ExpectedReserved: e.reserved,
+			})
+		}
+		return nil
+	})
+	slices.SortFunc(discrepancies, func(a, b *domain.ReconciliationDiscrepancy) int {
+		return cmp.Compare(a.InventoryID, b.InventoryID)
+	})
+	return page(discrepancies, limit, 0), len(discrepancies), err
+}
+
+// CreateReport records a reconciliation report with its discrepancies, each
+// owned by the tenant of its inventory row
+func (r *MemoryReconciliationRepository) CreateReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	report.ID = uuid.New().String()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored := &memoryReconciliationReport{Report: *report}
+		stored.Report.Discrepancies = nil
+		for _, d := range report.Discrepancies {
+			tenant := domain.DefaultTenantID
+			if item, ok := t.Inventory[d.InventoryID]; ok {
+				tenant = item.TenantID
+			}
+			stored.Discrepancies = append(stored.Discrepancies, memoryRow[domain.ReconciliationDiscrepancy]{Row: *d, TenantID: tenant})
+		}
+		put(tx, t.ReconciliationReports, report.ID, stored)
+		return nil
+	})
+}
+
+// LatestReport retrieves the latest reconciliation report with the
+// discrepancies of the tenant in scope. Within a tenant the discrepancy count
+// is that of its listed discrepancies. It returns ErrNotFound before the
+// first run.
+func (r *MemoryReconciliationRepository) LatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	var report *domain.ReconciliationReport
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		var latest *memoryReconciliationReport
+		for _, stored := range t.ReconciliationReports {
+			if latest == nil || stored.Report.CompletedAt.After(latest.Report.CompletedAt) {
+				latest = stored
+			}
+		}
+		if latest == nil {
+			return fmt.Errorf("reconciliation report %w", domain.ErrNotFound)
+		}
+
+		copied := latest.Report
+		copied.Discrepancies = []*domain.ReconciliationDiscrepancy{}
+		for _, d := range latest.Discrepancies {
+			if inTenantScope(ctx, d.TenantID) {
+				discrepancy := d.Row
+				copied.Discrepancies = append(copied.Discrepancies, &discrepancy)
+			}
+		}
+		report = &copied
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(report.Discrepancies, func(a, b *domain.ReconciliationDiscrepancy) int {
+		return cmp.Compare(a.InventoryID, b.InventoryID)
+	})
+	if tenantScope(ctx) != "" {
+		report.DiscrepancyCount = len(report.Discrepancies)
+	}
+	return report, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryRedactionRepository implements RedactionRepository with a MemoryStore
+type MemoryRedactionRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryRedactionRepository creates a new MemoryRedactionRepository
+func NewMemoryRedactionRepository(store *MemoryStore) *MemoryRedactionRepository {
+	return &MemoryRedactionRepository{store: store}
+}
+
+// Redact replaces every occurrence of identifier in the references and notes
+// of live and archived transactions, in their annotations and in sampled
+// payloads with the redaction token and records the redaction, in one unit
+// of work.
+// Quantities, types and timestamps are left untouched. Only the transactions of
+// the tenant of ctx are redacted when it is scoped; payload samples carry no
+// tenant, so every sample naming the identifier is.
+func (r *MemoryRedactionRepository) Redact(ctx context.Context, identifier string, redaction *domain.Redaction) error {
+	if err := redaction.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		var affected int64
+		for id, stored := range t.Transactions {
+			transaction := stored.Transaction
+			if !inTenantScope(ctx, stored.TenantID) ||
+				(!strings.Contains(transaction.Reference, identifier) && !strings.Contains(transaction.Notes, identifier)) {
+				continue
+			}
+			updated := *stored
+			updated.Transaction.Reference = strings.ReplaceAll(transaction.Reference, identifier, redaction.Token)
+			updated.Transaction.Notes = strings.ReplaceAll(transaction.Notes, identifier, redaction.Token)
+			put(tx, t.Transactions, id, &updated)
+			affected++
+		}
+
+		// Annotations are immutable except for erasure
+		for id, stored := range t.Annotations {
+			if inTenantScope(ctx, stored.TenantID) && strings.Contains(stored.Row.Note, identifier) {
+				updated := *stored
+				updated.Row.Note = strings.ReplaceAll(stored.Row.Note, identifier, redaction.Token)
+				put(tx, t.Annotations, id, &updated)
+			}
+		}
+
+		for id, sample := range t.PayloadSamples {
+			fields := []string{sample.Path, sample.Query, sample.RequestBody, sample.ResponseBody}
+			if !slices.ContainsFunc(fields, func(field string) bool { return strings.Contains(field, identifier) }) {
+				continue
+			}
+			updated := *sample
+			updated.Path = strings.ReplaceAll(sample.Path, identifier, redaction.Token)
+			updated.Query = strings.ReplaceAll(sample.Query, identifier, redaction.Token)
+			updated.RequestBody = strings.ReplaceAll(sample.RequestBody, identifier, redaction.Token)
+			updated.ResponseBody = strings.ReplaceAll(sample.ResponseBody, identifier, redaction.Token)
+			put(tx, t.PayloadSamples, id, &updated)
+		}
+
+		redaction.ID = uuid.New().String()
+		redaction.TransactionsAffected = affected
+		redaction.CreatedAt = time.Now()
+		put(tx, t.Redactions, redaction.ID, &memoryRow[domain.Redaction]{Row: *redaction, TenantID: tenantOwner(ctx)})
+		return nil
+	})
+}
+
+// List retrieves a paginated list of the redactions of the tenant of ctx,
+// newest first
+func (r *MemoryRedactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Redaction, error) {
+	var redactions []*domain.Redaction
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Redactions {
+			if !inTenantScope(ctx, stored.TenantID) {
+				continue
+			}
+			copied := stored.Row
+			redactions = append(redactions, &copied)
+		}
+		return nil
+	})
+	slices.SortFunc(redactions, func(a, b *domain.Redaction) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(redactions, limit, offset), err
+}
@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// memorySnapshotKey is the primary key of the stock snapshot of a product on
+// a day, formatted as time.DateOnly
+type memorySnapshotKey struct {
+	ProductID string
+	Day       string
+}
+
+// memoryStockSnapshot is a stock snapshot with the time it was captured
+type memoryStockSnapshot struct {
+	Snapshot   domain.StockSnapshot
+	CapturedAt time.Time
+}
+
+// MemoryReportRepository implements ReportRepository with a MemoryStore
+type MemoryReportRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryReportRepository creates a new MemoryReportRepository
+func NewMemoryReportRepository(store *MemoryStore) *MemoryReportRepository {
+	return &MemoryReportRepository{store: store}
+}
+
+// stock sums the quantity and reserved units of the inventory of a product
+// over all locations
+func (t *memoryTables) stock(productID string) (quantity, reserved int64) {
+	for _, item := range t.Inventory {
+		if item.ProductID == productID {
+			quantity += item.Quantity
+			reserved += item.Reserved
+		}
+	}
+	return quantity, reserved
+}
+
+// transactionDeltas are the changes a transaction made to the quantity and
+// reserved units of its inventory row. Adjustments record their direction in
+// the quantities before and after.
+func transactionDeltas(transaction *domain.Transaction) (quantity, reserved int64) {
+	switch transaction.Type {
+	case "IN", "RETURN", "TRANSFER_IN":
+		return transaction.Quantity, 0
+	case "OUT", "TRANSFER_OUT", "EXPIRED":
+		return -transaction.Quantity, 0
+	case "ADJUSTMENT":
+		if transaction.QuantityBefore != nil && transaction.QuantityAfter != nil {
+			return *transaction.QuantityAfter - *transaction.QuantityBefore, 0
+		}
+	case "RESERVE":
+		return 0, transaction.Quantity
+	case "UNRESERVE":
+		return 0, -transaction.Quantity
+	}
+	return 0, 0
+}
+
+// StockSummary aggregates on-hand, reserved and value per group. groupBy must
+// only contain supported dimensions; an empty groupBy returns one overall row.
+func (r *MemoryReportRepository) StockSummary(ctx context.Context, groupBy []string) ([]*domain.StockSummary, error) {
+	for _, dimension := range groupBy {
+		if _, ok := stockSummaryDimensions[dimension]; !ok {
+			return nil, fmt.Errorf("unsupported group_by dimension: %s", dimension)
+		}
+	}
+
+	type group struct {
+		summary  *domain.StockSummary
+		values   []string
+		products map[string]bool
+	}
+	groups := map[string]*group{}
+	if len(groupBy) == 0 {
+		groups[""] = &group{summary: &domain.StockSummary{Group: map[string]string{}}, products: map[string]bool{}}
+	}
+
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, item := range t.Inventory {
+			product, ok := t.Products[item.ProductID]
+			if !ok || !inTenantScope(ctx, product.Product.TenantID) {
+				continue
+			}
+			values := make([]string, len(groupBy))
+			for i, dimension := range groupBy {
+				if dimension == "category" {
+					values[i] = product.Product.Category
+				} else {
+					values[i] = item.Location
+				}
+			}
+			key := strings.Join(values, "\x00")
+			g, ok := groups[key]
+			if !ok {
+				g = &group{summary: &domain.StockSummary{Group: make(map[string]string, len(groupBy))}, values: values, products: map[string]bool{}}
+				for i, dimension := range groupBy {
+					g.summary.Group[dimension] = values[i]
+				}
+				groups[key] = g
+			}
+			g.products[item.ProductID] = true
+			g.summary.OnHand += item.Quantity
+			g.summary.Reserved += item.Reserved
+			g.summary.Value += float64(item.Quantity) * product.Product.Price
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		g.summary.ProductCount = int64(len(g.products))
+		g.summary.Available = g.summary.OnHand - g.summary.Reserved
+		ordered = append(ordered, g)
+	}
+	slices.SortFunc(ordered, func(a, b *group) int {
+		return slices.Compare(a.values, b.values)
+	})
+
+	summaries := make([]*domain.StockSummary, 0, len(ordered))
+	for _, g := range ordered {
+		summaries = append(summaries, g.summary)
+	}
+	return summaries, nil
+}
+
+// truncatePeriod returns the start of the day, week or month of at in loc.
+// Weeks start on Monday like PostgreSQL's date_trunc.
+func truncatePeriod(at time.Time, granularity string, loc *time.Location) time.Time {
+	year, month, day := at.In(loc).Date()
+	switch granularity {
+	case "week":
+		start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		return start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	case "month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// MovementSummary totals quantities and counts per transaction type and
+// period. Buckets follow the query location.
+func (r *MemoryReportRepository) MovementSummary(ctx context.Context, q MovementQuery) ([]*domain.MovementSummary, error) {
+	if !movementGranularities[q.Granularity] {
+		return nil, fmt.Errorf("unsupported granularity: %s", q.Granularity)
+	}
+
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var summaries []*domain.MovementSummary
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		transactions := t.transactions(ctx, true, func(transaction *domain.Transaction) bool {
+			return !transaction.CreatedAt.Before(q.From) && transaction.CreatedAt.Before(q.To) &&
+				(q.ProductID == "" || transaction.ProductID == q.ProductID)
+		})
+
+		byPeriod := map[[2]string]*domain.MovementSummary{}
+		for _, transaction := range transactions {
+			productID := ""
+			if q.GroupByProduct {
+				productID = transaction.ProductID
+			}
+			key := [2]string{truncatePeriod(transaction.CreatedAt, q.Granularity, loc).Format("2006-01-02"), productID}
+			summary, ok := byPeriod[key]
+			if !ok {
+				summary = &domain.MovementSummary{Period: key[0], ProductID: productID, Movements: make(map[string]domain.MovementTotals)}
+				byPeriod[key] = summary
+				summaries = append(summaries, summary)
+			}
+			totals := summary.Movements[transaction.Type]
+			totals.Quantity += transaction.Quantity
+			totals.Count++
+			summary.Movements[transaction.Type] = totals
+		}
+		return nil
+	})
+	slices.SortFunc(summaries, func(a, b *domain.MovementSummary) int {
+		return cmp.Or(cmp.Compare(a.Period, b.Period), cmp.Compare(a.ProductID, b.ProductID))
+	})
+	return summaries, err
+}
+
+// InventoryKPIs computes the product count, the total reserved units and the
+// number of products whose available quantity, summed over all locations, is
+// below lowStockThreshold. Products without inventory count as low on stock;
+// archived products are not counted.
+func (r *MemoryReportRepository) InventoryKPIs(ctx context.Context, lowStockThreshold int64) (*domain.InventoryKPIs, error) {
+	kpis := &domain.InventoryKPIs{}
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for id, product := range t.Products {
+			if product.Product.DeletedAt != nil || !inTenantScope(ctx, product.Product.TenantID) {
+				continue
+			}
+			quantity, reserved := t.stock(id)
+			kpis.Products++
+			kpis.ReservedUnits += reserved
+			if quantity-reserved < lowStockThreshold {
+				kpis.LowStockProducts++
+			}
+		}
+		return nil
+	})
+	return kpis, err
+}
+
+// DashboardStats computes the dashboard figures: the stock totals from the
+// inventory of every product that is not archived, and the number and units
+// of the transactions in the 24 hours and 7 days before now
+func (r *MemoryReportRepository) DashboardStats(ctx context.Context, lowStockThreshold int64, now time.Time) (*domain.DashboardStats, error) {
+	stats := &domain.DashboardStats{LowStockThreshold: lowStockThreshold, GeneratedAt: now}
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		quantities, reservations := map[string]int64{}, map[string]int64{}
+		for _, item := range t.Inventory {
+			quantities[item.ProductID] += item.Quantity
+			reservations[item.ProductID] += item.Reserved
+		}
+		for id, product := range t.Products {
+			if product.Product.DeletedAt != nil || !inTenantScope(ctx, product.Product.TenantID) {
+				continue
+			}
+			stats.TotalProducts++
+			stats.TotalUnits += quantities[id]
+			stats.TotalReserved += reservations[id]
+			switch available := quantities[id] - reservations[id]; {
+			case available <= 0:
+				stats.OutOfStock++
+			case available < lowStockThreshold:
+				stats.LowStock++
+			}
+		}
+
+		day, week := now.Add(-24*time.Hour), now.AddDate(0, 0, -7)
+		for _, transaction := range t.transactions(ctx, true, func(transaction *domain.Transaction) bool {
+			return !transaction.CreatedAt.Before(week)
+		}) {
+			stats.Transactions7d.Count++
+			stats.Transactions7d.Quantity += transaction.Quantity
+			if !transaction.CreatedAt.Before(day) {
+				stats.Transactions24h.Count++
+				stats.Transactions24h.Quantity += transaction.Quantity
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// StockVelocities returns, for every product that sold since the given time
+// and is not archived, its available stock over all locations and the units
+// sold since then
+func (r *MemoryReportRepository) StockVelocities(ctx context.Context, since time.Time) ([]*domain.StockVelocity, error) {
+	var velocities []*domain.StockVelocity
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		outbound := map[string]int64{}
+		for _, transaction := range t.transactions(ctx, true, func(transaction *domain.Transaction) bool {
+			return transaction.Type == "OUT" && !transaction.CreatedAt.Before(since)
+		}) {
+			outbound[transaction.ProductID] += transaction.Quantity
+		}
+
+		for id, units := range outbound {
+			product, ok := t.Products[id]
+			if !ok || product.Product.DeletedAt != nil {
+				continue
+			}
+			quantity, reserved := t.stock(id)
+			velocities = append(velocities, &domain.StockVelocity{
+				ProductID: id,
+				SKU:       product.Product.SKU,
+				Name:      product.Product.Name,
+				Available: quantity - reserved,
+				Outbound:  units,
+			})
+		}
+		return nil
+	})
+	slices.SortFunc(velocities, func(a, b *domain.StockVelocity) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+	return velocities, err
+}
+
+// OpenReservationHolds sums the pending reservations per reference, oldest
+// reservation first
+func (r *MemoryReportRepository) OpenReservationHolds(ctx context.Context) ([]*domain.ReservationHold, error) {
+	var holds []*domain.ReservationHold
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		byReference := map[string]*domain.ReservationHold{}
+		for _, reservation := range t.reservations(func(reservation *domain.Reservation) bool {
+			return reservation.Status == domain.ReservationStatusPending && inTenantScope(ctx, reservation.TenantID)
+		}) {
+			hold, ok := byReference[reservation.Reference]
+			if !ok {
+				hold = &domain.ReservationHold{Reference: reservation.Reference, OldestAt: reservation.CreatedAt}
+				byReference[reservation.Reference] = hold
+				holds = append(holds, hold)
+			}
+			hold.Reservations++
+			hold.Quantity += reservation.Quantity
+		}
+		return nil
+	})
+	slices.SortStableFunc(holds, func(a, b *domain.ReservationHold) int {
+		return cmp.Or(a.OldestAt.Compare(b.OldestAt), cmp.Compare(a.Reference, b.Reference))
+	})
+	return holds, err
+}
+
+// CaptureStockSnapshots records the current on-hand and available stock of
+// every active product as its snapshot for day, replacing an earlier capture
+// of the same day, and returns the number of products captured
+func (r *MemoryReportRepository) CaptureStockSnapshots(ctx context.Context, day time.Time) (int64, error) {
+	year, month, date := day.Date()
+	day = time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	var captured int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for id, product := range t.Products {
+			if product.Product.DeletedAt != nil {
+				continue
+			}
+			quantity, reserved := t.stock(id)
+			put(tx, t.StockSnapshots, memorySnapshotKey{ProductID: id, Day: day.Format(time.DateOnly)}, &memoryStockSnapshot{
+				Snapshot:   domain.StockSnapshot{ProductID: id, Day: day, OnHand: quantity, Available: quantity - reserved},
+				CapturedAt: now,
+			})
+			captured++
+		}
+		return nil
+	})
+	return captured, err
+}
+
+// StockSnapshots retrieves the snapshots of a product from day from to day to,
+// oldest first, preceded by the latest snapshot before from if there is one
+func (r *MemoryReportRepository) StockSnapshots(ctx context.Context, productID string, from, to time.Time) ([]*domain.StockSnapshot, error) {
+	first, last := from.Format(time.DateOnly), to.Format(time.DateOnly)
+
+	var snapshots []*domain.StockSnapshot
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		if _, ok := t.productInScope(ctx, productID); !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+
+		start := first
+		for key := range t.StockSnapshots {
+			if key.ProductID == productID && key.Day <= first && (start == first || key.Day > start) {
+				start = key.Day
+			}
+		}
+		for key, stored := range t.StockSnapshots {
+			if key.ProductID == productID && key.Day >= start && key.Day <= last {
+				snapshot := stored.Snapshot
+				snapshots = append(snapshots, &snapshot)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(snapshots, func(a, b *domain.StockSnapshot) int {
+		return a.Day.Compare(b.Day)
+	})
+	return snapshots, err
+}
+
+// StockAsOf reconstructs the stock of every product that existed at asOf,
+// ordered by SKU. A product's stock starts from its latest snapshot captured
+// by then, onto which the transactions recorded after the capture and before
+// asOf are replayed. Products without such a snapshot start from their
+// current stock instead, from which the transactions since asOf are rewound.
+func (r *MemoryReportRepository) StockAsOf(ctx context.Context, asOf time.Time) ([]*domain.HistoricStock, error) {
+	var stock []*domain.HistoricStock
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		baselines := map[string]*memoryStockSnapshot{}
+		for key, snapshot := range t.StockSnapshots {
+			if snapshot.CapturedAt.After(asOf) {
+				continue
+			}
+			if latest, ok := baselines[key.ProductID]; !ok || snapshot.CapturedAt.After(latest.CapturedAt) {
+				baselines[key.ProductID] = snapshot
+			}
+		}
+
+		for id, row := range t.Products {
+			product := row.Product
+			if !product.CreatedAt.Before(asOf) || (product.DeletedAt != nil && product.DeletedAt.Before(asOf)) ||
+				!inTenantScope(ctx, product.TenantID) {
+				continue
+			}
+
+			s := &domain.HistoricStock{ProductID: id, SKU: product.SKU, Name: product.Name}
+			if baseline, ok := baselines[id]; ok {
+				capturedAt := baseline.CapturedAt
+				s.Quantity = baseline.Snapshot.OnHand
+				s.Reserved = baseline.Snapshot.OnHand - baseline.Snapshot.Available
+				s.SnapshotAt = &capturedAt
+				for _, transaction := range t.Transactions {
+					if transaction.Transaction.ProductID == id && transaction.Transaction.CreatedAt.After(capturedAt) &&
+						transaction.Transaction.CreatedAt.Before(asOf) {
+						quantity, reserved := transactionDeltas(&transaction.Transaction)
+						s.Quantity += quantity
+						s.Reserved += reserved
+					}
+				}
+			} else {
+				s.Quantity, s.Reserved = t.stock(id)
+				for _, transaction := range t.Transactions {
+					if transaction.Transaction.ProductID == id && !transaction.Transaction.CreatedAt.Before(asOf) {
+						quantity, reserved := transactionDeltas(&transaction.Transaction)
+						s.Quantity -= quantity
+						s.Reserved -= reserved
+					}
+				}
+			}
+			s.Available = s.Quantity - s.Reserved
+			stock = append(stock, s)
+		}
+		return nil
+	})
+	slices.SortFunc(stock, func(a, b *domain.HistoricStock) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+	return stock, err
+}
+
+// ValuationInputs retrieves what the stock of every active product, or only
+// the given one, is valued from, ordered by SKU: its quantity on hand over
+// all locations, the totals of its costed receipts, live and archived, and
+// its latest costed receipts as far as they cover the quantity on hand
+func (r *MemoryReportRepository) ValuationInputs(ctx context.Context, productID string) ([]*domain.ValuationInput, error) {
+	var inputs []*domain.ValuationInput
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		receipts := map[string][]*domain.Transaction{}
+		for _, row := range t.Transactions {
+			transaction := row.Transaction
+			if (transaction.Type == "IN" || transaction.Type == "RETURN") && transaction.UnitCost != nil {
+				receipts[transaction.ProductID] = append(receipts[transaction.ProductID], &transaction)
+			}
+		}
+
+		for id, row := range t.Products {
+			product := row.Product
+			if product.DeletedAt != nil || (productID != "" && id != productID) || !inTenantScope(ctx, product.TenantID) {
+				continue
+			}
+
+			in := &domain.ValuationInput{ProductID: id, SKU: product.SKU, Name: product.Name}
+			in.Quantity, _ = t.stock(id)
+
+			// Each receipt's newer units are those received after it; a
+			// receipt is part of the stock on hand while they fall short of it
+			received := receipts[id]
+			slices.SortFunc(received, compareTransactionsNewestFirst)
+			var newer int64
+			for _, receipt := range received {
+				in.ReceivedUnits += receipt.Quantity
+				in.ReceivedCost += float64(receipt.Quantity) * *receipt.UnitCost
+				if newer < in.Quantity {
+					in.Layers = append(in.Layers, domain.CostLayer{Quantity: receipt.Quantity, UnitCost: *receipt.UnitCost})
+				}
+				newer += receipt.Quantity
+			}
+			inputs = append(inputs, in)
+		}
+		return nil
+	})
+	slices.SortFunc(inputs, func(a, b *domain.ValuationInput) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+	return inputs, err
+}
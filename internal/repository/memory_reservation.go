@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryReservationRepository implements ReservationRepository with a
+// MemoryStore
+type MemoryReservationRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryReservationRepository creates a new MemoryReservationRepository
+func NewMemoryReservationRepository(store *MemoryStore) *MemoryReservationRepository {
+	return &MemoryReservationRepository{store: store}
+}
+
+// Create inserts a new reservation, which belongs to the tenant of its
+// inventory item
+func (r *MemoryReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	if err := reservation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	reservation.ID = uuid.New().String()
+	now := time.Now()
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		item, ok := t.itemInScope(ctx, reservation.InventoryID)
+		if !ok {
+			return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+		}
+		reservation.TenantID = item.TenantID
+		stored := *reservation
+		put(tx, t.Reservations, reservation.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves a reservation by ID
+func (r *MemoryReservationRepository) GetByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	var reservation *domain.Reservation
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Reservations[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("reservation %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		reservation = &copied
+		return nil
+	})
+	return reservation, err
+}
+
+// UpdateStatus moves a reservation from one status to another. It reports
+// false when the reservation was no longer in the expected status, which lets
+// concurrent confirm, release and expiry race safely.
+func (r *MemoryReservationRepository) UpdateStatus(ctx context.Context, id string, from, to domain.ReservationStatus) (bool, error) {
+	updated := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Reservations[id]
+		if !ok || !inTenantScope(ctx, stored.TenantID) || stored.Status != from {
+			return nil
+		}
+		changed := *stored
+		changed.Status = to
+		changed.UpdatedAt = time.Now()
+		put(tx, t.Reservations, id, &changed)
+		updated = true
+		return nil
+	})
+	return updated, err
+}
+
+// ListExpired retrieves pending reservations whose expiry time has passed,
+// oldest expiry first
+func (r *MemoryReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error) {
+	reservations, err := r.list(ctx, func(reservation *domain.Reservation) bool {
+		return reservation.Status == domain.ReservationStatusPending && !reservation.ExpiresAt.After(now)
+	})
+	slices.SortStableFunc(reservations, func(a, b *domain.Reservation) int { return a.ExpiresAt.Compare(b.ExpiresAt) })
+	return page(reservations, limit, 0), err
+}
+
+// ListByProductID retrieves a paginated list of reservations of a product, newest first
+func (r *MemoryReservationRepository) ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Reservation, error) {
+	reservations, err := r.list(ctx, func(reservation *domain.Reservation) bool { return reservation.ProductID == productID })
+	slices.Reverse(reservations)
+	return page(reservations, limit, offset), err
+}
+
+// ListByParentID retrieves the children of a split reservation, oldest first
+func (r *MemoryReservationRepository) ListByParentID(ctx context.Context, parentID string) ([]*domain.Reservation, error) {
+	return r.list(ctx, func(reservation *domain.Reservation) bool { return reservation.ParentID == parentID })
+}
+
+// PendingByChannel sums the quantity of the pending reservations a sales
+// channel holds of a product
+func (r *MemoryReservationRepository) PendingByChannel(ctx context.Context, channelID, productID string) (int64, error) {
+	reservations, err := r.list(ctx, func(reservation *domain.Reservation) bool {
+		return reservation.ChannelID == channelID && reservation.ProductID == productID &&
+			reservation.Status == domain.ReservationStatusPending
+	})
+	return sumReserved(reservations), err
+}
+
+// ConfirmedByChannel sums the quantity of the reservations of a product a
+// sales channel confirmed since the given time
+func (r *MemoryReservationRepository) ConfirmedByChannel(ctx context.Context, channelID, productID string, since time.Time) (int64, error) {
+	reservations, err := r.list(ctx, func(reservation *domain.Reservation) bool {
+		return reservation.ChannelID == channelID && reservation.ProductID == productID &&
+			reservation.Status == domain.ReservationStatusConfirmed && !reservation.UpdatedAt.Before(since)
+	})
+	return sumReserved(reservations), err
+}
+
+// list returns copies of the reservations in scope that match, oldest first
+func (r *MemoryReservationRepository) list(ctx context.Context, match func(reservation *domain.Reservation) bool) ([]*domain.Reservation, error) {
+	var reservations []*domain.Reservation
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		reservations = t.reservations(func(reservation *domain.Reservation) bool {
+			return inTenantScope(ctx, reservation.TenantID) && match(reservation)
+		})
+		return nil
+	})
+	return reservations, err
+}
+
+// reservations returns copies of the reservations that match, oldest first
+func (t *memoryTables) reservations(match func(reservation *domain.Reservation) bool) []*domain.Reservation {
+	var reservations []*domain.Reservation
+	for _, stored := range t.Reservations {
+		if match(stored) {
+			copied := *stored
+			reservations = append(reservations, &copied)
+		}
+	}
+	slices.SortFunc(reservations, func(a, b *domain.Reservation) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return reservations
+}
+
+// sumReserved sums the quantity of reservations
+func sumReserved(reservations []*domain.Reservation) int64 {
+	var sum int64
+	for _, reservation := range reservations {
+		sum += reservation.Quantity
+	}
+	return sum
+}
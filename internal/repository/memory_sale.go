@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// memoryEventKey is the primary key of the tables of events delivered by
+// other systems, whose IDs are unique per tenant
+type memoryEventKey struct {
+	TenantID string
+	EventID  string
+}
+
+// MemorySaleRepository implements SaleRepository with a MemoryStore
+type MemorySaleRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySaleRepository creates a new MemorySaleRepository
+func NewMemorySaleRepository(store *MemoryStore) *MemorySaleRepository {
+	return &MemorySaleRepository{store: store}
+}
+
+// Create records a sale, its event time in UTC like the filters of List. It
+// returns ErrDuplicateSale when the tenant already recorded a sale with the
+// event ID.
+func (r *MemorySaleRepository) Create(ctx context.Context, sale *domain.Sale) error {
+	sale.OccurredAt = sale.OccurredAt.UTC()
+	sale.ReceivedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		key := memoryEventKey{TenantID: tenantOwner(ctx), EventID: sale.EventID}
+		if _, ok := t.Sales[key]; ok {
+			return domain.ErrDuplicateSale
+		}
+		if _, ok := t.Products[sale.ProductID]; !ok {
+			return fmt.Errorf("failed to create sale: product %s does not exist", sale.ProductID)
+		}
+		stored := *sale
+		stored.SKU = ""
+		put(tx, t.Sales, key, &stored)
+		return nil
+	})
+}
+
+// GetByEventID retrieves the sale recorded for an event ID
+func (r *MemorySaleRepository) GetByEventID(ctx context.Context, eventID string) (*domain.Sale, error) {
+	var sale *domain.Sale
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Sales[memoryEventKey{TenantID: tenantOwner(ctx), EventID: eventID}]
+		if !ok {
+			return fmt.Errorf("sale %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		sale = &copied
+		return nil
+	})
+	return sale, err
+}
+
+// List retrieves sales in the order they happened, ties broken by event ID
+func (r *MemorySaleRepository) List(ctx context.Context, filter domain.SaleFilter, limit, offset int) ([]*domain.Sale, error) {
+	var sales []*domain.Sale
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, stored := range t.Sales {
+			if !inTenantScope(ctx, key.TenantID) || (filter.Location != "" && stored.Location != filter.Location) ||
+				(!filter.From.IsZero() && stored.OccurredAt.Before(filter.From)) ||
+				(!filter.To.IsZero() && !stored.OccurredAt.Before(filter.To)) {
+				continue
+			}
+			copied := *stored
+			sales = append(sales, &copied)
+		}
+		return nil
+	})
+	slices.SortFunc(sales, func(a, b *domain.Sale) int {
+		return cmp.Or(a.OccurredAt.Compare(b.OccurredAt), cmp.Compare(a.EventID, b.EventID))
+	})
+	return page(sales, limit, offset), err
+}
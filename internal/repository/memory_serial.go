@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemorySerialUnitRepository implements SerialUnitRepository with a MemoryStore
+type MemorySerialUnitRepository struct {
+	store *MemoryStore
+}
+
+// NewMemorySerialUnitRepository creates a new MemorySerialUnitRepository
+func NewMemorySerialUnitRepository(store *MemoryStore) *MemorySerialUnitRepository {
+	return &MemorySerialUnitRepository{store: store}
+}
+
+// serialUnit returns the stored unit of a product with a serial number, if
+// any, whatever its tenant
+func (t *memoryTables) serialUnit(productID, serialNumber string) *domain.SerialUnit {
+	for _, stored := range t.SerialUnits {
+		if stored.ProductID == productID && stored.SerialNumber == serialNumber {
+			return stored
+		}
+	}
+	return nil
+}
+
+// Create inserts a new serial unit, which belongs to the tenant of its product
+func (r *MemorySerialUnitRepository) Create(ctx context.Context, unit *domain.SerialUnit) error {
+	if err := unit.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	unit.ID = uuid.New().String()
+	now := time.Now()
+	unit.CreatedAt = now
+	unit.UpdatedAt = now
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		product, ok := t.productInScope(ctx, unit.ProductID)
+		if !ok {
+			return fmt.Errorf("product %w", domain.ErrNotFound)
+		}
+		unit.TenantID = product.Product.TenantID
+		if t.serialUnit(unit.ProductID, unit.SerialNumber) != nil {
+			return fmt.Errorf("failed to create serial unit: serial number %s already exists", unit.SerialNumber)
+		}
+		stored := *unit
+		put(tx, t.SerialUnits, unit.ID, &stored)
+		return nil
+	})
+}
+
+// GetBySerial retrieves a serial unit by product and serial number
+func (r *MemorySerialUnitRepository) GetBySerial(ctx context.Context, productID, serialNumber string) (*domain.SerialUnit, error) {
+	var unit *domain.SerialUnit
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored := t.serialUnit(productID, serialNumber)
+		if stored == nil || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("serial unit %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		unit = &copied
+		return nil
+	})
+	return unit, err
+}
+
+// ListByProductID retrieves a paginated list of serial units for a product
+func (r *MemorySerialUnitRepository) ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.SerialUnit, error) {
+	var units []*domain.SerialUnit
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.SerialUnits {
+			if stored.ProductID == productID && inTenantScope(ctx, stored.TenantID) {
+				copied := *stored
+				units = append(units, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(units, func(a, b *domain.SerialUnit) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(units, limit, offset), err
+}
+
+// UpdateStatus persists a status change and its history event atomically.
+// The update only applies if the stored status still matches the event's
+// from status, so concurrent transitions of the same unit cannot both win.
+func (r *MemorySerialUnitRepository) UpdateStatus(ctx context.Context, unit *domain.SerialUnit, event *domain.SerialUnitEvent) error {
+	now := time.Now()
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.SerialUnits[unit.ID]
+		if !ok || !inTenantScope(ctx, stored.TenantID) {
+			return fmt.Errorf("serial unit %w", domain.ErrNotFound)
+		}
+		if stored.Status != event.FromStatus {
+			return fmt.Errorf("serial unit status changed concurrently to %s: %w", stored.Status, domain.ErrConflict)
+		}
+		updated := *stored
+		updated.Status = event.ToStatus
+		updated.UpdatedAt = now
+		put(tx, t.SerialUnits, unit.ID, &updated)
+
+		event.ID = uuid.New().String()
+		event.SerialUnitID = unit.ID
+		event.CreatedAt = now
+		recorded := *event
+		put(tx, t.SerialUnitEvents, event.ID, &recorded)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	unit.UpdatedAt = now
+	return nil
+}
+
+// GetHistory retrieves the status history of a serial unit, newest first.
+// Events are in the tenant scope of their unit.
+func (r *MemorySerialUnitRepository) GetHistory(ctx context.Context, unitID string, limit, offset int) ([]*domain.SerialUnitEvent, error) {
+	var events []*domain.SerialUnitEvent
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		if unit, ok := t.SerialUnits[unitID]; !ok || !inTenantScope(ctx, unit.TenantID) {
+			return nil
+		}
+		for _, stored := range t.SerialUnitEvents {
+			if stored.SerialUnitID == unitID {
+				copied := *stored
+				events = append(events, &copied)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(events, func(a, b *domain.SerialUnitEvent) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(events, limit, offset), err
+}
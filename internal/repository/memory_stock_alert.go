@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryStockAlertRepository implements StockAlertRepository with a
+// MemoryStore
+type MemoryStockAlertRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryStockAlertRepository creates a new MemoryStockAlertRepository
+func NewMemoryStockAlertRepository(store *MemoryStore) *MemoryStockAlertRepository {
+	return &MemoryStockAlertRepository{store: store}
+}
+
+// Raise stores a new open alert for an inventory row. It returns false
+// without storing anything when the row already has an open alert.
+func (r *MemoryStockAlertRepository) Raise(ctx context.Context, alert *domain.StockAlert) (bool, error) {
+	alert.ID = uuid.New().String()
+	now := time.Now()
+	alert.Status = domain.StockAlertStatusOpen
+	alert.RaisedAt = now
+	alert.UpdatedAt = now
+
+	raised := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		item, ok := t.Inventory[alert.InventoryID]
+		if !ok {
+			return nil
+		}
+		for _, other := range t.StockAlerts {
+			if other.Row.InventoryID == alert.InventoryID && other.Row.Status == domain.StockAlertStatusOpen {
+				return nil
+			}
+		}
+		stored := *alert
+		stored.SKU = ""
+		stored.ResolvedAt = nil
+		put(tx, t.StockAlerts, alert.ID, &memoryRow[domain.StockAlert]{Row: stored, TenantID: item.TenantID})
+		raised = true
+		return nil
+	})
+	return raised, err
+}
+
+// Refresh updates the kind and figures of an open alert
+func (r *MemoryStockAlertRepository) Refresh(ctx context.Context, alert *domain.StockAlert) error {
+	alert.UpdatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.StockAlerts[alert.ID]
+		if !ok || row.Row.Status != domain.StockAlertStatusOpen {
+			return nil
+		}
+		updated := *row
+		updated.Row.Kind = alert.Kind
+		updated.Row.Available = alert.Available
+		updated.Row.OnOrder = alert.OnOrder
+		updated.Row.ReorderLevel = alert.ReorderLevel
+		updated.Row.DailyVelocity = alert.DailyVelocity
+		updated.Row.LeadTimeDays = alert.LeadTimeDays
+		updated.Row.ProjectedStockoutAt = alert.ProjectedStockoutAt
+		updated.Row.UpdatedAt = alert.UpdatedAt
+		put(tx, t.StockAlerts, alert.ID, &updated)
+		return nil
+	})
+}
+
+// Resolve closes an open alert. It returns false when the alert was not
+// open anymore.
+func (r *MemoryStockAlertRepository) Resolve(ctx context.Context, id string, at time.Time) (bool, error) {
+	resolved := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		row, ok := t.StockAlerts[id]
+		if !ok || row.Row.Status != domain.StockAlertStatusOpen {
+			return nil
+		}
+		updated := *row
+		updated.Row.Status = domain.StockAlertStatusResolved
+		updated.Row.ResolvedAt = &at
+		updated.Row.UpdatedAt = at
+		put(tx, t.StockAlerts, id, &updated)
+		resolved = true
+		return nil
+	})
+	return resolved, err
+}
+
+// ListOpen retrieves every open alert
+func (r *MemoryStockAlertRepository) ListOpen(ctx context.Context) ([]*domain.StockAlert, error) {
+	return r.list(ctx, func(alert *domain.StockAlert) bool { return alert.Status == domain.StockAlertStatusOpen })
+}
+
+// List retrieves a page of alerts, newest first, optionally only those in
+// the given status
+func (r *MemoryStockAlertRepository) List(ctx context.Context, status domain.StockAlertStatus, limit, offset int) ([]*domain.StockAlert, error) {
+	alerts, err := r.list(ctx, func(alert *domain.StockAlert) bool { return status == "" || alert.Status == status })
+	slices.SortStableFunc(alerts, func(a, b *domain.StockAlert) int { return b.RaisedAt.Compare(a.RaisedAt) })
+	return page(alerts, limit, offset), err
+}
+
+// list returns the alerts in scope that match with the SKU of their product,
+// oldest first
+func (r *MemoryStockAlertRepository) list(ctx context.Context, match func(alert *domain.StockAlert) bool) ([]*domain.StockAlert, error) {
+	var alerts []*domain.StockAlert
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.StockAlerts {
+			product, ok := t.Products[row.Row.ProductID]
+			if !ok || !inTenantScope(ctx, row.TenantID) || !match(&row.Row) {
+				continue
+			}
+			alert := row.Row
+			alert.SKU = product.Product.SKU
+			alerts = append(alerts, &alert)
+		}
+		return nil
+	})
+	slices.SortFunc(alerts, func(a, b *domain.StockAlert) int {
+		return cmp.Or(a.RaisedAt.Compare(b.RaisedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return alerts, err
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryQueuedMovement is a queued movement with its position in the order
+// movements were received
+type memoryQueuedMovement struct {
+	Movement domain.QueuedMovement
+	Seq      int64
+}
+
+// MemoryStocktakeRepository implements StocktakeRepository with a MemoryStore
+type MemoryStocktakeRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryStocktakeRepository creates a new MemoryStocktakeRepository
+func NewMemoryStocktakeRepository(store *MemoryStore) *MemoryStocktakeRepository {
+	return &MemoryStocktakeRepository{store: store}
+}
+
+// copyStocktake copies a stored stocktake, its closing time included
+func copyStocktake(stored *domain.Stocktake) *domain.Stocktake {
+	copied := *stored
+	if stored.ClosedAt != nil {
+		closedAt := *stored.ClosedAt
+		copied.ClosedAt = &closedAt
+	}
+	return &copied
+}
+
+// openStocktake returns the open stocktake of a location, if any
+func (t *memoryTables) openStocktake(location string) *domain.Stocktake {
+	for _, stored := range t.Stocktakes {
+		if stored.Location == location && stored.Status == domain.StocktakeStatusOpen {
+			return stored
+		}
+	}
+	return nil
+}
+
+// Create inserts a new open stocktake. At most one stocktake can be open per
+// location.
+func (r *MemoryStocktakeRepository) Create(ctx context.Context, stocktake *domain.Stocktake) error {
+	if err := stocktake.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	stocktake.ID = uuid.New().String()
+	stocktake.Status = domain.StocktakeStatusOpen
+	stocktake.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if t.openStocktake(stocktake.Location) != nil {
+			return fmt.Errorf("failed to create stocktake: location %s already has an open stocktake", stocktake.Location)
+		}
+		stored := *stocktake
+		stored.Replayed = nil
+		put(tx, t.Stocktakes, stocktake.ID, &stored)
+		return nil
+	})
+}
+
+// GetByID retrieves a stocktake by ID
+func (r *MemoryStocktakeRepository) GetByID(ctx context.Context, id string) (*domain.Stocktake, error) {
+	var stocktake *domain.Stocktake
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.Stocktakes[id]
+		if !ok {
+			return fmt.Errorf("stocktake %w", domain.ErrNotFound)
+		}
+		stocktake = copyStocktake(stored)
+		return nil
+	})
+	return stocktake, err
+}
+
+// FindOpen returns the open stocktake of a location, or nil if there is none
+func (r *MemoryStocktakeRepository) FindOpen(ctx context.Context, location string) (*domain.Stocktake, error) {
+	var stocktake *domain.Stocktake
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		if stored := t.openStocktake(location); stored != nil {
+			stocktake = copyStocktake(stored)
+		}
+		return nil
+	})
+	return stocktake, err
+}
+
+// List retrieves a paginated list of stocktakes, newest first
+func (r *MemoryStocktakeRepository) List(ctx context.Context, limit, offset int) ([]*domain.Stocktake, error) {
+	var stocktakes []*domain.Stocktake
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Stocktakes {
+			stocktakes = append(stocktakes, copyStocktake(stored))
+		}
+		return nil
+	})
+	slices.SortFunc(stocktakes, func(a, b *domain.Stocktake) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(stocktakes, limit, offset), err
+}
+
+// Close marks an open stocktake closed. It reports false if the stocktake was
+// not open.
+func (r *MemoryStocktakeRepository) Close(ctx context.Context, id string) (bool, error) {
+	closed := false
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Stocktakes[id]
+		if !ok || stored.Status != domain.StocktakeStatusOpen {
+			return nil
+		}
+		updated := copyStocktake(stored)
+		closedAt := time.Now()
+		updated.Status = domain.StocktakeStatusClosed
+		updated.ClosedAt = &closedAt
+		put(tx, t.Stocktakes, id, updated)
+		closed = true
+		return nil
+	})
+	return closed, err
+}
+
+// QueueMovement stores a movement received while its location was frozen
+func (r *MemoryStocktakeRepository) QueueMovement(ctx context.Context, movement *domain.QueuedMovement) error {
+	movement.ID = uuid.New().String()
+	movement.Status = domain.QueuedMovementPending
+	movement.CreatedAt = time.Now()
+	if movement.Condition == "" {
+		movement.Condition = domain.ConditionNew
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Stocktakes[movement.StocktakeID]; !ok {
+			return fmt.Errorf("failed to queue movement: stocktake %s does not exist", movement.StocktakeID)
+		}
+		set(tx, &t.QueuedMovementSeq, t.QueuedMovementSeq+1)
+		put(tx, t.QueuedMovements, movement.ID, &memoryQueuedMovement{Movement: *movement, Seq: t.QueuedMovementSeq})
+		return nil
+	})
+}
+
+// ListPendingMovements returns the pending movements of a stocktake in the
+// order they were received
+func (r *MemoryStocktakeRepository) ListPendingMovements(ctx context.Context, stocktakeID string) ([]*domain.QueuedMovement, error) {
+	var pending []*memoryQueuedMovement
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.QueuedMovements {
+			if stored.Movement.StocktakeID == stocktakeID && stored.Movement.Status == domain.QueuedMovementPending {
+				pending = append(pending, stored)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(pending, func(a, b *memoryQueuedMovement) int {
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+
+	movements := make([]*domain.QueuedMovement, 0, len(pending))
+	for _, stored := range pending {
+		movement := stored.Movement
+		movements = append(movements, &movement)
+	}
+	return movements, err
+}
+
+// UpdateMovementStatus records the outcome of replaying a queued movement
+func (r *MemoryStocktakeRepository) UpdateMovementStatus(ctx context.Context, id string, status domain.QueuedMovementStatus, errorMessage string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.QueuedMovements[id]
+		if !ok {
+			return nil
+		}
+		updated := *stored
+		updated.Movement.Status = status
+		updated.Movement.Error = errorMessage
+		put(tx, t.QueuedMovements, id, &updated)
+		return nil
+	})
+}
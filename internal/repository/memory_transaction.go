@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// memoryTransaction is a row of the transactions table, or of the archive
+// once Archived is set
+type memoryTransaction struct {
+	Transaction domain.Transaction
+	TenantID    string
+	Archived    bool
+}
+
+// memoryRow is a row of a table whose domain type does not carry the tenant
+// the row belongs to
+type memoryRow[T any] struct {
+	Row      T
+	TenantID string
+}
+
+// insertTransaction records the transaction, which inherits the tenant of its
+// inventory item, as the next in its product's event sequence. It returns
+// ErrNotFound when the item is outside the tenant scope of ctx.
+func (t *memoryTables) insertTransaction(ctx context.Context, tx *memoryTx, transaction *domain.Transaction) error {
+	item, ok := t.itemInScope(ctx, transaction.InventoryID)
+	product, exists := t.Products[transaction.ProductID]
+	if !ok || !exists {
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+
+	numbered := *product
+	numbered.EventSequence++
+	put(tx, t.Products, product.Product.ID, &numbered)
+	transaction.Sequence = numbered.EventSequence
+
+	row := &memoryTransaction{Transaction: *transaction, TenantID: item.TenantID}
+	row.Transaction.Annotations = nil
+	put(tx, t.Transactions, transaction.ID, row)
+	return nil
+}
+
+// transactions returns copies of the transactions in scope that match,
+// newest first
+func (t *memoryTables) transactions(ctx context.Context, includeArchived bool, match func(transaction *domain.Transaction) bool) []*domain.Transaction {
+	var transactions []*domain.Transaction
+	for _, row := range t.Transactions {
+		if (includeArchived || !row.Archived) && inTenantScope(ctx, row.TenantID) && match(&row.Transaction) {
+			transaction := row.Transaction
+			transactions = append(transactions, &transaction)
+		}
+	}
+	slices.SortFunc(transactions, compareTransactionsNewestFirst)
+	return transactions
+}
+
+// compareTransactionsNewestFirst orders transactions by creation, newest first
+func compareTransactionsNewestFirst(a, b *domain.Transaction) int {
+	return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+}
+
+// MemoryTransactionRepository implements TransactionRepository and
+// TransactionRetentionRepository with a MemoryStore
+type MemoryTransactionRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryTransactionRepository creates a new MemoryTransactionRepository
+func NewMemoryTransactionRepository(store *MemoryStore) *MemoryTransactionRepository {
+	return &MemoryTransactionRepository{store: store}
+}
+
+// Create inserts a new transaction
+func (r *MemoryTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
+	if err := transaction.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	transaction.ID = uuid.New().String()
+	transaction.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if err := t.insertTransaction(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a transaction by ID, live or archived
+func (r *MemoryTransactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	var transaction *domain.Transaction
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		row, ok := t.Transactions[id]
+		if !ok || !inTenantScope(ctx, row.TenantID) {
+			return fmt.Errorf("transaction %w", domain.ErrNotFound)
+		}
+		copied := row.Transaction
+		transaction = &copied
+		return nil
+	})
+	return transaction, err
+}
+
+// GetByInventoryID retrieves transactions for a specific inventory item
+func (r *MemoryTransactionRepository) GetByInventoryID(ctx context.Context, inventoryID string, limit, offset int) ([]*domain.Transaction, error) {
+	return r.list(ctx, false, limit, offset, func(transaction *domain.Transaction) bool {
+		return transaction.InventoryID == inventoryID
+	})
+}
+
+// GetByProductID retrieves transactions for a specific product
+func (r *MemoryTransactionRepository) GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Transaction, error) {
+	return r.list(ctx, false, limit, offset, func(transaction *domain.Transaction) bool {
+		return transaction.ProductID == productID
+	})
+}
+
+// RecentByProductIDs retrieves the newest transactions of many products, at
+// most perProduct of each, keyed by product ID
+func (r *MemoryTransactionRepository) RecentByProductIDs(ctx context.Context, productIDs []string, perProduct int) (map[string][]*domain.Transaction, error) {
+	transactions := make(map[string][]*domain.Transaction, len(productIDs))
+	if len(productIDs) == 0 || perProduct <= 0 {
+		return transactions, nil
+	}
+
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, transaction := range t.transactions(ctx, false, func(transaction *domain.Transaction) bool {
+			return slices.Contains(productIDs, transaction.ProductID)
+		}) {
+			if len(transactions[transaction.ProductID]) < perProduct {
+				transactions[transaction.ProductID] = append(transactions[transaction.ProductID], transaction)
+			}
+		}
+		return nil
+	})
+	return transactions, err
+}
+
+// Search retrieves the transactions matching the filter, newest first. With
+// a cursor it returns the transactions after it and ignores offset.
+func (r *MemoryTransactionRepository) Search(ctx context.Context, filter TransactionFilter, after *domain.TransactionCursor, limit, offset int) ([]*domain.Transaction, error) {
+	if after != nil {
+		offset = 0
+	}
+	return r.list(ctx, filter.IncludeArchived, limit, offset, func(transaction *domain.Transaction) bool {
+		if after != nil && compareTransactionsNewestFirst(transaction, &domain.Transaction{CreatedAt: after.CreatedAt, ID: after.ID}) <= 0 {
+			return false
+		}
+		return filter.matches(transaction)
+	})
+}
+
+// CountMatching returns the number of transactions matching the filter
+func (r *MemoryTransactionRepository) CountMatching(ctx context.Context, filter TransactionFilter) (int64, error) {
+	transactions, err := r.list(ctx, filter.IncludeArchived, -1, 0, filter.matches)
+	return int64(len(transactions)), err
+}
+
+// matches reports whether the filter selects the transaction, leaving the
+// tenant scope and the archive to the caller
+func (f TransactionFilter) matches(transaction *domain.Transaction) bool {
+	return (f.ProductID == "" || transaction.ProductID == f.ProductID) &&
+		f.Expression.Matches(transaction.FilterValue)
+}
+
+// List retrieves a paginated list of transactions
+func (r *MemoryTransactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Transaction, error) {
+	return r.list(ctx, false, limit, offset, func(transaction *domain.Transaction) bool { return true })
+}
+
+// GetByDateRange retrieves transactions created in [from, to), live or
+// archived, oldest first; with a cursor, those after it
+func (r *MemoryTransactionRepository) GetByDateRange(ctx context.Context, from, to time.Time, after *domain.TransactionCursor, limit int) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		transactions = t.transactions(ctx, true, func(transaction *domain.Transaction) bool {
+			return !transaction.CreatedAt.Before(from) && transaction.CreatedAt.Before(to) &&
+				(after == nil || cmp.Or(transaction.CreatedAt.Compare(after.CreatedAt), cmp.Compare(transaction.ID, after.ID)) > 0)
+		})
+		return nil
+	})
+	slices.Reverse(transactions)
+	return page(transactions, limit, 0), err
+}
+
+// ReservedByReference returns the units of a product currently reserved under
+// a reference, i.e. its reservations minus its releases
+func (r *MemoryTransactionRepository) ReservedByReference(ctx context.Context, productID, reference string) (int64, error) {
+	transactions, err := r.list(ctx, false, -1, 0, func(transaction *domain.Transaction) bool {
+		return transaction.ProductID == productID && transaction.Reference == reference
+	})
+
+	var reserved int64
+	for _, transaction := range transactions {
+		switch transaction.Type {
+		case "RESERVE":
+			reserved += transaction.Quantity
+		case "UNRESERVE":
+			reserved -= transaction.Quantity
+		}
+	}
+	return reserved, err
+}
+
+// OutboundByInventory sums the units of a product that left each inventory
+// item through OUT transactions since the given time, keyed by inventory ID
+func (r *MemoryTransactionRepository) OutboundByInventory(ctx context.Context, productID string, since time.Time) (map[string]int64, error) {
+	transactions, err := r.list(ctx, false, -1, 0, func(transaction *domain.Transaction) bool {
+		return transaction.ProductID == productID && transaction.Type == "OUT" && !transaction.CreatedAt.Before(since)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outbound := make(map[string]int64)
+	for _, transaction := range transactions {
+		outbound[transaction.InventoryID] += transaction.Quantity
+	}
+	return outbound, nil
+}
+
+// Count returns the total number of transactions
+func (r *MemoryTransactionRepository) Count(ctx context.Context) (int64, error) {
+	transactions, err := r.list(ctx, false, -1, 0, func(transaction *domain.Transaction) bool { return true })
+	return int64(len(transactions)), err
+}
+
+// list returns a page of the transactions in scope that match, newest first
+func (r *MemoryTransactionRepository) list(ctx context.Context, includeArchived bool, limit, offset int, match func(transaction *domain.Transaction) bool) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		transactions = t.transactions(ctx, includeArchived, match)
+		return nil
+	})
+	return page(transactions, limit, offset), err
+}
+
+// EnsurePartitions does nothing, as the store does not partition
+// transactions, and reports no partition created
+func (r *MemoryTransactionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) (int, error) {
+	return 0, nil
+}
+
+// ArchiveBefore moves up to limit of the oldest transactions created before
+// the cutoff to the archive and returns how many it moved
+func (r *MemoryTransactionRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	var moved int64
+	err := r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		var ids []string
+		for id, row := range t.Transactions {
+			if !row.Archived && row.Transaction.CreatedAt.Before(cutoff) {
+				ids = append(ids, id)
+			}
+		}
+		slices.SortFunc(ids, func(a, b string) int {
+			return t.Transactions[a].Transaction.CreatedAt.Compare(t.Transactions[b].Transaction.CreatedAt)
+		})
+
+		for _, id := range page(ids, limit, 0) {
+			archived := *t.Transactions[id]
+			archived.Archived = true
+			put(tx, t.Transactions, id, &archived)
+			moved++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive transactions: %w", err)
+	}
+	return moved, nil
+}
+
+// DropPartitionsBefore does nothing, as the store does not partition
+// transactions, and reports no partition dropped
+func (r *MemoryTransactionRepository) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	return nil, nil
+}
+
+// MemoryTransactionAnnotationRepository implements
+// TransactionAnnotationRepository with a MemoryStore
+type MemoryTransactionAnnotationRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryTransactionAnnotationRepository creates a new
+// MemoryTransactionAnnotationRepository
+func NewMemoryTransactionAnnotationRepository(store *MemoryStore) *MemoryTransactionAnnotationRepository {
+	return &MemoryTransactionAnnotationRepository{store: store}
+}
+
+// Create appends an annotation to a transaction
+func (r *MemoryTransactionAnnotationRepository) Create(ctx context.Context, annotation *domain.TransactionAnnotation) error {
+	if err := annotation.Validate(); err != nil {
+		return err
+	}
+	annotation.ID = uuid.New().String()
+	annotation.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		put(tx, t.Annotations, annotation.ID, &memoryRow[domain.TransactionAnnotation]{Row: *annotation, TenantID: tenantOwner(ctx)})
+		return nil
+	})
+}
+
+// ListByTransactionIDs retrieves the annotations of the transactions, oldest
+// first, keyed by transaction ID. Transactions without annotations are
+// absent from the map.
+func (r *MemoryTransactionAnnotationRepository) ListByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*domain.TransactionAnnotation, error) {
+	var matching []*domain.TransactionAnnotation
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Annotations {
+			if inTenantScope(ctx, row.TenantID) && slices.Contains(transactionIDs, row.Row.TransactionID) {
+				annotation := row.Row
+				matching = append(matching, &annotation)
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(matching, func(a, b *domain.TransactionAnnotation) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	annotations := make(map[string][]*domain.TransactionAnnotation)
+	for _, annotation := range matching {
+		annotations[annotation.TransactionID] = append(annotations[annotation.TransactionID], annotation)
+	}
+	return annotations, err
+}
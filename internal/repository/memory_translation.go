@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// memoryTranslationKey is the primary key of the product_translations table
+type memoryTranslationKey struct {
+	ProductID string
+	Locale    string
+}
+
+// MemoryProductTranslationRepository implements ProductTranslationRepository
+// with a MemoryStore
+type MemoryProductTranslationRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryProductTranslationRepository creates a new
+// MemoryProductTranslationRepository
+func NewMemoryProductTranslationRepository(store *MemoryStore) *MemoryProductTranslationRepository {
+	return &MemoryProductTranslationRepository{store: store}
+}
+
+// Upsert creates or replaces the translation of a product for a locale
+func (r *MemoryProductTranslationRepository) Upsert(ctx context.Context, productID, locale string, translation domain.ProductTranslation) error {
+	if err := translation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		if _, ok := t.Products[productID]; !ok {
+			return fmt.Errorf("failed to save product translation: product %s does not exist", productID)
+		}
+		put(tx, t.Translations, memoryTranslationKey{ProductID: productID, Locale: locale}, translation)
+		return nil
+	})
+}
+
+// Delete removes the translation of a product for a locale
+func (r *MemoryProductTranslationRepository) Delete(ctx context.Context, productID, locale string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		key := memoryTranslationKey{ProductID: productID, Locale: locale}
+		if _, ok := t.Translations[key]; !ok {
+			return fmt.Errorf("product translation %w", domain.ErrNotFound)
+		}
+		remove(tx, t.Translations, key)
+		return nil
+	})
+}
+
+// GetByProductIDs retrieves all translations for the given products, keyed by
+// product ID and then locale
+func (r *MemoryProductTranslationRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string]map[string]domain.ProductTranslation, error) {
+	translations := make(map[string]map[string]domain.ProductTranslation)
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, translation := range t.Translations {
+			if !slices.Contains(productIDs, key.ProductID) {
+				continue
+			}
+			if translations[key.ProductID] == nil {
+				translations[key.ProductID] = make(map[string]domain.ProductTranslation)
+			}
+			translations[key.ProductID][key.Locale] = translation
+		}
+		return nil
+	})
+	return translations, err
+}
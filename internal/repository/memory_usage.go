@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// memoryUsageKey is the primary key of the usage counters of a tenant on a
+// day, formatted as time.DateOnly
+type memoryUsageKey struct {
+	Tenant string
+	Day    string
+}
+
+// MemoryUsageRepository implements UsageRepository with a MemoryStore
+type MemoryUsageRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryUsageRepository creates a new MemoryUsageRepository
+func NewMemoryUsageRepository(store *MemoryStore) *MemoryUsageRepository {
+	return &MemoryUsageRepository{store: store}
+}
+
+// AddUsage adds the counts to the tenants' counters of the given day
+func (r *MemoryUsageRepository) AddUsage(ctx context.Context, day time.Time, usage []*domain.TenantUsage) error {
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for _, u := range usage {
+			key := memoryUsageKey{Tenant: u.Tenant, Day: day.Format(time.DateOnly)}
+			counters := domain.TenantUsage{Tenant: u.Tenant}
+			if stored, ok := t.Usage[key]; ok {
+				counters = *stored
+			}
+			counters.Add(u)
+			put(tx, t.Usage, key, &counters)
+		}
+		return nil
+	})
+}
+
+// Usage sums the counters of the days from from to to, inclusive, per tenant.
+// A non-empty tenant restricts the result to it.
+func (r *MemoryUsageRepository) Usage(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantUsage, error) {
+	first, last := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	totals := map[string]*domain.TenantUsage{}
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for key, stored := range t.Usage {
+			if key.Day < first || key.Day > last || (tenant != "" && key.Tenant != tenant) {
+				continue
+			}
+			total, ok := totals[key.Tenant]
+			if !ok {
+				total = &domain.TenantUsage{Tenant: key.Tenant}
+				totals[key.Tenant] = total
+			}
+			total.Add(stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []*domain.TenantUsage
+	for _, total := range totals {
+		usage = append(usage, total)
+	}
+	slices.SortFunc(usage, func(a, b *domain.TenantUsage) int {
+		return cmp.Compare(a.Tenant, b.Tenant)
+	})
+	return usage, nil
+}
+
+// CountStoredSKUs counts the products that are not deleted
+func (r *MemoryUsageRepository) CountStoredSKUs(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Products {
+			if stored.Product.DeletedAt == nil {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// variant returns the variant of a row with the product fields and, when
+// withStock is set, the stock of the product joined in
+func (t *memoryTables) variant(row *memoryRow[domain.ProductVariant], withStock bool) *domain.ProductVariant {
+	variant := row.Row
+	variant.Attributes = maps.Clone(row.Row.Attributes)
+	if product, ok := t.Products[variant.ProductID]; ok {
+		variant.SKU = product.Product.SKU
+		variant.Name = product.Product.Name
+		variant.Price = product.Product.Price
+		variant.Archived = product.Product.DeletedAt != nil
+	}
+	if withStock {
+		for _, item := range t.Inventory {
+			if item.ProductID == variant.ProductID {
+				variant.Quantity += item.Quantity
+				variant.Reserved += item.Reserved
+			}
+		}
+		variant.Available = variant.Quantity - variant.Reserved
+	}
+	return &variant
+}
+
+// MemoryVariantRepository implements VariantRepository with a MemoryStore
+type MemoryVariantRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryVariantRepository creates a new MemoryVariantRepository
+func NewMemoryVariantRepository(store *MemoryStore) *MemoryVariantRepository {
+	return &MemoryVariantRepository{store: store}
+}
+
+// Create links the product of a variant to its parent. It returns
+// ErrDuplicateVariant when the parent already has a variant with the same
+// attributes.
+func (r *MemoryVariantRepository) Create(ctx context.Context, variant *domain.ProductVariant) error {
+	variant.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		_, product := t.Products[variant.ProductID]
+		_, parent := t.Products[variant.ParentID]
+		if _, exists := t.Variants[variant.ProductID]; exists || !product || !parent {
+			return fmt.Errorf("failed to create product variant: product %s cannot be a variant of %s", variant.ProductID, variant.ParentID)
+		}
+		for _, other := range t.Variants {
+			if other.Row.ParentID == variant.ParentID && maps.Equal(other.Row.Attributes, variant.Attributes) {
+				return domain.ErrDuplicateVariant
+			}
+		}
+
+		stored := domain.ProductVariant{
+			ProductID:  variant.ProductID,
+			ParentID:   variant.ParentID,
+			Attributes: maps.Clone(variant.Attributes),
+			CreatedAt:  variant.CreatedAt,
+		}
+		put(tx, t.Variants, variant.ProductID, &memoryRow[domain.ProductVariant]{Row: stored, TenantID: tenantOwner(ctx)})
+		return nil
+	})
+}
+
+// GetByProductID retrieves the variant a product is, without its stock. It
+// returns ErrNotFound when the product is not a variant.
+func (r *MemoryVariantRepository) GetByProductID(ctx context.Context, productID string) (*domain.ProductVariant, error) {
+	var variant *domain.ProductVariant
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		row, ok := t.Variants[productID]
+		if !ok || !inTenantScope(ctx, row.TenantID) {
+			return fmt.Errorf("product variant %w", domain.ErrNotFound)
+		}
+		variant = t.variant(row, false)
+		return nil
+	})
+	return variant, err
+}
+
+// ListByParent lists the variants of a parent product with their stock in
+// every location and condition, oldest first
+func (r *MemoryVariantRepository) ListByParent(ctx context.Context, parentID string) ([]*domain.ProductVariant, error) {
+	var variants []*domain.ProductVariant
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, row := range t.Variants {
+			if row.Row.ParentID == parentID && inTenantScope(ctx, row.TenantID) {
+				variants = append(variants, t.variant(row, true))
+			}
+		}
+		return nil
+	})
+	slices.SortFunc(variants, func(a, b *domain.ProductVariant) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ProductID, b.ProductID))
+	})
+	return variants, err
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryWarehouseRepository implements WarehouseRepository with a MemoryStore
+type MemoryWarehouseRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryWarehouseRepository creates a new MemoryWarehouseRepository
+func NewMemoryWarehouseRepository(store *MemoryStore) *MemoryWarehouseRepository {
+	return &MemoryWarehouseRepository{store: store}
+}
+
+// Create inserts a new warehouse
+func (r *MemoryWarehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for _, other := range t.Warehouses {
+			if other.Code == warehouse.Code {
+				return fmt.Errorf("failed to create warehouse: code %s is already used", warehouse.Code)
+			}
+		}
+
+		warehouse.ID = uuid.New().String()
+		now := time.Now()
+		warehouse.CreatedAt = now
+		warehouse.UpdatedAt = now
+		stored := *warehouse
+		put(tx, t.Warehouses, warehouse.ID, &stored)
+		return nil
+	})
+}
+
+// Update saves the name, address, ship schedule and calendar of a warehouse
+func (r *MemoryWarehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		stored, ok := t.Warehouses[warehouse.ID]
+		if !ok {
+			return fmt.Errorf("warehouse %w", domain.ErrNotFound)
+		}
+
+		warehouse.UpdatedAt = time.Now()
+		updated := *stored
+		updated.Name = warehouse.Name
+		updated.Address = warehouse.Address
+		updated.Timezone = warehouse.Timezone
+		updated.CutoffTime = warehouse.CutoffTime
+		updated.LeadTimeDays = warehouse.LeadTimeDays
+		updated.WorkingDays = slices.Clone(warehouse.WorkingDays)
+		updated.Holidays = slices.Clone(warehouse.Holidays)
+		updated.UpdatedAt = warehouse.UpdatedAt
+		put(tx, t.Warehouses, warehouse.ID, &updated)
+		return nil
+	})
+}
+
+// GetByID retrieves a warehouse by ID
+func (r *MemoryWarehouseRepository) GetByID(ctx context.Context, id string) (*domain.Warehouse, error) {
+	return r.get(ctx, func(warehouse *domain.Warehouse) bool { return warehouse.ID == id })
+}
+
+// GetByCode retrieves a warehouse by code
+func (r *MemoryWarehouseRepository) GetByCode(ctx context.Context, code string) (*domain.Warehouse, error) {
+	return r.get(ctx, func(warehouse *domain.Warehouse) bool { return warehouse.Code == code })
+}
+
+// get retrieves the warehouse that matches
+func (r *MemoryWarehouseRepository) get(ctx context.Context, match func(warehouse *domain.Warehouse) bool) (*domain.Warehouse, error) {
+	var warehouse *domain.Warehouse
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Warehouses {
+			if match(stored) {
+				copied := *stored
+				warehouse = &copied
+				return nil
+			}
+		}
+		return fmt.Errorf("warehouse %w", domain.ErrNotFound)
+	})
+	return warehouse, err
+}
+
+// List retrieves a paginated list of warehouses ordered by code
+func (r *MemoryWarehouseRepository) List(ctx context.Context, limit, offset int) ([]*domain.Warehouse, error) {
+	var warehouses []*domain.Warehouse
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.Warehouses {
+			copied := *stored
+			warehouses = append(warehouses, &copied)
+		}
+		return nil
+	})
+	slices.SortFunc(warehouses, func(a, b *domain.Warehouse) int { return cmp.Compare(a.Code, b.Code) })
+	return page(warehouses, limit, offset), err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryWMSRepository implements WMSRepository with a MemoryStore
+type MemoryWMSRepository struct {
+	store *MemoryStore
+}
+
+// NewMemoryWMSRepository creates a new MemoryWMSRepository
+func NewMemoryWMSRepository(store *MemoryStore) *MemoryWMSRepository {
+	return &MemoryWMSRepository{store: store}
+}
+
+// RecordEvent records an applied event. It returns ErrDuplicateWMSEvent when
+// the tenant already applied an event with the ID.
+func (r *MemoryWMSRepository) RecordEvent(ctx context.Context, event *domain.WMSEvent) error {
+	event.ScannedAt = event.ScannedAt.UTC()
+	event.ProcessedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		key := memoryEventKey{TenantID: tenantOwner(ctx), EventID: event.EventID}
+		if _, ok := t.WMSEvents[key]; ok {
+			return domain.ErrDuplicateWMSEvent
+		}
+		stored := *event
+		stored.TenantID = key.TenantID
+		stored.SKU = ""
+		stored.Reference = ""
+		put(tx, t.WMSEvents, key, &stored)
+		return nil
+	})
+}
+
+// GetEvent retrieves the event applied for an event ID
+func (r *MemoryWMSRepository) GetEvent(ctx context.Context, eventID string) (*domain.WMSEvent, error) {
+	var event *domain.WMSEvent
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		stored, ok := t.WMSEvents[memoryEventKey{TenantID: tenantOwner(ctx), EventID: eventID}]
+		if !ok {
+			return fmt.Errorf("WMS event %w", domain.ErrNotFound)
+		}
+		copied := *stored
+		event = &copied
+		return nil
+	})
+	return event, err
+}
+
+// DeadLetter records a message that could not be applied. A message
+// dead-lettered before, i.e. redelivered, is kept as first recorded.
+func (r *MemoryWMSRepository) DeadLetter(ctx context.Context, letter *domain.WMSDeadLetter) error {
+	letter.ID = uuid.New().String()
+	letter.CreatedAt = time.Now()
+
+	return r.store.write(ctx, func(ctx context.Context, t *memoryTables, tx *memoryTx) error {
+		for _, other := range t.WMSDeadLetters {
+			if other.Topic == letter.Topic && other.Partition == letter.Partition && other.Offset == letter.Offset {
+				return nil
+			}
+		}
+		stored := *letter
+		put(tx, t.WMSDeadLetters, letter.ID, &stored)
+		return nil
+	})
+}
+
+// ListDeadLetters retrieves dead-lettered messages, newest first
+func (r *MemoryWMSRepository) ListDeadLetters(ctx context.Context, limit, offset int) ([]*domain.WMSDeadLetter, error) {
+	var letters []*domain.WMSDeadLetter
+	err := r.store.read(ctx, func(t *memoryTables) error {
+		for _, stored := range t.WMSDeadLetters {
+			copied := *stored
+			letters = append(letters, &copied)
+		}
+		return nil
+	})
+	slices.SortFunc(letters, func(a, b *domain.WMSDeadLetter) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return page(letters, limit, offset), err
+}
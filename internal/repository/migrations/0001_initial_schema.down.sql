@@ -0,0 +1,20 @@
+-- Drops every table of the initial schema, and all data with it
+DROP TABLE IF EXISTS redactions;
+DROP TABLE IF EXISTS product_translations;
+DROP TABLE IF EXISTS stocktake_queued_movements;
+DROP TABLE IF EXISTS stocktakes;
+DROP TABLE IF EXISTS payload_samples;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS cart_hold_lines;
+DROP TABLE IF EXISTS cart_holds;
+DROP TABLE IF EXISTS stock_snapshots;
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS serial_unit_events;
+DROP TABLE IF EXISTS serial_units;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS inventory;
+DROP TABLE IF EXISTS warehouses;
+DROP TABLE IF EXISTS products;
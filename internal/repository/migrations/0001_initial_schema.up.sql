@@ -0,0 +1,274 @@
+-- Initial schema. Every statement is idempotent, so databases created before
+-- versioned migrations (by the former InitSchema) adopt this version as is.
+
+CREATE TABLE IF NOT EXISTS products (
+	id VARCHAR(36) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	sku VARCHAR(100) UNIQUE NOT NULL,
+	category VARCHAR(100),
+	price NUMERIC(10, 2) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(100);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS warehouses (
+	id VARCHAR(36) PRIMARY KEY,
+	code VARCHAR(255) NOT NULL UNIQUE,
+	name VARCHAR(255) NOT NULL,
+	address TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS inventory (
+	id VARCHAR(36) PRIMARY KEY,
+	product_id VARCHAR(36) NOT NULL,
+	warehouse_id VARCHAR(36) REFERENCES warehouses(id),
+	quantity BIGINT NOT NULL DEFAULT 0,
+	reserved BIGINT NOT NULL DEFAULT 0,
+	location VARCHAR(255) NOT NULL,
+	version BIGINT NOT NULL DEFAULT 1,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS reorder_level BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS safety_stock BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS bin_location VARCHAR(64);
+
+-- Multi-warehouse upgrade: one inventory row per product and location,
+-- with warehouses backfilled from the locations already in use
+ALTER TABLE inventory DROP CONSTRAINT IF EXISTS inventory_product_id_key;
+ALTER TABLE inventory ADD COLUMN IF NOT EXISTS warehouse_id VARCHAR(36) REFERENCES warehouses(id);
+INSERT INTO warehouses (id, code, name)
+	SELECT md5(random()::text || l.location)::uuid::text, l.location, l.location
+	FROM (SELECT DISTINCT location FROM inventory) l
+	ON CONFLICT (code) DO NOTHING;
+UPDATE inventory i SET warehouse_id = w.id
+	FROM warehouses w
+	WHERE i.warehouse_id IS NULL AND w.code = i.location;
+
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS cutoff_time VARCHAR(5);
+ALTER TABLE warehouses ADD COLUMN IF NOT EXISTS lead_time_days INT;
+
+CREATE TABLE IF NOT EXISTS transactions (
+	id VARCHAR(36) PRIMARY KEY,
+	inventory_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255),
+	notes TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS quantity_before BIGINT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS quantity_after BIGINT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reason_code VARCHAR(32);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS performed_by VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS serial_units (
+	id VARCHAR(36) PRIMARY KEY,
+	product_id VARCHAR(36) NOT NULL,
+	serial_number VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (product_id, serial_number),
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS serial_unit_events (
+	id VARCHAR(36) PRIMARY KEY,
+	serial_unit_id VARCHAR(36) NOT NULL,
+	from_status VARCHAR(20) NOT NULL,
+	to_status VARCHAR(20) NOT NULL,
+	reference VARCHAR(255),
+	notes TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (serial_unit_id) REFERENCES serial_units(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+	id VARCHAR(36) PRIMARY KEY,
+	product_id VARCHAR(36) NOT NULL,
+	inventory_id VARCHAR(36) NOT NULL,
+	location VARCHAR(255),
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS stock_snapshots (
+	product_id VARCHAR(36) NOT NULL,
+	day DATE NOT NULL,
+	on_hand BIGINT NOT NULL,
+	available BIGINT NOT NULL,
+	captured_at TIMESTAMP NOT NULL,
+	PRIMARY KEY (product_id, day),
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cart_holds (
+	id VARCHAR(36) PRIMARY KEY,
+	reference VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	expires_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS cart_hold_lines (
+	hold_id VARCHAR(36) NOT NULL,
+	line_no INTEGER NOT NULL,
+	sku VARCHAR(100) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	inventory_id VARCHAR(36) NOT NULL,
+	location VARCHAR(255),
+	quantity BIGINT NOT NULL,
+	PRIMARY KEY (hold_id, line_no),
+	FOREIGN KEY (hold_id) REFERENCES cart_holds(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+	id VARCHAR(36) PRIMARY KEY,
+	supplier VARCHAR(255) NOT NULL DEFAULT '',
+	notes TEXT NOT NULL DEFAULT '',
+	status VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	received_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+	order_id VARCHAR(36) NOT NULL,
+	line_no INTEGER NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	inventory_id VARCHAR(36) NOT NULL,
+	location VARCHAR(255),
+	quantity BIGINT NOT NULL,
+	PRIMARY KEY (order_id, line_no),
+	FOREIGN KEY (order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	key VARCHAR(255) PRIMARY KEY,
+	request_hash VARCHAR(64) NOT NULL,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	response BYTEA,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	completed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id VARCHAR(36) PRIMARY KEY,
+	type VARCHAR(50) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	progress_done BIGINT NOT NULL DEFAULT 0,
+	progress_total BIGINT NOT NULL DEFAULT 0,
+	summary JSONB,
+	error TEXT,
+	result BYTEA,
+	result_type VARCHAR(100),
+	created_by VARCHAR(255),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	finished_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS payload_samples (
+	id VARCHAR(36) PRIMARY KEY,
+	method VARCHAR(10) NOT NULL,
+	path TEXT NOT NULL,
+	query TEXT NOT NULL DEFAULT '',
+	status_code INTEGER NOT NULL,
+	request_body TEXT NOT NULL,
+	response_body TEXT NOT NULL,
+	truncated BOOLEAN NOT NULL DEFAULT FALSE,
+	duration_ms BIGINT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stocktakes (
+	id VARCHAR(36) PRIMARY KEY,
+	location VARCHAR(255) NOT NULL,
+	mode VARCHAR(10) NOT NULL,
+	status VARCHAR(10) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	closed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS stocktake_queued_movements (
+	seq BIGSERIAL,
+	id VARCHAR(36) PRIMARY KEY,
+	stocktake_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	location VARCHAR(255) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255) NOT NULL DEFAULT '',
+	status VARCHAR(10) NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (stocktake_id) REFERENCES stocktakes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS product_translations (
+	product_id VARCHAR(36) NOT NULL,
+	locale VARCHAR(8) NOT NULL,
+	name VARCHAR(255),
+	description TEXT,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (product_id, locale),
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS redactions (
+	id VARCHAR(36) PRIMARY KEY,
+	token VARCHAR(64) NOT NULL UNIQUE,
+	reason TEXT NOT NULL,
+	transactions_affected BIGINT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_sku ON products(sku);
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_active ON products(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_product_id ON inventory(product_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_product_location ON inventory(product_id, location);
+CREATE INDEX IF NOT EXISTS idx_inventory_warehouse_id ON inventory(warehouse_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_inventory_id ON transactions(inventory_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_product_id ON transactions(product_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_product_keyset ON transactions(product_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reservations_product_id ON reservations(product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reservations_pending_expiry ON reservations(expires_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_cart_holds_held_expiry ON cart_holds(expires_at) WHERE status = 'HELD';
+CREATE INDEX IF NOT EXISTS idx_cart_hold_lines_inventory_id ON cart_hold_lines(inventory_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_inventory_id ON purchase_order_lines(inventory_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_product_reference ON transactions(product_id, reference);
+CREATE INDEX IF NOT EXISTS idx_transactions_movements ON transactions(created_at) INCLUDE (product_id, type, quantity);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference ON transactions(reference, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_payload_samples_created_at ON payload_samples(created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stocktakes_open_location ON stocktakes(location) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_stocktake_queued_movements_stocktake ON stocktake_queued_movements(stocktake_id, seq);
+CREATE INDEX IF NOT EXISTS idx_serial_unit_events_unit_id ON serial_unit_events(serial_unit_id, created_at DESC);
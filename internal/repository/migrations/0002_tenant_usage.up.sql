@@ -0,0 +1,11 @@
+-- Daily usage counters per tenant (authenticated caller), for metering
+CREATE TABLE tenant_usage (
+	tenant VARCHAR(255) NOT NULL,
+	day DATE NOT NULL,
+	api_calls BIGINT NOT NULL DEFAULT 0,
+	transactions BIGINT NOT NULL DEFAULT 0,
+	transaction_units BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (tenant, day)
+);
+
+CREATE INDEX idx_tenant_usage_day ON tenant_usage(day);
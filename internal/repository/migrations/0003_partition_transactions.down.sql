@@ -0,0 +1,44 @@
+-- Back to a single transactions table, including the archived rows
+DROP VIEW IF EXISTS transactions_all;
+
+CREATE TABLE transactions_unpartitioned (
+	id VARCHAR(36) PRIMARY KEY,
+	inventory_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255),
+	notes TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	quantity_before BIGINT,
+	quantity_after BIGINT,
+	reason_code VARCHAR(32),
+	performed_by VARCHAR(255),
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+INSERT INTO transactions_unpartitioned
+SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+	quantity_before, quantity_after, reason_code, performed_by
+FROM transactions;
+
+-- Archived rows of since deleted products or inventory items cannot return
+INSERT INTO transactions_unpartitioned
+SELECT a.id, a.inventory_id, a.product_id, a.type, a.quantity, a.reference, a.notes, a.created_at,
+	a.quantity_before, a.quantity_after, a.reason_code, a.performed_by
+FROM transactions_archive a
+JOIN inventory i ON i.id = a.inventory_id
+JOIN products p ON p.id = a.product_id;
+
+DROP TABLE transactions_archive;
+DROP TABLE transactions;
+ALTER TABLE transactions_unpartitioned RENAME TO transactions;
+
+CREATE INDEX idx_transactions_inventory_id ON transactions(inventory_id);
+CREATE INDEX idx_transactions_product_id ON transactions(product_id);
+CREATE INDEX idx_transactions_product_keyset ON transactions(product_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC);
+CREATE INDEX idx_transactions_product_reference ON transactions(product_id, reference);
+CREATE INDEX idx_transactions_movements ON transactions(created_at) INCLUDE (product_id, type, quantity);
+CREATE INDEX idx_transactions_reference ON transactions(reference, created_at DESC, id DESC);
@@ -0,0 +1,86 @@
+-- Partition transactions by month of created_at so that old months can be
+-- archived and dropped cheaply. Rows outside the monthly partitions land in
+-- transactions_default. The partitioned table's primary key must include
+-- the partition key.
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+
+CREATE TABLE transactions (
+	id VARCHAR(36) NOT NULL,
+	inventory_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255),
+	notes TEXT,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	quantity_before BIGINT,
+	quantity_after BIGINT,
+	reason_code VARCHAR(32),
+	performed_by VARCHAR(255),
+	FOREIGN KEY (inventory_id) REFERENCES inventory(id) ON DELETE CASCADE,
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+) PARTITION BY RANGE (created_at);
+
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+
+-- One partition per month from the oldest transaction through two months ahead
+DO $$
+DECLARE
+	m DATE := date_trunc('month', COALESCE((SELECT MIN(created_at) FROM transactions_unpartitioned), CURRENT_TIMESTAMP))::date;
+	last_month DATE := (date_trunc('month', CURRENT_TIMESTAMP) + INTERVAL '2 months')::date;
+BEGIN
+	WHILE m <= last_month LOOP
+		EXECUTE format('CREATE TABLE %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+			'transactions_p' || to_char(m, 'YYYYMM'), m, (m + INTERVAL '1 month')::date);
+		m := (m + INTERVAL '1 month')::date;
+	END LOOP;
+END $$;
+
+INSERT INTO transactions (id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+	quantity_before, quantity_after, reason_code, performed_by)
+SELECT id, inventory_id, product_id, type, quantity, reference, notes, COALESCE(created_at, CURRENT_TIMESTAMP),
+	quantity_before, quantity_after, reason_code, performed_by
+FROM transactions_unpartitioned;
+
+DROP TABLE transactions_unpartitioned;
+
+ALTER TABLE transactions ADD PRIMARY KEY (id, created_at);
+CREATE INDEX idx_transactions_id ON transactions(id);
+CREATE INDEX idx_transactions_inventory_id ON transactions(inventory_id);
+CREATE INDEX idx_transactions_product_id ON transactions(product_id);
+CREATE INDEX idx_transactions_product_keyset ON transactions(product_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at DESC);
+CREATE INDEX idx_transactions_product_reference ON transactions(product_id, reference);
+CREATE INDEX idx_transactions_movements ON transactions(created_at) INCLUDE (product_id, type, quantity);
+CREATE INDEX idx_transactions_reference ON transactions(reference, created_at DESC, id DESC);
+
+-- Transactions past the retention period, moved here by the retention job
+CREATE TABLE transactions_archive (
+	id VARCHAR(36) PRIMARY KEY,
+	inventory_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255),
+	notes TEXT,
+	created_at TIMESTAMP NOT NULL,
+	quantity_before BIGINT,
+	quantity_after BIGINT,
+	reason_code VARCHAR(32),
+	performed_by VARCHAR(255),
+	archived_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transactions_archive_product_keyset ON transactions_archive(product_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_archive_created_at ON transactions_archive(created_at DESC);
+CREATE INDEX idx_transactions_archive_reference ON transactions_archive(reference, created_at DESC, id DESC);
+
+-- Live and archived transactions, for queries across the retention boundary
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by
+	FROM transactions_archive;
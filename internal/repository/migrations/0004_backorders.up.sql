@@ -0,0 +1,15 @@
+-- Shortfalls of partially reserved orders
+CREATE TABLE backorders (
+	id VARCHAR(36) PRIMARY KEY,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL DEFAULT '',
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	reference VARCHAR(255) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backorders_product_status ON backorders(product_id, status, created_at DESC);
+CREATE INDEX idx_backorders_status ON backorders(status, created_at DESC);
+CREATE INDEX idx_backorders_reference ON backorders(reference);
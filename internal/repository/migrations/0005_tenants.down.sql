@@ -0,0 +1,23 @@
+DROP VIEW IF EXISTS transactions_all;
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by
+	FROM transactions_archive;
+
+DROP INDEX IF EXISTS idx_transactions_archive_tenant_created_at;
+DROP INDEX IF EXISTS idx_transactions_tenant_created_at;
+DROP INDEX IF EXISTS idx_inventory_tenant_created_at;
+DROP INDEX IF EXISTS idx_products_tenant_created_at;
+
+-- Fails while two tenants share a SKU
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_tenant_sku_key;
+ALTER TABLE products ADD CONSTRAINT products_sku_key UNIQUE (sku);
+
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE inventory DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE products DROP COLUMN IF EXISTS tenant_id;
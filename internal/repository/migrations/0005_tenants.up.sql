@@ -0,0 +1,25 @@
+-- Tenant (merchant) owning each product and, through it, its inventory and
+-- transactions. Existing data belongs to the default tenant.
+ALTER TABLE products ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE inventory ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE transactions ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE transactions_archive ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- SKUs are unique per tenant
+ALTER TABLE products DROP CONSTRAINT products_sku_key;
+ALTER TABLE products ADD CONSTRAINT products_tenant_sku_key UNIQUE (tenant_id, sku);
+
+CREATE INDEX idx_products_tenant_created_at ON products(tenant_id, created_at DESC);
+CREATE INDEX idx_inventory_tenant_created_at ON inventory(tenant_id, created_at DESC);
+CREATE INDEX idx_transactions_tenant_created_at ON transactions(tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_archive_tenant_created_at ON transactions_archive(tenant_id, created_at DESC, id DESC);
+
+DROP VIEW transactions_all;
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id
+	FROM transactions_archive;
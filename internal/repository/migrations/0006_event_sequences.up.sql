@@ -0,0 +1,37 @@
+-- Per-product sequence numbering every stock change (transaction) of a
+-- product, so that consumers can order its events and detect gaps.
+-- products.event_sequence is the number of the product's latest transaction.
+ALTER TABLE products ADD COLUMN event_sequence BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions_archive ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0;
+
+DROP VIEW transactions_all;
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence
+	FROM transactions_archive;
+
+-- Number existing transactions, live and archived, in the order they were recorded
+CREATE TEMPORARY TABLE numbered_transactions AS
+	SELECT id, row_number() OVER (PARTITION BY product_id ORDER BY created_at, id) AS sequence
+	FROM transactions_all;
+
+UPDATE transactions t SET sequence = n.sequence
+FROM numbered_transactions n WHERE t.id = n.id;
+
+UPDATE transactions_archive t SET sequence = n.sequence
+FROM numbered_transactions n WHERE t.id = n.id;
+
+UPDATE products p SET event_sequence = s.sequence
+FROM (
+	SELECT product_id, MAX(sequence) AS sequence FROM transactions_all GROUP BY product_id
+) s
+WHERE p.id = s.product_id;
+
+DROP TABLE numbered_transactions;
+
+CREATE INDEX idx_transactions_product_sequence ON transactions(product_id, sequence);
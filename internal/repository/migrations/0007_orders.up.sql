@@ -0,0 +1,20 @@
+-- Orders coordinating the reservations of their lines; the order ID is the
+-- reference of every reservation and transaction of the order
+CREATE TABLE orders (
+	id VARCHAR(255) PRIMARY KEY,
+	status VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE order_lines (
+	order_id VARCHAR(255) NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+	line_no INTEGER NOT NULL,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL DEFAULT '',
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	reservation_id VARCHAR(36) NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+	PRIMARY KEY (order_id, line_no)
+);
+
+CREATE INDEX idx_orders_status ON orders(status, created_at DESC);
@@ -0,0 +1,8 @@
+ALTER TABLE stocktake_queued_movements DROP COLUMN IF EXISTS condition;
+
+-- Fails while a product is stocked in more than one condition at a location;
+-- merge or remove those rows first
+DROP INDEX IF EXISTS idx_inventory_product_location;
+CREATE UNIQUE INDEX idx_inventory_product_location ON inventory(product_id, location);
+
+ALTER TABLE inventory DROP COLUMN IF EXISTS condition;
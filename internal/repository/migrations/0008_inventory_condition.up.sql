@@ -0,0 +1,10 @@
+-- Stock of the same product and location is kept apart per condition, so
+-- refurbished or grade B units can be stocked and reserved next to new ones.
+-- Existing rows hold new stock.
+ALTER TABLE inventory ADD COLUMN condition VARCHAR(20) NOT NULL DEFAULT 'NEW';
+
+DROP INDEX idx_inventory_product_location;
+CREATE UNIQUE INDEX idx_inventory_product_location ON inventory(product_id, location, condition);
+
+-- Movements queued during a stocktake are replayed in their condition
+ALTER TABLE stocktake_queued_movements ADD COLUMN condition VARCHAR(20) NOT NULL DEFAULT 'NEW';
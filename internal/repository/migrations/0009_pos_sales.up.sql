@@ -0,0 +1,15 @@
+-- Point-of-sale sales, recorded once per client-assigned event ID and
+-- reported in the order they happened
+CREATE TABLE sales (
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	event_id VARCHAR(255) NOT NULL,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL,
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	status VARCHAR(20) NOT NULL,
+	occurred_at TIMESTAMP NOT NULL,
+	received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, event_id)
+);
+
+CREATE INDEX idx_sales_tenant_location_occurred_at ON sales(tenant_id, location, occurred_at);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS intercompany_transfers;
@@ -0,0 +1,23 @@
+-- Stock sold between tenants; the transfer ID is the reference of the
+-- sender's OUT and the receiver's IN transaction
+CREATE TABLE intercompany_transfers (
+	id VARCHAR(36) PRIMARY KEY,
+	from_tenant VARCHAR(64) NOT NULL,
+	to_tenant VARCHAR(64) NOT NULL,
+	sku VARCHAR(100) NOT NULL,
+	from_product_id VARCHAR(36) NOT NULL,
+	to_product_id VARCHAR(36) NOT NULL,
+	from_location VARCHAR(255) NOT NULL,
+	to_location VARCHAR(255) NOT NULL,
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	unit_cost NUMERIC(12, 2) NOT NULL,
+	total_cost NUMERIC(14, 2) NOT NULL,
+	reference VARCHAR(255) NOT NULL DEFAULT '',
+	out_transaction_id VARCHAR(36) NOT NULL,
+	in_transaction_id VARCHAR(36) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (from_tenant <> to_tenant)
+);
+
+CREATE INDEX idx_intercompany_transfers_from_tenant ON intercompany_transfers(from_tenant, created_at DESC);
+CREATE INDEX idx_intercompany_transfers_to_tenant ON intercompany_transfers(to_tenant, created_at DESC);
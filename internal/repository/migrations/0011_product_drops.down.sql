@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_products_release_at;
+ALTER TABLE products DROP COLUMN IF EXISTS release_at;
@@ -0,0 +1,5 @@
+-- Scheduled drops: a product's stock is hidden until its release time,
+-- which is cleared once the drop went live
+ALTER TABLE products ADD COLUMN release_at TIMESTAMP;
+
+CREATE INDEX idx_products_release_at ON products(release_at) WHERE release_at IS NOT NULL;
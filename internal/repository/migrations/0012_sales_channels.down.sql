@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reservations_pending_channel;
+ALTER TABLE reservations DROP COLUMN IF EXISTS channel_id;
+DROP TABLE IF EXISTS channel_allocations;
+DROP TABLE IF EXISTS sales_channels;
@@ -0,0 +1,24 @@
+-- Sales channels and the share of each product's stock they may hold
+-- reserved at once, as a percentage of on-hand stock or a fixed bucket
+CREATE TABLE sales_channels (
+	id VARCHAR(36) PRIMARY KEY,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	name VARCHAR(255) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE channel_allocations (
+	channel_id VARCHAR(36) NOT NULL REFERENCES sales_channels(id) ON DELETE CASCADE,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	percent NUMERIC(5, 2) CHECK (percent BETWEEN 0 AND 100),
+	quantity BIGINT CHECK (quantity >= 0),
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (channel_id, product_id),
+	CHECK ((percent IS NULL) <> (quantity IS NULL))
+);
+
+ALTER TABLE reservations ADD COLUMN channel_id VARCHAR(36) REFERENCES sales_channels(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_reservations_pending_channel ON reservations(channel_id, product_id) WHERE status = 'PENDING';
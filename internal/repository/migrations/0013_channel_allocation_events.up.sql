@@ -0,0 +1,16 @@
+-- History of channel allocation changes, made by hand or by the rebalancing
+-- job that returns unsold bucket units to the shared pool
+CREATE TABLE channel_allocation_events (
+	id VARCHAR(36) PRIMARY KEY,
+	channel_id VARCHAR(36) NOT NULL REFERENCES sales_channels(id) ON DELETE CASCADE,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	change VARCHAR(20) NOT NULL,
+	previous_percent NUMERIC(5, 2),
+	previous_quantity BIGINT,
+	percent NUMERIC(5, 2),
+	quantity BIGINT,
+	notes TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_channel_allocation_events_channel ON channel_allocation_events(channel_id, created_at DESC);
@@ -0,0 +1,25 @@
+-- Low-stock alerts per inventory row: raised when the projected stockout
+-- falls within the supplier lead time, or for rows without sales at their
+-- reorder level, and resolved once the row recovers
+CREATE TABLE stock_alerts (
+	id VARCHAR(36) PRIMARY KEY,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	inventory_id VARCHAR(36) NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL,
+	kind VARCHAR(30) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+	available BIGINT NOT NULL,
+	on_order BIGINT NOT NULL,
+	reorder_level BIGINT NOT NULL,
+	daily_velocity DOUBLE PRECISION NOT NULL,
+	lead_time_days INTEGER NOT NULL,
+	projected_stockout_at TIMESTAMP,
+	raised_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	resolved_at TIMESTAMP
+);
+
+-- At most one open alert per inventory row
+CREATE UNIQUE INDEX idx_stock_alerts_open ON stock_alerts(inventory_id) WHERE status = 'OPEN';
+CREATE INDEX idx_stock_alerts_status ON stock_alerts(tenant_id, status, raised_at DESC);
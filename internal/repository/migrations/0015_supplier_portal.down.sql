@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS purchase_order_asn_lines;
+DROP TABLE IF EXISTS purchase_order_asns;
+DROP INDEX IF EXISTS idx_purchase_orders_supplier;
+ALTER TABLE purchase_order_lines DROP COLUMN IF EXISTS confirmed_quantity;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS promised_at;
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS confirmed_at;
@@ -0,0 +1,31 @@
+-- Supplier portal: suppliers confirm open purchase orders with a delivery
+-- date and the quantities they will deliver, and announce shipments with
+-- advance shipping notices (ASNs)
+ALTER TABLE purchase_orders ADD COLUMN confirmed_at TIMESTAMP;
+ALTER TABLE purchase_orders ADD COLUMN promised_at TIMESTAMP;
+ALTER TABLE purchase_order_lines ADD COLUMN confirmed_quantity BIGINT;
+
+CREATE INDEX idx_purchase_orders_supplier ON purchase_orders(supplier, status, created_at DESC);
+
+CREATE TABLE purchase_order_asns (
+	id VARCHAR(36) PRIMARY KEY,
+	order_id VARCHAR(36) NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+	reference VARCHAR(100) NOT NULL,
+	carrier VARCHAR(100) NOT NULL DEFAULT '',
+	tracking_number VARCHAR(100) NOT NULL DEFAULT '',
+	shipped_at TIMESTAMP NOT NULL,
+	expected_at TIMESTAMP,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (order_id, reference)
+);
+
+CREATE TABLE purchase_order_asn_lines (
+	asn_id VARCHAR(36) NOT NULL REFERENCES purchase_order_asns(id) ON DELETE CASCADE,
+	order_id VARCHAR(36) NOT NULL,
+	line_no INTEGER NOT NULL,
+	quantity BIGINT NOT NULL,
+	PRIMARY KEY (asn_id, line_no),
+	FOREIGN KEY (order_id, line_no) REFERENCES purchase_order_lines(order_id, line_no) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_purchase_order_asn_lines_order ON purchase_order_asn_lines(order_id, line_no);
@@ -0,0 +1,15 @@
+-- Billable work per tenant for the 3PL operating the system: receipts and
+-- picks per transaction, shipments per outbound reference and storage per
+-- tenant and day. The key makes recording them idempotent.
+CREATE TABLE billing_events (
+	tenant_id VARCHAR(64) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	source_id VARCHAR(255) NOT NULL,
+	quantity BIGINT NOT NULL,
+	reference VARCHAR(255) NOT NULL DEFAULT '',
+	occurred_at TIMESTAMP NOT NULL,
+	recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, type, source_id)
+);
+
+CREATE INDEX idx_billing_events_occurred_at ON billing_events(occurred_at, tenant_id);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_transactions_costed_receipts;
+
+DROP VIEW IF EXISTS transactions_all;
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence
+	FROM transactions_archive;
+
+ALTER TABLE stocktake_queued_movements DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE transactions_archive DROP COLUMN IF EXISTS unit_cost;
+ALTER TABLE transactions DROP COLUMN IF EXISTS unit_cost;
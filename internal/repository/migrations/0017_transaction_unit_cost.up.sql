@@ -0,0 +1,19 @@
+-- Unit cost of received stock (IN and RETURN transactions), the cost layers
+-- inventory valuation is computed from. Queued receipts keep theirs until
+-- they are replayed.
+ALTER TABLE transactions ADD COLUMN unit_cost NUMERIC(14, 4);
+ALTER TABLE transactions_archive ADD COLUMN unit_cost NUMERIC(14, 4);
+ALTER TABLE stocktake_queued_movements ADD COLUMN unit_cost NUMERIC(14, 4);
+
+DROP VIEW transactions_all;
+CREATE VIEW transactions_all AS
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence, unit_cost
+	FROM transactions
+	UNION ALL
+	SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+		quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence, unit_cost
+	FROM transactions_archive;
+
+CREATE INDEX idx_transactions_costed_receipts ON transactions(product_id, created_at DESC)
+	WHERE unit_cost IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wms_dead_letters;
+DROP TABLE IF EXISTS wms_events;
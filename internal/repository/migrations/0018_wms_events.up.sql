@@ -0,0 +1,30 @@
+-- Warehouse scan events consumed from the WMS topic: the events applied,
+-- once per tenant and event ID, and the messages dead-lettered, once per
+-- position in the topic
+CREATE TABLE wms_events (
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	event_id VARCHAR(255) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL,
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	status VARCHAR(20) NOT NULL,
+	scanned_at TIMESTAMP NOT NULL,
+	processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (tenant_id, event_id)
+);
+
+CREATE TABLE wms_dead_letters (
+	id VARCHAR(36) PRIMARY KEY,
+	topic VARCHAR(255) NOT NULL,
+	partition INTEGER NOT NULL,
+	"offset" BIGINT NOT NULL,
+	key TEXT NOT NULL DEFAULT '',
+	payload TEXT NOT NULL,
+	event_id VARCHAR(255) NOT NULL DEFAULT '',
+	error TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (topic, partition, "offset")
+);
+
+CREATE INDEX idx_wms_dead_letters_created_at ON wms_dead_letters(created_at);
@@ -0,0 +1,12 @@
+-- Variants of a parent product, such as sizes and colors. Each variant is a
+-- product of its own, with its own SKU and inventory, linked to its parent
+-- with the attributes that tell it apart from its siblings.
+CREATE TABLE product_variants (
+	product_id VARCHAR(36) PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+	parent_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	attributes JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (product_id <> parent_id),
+	UNIQUE (parent_id, attributes)
+);
@@ -0,0 +1,8 @@
+-- Background components paused by an operator, across every instance. A
+-- component runs unless it has a row.
+CREATE TABLE background_pauses (
+	component VARCHAR(50) PRIMARY KEY,
+	paused_by VARCHAR(255) NOT NULL DEFAULT '',
+	reason TEXT NOT NULL DEFAULT '',
+	paused_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
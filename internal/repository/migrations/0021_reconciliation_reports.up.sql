@@ -0,0 +1,25 @@
+-- Runs of the stock reconciliation, which replays the transaction log of
+-- every inventory row and compares the result with the stored quantities,
+-- and the discrepancies each run found
+CREATE TABLE reconciliation_reports (
+	id VARCHAR(36) PRIMARY KEY,
+	started_at TIMESTAMP NOT NULL,
+	completed_at TIMESTAMP NOT NULL,
+	discrepancy_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_reconciliation_reports_completed_at ON reconciliation_reports(completed_at DESC);
+
+CREATE TABLE reconciliation_discrepancies (
+	report_id VARCHAR(36) NOT NULL REFERENCES reconciliation_reports(id) ON DELETE CASCADE,
+	inventory_id VARCHAR(36) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	location VARCHAR(255) NOT NULL,
+	condition VARCHAR(20) NOT NULL,
+	quantity BIGINT NOT NULL,
+	expected_quantity BIGINT NOT NULL,
+	reserved BIGINT NOT NULL,
+	expected_reserved BIGINT NOT NULL,
+	PRIMARY KEY (report_id, inventory_id)
+);
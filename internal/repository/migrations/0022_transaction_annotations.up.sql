@@ -0,0 +1,12 @@
+-- Notes appended to transactions after the fact. Transactions may be
+-- archived, so annotations reference them by ID without a foreign key.
+CREATE TABLE transaction_annotations (
+	id VARCHAR(36) PRIMARY KEY,
+	transaction_id VARCHAR(36) NOT NULL,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	note TEXT NOT NULL,
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_annotations_transaction_id ON transaction_annotations(transaction_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS supplier_payables;
+DROP TABLE IF EXISTS consignment_ownership_transfers;
+DROP TABLE IF EXISTS consignments;
@@ -0,0 +1,45 @@
+-- Consignment stock: lots received into inventory that remain the property
+-- of their supplier until sold. Selling a unit transfers its ownership to
+-- the tenant, recorded with the payable the tenant owes the supplier for it.
+CREATE TABLE consignments (
+	id VARCHAR(36) PRIMARY KEY,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	supplier VARCHAR(255) NOT NULL,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL,
+	unit_cost NUMERIC(12, 2) NOT NULL,
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	remaining BIGINT NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+	reference VARCHAR(255) NOT NULL DEFAULT '',
+	received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_consignments_open ON consignments(product_id, location, received_at) WHERE remaining > 0;
+
+CREATE TABLE consignment_ownership_transfers (
+	id VARCHAR(36) PRIMARY KEY,
+	consignment_id VARCHAR(36) NOT NULL REFERENCES consignments(id) ON DELETE CASCADE,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	supplier VARCHAR(255) NOT NULL,
+	product_id VARCHAR(36) NOT NULL,
+	location VARCHAR(255) NOT NULL,
+	quantity BIGINT NOT NULL,
+	unit_cost NUMERIC(12, 2) NOT NULL,
+	amount NUMERIC(14, 2) NOT NULL,
+	sale_event_id VARCHAR(255) NOT NULL,
+	sold_at TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_consignment_ownership_transfers_consignment ON consignment_ownership_transfers(consignment_id, created_at);
+
+CREATE TABLE supplier_payables (
+	id VARCHAR(36) PRIMARY KEY,
+	transfer_id VARCHAR(36) NOT NULL UNIQUE REFERENCES consignment_ownership_transfers(id) ON DELETE CASCADE,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	supplier VARCHAR(255) NOT NULL,
+	amount NUMERIC(14, 2) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_supplier_payables_created_at ON supplier_payables(created_at, tenant_id, supplier);
@@ -0,0 +1,19 @@
+-- Stock lots: batches of a product received into the new stock of a
+-- location with the day they expire. Removals take units from the lots
+-- expiring first; expired lots are written off with EXPIRED transactions.
+CREATE TABLE lots (
+	id VARCHAR(36) PRIMARY KEY,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+	inventory_id VARCHAR(36) NOT NULL REFERENCES inventory(id) ON DELETE CASCADE,
+	product_id VARCHAR(36) NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+	location VARCHAR(255) NOT NULL,
+	lot_number VARCHAR(100) NOT NULL,
+	expires_on DATE NOT NULL,
+	quantity BIGINT NOT NULL CHECK (quantity > 0),
+	remaining BIGINT NOT NULL CHECK (remaining >= 0 AND remaining <= quantity),
+	received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (inventory_id, lot_number)
+);
+
+CREATE INDEX idx_lots_product ON lots(product_id, location, received_at);
+CREATE INDEX idx_lots_open_expiry ON lots(expires_on, id) WHERE remaining > 0;
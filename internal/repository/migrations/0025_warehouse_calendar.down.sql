@@ -0,0 +1,2 @@
+ALTER TABLE warehouses DROP COLUMN IF EXISTS holidays;
+ALTER TABLE warehouses DROP COLUMN IF EXISTS working_days;
@@ -0,0 +1,5 @@
+-- Operating calendar of warehouses: the days of the week they work (MON to
+-- SUN) and the local dates they are closed. Ship-date estimates and lead
+-- times count operating days only; NULL takes the service default.
+ALTER TABLE warehouses ADD COLUMN working_days TEXT[];
+ALTER TABLE warehouses ADD COLUMN holidays DATE[];
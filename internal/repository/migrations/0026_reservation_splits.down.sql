@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reservations_parent;
+ALTER TABLE reservations DROP COLUMN IF EXISTS parent_id;
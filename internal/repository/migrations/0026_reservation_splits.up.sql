@@ -0,0 +1,6 @@
+-- Split reservations: a reservation no single location could cover is held
+-- as child reservations at several locations, linked by the ID of the split
+-- they share. Each child is confirmed, released and expires on its own.
+ALTER TABLE reservations ADD COLUMN parent_id VARCHAR(36);
+
+CREATE INDEX idx_reservations_parent ON reservations(parent_id) WHERE parent_id IS NOT NULL;
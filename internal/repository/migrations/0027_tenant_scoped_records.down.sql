@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_cart_holds_tenant;
+DROP INDEX IF EXISTS idx_orders_tenant_created_at;
+DROP INDEX IF EXISTS idx_serial_units_tenant_product;
+DROP INDEX IF EXISTS idx_reservations_tenant_status;
+
+ALTER TABLE cart_holds DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE serial_units DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE reservations DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,25 @@
+-- Reservations, serial units, orders and cart holds belong to a tenant like
+-- products do. Existing rows take the tenant of the stock they refer to.
+ALTER TABLE reservations ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE serial_units ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE orders ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE cart_holds ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+UPDATE reservations r SET tenant_id = i.tenant_id
+FROM inventory i WHERE i.id = r.inventory_id;
+
+UPDATE serial_units u SET tenant_id = p.tenant_id
+FROM products p WHERE p.id = u.product_id;
+
+UPDATE orders o SET tenant_id = p.tenant_id
+FROM order_lines l JOIN products p ON p.id = l.product_id
+WHERE l.order_id = o.id AND l.line_no = 1;
+
+UPDATE cart_holds h SET tenant_id = i.tenant_id
+FROM cart_hold_lines l JOIN inventory i ON i.id = l.inventory_id
+WHERE l.hold_id = h.id AND l.line_no = 1;
+
+CREATE INDEX idx_reservations_tenant_status ON reservations(tenant_id, status);
+CREATE INDEX idx_serial_units_tenant_product ON serial_units(tenant_id, product_id);
+CREATE INDEX idx_orders_tenant_created_at ON orders(tenant_id, created_at DESC, id DESC);
+CREATE INDEX idx_cart_holds_tenant ON cart_holds(tenant_id);
@@ -0,0 +1,15 @@
+-- Signed manifests of ledger exports, kept so that the manifest of a
+-- delivered file can be fetched again. tenant_id is the tenant the export
+-- was scoped to, or empty for an export of all tenants.
+CREATE TABLE audit_manifests (
+	id VARCHAR(36) PRIMARY KEY,
+	tenant_id VARCHAR(64) NOT NULL DEFAULT '',
+	period_from TIMESTAMP NOT NULL,
+	period_to TIMESTAMP NOT NULL,
+	generated_at TIMESTAMP NOT NULL,
+	row_count INTEGER NOT NULL,
+	sha256 VARCHAR(64) NOT NULL,
+	algorithm VARCHAR(20) NOT NULL,
+	public_key TEXT NOT NULL,
+	signature TEXT NOT NULL
+);
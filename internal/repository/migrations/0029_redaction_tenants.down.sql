@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_redactions_tenant_created_at;
+
+ALTER TABLE redactions DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,6 @@
+-- A redaction belongs to the tenant that requested it; redactions made
+-- without a tenant scope, and those recorded before, belong to the default
+-- tenant.
+ALTER TABLE redactions ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_redactions_tenant_created_at ON redactions(tenant_id, created_at DESC);
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/lib/pq"
+)
+
+// PostgresOrderRepository implements OrderRepository using PostgreSQL
+type PostgresOrderRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresOrderRepository creates a new PostgresOrderRepository
+func NewPostgresOrderRepository(db *sql.DB) *PostgresOrderRepository {
+	return &PostgresOrderRepository{db: db}
+}
+
+// Create inserts an order with its lines in one transaction, for the tenant
+// of ctx. It returns ErrDuplicateOrder when the order ID is already in use.
+func (r *PostgresOrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	order.TenantID = tenantOwner(ctx)
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO orders (id, status, created_at, updated_at, tenant_id)
+			VALUES ($1, $2, $3, $4, $5)
+		`, order.ID, order.Status, order.CreatedAt, order.UpdatedAt, order.TenantID)
+		if isUniqueViolation(err, "orders_pkey") {
+			return domain.ErrDuplicateOrder
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+
+		for i, line := range order.Lines {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO order_lines (order_id, line_no, product_id, location, quantity, reservation_id)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, order.ID, i+1, line.ProductID, line.Location, line.Quantity, line.ReservationID)
+			if err != nil {
+				return fmt.Errorf("failed to create order line %d: %w", i+1, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves an order with its lines
+func (r *PostgresOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	order := &domain.Order{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, tenant_id, status, created_at, updated_at FROM orders WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`, id, tenantScope(ctx)).Scan(&order.ID, &order.TenantID, &order.Status, &order.CreatedAt, &order.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("order %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	if err := r.loadLines(ctx, []*domain.Order{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// List retrieves orders with their lines, newest first, optionally only those
+// in the given status
+func (r *PostgresOrderRepository) List(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
+	query := `
+		SELECT id, tenant_id, status, created_at, updated_at
+		FROM orders
+		WHERE ($1 = '' OR status = $1) AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, status, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*domain.Order
+	for rows.Next() {
+		order := &domain.Order{}
+		if err := rows.Scan(&order.ID, &order.TenantID, &order.Status, &order.CreatedAt, &order.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating orders: %w", err)
+	}
+
+	if err := r.loadLines(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// UpdateStatus moves an order from one status to another. It reports false
+// when the order was no longer in the expected status.
+func (r *PostgresOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.OrderStatus) (bool, error) {
+	query := `
+		UPDATE orders
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND ($5 = '' OR tenant_id = $5)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, to, time.Now(), id, from, tenantScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// loadLines reads the lines of the given orders with one query
+func (r *PostgresOrderRepository) loadLines(ctx context.Context, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		order.Lines = []domain.OrderLine{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT order_id, product_id, location, quantity, reservation_id
+		FROM order_lines WHERE order_id = ANY($1) ORDER BY order_id, line_no
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get order lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var line domain.OrderLine
+		if err := rows.Scan(&orderID, &line.ProductID, &line.Location, &line.Quantity, &line.ReservationID); err != nil {
+			return fmt.Errorf("failed to scan order line: %w", err)
+		}
+		byID[orderID].Lines = append(byID[orderID].Lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating order lines: %w", err)
+	}
+
+	return nil
+}
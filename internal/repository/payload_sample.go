@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresPayloadSampleRepository implements PayloadSampleRepository using PostgreSQL
+type PostgresPayloadSampleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPayloadSampleRepository creates a new PostgresPayloadSampleRepository
+func NewPostgresPayloadSampleRepository(db *sql.DB) *PostgresPayloadSampleRepository {
+	return &PostgresPayloadSampleRepository{db: db}
+}
+
+// Create stores a payload sample
+func (r *PostgresPayloadSampleRepository) Create(ctx context.Context, sample *domain.PayloadSample) error {
+	sample.ID = uuid.New().String()
+	sample.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO payload_samples (id, method, path, query, status_code, request_body, response_body, truncated, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		sample.ID, sample.Method, sample.Path, sample.Query, sample.StatusCode,
+		sample.RequestBody, sample.ResponseBody, sample.Truncated, sample.DurationMs, sample.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create payload sample: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves a paginated list of payload samples, newest first. A
+// non-empty path restricts the result to requests containing it.
+func (r *PostgresPayloadSampleRepository) List(ctx context.Context, path string, limit, offset int) ([]*domain.PayloadSample, error) {
+	query := `
+		SELECT id, method, path, query, status_code, request_body, response_body, truncated, duration_ms, created_at
+		FROM payload_samples
+		WHERE $1 = '' OR path LIKE '%' || $1 || '%'
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, path, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payload samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []*domain.PayloadSample
+	for rows.Next() {
+		sample := &domain.PayloadSample{}
+		if err := rows.Scan(
+			&sample.ID, &sample.Method, &sample.Path, &sample.Query, &sample.StatusCode,
+			&sample.RequestBody, &sample.ResponseBody, &sample.Truncated, &sample.DurationMs, &sample.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan payload sample: %w", err)
+		}
+		samples = append(samples, sample)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating payload samples: %w", err)
+	}
+
+	return samples, nil
+}
+
+// DeleteBefore removes samples created before the cutoff and returns how many were deleted
+func (r *PostgresPayloadSampleRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM payload_samples WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete payload samples: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows, nil
+}
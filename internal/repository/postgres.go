@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
@@ -13,7 +12,8 @@ import (
 
 // PostgresProductRepository implements ProductRepository using PostgreSQL
 type PostgresProductRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	replica *ReadReplica
 }
 
 // NewPostgresProductRepository creates a new PostgresProductRepository
@@ -21,6 +21,13 @@ func NewPostgresProductRepository(db *sql.DB) *PostgresProductRepository {
 	return &PostgresProductRepository{db: db}
 }
 
+// WithReadReplica routes the product lookups and listings to replica while it
+// is healthy; a nil replica keeps every read on the primary
+func (r *PostgresProductRepository) WithReadReplica(replica *ReadReplica) *PostgresProductRepository {
+	r.replica = replica
+	return r
+}
+
 // Create inserts a new product
 func (r *PostgresProductRepository) Create(ctx context.Context, product *domain.Product) error {
 	if err := product.Validate(); err != nil {
@@ -28,19 +35,23 @@ func (r *PostgresProductRepository) Create(ctx context.Context, product *domain.
 	}
 
 	product.ID = uuid.New().String()
+	product.TenantID = tenantOwner(ctx)
 	now := time.Now()
 	product.CreatedAt = now
 	product.UpdatedAt = now
 
 	query := `
-		INSERT INTO products (id, name, description, sku, price, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO products (id, name, description, sku, category, price, created_at, updated_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		product.ID, product.Name, product.Description, product.SKU, product.Price,
-		product.CreatedAt, product.UpdatedAt,
+		product.ID, product.Name, product.Description, product.SKU, product.Category, product.Price,
+		product.CreatedAt, product.UpdatedAt, product.TenantID,
 	)
+	if isUniqueViolation(err, "products_tenant_sku_key") {
+		return &domain.DuplicateSKUError{SKU: product.SKU}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create product: %w", err)
 	}
@@ -48,21 +59,35 @@ func (r *PostgresProductRepository) Create(ctx context.Context, product *domain.
 	return nil
 }
 
-// GetByID retrieves a product by ID
-func (r *PostgresProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
-	query := `
-		SELECT id, name, description, sku, price, created_at, updated_at
-		FROM products WHERE id = $1
-	`
+// productColumns are the product columns read by scanProduct
+const productColumns = `id, name, description, sku, COALESCE(category, ''), price, created_at, updated_at, deleted_at, tenant_id, release_at`
 
+// scanProduct scans a row of productColumns
+func scanProduct(row interface{ Scan(...any) error }) (*domain.Product, error) {
 	product := &domain.Product{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&product.ID, &product.Name, &product.Description, &product.SKU,
-		&product.Price, &product.CreatedAt, &product.UpdatedAt,
-	)
+	var deletedAt, releaseAt sql.NullTime
+	if err := row.Scan(
+		&product.ID, &product.Name, &product.Description, &product.SKU, &product.Category,
+		&product.Price, &product.CreatedAt, &product.UpdatedAt, &deletedAt, &product.TenantID, &releaseAt,
+	); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.Time
+	}
+	if releaseAt.Valid {
+		product.ReleaseAt = &releaseAt.Time
+	}
+	return product, nil
+}
+
+// GetByID retrieves a product by ID, archived or not
+func (r *PostgresProductRepository) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	query := `SELECT ` + productColumns + ` FROM products WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
 
+	product, err := scanProduct(reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, id, tenantScope(ctx)))
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
@@ -71,21 +96,16 @@ func (r *PostgresProductRepository) GetByID(ctx context.Context, id string) (*do
 	return product, nil
 }
 
-// GetBySKU retrieves a product by SKU
+// GetBySKU retrieves a product by SKU, archived or not. Unscoped, the SKU may
+// be used by several tenants and the oldest product with it is returned.
 func (r *PostgresProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
-	query := `
-		SELECT id, name, description, sku, price, created_at, updated_at
-		FROM products WHERE sku = $1
-	`
-
-	product := &domain.Product{}
-	err := r.db.QueryRowContext(ctx, query, sku).Scan(
-		&product.ID, &product.Name, &product.Description, &product.SKU,
-		&product.Price, &product.CreatedAt, &product.UpdatedAt,
-	)
+	query := `SELECT ` + productColumns + ` FROM products WHERE sku = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at ASC, id ASC
+		LIMIT 1`
 
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, sku, tenantScope(ctx)))
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get product: %w", err)
@@ -94,16 +114,22 @@ func (r *PostgresProductRepository) GetBySKU(ctx context.Context, sku string) (*
 	return product, nil
 }
 
-// List retrieves a paginated list of products
-func (r *PostgresProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+// List retrieves a paginated list of the products matching filter, leaving
+// out archived products unless includeArchived is set
+func (r *PostgresProductRepository) List(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) ([]*domain.Product, error) {
+	condition, args, err := filterCondition(filter, productFilterColumns, []any{limit, offset, includeArchived, tenantScope(ctx)})
+	if err != nil {
+		return nil, err
+	}
 	query := `
-		SELECT id, name, description, sku, price, created_at, updated_at
+		SELECT ` + productColumns + `
 		FROM products
+		WHERE ($3 OR deleted_at IS NULL) AND ($4 = '' OR tenant_id = $4) AND ` + condition + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := reader(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
@@ -111,11 +137,8 @@ func (r *PostgresProductRepository) List(ctx context.Context, limit, offset int)
 
 	var products []*domain.Product
 	for rows.Next() {
-		product := &domain.Product{}
-		if err := rows.Scan(
-			&product.ID, &product.Name, &product.Description, &product.SKU,
-			&product.Price, &product.CreatedAt, &product.UpdatedAt,
-		); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
 		products = append(products, product)
@@ -138,14 +161,17 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.
 
 	query := `
 		UPDATE products
-		SET name = $1, description = $2, sku = $3, price = $4, updated_at = $5
-		WHERE id = $6
+		SET name = $1, description = $2, sku = $3, category = $4, price = $5, updated_at = $6
+		WHERE id = $7 AND ($8 = '' OR tenant_id = $8)
 	`
 
 	result, err := r.db.ExecContext(ctx, query,
-		product.Name, product.Description, product.SKU, product.Price,
-		product.UpdatedAt, product.ID,
+		product.Name, product.Description, product.SKU, product.Category, product.Price,
+		product.UpdatedAt, product.ID, tenantScope(ctx),
 	)
+	if isUniqueViolation(err, "products_tenant_sku_key") {
+		return &domain.DuplicateSKUError{SKU: product.SKU}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
@@ -156,17 +182,43 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *domain.
 	}
 
 	if rows == 0 {
-		return errors.New("product not found")
+		return fmt.Errorf("product %w", domain.ErrNotFound)
 	}
 
 	return nil
 }
 
-// Delete deletes a product
+// Patch updates the fields set in the patch in place, leaving the others as
+// they are even when they change concurrently, and returns the product as
+// patched
+func (r *PostgresProductRepository) Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	query := `
+		UPDATE products
+		SET name = COALESCE($1, name), description = COALESCE($2, description),
+			category = COALESCE($3, category), price = COALESCE($4, price), updated_at = $5
+		WHERE id = $6 AND ($7 = '' OR tenant_id = $7)
+		RETURNING ` + productColumns
+
+	product, err := scanProduct(conn(ctx, r.db).QueryRowContext(ctx, query,
+		patch.Name, patch.Description, patch.Category, patch.Price, time.Now(), id, tenantScope(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch product: %w", err)
+	}
+
+	return product, nil
+}
+
+// Delete permanently deletes a product together with its inventory and
+// transactions. Products are archived instead once they have been created;
+// Delete only undoes a creation that could not be completed.
 func (r *PostgresProductRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM products WHERE id = $1`
+	query := `DELETE FROM products WHERE id = $1 AND ($2 = '' OR tenant_id = $2)`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, tenantScope(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -177,21 +229,142 @@ func (r *PostgresProductRepository) Delete(ctx context.Context, id string) error
 	}
 
 	if rows == 0 {
-		return errors.New("product not found")
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Archive soft-deletes a product. Archiving an archived product keeps its
+// original deletion time.
+func (r *PostgresProductRepository) Archive(ctx context.Context, id string) error {
+	return r.setDeletedAt(ctx, id, `COALESCE(deleted_at, $2)`, time.Now())
+}
+
+// Restore brings an archived product back
+func (r *PostgresProductRepository) Restore(ctx context.Context, id string) error {
+	return r.setDeletedAt(ctx, id, `NULL`, time.Now())
+}
+
+func (r *PostgresProductRepository) setDeletedAt(ctx context.Context, id, deletedAt string, now time.Time) error {
+	query := `UPDATE products SET deleted_at = ` + deletedAt + `, updated_at = $2
+		WHERE id = $1 AND ($3 = '' OR tenant_id = $3)`
+
+	result, err := r.db.ExecContext(ctx, query, id, now, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
 	}
 
 	return nil
 }
 
-// Count returns the total number of products
-func (r *PostgresProductRepository) Count(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM products`
+// Count returns the number of products matching filter, leaving out
+// archived products unless includeArchived is set
+func (r *PostgresProductRepository) Count(ctx context.Context, includeArchived bool, filter *domain.Filter) (int64, error) {
+	condition, args, err := filterCondition(filter, productFilterColumns, []any{includeArchived, tenantScope(ctx)})
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT COUNT(*) FROM products WHERE ($1 OR deleted_at IS NULL) AND ($2 = '' OR tenant_id = $2) AND ` + condition
 
 	var count int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err = reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count products: %w", err)
 	}
 
 	return count, nil
 }
+
+// SetReleaseAt schedules the drop of a product, or cancels it when releaseAt
+// is nil
+func (r *PostgresProductRepository) SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error {
+	query := `UPDATE products SET release_at = $2, updated_at = $3
+		WHERE id = $1 AND ($4 = '' OR tenant_id = $4)`
+
+	result, err := r.db.ExecContext(ctx, query, id, releaseAt, time.Now(), tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to schedule product release: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListScheduledReleases retrieves the products with a drop scheduled, the
+// next release first
+func (r *PostgresProductRepository) ListScheduledReleases(ctx context.Context) ([]*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE release_at IS NOT NULL AND ($1 = '' OR tenant_id = $1)
+		ORDER BY release_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled releases: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	return products, nil
+}
+
+// ReleaseDue clears the drops whose release time has come by now and returns
+// the IDs of their products
+func (r *PostgresProductRepository) ReleaseDue(ctx context.Context, now time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE products SET release_at = NULL, updated_at = $1
+		WHERE release_at <= $1 AND ($2 = '' OR tenant_id = $2)
+		RETURNING id
+	`, now, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to release products: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan product id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating released products: %w", err)
+	}
+
+	return ids, nil
+}
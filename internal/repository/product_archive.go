@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/lib/pq"
+)
+
+// productArchiveCriteria matches the products p of the tenant $1 that a
+// filter selects: category $2, zero stock when $3 is set and, when $4 is set,
+// created before the cutoff $5 without any stock movement, live or
+// archived, since
+const productArchiveCriteria = `
+	p.deleted_at IS NULL AND ($1 = '' OR p.tenant_id = $1)
+	AND ($2 = '' OR p.category = $2)
+	AND NOT EXISTS (SELECT 1 FROM inventory i WHERE i.product_id = p.id AND i.reserved > 0)
+	AND (NOT $3 OR NOT EXISTS (SELECT 1 FROM inventory i WHERE i.product_id = p.id AND i.quantity <> 0))
+	AND (NOT $4 OR (p.created_at < $5 AND NOT EXISTS (
+		SELECT 1 FROM transactions_all t WHERE t.product_id = p.id AND t.created_at >= $5)))`
+
+// PostgresProductArchiveRepository implements ProductArchiveRepository using PostgreSQL
+type PostgresProductArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresProductArchiveRepository creates a new PostgresProductArchiveRepository
+func NewPostgresProductArchiveRepository(db *sql.DB) *PostgresProductArchiveRepository {
+	return &PostgresProductArchiveRepository{db: db}
+}
+
+// Candidates lists at most limit active products the filter selects, by SKU,
+// with their stock on hand and latest movement
+func (r *PostgresProductArchiveRepository) Candidates(ctx context.Context, filter domain.ProductArchiveFilter, cutoff time.Time, limit int) ([]*domain.ArchiveCandidate, error) {
+	query := `
+		SELECT p.id, p.sku, p.name, p.category,
+			COALESCE((SELECT SUM(i.quantity) FROM inventory i WHERE i.product_id = p.id), 0),
+			(SELECT MAX(t.created_at) FROM transactions_all t WHERE t.product_id = p.id)
+		FROM products p
+		WHERE ` + productArchiveCriteria + `
+		ORDER BY p.sku
+		LIMIT $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantScope(ctx), filter.Category, filter.ZeroStock,
+		filter.InactiveDays > 0, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find products to archive: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*domain.ArchiveCandidate
+	for rows.Next() {
+		candidate := &domain.ArchiveCandidate{}
+		var lastMovementAt sql.NullTime
+		if err := rows.Scan(&candidate.ProductID, &candidate.SKU, &candidate.Name, &candidate.Category,
+			&candidate.Quantity, &lastMovementAt); err != nil {
+			return nil, fmt.Errorf("failed to scan product to archive: %w", err)
+		}
+		if lastMovementAt.Valid {
+			candidate.LastMovementAt = &lastMovementAt.Time
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products to archive: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// ArchiveMatching archives those of the given products the filter still
+// selects and returns their IDs
+func (r *PostgresProductArchiveRepository) ArchiveMatching(ctx context.Context, ids []string, filter domain.ProductArchiveFilter, cutoff time.Time) ([]string, error) {
+	query := `
+		UPDATE products p SET deleted_at = $6, updated_at = $6
+		WHERE p.id = ANY($7) AND ` + productArchiveCriteria + `
+		RETURNING p.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantScope(ctx), filter.Category, filter.ZeroStock,
+		filter.InactiveDays > 0, cutoff, time.Now(), pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive products: %w", err)
+	}
+	defer rows.Close()
+
+	var archived []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan archived product: %w", err)
+		}
+		archived = append(archived, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating archived products: %w", err)
+	}
+
+	return archived, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresProductImportRepository implements ProductImportRepository using PostgreSQL
+type PostgresProductImportRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresProductImportRepository creates a new PostgresProductImportRepository
+func NewPostgresProductImportRepository(db *sql.DB) *PostgresProductImportRepository {
+	return &PostgresProductImportRepository{db: db}
+}
+
+// ExistingSKUs returns which of the given SKUs are already used by a product
+// of the tenant the import is for
+func (r *PostgresProductImportRepository) ExistingSKUs(ctx context.Context, skus []string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT sku FROM products WHERE sku = ANY($1) AND tenant_id = $2`,
+		pq.Array(skus), tenantOwner(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up SKUs: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var sku string
+		if err := rows.Scan(&sku); err != nil {
+			return nil, fmt.Errorf("failed to scan SKU: %w", err)
+		}
+		existing[sku] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating SKUs: %w", err)
+	}
+
+	return existing, nil
+}
+
+// ImportBatch inserts the products, inventory items and initial stock
+// transactions of a batch in one database transaction; either the whole
+// batch is written or none of it
+func (r *PostgresProductImportRepository) ImportBatch(ctx context.Context, batch []*domain.ProductImport) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	tenantID := tenantOwner(ctx)
+	for _, entry := range batch {
+		product, item := entry.Product, entry.Inventory
+
+		product.ID = uuid.New().String()
+		product.TenantID = tenantID
+		product.CreatedAt = now
+		product.UpdatedAt = now
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO products (id, name, description, sku, category, price, created_at, updated_at, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`, product.ID, product.Name, product.Description, product.SKU, product.Category, product.Price,
+			product.CreatedAt, product.UpdatedAt, product.TenantID)
+		if isUniqueViolation(err, "products_tenant_sku_key") {
+			return &domain.DuplicateSKUError{SKU: product.SKU}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create product %s: %w", product.SKU, err)
+		}
+
+		item.ID = uuid.New().String()
+		item.TenantID = tenantID
+		item.ProductID = product.ID
+		item.Version = 1
+		item.Condition = domain.ConditionNew
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO inventory (id, product_id, warehouse_id, quantity, reserved, location, version, created_at, updated_at,
+				tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, item.ID, item.ProductID, nullIfEmpty(item.WarehouseID), item.Quantity, item.Reserved, item.Location,
+			item.Version, item.CreatedAt, item.UpdatedAt, item.TenantID)
+		if err != nil {
+			return fmt.Errorf("failed to create inventory for %s: %w", product.SKU, err)
+		}
+
+		if transaction := entry.Transaction; transaction != nil {
+			transaction.ID = uuid.New().String()
+			transaction.InventoryID = item.ID
+			transaction.ProductID = product.ID
+			transaction.CreatedAt = now
+			if err := insertTransaction(ctx, tx, transaction); err != nil {
+				return fmt.Errorf("failed to record initial stock for %s: %w", product.SKU, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
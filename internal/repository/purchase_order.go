@@ -0,0 +1,409 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresPurchaseOrderRepository implements PurchaseOrderRepository using PostgreSQL
+type PostgresPurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresPurchaseOrderRepository creates a new PostgresPurchaseOrderRepository
+func NewPostgresPurchaseOrderRepository(db *sql.DB) *PostgresPurchaseOrderRepository {
+	return &PostgresPurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `id, supplier, notes, status, created_at, updated_at, received_at, confirmed_at, promised_at`
+
+// Create inserts a purchase order with its lines in one transaction
+func (r *PostgresPurchaseOrderRepository) Create(ctx context.Context, order *domain.PurchaseOrder) error {
+	if err := order.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	order.ID = uuid.New().String()
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO purchase_orders (id, supplier, notes, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, order.ID, order.Supplier, order.Notes, order.Status, order.CreatedAt, order.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order: %w", err)
+		}
+
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			line.Line = i + 1
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO purchase_order_lines (order_id, line_no, product_id, inventory_id, location, quantity)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, order.ID, line.Line, line.ProductID, line.InventoryID, line.Location, line.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to create purchase order line %d: %w", line.Line, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves a purchase order with its lines
+func (r *PostgresPurchaseOrderRepository) GetByID(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	query := `SELECT ` + purchaseOrderColumns + ` FROM purchase_orders WHERE id = $1`
+
+	order, err := scanPurchaseOrder(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("purchase order %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+
+	if err := r.loadLines(ctx, []*domain.PurchaseOrder{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// List retrieves purchase orders with their lines, newest first, optionally
+// only those in the given status
+func (r *PostgresPurchaseOrderRepository) List(ctx context.Context, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	return r.list(ctx, `
+		SELECT `+purchaseOrderColumns+`
+		FROM purchase_orders
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+}
+
+// ListForSupplier retrieves the purchase orders of one supplier with their
+// lines, newest first, optionally only those in the given status
+func (r *PostgresPurchaseOrderRepository) ListForSupplier(ctx context.Context, supplier string, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	return r.list(ctx, `
+		SELECT `+purchaseOrderColumns+`
+		FROM purchase_orders
+		WHERE supplier = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`, supplier, status, limit, offset)
+}
+
+// list runs a purchase order query and loads the lines of its orders
+func (r *PostgresPurchaseOrderRepository) list(ctx context.Context, query string, args ...any) ([]*domain.PurchaseOrder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*domain.PurchaseOrder
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating purchase orders: %w", err)
+	}
+
+	if err := r.loadLines(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// UpdateStatus moves a purchase order from one status to another, recording
+// the receipt time when it is received. It reports false when the order was
+// no longer in the expected status.
+func (r *PostgresPurchaseOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.PurchaseOrderStatus) (bool, error) {
+	query := `
+		UPDATE purchase_orders
+		SET status = $1, updated_at = $2,
+			received_at = CASE WHEN $1 = $5 THEN $2 END
+		WHERE id = $3 AND status = $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, to, time.Now(), id, from, domain.PurchaseOrderStatusReceived)
+	if err != nil {
+		return false, fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Lock locks a purchase order until the unit of work of ctx ends, so that
+// confirmations and shipping notices of the order are checked against its
+// current lines one at a time
+func (r *PostgresPurchaseOrderRepository) Lock(ctx context.Context, id string) error {
+	var locked string
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT id FROM purchase_orders WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("purchase order %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock purchase order: %w", err)
+	}
+	return nil
+}
+
+// Confirm records the supplier's confirmation of a draft purchase order: the
+// promised delivery date and the confirmed quantity of every line by line
+// number. It reports false when the order was no longer a draft.
+func (r *PostgresPurchaseOrderRepository) Confirm(ctx context.Context, id string, promisedAt time.Time, quantities map[int]int64) (bool, error) {
+	confirmed := false
+	err := withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		now := time.Now()
+		result, err := tx.ExecContext(ctx, `
+			UPDATE purchase_orders SET confirmed_at = $1, promised_at = $2, updated_at = $1
+			WHERE id = $3 AND status = $4
+		`, now, promisedAt, id, domain.PurchaseOrderStatusDraft)
+		if err != nil {
+			return fmt.Errorf("failed to confirm purchase order: %w", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		if rows == 0 {
+			return nil
+		}
+
+		for line, quantity := range quantities {
+			_, err := tx.ExecContext(ctx, `
+				UPDATE purchase_order_lines SET confirmed_quantity = $1 WHERE order_id = $2 AND line_no = $3
+			`, quantity, id, line)
+			if err != nil {
+				return fmt.Errorf("failed to confirm purchase order line %d: %w", line, err)
+			}
+		}
+		confirmed = true
+		return nil
+	})
+	return confirmed, err
+}
+
+// CreateShippingNotice stores a shipping notice with its lines in one
+// transaction. A notice whose reference the order already has is rejected
+// with domain.ErrDuplicateShippingNotice.
+func (r *PostgresPurchaseOrderRepository) CreateShippingNotice(ctx context.Context, notice *domain.AdvanceShippingNotice) error {
+	notice.ID = uuid.New().String()
+	notice.CreatedAt = time.Now()
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO purchase_order_asns (id, order_id, reference, carrier, tracking_number, shipped_at, expected_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, notice.ID, notice.PurchaseOrderID, notice.Reference, notice.Carrier, notice.TrackingNumber,
+			notice.ShippedAt, notice.ExpectedAt, notice.CreatedAt)
+		if isUniqueViolation(err, "purchase_order_asns_order_id_reference_key") {
+			return domain.ErrDuplicateShippingNotice
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create shipping notice: %w", err)
+		}
+
+		for _, line := range notice.Lines {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO purchase_order_asn_lines (asn_id, order_id, line_no, quantity) VALUES ($1, $2, $3, $4)
+			`, notice.ID, notice.PurchaseOrderID, line.Line, line.Quantity)
+			if err != nil {
+				return fmt.Errorf("failed to create shipping notice line %d: %w", line.Line, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListShippingNotices retrieves the shipping notices of a purchase order
+// with their lines, oldest first
+func (r *PostgresPurchaseOrderRepository) ListShippingNotices(ctx context.Context, orderID string) ([]*domain.AdvanceShippingNotice, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.order_id, a.reference, a.carrier, a.tracking_number, a.shipped_at, a.expected_at, a.created_at,
+			l.line_no, l.quantity
+		FROM purchase_order_asns a
+		JOIN purchase_order_asn_lines l ON l.asn_id = a.id
+		WHERE a.order_id = $1
+		ORDER BY a.created_at, a.id, l.line_no
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipping notices: %w", err)
+	}
+	defer rows.Close()
+
+	var notices []*domain.AdvanceShippingNotice
+	for rows.Next() {
+		notice := &domain.AdvanceShippingNotice{}
+		var expectedAt sql.NullTime
+		var line domain.QuantityLine
+		if err := rows.Scan(
+			&notice.ID, &notice.PurchaseOrderID, &notice.Reference, &notice.Carrier, &notice.TrackingNumber,
+			&notice.ShippedAt, &expectedAt, &notice.CreatedAt, &line.Line, &line.Quantity,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan shipping notice: %w", err)
+		}
+		if n := len(notices); n > 0 && notices[n-1].ID == notice.ID {
+			notice = notices[n-1]
+		} else {
+			if expectedAt.Valid {
+				notice.ExpectedAt = &expectedAt.Time
+			}
+			notices = append(notices, notice)
+		}
+		notice.Lines = append(notice.Lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shipping notices: %w", err)
+	}
+
+	return notices, nil
+}
+
+// ReorderCandidates returns the inventory rows of active products that have
+// a reorder level or sold since the given time, with their units sold since
+// then and the units on draft purchase orders
+func (r *PostgresPurchaseOrderRepository) ReorderCandidates(ctx context.Context, since time.Time) ([]*domain.ReorderCandidate, error) {
+	query := `
+		WITH outbound AS (
+			SELECT inventory_id, SUM(quantity) AS quantity
+			FROM transactions
+			WHERE type = 'OUT' AND created_at >= $1 AND ($3 = '' OR tenant_id = $3)
+			GROUP BY inventory_id
+		), on_order AS (
+			SELECT l.inventory_id, SUM(l.quantity) AS quantity
+			FROM purchase_order_lines l
+			JOIN purchase_orders o ON o.id = l.order_id
+			WHERE o.status = $2
+			GROUP BY l.inventory_id
+		)
+		SELECT p.id, p.sku, p.name, i.id, i.location,
+			GREATEST(i.quantity - i.reserved, 0), i.reorder_level, i.safety_stock,
+			COALESCE(ob.quantity, 0), COALESCE(oo.quantity, 0)
+		FROM inventory i
+		JOIN products p ON p.id = i.product_id
+		LEFT JOIN outbound ob ON ob.inventory_id = i.id
+		LEFT JOIN on_order oo ON oo.inventory_id = i.id
+		WHERE p.deleted_at IS NULL AND (i.reorder_level > 0 OR ob.quantity > 0) AND ($3 = '' OR p.tenant_id = $3)
+		ORDER BY p.sku, i.location
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since.UTC(), domain.PurchaseOrderStatusDraft, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reorder candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*domain.ReorderCandidate
+	for rows.Next() {
+		c := &domain.ReorderCandidate{}
+		if err := rows.Scan(
+			&c.ProductID, &c.SKU, &c.Name, &c.InventoryID, &c.Location,
+			&c.Available, &c.ReorderLevel, &c.SafetyStock, &c.Outbound, &c.OnOrder,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan reorder candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reorder candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// loadLines reads the lines of the given purchase orders with one query
+func (r *PostgresPurchaseOrderRepository) loadLines(ctx context.Context, orders []*domain.PurchaseOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*domain.PurchaseOrder, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		order.Lines = []domain.PurchaseOrderLine{}
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT l.order_id, l.line_no, l.product_id, l.inventory_id, COALESCE(l.location, ''), l.quantity,
+			l.confirmed_quantity, COALESCE(s.quantity, 0)
+		FROM purchase_order_lines l
+		LEFT JOIN (
+			SELECT order_id, line_no, SUM(quantity) AS quantity
+			FROM purchase_order_asn_lines WHERE order_id = ANY($1)
+			GROUP BY order_id, line_no
+		) s ON s.order_id = l.order_id AND s.line_no = l.line_no
+		WHERE l.order_id = ANY($1) ORDER BY l.order_id, l.line_no
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get purchase order lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var line domain.PurchaseOrderLine
+		var confirmed sql.NullInt64
+		if err := rows.Scan(
+			&orderID, &line.Line, &line.ProductID, &line.InventoryID, &line.Location, &line.Quantity,
+			&confirmed, &line.ShippedQuantity,
+		); err != nil {
+			return fmt.Errorf("failed to scan purchase order line: %w", err)
+		}
+		if confirmed.Valid {
+			line.ConfirmedQuantity = &confirmed.Int64
+		}
+		byID[orderID].Lines = append(byID[orderID].Lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating purchase order lines: %w", err)
+	}
+
+	return nil
+}
+
+// scanPurchaseOrder scans a purchase order row selected with purchaseOrderColumns
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (*domain.PurchaseOrder, error) {
+	order := &domain.PurchaseOrder{}
+	var receivedAt, confirmedAt, promisedAt sql.NullTime
+	if err := row.Scan(
+		&order.ID, &order.Supplier, &order.Notes, &order.Status, &order.CreatedAt, &order.UpdatedAt, &receivedAt,
+		&confirmedAt, &promisedAt,
+	); err != nil {
+		return nil, err
+	}
+	if receivedAt.Valid {
+		order.ReceivedAt = &receivedAt.Time
+	}
+	if confirmedAt.Valid {
+		order.ConfirmedAt = &confirmedAt.Time
+	}
+	if promisedAt.Valid {
+		order.PromisedAt = &promisedAt.Time
+	}
+	return order, nil
+}
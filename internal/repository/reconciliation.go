@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresReconciliationRepository implements ReconciliationRepository using
+// PostgreSQL
+type PostgresReconciliationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReconciliationRepository creates a new
+// PostgresReconciliationRepository
+func NewPostgresReconciliationRepository(db *sql.DB) *PostgresReconciliationRepository {
+	return &PostgresReconciliationRepository{db: db}
+}
+
+// FindDiscrepancies replays the transaction log, live and archived, of every
+// inventory row of all tenants, or only of the given rows, and returns at
+// most limit rows whose stored quantity or reserved units differ from it,
+// with the number found. The rows and their log are read in one statement,
+// so movements committed meanwhile are either counted on both sides or on
+// neither.
+func (r *PostgresReconciliationRepository) FindDiscrepancies(ctx context.Context, inventoryIDs []string, limit int) ([]*domain.ReconciliationDiscrepancy, int, error) {
+	var ids any
+	if inventoryIDs != nil {
+		ids = pq.Array(inventoryIDs)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		WITH expected AS (
+			SELECT t.inventory_id,
+				SUM(`+transactionQuantityDelta+`) AS quantity,
+				SUM(`+transactionReservedDelta+`) AS reserved
+			FROM transactions_all t
+			WHERE $1::text[] IS NULL OR t.inventory_id = ANY($1)
+			GROUP BY t.inventory_id
+		)
+		SELECT i.id, i.product_id, i.location, i.condition, i.quantity, COALESCE(e.quantity, 0),
+			i.reserved, COALESCE(e.reserved, 0), COUNT(*) OVER ()
+		FROM inventory i
+		LEFT JOIN expected e ON e.inventory_id = i.id
+		WHERE ($1::text[] IS NULL OR i.id = ANY($1))
+			AND (i.quantity <> COALESCE(e.quantity, 0) OR i.reserved <> COALESCE(e.reserved, 0))
+		ORDER BY i.id
+		LIMIT $2
+	`, ids, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to reconcile stock: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		discrepancies []*domain.ReconciliationDiscrepancy
+		total         int
+	)
+	for rows.Next() {
+		d := &domain.ReconciliationDiscrepancy{}
+		if err := rows.Scan(&d.InventoryID, &d.ProductID, &d.Location, &d.Condition, &d.Quantity, &d.ExpectedQuantity,
+			&d.Reserved, &d.ExpectedReserved, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan discrepancy: %w", err)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	return discrepancies, total, rows.Err()
+}
+
+// CreateReport records a reconciliation report with its discrepancies, each
+// owned by the tenant of its inventory row
+func (r *PostgresReconciliationRepository) CreateReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	report.ID = uuid.New().String()
+
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO reconciliation_reports (id, started_at, completed_at, discrepancy_count)
+			VALUES ($1, $2, $3, $4)
+		`, report.ID, report.StartedAt, report.CompletedAt, report.DiscrepancyCount)
+		if err != nil {
+			return fmt.Errorf("failed to create reconciliation report: %w", err)
+		}
+
+		for _, d := range report.Discrepancies {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO reconciliation_discrepancies (report_id, inventory_id, product_id, tenant_id, location, condition,
+					quantity, expected_quantity, reserved, expected_reserved)
+				SELECT $1, $2, $3, COALESCE((SELECT tenant_id FROM inventory WHERE id = $2), 'default'), $4, $5, $6, $7, $8, $9
+			`, report.ID, d.InventoryID, d.ProductID, d.Location, d.Condition, d.Quantity, d.ExpectedQuantity,
+				d.Reserved, d.ExpectedReserved)
+			if err != nil {
+				return fmt.Errorf("failed to record discrepancy: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// LatestReport retrieves the latest reconciliation report with the
+// discrepancies of the tenant in scope. Within a tenant the discrepancy count
+// is that of its listed discrepancies. It returns ErrNotFound before the
+// first run.
+func (r *PostgresReconciliationRepository) LatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	report := &domain.ReconciliationReport{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, started_at, completed_at, discrepancy_count
+		FROM reconciliation_reports
+		ORDER BY completed_at DESC
+		LIMIT 1
+	`).Scan(&report.ID, &report.StartedAt, &report.CompletedAt, &report.DiscrepancyCount)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reconciliation report %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation report: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT inventory_id, product_id, location, condition, quantity, expected_quantity, reserved, expected_reserved
+		FROM reconciliation_discrepancies
+		WHERE report_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY inventory_id
+	`, report.ID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	report.Discrepancies = []*domain.ReconciliationDiscrepancy{}
+	for rows.Next() {
+		d := &domain.ReconciliationDiscrepancy{}
+		if err := rows.Scan(&d.InventoryID, &d.ProductID, &d.Location, &d.Condition, &d.Quantity, &d.ExpectedQuantity,
+			&d.Reserved, &d.ExpectedReserved); err != nil {
+			return nil, fmt.Errorf("failed to scan discrepancy: %w", err)
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating discrepancies: %w", err)
+	}
+
+	if tenantScope(ctx) != "" {
+		report.DiscrepancyCount = len(report.Discrepancies)
+	}
+	return report, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresRedactionRepository implements RedactionRepository using PostgreSQL
+type PostgresRedactionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRedactionRepository creates a new PostgresRedactionRepository
+func NewPostgresRedactionRepository(db *sql.DB) *PostgresRedactionRepository {
+	return &PostgresRedactionRepository{db: db}
+}
+
+// Redact replaces every occurrence of identifier in the references and notes
+// of live and archived transactions, in their annotations and in sampled
+// payloads with the redaction token and records the redaction, in one
+// transaction.
+// Quantities, types and timestamps are left untouched. Only the transactions of
+// the tenant of ctx are redacted when it is scoped; payload samples carry no
+// tenant, so every sample naming the identifier is.
+func (r *PostgresRedactionRepository) Redact(ctx context.Context, identifier string, redaction *domain.Redaction) error {
+	if err := redaction.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE transactions
+		SET reference = REPLACE(reference, $1, $2), notes = REPLACE(notes, $1, $2)
+		WHERE (STRPOS(reference, $1) > 0 OR STRPOS(notes, $1) > 0) AND ($3 = '' OR tenant_id = $3)
+	`, identifier, redaction.Token, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to redact transactions: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE transactions_archive
+		SET reference = REPLACE(reference, $1, $2), notes = REPLACE(notes, $1, $2)
+		WHERE (STRPOS(reference, $1) > 0 OR STRPOS(notes, $1) > 0) AND ($3 = '' OR tenant_id = $3)
+	`, identifier, redaction.Token, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to redact archived transactions: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	affected += archived
+
+	// Annotations are immutable except for erasure
+	_, err = tx.ExecContext(ctx, `
+		UPDATE transaction_annotations
+		SET note = REPLACE(note, $1, $2)
+		WHERE STRPOS(note, $1) > 0 AND ($3 = '' OR tenant_id = $3)
+	`, identifier, redaction.Token, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to redact transaction annotations: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE payload_samples
+		SET path = REPLACE(path, $1, $2), query = REPLACE(query, $1, $2),
+			request_body = REPLACE(request_body, $1, $2), response_body = REPLACE(response_body, $1, $2)
+		WHERE STRPOS(path, $1) > 0 OR STRPOS(query, $1) > 0 OR STRPOS(request_body, $1) > 0 OR STRPOS(response_body, $1) > 0
+	`, identifier, redaction.Token)
+	if err != nil {
+		return fmt.Errorf("failed to redact payload samples: %w", err)
+	}
+
+	redaction.ID = uuid.New().String()
+	redaction.TransactionsAffected = affected
+	redaction.CreatedAt = time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO redactions (id, token, reason, transactions_affected, created_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, redaction.ID, redaction.Token, redaction.Reason, redaction.TransactionsAffected, redaction.CreatedAt, tenantOwner(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to record redaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves a paginated list of the redactions of the tenant of ctx,
+// newest first
+func (r *PostgresRedactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Redaction, error) {
+	query := `
+		SELECT id, token, reason, transactions_affected, created_at
+		FROM redactions
+		WHERE ($3 = '' OR tenant_id = $3)
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redactions: %w", err)
+	}
+	defer rows.Close()
+
+	var redactions []*domain.Redaction
+	for rows.Next() {
+		redaction := &domain.Redaction{}
+		if err := rows.Scan(
+			&redaction.ID, &redaction.Token, &redaction.Reason,
+			&redaction.TransactionsAffected, &redaction.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan redaction: %w", err)
+		}
+		redactions = append(redactions, redaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating redactions: %w", err)
+	}
+
+	return redactions, nil
+}
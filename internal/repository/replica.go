@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// ReadReplica is a read-only connection pool to a streaming replica of the
+// primary database. Repositories given a replica run their lookups and
+// listings against it while it is healthy and fall back to the primary while
+// it is not. Reads inside a unit of work always go to the primary, and so do
+// reads that must see a write the replica has not replayed yet.
+type ReadReplica struct {
+	conn    *sql.DB
+	healthy atomic.Bool
+	// replayed is the latest LSN the replica was seen to have replayed
+	replayed atomic.Uint64
+}
+
+// NewReadReplica opens the replica pool. Unlike the primary, a replica that
+// cannot be reached yet does not stop the server: reads stay on the primary
+// until a health check succeeds.
+func NewReadReplica(dsn string, pool PoolConfig) (*ReadReplica, error) {
+	conn, err := openTraced(dsn, "replica")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read replica: %w", err)
+	}
+
+	conn.SetMaxOpenConns(pool.MaxOpenConns)
+	conn.SetMaxIdleConns(pool.MaxIdleConns)
+	conn.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	replica := &ReadReplica{conn: conn}
+	replica.check(context.Background())
+	return replica, nil
+}
+
+// Healthy reports whether reads currently go to the replica
+func (r *ReadReplica) Healthy() bool {
+	return r != nil && r.healthy.Load()
+}
+
+// Ping checks that the replica can be reached
+func (r *ReadReplica) Ping(ctx context.Context) error {
+	return r.conn.PingContext(ctx)
+}
+
+// Run checks the replica every interval until ctx is cancelled, moving reads
+// back to the primary as soon as a check fails
+func (r *ReadReplica) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
+
+// check pings the replica and logs when its health changes
+func (r *ReadReplica) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	err := r.conn.PingContext(pingCtx)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	healthy := err == nil
+	if r.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		slog.InfoContext(ctx, "read replica available, routing reads to it")
+	} else {
+		slog.WarnContext(ctx, "read replica unavailable, routing reads to the primary", "error", err)
+	}
+}
+
+// caughtUp reports whether the replica has replayed the log up to lsn. The
+// replayed position is only queried while the last one seen is behind lsn;
+// a replica whose position cannot be read is taken as behind.
+func (r *ReadReplica) caughtUp(ctx context.Context, lsn LSN) bool {
+	if LSN(r.replayed.Load()) >= lsn {
+		return true
+	}
+
+	var value sql.NullString
+	if err := r.conn.QueryRowContext(ctx, "SELECT pg_last_wal_replay_lsn()::text").Scan(&value); err != nil {
+		return false
+	}
+	if !value.Valid {
+		// Not in recovery: the "replica" is a primary and sees every write
+		return true
+	}
+	replayed, err := ParseLSN(value.String)
+	if err != nil {
+		return false
+	}
+	for {
+		seen := r.replayed.Load()
+		if uint64(replayed) <= seen || r.replayed.CompareAndSwap(seen, uint64(replayed)) {
+			break
+		}
+	}
+	return replayed >= lsn
+}
+
+// Close closes the replica pool
+func (r *ReadReplica) Close() error {
+	return r.conn.Close()
+}
+
+// reader returns what a read-only query of ctx runs against: the transaction
+// of its unit of work, the replica while it is healthy and has replayed the
+// writes ctx must read after, or the primary
+func reader(ctx context.Context, primary *sql.DB, replica *ReadReplica) dbtx {
+	if InTransaction(ctx) || !replica.Healthy() {
+		return conn(ctx, primary)
+	}
+	if lsn, ok := ReadAfter(ctx); ok && !replica.caughtUp(ctx, lsn) {
+		return primary
+	}
+	return replica.conn
+}
@@ -0,0 +1,549 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// stockSummaryDimensions maps the supported group_by names to SQL expressions
+var stockSummaryDimensions = map[string]string{
+	"category": "COALESCE(p.category, '')",
+	"location": "i.location",
+}
+
+// PostgresReportRepository implements ReportRepository using PostgreSQL
+type PostgresReportRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReportRepository creates a new PostgresReportRepository
+func NewPostgresReportRepository(db *sql.DB) *PostgresReportRepository {
+	return &PostgresReportRepository{db: db}
+}
+
+// StockSummary aggregates on-hand, reserved and value per group in a single
+// query. groupBy must only contain supported dimensions; an empty groupBy
+// returns one overall row.
+func (r *PostgresReportRepository) StockSummary(ctx context.Context, groupBy []string) ([]*domain.StockSummary, error) {
+	columns := make([]string, 0, len(groupBy))
+	for _, dimension := range groupBy {
+		expr, ok := stockSummaryDimensions[dimension]
+		if !ok {
+			return nil, fmt.Errorf("unsupported group_by dimension: %s", dimension)
+		}
+		columns = append(columns, expr)
+	}
+
+	selectColumns := ""
+	groupClause := ""
+	if len(columns) > 0 {
+		selectColumns = strings.Join(columns, ", ") + ", "
+		groupClause = "GROUP BY " + strings.Join(columns, ", ") + " ORDER BY " + strings.Join(columns, ", ")
+	}
+
+	query := `
+		SELECT ` + selectColumns + `
+			COUNT(DISTINCT p.id),
+			COALESCE(SUM(i.quantity), 0),
+			COALESCE(SUM(i.reserved), 0),
+			COALESCE(SUM(i.quantity * p.price), 0)
+		FROM inventory i
+		JOIN products p ON p.id = i.product_id
+		WHERE $1 = '' OR p.tenant_id = $1
+		` + groupClause
+
+	rows, err := r.db.QueryContext(ctx, query, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize stock: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*domain.StockSummary
+	for rows.Next() {
+		summary := &domain.StockSummary{Group: make(map[string]string, len(groupBy))}
+		groupValues := make([]string, len(groupBy))
+
+		dest := make([]interface{}, 0, len(groupBy)+4)
+		for i := range groupValues {
+			dest = append(dest, &groupValues[i])
+		}
+		dest = append(dest, &summary.ProductCount, &summary.OnHand, &summary.Reserved, &summary.Value)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan stock summary: %w", err)
+		}
+
+		for i, dimension := range groupBy {
+			summary.Group[dimension] = groupValues[i]
+		}
+		summary.Available = summary.OnHand - summary.Reserved
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock summary: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// movementGranularities lists the supported date_trunc units
+var movementGranularities = map[string]bool{"day": true, "week": true, "month": true}
+
+// MovementSummary totals quantities and counts per transaction type and
+// period. Stored timestamps are UTC; buckets follow the query location.
+func (r *PostgresReportRepository) MovementSummary(ctx context.Context, q MovementQuery) ([]*domain.MovementSummary, error) {
+	if !movementGranularities[q.Granularity] {
+		return nil, fmt.Errorf("unsupported granularity: %s", q.Granularity)
+	}
+
+	loc := q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	productColumn := "''"
+	if q.GroupByProduct {
+		productColumn = "product_id"
+	}
+
+	query := `
+		SELECT
+			date_trunc('` + q.Granularity + `', (created_at AT TIME ZONE 'UTC') AT TIME ZONE $3) AS period,
+			` + productColumn + ` AS product,
+			type,
+			SUM(quantity),
+			COUNT(*)
+		FROM transactions_all
+		WHERE created_at >= $1 AND created_at < $2 AND ($4 = '' OR product_id = $4) AND ($5 = '' OR tenant_id = $5)
+		GROUP BY period, product, type
+		ORDER BY period, product, type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, q.From.UTC(), q.To.UTC(), loc.String(), q.ProductID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize movements: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*domain.MovementSummary
+	var current *domain.MovementSummary
+	for rows.Next() {
+		var period time.Time
+		var productID, txType string
+		var totals domain.MovementTotals
+		if err := rows.Scan(&period, &productID, &txType, &totals.Quantity, &totals.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan movement summary: %w", err)
+		}
+
+		label := period.Format("2006-01-02")
+		if current == nil || current.Period != label || current.ProductID != productID {
+			current = &domain.MovementSummary{
+				Period:    label,
+				ProductID: productID,
+				Movements: make(map[string]domain.MovementTotals),
+			}
+			summaries = append(summaries, current)
+		}
+		current.Movements[txType] = totals
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating movement summary: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// InventoryKPIs computes the product count, the total reserved units and the
+// number of products whose available quantity, summed over all locations, is
+// below lowStockThreshold. Products without inventory count as low on stock;
+// archived products are not counted.
+func (r *PostgresReportRepository) InventoryKPIs(ctx context.Context, lowStockThreshold int64) (*domain.InventoryKPIs, error) {
+	query := `
+		WITH per_product AS (
+			SELECT p.id,
+				COALESCE(SUM(i.reserved), 0) AS reserved,
+				COALESCE(SUM(i.quantity - i.reserved), 0) AS available
+			FROM products p
+			LEFT JOIN inventory i ON i.product_id = p.id
+			WHERE p.deleted_at IS NULL AND ($2 = '' OR p.tenant_id = $2)
+			GROUP BY p.id
+		)
+		SELECT COUNT(*), COALESCE(SUM(reserved), 0), COUNT(*) FILTER (WHERE available < $1)
+		FROM per_product
+	`
+
+	kpis := &domain.InventoryKPIs{}
+	if err := r.db.QueryRowContext(ctx, query, lowStockThreshold, tenantScope(ctx)).Scan(&kpis.Products, &kpis.ReservedUnits, &kpis.LowStockProducts); err != nil {
+		return nil, fmt.Errorf("failed to compute inventory KPIs: %w", err)
+	}
+
+	return kpis, nil
+}
+
+// DashboardStats computes the dashboard figures in one round trip: the stock
+// totals from the inventory of every product that is not archived, and the
+// number and units of the transactions in the 24 hours and 7 days before now
+func (r *PostgresReportRepository) DashboardStats(ctx context.Context, lowStockThreshold int64, now time.Time) (*domain.DashboardStats, error) {
+	query := `
+		WITH per_product AS (
+			SELECT p.id,
+				COALESCE(SUM(i.quantity), 0) AS quantity,
+				COALESCE(SUM(i.reserved), 0) AS reserved,
+				COALESCE(SUM(i.quantity - i.reserved), 0) AS available
+			FROM products p
+			LEFT JOIN inventory i ON i.product_id = p.id
+			WHERE p.deleted_at IS NULL AND ($2 = '' OR p.tenant_id = $2)
+			GROUP BY p.id
+		), stock AS (
+			SELECT COUNT(*) AS products,
+				COALESCE(SUM(quantity), 0) AS quantity,
+				COALESCE(SUM(reserved), 0) AS reserved,
+				COUNT(*) FILTER (WHERE available <= 0) AS out_of_stock,
+				COUNT(*) FILTER (WHERE available > 0 AND available < $1) AS low_stock
+			FROM per_product
+		), movements AS (
+			SELECT COUNT(*) FILTER (WHERE created_at >= $3) AS day_count,
+				COALESCE(SUM(quantity) FILTER (WHERE created_at >= $3), 0) AS day_quantity,
+				COUNT(*) AS week_count,
+				COALESCE(SUM(quantity), 0) AS week_quantity
+			FROM transactions_all
+			WHERE created_at >= $4 AND ($2 = '' OR tenant_id = $2)
+		)
+		SELECT stock.products, stock.quantity, stock.reserved, stock.out_of_stock, stock.low_stock,
+			movements.day_count, movements.day_quantity, movements.week_count, movements.week_quantity
+		FROM stock CROSS JOIN movements
+	`
+
+	stats := &domain.DashboardStats{LowStockThreshold: lowStockThreshold, GeneratedAt: now}
+	err := r.db.QueryRowContext(ctx, query, lowStockThreshold, tenantScope(ctx), now.Add(-24*time.Hour), now.AddDate(0, 0, -7)).Scan(
+		&stats.TotalProducts, &stats.TotalUnits, &stats.TotalReserved, &stats.OutOfStock, &stats.LowStock,
+		&stats.Transactions24h.Count, &stats.Transactions24h.Quantity,
+		&stats.Transactions7d.Count, &stats.Transactions7d.Quantity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute dashboard stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// StockVelocities returns, for every product that sold since the given time
+// and is not archived, its available stock over all locations and the units
+// sold since then
+func (r *PostgresReportRepository) StockVelocities(ctx context.Context, since time.Time) ([]*domain.StockVelocity, error) {
+	query := `
+		WITH outbound AS (
+			SELECT product_id, SUM(quantity) AS quantity
+			FROM transactions_all
+			WHERE type = 'OUT' AND created_at >= $1 AND ($2 = '' OR tenant_id = $2)
+			GROUP BY product_id
+		)
+		SELECT p.id, p.sku, p.name,
+			COALESCE((SELECT SUM(i.quantity - i.reserved) FROM inventory i WHERE i.product_id = p.id), 0),
+			o.quantity
+		FROM outbound o
+		JOIN products p ON p.id = o.product_id
+		WHERE p.deleted_at IS NULL
+		ORDER BY p.sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since.UTC(), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute stock velocities: %w", err)
+	}
+	defer rows.Close()
+
+	var velocities []*domain.StockVelocity
+	for rows.Next() {
+		velocity := &domain.StockVelocity{}
+		if err := rows.Scan(&velocity.ProductID, &velocity.SKU, &velocity.Name, &velocity.Available, &velocity.Outbound); err != nil {
+			return nil, fmt.Errorf("failed to scan stock velocity: %w", err)
+		}
+		velocities = append(velocities, velocity)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock velocities: %w", err)
+	}
+
+	return velocities, nil
+}
+
+// OpenReservationHolds sums the pending reservations per reference, oldest
+// reservation first
+func (r *PostgresReportRepository) OpenReservationHolds(ctx context.Context) ([]*domain.ReservationHold, error) {
+	query := `
+		SELECT reference, COUNT(*), SUM(quantity), MIN(created_at)
+		FROM reservations
+		WHERE status = $1 AND ($2 = '' OR tenant_id = $2)
+		GROUP BY reference
+		ORDER BY MIN(created_at), reference
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.ReservationStatusPending, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []*domain.ReservationHold
+	for rows.Next() {
+		hold := &domain.ReservationHold{}
+		if err := rows.Scan(&hold.Reference, &hold.Reservations, &hold.Quantity, &hold.OldestAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservation holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+// CaptureStockSnapshots records the current on-hand and available stock of
+// every active product as its snapshot for day, replacing an earlier capture
+// of the same day, and returns the number of products captured
+func (r *PostgresReportRepository) CaptureStockSnapshots(ctx context.Context, day time.Time) (int64, error) {
+	query := `
+		INSERT INTO stock_snapshots (product_id, day, on_hand, available, captured_at)
+		SELECT p.id, $1, COALESCE(SUM(i.quantity), 0), COALESCE(SUM(i.quantity - i.reserved), 0), $2
+		FROM products p
+		LEFT JOIN inventory i ON i.product_id = p.id
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.id
+		ON CONFLICT (product_id, day) DO UPDATE
+		SET on_hand = EXCLUDED.on_hand, available = EXCLUDED.available, captured_at = EXCLUDED.captured_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query, day, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture stock snapshots: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// StockSnapshots retrieves the snapshots of a product from day from to day to,
+// oldest first, preceded by the latest snapshot before from if there is one
+func (r *PostgresReportRepository) StockSnapshots(ctx context.Context, productID string, from, to time.Time) ([]*domain.StockSnapshot, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND ($2 = '' OR tenant_id = $2))`,
+		productID, tenantScope(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	query := `
+		SELECT product_id, day, on_hand, available
+		FROM stock_snapshots
+		WHERE product_id = $1 AND day <= $3
+			AND day >= COALESCE((SELECT MAX(day) FROM stock_snapshots WHERE product_id = $1 AND day <= $2), $2)
+		ORDER BY day
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.StockSnapshot
+	for rows.Next() {
+		snapshot := &domain.StockSnapshot{}
+		if err := rows.Scan(&snapshot.ProductID, &snapshot.Day, &snapshot.OnHand, &snapshot.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan stock snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// transactionQuantityDelta and transactionReservedDelta are the changes a
+// transaction made to the quantity and reserved units of its inventory row.
+// Adjustments record their direction in the quantities before and after.
+const (
+	transactionQuantityDelta = `CASE t.type
+		WHEN 'IN' THEN t.quantity WHEN 'RETURN' THEN t.quantity WHEN 'TRANSFER_IN' THEN t.quantity
+		WHEN 'OUT' THEN -t.quantity WHEN 'TRANSFER_OUT' THEN -t.quantity WHEN 'EXPIRED' THEN -t.quantity
+		WHEN 'ADJUSTMENT' THEN COALESCE(t.quantity_after - t.quantity_before, 0)
+		ELSE 0 END`
+	transactionReservedDelta = `CASE t.type
+		WHEN 'RESERVE' THEN t.quantity WHEN 'UNRESERVE' THEN -t.quantity
+		ELSE 0 END`
+)
+
+// StockAsOf reconstructs the stock of every product that existed at asOf,
+// ordered by SKU. A product's stock starts from its latest snapshot captured
+// by then, onto which the transactions recorded after the capture and before
+// asOf are replayed. Products without such a snapshot start from their
+// current stock instead, from which the transactions since asOf are rewound.
+func (r *PostgresReportRepository) StockAsOf(ctx context.Context, asOf time.Time) ([]*domain.HistoricStock, error) {
+	query := `
+		WITH baseline AS (
+			SELECT DISTINCT ON (product_id) product_id, on_hand, on_hand - available AS reserved, captured_at
+			FROM stock_snapshots
+			WHERE captured_at <= $1
+			ORDER BY product_id, captured_at DESC
+		), replayed AS (
+			SELECT t.product_id,
+				SUM(` + transactionQuantityDelta + `) AS quantity,
+				SUM(` + transactionReservedDelta + `) AS reserved
+			FROM transactions_all t
+			JOIN baseline b ON b.product_id = t.product_id
+			WHERE t.created_at > b.captured_at AND t.created_at < $1
+			GROUP BY t.product_id
+		), current_stock AS (
+			SELECT product_id, SUM(quantity) AS quantity, SUM(reserved) AS reserved
+			FROM inventory
+			GROUP BY product_id
+		), rewound AS (
+			SELECT t.product_id,
+				SUM(` + transactionQuantityDelta + `) AS quantity,
+				SUM(` + transactionReservedDelta + `) AS reserved
+			FROM transactions_all t
+			WHERE t.created_at >= $1 AND NOT EXISTS (SELECT 1 FROM baseline b WHERE b.product_id = t.product_id)
+			GROUP BY t.product_id
+		)
+		SELECT p.id, p.sku, p.name,
+			CASE WHEN b.product_id IS NOT NULL THEN b.on_hand + COALESCE(rp.quantity, 0)
+				ELSE COALESCE(c.quantity, 0) - COALESCE(rw.quantity, 0) END,
+			CASE WHEN b.product_id IS NOT NULL THEN b.reserved + COALESCE(rp.reserved, 0)
+				ELSE COALESCE(c.reserved, 0) - COALESCE(rw.reserved, 0) END,
+			b.captured_at
+		FROM products p
+		LEFT JOIN baseline b ON b.product_id = p.id
+		LEFT JOIN replayed rp ON rp.product_id = p.id
+		LEFT JOIN current_stock c ON c.product_id = p.id
+		LEFT JOIN rewound rw ON rw.product_id = p.id
+		WHERE p.created_at < $1 AND (p.deleted_at IS NULL OR p.deleted_at >= $1)
+			AND ($2 = '' OR p.tenant_id = $2)
+		ORDER BY p.sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, asOf.UTC(), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct stock: %w", err)
+	}
+	defer rows.Close()
+
+	var stock []*domain.HistoricStock
+	for rows.Next() {
+		s := &domain.HistoricStock{}
+		var snapshotAt sql.NullTime
+		if err := rows.Scan(&s.ProductID, &s.SKU, &s.Name, &s.Quantity, &s.Reserved, &snapshotAt); err != nil {
+			return nil, fmt.Errorf("failed to scan historic stock: %w", err)
+		}
+		s.Available = s.Quantity - s.Reserved
+		if snapshotAt.Valid {
+			s.SnapshotAt = &snapshotAt.Time
+		}
+		stock = append(stock, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating historic stock: %w", err)
+	}
+
+	return stock, nil
+}
+
+// ValuationInputs retrieves what the stock of every active product, or only
+// the given one, is valued from, ordered by SKU: its quantity on hand over
+// all locations, the totals of its costed receipts, live and archived, and
+// its latest costed receipts as far as they cover the quantity on hand
+func (r *PostgresReportRepository) ValuationInputs(ctx context.Context, productID string) ([]*domain.ValuationInput, error) {
+	query := `
+		WITH stock AS (
+			SELECT product_id, SUM(quantity) AS quantity FROM inventory GROUP BY product_id
+		), received AS (
+			SELECT product_id, SUM(quantity) AS units, SUM(quantity * unit_cost) AS cost
+			FROM transactions_all
+			WHERE type IN ('IN', 'RETURN') AND unit_cost IS NOT NULL
+			GROUP BY product_id
+		)
+		SELECT p.id, p.sku, p.name, COALESCE(s.quantity, 0), COALESCE(rc.units, 0), COALESCE(rc.cost, 0)
+		FROM products p
+		LEFT JOIN stock s ON s.product_id = p.id
+		LEFT JOIN received rc ON rc.product_id = p.id
+		WHERE p.deleted_at IS NULL AND ($1 = '' OR p.id = $1) AND ($2 = '' OR p.tenant_id = $2)
+		ORDER BY p.sku
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stock valuation: %w", err)
+	}
+	defer rows.Close()
+
+	var inputs []*domain.ValuationInput
+	byProduct := make(map[string]*domain.ValuationInput)
+	for rows.Next() {
+		in := &domain.ValuationInput{}
+		if err := rows.Scan(&in.ProductID, &in.SKU, &in.Name, &in.Quantity, &in.ReceivedUnits, &in.ReceivedCost); err != nil {
+			return nil, fmt.Errorf("failed to scan stock valuation: %w", err)
+		}
+		inputs = append(inputs, in)
+		byProduct[in.ProductID] = in
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock valuation: %w", err)
+	}
+
+	// Each receipt's newer units are those received after it; a receipt is
+	// part of the stock on hand while they fall short of it
+	layerQuery := `
+		WITH stock AS (
+			SELECT product_id, SUM(quantity) AS quantity FROM inventory GROUP BY product_id
+		), layers AS (
+			SELECT t.product_id, t.quantity, t.unit_cost,
+				SUM(t.quantity) OVER (PARTITION BY t.product_id ORDER BY t.created_at DESC, t.id DESC) - t.quantity AS newer
+			FROM transactions_all t
+			JOIN products p ON p.id = t.product_id
+			WHERE t.type IN ('IN', 'RETURN') AND t.unit_cost IS NOT NULL AND p.deleted_at IS NULL
+				AND ($1 = '' OR t.product_id = $1) AND ($2 = '' OR p.tenant_id = $2)
+		)
+		SELECT l.product_id, l.quantity, l.unit_cost
+		FROM layers l
+		JOIN stock s ON s.product_id = l.product_id
+		WHERE l.newer < s.quantity
+		ORDER BY l.product_id, l.newer
+	`
+
+	layerRows, err := r.db.QueryContext(ctx, layerQuery, productID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cost layers: %w", err)
+	}
+	defer layerRows.Close()
+
+	for layerRows.Next() {
+		var id string
+		var layer domain.CostLayer
+		if err := layerRows.Scan(&id, &layer.Quantity, &layer.UnitCost); err != nil {
+			return nil, fmt.Errorf("failed to scan cost layer: %w", err)
+		}
+		if in, ok := byProduct[id]; ok {
+			in.Layers = append(in.Layers, layer)
+		}
+	}
+	if err = layerRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cost layers: %w", err)
+	}
+
+	return inputs, nil
+}
@@ -0,0 +1,608 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// testRepositories are the repositories of one database driver, exercised
+// by the same tests so that the drivers cannot drift apart
+type testRepositories struct {
+	product     ProductRepository
+	inventory   InventoryRepository
+	transaction TransactionRepository
+	reservation ReservationRepository
+	serial      SerialUnitRepository
+	order       OrderRepository
+	cartHold    CartHoldRepository
+	manifest    AuditManifestRepository
+	redaction   RedactionRepository
+	sample      PayloadSampleRepository
+	report      ReportRepository
+	transactor  Transactor
+}
+
+var (
+	postgresOnce sync.Once
+	postgresDB   *Database
+	postgresErr  error
+)
+
+// openTestPostgres connects to DATABASE_URL and applies the pending
+// migrations, once per test binary
+func openTestPostgres() (*Database, error) {
+	postgresOnce.Do(func() {
+		postgresDB, postgresErr = NewDatabase(os.Getenv("DATABASE_URL"), PoolConfig{})
+		if postgresErr != nil {
+			return
+		}
+		migrator, err := postgresDB.Migrator()
+		if err != nil {
+			postgresErr = err
+			return
+		}
+		_, postgresErr = migrator.Up(context.Background(), 0)
+	})
+	return postgresDB, postgresErr
+}
+
+// forEachDriver runs test against a fresh memory store and, when
+// DATABASE_URL is set, against PostgreSQL. The PostgreSQL database is shared
+// between tests, so they must create rows with unique SKUs and references.
+func forEachDriver(t *testing.T, test func(t *testing.T, repos testRepositories)) {
+	t.Run("memory", func(t *testing.T) {
+		store, err := OpenMemoryStore("")
+		if err != nil {
+			t.Fatalf("Failed to open memory store: %v", err)
+		}
+		test(t, testRepositories{
+			product:     NewMemoryProductRepository(store),
+			inventory:   NewMemoryInventoryRepository(store),
+			transaction: NewMemoryTransactionRepository(store),
+			reservation: NewMemoryReservationRepository(store),
+			serial:      NewMemorySerialUnitRepository(store),
+			order:       NewMemoryOrderRepository(store),
+			cartHold:    NewMemoryCartHoldRepository(store),
+			manifest:    NewMemoryAuditManifestRepository(store),
+			redaction:   NewMemoryRedactionRepository(store),
+			sample:      NewMemoryPayloadSampleRepository(store),
+			report:      NewMemoryReportRepository(store),
+			transactor:  store,
+		})
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		if os.Getenv("DATABASE_URL") == "" {
+			t.Skip("DATABASE_URL is not set")
+		}
+		db, err := openTestPostgres()
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		conn := db.GetConnection()
+		test(t, testRepositories{
+			product:     NewPostgresProductRepository(conn),
+			inventory:   NewPostgresInventoryRepository(conn),
+			transaction: NewPostgresTransactionRepository(conn),
+			reservation: NewPostgresReservationRepository(conn),
+			serial:      NewPostgresSerialUnitRepository(conn),
+			order:       NewPostgresOrderRepository(conn),
+			cartHold:    NewPostgresCartHoldRepository(conn),
+			manifest:    NewPostgresAuditManifestRepository(conn),
+			redaction:   NewPostgresRedactionRepository(conn),
+			sample:      NewPostgresPayloadSampleRepository(conn),
+			report:      NewPostgresReportRepository(conn),
+			transactor:  NewPostgresTransactor(conn),
+		})
+	})
+}
+
+// createStockedProduct creates a product with a unique SKU stocked with
+// quantity units at one location
+func createStockedProduct(t *testing.T, ctx context.Context, repos testRepositories, quantity int64) (*domain.Product, *domain.InventoryItem) {
+	t.Helper()
+
+	product := &domain.Product{Name: "Widget", SKU: "TEST-" + uuid.NewString(), Price: 9.99}
+	if err := repos.product.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	item := &domain.InventoryItem{ProductID: product.ID, Quantity: quantity, Location: "Warehouse A"}
+	if err := repos.inventory.Create(ctx, item); err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	return product, item
+}
+
+func TestProductRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		product, _ := createStockedProduct(t, ctx, repos, 0)
+
+		found, err := repos.product.GetBySKU(ctx, product.SKU)
+		if err != nil || found.ID != product.ID {
+			t.Fatalf("Expected to find the product by SKU, got %+v (%v)", found, err)
+		}
+
+		product.Name = "Gadget"
+		if err := repos.product.Update(ctx, product); err != nil {
+			t.Fatalf("Failed to update product: %v", err)
+		}
+		if found, _ := repos.product.GetByID(ctx, product.ID); found.Name != "Gadget" {
+			t.Errorf("Expected updated name Gadget, got %q", found.Name)
+		}
+
+		if err := repos.product.Create(ctx, &domain.Product{Name: "Copy", SKU: product.SKU}); err == nil {
+			t.Error("Expected a duplicate SKU to be rejected")
+		}
+
+		if err := repos.product.Delete(ctx, product.ID); err != nil {
+			t.Fatalf("Failed to delete product: %v", err)
+		}
+		if _, err := repos.product.GetByID(ctx, product.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after delete, got %v", err)
+		}
+	})
+}
+
+func TestInventoryRepositoryUpdateQuantity(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		_, item := createStockedProduct(t, ctx, repos, 10)
+
+		if err := repos.inventory.UpdateQuantity(ctx, item.ID, -4, 3); err != nil {
+			t.Fatalf("Failed to update quantity: %v", err)
+		}
+		if err := repos.inventory.UpdateQuantity(ctx, item.ID, -4, 0); err == nil {
+			t.Error("Expected removing reserved units to be rejected")
+		}
+		if err := repos.inventory.UpdateQuantity(ctx, item.ID, 0, 4); err == nil {
+			t.Error("Expected reserving more than the quantity to be rejected")
+		}
+
+		found, err := repos.inventory.GetByID(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("Failed to get inventory: %v", err)
+		}
+		if found.Quantity != 6 || found.Reserved != 3 {
+			t.Errorf("Expected quantity 6 and 3 reserved, got %d and %d", found.Quantity, found.Reserved)
+		}
+	})
+}
+
+func TestTransactorRollsBack(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		product, item := createStockedProduct(t, ctx, repos, 10)
+
+		failure := errors.New("ledger unavailable")
+		err := repos.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := repos.inventory.UpdateQuantity(ctx, item.ID, -5, 0); err != nil {
+				return err
+			}
+			transaction := &domain.Transaction{InventoryID: item.ID, ProductID: product.ID, Type: "OUT", Quantity: 5, Reference: "ROLLBACK"}
+			if err := repos.transaction.Create(ctx, transaction); err != nil {
+				return err
+			}
+			return failure
+		})
+		if !errors.Is(err, failure) {
+			t.Fatalf("Expected the unit of work to fail, got %v", err)
+		}
+
+		if found, _ := repos.inventory.GetByID(ctx, item.ID); found.Quantity != 10 {
+			t.Errorf("Expected quantity 10 after the rollback, got %d", found.Quantity)
+		}
+		if transactions, _ := repos.transaction.GetByInventoryID(ctx, item.ID, 10, 0); len(transactions) != 0 {
+			t.Errorf("Expected no transactions after the rollback, got %d", len(transactions))
+		}
+	})
+}
+
+func TestTenantIsolationProductsAndInventory(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		product, item := createStockedProduct(t, acme, repos, 10)
+
+		if _, err := repos.product.GetByID(evil, product.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the product of another tenant to be hidden, got %v", err)
+		}
+		if products, _ := repos.product.List(evil, 100, 0, true, nil); len(products) != 0 {
+			t.Errorf("Expected no products listed for another tenant, got %d", len(products))
+		}
+		if _, err := repos.inventory.GetByID(evil, item.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the inventory of another tenant to be hidden, got %v", err)
+		}
+		if err := repos.inventory.UpdateQuantity(evil, item.ID, -1, 0); err == nil {
+			t.Error("Expected the stock of another tenant not to change")
+		}
+		if found, err := repos.inventory.GetByID(acme, item.ID); err != nil || found.Quantity != 10 {
+			t.Errorf("Expected acme to keep 10 units, got %+v (%v)", found, err)
+		}
+	})
+}
+
+func TestInventoryRepositoryTransfer(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		product, from := createStockedProduct(t, ctx, repos, 10)
+		to := &domain.InventoryItem{ProductID: product.ID, Location: "Warehouse B"}
+		if err := repos.inventory.Create(ctx, to); err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+
+		transfer := func(quantity int64) error {
+			out := &domain.Transaction{InventoryID: from.ID, ProductID: product.ID, Type: "TRANSFER_OUT", Quantity: quantity, Reference: "MOVE"}
+			in := &domain.Transaction{InventoryID: to.ID, ProductID: product.ID, Type: "TRANSFER_IN", Quantity: quantity, Reference: "MOVE"}
+			return repos.inventory.Transfer(ctx, from.ID, to.ID, quantity, out, in)
+		}
+		if err := transfer(4); err != nil {
+			t.Fatalf("Failed to transfer: %v", err)
+		}
+		if err := transfer(7); !errors.Is(err, domain.ErrInsufficientStock) {
+			t.Errorf("Expected ErrInsufficientStock, got %v", err)
+		}
+
+		levels, err := repos.inventory.ListByProductID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("Failed to list inventory: %v", err)
+		}
+		quantities := make(map[string]int64)
+		for _, level := range levels {
+			quantities[level.Location] = level.Quantity
+		}
+		if quantities["Warehouse A"] != 6 || quantities["Warehouse B"] != 4 {
+			t.Errorf("Expected 6 units at A and 4 at B, got %v", quantities)
+		}
+		if transactions, _ := repos.transaction.GetByProductID(ctx, product.ID, 10, 0); len(transactions) != 2 {
+			t.Errorf("Expected the transfer to record 2 transactions, got %d", len(transactions))
+		}
+	})
+}
+
+func TestReservationRepositoryStatus(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		product, item := createStockedProduct(t, ctx, repos, 10)
+
+		expiresAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Microsecond)
+		reservation := &domain.Reservation{
+			ProductID: product.ID, InventoryID: item.ID, Location: item.Location, Quantity: 3,
+			Reference: "ORDER-" + uuid.NewString(), Status: domain.ReservationStatusPending, ExpiresAt: expiresAt,
+		}
+		if err := repos.reservation.Create(ctx, reservation); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+
+		expired, err := repos.reservation.ListExpired(ctx, time.Now(), 1000)
+		if err != nil {
+			t.Fatalf("Failed to list expired reservations: %v", err)
+		}
+		if !slices.ContainsFunc(expired, func(r *domain.Reservation) bool { return r.ID == reservation.ID }) {
+			t.Error("Expected the reservation to be listed as expired")
+		}
+
+		if ok, err := repos.reservation.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusPending, domain.ReservationStatusExpired); err != nil || !ok {
+			t.Fatalf("Expected the reservation to expire, got %v (%v)", ok, err)
+		}
+		if ok, _ := repos.reservation.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusPending, domain.ReservationStatusConfirmed); ok {
+			t.Error("Expected a transition from a stale status to be refused")
+		}
+
+		found, err := repos.reservation.GetByID(ctx, reservation.ID)
+		if err != nil {
+			t.Fatalf("Failed to get reservation: %v", err)
+		}
+		if found.Status != domain.ReservationStatusExpired || found.Quantity != 3 || !found.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Unexpected reservation: %+v", found)
+		}
+	})
+}
+
+// tenantContexts returns the contexts of two tenants unique to the test
+func tenantContexts() (acme, evil context.Context) {
+	acme = domain.WithTenantID(context.Background(), "acme-"+uuid.NewString())
+	evil = domain.WithTenantID(context.Background(), "evil-"+uuid.NewString())
+	return acme, evil
+}
+
+func TestTenantIsolationReservations(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		product, item := createStockedProduct(t, acme, repos, 10)
+
+		reservation := &domain.Reservation{
+			ProductID: product.ID, InventoryID: item.ID, Location: item.Location, Quantity: 2,
+			Reference: "ORDER-" + uuid.NewString(), Status: domain.ReservationStatusPending, ExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repos.reservation.Create(evil, reservation); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected reserving the stock of another tenant to fail, got %v", err)
+		}
+		if err := repos.reservation.Create(acme, reservation); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		if reservation.TenantID != domain.TenantIDFromContext(acme) {
+			t.Errorf("Expected the reservation to belong to acme, got %q", reservation.TenantID)
+		}
+
+		if _, err := repos.reservation.GetByID(evil, reservation.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the reservation of another tenant to be hidden, got %v", err)
+		}
+		if reservations, _ := repos.reservation.ListByProductID(evil, product.ID, 10, 0); len(reservations) != 0 {
+			t.Errorf("Expected no reservations listed for another tenant, got %d", len(reservations))
+		}
+		if ok, _ := repos.reservation.UpdateStatus(evil, reservation.ID, domain.ReservationStatusPending, domain.ReservationStatusReleased); ok {
+			t.Error("Expected the reservation of another tenant not to change")
+		}
+		if reservations, _ := repos.reservation.ListByProductID(acme, product.ID, 10, 0); len(reservations) != 1 {
+			t.Errorf("Expected acme to list its reservation, got %d", len(reservations))
+		}
+	})
+}
+
+func TestTenantIsolationSerialUnits(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		product, _ := createStockedProduct(t, acme, repos, 1)
+
+		if err := repos.serial.Create(evil, &domain.SerialUnit{ProductID: product.ID, SerialNumber: "SN-2", Status: domain.SerialStatusInStock}); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected registering a unit of another tenant's product to fail, got %v", err)
+		}
+		unit := &domain.SerialUnit{ProductID: product.ID, SerialNumber: "SN-1", Status: domain.SerialStatusInStock}
+		if err := repos.serial.Create(acme, unit); err != nil {
+			t.Fatalf("Failed to create serial unit: %v", err)
+		}
+
+		if _, err := repos.serial.GetBySerial(evil, product.ID, "SN-1"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the unit of another tenant to be hidden, got %v", err)
+		}
+		if units, _ := repos.serial.ListByProductID(evil, product.ID, 10, 0); len(units) != 0 {
+			t.Errorf("Expected no units listed for another tenant, got %d", len(units))
+		}
+		scrap := &domain.SerialUnitEvent{FromStatus: domain.SerialStatusInStock, ToStatus: domain.SerialStatusScrapped, Reference: "SCRAP"}
+		if err := repos.serial.UpdateStatus(evil, unit, scrap); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the unit of another tenant not to change, got %v", err)
+		}
+
+		ship := &domain.SerialUnitEvent{FromStatus: domain.SerialStatusInStock, ToStatus: domain.SerialStatusShipped, Reference: "SHIP"}
+		if err := repos.serial.UpdateStatus(acme, unit, ship); err != nil {
+			t.Fatalf("Failed to update serial unit: %v", err)
+		}
+		stale := &domain.SerialUnitEvent{FromStatus: domain.SerialStatusInStock, ToStatus: domain.SerialStatusReserved, Reference: "RESERVE"}
+		if err := repos.serial.UpdateStatus(acme, unit, stale); !errors.Is(err, domain.ErrConflict) {
+			t.Errorf("Expected a change from a stale status to conflict, got %v", err)
+		}
+		if events, _ := repos.serial.GetHistory(evil, unit.ID, 10, 0); len(events) != 0 {
+			t.Errorf("Expected no history for another tenant, got %d events", len(events))
+		}
+		found, err := repos.serial.GetBySerial(acme, product.ID, "SN-1")
+		if err != nil || found.Status != domain.SerialStatusShipped {
+			t.Errorf("Expected acme's unit to be shipped, got %+v (%v)", found, err)
+		}
+	})
+}
+
+func TestTenantIsolationOrders(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		product, item := createStockedProduct(t, acme, repos, 10)
+
+		reservation := &domain.Reservation{
+			ProductID: product.ID, InventoryID: item.ID, Location: item.Location, Quantity: 1,
+			Reference: "ORDER-" + uuid.NewString(), Status: domain.ReservationStatusPending, ExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repos.reservation.Create(acme, reservation); err != nil {
+			t.Fatalf("Failed to create reservation: %v", err)
+		}
+		order := &domain.Order{
+			ID:     reservation.Reference,
+			Status: domain.OrderStatusPending,
+			Lines:  []domain.OrderLine{{ProductID: product.ID, Location: item.Location, Quantity: 1, ReservationID: reservation.ID}},
+		}
+		if err := repos.order.Create(acme, order); err != nil {
+			t.Fatalf("Failed to create order: %v", err)
+		}
+
+		if _, err := repos.order.GetByID(evil, order.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the order of another tenant to be hidden, got %v", err)
+		}
+		if orders, _ := repos.order.List(evil, "", 100, 0); len(orders) != 0 {
+			t.Errorf("Expected no orders listed for another tenant, got %d", len(orders))
+		}
+		if ok, _ := repos.order.UpdateStatus(evil, order.ID, domain.OrderStatusPending, domain.OrderStatusCancelled); ok {
+			t.Error("Expected the order of another tenant not to change")
+		}
+		found, err := repos.order.GetByID(acme, order.ID)
+		if err != nil || found.Status != domain.OrderStatusPending || len(found.Lines) != 1 {
+			t.Errorf("Expected acme's pending order with its line, got %+v (%v)", found, err)
+		}
+	})
+}
+
+func TestTenantIsolationCartHolds(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		product, item := createStockedProduct(t, acme, repos, 10)
+
+		hold := &domain.CartHold{
+			Reference: "CART-" + uuid.NewString(),
+			Status:    domain.CartHoldStatusHeld,
+			Lines:     []domain.CartHoldLine{{SKU: product.SKU, ProductID: product.ID, InventoryID: item.ID, Location: item.Location, Quantity: 2}},
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		if err := repos.cartHold.Create(acme, hold); err != nil {
+			t.Fatalf("Failed to create cart hold: %v", err)
+		}
+
+		if _, err := repos.cartHold.GetByID(evil, hold.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected the hold of another tenant to be hidden, got %v", err)
+		}
+		if ok, _ := repos.cartHold.Claim(evil, hold.ID, domain.CartHoldStatusConverted, time.Now()); ok {
+			t.Error("Expected the hold of another tenant not to be claimed")
+		}
+		if ok, _ := repos.cartHold.UpdateStatus(evil, hold.ID, domain.CartHoldStatusHeld, domain.CartHoldStatusReleased); ok {
+			t.Error("Expected the hold of another tenant not to change")
+		}
+		found, err := repos.cartHold.GetByID(acme, hold.ID)
+		if err != nil || found.Status != domain.CartHoldStatusHeld || len(found.Lines) != 1 {
+			t.Errorf("Expected acme's hold with its line, got %+v (%v)", found, err)
+		}
+	})
+}
+
+func TestAuditManifestRepository(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		manifest := &domain.AuditManifest{
+			ID: uuid.NewString(), From: from, To: from.AddDate(0, 1, 0),
+			GeneratedAt: time.Now().UTC().Truncate(time.Microsecond), RowCount: 42,
+			SHA256: "0123abcd", Algorithm: "ed25519", PublicKey: "key", Signature: "signature",
+		}
+		if err := repos.manifest.Create(acme, manifest); err != nil {
+			t.Fatalf("Failed to create manifest: %v", err)
+		}
+
+		stored, err := repos.manifest.GetByID(acme, manifest.ID)
+		if err != nil {
+			t.Fatalf("Failed to get manifest: %v", err)
+		}
+		if string(stored.SigningPayload()) != string(manifest.SigningPayload()) || stored.Signature != manifest.Signature {
+			t.Errorf("Expected the manifest as signed, got %+v", stored)
+		}
+
+		if _, err := repos.manifest.GetByID(evil, manifest.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Expected another tenant not to see the manifest, got %v", err)
+		}
+		if _, err := repos.manifest.GetByID(context.Background(), manifest.ID); err != nil {
+			t.Errorf("Expected the manifest to be visible without a tenant scope, got %v", err)
+		}
+	})
+}
+
+func TestRedactionCoversArchiveAndPayloadSamples(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		_, item := createStockedProduct(t, ctx, repos, 10)
+		email := uuid.NewString() + "@example.com"
+
+		archived := &domain.Transaction{InventoryID: item.ID, ProductID: item.ProductID, Type: "OUT", Quantity: 1,
+			Reference: "order for " + email, Notes: "shipped to " + email}
+		if err := repos.transaction.Create(ctx, archived); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		if _, err := repos.transaction.(TransactionRetentionRepository).ArchiveBefore(ctx, time.Now().Add(time.Minute), 1000000); err != nil {
+			t.Fatalf("Failed to archive transactions: %v", err)
+		}
+		live := &domain.Transaction{InventoryID: item.ID, ProductID: item.ProductID, Type: "IN", Quantity: 1, Reference: "return from " + email}
+		if err := repos.transaction.Create(ctx, live); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		sample := &domain.PayloadSample{ID: uuid.NewString(), Method: "POST", Path: "/api/orders", StatusCode: 201,
+			RequestBody: `{"email":"` + email + `"}`, ResponseBody: `{"customer":"` + email + `"}`, CreatedAt: time.Now()}
+		if err := repos.sample.Create(ctx, sample); err != nil {
+			t.Fatalf("Failed to create payload sample: %v", err)
+		}
+
+		redaction := &domain.Redaction{Token: "[REDACTED-1]", Reason: "GDPR erasure request"}
+		if err := repos.redaction.Redact(ctx, email, redaction); err != nil {
+			t.Fatalf("Failed to redact: %v", err)
+		}
+		if redaction.TransactionsAffected != 2 {
+			t.Errorf("Expected 2 transactions redacted, got %d", redaction.TransactionsAffected)
+		}
+
+		for _, id := range []string{archived.ID, live.ID} {
+			transaction, err := repos.transaction.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("Failed to get transaction: %v", err)
+			}
+			if strings.Contains(transaction.Reference+transaction.Notes, email) {
+				t.Errorf("Expected transaction %s redacted, got %q / %q", id, transaction.Reference, transaction.Notes)
+			}
+		}
+		samples, err := repos.sample.List(ctx, "/api/orders", 1000, 0)
+		if err != nil {
+			t.Fatalf("Failed to list payload samples: %v", err)
+		}
+		for _, stored := range samples {
+			if stored.ID == sample.ID && strings.Contains(stored.RequestBody+stored.ResponseBody, email) {
+				t.Errorf("Expected the payload sample redacted, got %q / %q", stored.RequestBody, stored.ResponseBody)
+			}
+		}
+	})
+}
+
+func TestTenantIsolationRedactions(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		acme, evil := tenantContexts()
+
+		redaction := &domain.Redaction{Token: "REDACTED-" + uuid.NewString()[:8], Reason: "GDPR erasure request"}
+		if err := repos.redaction.Redact(acme, uuid.NewString()+"@example.com", redaction); err != nil {
+			t.Fatalf("Failed to redact: %v", err)
+		}
+
+		listed := func(ctx context.Context) bool {
+			redactions, err := repos.redaction.List(ctx, 1000000, 0)
+			if err != nil {
+				t.Fatalf("Failed to list redactions: %v", err)
+			}
+			return slices.ContainsFunc(redactions, func(r *domain.Redaction) bool { return r.ID == redaction.ID })
+		}
+		if listed(evil) {
+			t.Error("Expected the redaction of another tenant to be hidden")
+		}
+		if !listed(acme) {
+			t.Error("Expected the redaction listed for its own tenant")
+		}
+		if !listed(context.Background()) {
+			t.Error("Expected the redaction listed without a tenant scope")
+		}
+	})
+}
+
+func TestReportsCoverArchivedTransactions(t *testing.T) {
+	forEachDriver(t, func(t *testing.T, repos testRepositories) {
+		ctx := context.Background()
+		product, item := createStockedProduct(t, ctx, repos, 10)
+
+		sale := &domain.Transaction{InventoryID: item.ID, ProductID: product.ID, Type: "OUT", Quantity: 3, Reference: "ORD-" + uuid.NewString()}
+		if err := repos.transaction.Create(ctx, sale); err != nil {
+			t.Fatalf("Failed to create transaction: %v", err)
+		}
+		if _, err := repos.transaction.(TransactionRetentionRepository).ArchiveBefore(ctx, time.Now().Add(time.Minute), 1000000); err != nil {
+			t.Fatalf("Failed to archive transactions: %v", err)
+		}
+
+		now := time.Now()
+		summaries, err := repos.report.MovementSummary(ctx, MovementQuery{
+			From: now.Add(-time.Hour), To: now.Add(time.Hour), Granularity: "day", ProductID: product.ID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to summarize movements: %v", err)
+		}
+		var sold int64
+		for _, summary := range summaries {
+			sold += summary.Movements["OUT"].Quantity
+		}
+		if sold != 3 {
+			t.Errorf("Expected the 3 archived units sold in the summary, got %d", sold)
+		}
+
+		velocities, err := repos.report.StockVelocities(ctx, now.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("Failed to compute stock velocities: %v", err)
+		}
+		if !slices.ContainsFunc(velocities, func(v *domain.StockVelocity) bool { return v.ProductID == product.ID }) {
+			t.Errorf("Expected a velocity for the product sold in archived transactions")
+		}
+	})
+}
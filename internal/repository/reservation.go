@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresReservationRepository implements ReservationRepository using PostgreSQL
+type PostgresReservationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReservationRepository creates a new PostgresReservationRepository
+func NewPostgresReservationRepository(db *sql.DB) *PostgresReservationRepository {
+	return &PostgresReservationRepository{db: db}
+}
+
+// Create inserts a new reservation, which belongs to the tenant of its
+// inventory item
+func (r *PostgresReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	if err := reservation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	reservation.ID = uuid.New().String()
+	now := time.Now()
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+
+	query := `
+		INSERT INTO reservations (id, parent_id, product_id, inventory_id, location, quantity, reference, channel_id, status, expires_at, created_at, updated_at, tenant_id)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, tenant_id
+		FROM inventory WHERE id = $4 AND ($13 = '' OR tenant_id = $13)
+		RETURNING tenant_id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		reservation.ID, nullIfEmpty(reservation.ParentID), reservation.ProductID, reservation.InventoryID, reservation.Location,
+		reservation.Quantity, reservation.Reference, nullIfEmpty(reservation.ChannelID), reservation.Status,
+		reservation.ExpiresAt, reservation.CreatedAt, reservation.UpdatedAt, tenantScope(ctx),
+	).Scan(&reservation.TenantID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a reservation by ID
+func (r *PostgresReservationRepository) GetByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	query := `
+		SELECT id, tenant_id, COALESCE(parent_id, ''), product_id, inventory_id, location, quantity, reference, COALESCE(channel_id, ''), status, expires_at, created_at, updated_at
+		FROM reservations WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+	`
+
+	reservation := &domain.Reservation{}
+	err := r.db.QueryRowContext(ctx, query, id, tenantScope(ctx)).Scan(
+		&reservation.ID, &reservation.TenantID, &reservation.ParentID, &reservation.ProductID, &reservation.InventoryID, &reservation.Location,
+		&reservation.Quantity, &reservation.Reference, &reservation.ChannelID, &reservation.Status, &reservation.ExpiresAt,
+		&reservation.CreatedAt, &reservation.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reservation %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	return reservation, nil
+}
+
+// UpdateStatus moves a reservation from one status to another. It reports
+// false when the reservation was no longer in the expected status, which lets
+// concurrent confirm, release and expiry race safely.
+func (r *PostgresReservationRepository) UpdateStatus(ctx context.Context, id string, from, to domain.ReservationStatus) (bool, error) {
+	query := `
+		UPDATE reservations
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND ($5 = '' OR tenant_id = $5)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, to, time.Now(), id, from, tenantScope(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to update reservation status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ListExpired retrieves pending reservations whose expiry time has passed,
+// oldest expiry first
+func (r *PostgresReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error) {
+	query := `
+		SELECT id, tenant_id, COALESCE(parent_id, ''), product_id, inventory_id, location, quantity, reference, COALESCE(channel_id, ''), status, expires_at, created_at, updated_at
+		FROM reservations
+		WHERE status = $1 AND expires_at <= $2 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY expires_at ASC
+		LIMIT $3
+	`
+
+	return r.query(ctx, query, domain.ReservationStatusPending, now, limit, tenantScope(ctx))
+}
+
+// ListByProductID retrieves a paginated list of reservations of a product, newest first
+func (r *PostgresReservationRepository) ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Reservation, error) {
+	query := `
+		SELECT id, tenant_id, COALESCE(parent_id, ''), product_id, inventory_id, location, quantity, reference, COALESCE(channel_id, ''), status, expires_at, created_at, updated_at
+		FROM reservations
+		WHERE product_id = $1 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.query(ctx, query, productID, limit, offset, tenantScope(ctx))
+}
+
+// ListByParentID retrieves the children of a split reservation, oldest first
+func (r *PostgresReservationRepository) ListByParentID(ctx context.Context, parentID string) ([]*domain.Reservation, error) {
+	query := `
+		SELECT id, tenant_id, COALESCE(parent_id, ''), product_id, inventory_id, location, quantity, reference, COALESCE(channel_id, ''), status, expires_at, created_at, updated_at
+		FROM reservations
+		WHERE parent_id = $1 AND ($2 = '' OR tenant_id = $2)
+		ORDER BY created_at ASC, id ASC
+	`
+
+	return r.query(ctx, query, parentID, tenantScope(ctx))
+}
+
+// PendingByChannel sums the quantity of the pending reservations a sales
+// channel holds of a product
+func (r *PostgresReservationRepository) PendingByChannel(ctx context.Context, channelID, productID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM reservations
+		WHERE channel_id = $1 AND product_id = $2 AND status = $3 AND ($4 = '' OR tenant_id = $4)
+	`
+
+	var pending int64
+	if err := r.db.QueryRowContext(ctx, query, channelID, productID, domain.ReservationStatusPending, tenantScope(ctx)).Scan(&pending); err != nil {
+		return 0, fmt.Errorf("failed to sum channel reservations: %w", err)
+	}
+	return pending, nil
+}
+
+// ConfirmedByChannel sums the quantity of the reservations of a product a
+// sales channel confirmed since the given time
+func (r *PostgresReservationRepository) ConfirmedByChannel(ctx context.Context, channelID, productID string, since time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM reservations
+		WHERE channel_id = $1 AND product_id = $2 AND status = $3 AND updated_at >= $4 AND ($5 = '' OR tenant_id = $5)
+	`
+
+	var confirmed int64
+	if err := r.db.QueryRowContext(ctx, query, channelID, productID, domain.ReservationStatusConfirmed, since, tenantScope(ctx)).Scan(&confirmed); err != nil {
+		return 0, fmt.Errorf("failed to sum confirmed channel reservations: %w", err)
+	}
+	return confirmed, nil
+}
+
+// query runs a reservation select and scans all rows
+func (r *PostgresReservationRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []*domain.Reservation
+	for rows.Next() {
+		reservation := &domain.Reservation{}
+		if err := rows.Scan(
+			&reservation.ID, &reservation.TenantID, &reservation.ParentID, &reservation.ProductID, &reservation.InventoryID, &reservation.Location,
+			&reservation.Quantity, &reservation.Reference, &reservation.ChannelID, &reservation.Status, &reservation.ExpiresAt,
+			&reservation.CreatedAt, &reservation.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reservations: %w", err)
+	}
+
+	return reservations, nil
+}
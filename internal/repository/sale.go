@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresSaleRepository implements SaleRepository using PostgreSQL
+type PostgresSaleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSaleRepository creates a new PostgresSaleRepository
+func NewPostgresSaleRepository(db *sql.DB) *PostgresSaleRepository {
+	return &PostgresSaleRepository{db: db}
+}
+
+// Create records a sale, its event time in UTC like the filters of List. It
+// returns ErrDuplicateSale when the tenant already recorded a sale with the
+// event ID.
+func (r *PostgresSaleRepository) Create(ctx context.Context, sale *domain.Sale) error {
+	sale.OccurredAt = sale.OccurredAt.UTC()
+	sale.ReceivedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO sales (tenant_id, event_id, product_id, location, quantity, status, occurred_at, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, tenantOwner(ctx), sale.EventID, sale.ProductID, sale.Location, sale.Quantity, sale.Status, sale.OccurredAt, sale.ReceivedAt)
+	if isUniqueViolation(err, "sales_pkey") {
+		return domain.ErrDuplicateSale
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create sale: %w", err)
+	}
+	return nil
+}
+
+// GetByEventID retrieves the sale recorded for an event ID
+func (r *PostgresSaleRepository) GetByEventID(ctx context.Context, eventID string) (*domain.Sale, error) {
+	sale := &domain.Sale{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT event_id, product_id, location, quantity, status, occurred_at, received_at
+		FROM sales WHERE tenant_id = $1 AND event_id = $2
+	`, tenantOwner(ctx), eventID).Scan(
+		&sale.EventID, &sale.ProductID, &sale.Location, &sale.Quantity, &sale.Status, &sale.OccurredAt, &sale.ReceivedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("sale %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sale: %w", err)
+	}
+	return sale, nil
+}
+
+// List retrieves sales in the order they happened, ties broken by event ID
+func (r *PostgresSaleRepository) List(ctx context.Context, filter domain.SaleFilter, limit, offset int) ([]*domain.Sale, error) {
+	query := `
+		SELECT event_id, product_id, location, quantity, status, occurred_at, received_at
+		FROM sales
+		WHERE ($1 = '' OR tenant_id = $1)
+			AND ($2 = '' OR location = $2)
+			AND ($3::timestamp IS NULL OR occurred_at >= $3)
+			AND ($4::timestamp IS NULL OR occurred_at < $4)
+		ORDER BY occurred_at, event_id
+		LIMIT $5 OFFSET $6
+	`
+
+	from := sql.NullTime{Time: filter.From.UTC(), Valid: !filter.From.IsZero()}
+	to := sql.NullTime{Time: filter.To.UTC(), Valid: !filter.To.IsZero()}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, tenantScope(ctx), filter.Location, from, to, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales: %w", err)
+	}
+	defer rows.Close()
+
+	var sales []*domain.Sale
+	for rows.Next() {
+		sale := &domain.Sale{}
+		if err := rows.Scan(&sale.EventID, &sale.ProductID, &sale.Location, &sale.Quantity, &sale.Status, &sale.OccurredAt, &sale.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sale: %w", err)
+		}
+		sales = append(sales, sale)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sales: %w", err)
+	}
+
+	return sales, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SchemaDrift is how the live database schema differs from the one the
+// migrations of the binary build: the versions missing from or unknown to
+// schema_migrations, and the tables and columns whose shape differs
+type SchemaDrift struct {
+	PendingVersions []int64
+	UnknownVersions []int64
+	Differences     []SchemaDifference
+}
+
+// SchemaDifference is a table or column of the expected or the live schema.
+// A table difference has no Column. Expected is empty when only the live
+// schema has it and Actual when it is missing there.
+type SchemaDifference struct {
+	Table    string
+	Column   string
+	Expected string
+	Actual   string
+}
+
+// Empty reports whether the live schema matches
+func (d *SchemaDrift) Empty() bool {
+	return len(d.PendingVersions) == 0 && len(d.UnknownVersions) == 0 && len(d.Differences) == 0
+}
+
+// Report lists the drift one difference per line
+func (d *SchemaDrift) Report() []string {
+	var lines []string
+	for _, version := range d.PendingVersions {
+		lines = append(lines, fmt.Sprintf("migration %d is not applied", version))
+	}
+	for _, version := range d.UnknownVersions {
+		lines = append(lines, fmt.Sprintf("migration %d is applied but unknown to this binary", version))
+	}
+	for _, diff := range d.Differences {
+		name := diff.Table
+		if diff.Column != "" {
+			name += "." + diff.Column
+		}
+		switch {
+		case diff.Actual == "":
+			lines = append(lines, fmt.Sprintf("%s is missing, expected %s", name, diff.Expected))
+		case diff.Expected == "":
+			lines = append(lines, fmt.Sprintf("%s is unexpected: %s", name, diff.Actual))
+		default:
+			lines = append(lines, fmt.Sprintf("%s is %s, expected %s", name, diff.Actual, diff.Expected))
+		}
+	}
+	return lines
+}
+
+// schemaColumns maps the tables and views of a schema to the shape of their
+// columns, such as "character varying(36) NOT NULL"
+type schemaColumns map[string]map[string]string
+
+// Drift compares the live schema with the one the migrations build, which
+// it gets by applying every migration to a scratch schema in a transaction
+// that is rolled back. Partitions are left out, since the monthly ones
+// depend on the date, as is schema_migrations.
+func (m *Migrator) Drift(ctx context.Context) (*SchemaDrift, error) {
+	applied, err := appliedMigrations(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	drift := &SchemaDrift{}
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	for _, migration := range m.pending(applied) {
+		drift.PendingVersions = append(drift.PendingVersions, migration.Version)
+	}
+	for version := range applied {
+		if !known[version] {
+			drift.UnknownVersions = append(drift.UnknownVersions, version)
+		}
+	}
+	slices.Sort(drift.UnknownVersions)
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var live string
+	if err := tx.QueryRowContext(ctx, `SELECT current_schema()`).Scan(&live); err != nil {
+		return nil, fmt.Errorf("failed to read current schema: %w", err)
+	}
+	actual, err := readSchemaColumns(ctx, tx, live)
+	if err != nil {
+		return nil, err
+	}
+
+	scratch := fmt.Sprintf("schema_drift_%d", time.Now().UnixNano())
+	if _, err := tx.ExecContext(ctx, `CREATE SCHEMA `+scratch); err != nil {
+		return nil, fmt.Errorf("failed to create scratch schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `SET LOCAL search_path TO `+scratch); err != nil {
+		return nil, fmt.Errorf("failed to switch to scratch schema: %w", err)
+	}
+	for _, migration := range m.migrations {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return nil, fmt.Errorf("migration %d_%s failed on the scratch schema: %w", migration.Version, migration.Name, err)
+		}
+	}
+	expected, err := readSchemaColumns(ctx, tx, scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	drift.Differences = compareSchemas(expected, actual)
+	return drift, nil
+}
+
+// readSchemaColumns reads the columns of the tables and views of schema,
+// leaving out partitions and schema_migrations
+func readSchemaColumns(ctx context.Context, q dbtx, schema string) (schemaColumns, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v') AND NOT c.relispartition
+			AND c.relname <> 'schema_migrations' AND a.attnum > 0 AND NOT a.attisdropped`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of schema %s: %w", schema, err)
+	}
+	defer rows.Close()
+
+	columns := make(schemaColumns)
+	for rows.Next() {
+		var table, column, dataType string
+		var notNull bool
+		if err := rows.Scan(&table, &column, &dataType, &notNull); err != nil {
+			return nil, fmt.Errorf("failed to scan column: %w", err)
+		}
+		if notNull {
+			dataType += " NOT NULL"
+		}
+		if columns[table] == nil {
+			columns[table] = make(map[string]string)
+		}
+		columns[table][column] = dataType
+	}
+	return columns, rows.Err()
+}
+
+// compareSchemas lists the differences between the expected and the actual
+// columns, ordered by table and column. A table missing on either side is
+// one difference rather than one per column.
+func compareSchemas(expected, actual schemaColumns) []SchemaDifference {
+	var diffs []SchemaDifference
+	for table, columns := range expected {
+		if actual[table] == nil {
+			diffs = append(diffs, SchemaDifference{Table: table, Expected: tableShape(columns)})
+			continue
+		}
+		for column, shape := range columns {
+			if got, ok := actual[table][column]; !ok || got != shape {
+				diffs = append(diffs, SchemaDifference{Table: table, Column: column, Expected: shape, Actual: got})
+			}
+		}
+		for column, shape := range actual[table] {
+			if _, ok := columns[column]; !ok {
+				diffs = append(diffs, SchemaDifference{Table: table, Column: column, Actual: shape})
+			}
+		}
+	}
+	for table, columns := range actual {
+		if expected[table] == nil {
+			diffs = append(diffs, SchemaDifference{Table: table, Actual: tableShape(columns)})
+		}
+	}
+	slices.SortFunc(diffs, func(a, b SchemaDifference) int {
+		return cmp.Or(cmp.Compare(a.Table, b.Table), cmp.Compare(a.Column, b.Column))
+	})
+	return diffs
+}
+
+// tableShape describes a table by its column names
+func tableShape(columns map[string]string) string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return fmt.Sprintf("a table of %d columns (%s)", len(names), strings.Join(names, ", "))
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresSerialUnitRepository implements SerialUnitRepository using PostgreSQL
+type PostgresSerialUnitRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSerialUnitRepository creates a new PostgresSerialUnitRepository
+func NewPostgresSerialUnitRepository(db *sql.DB) *PostgresSerialUnitRepository {
+	return &PostgresSerialUnitRepository{db: db}
+}
+
+// Create inserts a new serial unit, which belongs to the tenant of its product
+func (r *PostgresSerialUnitRepository) Create(ctx context.Context, unit *domain.SerialUnit) error {
+	if err := unit.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	unit.ID = uuid.New().String()
+	now := time.Now()
+	unit.CreatedAt = now
+	unit.UpdatedAt = now
+
+	query := `
+		INSERT INTO serial_units (id, product_id, serial_number, status, created_at, updated_at, tenant_id)
+		SELECT $1, $2, $3, $4, $5, $6, tenant_id
+		FROM products WHERE id = $2 AND ($7 = '' OR tenant_id = $7)
+		RETURNING tenant_id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		unit.ID, unit.ProductID, unit.SerialNumber, unit.Status, unit.CreatedAt, unit.UpdatedAt, tenantScope(ctx),
+	).Scan(&unit.TenantID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create serial unit: %w", err)
+	}
+
+	return nil
+}
+
+// GetBySerial retrieves a serial unit by product and serial number
+func (r *PostgresSerialUnitRepository) GetBySerial(ctx context.Context, productID, serialNumber string) (*domain.SerialUnit, error) {
+	query := `
+		SELECT id, tenant_id, product_id, serial_number, status, created_at, updated_at
+		FROM serial_units WHERE product_id = $1 AND serial_number = $2 AND ($3 = '' OR tenant_id = $3)
+	`
+
+	unit := &domain.SerialUnit{}
+	err := r.db.QueryRowContext(ctx, query, productID, serialNumber, tenantScope(ctx)).Scan(
+		&unit.ID, &unit.TenantID, &unit.ProductID, &unit.SerialNumber, &unit.Status, &unit.CreatedAt, &unit.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("serial unit %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial unit: %w", err)
+	}
+
+	return unit, nil
+}
+
+// ListByProductID retrieves a paginated list of serial units for a product
+func (r *PostgresSerialUnitRepository) ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.SerialUnit, error) {
+	query := `
+		SELECT id, tenant_id, product_id, serial_number, status, created_at, updated_at
+		FROM serial_units
+		WHERE product_id = $1 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serial units: %w", err)
+	}
+	defer rows.Close()
+
+	var units []*domain.SerialUnit
+	for rows.Next() {
+		unit := &domain.SerialUnit{}
+		if err := rows.Scan(
+			&unit.ID, &unit.TenantID, &unit.ProductID, &unit.SerialNumber, &unit.Status, &unit.CreatedAt, &unit.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan serial unit: %w", err)
+		}
+		units = append(units, unit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating serial units: %w", err)
+	}
+
+	return units, nil
+}
+
+// UpdateStatus persists a status change and its history event atomically.
+// The update only applies if the stored status still matches the event's
+// from status, so concurrent transitions of the same unit cannot both win.
+func (r *PostgresSerialUnitRepository) UpdateStatus(ctx context.Context, unit *domain.SerialUnit, event *domain.SerialUnitEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE serial_units
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND ($5 = '' OR tenant_id = $5)
+	`, event.ToStatus, now, unit.ID, event.FromStatus, tenantScope(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update serial unit: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		// Nothing was updated: either the unit does not exist or its status
+		// is no longer the one the change was made from
+		var status string
+		err := tx.QueryRowContext(ctx, `
+			SELECT status FROM serial_units WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
+		`, unit.ID, tenantScope(ctx)).Scan(&status)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("serial unit %w", domain.ErrNotFound)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get serial unit: %w", err)
+		}
+		return fmt.Errorf("serial unit status changed concurrently to %s: %w", status, domain.ErrConflict)
+	}
+
+	event.ID = uuid.New().String()
+	event.SerialUnitID = unit.ID
+	event.CreatedAt = now
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO serial_unit_events (id, serial_unit_id, from_status, to_status, reference, notes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, event.ID, event.SerialUnitID, event.FromStatus, event.ToStatus, event.Reference, event.Notes, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record serial unit event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	unit.UpdatedAt = now
+	return nil
+}
+
+// GetHistory retrieves the status history of a serial unit, newest first.
+// Events are in the tenant scope of their unit.
+func (r *PostgresSerialUnitRepository) GetHistory(ctx context.Context, unitID string, limit, offset int) ([]*domain.SerialUnitEvent, error) {
+	query := `
+		SELECT e.id, e.serial_unit_id, e.from_status, e.to_status, e.reference, e.notes, e.created_at
+		FROM serial_unit_events e
+		JOIN serial_units u ON u.id = e.serial_unit_id
+		WHERE e.serial_unit_id = $1 AND ($4 = '' OR u.tenant_id = $4)
+		ORDER BY e.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, unitID, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serial unit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.SerialUnitEvent
+	for rows.Next() {
+		event := &domain.SerialUnitEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.SerialUnitID, &event.FromStatus, &event.ToStatus,
+			&event.Reference, &event.Notes, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan serial unit event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating serial unit events: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresStockAlertRepository implements StockAlertRepository using PostgreSQL
+type PostgresStockAlertRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresStockAlertRepository creates a new PostgresStockAlertRepository
+func NewPostgresStockAlertRepository(db *sql.DB) *PostgresStockAlertRepository {
+	return &PostgresStockAlertRepository{db: db}
+}
+
+const stockAlertColumns = `a.id, a.product_id, p.sku, a.inventory_id, a.location, a.kind, a.status, a.available,
+	a.on_order, a.reorder_level, a.daily_velocity, a.lead_time_days, a.projected_stockout_at, a.raised_at,
+	a.updated_at, a.resolved_at`
+
+// Raise stores a new open alert for an inventory row. It returns false
+// without storing anything when the row already has an open alert, e.g. one
+// raised by another replica at the same time.
+func (r *PostgresStockAlertRepository) Raise(ctx context.Context, alert *domain.StockAlert) (bool, error) {
+	alert.ID = uuid.New().String()
+	now := time.Now()
+	alert.Status = domain.StockAlertStatusOpen
+	alert.RaisedAt = now
+	alert.UpdatedAt = now
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO stock_alerts (id, tenant_id, product_id, inventory_id, location, kind, status, available, on_order,
+			reorder_level, daily_velocity, lead_time_days, projected_stockout_at, raised_at, updated_at)
+		SELECT $1, i.tenant_id, $2, i.id, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12
+		FROM inventory i WHERE i.id = $13
+		ON CONFLICT (inventory_id) WHERE status = 'OPEN' DO NOTHING
+	`, alert.ID, alert.ProductID, alert.Location, alert.Kind, alert.Status, alert.Available, alert.OnOrder,
+		alert.ReorderLevel, alert.DailyVelocity, alert.LeadTimeDays, alert.ProjectedStockoutAt, now, alert.InventoryID)
+	if err != nil {
+		return false, fmt.Errorf("failed to raise stock alert: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Refresh updates the kind and figures of an open alert
+func (r *PostgresStockAlertRepository) Refresh(ctx context.Context, alert *domain.StockAlert) error {
+	alert.UpdatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE stock_alerts
+		SET kind = $2, available = $3, on_order = $4, reorder_level = $5, daily_velocity = $6, lead_time_days = $7,
+			projected_stockout_at = $8, updated_at = $9
+		WHERE id = $1 AND status = 'OPEN'
+	`, alert.ID, alert.Kind, alert.Available, alert.OnOrder, alert.ReorderLevel, alert.DailyVelocity,
+		alert.LeadTimeDays, alert.ProjectedStockoutAt, alert.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to refresh stock alert: %w", err)
+	}
+	return nil
+}
+
+// Resolve closes an open alert. It returns false when the alert was not
+// open anymore.
+func (r *PostgresStockAlertRepository) Resolve(ctx context.Context, id string, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE stock_alerts SET status = $2, resolved_at = $3, updated_at = $3
+		WHERE id = $1 AND status = 'OPEN'
+	`, id, domain.StockAlertStatusResolved, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve stock alert: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// ListOpen retrieves every open alert
+func (r *PostgresStockAlertRepository) ListOpen(ctx context.Context) ([]*domain.StockAlert, error) {
+	return r.query(ctx, `
+		SELECT `+stockAlertColumns+`
+		FROM stock_alerts a JOIN products p ON p.id = a.product_id
+		WHERE a.status = 'OPEN' AND ($1 = '' OR a.tenant_id = $1)
+		ORDER BY a.raised_at, a.id
+	`, tenantScope(ctx))
+}
+
+// List retrieves a page of alerts, newest first, optionally only those in
+// the given status
+func (r *PostgresStockAlertRepository) List(ctx context.Context, status domain.StockAlertStatus, limit, offset int) ([]*domain.StockAlert, error) {
+	return r.query(ctx, `
+		SELECT `+stockAlertColumns+`
+		FROM stock_alerts a JOIN products p ON p.id = a.product_id
+		WHERE ($1 = '' OR a.status = $1) AND ($2 = '' OR a.tenant_id = $2)
+		ORDER BY a.raised_at DESC, a.id
+		LIMIT $3 OFFSET $4
+	`, status, tenantScope(ctx), limit, offset)
+}
+
+// query runs an alert query and scans its rows
+func (r *PostgresStockAlertRepository) query(ctx context.Context, query string, args ...any) ([]*domain.StockAlert, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*domain.StockAlert
+	for rows.Next() {
+		alert := &domain.StockAlert{}
+		var projected, resolved sql.NullTime
+		if err := rows.Scan(
+			&alert.ID, &alert.ProductID, &alert.SKU, &alert.InventoryID, &alert.Location, &alert.Kind, &alert.Status,
+			&alert.Available, &alert.OnOrder, &alert.ReorderLevel, &alert.DailyVelocity, &alert.LeadTimeDays,
+			&projected, &alert.RaisedAt, &alert.UpdatedAt, &resolved,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan stock alert: %w", err)
+		}
+		if projected.Valid {
+			alert.ProjectedStockoutAt = &projected.Time
+		}
+		if resolved.Valid {
+			alert.ResolvedAt = &resolved.Time
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stock alerts: %w", err)
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresStocktakeRepository implements StocktakeRepository using PostgreSQL
+type PostgresStocktakeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresStocktakeRepository creates a new PostgresStocktakeRepository
+func NewPostgresStocktakeRepository(db *sql.DB) *PostgresStocktakeRepository {
+	return &PostgresStocktakeRepository{db: db}
+}
+
+const stocktakeColumns = `id, location, mode, status, created_at, closed_at`
+
+// Create inserts a new open stocktake. At most one stocktake can be open per
+// location; the partial unique index rejects a second one.
+func (r *PostgresStocktakeRepository) Create(ctx context.Context, stocktake *domain.Stocktake) error {
+	if err := stocktake.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	stocktake.ID = uuid.New().String()
+	stocktake.Status = domain.StocktakeStatusOpen
+	stocktake.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO stocktakes (id, location, mode, status, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		stocktake.ID, stocktake.Location, stocktake.Mode, stocktake.Status, stocktake.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create stocktake: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a stocktake by ID
+func (r *PostgresStocktakeRepository) GetByID(ctx context.Context, id string) (*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes WHERE id = $1`
+
+	stocktake, err := scanStocktake(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("stocktake %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stocktake: %w", err)
+	}
+
+	return stocktake, nil
+}
+
+// FindOpen returns the open stocktake of a location, or nil if there is none
+func (r *PostgresStocktakeRepository) FindOpen(ctx context.Context, location string) (*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes WHERE location = $1 AND status = $2`
+
+	stocktake, err := scanStocktake(r.db.QueryRowContext(ctx, query, location, domain.StocktakeStatusOpen))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open stocktake: %w", err)
+	}
+
+	return stocktake, nil
+}
+
+// List retrieves a paginated list of stocktakes, newest first
+func (r *PostgresStocktakeRepository) List(ctx context.Context, limit, offset int) ([]*domain.Stocktake, error) {
+	query := `SELECT ` + stocktakeColumns + ` FROM stocktakes ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stocktakes: %w", err)
+	}
+	defer rows.Close()
+
+	var stocktakes []*domain.Stocktake
+	for rows.Next() {
+		stocktake, err := scanStocktake(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stocktake: %w", err)
+		}
+		stocktakes = append(stocktakes, stocktake)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stocktakes: %w", err)
+	}
+
+	return stocktakes, nil
+}
+
+// Close marks an open stocktake closed. It reports false if the stocktake was
+// not open.
+func (r *PostgresStocktakeRepository) Close(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE stocktakes SET status = $1, closed_at = $2 WHERE id = $3 AND status = $4`,
+		domain.StocktakeStatusClosed, time.Now(), id, domain.StocktakeStatusOpen,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to close stocktake: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// QueueMovement stores a movement received while its location was frozen
+func (r *PostgresStocktakeRepository) QueueMovement(ctx context.Context, movement *domain.QueuedMovement) error {
+	movement.ID = uuid.New().String()
+	movement.Status = domain.QueuedMovementPending
+	movement.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO stocktake_queued_movements (id, stocktake_id, product_id, location, type, quantity, reference, status, created_at,
+			condition, unit_cost)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	if movement.Condition == "" {
+		movement.Condition = domain.ConditionNew
+	}
+	_, err := r.db.ExecContext(ctx, query,
+		movement.ID, movement.StocktakeID, movement.ProductID, movement.Location, movement.Type,
+		movement.Quantity, movement.Reference, movement.Status, movement.CreatedAt, movement.Condition, movement.UnitCost,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue movement: %w", err)
+	}
+
+	return nil
+}
+
+// ListPendingMovements returns the pending movements of a stocktake in the
+// order they were received
+func (r *PostgresStocktakeRepository) ListPendingMovements(ctx context.Context, stocktakeID string) ([]*domain.QueuedMovement, error) {
+	query := `
+		SELECT id, stocktake_id, product_id, location, type, quantity, reference, status, error, created_at, condition,
+			unit_cost
+		FROM stocktake_queued_movements
+		WHERE stocktake_id = $1 AND status = $2
+		ORDER BY seq
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, stocktakeID, domain.QueuedMovementPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued movements: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []*domain.QueuedMovement
+	for rows.Next() {
+		movement := &domain.QueuedMovement{}
+		if err := rows.Scan(
+			&movement.ID, &movement.StocktakeID, &movement.ProductID, &movement.Location, &movement.Type,
+			&movement.Quantity, &movement.Reference, &movement.Status, &movement.Error, &movement.CreatedAt,
+			&movement.Condition, &movement.UnitCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan queued movement: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating queued movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+// UpdateMovementStatus records the outcome of replaying a queued movement
+func (r *PostgresStocktakeRepository) UpdateMovementStatus(ctx context.Context, id string, status domain.QueuedMovementStatus, errorMessage string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE stocktake_queued_movements SET status = $1, error = $2 WHERE id = $3`,
+		status, errorMessage, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update queued movement: %w", err)
+	}
+
+	return nil
+}
+
+// scanStocktake scans a stocktake row
+func scanStocktake(row interface{ Scan(...any) error }) (*domain.Stocktake, error) {
+	stocktake := &domain.Stocktake{}
+	var closedAt sql.NullTime
+	if err := row.Scan(
+		&stocktake.ID, &stocktake.Location, &stocktake.Mode, &stocktake.Status,
+		&stocktake.CreatedAt, &closedAt,
+	); err != nil {
+		return nil, err
+	}
+	if closedAt.Valid {
+		stocktake.ClosedAt = &closedAt.Time
+	}
+	return stocktake, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// tenantScope returns the tenant the rows read or changed with ctx are
+// restricted to, or "" when ctx is unscoped. Queries compare it to tenant_id
+// unless it is empty.
+func tenantScope(ctx context.Context) string {
+	return domain.TenantIDFromContext(ctx)
+}
+
+// tenantOwner returns the tenant products, orders and cart holds created
+// with ctx belong to. Inventory, transactions and serial units belong to the
+// tenant of their product, reservations to that of their inventory item.
+func tenantOwner(ctx context.Context) string {
+	if id := domain.TenantIDFromContext(ctx); id != "" {
+		return id
+	}
+	return domain.DefaultTenantID
+}
+
+// inTenantScope reports whether a row of the tenant may be read with ctx
+func inTenantScope(ctx context.Context, tenantID string) bool {
+	scope := tenantScope(ctx)
+	return scope == "" || scope == tenantID
+}
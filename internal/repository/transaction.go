@@ -3,17 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"time"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // PostgresTransactionRepository implements TransactionRepository using PostgreSQL
 type PostgresTransactionRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	replica *ReadReplica
 }
 
 // NewPostgresTransactionRepository creates a new PostgresTransactionRepository
@@ -21,6 +22,13 @@ func NewPostgresTransactionRepository(db *sql.DB) *PostgresTransactionRepository
 	return &PostgresTransactionRepository{db: db}
 }
 
+// WithReadReplica routes the transaction lookups and listings to replica while it
+// is healthy; a nil replica keeps every read on the primary
+func (r *PostgresTransactionRepository) WithReadReplica(replica *ReadReplica) *PostgresTransactionRepository {
+	r.replica = replica
+	return r
+}
+
 // Create inserts a new transaction
 func (r *PostgresTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
 	if err := transaction.Validate(); err != nil {
@@ -30,37 +38,64 @@ func (r *PostgresTransactionRepository) Create(ctx context.Context, transaction
 	transaction.ID = uuid.New().String()
 	transaction.CreatedAt = time.Now()
 
+	if err := insertTransaction(ctx, conn(ctx, r.db), transaction); err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertTransaction records the transaction, which inherits the tenant of its
+// inventory item, as the next in its product's event sequence. Numbering
+// locks the product row until q commits, so the product's transactions commit
+// in sequence order and a rolled-back one leaves no gap. It returns
+// ErrNotFound when the item is outside the tenant scope of ctx.
+func insertTransaction(ctx context.Context, q dbtx, transaction *domain.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, inventory_id, product_id, type, quantity, reference, notes, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		WITH numbered AS (
+			UPDATE products p SET event_sequence = p.event_sequence + 1
+			FROM inventory i
+			WHERE p.id = $3 AND i.id = $2 AND ($13 = '' OR i.tenant_id = $13)
+			RETURNING p.event_sequence, i.tenant_id
+		)
+		INSERT INTO transactions (id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence, unit_cost)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, tenant_id, event_sequence, $14
+		FROM numbered
+		RETURNING sequence
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := q.QueryRowContext(ctx, query,
 		transaction.ID, transaction.InventoryID, transaction.ProductID, transaction.Type,
 		transaction.Quantity, transaction.Reference, transaction.Notes, transaction.CreatedAt,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+		transaction.QuantityBefore, transaction.QuantityAfter,
+		nullIfEmpty(string(transaction.ReasonCode)), nullIfEmpty(transaction.PerformedBy), tenantScope(ctx),
+		transaction.UnitCost,
+	).Scan(&transaction.Sequence)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
 	}
-
-	return nil
+	return err
 }
 
-// GetByID retrieves a transaction by ID
+// GetByID retrieves a transaction by ID, live or archived
 func (r *PostgresTransactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
 	query := `
-		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at
-		FROM transactions WHERE id = $1
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
+		FROM transactions_all WHERE id = $1 AND ($2 = '' OR tenant_id = $2)
 	`
 
 	transaction := &domain.Transaction{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, id, tenantScope(ctx)).Scan(
 		&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
 		&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+		&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+		&transaction.Sequence, &transaction.UnitCost,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("transaction not found")
+		return nil, fmt.Errorf("transaction %w", domain.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
@@ -72,14 +107,15 @@ func (r *PostgresTransactionRepository) GetByID(ctx context.Context, id string)
 // GetByInventoryID retrieves transactions for a specific inventory item
 func (r *PostgresTransactionRepository) GetByInventoryID(ctx context.Context, inventoryID string, limit, offset int) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
 		FROM transactions
-		WHERE inventory_id = $1
+		WHERE inventory_id = $1 AND ($4 = '' OR tenant_id = $4)
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, inventoryID, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, inventoryID, limit, offset, tenantScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -91,6 +127,8 @@ func (r *PostgresTransactionRepository) GetByInventoryID(ctx context.Context, in
 		if err := rows.Scan(
 			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
 			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -107,25 +145,131 @@ func (r *PostgresTransactionRepository) GetByInventoryID(ctx context.Context, in
 // GetByProductID retrieves transactions for a specific product
 func (r *PostgresTransactionRepository) GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
 		FROM transactions
-		WHERE product_id = $1
-		ORDER BY created_at DESC
+		WHERE product_id = $1 AND ($4 = '' OR tenant_id = $4)
+		ORDER BY created_at DESC, id DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, productID, limit, offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, productID, limit, offset, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		transaction := &domain.Transaction{}
+		if err := rows.Scan(
+			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
+			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// RecentByProductIDs retrieves the newest transactions of many products with
+// a single query, at most perProduct of each, keyed by product ID. Each
+// product reads only its newest rows from the keyset index.
+func (r *PostgresTransactionRepository) RecentByProductIDs(ctx context.Context, productIDs []string, perProduct int) (map[string][]*domain.Transaction, error) {
+	transactions := make(map[string][]*domain.Transaction, len(productIDs))
+	if len(productIDs) == 0 || perProduct <= 0 {
+		return transactions, nil
+	}
+
+	query := `
+		SELECT t.id, t.inventory_id, t.product_id, t.type, t.quantity, t.reference, t.notes, t.created_at,
+			t.quantity_before, t.quantity_after, COALESCE(t.reason_code, ''), COALESCE(t.performed_by, ''), t.sequence, t.unit_cost
+		FROM unnest($1::varchar[]) AS p(id)
+		CROSS JOIN LATERAL (
+			SELECT * FROM transactions
+			WHERE product_id = p.id AND ($3 = '' OR tenant_id = $3)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		) t
+		ORDER BY t.product_id, t.created_at DESC, t.id DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(productIDs), perProduct, tenantScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
 	defer rows.Close()
 
+	for rows.Next() {
+		transaction := &domain.Transaction{}
+		if err := rows.Scan(
+			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
+			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions[transaction.ProductID] = append(transactions[transaction.ProductID], transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// Search retrieves the transactions matching the filter, newest first. With
+// a cursor it returns the transactions after it and ignores offset; the
+// keyset condition keeps deep pages as cheap as the first.
+func (r *PostgresTransactionRepository) Search(ctx context.Context, filter TransactionFilter, after *domain.TransactionCursor, limit, offset int) ([]*domain.Transaction, error) {
+	var afterTime sql.NullTime
+	var afterID string
+	if after != nil {
+		afterTime = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = after.ID
+		offset = 0
+	}
+
+	condition, args, err := filter.condition(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n := len(args)
+	query := `
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
+		FROM ` + filter.table() + `
+		WHERE ` + condition + fmt.Sprintf(`
+			AND ($%d::timestamptz IS NULL OR (created_at, id) < ($%d, $%d))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, n+1, n+1, n+2, n+3, n+4)
+
+	args = append(args, afterTime, afterID, limit, offset)
+	rows, err := reader(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	defer rows.Close()
+
 	var transactions []*domain.Transaction
 	for rows.Next() {
 		transaction := &domain.Transaction{}
 		if err := rows.Scan(
 			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
 			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -139,16 +283,67 @@ func (r *PostgresTransactionRepository) GetByProductID(ctx context.Context, prod
 	return transactions, nil
 }
 
+// CountMatching returns the number of transactions matching the filter
+func (r *PostgresTransactionRepository) CountMatching(ctx context.Context, filter TransactionFilter) (int64, error) {
+	condition, args, err := filter.condition(ctx)
+	if err != nil {
+		return 0, err
+	}
+	query := `SELECT COUNT(*) FROM ` + filter.table() + ` WHERE ` + condition
+
+	var count int64
+	err = reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	return count, nil
+}
+
+// transactionFilterCondition selects the transactions matching the first
+// two query arguments, as returned by TransactionFilter.args. Empty values
+// match everything.
+const transactionFilterCondition = `($1 = '' OR product_id = $1)
+			AND ($2 = '' OR tenant_id = $2)`
+
+// condition returns the SQL condition selecting the transactions matching
+// the filter, scoped to the tenant of ctx, and its arguments: those of
+// transactionFilterCondition followed by the values of the expression
+func (f TransactionFilter) condition(ctx context.Context) (string, []any, error) {
+	expression, args, err := filterCondition(f.Expression, transactionFilterColumns, f.args(ctx))
+	if err != nil {
+		return "", nil, err
+	}
+	return transactionFilterCondition + `
+			AND ` + expression, args, nil
+}
+
+// table returns the table or view the filter selects from
+func (f TransactionFilter) table() string {
+	if f.IncludeArchived {
+		return "transactions_all"
+	}
+	return "transactions"
+}
+
+// args returns the filter, scoped to the tenant of ctx, as the arguments of
+// transactionFilterCondition
+func (f TransactionFilter) args(ctx context.Context) []any {
+	return []any{f.ProductID, tenantScope(ctx)}
+}
+
 // List retrieves a paginated list of transactions
 func (r *PostgresTransactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Transaction, error) {
 	query := `
-		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
 		FROM transactions
+		WHERE $3 = '' OR tenant_id = $3
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := reader(ctx, r.db, r.replica).QueryContext(ctx, query, limit, offset, tenantScope(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
@@ -160,6 +355,8 @@ func (r *PostgresTransactionRepository) List(ctx context.Context, limit, offset
 		if err := rows.Scan(
 			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
 			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -173,12 +370,111 @@ func (r *PostgresTransactionRepository) List(ctx context.Context, limit, offset
 	return transactions, nil
 }
 
+// GetByDateRange retrieves transactions created in [from, to), live or
+// archived, oldest first. With a cursor it returns those after it, so that
+// reading a long period page by page costs the same for every page.
+func (r *PostgresTransactionRepository) GetByDateRange(ctx context.Context, from, to time.Time, after *domain.TransactionCursor, limit int) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, COALESCE(reason_code, ''), COALESCE(performed_by, ''), sequence, unit_cost
+		FROM transactions_all
+		WHERE created_at >= $1 AND created_at < $2 AND ($6 = '' OR tenant_id = $6)
+			AND ($3::timestamptz IS NULL OR (created_at, id) > ($3, $4))
+		ORDER BY created_at ASC, id ASC
+		LIMIT $5
+	`
+
+	var afterTime sql.NullTime
+	var afterID string
+	if after != nil {
+		afterTime = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = after.ID
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to, afterTime, afterID, limit, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		transaction := &domain.Transaction{}
+		if err := rows.Scan(
+			&transaction.ID, &transaction.InventoryID, &transaction.ProductID, &transaction.Type,
+			&transaction.Quantity, &transaction.Reference, &transaction.Notes, &transaction.CreatedAt,
+			&transaction.QuantityBefore, &transaction.QuantityAfter, &transaction.ReasonCode, &transaction.PerformedBy,
+			&transaction.Sequence, &transaction.UnitCost,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// ReservedByReference returns the units of a product currently reserved under
+// a reference, i.e. its reservations minus its releases
+func (r *PostgresTransactionRepository) ReservedByReference(ctx context.Context, productID, reference string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(CASE type WHEN 'RESERVE' THEN quantity WHEN 'UNRESERVE' THEN -quantity ELSE 0 END), 0)
+		FROM transactions
+		WHERE product_id = $1 AND reference = $2 AND ($3 = '' OR tenant_id = $3)
+	`
+
+	var reserved int64
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, productID, reference, tenantScope(ctx)).Scan(&reserved)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum reservations: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// OutboundByInventory sums the units of a product that left each inventory
+// item through OUT transactions since the given time, keyed by inventory ID
+func (r *PostgresTransactionRepository) OutboundByInventory(ctx context.Context, productID string, since time.Time) (map[string]int64, error) {
+	query := `
+		SELECT inventory_id, SUM(quantity)
+		FROM transactions
+		WHERE product_id = $1 AND type = 'OUT' AND created_at >= $2 AND ($3 = '' OR tenant_id = $3)
+		GROUP BY inventory_id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, productID, since.UTC(), tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum outbound transactions: %w", err)
+	}
+	defer rows.Close()
+
+	outbound := make(map[string]int64)
+	for rows.Next() {
+		var inventoryID string
+		var quantity int64
+		if err := rows.Scan(&inventoryID, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan outbound transactions: %w", err)
+		}
+		outbound[inventoryID] = quantity
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbound transactions: %w", err)
+	}
+
+	return outbound, nil
+}
+
 // Count returns the total number of transactions
 func (r *PostgresTransactionRepository) Count(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM transactions`
+	query := `SELECT COUNT(*) FROM transactions WHERE $1 = '' OR tenant_id = $1`
 
 	var count int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := reader(ctx, r.db, r.replica).QueryRowContext(ctx, query, tenantScope(ctx)).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count transactions: %w", err)
 	}
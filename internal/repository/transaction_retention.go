@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// transactionPartitionPrefix names the monthly partitions of transactions,
+// followed by the month as YYYYMM
+const transactionPartitionPrefix = "transactions_p"
+
+// monthStart returns the first instant of t's month in UTC
+func monthStart(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// EnsurePartitions creates the missing monthly partitions of the transactions
+// table for the months from from through through, and returns how many it
+// created. A month whose rows already landed in the default partition cannot
+// get its own partition and fails.
+func (r *PostgresTransactionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) (int, error) {
+	existing, err := r.partitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for month := monthStart(from); !month.After(through); month = month.AddDate(0, 1, 0) {
+		name := transactionPartitionPrefix + month.Format("200601")
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		// DDL takes no parameters; the name and bounds are formatted from dates
+		query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF transactions FOR VALUES FROM ('%s') TO ('%s')`,
+			name, month.Format(time.DateOnly), month.AddDate(0, 1, 0).Format(time.DateOnly))
+		if _, err := r.db.ExecContext(ctx, query); err != nil {
+			return created, fmt.Errorf("failed to create partition %s: %w", name, err)
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// ArchiveBefore moves up to limit of the oldest transactions created before
+// the cutoff to transactions_archive, in one database transaction, and
+// returns how many it moved
+func (r *PostgresTransactionRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM transactions
+			WHERE (id, created_at) IN (
+				SELECT id, created_at FROM transactions
+				WHERE created_at < $1
+				ORDER BY created_at
+				LIMIT $2
+			)
+			RETURNING id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+				quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence, unit_cost
+		)
+		INSERT INTO transactions_archive (id, inventory_id, product_id, type, quantity, reference, notes, created_at,
+			quantity_before, quantity_after, reason_code, performed_by, tenant_id, sequence, unit_cost)
+		SELECT * FROM moved
+	`
+
+	result, err := r.db.ExecContext(ctx, query, cutoff.UTC(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive transactions: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// DropPartitionsBefore drops the empty monthly partitions that end at or
+// before the cutoff, i.e. those emptied by archiving, and returns their names
+func (r *PostgresTransactionRepository) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	existing, err := r.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for name, month := range existing {
+		if month.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+
+		var empty bool
+		if err := r.db.QueryRowContext(ctx, `SELECT NOT EXISTS (SELECT 1 FROM `+name+`)`).Scan(&empty); err != nil {
+			return dropped, fmt.Errorf("failed to check partition %s: %w", name, err)
+		}
+		if !empty {
+			continue
+		}
+		if _, err := r.db.ExecContext(ctx, `DROP TABLE `+name); err != nil {
+			return dropped, fmt.Errorf("failed to drop partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, nil
+}
+
+// partitions returns the monthly partitions of transactions by name, with
+// the month each covers; the default partition is left out
+func (r *PostgresTransactionRepository) partitions(ctx context.Context) (map[string]time.Time, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'transactions'::regclass
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transaction partitions: %w", err)
+	}
+	defer rows.Close()
+
+	partitions := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction partition: %w", err)
+		}
+		suffix, ok := strings.CutPrefix(name, transactionPartitionPrefix)
+		if !ok {
+			continue
+		}
+		if month, err := time.Parse("200601", suffix); err == nil {
+			partitions[name] = month
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transaction partitions: %w", err)
+	}
+
+	return partitions, nil
+}
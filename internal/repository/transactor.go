@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Transactor runs a unit of work: all repository writes made with the
+// context passed to fn are committed together, or rolled back together if
+// fn returns an error. Units of work nest; an inner one joins the outer.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// dbtx is what repositories need to run statements, implemented by both
+// *sql.DB and *sql.Tx
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// afterCommitKey holds the functions to run once the unit of work committed
+type afterCommitKey struct{}
+
+// conn returns the transaction of the unit of work ctx belongs to, or db
+// outside of one
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTransaction reports whether ctx belongs to a unit of work, of the
+// database or of a MemoryStore
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	_, memory := ctx.Value(memoryTxKey{}).(*memoryTx)
+	return ok || memory
+}
+
+// afterCommit runs fn once the unit of work of ctx committed, or right away
+// outside of one. fn does not run when the unit of work rolls back.
+func afterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
+// AfterCommit runs fn once the unit of work of ctx committed, or right away
+// outside of one, with a context that no longer belongs to the unit of work.
+// fn does not run when the unit of work rolls back.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if _, ok := ctx.Value(afterCommitKey{}).(*[]func()); !ok {
+		fn(ctx)
+		return
+	}
+	detached := context.WithValue(context.WithValue(context.WithValue(ctx,
+		txKey{}, nil), memoryTxKey{}, nil), afterCommitKey{}, nil)
+	afterCommit(ctx, func() { fn(detached) })
+}
+
+// WithCommitHooks returns ctx for a new unit of work, collecting the functions
+// registered with AfterCommit, and committed, which runs them. Transactors
+// call committed once the unit of work committed.
+func WithCommitHooks(ctx context.Context) (_ context.Context, committed func()) {
+	var hooks []func()
+	return context.WithValue(ctx, afterCommitKey{}, &hooks), func() {
+		for _, hook := range hooks {
+			hook()
+		}
+	}
+}
+
+// withinTransaction runs fn in the unit of work of ctx, or in a transaction
+// of its own outside of one
+func withinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx dbtx) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx, tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ctx, committed := WithCommitHooks(context.WithValue(ctx, txKey{}, tx))
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed()
+	return nil
+}
+
+// PostgresTransactor implements Transactor with PostgreSQL transactions
+type PostgresTransactor struct {
+	db *sql.DB
+}
+
+// NewPostgresTransactor creates a new PostgresTransactor
+func NewPostgresTransactor(db *sql.DB) *PostgresTransactor {
+	return &PostgresTransactor{db: db}
+}
+
+// WithinTransaction runs fn in one database transaction
+func (t *PostgresTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, t.db, func(ctx context.Context, _ dbtx) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/lib/pq"
+)
+
+// PostgresProductTranslationRepository implements ProductTranslationRepository using PostgreSQL
+type PostgresProductTranslationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresProductTranslationRepository creates a new PostgresProductTranslationRepository
+func NewPostgresProductTranslationRepository(db *sql.DB) *PostgresProductTranslationRepository {
+	return &PostgresProductTranslationRepository{db: db}
+}
+
+// Upsert creates or replaces the translation of a product for a locale
+func (r *PostgresProductTranslationRepository) Upsert(ctx context.Context, productID, locale string, translation domain.ProductTranslation) error {
+	if err := translation.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	query := `
+		INSERT INTO product_translations (product_id, locale, name, description, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id, locale)
+		DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, productID, locale, translation.Name, translation.Description, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save product translation: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the translation of a product for a locale
+func (r *PostgresProductTranslationRepository) Delete(ctx context.Context, productID, locale string) error {
+	query := `DELETE FROM product_translations WHERE product_id = $1 AND locale = $2`
+
+	result, err := r.db.ExecContext(ctx, query, productID, locale)
+	if err != nil {
+		return fmt.Errorf("failed to delete product translation: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("product translation %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetByProductIDs retrieves all translations for the given products in one
+// query, keyed by product ID and then locale
+func (r *PostgresProductTranslationRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string]map[string]domain.ProductTranslation, error) {
+	translations := make(map[string]map[string]domain.ProductTranslation)
+	if len(productIDs) == 0 {
+		return translations, nil
+	}
+
+	query := `
+		SELECT product_id, locale, COALESCE(name, ''), COALESCE(description, '')
+		FROM product_translations
+		WHERE product_id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product translations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID, locale string
+		var translation domain.ProductTranslation
+		if err := rows.Scan(&productID, &locale, &translation.Name, &translation.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan product translation: %w", err)
+		}
+		if translations[productID] == nil {
+			translations[productID] = make(map[string]domain.ProductTranslation)
+		}
+		translations[productID][locale] = translation
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product translations: %w", err)
+	}
+
+	return translations, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresUsageRepository implements UsageRepository using PostgreSQL
+type PostgresUsageRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresUsageRepository creates a new PostgresUsageRepository
+func NewPostgresUsageRepository(db *sql.DB) *PostgresUsageRepository {
+	return &PostgresUsageRepository{db: db}
+}
+
+// AddUsage adds the counts to the tenants' counters of the given day
+func (r *PostgresUsageRepository) AddUsage(ctx context.Context, day time.Time, usage []*domain.TenantUsage) error {
+	return withinTransaction(ctx, r.db, func(ctx context.Context, tx dbtx) error {
+		query := `
+			INSERT INTO tenant_usage (tenant, day, api_calls, transactions, transaction_units)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (tenant, day) DO UPDATE SET
+				api_calls = tenant_usage.api_calls + EXCLUDED.api_calls,
+				transactions = tenant_usage.transactions + EXCLUDED.transactions,
+				transaction_units = tenant_usage.transaction_units + EXCLUDED.transaction_units
+		`
+		for _, u := range usage {
+			if _, err := tx.ExecContext(ctx, query, u.Tenant, day, u.APICalls, u.Transactions, u.TransactionUnits); err != nil {
+				return fmt.Errorf("failed to add usage of %s: %w", u.Tenant, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Usage sums the counters of the days from from to to, inclusive, per tenant.
+// A non-empty tenant restricts the result to it.
+func (r *PostgresUsageRepository) Usage(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantUsage, error) {
+	query := `
+		SELECT tenant, SUM(api_calls), SUM(transactions), SUM(transaction_units)
+		FROM tenant_usage
+		WHERE day BETWEEN $1 AND $2 AND ($3 = '' OR tenant = $3)
+		GROUP BY tenant
+		ORDER BY tenant
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, from, to, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []*domain.TenantUsage
+	for rows.Next() {
+		u := &domain.TenantUsage{}
+		if err := rows.Scan(&u.Tenant, &u.APICalls, &u.Transactions, &u.TransactionUnits); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// CountStoredSKUs counts the products that are not deleted
+func (r *PostgresUsageRepository) CountStoredSKUs(ctx context.Context) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count stored SKUs: %w", err)
+	}
+	return count, nil
+}
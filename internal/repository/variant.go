@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// PostgresVariantRepository implements VariantRepository using PostgreSQL
+type PostgresVariantRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresVariantRepository creates a new PostgresVariantRepository
+func NewPostgresVariantRepository(db *sql.DB) *PostgresVariantRepository {
+	return &PostgresVariantRepository{db: db}
+}
+
+// Create links the product of a variant to its parent. It returns
+// ErrDuplicateVariant when the parent already has a variant with the same
+// attributes.
+func (r *PostgresVariantRepository) Create(ctx context.Context, variant *domain.ProductVariant) error {
+	attributes, err := json.Marshal(variant.Attributes)
+	if err != nil {
+		return fmt.Errorf("failed to encode variant attributes: %w", err)
+	}
+	variant.CreatedAt = time.Now()
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO product_variants (product_id, parent_id, tenant_id, attributes, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, variant.ProductID, variant.ParentID, tenantOwner(ctx), attributes, variant.CreatedAt)
+	if isUniqueViolation(err, "product_variants_parent_id_attributes_key") {
+		return domain.ErrDuplicateVariant
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create product variant: %w", err)
+	}
+	return nil
+}
+
+// GetByProductID retrieves the variant a product is, without its stock. It
+// returns ErrNotFound when the product is not a variant.
+func (r *PostgresVariantRepository) GetByProductID(ctx context.Context, productID string) (*domain.ProductVariant, error) {
+	variant := &domain.ProductVariant{}
+	var attributes []byte
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT v.product_id, v.parent_id, p.sku, p.name, p.price, v.attributes, p.deleted_at IS NOT NULL, v.created_at
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.product_id = $1 AND ($2 = '' OR v.tenant_id = $2)
+	`, productID, tenantScope(ctx)).Scan(
+		&variant.ProductID, &variant.ParentID, &variant.SKU, &variant.Name, &variant.Price,
+		&attributes, &variant.Archived, &variant.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product variant %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product variant: %w", err)
+	}
+	if err := json.Unmarshal(attributes, &variant.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to decode variant attributes: %w", err)
+	}
+	return variant, nil
+}
+
+// ListByParent lists the variants of a parent product with their stock in
+// every location and condition, oldest first
+func (r *PostgresVariantRepository) ListByParent(ctx context.Context, parentID string) ([]*domain.ProductVariant, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT v.product_id, v.parent_id, p.sku, p.name, p.price, v.attributes,
+			COALESCE(SUM(i.quantity), 0), COALESCE(SUM(i.reserved), 0),
+			p.deleted_at IS NOT NULL, v.created_at
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		LEFT JOIN inventory i ON i.product_id = v.product_id
+		WHERE v.parent_id = $1 AND ($2 = '' OR v.tenant_id = $2)
+		GROUP BY v.product_id, p.id
+		ORDER BY v.created_at, v.product_id
+	`, parentID, tenantScope(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product variants: %w", err)
+	}
+	defer rows.Close()
+
+	var variants []*domain.ProductVariant
+	for rows.Next() {
+		variant := &domain.ProductVariant{}
+		var attributes []byte
+		if err := rows.Scan(
+			&variant.ProductID, &variant.ParentID, &variant.SKU, &variant.Name, &variant.Price, &attributes,
+			&variant.Quantity, &variant.Reserved, &variant.Archived, &variant.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan product variant: %w", err)
+		}
+		if err := json.Unmarshal(attributes, &variant.Attributes); err != nil {
+			return nil, fmt.Errorf("failed to decode variant attributes: %w", err)
+		}
+		variant.Available = variant.Quantity - variant.Reserved
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
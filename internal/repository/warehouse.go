@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresWarehouseRepository implements WarehouseRepository using PostgreSQL
+type PostgresWarehouseRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWarehouseRepository creates a new PostgresWarehouseRepository
+func NewPostgresWarehouseRepository(db *sql.DB) *PostgresWarehouseRepository {
+	return &PostgresWarehouseRepository{db: db}
+}
+
+// Create inserts a new warehouse
+func (r *PostgresWarehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	warehouse.ID = uuid.New().String()
+	now := time.Now()
+	warehouse.CreatedAt = now
+	warehouse.UpdatedAt = now
+
+	query := `
+		INSERT INTO warehouses (id, code, name, address, timezone, cutoff_time, lead_time_days,
+			working_days, holidays, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		warehouse.ID, warehouse.Code, warehouse.Name, warehouse.Address,
+		warehouse.Timezone, warehouse.CutoffTime, leadTimeDays(warehouse),
+		calendarDays(warehouse.WorkingDays), calendarDays(warehouse.Holidays),
+		warehouse.CreatedAt, warehouse.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create warehouse: %w", err)
+	}
+
+	return nil
+}
+
+// Update saves the name, address, ship schedule and calendar of a warehouse
+func (r *PostgresWarehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	warehouse.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE warehouses
+		SET name = $1, address = $2, timezone = NULLIF($3, ''), cutoff_time = NULLIF($4, ''),
+			lead_time_days = $5, working_days = $6, holidays = $7, updated_at = $8
+		WHERE id = $9
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		warehouse.Name, warehouse.Address, warehouse.Timezone, warehouse.CutoffTime,
+		leadTimeDays(warehouse), calendarDays(warehouse.WorkingDays), calendarDays(warehouse.Holidays),
+		warehouse.UpdatedAt, warehouse.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update warehouse: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("warehouse %w", domain.ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a warehouse by ID
+func (r *PostgresWarehouseRepository) GetByID(ctx context.Context, id string) (*domain.Warehouse, error) {
+	query := `
+		SELECT id, code, name, COALESCE(address, ''),
+			COALESCE(timezone, ''), COALESCE(cutoff_time, ''), lead_time_days,
+			working_days, holidays::text[], created_at, updated_at
+		FROM warehouses WHERE id = $1
+	`
+
+	warehouse, err := scanWarehouse(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("warehouse %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	return warehouse, nil
+}
+
+// GetByCode retrieves a warehouse by code
+func (r *PostgresWarehouseRepository) GetByCode(ctx context.Context, code string) (*domain.Warehouse, error) {
+	query := `
+		SELECT id, code, name, COALESCE(address, ''),
+			COALESCE(timezone, ''), COALESCE(cutoff_time, ''), lead_time_days,
+			working_days, holidays::text[], created_at, updated_at
+		FROM warehouses WHERE code = $1
+	`
+
+	warehouse, err := scanWarehouse(r.db.QueryRowContext(ctx, query, code))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("warehouse %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	return warehouse, nil
+}
+
+// List retrieves a paginated list of warehouses ordered by code
+func (r *PostgresWarehouseRepository) List(ctx context.Context, limit, offset int) ([]*domain.Warehouse, error) {
+	query := `
+		SELECT id, code, name, COALESCE(address, ''),
+			COALESCE(timezone, ''), COALESCE(cutoff_time, ''), lead_time_days,
+			working_days, holidays::text[], created_at, updated_at
+		FROM warehouses
+		ORDER BY code ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouses: %w", err)
+	}
+	defer rows.Close()
+
+	var warehouses []*domain.Warehouse
+	for rows.Next() {
+		warehouse, err := scanWarehouse(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan warehouse: %w", err)
+		}
+		warehouses = append(warehouses, warehouse)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating warehouses: %w", err)
+	}
+
+	return warehouses, nil
+}
+
+// scanWarehouse scans a warehouse row selected with its ship schedule
+func scanWarehouse(row interface{ Scan(...any) error }) (*domain.Warehouse, error) {
+	warehouse := &domain.Warehouse{}
+	var leadTime sql.NullInt64
+	if err := row.Scan(
+		&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Address,
+		&warehouse.Timezone, &warehouse.CutoffTime, &leadTime,
+		(*pq.StringArray)(&warehouse.WorkingDays), (*pq.StringArray)(&warehouse.Holidays),
+		&warehouse.CreatedAt, &warehouse.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if leadTime.Valid {
+		days := int(leadTime.Int64)
+		warehouse.LeadTimeDays = &days
+	}
+	return warehouse, nil
+}
+
+// leadTimeDays returns the lead time of a warehouse as a nullable column value
+func leadTimeDays(warehouse *domain.Warehouse) sql.NullInt64 {
+	if warehouse.LeadTimeDays == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*warehouse.LeadTimeDays), Valid: true}
+}
+
+// calendarDays returns the working days or holidays of a warehouse as a
+// nullable array column value, NULL when the warehouse sets none
+func calendarDays(days []string) any {
+	if len(days) == 0 {
+		return nil
+	}
+	return pq.Array(days)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PostgresWMSRepository implements WMSRepository using PostgreSQL
+type PostgresWMSRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresWMSRepository creates a new PostgresWMSRepository
+func NewPostgresWMSRepository(db *sql.DB) *PostgresWMSRepository {
+	return &PostgresWMSRepository{db: db}
+}
+
+// RecordEvent records an applied event. It returns ErrDuplicateWMSEvent when
+// the tenant already applied an event with the ID.
+func (r *PostgresWMSRepository) RecordEvent(ctx context.Context, event *domain.WMSEvent) error {
+	event.ScannedAt = event.ScannedAt.UTC()
+	event.ProcessedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO wms_events (tenant_id, event_id, type, product_id, location, quantity, status, scanned_at, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, tenantOwner(ctx), event.EventID, event.Type, event.ProductID, event.Location, event.Quantity, event.Status,
+		event.ScannedAt, event.ProcessedAt)
+	if isUniqueViolation(err, "wms_events_pkey") {
+		return domain.ErrDuplicateWMSEvent
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record WMS event: %w", err)
+	}
+	return nil
+}
+
+// GetEvent retrieves the event applied for an event ID
+func (r *PostgresWMSRepository) GetEvent(ctx context.Context, eventID string) (*domain.WMSEvent, error) {
+	event := &domain.WMSEvent{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT event_id, tenant_id, type, product_id, location, quantity, status, scanned_at, processed_at
+		FROM wms_events WHERE tenant_id = $1 AND event_id = $2
+	`, tenantOwner(ctx), eventID).Scan(
+		&event.EventID, &event.TenantID, &event.Type, &event.ProductID, &event.Location, &event.Quantity,
+		&event.Status, &event.ScannedAt, &event.ProcessedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("WMS event %w", domain.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WMS event: %w", err)
+	}
+	return event, nil
+}
+
+// DeadLetter records a message that could not be applied. A message
+// dead-lettered before, i.e. redelivered, is kept as first recorded.
+func (r *PostgresWMSRepository) DeadLetter(ctx context.Context, letter *domain.WMSDeadLetter) error {
+	letter.ID = uuid.New().String()
+	letter.CreatedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO wms_dead_letters (id, topic, partition, "offset", key, payload, event_id, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (topic, partition, "offset") DO NOTHING
+	`, letter.ID, letter.Topic, letter.Partition, letter.Offset, letter.Key, letter.Payload, letter.EventID,
+		letter.Error, letter.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters retrieves dead-lettered messages, newest first
+func (r *PostgresWMSRepository) ListDeadLetters(ctx context.Context, limit, offset int) ([]*domain.WMSDeadLetter, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, topic, partition, "offset", key, payload, event_id, error, created_at
+		FROM wms_dead_letters
+		ORDER BY created_at DESC, id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*domain.WMSDeadLetter
+	for rows.Next() {
+		letter := &domain.WMSDeadLetter{}
+		if err := rows.Scan(&letter.ID, &letter.Topic, &letter.Partition, &letter.Offset, &letter.Key,
+			&letter.Payload, &letter.EventID, &letter.Error, &letter.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating dead letters: %w", err)
+	}
+
+	return letters, nil
+}
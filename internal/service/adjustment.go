@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// adjustAttempts bounds how often an adjustment is retried when stock moves
+// between reading the quantity on record and writing the counted one
+const adjustAttempts = 3
+
+// AdjustStock sets the on-hand quantity of a product at a location (the
+// default location when empty) to a counted quantity, e.g. after a physical
+// audit, and records an ADJUSTMENT transaction with the quantities before and
+// after, the reason and who performed it. Unlike SetStockCount no version is
+// needed: the count wins over movements made while it is applied. The count
+// cannot go below the reserved units.
+func (s *InventoryService) AdjustStock(ctx context.Context, productID, location string, quantity int64, reason domain.AdjustmentReason, reference, performedBy string) (*domain.Transaction, error) {
+	if quantity < 0 {
+		return nil, domain.NewValidationError("quantity cannot be negative")
+	}
+	if _, err := domain.ParseAdjustmentReason(string(reason)); err != nil {
+		return nil, err
+	}
+	if performedBy == "" {
+		return nil, domain.NewValidationError("an operator identity is required to adjust stock")
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, err := s.resolveInventory(ctx, productID, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory: %w", err)
+		}
+		if quantity < current.Reserved {
+			return nil, domain.NewValidationError("quantity cannot be below the %d reserved units", current.Reserved)
+		}
+
+		item := *current
+		item.Quantity = quantity
+
+		before, after := current.Quantity, quantity
+		delta := after - before
+		transaction := &domain.Transaction{
+			InventoryID:    item.ID,
+			ProductID:      productID,
+			Type:           "ADJUSTMENT",
+			Quantity:       max(delta, -delta),
+			Reference:      reference,
+			Notes:          "Stock adjustment",
+			QuantityBefore: &before,
+			QuantityAfter:  &after,
+			ReasonCode:     reason,
+			PerformedBy:    performedBy,
+		}
+
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.inventoryRepo.Update(ctx, &item); err != nil {
+				return fmt.Errorf("failed to update inventory: %w", err)
+			}
+			if s.lotRepo != nil && delta < 0 {
+				consumptions, err := s.takeFromLots(ctx, item.ID, -delta, before)
+				if err != nil {
+					return err
+				}
+				if len(consumptions) > 0 {
+					transaction.Notes += " from " + domain.LotNotes(consumptions)
+				}
+			}
+			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+				return fmt.Errorf("failed to record transaction: %w", err)
+			}
+			return nil
+		})
+		if errors.Is(err, domain.ErrConflict) && attempt < adjustAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		whenCommitted(ctx, func(ctx context.Context) {
+			if s.availability != nil && delta != 0 {
+				s.availability.ApplyDelta(productID, delta, 0)
+			}
+			s.notifyTransactions(ctx, transaction)
+		})
+		return transaction, nil
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestAdjustStock(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Reserved: 10, Location: "WH-A"})
+
+	transaction, err := service.AdjustStock(ctx, "prod-1", "WH-A", 46, domain.AdjustCycleCount, "CC-2024-03", "alice")
+	if err != nil {
+		t.Fatalf("AdjustStock() error = %v", err)
+	}
+	if transaction.Type != "ADJUSTMENT" || transaction.Quantity != 4 {
+		t.Errorf("Expected an ADJUSTMENT of 4, got %s of %d", transaction.Type, transaction.Quantity)
+	}
+	if *transaction.QuantityBefore != 50 || *transaction.QuantityAfter != 46 || transaction.PerformedBy != "alice" {
+		t.Errorf("Expected 50 -> 46 by alice, got %d -> %d by %s", *transaction.QuantityBefore, *transaction.QuantityAfter, transaction.PerformedBy)
+	}
+	if err := transaction.Validate(); err != nil {
+		t.Errorf("Expected the recorded transaction to be valid, got %v", err)
+	}
+
+	item, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if item.Quantity != 46 || item.Reserved != 10 {
+		t.Errorf("Expected 46 on hand with 10 reserved, got %d/%d", item.Quantity, item.Reserved)
+	}
+
+	// A count confirming the quantity on record is still recorded
+	confirmed, err := service.AdjustStock(ctx, "prod-1", "", 46, domain.AdjustPhysicalAudit, "AUDIT-1", "bob")
+	if err != nil {
+		t.Fatalf("AdjustStock() error = %v", err)
+	}
+	if confirmed.Quantity != 0 || len(transactionRepo.transactions) != 2 {
+		t.Errorf("Expected a zero adjustment to be recorded, got %d with %d transactions", confirmed.Quantity, len(transactionRepo.transactions))
+	}
+
+	failures := []struct {
+		name      string
+		quantity  int64
+		reason    domain.AdjustmentReason
+		performer string
+	}{
+		{"Below reserved", 5, domain.AdjustDamage, "alice"},
+		{"Unknown reason", 40, "MISC", "alice"},
+		{"No operator", 40, domain.AdjustLoss, ""},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.AdjustStock(ctx, "prod-1", "WH-A", tt.quantity, tt.reason, "", tt.performer); !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// WithTransactionAnnotations enables appending notes to transactions after
+// the fact and lists them with the ledger
+func WithTransactionAnnotations(annotationRepo repository.TransactionAnnotationRepository) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.annotationRepo = annotationRepo
+	}
+}
+
+// AnnotateTransaction appends a note to an existing transaction, live or
+// archived, e.g. the outcome of investigating it. The transaction itself is
+// left unchanged.
+func (s *InventoryService) AnnotateTransaction(ctx context.Context, transactionID, note, createdBy string) (*domain.TransactionAnnotation, error) {
+	if s.annotationRepo == nil {
+		return nil, errors.New("transaction annotations are not enabled")
+	}
+
+	annotation := &domain.TransactionAnnotation{TransactionID: transactionID, Note: note, CreatedBy: createdBy}
+	if err := annotation.Validate(); err != nil {
+		return nil, err
+	}
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		return nil, fmt.Errorf("transaction %w", domain.ErrNotFound)
+	}
+
+	if err := s.annotationRepo.Create(ctx, annotation); err != nil {
+		return nil, fmt.Errorf("failed to annotate transaction: %w", err)
+	}
+	return annotation, nil
+}
+
+// attachAnnotations loads the annotations of the transactions with one query
+func (s *InventoryService) attachAnnotations(ctx context.Context, transactions []*domain.Transaction) error {
+	if s.annotationRepo == nil || len(transactions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+	annotations, err := s.annotationRepo.ListByTransactionIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list transaction annotations: %w", err)
+	}
+	for _, transaction := range transactions {
+		transaction.Annotations = annotations[transaction.ID]
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MockTransactionAnnotationRepository implements
+// TransactionAnnotationRepository interface
+type MockTransactionAnnotationRepository struct {
+	annotations []*domain.TransactionAnnotation
+}
+
+func (m *MockTransactionAnnotationRepository) Create(ctx context.Context, annotation *domain.TransactionAnnotation) error {
+	annotation.ID = fmt.Sprintf("annotation-%d", len(m.annotations)+1)
+	m.annotations = append(m.annotations, annotation)
+	return nil
+}
+
+func (m *MockTransactionAnnotationRepository) ListByTransactionIDs(ctx context.Context, transactionIDs []string) (map[string][]*domain.TransactionAnnotation, error) {
+	annotations := make(map[string][]*domain.TransactionAnnotation)
+	for _, annotation := range m.annotations {
+		annotations[annotation.TransactionID] = append(annotations[annotation.TransactionID], annotation)
+	}
+	return annotations, nil
+}
+
+func TestAnnotateTransaction(t *testing.T) {
+	ctx := context.Background()
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	annotationRepo := &MockTransactionAnnotationRepository{}
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithTransactionAnnotations(annotationRepo))
+
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Location: "Warehouse A"})
+	if err := service.RemoveStock(ctx, "prod-1", 5, "SHRINK-1"); err != nil {
+		t.Fatalf("Failed to remove stock: %v", err)
+	}
+
+	page, err := service.ListTransactions(ctx, repository.TransactionFilter{ProductID: "prod-1"}, domain.PageRequest{Limit: 10})
+	if err != nil || len(page.Items) != 1 {
+		t.Fatalf("Expected one transaction, got %v, %v", page, err)
+	}
+	original := *page.Items[0]
+
+	for _, note := range []string{"investigating", "investigated, shrinkage confirmed"} {
+		if _, err := service.AnnotateTransaction(ctx, original.ID, note, "auditor"); err != nil {
+			t.Fatalf("AnnotateTransaction() error = %v", err)
+		}
+	}
+
+	page, _ = service.ListTransactions(ctx, repository.TransactionFilter{ProductID: "prod-1"}, domain.PageRequest{Limit: 10})
+	annotated := page.Items[0]
+	if len(annotated.Annotations) != 2 || annotated.Annotations[1].Note != "investigated, shrinkage confirmed" || annotated.Annotations[1].CreatedBy != "auditor" {
+		t.Errorf("Expected both annotations in order, got %+v", annotated.Annotations)
+	}
+	if annotated.Quantity != original.Quantity || annotated.Reference != original.Reference || annotated.Notes != original.Notes {
+		t.Errorf("Expected the transaction unchanged, got %+v", annotated)
+	}
+
+	if _, err := service.AnnotateTransaction(ctx, "missing", "note", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing transaction, got %v", err)
+	}
+	if _, err := service.AnnotateTransaction(ctx, original.ID, "  ", ""); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected ErrValidation for an empty note, got %v", err)
+	}
+	if len(annotationRepo.annotations) != 2 {
+		t.Errorf("Expected rejected annotations not to be recorded, got %d", len(annotationRepo.annotations))
+	}
+}
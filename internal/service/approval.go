@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/httpclient"
+)
+
+var (
+	// ErrMovementRejected is returned when an approval validator rejects a stock movement
+	ErrMovementRejected = errors.New("stock movement rejected")
+	// ErrApprovalUnavailable is returned when a validator cannot be reached and the policy is fail-closed
+	ErrApprovalUnavailable = errors.New("stock movement approval unavailable")
+)
+
+// StockMovement describes a pending stock change sent to approval validators
+type StockMovement struct {
+	ProductID string                `json:"product_id"`
+	Location  string                `json:"location"`
+	Condition domain.StockCondition `json:"condition,omitempty"`
+	Type      string                `json:"type"`
+	Quantity  int64                 `json:"quantity"`
+	Reference string                `json:"reference"`
+	UnitCost  *float64              `json:"unit_cost,omitempty"`
+}
+
+// MovementValidator approves or rejects a stock movement before it is
+// committed. Implementations reject by returning an error wrapping
+// ErrMovementRejected; any other error means the validator was unavailable.
+type MovementValidator interface {
+	ValidateMovement(ctx context.Context, movement StockMovement) error
+}
+
+// ApprovalPolicy configures when validators are called and what happens when
+// one of them is unavailable
+type ApprovalPolicy struct {
+	// Threshold is the smallest removal that needs approval
+	Threshold int64
+	// Timeout bounds each validator call
+	Timeout time.Duration
+	// FailOpen lets movements through when a validator is unavailable;
+	// otherwise they are refused with ErrApprovalUnavailable
+	FailOpen bool
+}
+
+// WithMovementApproval calls the validators, in order, before committing stock
+// removals of at least policy.Threshold units
+func WithMovementApproval(policy ApprovalPolicy, validators ...MovementValidator) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.approvalPolicy = policy
+		s.validators = validators
+	}
+}
+
+// approveMovement runs the configured validators for a movement
+func (s *InventoryService) approveMovement(ctx context.Context, movement StockMovement) error {
+	if len(s.validators) == 0 || movement.Quantity < s.approvalPolicy.Threshold {
+		return nil
+	}
+
+	for _, validator := range s.validators {
+		err := s.callValidator(ctx, validator, movement)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrMovementRejected) {
+			return err
+		}
+		if s.approvalPolicy.FailOpen {
+			slog.WarnContext(ctx, "approval validator unavailable, allowing movement",
+				"type", movement.Type, "quantity", movement.Quantity, "product_id", movement.ProductID, "error", err)
+			continue
+		}
+		return fmt.Errorf("%w: %v", ErrApprovalUnavailable, err)
+	}
+
+	return nil
+}
+
+// callValidator calls one validator within the policy timeout
+func (s *InventoryService) callValidator(ctx context.Context, validator MovementValidator, movement StockMovement) error {
+	if s.approvalPolicy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.approvalPolicy.Timeout)
+		defer cancel()
+	}
+	return validator.ValidateMovement(ctx, movement)
+}
+
+// WebhookValidator approves stock movements by POSTing them as JSON to an
+// external endpoint. A 2xx response approves, 403 and 422 reject (the
+// response body is used as the reason), anything else counts as unavailable.
+type WebhookValidator struct {
+	url    string
+	client httpclient.Doer
+}
+
+// NewWebhookValidator creates a validator for the webhook URL
+func NewWebhookValidator(url string, client httpclient.Doer) *WebhookValidator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookValidator{url: url, client: client}
+}
+
+// ValidateMovement implements MovementValidator
+func (v *WebhookValidator) ValidateMovement(ctx context.Context, movement StockMovement) error {
+	payload, err := json.Marshal(movement)
+	if err != nil {
+		return fmt.Errorf("failed to encode movement: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build approval request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("approval webhook %s: %w", v.url, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnprocessableEntity:
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if text := strings.TrimSpace(string(reason)); text != "" {
+			return fmt.Errorf("%w: %s", ErrMovementRejected, text)
+		}
+		return ErrMovementRejected
+	default:
+		return fmt.Errorf("approval webhook %s returned status %d", v.url, resp.StatusCode)
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// stubValidator returns a fixed result and counts its calls
+type stubValidator struct {
+	err   error
+	calls int
+}
+
+func (v *stubValidator) ValidateMovement(ctx context.Context, movement StockMovement) error {
+	v.calls++
+	return v.err
+}
+
+func newApprovalTestService(policy ApprovalPolicy, validators ...MovementValidator) (*InventoryService, *MockInventoryRepository) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 500, Location: "WH-A"})
+
+	return NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithMovementApproval(policy, validators...)), inventoryRepo
+}
+
+func TestMovementApproval(t *testing.T) {
+	unavailable := errors.New("connection refused")
+
+	tests := []struct {
+		name      string
+		failOpen  bool
+		quantity  int64
+		result    error
+		wantErr   error
+		wantCalls int
+	}{
+		{"Below threshold skips validators", false, 10, ErrMovementRejected, nil, 0},
+		{"Approved", false, 200, nil, nil, 1},
+		{"Rejected", false, 200, ErrMovementRejected, ErrMovementRejected, 1},
+		{"Unavailable fail-closed", false, 200, unavailable, ErrApprovalUnavailable, 1},
+		{"Unavailable fail-open", true, 200, unavailable, nil, 1},
+		{"Rejected fail-open", true, 200, ErrMovementRejected, ErrMovementRejected, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &stubValidator{err: tt.result}
+			service, inventoryRepo := newApprovalTestService(ApprovalPolicy{Threshold: 100, FailOpen: tt.failOpen}, validator)
+
+			err := service.RemoveStock(context.Background(), "prod-1", tt.quantity, "SO-1")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Expected removal to succeed, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if validator.calls != tt.wantCalls {
+				t.Errorf("Expected %d validator calls, got %d", tt.wantCalls, validator.calls)
+			}
+
+			inventory, _ := inventoryRepo.GetByID(context.Background(), "inv-1")
+			wantQuantity := int64(500)
+			if tt.wantErr == nil {
+				wantQuantity -= tt.quantity
+			}
+			if inventory.Quantity != wantQuantity {
+				t.Errorf("Expected quantity %d, got %d", wantQuantity, inventory.Quantity)
+			}
+		})
+	}
+}
+
+func TestWebhookValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		delay    time.Duration
+		wantErr  bool
+		rejected bool
+	}{
+		{"Approved", http.StatusOK, 0, false, false},
+		{"Rejected", http.StatusForbidden, 0, true, true},
+		{"Server error", http.StatusBadGateway, 0, true, false},
+		{"Timeout", http.StatusOK, 200 * time.Millisecond, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				w.WriteHeader(tt.status)
+				w.Write([]byte("fraud score too high"))
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := NewWebhookValidator(server.URL, nil).ValidateMovement(ctx, StockMovement{ProductID: "prod-1", Type: "OUT", Quantity: 200})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, ErrMovementRejected) != tt.rejected {
+				t.Errorf("Expected rejected=%v, got %v", tt.rejected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// auditExportBatchSize is the number of ledger rows fetched per query during export
+const auditExportBatchSize = 1000
+
+// auditCSVHeader is the column layout of the exported ledger
+var auditCSVHeader = []string{
+	"id", "inventory_id", "product_id", "type", "quantity", "reference", "notes", "created_at",
+}
+
+// AuditExport is a ledger export together with its signed manifest
+type AuditExport struct {
+	CSV      []byte
+	Manifest *domain.AuditManifest
+}
+
+// AuditService exports the transaction ledger for external auditors
+type AuditService struct {
+	transactionRepo repository.TransactionRepository
+	manifestRepo    repository.AuditManifestRepository
+	signingKey      ed25519.PrivateKey
+	maxRows         int64
+}
+
+// NewAuditService creates a new AuditService that signs exports with the
+// given key and keeps their manifests in manifestRepo. Exports of periods
+// with more than maxRows transactions are refused; zero allows any number.
+func NewAuditService(transactionRepo repository.TransactionRepository, manifestRepo repository.AuditManifestRepository, signingKey ed25519.PrivateKey, maxRows int64) *AuditService {
+	return &AuditService{
+		transactionRepo: transactionRepo,
+		manifestRepo:    manifestRepo,
+		signingKey:      signingKey,
+		maxRows:         maxRows,
+	}
+}
+
+// PublicKey returns the key auditors use to verify export signatures
+func (s *AuditService) PublicKey() ed25519.PublicKey {
+	return s.signingKey.Public().(ed25519.PublicKey)
+}
+
+// ExportLedger renders all transactions created in [from, to) as CSV and
+// signs a manifest containing the file's SHA-256 digest. The CSV is held in
+// memory; StreamLedger writes it out instead.
+func (s *AuditService) ExportLedger(ctx context.Context, from, to time.Time) (*AuditExport, error) {
+	var buf bytes.Buffer
+	manifest, err := s.StreamLedger(ctx, from, to, &buf, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditExport{CSV: buf.Bytes(), Manifest: manifest}, nil
+}
+
+// ExportJob returns a ledger export as a job. The job summary is the signed
+// manifest; the CSV is its result, which is stored whole.
+func (s *AuditService) ExportJob(from, to time.Time) JobFunc {
+	return func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error) {
+		var buf bytes.Buffer
+		manifest, err := s.StreamLedger(ctx, from, to, &buf, progress)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.JobResult{Summary: manifest, ContentType: "text/csv", Data: buf.Bytes()}, nil
+	}
+}
+
+// StreamLedger writes the transactions created in [from, to) to w as CSV
+// and returns the manifest signed over what was written, which is stored
+// under its ID once the export is complete. The ledger is read
+// a batch at a time after a cursor and hashed as it is written, so memory use
+// does not grow with the period. The period is counted first and refused
+// before anything is written when it exceeds the row limit; an error of w,
+// such as a client gone away, or a cancelled ctx stops the export.
+func (s *AuditService) StreamLedger(ctx context.Context, from, to time.Time, w io.Writer, progress ProgressFunc) (*domain.AuditManifest, error) {
+	if !from.Before(to) {
+		return nil, domain.NewValidationError("export period start must be before its end")
+	}
+	total, err := s.transactionRepo.CountMatching(ctx, repository.TransactionFilter{
+		Expression:      domain.FilterPeriod("created_at", from, to),
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ledger: %w", err)
+	}
+	if s.maxRows > 0 && total > s.maxRows {
+		return nil, domain.NewValidationError("the export period has %d transactions, more than the limit of %d: export a shorter period", total, s.maxRows)
+	}
+
+	digest := sha256.New()
+	writer := csv.NewWriter(io.MultiWriter(w, digest))
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return nil, fmt.Errorf("failed to write export header: %w", err)
+	}
+
+	rowCount := 0
+	var after *domain.TransactionCursor
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		transactions, err := s.transactionRepo.GetByDateRange(ctx, from, to, after, auditExportBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ledger: %w", err)
+		}
+
+		for _, t := range transactions {
+			record := []string{
+				t.ID, t.InventoryID, t.ProductID, t.Type,
+				strconv.FormatInt(t.Quantity, 10), t.Reference, t.Notes,
+				t.CreatedAt.UTC().Format(time.RFC3339Nano),
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, fmt.Errorf("failed to write export row: %w", err)
+			}
+		}
+		rowCount += len(transactions)
+		progress.report(int64(rowCount), max(total, int64(rowCount)))
+
+		if len(transactions) < auditExportBatchSize {
+			break
+		}
+		cursor := domain.NewTransactionCursor(transactions[len(transactions)-1])
+		after = &cursor
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	progress.report(int64(rowCount), int64(rowCount))
+
+	// The database keeps microseconds, and the stored manifest must verify
+	// like the one returned
+	manifest := &domain.AuditManifest{
+		ID:          uuid.New().String(),
+		From:        from.UTC(),
+		To:          to.UTC(),
+		GeneratedAt: time.Now().UTC().Truncate(time.Microsecond),
+		RowCount:    rowCount,
+		SHA256:      hex.EncodeToString(digest.Sum(nil)),
+		Algorithm:   "ed25519",
+		PublicKey:   base64.StdEncoding.EncodeToString(s.PublicKey()),
+	}
+	manifest.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.signingKey, manifest.SigningPayload()))
+	if err := s.manifestRepo.Create(ctx, manifest); err != nil {
+		return nil, fmt.Errorf("failed to store export manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// Manifest returns the manifest stored when the export with the given ID
+// was made
+func (s *AuditService) Manifest(ctx context.Context, id string) (*domain.AuditManifest, error) {
+	return s.manifestRepo.GetByID(ctx, id)
+}
+
+// VerifyExport checks that the CSV matches the manifest digest and that the
+// manifest signature is valid for the given public key
+func VerifyExport(csvData []byte, manifest *domain.AuditManifest, publicKey ed25519.PublicKey) error {
+	digest := sha256.Sum256(csvData)
+	if hex.EncodeToString(digest[:]) != manifest.SHA256 {
+		return errors.New("export content does not match manifest digest")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(manifest.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid manifest signature encoding: %w", err)
+	}
+
+	if !ed25519.Verify(publicKey, manifest.SigningPayload(), signature) {
+		return errors.New("manifest signature is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// ErrInvalidCredentials is returned for an unknown API key or a bearer token
+// that is malformed, wrongly signed or expired
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// APIKey is a static API key, the role it grants and the tenant it is bound
+// to, if any
+type APIKey struct {
+	Name   string
+	Role   domain.Role
+	Key    string
+	Tenant string
+}
+
+// ParseAPIKeys parses API keys given as name:role:key entries separated by
+// commas or newlines, as in the AUTH_API_KEYS variable or a keys file. A name
+// of the form name@tenant binds the key to the tenant. Blank entries and
+// lines starting with # are skipped.
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	entryNumber := 0
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(spec, ",", "\n")))
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		entryNumber++
+
+		// The entry itself is not echoed in errors: it may be a bare key
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid API key entry %d: expected name:role:key", entryNumber)
+		}
+		role, err := domain.ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("API key %s: %w", parts[0], err)
+		}
+		name, tenant, bound := strings.Cut(parts[0], "@")
+		if bound {
+			if err := domain.ValidateTenantID(tenant); err != nil {
+				return nil, fmt.Errorf("API key %s: %w", name, err)
+			}
+		}
+		keys = append(keys, APIKey{Name: name, Role: role, Key: parts[2], Tenant: tenant})
+	}
+	return keys, scanner.Err()
+}
+
+// AuthService authenticates callers by static API key or by HS256-signed JWT
+// bearer token carrying sub, role and exp claims and optionally the tenant
+// claim binding the caller to a tenant
+type AuthService struct {
+	keys      map[[sha256.Size]byte]APIKey
+	jwtSecret []byte
+	now       func() time.Time
+}
+
+// NewAuthService creates a new AuthService. Without keys and without a JWT
+// secret authentication is disabled and every request is let through.
+func NewAuthService(keys []APIKey, jwtSecret []byte) (*AuthService, error) {
+	s := &AuthService{
+		keys:      make(map[[sha256.Size]byte]APIKey, len(keys)),
+		jwtSecret: jwtSecret,
+		now:       time.Now,
+	}
+	for _, key := range keys {
+		digest := sha256.Sum256([]byte(key.Key))
+		if _, ok := s.keys[digest]; ok {
+			return nil, fmt.Errorf("API key %s is configured twice", key.Name)
+		}
+		s.keys[digest] = key
+	}
+	return s, nil
+}
+
+// Enabled reports whether any credentials are configured
+func (s *AuthService) Enabled() bool {
+	return len(s.keys) > 0 || len(s.jwtSecret) > 0
+}
+
+// AuthenticateAPIKey returns the principal of a static API key. Keys are
+// looked up by hash, so the comparison does not leak key prefixes.
+func (s *AuthService) AuthenticateAPIKey(key string) (*domain.Principal, error) {
+	apiKey, ok := s.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return &domain.Principal{Subject: apiKey.Name, Role: apiKey.Role, Method: "api_key", Tenant: apiKey.Tenant}, nil
+}
+
+// jwtHeader is the JOSE header of a bearer token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+// jwtClaims are the claims read from a bearer token
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	Tenant    string `json:"tenant"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+// AuthenticateJWT verifies an HS256 bearer token and returns its principal.
+// Tokens must carry an exp claim; other algorithms are rejected.
+func (s *AuthService) AuthenticateJWT(token string) (*domain.Principal, error) {
+	if len(s.jwtSecret) == 0 {
+		return nil, ErrInvalidCredentials
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCredentials)
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil || header.Algorithm != "HS256" {
+		return nil, fmt.Errorf("%w: unsupported token algorithm", ErrInvalidCredentials)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidCredentials)
+	}
+	mac := hmac.New(sha256.New, s.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidCredentials)
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidCredentials)
+	}
+
+	now := s.now().Unix()
+	if claims.ExpiresAt == 0 || now >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidCredentials)
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, fmt.Errorf("%w: token not yet valid", ErrInvalidCredentials)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	role, err := domain.ParseRole(claims.Role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	if claims.Tenant != "" {
+		if err := domain.ValidateTenantID(claims.Tenant); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+		}
+	}
+
+	return &domain.Principal{Subject: claims.Subject, Role: role, Method: "jwt", Tenant: claims.Tenant}, nil
+}
+
+// decodeJWTSegment decodes a base64url JSON segment of a token
+func decodeJWTSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
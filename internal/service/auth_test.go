@@ -0,0 +1,101 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// signJWT builds a token from raw header and claims JSON signed with secret
+func signJWT(header, claims string, secret []byte) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("erp:operator:k1:with:colons, # comment\n\ndashboard@acme:reader:k2")
+	if err != nil {
+		t.Fatalf("ParseAPIKeys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0].Key != "k1:with:colons" || keys[0].Role != domain.RoleOperator || keys[0].Tenant != "" {
+		t.Errorf("Unexpected keys: %+v", keys)
+	}
+	if keys[1].Name != "dashboard" || keys[1].Tenant != "acme" {
+		t.Errorf("Expected dashboard to be bound to acme, got %+v", keys[1])
+	}
+
+	for _, spec := range []string{"just-a-key", "erp:superuser:k1", ":reader:k1", "erp@Not_A_Tenant:reader:k1"} {
+		if _, err := ParseAPIKeys(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	auth, err := NewAuthService([]APIKey{{Name: "erp", Role: domain.RoleOperator, Key: "secret-key"}}, nil)
+	if err != nil {
+		t.Fatalf("NewAuthService() error = %v", err)
+	}
+
+	principal, err := auth.AuthenticateAPIKey("secret-key")
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey() error = %v", err)
+	}
+	if principal.Subject != "erp" || principal.Role != domain.RoleOperator {
+		t.Errorf("Unexpected principal: %+v", principal)
+	}
+	if _, err := auth.AuthenticateAPIKey("secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+
+	if _, err := NewAuthService([]APIKey{{Name: "a", Key: "k"}, {Name: "b", Key: "k"}}, nil); err == nil {
+		t.Error("Expected duplicate keys to be rejected")
+	}
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	secret := []byte("jwt-secret")
+	auth, _ := NewAuthService(nil, secret)
+	now := time.Unix(1700000000, 0)
+	auth.now = func() time.Time { return now }
+
+	hs256 := `{"alg":"HS256","typ":"JWT"}`
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"Valid", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1700000060}`, secret), false},
+		{"Expired", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1699999999}`, secret), true},
+		{"Missing exp", signJWT(hs256, `{"sub":"alice","role":"admin"}`, secret), true},
+		{"Not yet valid", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1700000060,"nbf":1700000030}`, secret), true},
+		{"Unknown role", signJWT(hs256, `{"sub":"alice","role":"root","exp":1700000060}`, secret), true},
+		{"Wrong secret", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1700000060}`, []byte("other")), true},
+		{"Algorithm none", signJWT(`{"alg":"none"}`, `{"sub":"alice","role":"admin","exp":1700000060}`, secret), true},
+		{"Malformed", "a.b", true},
+		{"Tenant", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1700000060,"tenant":"acme"}`, secret), false},
+		{"Invalid tenant", signJWT(hs256, `{"sub":"alice","role":"admin","exp":1700000060,"tenant":"ACME!"}`, secret), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := auth.AuthenticateJWT(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AuthenticateJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+			}
+			if !tt.wantErr && (principal.Subject != "alice" || principal.Role != domain.RoleAdmin) {
+				t.Errorf("Unexpected principal: %+v", principal)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// availabilityReconcileBatchSize is the number of inventory rows read per page during reconciliation
+const availabilityReconcileBatchSize = 500
+
+// availabilityEntry is the cached stock level of a product
+type availabilityEntry struct {
+	quantity int64
+	reserved int64
+	// tenantID owns the product; "" when not known, as for products
+	// without inventory
+	tenantID string
+}
+
+// available returns the non-reserved quantity, never negative
+func (e availabilityEntry) available() int64 {
+	if e.quantity < e.reserved {
+		return 0
+	}
+	return e.quantity - e.reserved
+}
+
+// AvailabilityCache keeps an in-memory copy of per-product stock levels,
+// summed over all locations. It is
+// updated write-through by InventoryService on every successful stock
+// operation and periodically reconciled against the database, which bounds
+// drift caused by writes from other instances.
+type AvailabilityCache struct {
+	inventoryRepo repository.InventoryRepository
+
+	mu          sync.RWMutex
+	entries     map[string]availabilityEntry
+	reconciling bool
+	touched     map[string]bool
+	lastSync    time.Time
+}
+
+// NewAvailabilityCache creates an empty AvailabilityCache
+func NewAvailabilityCache(inventoryRepo repository.InventoryRepository) *AvailabilityCache {
+	return &AvailabilityCache{
+		inventoryRepo: inventoryRepo,
+		entries:       make(map[string]availabilityEntry),
+	}
+}
+
+// Available returns the cached available quantity of a product. Products of
+// other tenants than the one of ctx are reported as not cached, and so is
+// every product when ctx must read after a given write.
+func (c *AvailabilityCache) Available(ctx context.Context, productID string) (int64, bool) {
+	if mustReadAfter(ctx) {
+		return 0, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[productID]
+	if scope := domain.TenantIDFromContext(ctx); ok && scope != "" && entry.tenantID != scope {
+		return 0, false
+	}
+	return entry.available(), ok
+}
+
+// Set stores the stock level of a product stocked at a single location
+func (c *AvailabilityCache) Set(item *domain.InventoryItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[item.ProductID] = availabilityEntry{quantity: item.Quantity, reserved: item.Reserved, tenantID: item.TenantID}
+	c.touch(item.ProductID)
+}
+
+// SetLevel stores the aggregated stock level of a product
+func (c *AvailabilityCache) SetLevel(level *domain.StockLevel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := availabilityEntry{quantity: level.Quantity, reserved: level.Reserved}
+	if len(level.Locations) > 0 {
+		entry.tenantID = level.Locations[0].TenantID
+	}
+	c.entries[level.ProductID] = entry
+	c.touch(level.ProductID)
+}
+
+// ApplyDelta applies a committed quantity change to a cached product. Products
+// that are not cached are left alone and loaded on their next read.
+func (c *AvailabilityCache) ApplyDelta(productID string, quantityDelta, reservedDelta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[productID]
+	if !ok {
+		return
+	}
+	entry.quantity += quantityDelta
+	entry.reserved += reservedDelta
+	c.entries[productID] = entry
+	c.touch(productID)
+}
+
+// Evict removes a product from the cache
+func (c *AvailabilityCache) Evict(productID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, productID)
+	c.touch(productID)
+}
+
+// touch marks a product as written during a running reconciliation, so the
+// reconciliation keeps the live value instead of its older database read.
+// Callers must hold the write lock.
+func (c *AvailabilityCache) touch(productID string) {
+	if c.reconciling {
+		c.touched[productID] = true
+	}
+}
+
+// LastSync returns when the cache was last reconciled with the database
+func (c *AvailabilityCache) LastSync() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastSync
+}
+
+// Reconcile reloads all stock levels from the database, summed over the
+// locations of each product, and replaces the cached values, except for
+// products written while the reload was running
+func (c *AvailabilityCache) Reconcile(ctx context.Context) error {
+	c.mu.Lock()
+	c.reconciling = true
+	c.touched = make(map[string]bool)
+	c.mu.Unlock()
+
+	fresh := make(map[string]availabilityEntry)
+	var loadErr error
+	for offset := 0; ; offset += availabilityReconcileBatchSize {
+		items, err := c.inventoryRepo.List(ctx, availabilityReconcileBatchSize, offset, nil)
+		if err != nil {
+			loadErr = fmt.Errorf("failed to load inventory: %w", err)
+			break
+		}
+		for _, item := range items {
+			entry := fresh[item.ProductID]
+			entry.quantity += item.Quantity
+			entry.reserved += item.Reserved
+			entry.tenantID = item.TenantID
+			fresh[item.ProductID] = entry
+		}
+		if len(items) < availabilityReconcileBatchSize {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if loadErr == nil {
+		for productID := range c.touched {
+			if entry, ok := c.entries[productID]; ok {
+				fresh[productID] = entry
+			} else {
+				delete(fresh, productID)
+			}
+		}
+		c.entries = fresh
+		c.lastSync = time.Now()
+	}
+	c.reconciling = false
+	c.touched = nil
+
+	return loadErr
+}
+
+// Run reconciles the cache every interval until ctx is cancelled
+func (c *AvailabilityCache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "availability cache reconciliation failed", "error", err)
+			}
+		}
+	}
+}
+
+// AvailabilityCheck is a single line of a bulk availability check. An empty
+// condition counts the stock in every condition.
+type AvailabilityCheck struct {
+	ProductID string                `json:"product_id"`
+	Condition domain.StockCondition `json:"condition,omitempty"`
+	Quantity  int64                 `json:"quantity"`
+}
+
+// AvailabilityResult reports whether a product can cover the requested
+// quantity and, with ship dates enabled, the local date it ships in full from
+// the ShipsFrom locations
+type AvailabilityResult struct {
+	ProductID         string                `json:"product_id"`
+	Condition         domain.StockCondition `json:"condition,omitempty"`
+	Requested         int64                 `json:"requested"`
+	Available         int64                 `json:"available"`
+	Sufficient        bool                  `json:"sufficient"`
+	EstimatedShipDate string                `json:"estimated_ship_date,omitempty"`
+	ShipsFrom         []string              `json:"ships_from,omitempty"`
+	ReleaseAt         *time.Time            `json:"release_at,omitempty"`
+	Error             string                `json:"error,omitempty"`
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestAvailabilityCacheWriteThrough(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 20, Location: "Warehouse A"})
+
+	cache := NewAvailabilityCache(inventoryRepo)
+	if err := cache.Reconcile(ctx); err != nil {
+		t.Fatalf("Failed to reconcile cache: %v", err)
+	}
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithAvailabilityCache(cache))
+
+	if err := service.ReserveStock(ctx, "prod-1", 5, "ORDER-001"); err != nil {
+		t.Fatalf("Failed to reserve stock: %v", err)
+	}
+	if err := service.RemoveStock(ctx, "prod-1", 3, "ORDER-002"); err != nil {
+		t.Fatalf("Failed to remove stock: %v", err)
+	}
+
+	available, ok := cache.Available(ctx, "prod-1")
+	if !ok || available != 12 {
+		t.Errorf("Expected cached availability 12, got %d (cached=%v)", available, ok)
+	}
+
+	// A change made behind the cache's back is only visible after reconciliation
+	inventoryRepo.items["inv-1"].Quantity = 100
+	results, err := service.CheckAvailability(ctx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 50}})
+	if err != nil {
+		t.Fatalf("Failed to check availability: %v", err)
+	}
+	if results[0].Sufficient {
+		t.Error("Expected check to be answered from the cache before reconciliation")
+	}
+
+	if err := cache.Reconcile(ctx); err != nil {
+		t.Fatalf("Failed to reconcile cache: %v", err)
+	}
+	results, _ = service.CheckAvailability(ctx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 50}})
+	if !results[0].Sufficient || results[0].Available != 95 {
+		t.Errorf("Expected 95 available after reconciliation, got %+v", results[0])
+	}
+}
+
+func TestCheckAvailabilityLoadsCacheMisses(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	ctx := context.Background()
+
+	cache := NewAvailabilityCache(inventoryRepo)
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithAvailabilityCache(cache))
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 8, Reserved: 2, Location: "Warehouse A"})
+
+	results, err := service.CheckAvailability(ctx, []AvailabilityCheck{
+		{ProductID: "prod-1", Quantity: 6},
+		{ProductID: "prod-1", Quantity: 7},
+	})
+	if err != nil {
+		t.Fatalf("Failed to check availability: %v", err)
+	}
+
+	if !results[0].Sufficient || results[1].Sufficient {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+	if _, ok := cache.Available(ctx, "prod-1"); !ok {
+		t.Error("Expected cache miss to populate the cache")
+	}
+}
+
+func TestAvailabilityCacheScopesTenants(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", TenantID: "acme", ProductID: "prod-1", Quantity: 5, Location: "Warehouse A"})
+
+	cache := NewAvailabilityCache(inventoryRepo)
+	if err := cache.Reconcile(ctx); err != nil {
+		t.Fatalf("Failed to reconcile cache: %v", err)
+	}
+
+	if available, ok := cache.Available(domain.WithTenantID(ctx, "acme"), "prod-1"); !ok || available != 5 {
+		t.Errorf("Expected 5 available to acme, got %d (cached=%v)", available, ok)
+	}
+	if _, ok := cache.Available(domain.WithTenantID(ctx, "globex"), "prod-1"); ok {
+		t.Error("Expected the product of acme not to be cached for globex")
+	}
+	if _, ok := cache.Available(ctx, "prod-1"); !ok {
+		t.Error("Expected unscoped reads to see every tenant")
+	}
+}
+
+func TestAvailabilityCacheReconcileSumsLocations(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 40, Location: "Warehouse A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-1", Quantity: 10, Reserved: 3, Location: "Warehouse B"})
+
+	cache := NewAvailabilityCache(inventoryRepo)
+	if err := cache.Reconcile(ctx); err != nil {
+		t.Fatalf("Failed to reconcile cache: %v", err)
+	}
+
+	if available, ok := cache.Available(ctx, "prod-1"); !ok || available != 47 {
+		t.Errorf("Expected cached availability 47, got %d (cached=%v)", available, ok)
+	}
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithAvailabilityCache(cache))
+	results, err := service.CheckAvailability(ctx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 45}})
+	if err != nil {
+		t.Fatalf("Failed to check availability: %v", err)
+	}
+	if !results[0].Sufficient {
+		t.Errorf("Expected 45 units to be available over both locations, got %+v", results[0])
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// BackgroundControl pauses and resumes background processing across every
+// instance, e.g. to quiesce background load during an incident or a
+// migration. Pauses are stored, and each instance refreshes them every
+// interval; a paused component finishes the work in progress and then waits
+// until it is resumed.
+type BackgroundControl struct {
+	repo repository.BackgroundControlRepository
+
+	mu     sync.Mutex
+	paused map[domain.BackgroundComponent]*domain.BackgroundPause
+	// changed is closed and replaced whenever the pauses change, waking the
+	// paused components
+	changed chan struct{}
+}
+
+// NewBackgroundControl creates a new BackgroundControl with every component
+// running until the pauses are refreshed
+func NewBackgroundControl(repo repository.BackgroundControlRepository) *BackgroundControl {
+	return &BackgroundControl{
+		repo:    repo,
+		paused:  map[domain.BackgroundComponent]*domain.BackgroundPause{},
+		changed: make(chan struct{}),
+	}
+}
+
+// Pause pauses the components, or every component when none are given, on
+// behalf of the operator
+func (c *BackgroundControl) Pause(ctx context.Context, names []string, pausedBy, reason string) ([]*domain.BackgroundStatus, error) {
+	components, err := domain.ParseBackgroundComponents(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		pause := &domain.BackgroundPause{Component: component, PausedBy: pausedBy, Reason: reason}
+		if err := c.repo.Pause(ctx, pause); err != nil {
+			return nil, err
+		}
+	}
+	return c.Status(ctx)
+}
+
+// Resume resumes the components, or every component when none are given
+func (c *BackgroundControl) Resume(ctx context.Context, names []string) ([]*domain.BackgroundStatus, error) {
+	components, err := domain.ParseBackgroundComponents(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		if err := c.repo.Resume(ctx, component); err != nil {
+			return nil, err
+		}
+	}
+	return c.Status(ctx)
+}
+
+// Status refreshes the pauses and reports whether each component is paused
+func (c *BackgroundControl) Status(ctx context.Context) ([]*domain.BackgroundStatus, error) {
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	statuses := make([]*domain.BackgroundStatus, len(domain.BackgroundComponents))
+	for i, component := range domain.BackgroundComponents {
+		statuses[i] = &domain.BackgroundStatus{Component: component}
+		if pause := c.paused[component]; pause != nil {
+			statuses[i].Paused = true
+			statuses[i].PausedBy = pause.PausedBy
+			statuses[i].Reason = pause.Reason
+			statuses[i].PausedAt = &pause.PausedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Refresh loads the pauses, waking the components resumed since the last
+// refresh
+func (c *BackgroundControl) Refresh(ctx context.Context) error {
+	pauses, err := c.repo.ListPauses(ctx)
+	if err != nil {
+		return err
+	}
+
+	paused := make(map[domain.BackgroundComponent]*domain.BackgroundPause, len(pauses))
+	for _, pause := range pauses {
+		paused[pause.Component] = pause
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changed := false
+	for component, pause := range paused {
+		if c.paused[component] == nil {
+			slog.InfoContext(ctx, "background processing paused", "component", component,
+				"paused_by", pause.PausedBy, "reason", pause.Reason)
+			changed = true
+		}
+	}
+	for component := range c.paused {
+		if paused[component] == nil {
+			slog.InfoContext(ctx, "background processing resumed", "component", component)
+			changed = true
+		}
+	}
+
+	c.paused = paused
+	if changed {
+		close(c.changed)
+		c.changed = make(chan struct{})
+	}
+	return nil
+}
+
+// Run refreshes the pauses every interval until ctx is cancelled, so pauses
+// and resumes made through any instance take effect on this one
+func (c *BackgroundControl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+				// The pauses refreshed last stay in effect
+				slog.ErrorContext(ctx, "failed to refresh background pauses", "error", err)
+			}
+		}
+	}
+}
+
+// Gate returns a context for the background loops of the component: they
+// wait in between their work while it is paused
+func (c *BackgroundControl) Gate(ctx context.Context, component domain.BackgroundComponent) context.Context {
+	return context.WithValue(ctx, backgroundGateKey{}, &backgroundGate{control: c, component: component})
+}
+
+// state reports whether the component is paused and the channel closed on
+// the next change of the pauses
+func (c *BackgroundControl) state(component domain.BackgroundComponent) (bool, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused[component] != nil, c.changed
+}
+
+type backgroundGateKey struct{}
+
+// backgroundGate is the component a background loop belongs to
+type backgroundGate struct {
+	control   *BackgroundControl
+	component domain.BackgroundComponent
+}
+
+// backgroundPaused reports whether the component of the background loop
+// running with ctx is paused. Loops without a component are never paused.
+func backgroundPaused(ctx context.Context) bool {
+	gate, _ := ctx.Value(backgroundGateKey{}).(*backgroundGate)
+	if gate == nil {
+		return false
+	}
+	paused, _ := gate.control.state(gate.component)
+	return paused
+}
+
+// awaitResumed waits while the component of the background loop running with
+// ctx is paused. It reports false if ctx was cancelled first.
+func awaitResumed(ctx context.Context) bool {
+	gate, _ := ctx.Value(backgroundGateKey{}).(*backgroundGate)
+	if gate == nil {
+		return ctx.Err() == nil
+	}
+
+	for {
+		paused, changed := gate.control.state(gate.component)
+		if !paused {
+			return ctx.Err() == nil
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-changed:
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockBackgroundControlRepository implements BackgroundControlRepository
+// interface
+type MockBackgroundControlRepository struct {
+	mu     sync.Mutex
+	pauses map[domain.BackgroundComponent]domain.BackgroundPause
+}
+
+func NewMockBackgroundControlRepository() *MockBackgroundControlRepository {
+	return &MockBackgroundControlRepository{pauses: map[domain.BackgroundComponent]domain.BackgroundPause{}}
+}
+
+func (m *MockBackgroundControlRepository) Pause(ctx context.Context, pause *domain.BackgroundPause) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.pauses[pause.Component]; ok {
+		*pause = existing
+		return nil
+	}
+	pause.PausedAt = time.Now()
+	m.pauses[pause.Component] = *pause
+	return nil
+}
+
+func (m *MockBackgroundControlRepository) Resume(ctx context.Context, component domain.BackgroundComponent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pauses, component)
+	return nil
+}
+
+func (m *MockBackgroundControlRepository) ListPauses(ctx context.Context) ([]*domain.BackgroundPause, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var pauses []*domain.BackgroundPause
+	for _, pause := range m.pauses {
+		pauses = append(pauses, &pause)
+	}
+	return pauses, nil
+}
+
+func TestBackgroundControl(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMockBackgroundControlRepository()
+	control := NewBackgroundControl(repo)
+	other := NewBackgroundControl(repo)
+
+	statuses, err := control.Pause(ctx, []string{"scheduler"}, "ops@example.com", "failover")
+	if err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if !statuses[0].Paused || statuses[0].PausedBy != "ops@example.com" || statuses[0].Reason != "failover" || statuses[1].Paused {
+		t.Errorf("Expected only the scheduler paused, got %+v, %+v", statuses[0], statuses[1])
+	}
+
+	// A second pause keeps the first one's reason
+	statuses, _ = control.Pause(ctx, nil, "other@example.com", "migration")
+	if statuses[0].Reason != "failover" || !statuses[1].Paused || statuses[1].Reason != "migration" {
+		t.Errorf("Unexpected statuses %+v, %+v", statuses[0], statuses[1])
+	}
+
+	if _, err := control.Pause(ctx, []string{"outbox"}, "", ""); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for an unknown component, got %v", err)
+	}
+
+	// Another instance waits once it has refreshed the pauses
+	if err := other.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	gated := other.Gate(ctx, domain.BackgroundConsumers)
+	if !backgroundPaused(gated) || backgroundPaused(ctx) {
+		t.Error("Expected only the gated context to be paused")
+	}
+	resumed := make(chan bool)
+	go func() { resumed <- awaitResumed(gated) }()
+
+	select {
+	case <-resumed:
+		t.Fatal("Expected the paused consumers to wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if _, err := control.Resume(ctx, []string{"consumers"}); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if err := other.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	select {
+	case ok := <-resumed:
+		if !ok {
+			t.Error("Expected awaitResumed to report the consumers resumed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumers to resume after the refresh")
+	}
+
+	// The scheduler is still paused; cancelling stops the wait
+	cancelled, cancel := context.WithCancel(other.Gate(ctx, domain.BackgroundScheduler))
+	cancel()
+	if awaitResumed(cancelled) {
+		t.Error("Expected awaitResumed to report a cancelled context")
+	}
+}
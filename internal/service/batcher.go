@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// batchWriteFunc writes a quantity change of an inventory row and the
+// transactions recording it in one unit of work
+type batchWriteFunc func(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, transactions []*domain.Transaction) error
+
+// batchKey identifies the pending deltas flushed together: those of one
+// inventory row queued under the same tenant scope
+type batchKey struct {
+	tenantID    string
+	inventoryID string
+}
+
+// pendingDelta is a quantity change waiting for the next batch flush, with
+// the transactions recording it
+type pendingDelta struct {
+	quantity     int64
+	reserved     int64
+	transactions []*domain.Transaction
+	done         chan error
+}
+
+// writeBatcher coalesces concurrent quantity deltas for the same inventory row
+// into a single UPDATE per time window, so very hot products do not serialize
+// every request on the row lock. The combined UPDATE and the transactions of
+// all its deltas are written in one unit of work.
+type writeBatcher struct {
+	write  batchWriteFunc
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[batchKey][]*pendingDelta
+}
+
+// newWriteBatcher creates a batcher that flushes each row window after its first pending delta
+func newWriteBatcher(write batchWriteFunc, window time.Duration) *writeBatcher {
+	return &writeBatcher{
+		write:   write,
+		window:  window,
+		pending: make(map[batchKey][]*pendingDelta),
+	}
+}
+
+// Apply queues a delta for the inventory row and blocks until its batch has
+// been written. A delta still waiting for its flush when ctx is done is
+// withdrawn and the caller gets the context error; once its flush has
+// started, Apply waits for it and reports its outcome, so that a delta
+// written is never reported as failed.
+func (b *writeBatcher) Apply(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, transactions ...*domain.Transaction) error {
+	key := batchKey{tenantID: domain.TenantIDFromContext(ctx), inventoryID: inventoryID}
+	delta := &pendingDelta{
+		quantity:     quantityDelta,
+		reserved:     reservedDelta,
+		transactions: transactions,
+		done:         make(chan error, 1),
+	}
+
+	b.mu.Lock()
+	first := len(b.pending[key]) == 0
+	b.pending[key] = append(b.pending[key], delta)
+	b.mu.Unlock()
+
+	if first {
+		time.AfterFunc(b.window, func() { b.flush(key) })
+	}
+
+	select {
+	case err := <-delta.done:
+		return err
+	case <-ctx.Done():
+		if b.withdraw(key, delta) {
+			return ctx.Err()
+		}
+		return <-delta.done
+	}
+}
+
+// withdraw removes a delta that has not been flushed yet from the pending
+// deltas of its row, and reports false if its flush has already started
+func (b *writeBatcher) withdraw(key batchKey, delta *pendingDelta) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch := b.pending[key]
+	i := slices.Index(batch, delta)
+	if i < 0 {
+		return false
+	}
+	if batch = slices.Delete(batch, i, i+1); len(batch) == 0 {
+		delete(b.pending, key)
+	} else {
+		b.pending[key] = batch
+	}
+	return true
+}
+
+// flush writes all pending deltas of a row under the tenant scope they were
+// queued with. The combined delta is applied in one UPDATE; if that is
+// rejected (e.g. the sum would oversell), each delta is retried on its own so
+// that one failing request cannot fail the whole batch.
+func (b *writeBatcher) flush(key batchKey) {
+	b.mu.Lock()
+	batch := b.pending[key]
+	delete(b.pending, key)
+	b.mu.Unlock()
+
+	ctx := context.Background()
+	if key.tenantID != "" {
+		ctx = domain.WithTenantID(ctx, key.tenantID)
+	}
+
+	if len(batch) > 1 {
+		var quantity, reserved int64
+		var transactions []*domain.Transaction
+		for _, delta := range batch {
+			quantity += delta.quantity
+			reserved += delta.reserved
+			transactions = append(transactions, delta.transactions...)
+		}
+
+		if err := b.write(ctx, key.inventoryID, quantity, reserved, transactions); err == nil {
+			for _, delta := range batch {
+				delta.done <- nil
+			}
+			return
+		}
+	}
+
+	for _, delta := range batch {
+		delta.done <- b.write(ctx, key.inventoryID, delta.quantity, delta.reserved, delta.transactions)
+	}
+}
+
+// depth returns the number of rows with pending deltas and the total number
+// of deltas waiting to be flushed
+func (b *writeBatcher) depth() (rows, deltas int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, batch := range b.pending {
+		deltas += len(batch)
+	}
+	return len(b.pending), deltas
+}
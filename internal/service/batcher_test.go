@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// countingInventoryRepository is a thread-safe inventory repository that
+// counts UpdateQuantity calls and enforces the non-negative constraints
+type countingInventoryRepository struct {
+	*MockInventoryRepository
+	mu      sync.Mutex
+	updates int
+}
+
+func (m *countingInventoryRepository) UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updates++
+
+	item := m.items[inventoryID]
+	if item.Quantity+quantityDelta < 0 || item.Quantity+quantityDelta < item.Reserved+reservedDelta {
+		return errors.New("quantity update failed: invalid operation or item not found")
+	}
+	item.Quantity += quantityDelta
+	item.Reserved += reservedDelta
+	return nil
+}
+
+// updateOnly writes batched deltas to repo without recording their transactions
+func updateOnly(repo repository.InventoryRepository) batchWriteFunc {
+	return func(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, _ []*domain.Transaction) error {
+		return repo.UpdateQuantity(ctx, inventoryID, quantityDelta, reservedDelta)
+	}
+}
+
+func TestWriteBatcherCoalescesConcurrentDeltas(t *testing.T) {
+	repo := &countingInventoryRepository{MockInventoryRepository: NewMockInventoryRepository()}
+	repo.items["inv-1"] = &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 100, Location: "Warehouse A"}
+
+	batcher := newWriteBatcher(updateOnly(repo), 20*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := batcher.Apply(context.Background(), "inv-1", -1, 0); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if repo.items["inv-1"].Quantity != 50 {
+		t.Errorf("Expected quantity 50, got %d", repo.items["inv-1"].Quantity)
+	}
+	if repo.updates >= 50 {
+		t.Errorf("Expected deltas to be coalesced, got %d updates", repo.updates)
+	}
+}
+
+func TestWriteBatcherIsolatesFailingDelta(t *testing.T) {
+	repo := &countingInventoryRepository{MockInventoryRepository: NewMockInventoryRepository()}
+	repo.items["inv-1"] = &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "Warehouse A"}
+
+	batcher := newWriteBatcher(updateOnly(repo), 20*time.Millisecond)
+
+	var wg sync.WaitGroup
+	results := make([]error, 2)
+	for i, delta := range []int64{-4, -20} {
+		wg.Add(1)
+		go func(i int, delta int64) {
+			defer wg.Done()
+			results[i] = batcher.Apply(context.Background(), "inv-1", delta, 0)
+		}(i, delta)
+	}
+	wg.Wait()
+
+	if results[0] != nil {
+		t.Errorf("Expected small removal to succeed, got %v", results[0])
+	}
+	if results[1] == nil {
+		t.Error("Expected oversized removal to fail")
+	}
+	if repo.items["inv-1"].Quantity != 6 {
+		t.Errorf("Expected quantity 6, got %d", repo.items["inv-1"].Quantity)
+	}
+}
+
+func TestWriteBatcherWithdrawsCancelledDelta(t *testing.T) {
+	repo := &countingInventoryRepository{MockInventoryRepository: NewMockInventoryRepository()}
+	repo.items["inv-1"] = &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "Warehouse A"}
+
+	batcher := newWriteBatcher(updateOnly(repo), time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := batcher.Apply(ctx, "inv-1", -4, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to end the wait, got %v", err)
+	}
+
+	if rows, deltas := batcher.depth(); rows != 0 || deltas != 0 {
+		t.Errorf("Expected the delta to be withdrawn, got %d rows and %d deltas pending", rows, deltas)
+	}
+	batcher.flush(batchKey{inventoryID: "inv-1"})
+	if repo.items["inv-1"].Quantity != 10 {
+		t.Errorf("Expected quantity 10, got %d", repo.items["inv-1"].Quantity)
+	}
+}
+
+func TestWriteBatcherReportsFlushOfCancelledDelta(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var written int64
+	batcher := newWriteBatcher(func(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, _ []*domain.Transaction) error {
+		close(started)
+		<-release
+		written += quantityDelta
+		return nil
+	}, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() { result <- batcher.Apply(ctx, "inv-1", -4, 0) }()
+
+	// Cancelled once its flush has started, the delta is written all the same
+	<-started
+	cancel()
+	close(release)
+	if err := <-result; err != nil {
+		t.Fatalf("Expected the written delta to be reported, got %v", err)
+	}
+	if written != -4 {
+		t.Errorf("Expected the delta written, got %d", written)
+	}
+}
+
+func TestAddStockWithWriteBatching(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithWriteBatching(time.Millisecond, "prod-1"),
+	)
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 5, Location: "Warehouse A"})
+
+	if err := service.AddStock(ctx, "prod-1", 3, "PO-001"); err != nil {
+		t.Fatalf("Failed to add stock: %v", err)
+	}
+
+	updated, _ := inventoryRepo.GetByProductID(ctx, "prod-1")
+	if updated.Quantity != 8 {
+		t.Errorf("Expected quantity 8, got %d", updated.Quantity)
+	}
+	if len(transactionRepo.transactions) != 1 {
+		t.Errorf("Expected the operation to record its transaction, got %d", len(transactionRepo.transactions))
+	}
+}
+
+func TestBatchedMovementUnitOfWork(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	transactor := NewMockTransactor(inventoryRepo, transactionRepo)
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo,
+		WithTransactor(transactor), WithWriteBatching(time.Millisecond))
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "Warehouse A"})
+
+	transactionRepo.failCreate = errors.New("connection reset")
+	if err := service.RemoveStock(ctx, "prod-1", 3, "ORD-1"); err == nil {
+		t.Fatal("Expected RemoveStock to fail when its transaction cannot be recorded")
+	}
+	if transactor.rollbacks != 1 {
+		t.Errorf("Expected the batch to roll back, got %d rollbacks", transactor.rollbacks)
+	}
+	if item, _ := inventoryRepo.GetByID(ctx, "inv-1"); item.Quantity != 10 {
+		t.Errorf("Expected quantity 10 after the rollback, got %d", item.Quantity)
+	}
+}
+
+func TestWriteBatchingWithMemoryStore(t *testing.T) {
+	store, err := repository.OpenMemoryStore("")
+	if err != nil {
+		t.Fatalf("Failed to open memory store: %v", err)
+	}
+	productRepo := repository.NewMemoryProductRepository(store)
+	inventoryRepo := repository.NewMemoryInventoryRepository(store)
+	transactionRepo := repository.NewMemoryTransactionRepository(store)
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(store), WithWriteBatching(5*time.Millisecond))
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Widget", SKU: "WID-1", Price: 1}
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := inventoryRepo.Create(ctx, &domain.InventoryItem{ProductID: product.ID, Quantity: 10, Location: "Warehouse A"}); err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := service.RemoveStock(ctx, product.ID, 1, "ORDER-1"); err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Batched removals did not complete")
+	}
+
+	item, _ := inventoryRepo.GetByProductID(ctx, product.ID)
+	if item.Quantity != 5 {
+		t.Errorf("Expected quantity 5, got %d", item.Quantity)
+	}
+	if transactions, _ := transactionRepo.GetByProductID(ctx, product.ID, 10, 0); len(transactions) != 5 {
+		t.Errorf("Expected 5 transactions, got %d", len(transactions))
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+const (
+	// MaxBillingReportDays bounds the period of a billing report
+	MaxBillingReportDays = 366
+
+	// billingMovementOverlap is how far before the latest recorded movement
+	// the ledger is scanned again, for transactions committed after later
+	// ones were recorded
+	billingMovementOverlap = time.Hour
+)
+
+// BillingService records the billable work of every tenant from the
+// transaction ledger and the stock on hand, for the 3PL operating the
+// system to invoice: receipts, picks and shipments as they happen and the
+// pallets stored once a day
+type BillingService struct {
+	billingRepo    repository.BillingRepository
+	unitsPerPallet int64
+}
+
+// NewBillingService creates a new BillingService. unitsPerPallet is how many
+// units of a product fit on one pallet.
+func NewBillingService(billingRepo repository.BillingRepository, unitsPerPallet int64) *BillingService {
+	return &BillingService{
+		billingRepo:    billingRepo,
+		unitsPerPallet: unitsPerPallet,
+	}
+}
+
+// BillingRun is the outcome of one recording of billing events
+type BillingRun struct {
+	Movements      int64 `json:"movements"`
+	StorageTenants int64 `json:"storage_tenants"`
+}
+
+// Record records the receipts, picks and shipments since the latest one
+// recorded and, unless recorded already, today's storage (UTC)
+func (s *BillingService) Record(ctx context.Context) (*BillingRun, error) {
+	now := time.Now()
+	latest, err := s.billingRepo.LatestMovementAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	since := latest
+	if !since.IsZero() {
+		since = since.Add(-billingMovementOverlap)
+	}
+
+	run := &BillingRun{}
+	if run.Movements, err = s.billingRepo.RecordMovements(ctx, since); err != nil {
+		return nil, err
+	}
+	if run.StorageTenants, err = s.billingRepo.RecordStorage(ctx, usageDay(now), s.unitsPerPallet); err != nil {
+		return nil, err
+	}
+
+	if run.Movements > 0 || run.StorageTenants > 0 {
+		slog.InfoContext(ctx, "billing events recorded", "movements", run.Movements, "storage_tenants", run.StorageTenants)
+	}
+	return run, nil
+}
+
+// Report totals the billing events of the days from from to to (UTC,
+// inclusive) per tenant, optionally only the given tenant. Events not
+// recorded yet are recorded first, so the report is complete up to now.
+func (s *BillingService) Report(ctx context.Context, tenant string, from, to time.Time) (*domain.BillingReport, error) {
+	from, to = usageDay(from), usageDay(to)
+	if to.Before(from) {
+		return nil, domain.NewValidationError("from must not be after to")
+	}
+	if to.Sub(from) >= MaxBillingReportDays*24*time.Hour {
+		return nil, domain.NewValidationError("billing reports cover at most %d days", MaxBillingReportDays)
+	}
+
+	if _, err := s.Record(ctx); err != nil {
+		return nil, err
+	}
+
+	tenants, err := s.billingRepo.Billing(ctx, tenant, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read billing: %w", err)
+	}
+	if tenants == nil {
+		tenants = []*domain.TenantBilling{}
+	}
+	return &domain.BillingReport{From: from, To: to, UnitsPerPallet: s.unitsPerPallet, Tenants: tenants}, nil
+}
+
+// Run records the billing events every interval until ctx is cancelled
+func (s *BillingService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if _, err := s.Record(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to record billing events", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockBillingRepository implements BillingRepository interface
+type MockBillingRepository struct {
+	latest         time.Time
+	since          []time.Time
+	storageDays    []time.Time
+	unitsPerPallet int64
+	billing        []*domain.TenantBilling
+	tenant         string
+}
+
+func (m *MockBillingRepository) LatestMovementAt(ctx context.Context) (time.Time, error) {
+	return m.latest, nil
+}
+
+func (m *MockBillingRepository) RecordMovements(ctx context.Context, since time.Time) (int64, error) {
+	m.since = append(m.since, since)
+	return 3, nil
+}
+
+func (m *MockBillingRepository) RecordStorage(ctx context.Context, day time.Time, unitsPerPallet int64) (int64, error) {
+	m.storageDays = append(m.storageDays, day)
+	m.unitsPerPallet = unitsPerPallet
+	return 1, nil
+}
+
+func (m *MockBillingRepository) Billing(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantBilling, error) {
+	m.tenant = tenant
+	return m.billing, nil
+}
+
+func TestBillingRecord(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockBillingRepository{}
+	service := NewBillingService(repo, 40)
+
+	// Nothing recorded yet: the whole ledger is scanned
+	run, err := service.Record(ctx)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if run.Movements != 3 || run.StorageTenants != 1 {
+		t.Errorf("Expected the recorded counts, got %+v", run)
+	}
+	if !repo.since[0].IsZero() {
+		t.Errorf("Expected the first scan to start at the beginning, got %v", repo.since[0])
+	}
+	if day := repo.storageDays[0]; day.Hour() != 0 || day.Location() != time.UTC || repo.unitsPerPallet != 40 {
+		t.Errorf("Expected storage of the UTC day at 40 units per pallet, got %v at %d", day, repo.unitsPerPallet)
+	}
+
+	// Later scans overlap the latest recorded movement
+	repo.latest = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if _, err := service.Record(ctx); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if want := repo.latest.Add(-billingMovementOverlap); !repo.since[1].Equal(want) {
+		t.Errorf("Expected the scan to start at %v, got %v", want, repo.since[1])
+	}
+}
+
+func TestBillingReport(t *testing.T) {
+	ctx := context.Background()
+	billing := &domain.TenantBilling{TenantID: "acme"}
+	billing.Add(domain.BillingEventReceipt, 2, 150)
+	billing.Add(domain.BillingEventStorage, 1, 12)
+	billing.Add(domain.BillingEventPick, 4, 9)
+	billing.Add(domain.BillingEventShipment, 3, 3)
+	repo := &MockBillingRepository{billing: []*domain.TenantBilling{billing}}
+	service := NewBillingService(repo, 100)
+
+	from := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	report, err := service.Report(ctx, "acme", from, to)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(repo.since) != 1 {
+		t.Errorf("Expected outstanding events to be recorded before reporting, got %d scans", len(repo.since))
+	}
+	if repo.tenant != "acme" || !report.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || report.UnitsPerPallet != 100 {
+		t.Errorf("Unexpected report %+v for tenant %q", report, repo.tenant)
+	}
+	want := domain.TenantBilling{TenantID: "acme", Receipts: 2, ReceivedUnits: 150, PalletDays: 12, Picks: 4, PickedUnits: 9, Shipments: 3}
+	if len(report.Tenants) != 1 || *report.Tenants[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Tenants)
+	}
+
+	if _, err := service.Report(ctx, "", to, from); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for from after to, got %v", err)
+	}
+	if _, err := service.Report(ctx, "", from.AddDate(-2, 0, 0), to); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a period over %d days, got %v", MaxBillingReportDays, err)
+	}
+}
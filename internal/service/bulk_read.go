@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// BulkReadService answers large reads of internal services, such as the
+// availability of thousands of SKUs for the search index. Each read is a
+// single query and so holds one database connection; at most concurrency
+// reads run at once and further reads wait for a slot, so bulk readers cannot
+// take over the connection pool the API depends on.
+type BulkReadService struct {
+	repo  repository.BulkReadRepository
+	slots chan struct{}
+}
+
+// NewBulkReadService creates a new BulkReadService running at most
+// concurrency reads at a time
+func NewBulkReadService(repo repository.BulkReadRepository, concurrency int) *BulkReadService {
+	return &BulkReadService{
+		repo:  repo,
+		slots: make(chan struct{}, max(concurrency, 1)),
+	}
+}
+
+// Availability returns the stock of each SKU summed over all its locations,
+// in the order of skus. Repeated SKUs are answered once; SKUs without an
+// active product are reported as missing.
+func (s *BulkReadService) Availability(ctx context.Context, skus []string) (*domain.BulkAvailability, error) {
+	unique, err := uniqueSKUs(skus)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.AvailabilityBySKUs(ctx, unique)
+	release()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read availability: %w", err)
+	}
+
+	bySKU := make(map[string]*domain.SKUAvailability, len(items))
+	for _, item := range items {
+		bySKU[item.SKU] = item
+	}
+
+	result := &domain.BulkAvailability{
+		Items:   make([]*domain.SKUAvailability, 0, len(items)),
+		Missing: []string{},
+	}
+	for _, sku := range unique {
+		if item, ok := bySKU[sku]; ok {
+			result.Items = append(result.Items, item)
+		} else {
+			result.Missing = append(result.Missing, sku)
+		}
+	}
+	return result, nil
+}
+
+// productExportPageSize is how many products a catalog export reads per query
+const productExportPageSize = 500
+
+// ExportProducts streams the catalog matching filter to emit, product by
+// product in ID order, with the stock at every location. Products are read a
+// page at a time, so memory use does not grow with the catalog; each page
+// takes a read slot of its own, so a long export shares the slots with other
+// bulk reads rather than holding one for its whole run. An error of emit,
+// such as a client gone away, stops the export and is returned.
+func (s *BulkReadService) ExportProducts(ctx context.Context, filter repository.ProductExportFilter, emit func(*domain.ProductExport) error) error {
+	afterID := ""
+	for {
+		release, err := s.acquire(ctx)
+		if err != nil {
+			return err
+		}
+		products, err := s.repo.ExportProducts(ctx, filter, afterID, productExportPageSize)
+		release()
+		if err != nil {
+			return fmt.Errorf("failed to export products: %w", err)
+		}
+
+		for _, product := range products {
+			if err := emit(product); err != nil {
+				return err
+			}
+		}
+		if len(products) < productExportPageSize {
+			return nil
+		}
+		afterID = products[len(products)-1].ProductID
+	}
+}
+
+// acquire waits for a read slot. The returned function gives it back.
+func (s *BulkReadService) acquire(ctx context.Context) (func(), error) {
+	select {
+	case s.slots <- struct{}{}:
+		return func() { <-s.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// uniqueSKUs validates a bulk read's SKUs and drops repeats, keeping the
+// first occurrence of each
+func uniqueSKUs(skus []string) ([]string, error) {
+	if len(skus) == 0 {
+		return nil, domain.NewValidationError("skus cannot be empty")
+	}
+	if len(skus) > domain.MaxBulkReadSKUs {
+		return nil, domain.NewValidationError("at most %d skus can be read at once", domain.MaxBulkReadSKUs)
+	}
+
+	seen := make(map[string]bool, len(skus))
+	unique := make([]string, 0, len(skus))
+	for i, sku := range skus {
+		if sku == "" {
+			return nil, domain.NewValidationError("sku %d cannot be empty", i+1)
+		}
+		if !seen[sku] {
+			seen[sku] = true
+			unique = append(unique, sku)
+		}
+	}
+	return unique, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MockBulkReadRepository implements BulkReadRepository interface for testing
+type MockBulkReadRepository struct {
+	stock    map[string]*domain.SKUAvailability
+	products []*domain.ProductExport
+	queried  [][]string
+	block    chan struct{}
+}
+
+func (m *MockBulkReadRepository) AvailabilityBySKUs(ctx context.Context, skus []string) ([]*domain.SKUAvailability, error) {
+	m.queried = append(m.queried, skus)
+	if m.block != nil {
+		<-m.block
+	}
+
+	var items []*domain.SKUAvailability
+	for _, sku := range skus {
+		if item, ok := m.stock[sku]; ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (m *MockBulkReadRepository) ExportProducts(ctx context.Context, filter repository.ProductExportFilter, afterID string, limit int) ([]*domain.ProductExport, error) {
+	m.queried = append(m.queried, []string{afterID})
+
+	var page []*domain.ProductExport
+	for _, product := range m.products {
+		if product.ProductID > afterID && (filter.Category == "" || product.Category == filter.Category) && len(page) < limit {
+			page = append(page, product)
+		}
+	}
+	return page, nil
+}
+
+func TestBulkReadAvailability(t *testing.T) {
+	repo := &MockBulkReadRepository{stock: map[string]*domain.SKUAvailability{
+		"LAP001": {SKU: "LAP001", ProductID: "prod-1", Quantity: 10, Reserved: 4, Available: 6},
+		"MOU001": {SKU: "MOU001", ProductID: "prod-2"},
+	}}
+	service := NewBulkReadService(repo, 2)
+	ctx := context.Background()
+
+	availability, err := service.Availability(ctx, []string{"MOU001", "GONE01", "LAP001", "MOU001"})
+	if err != nil {
+		t.Fatalf("Availability() error = %v", err)
+	}
+	if len(repo.queried) != 1 || len(repo.queried[0]) != 3 {
+		t.Errorf("Expected one query for the 3 distinct SKUs, got %v", repo.queried)
+	}
+	if len(availability.Items) != 2 || availability.Items[0].SKU != "MOU001" || availability.Items[1].Available != 6 {
+		t.Errorf("Expected MOU001 and LAP001 in request order, got %+v", availability.Items)
+	}
+	if fmt.Sprint(availability.Missing) != "[GONE01]" {
+		t.Errorf("Expected GONE01 to be missing, got %v", availability.Missing)
+	}
+
+	for _, skus := range [][]string{nil, {"LAP001", ""}, make([]string, domain.MaxBulkReadSKUs+1)} {
+		if _, err := service.Availability(ctx, skus); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Expected a validation error for %d skus, got %v", len(skus), err)
+		}
+	}
+}
+
+func TestBulkReadConcurrencyLimit(t *testing.T) {
+	repo := &MockBulkReadRepository{block: make(chan struct{})}
+	service := NewBulkReadService(repo, 1)
+
+	done := make(chan error)
+	go func() {
+		_, err := service.Availability(context.Background(), []string{"LAP001"})
+		done <- err
+	}()
+	for deadline := time.Now().Add(time.Second); len(service.slots) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("First read did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The only slot is taken, so a second read waits until its context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := service.Availability(ctx, []string{"MOU001"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the second read to wait for a slot, got %v", err)
+	}
+
+	close(repo.block)
+	if err := <-done; err != nil {
+		t.Errorf("First read failed: %v", err)
+	}
+	if _, err := service.Availability(context.Background(), []string{"MOU001"}); err != nil {
+		t.Errorf("Expected the slot to be given back, got %v", err)
+	}
+}
+
+func TestBulkReadExportProducts(t *testing.T) {
+	repo := &MockBulkReadRepository{}
+	for i := range productExportPageSize*2 + 1 {
+		category := "Electronics"
+		if i%2 == 1 {
+			category = "Office"
+		}
+		repo.products = append(repo.products, &domain.ProductExport{ProductID: fmt.Sprintf("prod-%04d", i), Category: category})
+	}
+	service := NewBulkReadService(repo, 1)
+	ctx := context.Background()
+
+	var exported []string
+	err := service.ExportProducts(ctx, repository.ProductExportFilter{}, func(product *domain.ProductExport) error {
+		exported = append(exported, product.ProductID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportProducts() error = %v", err)
+	}
+	if len(exported) != len(repo.products) || exported[len(exported)-1] != "prod-1000" {
+		t.Errorf("Expected all %d products in ID order, got %d", len(repo.products), len(exported))
+	}
+	if len(repo.queried) != 3 || repo.queried[1][0] != "prod-0499" {
+		t.Errorf("Expected 3 pages, each after the last product of the one before, got %v", repo.queried)
+	}
+	if len(service.slots) != 0 {
+		t.Error("Expected the export to give back its read slot")
+	}
+
+	// A failing consumer stops the export
+	repo.queried = nil
+	stop := errors.New("client gone")
+	err = service.ExportProducts(ctx, repository.ProductExportFilter{Category: "Office"}, func(product *domain.ProductExport) error {
+		return stop
+	})
+	if !errors.Is(err, stop) || len(repo.queried) != 1 {
+		t.Errorf("Expected the consumer's error after one page, got %v after %d pages", err, len(repo.queried))
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxCartHoldTTL is the longest a cart hold may keep stock
+const MaxCartHoldTTL = time.Hour
+
+// ErrCartHoldNotActive is returned when converting or releasing a cart hold
+// that was already converted, released or has expired
+var ErrCartHoldNotActive = errors.New("cart hold is no longer active")
+
+// CartHoldService keeps stock for checkouts in progress. Holds are cheaper
+// than reservations: creating one locks the inventory rows only to check
+// stock, and expiry needs no stock update. They only keep stock from other
+// holds; orders and reservations are not limited by them, so converting a
+// hold checks stock again.
+type CartHoldService struct {
+	inventory    *InventoryService
+	reservations *ReservationService
+	holdRepo     repository.CartHoldRepository
+	defaultTTL   time.Duration
+}
+
+// NewCartHoldService creates a new CartHoldService. Holds created without an
+// explicit TTL expire after defaultTTL.
+func NewCartHoldService(reservations *ReservationService, holdRepo repository.CartHoldRepository, defaultTTL time.Duration) *CartHoldService {
+	return &CartHoldService{
+		inventory:    reservations.inventory,
+		reservations: reservations,
+		holdRepo:     holdRepo,
+		defaultTTL:   defaultTTL,
+	}
+}
+
+// CreateHold holds the lines of a cart for ttl, or the service default when
+// ttl is zero. Lines without a location are held at the product's default
+// location. It fails with ErrInsufficientStock if any line does not fit.
+func (s *CartHoldService) CreateHold(ctx context.Context, reference string, lines []domain.CartHoldLine, ttl time.Duration) (*domain.CartHold, error) {
+	if ttl < 0 || ttl > MaxCartHoldTTL {
+		return nil, domain.NewValidationError("ttl must be between 0 and %s", MaxCartHoldTTL)
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+
+	hold := &domain.CartHold{
+		Reference: reference,
+		Status:    domain.CartHoldStatusHeld,
+		Lines:     lines,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := hold.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i := range hold.Lines {
+		line := &hold.Lines[i]
+		productID, err := s.inventory.ResolveSKU(ctx, line.SKU)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.inventory.checkReleased(ctx, productID); err != nil {
+			return nil, fmt.Errorf("%s: %w", line.SKU, err)
+		}
+		item, err := s.inventory.resolveInventory(ctx, productID, line.Location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory for %s: %w", line.SKU, err)
+		}
+		line.ProductID = productID
+		line.InventoryID = item.ID
+		line.Location = item.Location
+	}
+
+	if err := s.holdRepo.Create(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// GetHold retrieves a cart hold. A hold past its expiry time is reported as
+// expired even before the expiry run marked it.
+func (s *CartHoldService) GetHold(ctx context.Context, id string) (*domain.CartHold, error) {
+	hold, err := s.holdRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart hold: %w", err)
+	}
+	if hold.Status == domain.CartHoldStatusHeld && !hold.IsActive(time.Now()) {
+		hold.Status = domain.CartHoldStatusExpired
+	}
+	return hold, nil
+}
+
+// ConvertHold turns an active hold into one firm reservation per line under
+// the hold's reference, typically once the payment succeeded. Either every
+// line is reserved or none is; on failure the hold stays active.
+func (s *CartHoldService) ConvertHold(ctx context.Context, id string) (*domain.CartHold, error) {
+	hold, err := s.claim(ctx, id, domain.CartHoldStatusConverted)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range hold.Lines {
+		reservation, err := s.reservations.CreateReservation(ctx, line.ProductID, line.Location, "", line.Quantity, hold.Reference, 0)
+		if err != nil {
+			s.undoConversion(ctx, hold)
+			return nil, fmt.Errorf("failed to reserve %s: %w", line.SKU, err)
+		}
+		hold.Reservations = append(hold.Reservations, reservation)
+	}
+
+	return hold, nil
+}
+
+// ReleaseHold gives the stock of an active hold back, e.g. when the cart is
+// abandoned before the hold expires
+func (s *CartHoldService) ReleaseHold(ctx context.Context, id string) (*domain.CartHold, error) {
+	return s.claim(ctx, id, domain.CartHoldStatusReleased)
+}
+
+// claim moves an active hold to its final status
+func (s *CartHoldService) claim(ctx context.Context, id string, status domain.CartHoldStatus) (*domain.CartHold, error) {
+	hold, err := s.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status != domain.CartHoldStatusHeld {
+		return nil, fmt.Errorf("%w: cart hold is %s", ErrCartHoldNotActive, hold.Status)
+	}
+
+	claimed, err := s.holdRepo.Claim(ctx, id, status, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrCartHoldNotActive
+	}
+
+	hold.Status = status
+	hold.UpdatedAt = time.Now()
+	return hold, nil
+}
+
+// undoConversion releases the reservations made for a hold whose conversion
+// failed and makes the hold active again
+func (s *CartHoldService) undoConversion(ctx context.Context, hold *domain.CartHold) {
+	for _, reservation := range hold.Reservations {
+		if _, err := s.reservations.ReleaseReservation(ctx, reservation.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to release reservation of failed cart hold conversion",
+				"hold_id", hold.ID, "reservation_id", reservation.ID, "error", err)
+		}
+	}
+	hold.Reservations = nil
+
+	if _, err := s.holdRepo.UpdateStatus(ctx, hold.ID, domain.CartHoldStatusConverted, domain.CartHoldStatusHeld); err != nil {
+		slog.ErrorContext(ctx, "failed to restore cart hold", "hold_id", hold.ID, "error", err)
+	}
+	hold.Status = domain.CartHoldStatusHeld
+}
+
+// Run marks lapsed holds as expired every interval until ctx is cancelled.
+// Expired holds stop counting right away; the run only updates their status.
+func (s *CartHoldService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			count, err := s.holdRepo.ExpireHeld(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "cart hold expiry failed", "error", err)
+			}
+			if count > 0 {
+				slog.InfoContext(ctx, "expired cart holds", "count", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockCartHoldRepository implements CartHoldRepository interface for testing;
+// it checks holds against the unreserved stock of the inventory mock
+type MockCartHoldRepository struct {
+	inventory *MockInventoryRepository
+	holds     map[string]*domain.CartHold
+}
+
+func NewMockCartHoldRepository(inventory *MockInventoryRepository) *MockCartHoldRepository {
+	return &MockCartHoldRepository{inventory: inventory, holds: make(map[string]*domain.CartHold)}
+}
+
+func (m *MockCartHoldRepository) Create(ctx context.Context, hold *domain.CartHold) error {
+	now := time.Now()
+	needed := make(map[string]int64)
+	for _, line := range hold.Lines {
+		needed[line.InventoryID] += line.Quantity
+	}
+	for _, other := range m.holds {
+		if other.IsActive(now) {
+			for _, line := range other.Lines {
+				needed[line.InventoryID] += line.Quantity
+			}
+		}
+	}
+	for _, line := range hold.Lines {
+		if item, _ := m.inventory.GetByID(ctx, line.InventoryID); item.AvailableQuantity() < needed[line.InventoryID] {
+			return fmt.Errorf("%w to hold %s", domain.ErrInsufficientStock, line.SKU)
+		}
+	}
+
+	hold.ID = fmt.Sprintf("hold-%d", len(m.holds)+1)
+	stored := *hold
+	m.holds[hold.ID] = &stored
+	return nil
+}
+
+func (m *MockCartHoldRepository) GetByID(ctx context.Context, id string) (*domain.CartHold, error) {
+	if hold, ok := m.holds[id]; ok {
+		copied := *hold
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("cart hold %w", domain.ErrNotFound)
+}
+
+func (m *MockCartHoldRepository) Claim(ctx context.Context, id string, to domain.CartHoldStatus, now time.Time) (bool, error) {
+	hold, ok := m.holds[id]
+	if !ok || !hold.IsActive(now) {
+		return false, nil
+	}
+	hold.Status = to
+	return true, nil
+}
+
+func (m *MockCartHoldRepository) UpdateStatus(ctx context.Context, id string, from, to domain.CartHoldStatus) (bool, error) {
+	hold, ok := m.holds[id]
+	if !ok || hold.Status != from {
+		return false, nil
+	}
+	hold.Status = to
+	return true, nil
+}
+
+func (m *MockCartHoldRepository) ExpireHeld(ctx context.Context, now time.Time) (int64, error) {
+	var count int64
+	for _, hold := range m.holds {
+		if hold.Status == domain.CartHoldStatusHeld && !hold.IsActive(now) {
+			hold.Status = domain.CartHoldStatusExpired
+			count++
+		}
+	}
+	return count, nil
+}
+
+func setupCartHoldTest(t *testing.T) (*CartHoldService, *MockCartHoldRepository, *MockInventoryRepository) {
+	t.Helper()
+
+	reservations, _, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+	reservations.inventory.productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Controller", SKU: "CTL001", Price: 59})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-2", Quantity: 2, Location: "WH-A"})
+
+	holdRepo := NewMockCartHoldRepository(inventoryRepo)
+	return NewCartHoldService(reservations, holdRepo, 10*time.Minute), holdRepo, inventoryRepo
+}
+
+func TestCartHoldConvert(t *testing.T) {
+	service, _, inventoryRepo := setupCartHoldTest(t)
+	ctx := context.Background()
+
+	hold, err := service.CreateHold(ctx, "CART-1", []domain.CartHoldLine{
+		{SKU: "CON001", Quantity: 8},
+		{SKU: "CTL001", Quantity: 2},
+	}, 0)
+	if err != nil {
+		t.Fatalf("CreateHold() error = %v", err)
+	}
+	if hold.Lines[0].InventoryID != "inv-1" || hold.Lines[0].Location != "WH-A" {
+		t.Errorf("Expected lines resolved to their inventory, got %+v", hold.Lines[0])
+	}
+
+	// Holds leave the inventory untouched but keep their units from other holds
+	if inventory, _ := inventoryRepo.GetByID(ctx, "inv-1"); inventory.Reserved != 0 {
+		t.Errorf("Expected a hold not to reserve stock, got %d reserved", inventory.Reserved)
+	}
+	if _, err := service.CreateHold(ctx, "CART-2", []domain.CartHoldLine{{SKU: "CON001", Quantity: 3}}, 0); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock for a second hold, got %v", err)
+	}
+
+	converted, err := service.ConvertHold(ctx, hold.ID)
+	if err != nil {
+		t.Fatalf("ConvertHold() error = %v", err)
+	}
+	if converted.Status != domain.CartHoldStatusConverted || len(converted.Reservations) != 2 || converted.Reservations[0].Reference != "CART-1" {
+		t.Errorf("Unexpected conversion: %+v", converted)
+	}
+	if inventory, _ := inventoryRepo.GetByID(ctx, "inv-1"); inventory.Reserved != 8 {
+		t.Errorf("Expected 8 reserved after conversion, got %d", inventory.Reserved)
+	}
+
+	if _, err := service.ReleaseHold(ctx, hold.ID); !errors.Is(err, ErrCartHoldNotActive) {
+		t.Errorf("Expected ErrCartHoldNotActive releasing a converted hold, got %v", err)
+	}
+}
+
+func TestCartHoldConvertIsAllOrNothing(t *testing.T) {
+	service, _, inventoryRepo := setupCartHoldTest(t)
+	ctx := context.Background()
+
+	hold, err := service.CreateHold(ctx, "CART-1", []domain.CartHoldLine{
+		{SKU: "CON001", Quantity: 5},
+		{SKU: "CTL001", Quantity: 2},
+	}, 0)
+	if err != nil {
+		t.Fatalf("CreateHold() error = %v", err)
+	}
+
+	// Holds do not limit direct sales, so the conversion can run short
+	controller, _ := inventoryRepo.GetByID(ctx, "inv-2")
+	controller.Quantity = 1
+
+	if _, err := service.ConvertHold(ctx, hold.ID); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("Expected ErrInsufficientStock, got %v", err)
+	}
+	if inventory, _ := inventoryRepo.GetByID(ctx, "inv-1"); inventory.Reserved != 0 {
+		t.Errorf("Expected the first line's reservation to be released, got %d reserved", inventory.Reserved)
+	}
+	if got, _ := service.GetHold(ctx, hold.ID); got.Status != domain.CartHoldStatusHeld {
+		t.Errorf("Expected the hold to stay active, got %s", got.Status)
+	}
+}
+
+func TestCartHoldExpiry(t *testing.T) {
+	service, holdRepo, _ := setupCartHoldTest(t)
+	ctx := context.Background()
+
+	if _, err := service.CreateHold(ctx, "CART-1", []domain.CartHoldLine{{SKU: "CON001", Quantity: 10}}, 2*time.Hour); err == nil {
+		t.Error("Expected a TTL above the maximum to be rejected")
+	}
+
+	hold, err := service.CreateHold(ctx, "CART-1", []domain.CartHoldLine{{SKU: "CON001", Quantity: 10}}, time.Minute)
+	if err != nil {
+		t.Fatalf("CreateHold() error = %v", err)
+	}
+	holdRepo.holds[hold.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+	// A lapsed hold stops counting before the expiry run marks it
+	if got, _ := service.GetHold(ctx, hold.ID); got.Status != domain.CartHoldStatusExpired {
+		t.Errorf("Expected EXPIRED, got %s", got.Status)
+	}
+	if _, err := service.CreateHold(ctx, "CART-2", []domain.CartHoldLine{{SKU: "CON001", Quantity: 10}}, 0); err != nil {
+		t.Errorf("Expected the lapsed hold's stock to be free, got %v", err)
+	}
+	if _, err := service.ConvertHold(ctx, hold.ID); !errors.Is(err, ErrCartHoldNotActive) {
+		t.Errorf("Expected ErrCartHoldNotActive converting a lapsed hold, got %v", err)
+	}
+}
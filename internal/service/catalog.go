@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MaxCatalogEntries bounds the size of one catalog snapshot
+const MaxCatalogEntries = 10000
+
+// catalogPageSize is the number of products read per query when loading the catalog
+const catalogPageSize = 500
+
+// CatalogSyncOptions controls how a catalog snapshot is synced
+type CatalogSyncOptions struct {
+	// Apply writes the changes; otherwise only the diff is computed
+	Apply bool
+	// Location is where created products are stocked, with zero quantity.
+	// It is required when applying a diff that creates products.
+	Location string
+}
+
+// CatalogService keeps the product catalog aligned with an external source
+type CatalogService struct {
+	inventory *InventoryService
+}
+
+// NewCatalogService creates a new CatalogService
+func NewCatalogService(inventory *InventoryService) *CatalogService {
+	return &CatalogService{inventory: inventory}
+}
+
+// Sync compares a full catalog snapshot with the products by SKU: snapshot
+// entries without a product are creates, products whose fields differ are
+// updates and products missing from the snapshot are deletes. With
+// opts.Apply the changes are written one by one; a change that fails is
+// reported with its error and does not stop the others. Products that still
+// hold stock are never deleted. Archived products are matched like any other
+// but stay archived, and are not deleted again when missing.
+func (s *CatalogService) Sync(ctx context.Context, entries []domain.CatalogEntry, opts CatalogSyncOptions) (*domain.CatalogDiff, error) {
+	seen, err := checkCatalog(entries)
+	if err != nil {
+		return nil, err
+	}
+	return s.sync(ctx, entries, seen, opts, nil)
+}
+
+// SyncJob validates a catalog snapshot and returns its sync as a job. The
+// job summary counts the changes; the full diff is its result.
+func (s *CatalogService) SyncJob(entries []domain.CatalogEntry, opts CatalogSyncOptions) (JobFunc, error) {
+	seen, err := checkCatalog(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error) {
+		diff, err := s.sync(ctx, entries, seen, opts, progress)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(diff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode catalog diff: %w", err)
+		}
+
+		failed := 0
+		for _, changes := range [][]*domain.CatalogChange{diff.Creates, diff.Updates, diff.Deletes} {
+			for _, change := range changes {
+				if change.Error != "" {
+					failed++
+				}
+			}
+		}
+		summary := map[string]any{
+			"creates":   len(diff.Creates),
+			"updates":   len(diff.Updates),
+			"deletes":   len(diff.Deletes),
+			"unchanged": diff.Unchanged,
+			"failed":    failed,
+			"applied":   diff.Applied,
+		}
+		return &domain.JobResult{Summary: summary, ContentType: "application/json", Data: data}, nil
+	}, nil
+}
+
+// checkCatalog validates a snapshot and returns the set of its SKUs
+func checkCatalog(entries []domain.CatalogEntry) (map[string]bool, error) {
+	if len(entries) > MaxCatalogEntries {
+		return nil, domain.NewValidationError("catalog snapshot has more than %d products", MaxCatalogEntries)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		if err := entries[i].Product().Validate(); err != nil {
+			return nil, fmt.Errorf("product %d: %w", i+1, err)
+		}
+		if seen[entries[i].SKU] {
+			return nil, domain.NewValidationError("duplicate SKU %s in catalog snapshot", entries[i].SKU)
+		}
+		seen[entries[i].SKU] = true
+	}
+	return seen, nil
+}
+
+// sync diffs a validated snapshot and applies it on request, reporting the
+// applied changes as progress
+func (s *CatalogService) sync(ctx context.Context, entries []domain.CatalogEntry, seen map[string]bool, opts CatalogSyncOptions, progress ProgressFunc) (*domain.CatalogDiff, error) {
+	products, err := s.loadCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &domain.CatalogDiff{
+		Creates: []*domain.CatalogChange{},
+		Updates: []*domain.CatalogChange{},
+		Deletes: []*domain.CatalogChange{},
+	}
+	for i := range entries {
+		entry := &entries[i]
+		product, ok := products[entry.SKU]
+		if !ok {
+			diff.Creates = append(diff.Creates, &domain.CatalogChange{Action: domain.CatalogActionCreate, SKU: entry.SKU})
+			continue
+		}
+		if fields := entry.ChangedFields(product); len(fields) > 0 {
+			diff.Updates = append(diff.Updates, &domain.CatalogChange{Action: domain.CatalogActionUpdate, SKU: entry.SKU, ProductID: product.ID, Fields: fields})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for sku, product := range products {
+		if !seen[sku] && !product.Archived() {
+			diff.Deletes = append(diff.Deletes, &domain.CatalogChange{Action: domain.CatalogActionDelete, SKU: sku, ProductID: product.ID})
+		}
+	}
+	for _, changes := range [][]*domain.CatalogChange{diff.Creates, diff.Updates, diff.Deletes} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].SKU < changes[j].SKU })
+	}
+
+	if !opts.Apply {
+		return diff, nil
+	}
+	if len(diff.Creates) > 0 && opts.Location == "" {
+		return nil, domain.NewValidationError("location is required to create products")
+	}
+
+	bySKU := make(map[string]*domain.CatalogEntry, len(entries))
+	for i := range entries {
+		bySKU[entries[i].SKU] = &entries[i]
+	}
+	var done int64
+	total := int64(len(diff.Creates) + len(diff.Updates) + len(diff.Deletes))
+	progress.report(done, total)
+	for _, change := range diff.Creates {
+		product := bySKU[change.SKU].Product()
+		if err := s.inventory.CreateProduct(ctx, product, opts.Location, 0); err != nil {
+			change.Error = err.Error()
+		} else {
+			change.ProductID = product.ID
+		}
+		done++
+		progress.report(done, total)
+	}
+	for _, change := range diff.Updates {
+		product := bySKU[change.SKU].Product()
+		product.ID = change.ProductID
+		if err := s.inventory.UpdateProduct(ctx, product); err != nil {
+			change.Error = err.Error()
+		}
+		done++
+		progress.report(done, total)
+	}
+	for _, change := range diff.Deletes {
+		if err := s.deleteProduct(ctx, change.ProductID); err != nil {
+			change.Error = err.Error()
+		}
+		done++
+		progress.report(done, total)
+	}
+	diff.Applied = true
+
+	return diff, nil
+}
+
+// loadCatalog reads every product, archived ones included, keyed by SKU
+func (s *CatalogService) loadCatalog(ctx context.Context) (map[string]*domain.Product, error) {
+	products := make(map[string]*domain.Product)
+	for offset := 0; ; offset += catalogPageSize {
+		page, err := s.inventory.productRepo.List(ctx, catalogPageSize, offset, true, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %w", err)
+		}
+		for _, product := range page {
+			products[product.SKU] = product
+		}
+		if len(page) < catalogPageSize {
+			return products, nil
+		}
+	}
+}
+
+// deleteProduct deletes a product that no longer holds any stock
+func (s *CatalogService) deleteProduct(ctx context.Context, productID string) error {
+	items, err := s.inventory.ListStockByLocation(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	var onHand int64
+	for _, item := range items {
+		onHand += item.Quantity
+	}
+	if onHand > 0 {
+		return fmt.Errorf("product still holds %d units", onHand)
+	}
+
+	return s.inventory.DeleteProduct(ctx, productID)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestCatalogSync(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	service := NewCatalogService(NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository()))
+	ctx := context.Background()
+
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Mouse", SKU: "MOU001", Price: 25})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-3", Name: "Old cable", SKU: "CAB001", Price: 5})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-4", Name: "Old dock", SKU: "DOC001", Price: 90})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-4", ProductID: "prod-4", Quantity: 3, Location: "WH-A"})
+
+	snapshot := []domain.CatalogEntry{
+		{SKU: "LAP001", Name: "Laptop", Price: 1500},
+		{SKU: "MOU001", Name: "Wireless Mouse", Price: 29},
+		{SKU: "KEY001", Name: "Keyboard", Price: 49},
+	}
+
+	diff, err := service.Sync(ctx, snapshot, CatalogSyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if diff.Applied || len(diff.Creates) != 1 || len(diff.Updates) != 1 || len(diff.Deletes) != 2 || diff.Unchanged != 1 {
+		t.Fatalf("Unexpected diff: %+v", diff)
+	}
+	if fields := diff.Updates[0].Fields; len(fields) != 2 || fields[0] != "name" || fields[1] != "price" {
+		t.Errorf("Expected name and price to change, got %v", fields)
+	}
+	if len(productRepo.products) != 4 {
+		t.Fatalf("Expected a dry run to change nothing, got %d products", len(productRepo.products))
+	}
+
+	if _, err := service.Sync(ctx, snapshot, CatalogSyncOptions{Apply: true}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("Expected a validation error without a location, got %v", err)
+	}
+
+	diff, err = service.Sync(ctx, snapshot, CatalogSyncOptions{Apply: true, Location: "WH-A"})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if !diff.Applied || diff.Creates[0].ProductID == "" {
+		t.Errorf("Expected the diff to be applied, got %+v", diff)
+	}
+	if productRepo.products["prod-2"].Name != "Wireless Mouse" {
+		t.Errorf("Expected MOU001 to be renamed, got %q", productRepo.products["prod-2"].Name)
+	}
+	if !productRepo.products["prod-3"].Archived() {
+		t.Error("Expected CAB001 to be archived")
+	}
+	if productRepo.products["prod-4"].Archived() {
+		t.Error("Expected DOC001 to be kept while it holds stock")
+	}
+	for _, change := range diff.Deletes {
+		if change.SKU == "DOC001" && change.Error == "" {
+			t.Error("Expected the skipped delete of DOC001 to report an error")
+		}
+	}
+
+	// Archived products are not deleted again
+	diff, err = service.Sync(ctx, snapshot, CatalogSyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(diff.Creates) != 0 || len(diff.Deletes) != 1 || diff.Deletes[0].SKU != "DOC001" {
+		t.Errorf("Expected only DOC001 to remain a delete, got %+v", diff)
+	}
+}
+
+func TestCatalogSyncValidation(t *testing.T) {
+	service := NewCatalogService(NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository()))
+
+	tests := []struct {
+		name    string
+		entries []domain.CatalogEntry
+	}{
+		{"Missing name", []domain.CatalogEntry{{SKU: "LAP001", Price: 1}}},
+		{"Negative price", []domain.CatalogEntry{{SKU: "LAP001", Name: "Laptop", Price: -1}}},
+		{"Duplicate SKU", []domain.CatalogEntry{{SKU: "LAP001", Name: "Laptop"}, {SKU: "LAP001", Name: "Laptop"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Sync(context.Background(), tt.entries, CatalogSyncOptions{}); !errors.Is(err, domain.ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ChannelService manages sales channels and the share of each product's
+// stock they may hold reserved, so one channel cannot oversell into the
+// stock meant for another
+type ChannelService struct {
+	reservations *ReservationService
+	channelRepo  repository.SalesChannelRepository
+	rebalance    ChannelRebalancePolicy
+}
+
+// NewChannelService creates a new ChannelService. The rebalancing job sizes
+// fixed buckets by the rebalance policy.
+func NewChannelService(reservations *ReservationService, channelRepo repository.SalesChannelRepository, rebalance ChannelRebalancePolicy) *ChannelService {
+	return &ChannelService{
+		reservations: reservations,
+		channelRepo:  channelRepo,
+		rebalance:    rebalance,
+	}
+}
+
+// CreateChannel creates a sales channel
+func (s *ChannelService) CreateChannel(ctx context.Context, channel *domain.SalesChannel) error {
+	if err := channel.Validate(); err != nil {
+		return err
+	}
+	if err := s.channelRepo.Create(ctx, channel); err != nil {
+		return fmt.Errorf("failed to create sales channel: %w", err)
+	}
+	return nil
+}
+
+// GetChannel retrieves a sales channel by ID
+func (s *ChannelService) GetChannel(ctx context.Context, id string) (*domain.SalesChannel, error) {
+	channel, err := s.channelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sales channel: %w", err)
+	}
+	return channel, nil
+}
+
+// ListChannels lists all sales channels by name
+func (s *ChannelService) ListChannels(ctx context.Context) ([]*domain.SalesChannel, error) {
+	channels, err := s.channelRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales channels: %w", err)
+	}
+	return channels, nil
+}
+
+// SetAllocation creates or replaces the allocation of a product to a
+// channel. Lowering an allocation below what the channel holds reserved
+// keeps the reservations; the channel cannot reserve more until it is back
+// under its allocation. The change is recorded as a MANUAL allocation event.
+func (s *ChannelService) SetAllocation(ctx context.Context, allocation *domain.ChannelAllocation) error {
+	if err := allocation.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.GetChannel(ctx, allocation.ChannelID); err != nil {
+		return err
+	}
+	if _, err := s.reservations.inventory.requireProduct(ctx, allocation.ProductID); err != nil {
+		return err
+	}
+
+	_, err := s.changeAllocation(ctx, allocation.ChannelID, allocation.ProductID, allocation, domain.ChannelAllocationManual, "")
+	return err
+}
+
+// ListAllocations lists the allocations of a channel by product
+func (s *ChannelService) ListAllocations(ctx context.Context, channelID string) ([]*domain.ChannelAllocation, error) {
+	if _, err := s.GetChannel(ctx, channelID); err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.channelRepo.ListAllocations(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channel allocations: %w", err)
+	}
+	return allocations, nil
+}
+
+// DeleteAllocation removes the allocation of a product to a channel, which
+// lifts the channel's cap on the product
+func (s *ChannelService) DeleteAllocation(ctx context.Context, channelID, productID string) error {
+	if _, err := s.GetChannel(ctx, channelID); err != nil {
+		return err
+	}
+	_, err := s.changeAllocation(ctx, channelID, productID, nil, domain.ChannelAllocationManual, "")
+	return err
+}
+
+// ListAllocationEvents lists a page of the allocation changes of a channel,
+// newest first
+func (s *ChannelService) ListAllocationEvents(ctx context.Context, channelID string, limit, offset int) ([]*domain.ChannelAllocationEvent, error) {
+	if _, err := s.GetChannel(ctx, channelID); err != nil {
+		return nil, err
+	}
+
+	events, err := s.channelRepo.ListEvents(ctx, channelID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list channel allocation events: %w", err)
+	}
+	return events, nil
+}
+
+// changeAllocation sets the allocation of a product to a channel, or removes
+// it when next is nil, and records the change as an event in the same unit
+// of work
+func (s *ChannelService) changeAllocation(ctx context.Context, channelID, productID string, next *domain.ChannelAllocation, change domain.ChannelAllocationChange, notes string) (*domain.ChannelAllocationEvent, error) {
+	var event *domain.ChannelAllocationEvent
+	err := s.reservations.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		previous, err := s.channelRepo.GetAllocation(ctx, channelID, productID)
+		if err != nil {
+			return fmt.Errorf("failed to get channel allocation: %w", err)
+		}
+
+		if next != nil {
+			err = s.channelRepo.SetAllocation(ctx, next)
+		} else {
+			err = s.channelRepo.DeleteAllocation(ctx, channelID, productID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to change channel allocation: %w", err)
+		}
+
+		event = domain.NewChannelAllocationEvent(channelID, productID, change, previous, next, notes)
+		return s.channelRepo.RecordEvent(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "channel allocation changed",
+		"channel_id", channelID, "product_id", productID, "change", event.Change, "event_id", event.ID)
+	return event, nil
+}
+
+// Availability returns what a channel may still reserve of a product
+func (s *ChannelService) Availability(ctx context.Context, channelID, productID string) (*domain.ChannelAvailability, error) {
+	if _, err := s.GetChannel(ctx, channelID); err != nil {
+		return nil, err
+	}
+	return s.availability(ctx, channelID, productID)
+}
+
+// availability computes the channel availability of a product from its
+// current stock level, the channel's allocation and its pending reservations
+func (s *ChannelService) availability(ctx context.Context, channelID, productID string) (*domain.ChannelAvailability, error) {
+	level, err := s.reservations.inventory.stockLevel(ctx, productID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	releaseAt, err := s.reservations.inventory.releaseAt(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ChannelAvailability{ChannelID: channelID, ProductID: productID, Available: level.Available}
+	if releaseAt != nil {
+		// Stock of a scheduled drop is hidden until its release
+		result.Available = 0
+	}
+
+	reserved, err := s.reservations.reservationRepo.PendingByChannel(ctx, channelID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel reservations: %w", err)
+	}
+	result.Reserved = reserved
+
+	allocation, err := s.channelRepo.GetAllocation(ctx, channelID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel allocation: %w", err)
+	}
+	if allocation != nil {
+		allocated := allocation.Limit(level.Quantity)
+		result.Allocated = &allocated
+		result.Available = max(0, min(result.Available, allocated-reserved))
+	}
+
+	return result, nil
+}
+
+// Reserve reserves stock for an order placed through a channel. Reservations
+// beyond the channel's allocation fail with ErrInsufficientStock even when
+// the product has stock left for other channels. With product locks the
+// check and the reservation run under the product's lock.
+func (s *ChannelService) Reserve(ctx context.Context, channelID, productID, location string, condition domain.StockCondition, quantity int64, reference string, ttl time.Duration) (*domain.Reservation, error) {
+	channel, err := s.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if quantity <= 0 {
+		return nil, domain.NewValidationError("quantity must be positive")
+	}
+
+	inventory := s.reservations.inventory
+	var reservation *domain.Reservation
+	err = inventory.withProductLock(ctx, productID, "reserve", func(ctx context.Context) error {
+		available, err := s.availability(ctx, channel.ID, productID)
+		if err != nil {
+			return err
+		}
+		if available.Allocated != nil && quantity > available.Available {
+			err := fmt.Errorf("%w within the allocation of channel %s: %d available", domain.ErrInsufficientStock, channel.Name, available.Available)
+			inventory.metrics.recordReservationFailure(ctx, err)
+			return err
+		}
+
+		reservation, err = s.reservations.createReservation(ctx, channel.ID, "", productID, location, condition, quantity, reference, ttl)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// maxChannelRebalanceDays bounds the sales window and coverage of the
+// channel rebalancing job
+const maxChannelRebalanceDays = 365
+
+// ChannelRebalancePolicy sets how the rebalancing job sizes fixed channel
+// buckets: a bucket keeps what the channel sells in CoverDays at its average
+// daily rate over the last WindowDays, and never less than the channel holds
+// reserved
+type ChannelRebalancePolicy struct {
+	WindowDays int
+	CoverDays  int
+}
+
+// Validate checks if the rebalance policy is valid
+func (p ChannelRebalancePolicy) Validate() error {
+	if p.WindowDays < 1 || p.WindowDays > maxChannelRebalanceDays {
+		return domain.NewValidationError("window_days must be between 1 and %d", maxChannelRebalanceDays)
+	}
+	if p.CoverDays < 0 || p.CoverDays > maxChannelRebalanceDays {
+		return domain.NewValidationError("cover_days must be between 0 and %d", maxChannelRebalanceDays)
+	}
+	return nil
+}
+
+// ChannelRebalance is the outcome of a rebalancing run: the buckets checked,
+// the units they returned to the shared pool and the resulting allocation
+// events
+type ChannelRebalance struct {
+	Checked  int                              `json:"checked"`
+	Released int64                            `json:"released"`
+	Events   []*domain.ChannelAllocationEvent `json:"events"`
+}
+
+// Rebalance shrinks the fixed buckets that hold more than their channel is
+// expected to sell, returning the unsold units to the shared pool that
+// channels without an allocation reserve from. Buckets are never grown and
+// percentage allocations are left alone; both stay manual decisions. Every
+// change is recorded as a REBALANCE allocation event.
+func (s *ChannelService) Rebalance(ctx context.Context) (*ChannelRebalance, error) {
+	if err := s.rebalance.Validate(); err != nil {
+		return nil, err
+	}
+
+	channels, err := s.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -s.rebalance.WindowDays)
+	result := &ChannelRebalance{Events: []*domain.ChannelAllocationEvent{}}
+	for _, channel := range channels {
+		allocations, err := s.channelRepo.ListAllocations(ctx, channel.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to list allocations of channel %s: %w", channel.ID, err)
+		}
+
+		for _, allocation := range allocations {
+			if allocation.Quantity == nil {
+				continue
+			}
+			result.Checked++
+
+			bucket := *allocation.Quantity
+			event, err := s.rebalanceBucket(ctx, allocation, since)
+			if err != nil {
+				return result, fmt.Errorf("failed to rebalance channel %s product %s: %w", channel.ID, allocation.ProductID, err)
+			}
+			if event != nil {
+				result.Released += bucket - *event.Quantity
+				result.Events = append(result.Events, event)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// rebalanceBucket shrinks one fixed bucket to its channel's expected sales.
+// It returns nil when the bucket is already at or below them.
+func (s *ChannelService) rebalanceBucket(ctx context.Context, allocation *domain.ChannelAllocation, since time.Time) (*domain.ChannelAllocationEvent, error) {
+	reservationRepo := s.reservations.reservationRepo
+	sold, err := reservationRepo.ConfirmedByChannel(ctx, allocation.ChannelID, allocation.ProductID, since)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := reservationRepo.PendingByChannel(ctx, allocation.ChannelID, allocation.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := int64(math.Ceil(float64(sold) * float64(s.rebalance.CoverDays) / float64(s.rebalance.WindowDays)))
+	keep := max(expected, pending)
+	if keep >= *allocation.Quantity {
+		return nil, nil
+	}
+
+	next := *allocation
+	next.Quantity = &keep
+	notes := fmt.Sprintf("sold %d in %d days, %d pending", sold, s.rebalance.WindowDays, pending)
+	return s.changeAllocation(ctx, allocation.ChannelID, allocation.ProductID, &next, domain.ChannelAllocationRebalance, notes)
+}
+
+// RunRebalance rebalances the channel buckets every interval until ctx is
+// cancelled
+func (s *ChannelService) RunRebalance(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			result, err := s.Rebalance(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "channel rebalancing failed", "error", err)
+			}
+			if result != nil && len(result.Events) > 0 {
+				slog.InfoContext(ctx, "rebalanced channel buckets", "changed", len(result.Events), "released", result.Released)
+			}
+		}
+	}
+}
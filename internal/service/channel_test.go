@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockSalesChannelRepository implements SalesChannelRepository interface for testing
+type MockSalesChannelRepository struct {
+	channels    map[string]*domain.SalesChannel
+	allocations map[[2]string]*domain.ChannelAllocation
+	events      []*domain.ChannelAllocationEvent
+}
+
+func NewMockSalesChannelRepository() *MockSalesChannelRepository {
+	return &MockSalesChannelRepository{
+		channels:    make(map[string]*domain.SalesChannel),
+		allocations: make(map[[2]string]*domain.ChannelAllocation),
+	}
+}
+
+func (m *MockSalesChannelRepository) Create(ctx context.Context, channel *domain.SalesChannel) error {
+	for _, c := range m.channels {
+		if c.Name == channel.Name {
+			return domain.ErrDuplicateChannel
+		}
+	}
+	channel.ID = fmt.Sprintf("chan-%d", len(m.channels)+1)
+	channel.CreatedAt = time.Now()
+	stored := *channel
+	m.channels[channel.ID] = &stored
+	return nil
+}
+
+func (m *MockSalesChannelRepository) GetByID(ctx context.Context, id string) (*domain.SalesChannel, error) {
+	c, ok := m.channels[id]
+	if !ok {
+		return nil, fmt.Errorf("sales channel %w", domain.ErrNotFound)
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (m *MockSalesChannelRepository) List(ctx context.Context) ([]*domain.SalesChannel, error) {
+	var channels []*domain.SalesChannel
+	for _, c := range m.channels {
+		channels = append(channels, c)
+	}
+	return channels, nil
+}
+
+func (m *MockSalesChannelRepository) SetAllocation(ctx context.Context, allocation *domain.ChannelAllocation) error {
+	stored := *allocation
+	m.allocations[[2]string{allocation.ChannelID, allocation.ProductID}] = &stored
+	return nil
+}
+
+func (m *MockSalesChannelRepository) GetAllocation(ctx context.Context, channelID, productID string) (*domain.ChannelAllocation, error) {
+	return m.allocations[[2]string{channelID, productID}], nil
+}
+
+func (m *MockSalesChannelRepository) ListAllocations(ctx context.Context, channelID string) ([]*domain.ChannelAllocation, error) {
+	var allocations []*domain.ChannelAllocation
+	for key, a := range m.allocations {
+		if key[0] == channelID {
+			allocations = append(allocations, a)
+		}
+	}
+	return allocations, nil
+}
+
+func (m *MockSalesChannelRepository) DeleteAllocation(ctx context.Context, channelID, productID string) error {
+	key := [2]string{channelID, productID}
+	if _, ok := m.allocations[key]; !ok {
+		return fmt.Errorf("channel allocation %w", domain.ErrNotFound)
+	}
+	delete(m.allocations, key)
+	return nil
+}
+
+func (m *MockSalesChannelRepository) RecordEvent(ctx context.Context, event *domain.ChannelAllocationEvent) error {
+	event.ID = fmt.Sprintf("evt-%d", len(m.events)+1)
+	event.CreatedAt = time.Now()
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *MockSalesChannelRepository) ListEvents(ctx context.Context, channelID string, limit, offset int) ([]*domain.ChannelAllocationEvent, error) {
+	var events []*domain.ChannelAllocationEvent
+	for i := len(m.events) - 1; i >= 0; i-- {
+		if m.events[i].ChannelID == channelID {
+			events = append(events, m.events[i])
+		}
+	}
+	return events, nil
+}
+
+func TestChannelAllocations(t *testing.T) {
+	reservations, _, inventoryRepo := setupReservationTest(t)
+	service := NewChannelService(reservations, NewMockSalesChannelRepository(), ChannelRebalancePolicy{WindowDays: 7, CoverDays: 7})
+	ctx := context.Background()
+
+	web := &domain.SalesChannel{Name: "Web shop", Type: domain.SalesChannelWeb}
+	marketplace := &domain.SalesChannel{Name: "Marketplace", Type: domain.SalesChannelMarketplace}
+	for _, channel := range []*domain.SalesChannel{web, marketplace} {
+		if err := service.CreateChannel(ctx, channel); err != nil {
+			t.Fatalf("Failed to create channel: %v", err)
+		}
+	}
+	if err := service.CreateChannel(ctx, &domain.SalesChannel{Name: "Web shop", Type: domain.SalesChannelWeb}); !errors.Is(err, domain.ErrDuplicateChannel) {
+		t.Errorf("Expected ErrDuplicateChannel, got %v", err)
+	}
+
+	percent := 30.0
+	if err := service.SetAllocation(ctx, &domain.ChannelAllocation{ChannelID: marketplace.ID, ProductID: "prod-1", Percent: &percent}); err != nil {
+		t.Fatalf("Failed to set allocation: %v", err)
+	}
+	bucket := int64(2)
+	both := &domain.ChannelAllocation{ChannelID: marketplace.ID, ProductID: "prod-1", Percent: &percent, Quantity: &bucket}
+	if err := service.SetAllocation(ctx, both); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected percent and quantity together to be rejected, got %v", err)
+	}
+
+	t.Run("caps the allocated channel", func(t *testing.T) {
+		if _, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 2, "MP-1", 0); err != nil {
+			t.Fatalf("Failed to reserve within the allocation: %v", err)
+		}
+		if _, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 2, "MP-2", 0); !errors.Is(err, domain.ErrInsufficientStock) {
+			t.Errorf("Expected the allocation to be exceeded, got %v", err)
+		}
+
+		availability, err := service.Availability(ctx, marketplace.ID, "prod-1")
+		if err != nil {
+			t.Fatalf("Failed to get availability: %v", err)
+		}
+		if *availability.Allocated != 3 || availability.Reserved != 2 || availability.Available != 1 {
+			t.Errorf("Expected 3 allocated, 2 reserved, 1 available, got %+v", availability)
+		}
+	})
+
+	t.Run("leaves the rest to other channels", func(t *testing.T) {
+		reservation, err := service.Reserve(ctx, web.ID, "prod-1", "", "", 7, "WEB-1", 0)
+		if err != nil {
+			t.Fatalf("Failed to reserve through the web channel: %v", err)
+		}
+		if reservation.ChannelID != web.ID {
+			t.Errorf("Expected channel %s, got %s", web.ID, reservation.ChannelID)
+		}
+		if _, reserved := stock(t, inventoryRepo, "inv-1"); reserved != 9 {
+			t.Errorf("Expected 9 reserved, got %d", reserved)
+		}
+
+		availability, err := service.Availability(ctx, web.ID, "prod-1")
+		if err != nil {
+			t.Fatalf("Failed to get availability: %v", err)
+		}
+		if availability.Allocated != nil || availability.Available != 1 {
+			t.Errorf("Expected the uncapped channel to see the product's available stock, got %+v", availability)
+		}
+	})
+
+	t.Run("releasing frees the allocation", func(t *testing.T) {
+		if err := service.SetAllocation(ctx, &domain.ChannelAllocation{ChannelID: marketplace.ID, ProductID: "prod-1", Quantity: &bucket}); err != nil {
+			t.Fatalf("Failed to set allocation: %v", err)
+		}
+		if _, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 1, "MP-3", 0); !errors.Is(err, domain.ErrInsufficientStock) {
+			t.Fatalf("Expected the full bucket to reject, got %v", err)
+		}
+
+		if _, err := reservations.ReleaseReservation(ctx, "res-1"); err != nil {
+			t.Fatalf("Failed to release reservation: %v", err)
+		}
+		if _, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 1, "MP-3", 0); err != nil {
+			t.Errorf("Failed to reserve after the release: %v", err)
+		}
+	})
+}
+
+func TestChannelRebalance(t *testing.T) {
+	reservations, _, _ := setupReservationTest(t)
+	channelRepo := NewMockSalesChannelRepository()
+	service := NewChannelService(reservations, channelRepo, ChannelRebalancePolicy{WindowDays: 7, CoverDays: 7})
+	ctx := context.Background()
+
+	marketplace := &domain.SalesChannel{Name: "Marketplace", Type: domain.SalesChannelMarketplace}
+	if err := service.CreateChannel(ctx, marketplace); err != nil {
+		t.Fatalf("Failed to create channel: %v", err)
+	}
+	bucket := int64(8)
+	if err := service.SetAllocation(ctx, &domain.ChannelAllocation{ChannelID: marketplace.ID, ProductID: "prod-1", Quantity: &bucket}); err != nil {
+		t.Fatalf("Failed to set allocation: %v", err)
+	}
+
+	// Two units sold over the window, one more pending
+	sold, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 2, "MP-1", 0)
+	if err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+	if _, err := reservations.ConfirmReservation(ctx, sold.ID); err != nil {
+		t.Fatalf("Failed to confirm reservation: %v", err)
+	}
+	if _, err := service.Reserve(ctx, marketplace.ID, "prod-1", "", "", 1, "MP-2", 0); err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+
+	result, err := service.Rebalance(ctx)
+	if err != nil {
+		t.Fatalf("Failed to rebalance: %v", err)
+	}
+	if result.Checked != 1 || result.Released != 6 || len(result.Events) != 1 {
+		t.Fatalf("Expected the bucket of 8 to shrink to 2, got %+v", result)
+	}
+	allocation, _ := channelRepo.GetAllocation(ctx, marketplace.ID, "prod-1")
+	if *allocation.Quantity != 2 {
+		t.Errorf("Expected a bucket of 2, got %d", *allocation.Quantity)
+	}
+
+	events, err := service.ListAllocationEvents(ctx, marketplace.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events) != 2 || events[0].Change != domain.ChannelAllocationRebalance || *events[0].PreviousQuantity != 8 ||
+		events[1].Change != domain.ChannelAllocationManual || events[1].PreviousQuantity != nil {
+		t.Errorf("Unexpected allocation events: %+v", events)
+	}
+
+	// The bucket is at the expected sales now; another run changes nothing
+	if result, err := service.Rebalance(ctx); err != nil || len(result.Events) != 0 {
+		t.Errorf("Expected no further change, got %+v (err %v)", result, err)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// errReadAborted is what callers sharing a read get when it panicked
+var errReadAborted = errors.New("coalesced read did not complete")
+
+// readGroup coalesces identical concurrent reads: a caller asking for a key
+// while a read of it is in flight waits for that read and shares its result
+// instead of querying again. Every caller gets its own copy of the result.
+type readGroup[T any] struct {
+	clone func(T) T
+
+	mu    sync.Mutex
+	calls map[string]*readCall[T]
+}
+
+// readCall is a read in flight and the callers waiting for it
+type readCall[T any] struct {
+	done    chan struct{}
+	waiters int
+	val     T
+	err     error
+}
+
+func newReadGroup[T any](clone func(T) T) *readGroup[T] {
+	return &readGroup[T]{clone: clone, calls: make(map[string]*readCall[T])}
+}
+
+// do returns the result of read for key, joining a read of key that is
+// already in flight. shared reports whether the result came from another
+// caller's read. When that read was canceled with its caller's context, the
+// waiting callers read on their own. Reads of different tenants are never
+// joined, and reads that must see a given write never join a read that may
+// have started before it.
+func (g *readGroup[T]) do(ctx context.Context, key string, read func(ctx context.Context) (T, error)) (val T, shared bool, err error) {
+	if mustReadAfter(ctx) {
+		val, err = read(ctx)
+		return val, false, err
+	}
+	key = readKey(ctx, key)
+
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.waiters++
+		g.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return val, false, ctx.Err()
+		}
+		if call.err != nil && ctx.Err() == nil &&
+			(errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)) {
+			val, err = read(ctx)
+			return val, false, err
+		}
+		if call.err != nil {
+			return val, true, call.err
+		}
+		return g.clone(call.val), true, nil
+	}
+
+	call := &readCall[T]{done: make(chan struct{}), err: errReadAborted}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.val, call.err = read(ctx)
+	if call.err != nil {
+		return val, false, call.err
+	}
+	return g.clone(call.val), false, nil
+}
+
+// readKey scopes the key of a read to the tenant of ctx
+func readKey(ctx context.Context, key string) string {
+	return domain.TenantIDFromContext(ctx) + "/" + key
+}
+
+// productRead is the result of GetProduct
+type productRead struct {
+	product   *domain.Product
+	inventory *domain.InventoryItem
+}
+
+func cloneProductRead(read productRead) productRead {
+	product := *read.product
+	return productRead{product: &product, inventory: cloneInventoryItem(read.inventory)}
+}
+
+func cloneInventoryItem(item *domain.InventoryItem) *domain.InventoryItem {
+	clone := *item
+	return &clone
+}
+
+func cloneStockLevel(level *domain.StockLevel) *domain.StockLevel {
+	clone := *level
+	clone.Locations = make([]*domain.InventoryItem, len(level.Locations))
+	for i, item := range level.Locations {
+		clone.Locations[i] = cloneInventoryItem(item)
+	}
+	return &clone
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// waitForWaiters blocks until n callers wait for the read of key
+func waitForWaiters[T any](t *testing.T, g *readGroup[T], key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[readKey(context.Background(), key)]
+		waiting := ok && call.waiters == n
+		g.mu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers to join the read of %s", n, key)
+}
+
+func TestReadGroupCoalescesConcurrentReads(t *testing.T) {
+	group := newReadGroup(cloneInventoryItem)
+	var queries atomic.Int32
+	release := make(chan struct{})
+	read := func(ctx context.Context) (*domain.InventoryItem, error) {
+		queries.Add(1)
+		<-release
+		return &domain.InventoryItem{ProductID: "prod-1", Quantity: 7}, nil
+	}
+
+	const callers = 5
+	results := make([]*domain.InventoryItem, callers)
+	var sharedCount atomic.Int32
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, shared, err := group.do(context.Background(), "prod-1", read)
+			if err != nil {
+				t.Errorf("do() error = %v", err)
+				return
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+			results[i] = item
+		}()
+		if i == 0 {
+			// Let the first caller start the read before the others join it
+			for queries.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	waitForWaiters(t, group, "prod-1", callers-1)
+	close(release)
+	wg.Wait()
+
+	if queries.Load() != 1 || sharedCount.Load() != callers-1 {
+		t.Errorf("Expected 1 query shared by %d callers, got %d queries and %d shared", callers-1, queries.Load(), sharedCount.Load())
+	}
+	for i, item := range results {
+		if item == nil || item.Quantity != 7 {
+			t.Fatalf("Caller %d got %+v", i, item)
+		}
+		for _, other := range results[:i] {
+			if item == other {
+				t.Fatal("Expected every caller to get its own copy")
+			}
+		}
+	}
+
+	// Reads after the flight landed query again
+	if _, shared, _ := group.do(context.Background(), "prod-1", read); shared || queries.Load() != 2 {
+		t.Errorf("Expected a fresh query once the first read completed, got %d queries", queries.Load())
+	}
+}
+
+func TestReadGroupCanceledLeader(t *testing.T) {
+	group := newReadGroup(cloneInventoryItem)
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+
+	go group.do(leaderCtx, "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	done := make(chan error, 1)
+	go func() {
+		item, shared, err := group.do(context.Background(), "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+			return &domain.InventoryItem{ProductID: "prod-1", Quantity: 3}, nil
+		})
+		if err == nil && (shared || item.Quantity != 3) {
+			t.Errorf("Expected the follower to read on its own, got %+v (shared %v)", item, shared)
+		}
+		done <- err
+	}()
+	waitForWaiters(t, group, "prod-1", 1)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Expected the follower to succeed after the leader was canceled, got %v", err)
+	}
+}
+
+func TestReadGroupSeparatesTenants(t *testing.T) {
+	group := newReadGroup(cloneInventoryItem)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	acme := domain.WithTenantID(context.Background(), "acme")
+	go group.do(acme, "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+		close(started)
+		<-release
+		return &domain.InventoryItem{ProductID: "prod-1", TenantID: "acme"}, nil
+	})
+	<-started
+	defer close(release)
+
+	globex := domain.WithTenantID(context.Background(), "globex")
+	item, shared, err := group.do(globex, "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+		return nil, domain.ErrNotFound
+	})
+	if shared || item != nil || err != domain.ErrNotFound {
+		t.Errorf("Expected another tenant to read on its own, got %+v (shared %v, err %v)", item, shared, err)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxSettlementReportDays bounds the period of a consignment settlement report
+const MaxSettlementReportDays = 366
+
+// ConsignmentService receives consignment stock, which its supplier owns
+// until it is sold, and transfers the ownership of the units sales sell,
+// recording what the tenant owes the supplier for them
+type ConsignmentService struct {
+	inventory       *InventoryService
+	consignmentRepo repository.ConsignmentRepository
+}
+
+// NewConsignmentService creates a new ConsignmentService
+func NewConsignmentService(inventory *InventoryService, consignmentRepo repository.ConsignmentRepository) *ConsignmentService {
+	return &ConsignmentService{
+		inventory:       inventory,
+		consignmentRepo: consignmentRepo,
+	}
+}
+
+// Receive adds a lot of consignment stock to the new stock of the product at
+// the location, stocking the location first if needed. The IN transaction,
+// with the lot ID as reference, and the lot are committed together.
+// Locations with an open stocktake are rejected in every freeze mode, as a
+// queued receipt would leave the lot without its stock.
+func (s *ConsignmentService) Receive(ctx context.Context, req domain.ConsignmentRequest) (*domain.Consignment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.inventory.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	consignment := &domain.Consignment{
+		ID:         uuid.New().String(),
+		Supplier:   req.Supplier,
+		ProductID:  product.ID,
+		Location:   req.Location,
+		UnitCost:   req.UnitCost,
+		Quantity:   req.Quantity,
+		Remaining:  req.Quantity,
+		Reference:  req.Reference,
+		ReceivedAt: time.Now(),
+	}
+
+	err = s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventory.rejectFrozen(ctx, req.Location); err != nil {
+			return err
+		}
+
+		item, err := s.inventory.inventoryRepo.GetByProductAndLocation(ctx, product.ID, req.Location, domain.ConditionNew)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("failed to get inventory: %w", err)
+			}
+			item, err = s.inventory.CreateInventoryAt(ctx, product.ID, req.Location, domain.ConditionNew, 0)
+			if err != nil {
+				return fmt.Errorf("failed to create inventory: %w", err)
+			}
+		}
+
+		unitCost := req.UnitCost
+		transaction := &domain.Transaction{
+			InventoryID: item.ID,
+			ProductID:   product.ID,
+			Type:        "IN",
+			Quantity:    req.Quantity,
+			Reference:   consignment.ID,
+			Notes:       "Consignment from " + req.Supplier,
+			UnitCost:    &unitCost,
+		}
+		if err := s.inventory.applyMovement(ctx, product.ID, item.ID, req.Quantity, 0, transaction); err != nil {
+			return fmt.Errorf("failed to add stock: %w", err)
+		}
+		return s.consignmentRepo.Create(ctx, consignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "consignment received", "consignment_id", consignment.ID, "supplier", consignment.Supplier,
+		"product_id", consignment.ProductID, "location", consignment.Location, "quantity", consignment.Quantity)
+	return consignment, nil
+}
+
+// GetConsignment retrieves a consignment lot
+func (s *ConsignmentService) GetConsignment(ctx context.Context, id string) (*domain.Consignment, error) {
+	consignment, err := s.consignmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consignment: %w", err)
+	}
+	return consignment, nil
+}
+
+// ListConsignments lists the consignment lots matching a filter, newest first
+func (s *ConsignmentService) ListConsignments(ctx context.Context, filter domain.ConsignmentFilter, limit, offset int) ([]*domain.Consignment, error) {
+	consignments, err := s.consignmentRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consignments: %w", err)
+	}
+	return consignments, nil
+}
+
+// ListTransfers lists the ownership transfers of a consignment lot, oldest
+// first
+func (s *ConsignmentService) ListTransfers(ctx context.Context, consignmentID string, limit, offset int) ([]*domain.OwnershipTransfer, error) {
+	if _, err := s.GetConsignment(ctx, consignmentID); err != nil {
+		return nil, err
+	}
+	transfers, err := s.consignmentRepo.ListTransfers(ctx, consignmentID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ownership transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// TransferOwnership transfers the ownership of the consignment units a sale
+// sold, oldest lots first, recording a transfer and a supplier payable per
+// lot. Units beyond those on consignment at the sale's location were the
+// tenant's own. It must run in the unit of work recording the sale.
+func (s *ConsignmentService) TransferOwnership(ctx context.Context, sale *domain.Sale) ([]*domain.OwnershipTransfer, error) {
+	lots, err := s.consignmentRepo.LockOpen(ctx, sale.ProductID, sale.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfers := domain.TransferOwnership(lots, sale)
+	for _, transfer := range transfers {
+		transfer.ID = uuid.New().String()
+		transfer.CreatedAt = now
+		payable := &domain.SupplierPayable{
+			ID:         uuid.New().String(),
+			TransferID: transfer.ID,
+			Supplier:   transfer.Supplier,
+			Amount:     transfer.Amount,
+			CreatedAt:  now,
+		}
+		if err := s.consignmentRepo.RecordTransfer(ctx, transfer, payable); err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "consignment ownership transferred", "consignment_id", transfer.ConsignmentID,
+			"supplier", transfer.Supplier, "sale_event_id", sale.EventID, "quantity", transfer.Quantity, "amount", transfer.Amount)
+	}
+	return transfers, nil
+}
+
+// Settlement totals what the tenant owes each supplier for the payables
+// recorded on the days from from to to (UTC, inclusive), optionally only
+// those of the given supplier. Payables are dated when recorded rather than
+// when the sale happened, so a settled period never changes.
+func (s *ConsignmentService) Settlement(ctx context.Context, supplier string, from, to time.Time) (*domain.ConsignmentSettlement, error) {
+	from, to = usageDay(from), usageDay(to)
+	if to.Before(from) {
+		return nil, domain.NewValidationError("from must not be after to")
+	}
+	if to.Sub(from) >= MaxSettlementReportDays*24*time.Hour {
+		return nil, domain.NewValidationError("settlement reports cover at most %d days", MaxSettlementReportDays)
+	}
+
+	suppliers, err := s.consignmentRepo.Settlement(ctx, supplier, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read consignment settlement: %w", err)
+	}
+	if suppliers == nil {
+		suppliers = []*domain.SupplierSettlement{}
+	}
+	return &domain.ConsignmentSettlement{From: from, To: to, Suppliers: suppliers}, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockConsignmentRepository implements ConsignmentRepository for testing
+type MockConsignmentRepository struct {
+	consignments []*domain.Consignment
+	transfers    []*domain.OwnershipTransfer
+	payables     []*domain.SupplierPayable
+}
+
+func (m *MockConsignmentRepository) Create(ctx context.Context, consignment *domain.Consignment) error {
+	stored := *consignment
+	m.consignments = append(m.consignments, &stored)
+	return nil
+}
+
+func (m *MockConsignmentRepository) GetByID(ctx context.Context, id string) (*domain.Consignment, error) {
+	for _, consignment := range m.consignments {
+		if consignment.ID == id {
+			copied := *consignment
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *MockConsignmentRepository) List(ctx context.Context, filter domain.ConsignmentFilter, limit, offset int) ([]*domain.Consignment, error) {
+	var consignments []*domain.Consignment
+	for _, consignment := range m.consignments {
+		if (filter.Supplier == "" || consignment.Supplier == filter.Supplier) && (!filter.Open || consignment.Remaining > 0) {
+			copied := *consignment
+			consignments = append(consignments, &copied)
+		}
+	}
+	return consignments, nil
+}
+
+func (m *MockConsignmentRepository) LockOpen(ctx context.Context, productID, location string) ([]*domain.Consignment, error) {
+	var lots []*domain.Consignment
+	for _, consignment := range m.consignments {
+		if consignment.ProductID == productID && consignment.Location == location && consignment.Remaining > 0 {
+			copied := *consignment
+			lots = append(lots, &copied)
+		}
+	}
+	return lots, nil
+}
+
+func (m *MockConsignmentRepository) RecordTransfer(ctx context.Context, transfer *domain.OwnershipTransfer, payable *domain.SupplierPayable) error {
+	for _, consignment := range m.consignments {
+		if consignment.ID == transfer.ConsignmentID {
+			consignment.Remaining -= transfer.Quantity
+		}
+	}
+	m.transfers = append(m.transfers, transfer)
+	m.payables = append(m.payables, payable)
+	return nil
+}
+
+func (m *MockConsignmentRepository) ListTransfers(ctx context.Context, consignmentID string, limit, offset int) ([]*domain.OwnershipTransfer, error) {
+	var transfers []*domain.OwnershipTransfer
+	for _, transfer := range m.transfers {
+		if transfer.ConsignmentID == consignmentID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *MockConsignmentRepository) Settlement(ctx context.Context, supplier string, from, to time.Time) ([]*domain.SupplierSettlement, error) {
+	var settlements []*domain.SupplierSettlement
+	for _, transfer := range m.transfers {
+		if supplier != "" && transfer.Supplier != supplier {
+			continue
+		}
+		if len(settlements) == 0 || settlements[len(settlements)-1].Supplier != transfer.Supplier {
+			settlements = append(settlements, &domain.SupplierSettlement{Supplier: transfer.Supplier})
+		}
+		settlements[len(settlements)-1].Add(&domain.ConsignmentSettlementLine{ProductID: transfer.ProductID,
+			Location: transfer.Location, Payables: 1, Units: transfer.Quantity, Amount: transfer.Amount})
+	}
+	return settlements, nil
+}
+
+func TestConsignmentSales(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	consignmentRepo := &MockConsignmentRepository{}
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Vase", SKU: "VAS001", Price: 40})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 1, Location: "STORE-1"})
+
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)))
+	consignments := NewConsignmentService(inventory, consignmentRepo)
+	sales := NewSaleService(inventory, NewMockSaleRepository(), consignments)
+
+	if _, err := consignments.Receive(ctx, domain.ConsignmentRequest{Supplier: "Acme", ProductID: "prod-1", Location: "STORE-1"}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error without a quantity, got %v", err)
+	}
+
+	// Two lots at different costs; the oldest is sold first
+	first, err := consignments.Receive(ctx, domain.ConsignmentRequest{Supplier: "Acme", ProductID: "prod-1", Location: "STORE-1", Quantity: 2, UnitCost: 12.5})
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	second, err := consignments.Receive(ctx, domain.ConsignmentRequest{Supplier: "Potters", ProductID: "prod-1", Location: "STORE-1", Quantity: 3, UnitCost: 10})
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 6 {
+		t.Errorf("Expected 6 units after receiving 5 on consignment, got %d", quantity)
+	}
+
+	// Receiving at a new location stocks it
+	if _, err := consignments.Receive(ctx, domain.ConsignmentRequest{Supplier: "Acme", ProductID: "prod-1", Location: "STORE-2", Quantity: 1, UnitCost: 12.5}); err != nil {
+		t.Fatalf("Receive() at a new location error = %v", err)
+	}
+
+	sale := &domain.Sale{EventID: "till-1/1", ProductID: "prod-1", Location: "STORE-1", Quantity: 3, OccurredAt: time.Now()}
+	if recorded, _, err := sales.RecordSale(ctx, sale); err != nil || recorded.Status != domain.SaleStatusApplied {
+		t.Fatalf("RecordSale() = %+v, error %v", recorded, err)
+	}
+	if len(consignmentRepo.transfers) != 2 {
+		t.Fatalf("Expected a transfer per lot sold from, got %d", len(consignmentRepo.transfers))
+	}
+	if transfer := consignmentRepo.transfers[0]; transfer.ConsignmentID != first.ID || transfer.Quantity != 2 || transfer.Amount != 25 {
+		t.Errorf("Expected the first lot sold out first, got %+v", transfer)
+	}
+	if transfer := consignmentRepo.transfers[1]; transfer.ConsignmentID != second.ID || transfer.Quantity != 1 || transfer.Amount != 10 {
+		t.Errorf("Expected one unit of the second lot, got %+v", transfer)
+	}
+	if len(consignmentRepo.payables) != 2 || consignmentRepo.payables[0].Supplier != "Acme" || consignmentRepo.payables[0].Amount != 25 {
+		t.Errorf("Expected a payable per transfer, got %+v", consignmentRepo.payables)
+	}
+
+	// Units beyond the consigned ones are the tenant's own; short sales
+	// transfer nothing
+	sale = &domain.Sale{EventID: "till-1/2", ProductID: "prod-1", Location: "STORE-1", Quantity: 3, OccurredAt: time.Now()}
+	if _, _, err := sales.RecordSale(ctx, sale); err != nil {
+		t.Fatalf("RecordSale() error = %v", err)
+	}
+	short := &domain.Sale{EventID: "till-1/3", ProductID: "prod-1", Location: "STORE-1", Quantity: 5, OccurredAt: time.Now()}
+	if recorded, _, err := sales.RecordSale(ctx, short); err != nil || recorded.Status != domain.SaleStatusShort {
+		t.Fatalf("Expected a short sale, got %+v, error %v", recorded, err)
+	}
+	if len(consignmentRepo.transfers) != 3 || consignmentRepo.transfers[2].Quantity != 2 {
+		t.Errorf("Expected the rest of the second lot transferred once, got %d transfers", len(consignmentRepo.transfers))
+	}
+	if open, _ := consignments.ListConsignments(ctx, domain.ConsignmentFilter{Open: true}, 10, 0); len(open) != 1 || open[0].Location != "STORE-2" {
+		t.Errorf("Expected only the STORE-2 lot open, got %+v", open)
+	}
+
+	report, err := consignments.Settlement(ctx, "", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Settlement() error = %v", err)
+	}
+	if len(report.Suppliers) != 2 || report.Suppliers[0].Amount != 25 || report.Suppliers[1].Units != 3 || report.Suppliers[1].Amount != 30 {
+		t.Errorf("Unexpected settlement %+v", report.Suppliers)
+	}
+	if _, err := consignments.Settlement(ctx, "", time.Now(), time.Now().AddDate(0, 0, -1)); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a reversed period, got %v", err)
+	}
+}
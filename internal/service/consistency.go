@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ErrInvalidConsistencyToken is returned for consistency tokens this service
+// did not issue
+var ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+
+// ConsistencyService issues read-your-writes tokens. A token returned after a
+// write is the primary's log position at the time; reads presenting it see
+// that write even when they would otherwise be served by a lagging replica or
+// an in-process cache.
+type ConsistencyService struct {
+	consistencyRepo repository.ConsistencyRepository
+}
+
+// NewConsistencyService creates a new ConsistencyService
+func NewConsistencyService(consistencyRepo repository.ConsistencyRepository) *ConsistencyService {
+	return &ConsistencyService{consistencyRepo: consistencyRepo}
+}
+
+// Token returns a token covering every write committed so far
+func (s *ConsistencyService) Token(ctx context.Context) (string, error) {
+	lsn, err := s.consistencyRepo.CurrentLSN(ctx)
+	if err != nil {
+		return "", err
+	}
+	return lsn.String(), nil
+}
+
+// WithToken makes the reads of ctx see every write the token covers
+func (s *ConsistencyService) WithToken(ctx context.Context, token string) (context.Context, error) {
+	lsn, err := repository.ParseLSN(token)
+	if err != nil {
+		return ctx, ErrInvalidConsistencyToken
+	}
+	return repository.WithReadAfter(ctx, lsn), nil
+}
+
+// mustReadAfter reports whether the reads of ctx must see a given write, so
+// that results cached or read before it cannot be used
+func mustReadAfter(ctx context.Context) bool {
+	_, ok := repository.ReadAfter(ctx)
+	return ok
+}
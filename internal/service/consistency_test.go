@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MockConsistencyRepository implements ConsistencyRepository interface
+type MockConsistencyRepository struct {
+	lsn repository.LSN
+}
+
+func (m *MockConsistencyRepository) CurrentLSN(ctx context.Context) (repository.LSN, error) {
+	return m.lsn, nil
+}
+
+func TestConsistencyToken(t *testing.T) {
+	ctx := context.Background()
+	service := NewConsistencyService(&MockConsistencyRepository{lsn: 0x16_B374D848})
+
+	token, err := service.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token != "16/B374D848" {
+		t.Errorf("Expected the LSN in PostgreSQL form, got %q", token)
+	}
+
+	readCtx, err := service.WithToken(ctx, token)
+	if err != nil {
+		t.Fatalf("WithToken() error = %v", err)
+	}
+	if lsn, ok := repository.ReadAfter(readCtx); !ok || lsn != 0x16_B374D848 {
+		t.Errorf("Expected reads after the token's LSN, got %v (%v)", lsn, ok)
+	}
+
+	for _, token := range []string{"B374D848", "16/", "x/1", "1/100000000"} {
+		if _, err := service.WithToken(ctx, token); !errors.Is(err, ErrInvalidConsistencyToken) {
+			t.Errorf("Expected %q to be rejected, got %v", token, err)
+		}
+	}
+}
+
+func TestConsistencyTokenBypassesReadCaches(t *testing.T) {
+	ctx := context.Background()
+	readCtx := repository.WithReadAfter(ctx, 1)
+
+	// Reads with a token never join a read in flight
+	group := newReadGroup(cloneInventoryItem)
+	var queries atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go group.do(ctx, "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+		close(started)
+		<-release
+		return &domain.InventoryItem{ProductID: "prod-1", Quantity: 1}, nil
+	})
+	<-started
+	item, shared, err := group.do(readCtx, "prod-1", func(ctx context.Context) (*domain.InventoryItem, error) {
+		queries.Add(1)
+		return &domain.InventoryItem{ProductID: "prod-1", Quantity: 2}, nil
+	})
+	close(release)
+	if err != nil || shared || queries.Load() != 1 || item.Quantity != 2 {
+		t.Errorf("Expected an own read, got %+v (shared=%v, err=%v)", item, shared, err)
+	}
+
+	// Availability checks with a token skip the availability cache
+	inventoryRepo := NewMockInventoryRepository()
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 20, Location: "Warehouse A"})
+	cache := NewAvailabilityCache(inventoryRepo)
+	if err := cache.Reconcile(ctx); err != nil {
+		t.Fatalf("Failed to reconcile cache: %v", err)
+	}
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository(), WithAvailabilityCache(cache))
+	inventoryRepo.items["inv-1"].Quantity = 100
+
+	if _, ok := cache.Available(readCtx, "prod-1"); ok {
+		t.Error("Expected the cache to be bypassed with a token")
+	}
+	results, err := service.CheckAvailability(readCtx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 50}})
+	if err != nil {
+		t.Fatalf("Failed to check availability: %v", err)
+	}
+	if results[0].Available != 100 {
+		t.Errorf("Expected the stored availability 100, got %d", results[0].Available)
+	}
+}
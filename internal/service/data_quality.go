@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// dataQualityFindingLimit bounds the findings listed per check
+const dataQualityFindingLimit = 100
+
+// dataQualityFixReference is the reference of the transactions recorded by
+// fixes
+const dataQualityFixReference = "data-quality-fix"
+
+// DataQualityService looks for inconsistent inventory data: products without
+// inventory, inventory without products, reservations beyond the stock on
+// hand and transactions of missing rows. It keeps the report of its latest
+// run.
+type DataQualityService struct {
+	inventory *InventoryService
+	repo      repository.DataQualityRepository
+
+	mu     sync.Mutex
+	latest *domain.DataQualityReport
+}
+
+// NewDataQualityService creates a new DataQualityService
+func NewDataQualityService(inventory *InventoryService, repo repository.DataQualityRepository) *DataQualityService {
+	return &DataQualityService{
+		inventory: inventory,
+		repo:      repo,
+	}
+}
+
+// Check runs every check and, with fix set, repairs the fixable findings:
+// empty inventory rows without a product are deleted and reservations beyond
+// the stock on hand are released with an UNRESERVE transaction. Findings
+// that fail to be fixed carry the error; the others are reported either way.
+func (s *DataQualityService) Check(ctx context.Context, fix bool) (*domain.DataQualityReport, error) {
+	report := &domain.DataQualityReport{
+		CheckedAt: time.Now(),
+		Totals:    make(map[domain.DataQualityCheck]int, len(domain.DataQualityChecks)),
+		Findings:  []*domain.DataQualityFinding{},
+	}
+
+	for _, check := range domain.DataQualityChecks {
+		findings, total, err := s.repo.Check(ctx, check, dataQualityFindingLimit)
+		if err != nil {
+			return nil, err
+		}
+		report.Totals[check] = total
+
+		for _, finding := range findings {
+			finding.Fixable = dataQualityFixable(finding)
+			if fix && finding.Fixable {
+				if err := s.fix(ctx, finding); err != nil {
+					finding.FixError = err.Error()
+				} else {
+					finding.Fixed = true
+					report.Fixed++
+				}
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	if len(report.Findings) > 0 {
+		slog.WarnContext(ctx, "data quality issues found", "findings", len(report.Findings), "fixed", report.Fixed)
+	}
+
+	s.mu.Lock()
+	s.latest = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// dataQualityFixable reports whether a finding can be repaired without
+// losing stock
+func dataQualityFixable(finding *domain.DataQualityFinding) bool {
+	switch finding.Check {
+	case domain.CheckInventoryWithoutProduct:
+		return finding.Quantity == 0 && finding.Reserved == 0
+	case domain.CheckReservedExceedsQuantity:
+		return true
+	}
+	return false
+}
+
+// fix repairs a fixable finding
+func (s *DataQualityService) fix(ctx context.Context, finding *domain.DataQualityFinding) error {
+	switch finding.Check {
+	case domain.CheckInventoryWithoutProduct:
+		deleted, err := s.repo.DeleteOrphanedInventory(ctx, finding.InventoryID)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return fmt.Errorf("inventory row %s changed since it was checked", finding.InventoryID)
+		}
+		return nil
+
+	case domain.CheckReservedExceedsQuantity:
+		excess := finding.Reserved - finding.Quantity
+		transaction := &domain.Transaction{
+			InventoryID: finding.InventoryID,
+			ProductID:   finding.ProductID,
+			Type:        "UNRESERVE",
+			Quantity:    excess,
+			Reference:   dataQualityFixReference,
+			Notes:       "Released reservations beyond the stock on hand",
+		}
+		return s.inventory.applyMovement(ctx, finding.ProductID, finding.InventoryID, 0, -excess, transaction)
+	}
+	return fmt.Errorf("%s findings cannot be fixed", finding.Check)
+}
+
+// Latest returns the report of the latest run, or nil before the first
+func (s *DataQualityService) Latest() *domain.DataQualityReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// Run checks the data every interval until ctx is cancelled, fixing the
+// fixable findings when fix is set
+func (s *DataQualityService) Run(ctx context.Context, interval time.Duration, fix bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if _, err := s.Check(ctx, fix); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to check data quality", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockDataQualityRepository implements DataQualityRepository interface
+type MockDataQualityRepository struct {
+	findings map[domain.DataQualityCheck][]*domain.DataQualityFinding
+	deleted  []string
+}
+
+func (m *MockDataQualityRepository) Check(ctx context.Context, check domain.DataQualityCheck, limit int) ([]*domain.DataQualityFinding, int, error) {
+	var findings []*domain.DataQualityFinding
+	for _, finding := range m.findings[check] {
+		copied := *finding
+		findings = append(findings, &copied)
+	}
+	return findings, len(findings), nil
+}
+
+func (m *MockDataQualityRepository) DeleteOrphanedInventory(ctx context.Context, id string) (bool, error) {
+	m.deleted = append(m.deleted, id)
+	return true, nil
+}
+
+func TestDataQualityCheck(t *testing.T) {
+	ctx := context.Background()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	inventoryService := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo)
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-over", ProductID: "prod-1", Location: "WH-A", Quantity: 4, Reserved: 7})
+
+	repo := &MockDataQualityRepository{findings: map[domain.DataQualityCheck][]*domain.DataQualityFinding{
+		domain.CheckProductWithoutInventory: {
+			{Check: domain.CheckProductWithoutInventory, EntityID: "prod-2", ProductID: "prod-2"},
+		},
+		domain.CheckInventoryWithoutProduct: {
+			{Check: domain.CheckInventoryWithoutProduct, EntityID: "inv-empty", ProductID: "gone", InventoryID: "inv-empty"},
+			{Check: domain.CheckInventoryWithoutProduct, EntityID: "inv-stocked", ProductID: "gone", InventoryID: "inv-stocked", Quantity: 3},
+		},
+		domain.CheckReservedExceedsQuantity: {
+			{Check: domain.CheckReservedExceedsQuantity, EntityID: "inv-over", ProductID: "prod-1", InventoryID: "inv-over", Quantity: 4, Reserved: 7},
+		},
+	}}
+	service := NewDataQualityService(inventoryService, repo)
+
+	if service.Latest() != nil {
+		t.Fatal("Expected no report before the first check")
+	}
+
+	// Without fix, findings are only reported
+	report, err := service.Check(ctx, false)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(report.Findings) != 4 || report.Fixed != 0 || report.Totals[domain.CheckInventoryWithoutProduct] != 2 || report.Totals[domain.CheckOrphanedTransaction] != 0 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	fixable := map[string]bool{}
+	for _, finding := range report.Findings {
+		fixable[finding.EntityID] = finding.Fixable
+	}
+	if fixable["prod-2"] || !fixable["inv-empty"] || fixable["inv-stocked"] || !fixable["inv-over"] {
+		t.Errorf("Unexpected fixable findings %v", fixable)
+	}
+	if len(repo.deleted) != 0 || len(transactionRepo.transactions) != 0 {
+		t.Error("Expected nothing to be changed without fix")
+	}
+	if service.Latest() != report {
+		t.Error("Expected the report to be kept as the latest")
+	}
+
+	// With fix, the empty orphaned row is deleted and the excess reservation
+	// released through the ledger
+	report, err = service.Check(ctx, true)
+	if err != nil {
+		t.Fatalf("Check() with fix error = %v", err)
+	}
+	if report.Fixed != 2 {
+		t.Errorf("Expected 2 findings to be fixed, got %d", report.Fixed)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != "inv-empty" {
+		t.Errorf("Expected only inv-empty to be deleted, got %v", repo.deleted)
+	}
+	if item := inventoryRepo.items["inv-over"]; item.Reserved != 4 {
+		t.Errorf("Expected the reservation to be capped at the quantity, got %d", item.Reserved)
+	}
+	if len(transactionRepo.transactions) != 1 {
+		t.Fatalf("Expected one transaction to be recorded, got %d", len(transactionRepo.transactions))
+	}
+	for _, transaction := range transactionRepo.transactions {
+		if transaction.Type != "UNRESERVE" || transaction.Quantity != 3 || transaction.InventoryID != "inv-over" {
+			t.Errorf("Expected an UNRESERVE of 3 on inv-over, got %+v", transaction)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// ErrNotReleased is returned when stock of a product is reserved before its
+// scheduled drop
+var ErrNotReleased = errors.New("product is not released yet")
+
+// ScheduleDrop hides the stock of a product from availability and
+// reservations until releaseAt
+func (s *InventoryService) ScheduleDrop(ctx context.Context, productID string, releaseAt time.Time) (*domain.Product, error) {
+	if releaseAt.IsZero() {
+		return nil, domain.NewValidationError("release_at is required")
+	}
+	if !releaseAt.After(time.Now()) {
+		return nil, domain.NewValidationError("release_at must be in the future")
+	}
+
+	releaseAt = releaseAt.UTC()
+	if err := s.productRepo.SetReleaseAt(ctx, productID, &releaseAt); err != nil {
+		return nil, fmt.Errorf("failed to schedule drop: %w", err)
+	}
+	s.evictAvailability(productID)
+
+	return s.requireProduct(ctx, productID)
+}
+
+// CancelDrop releases the stock of a product right away
+func (s *InventoryService) CancelDrop(ctx context.Context, productID string) (*domain.Product, error) {
+	if err := s.productRepo.SetReleaseAt(ctx, productID, nil); err != nil {
+		return nil, fmt.Errorf("failed to cancel drop: %w", err)
+	}
+	s.evictAvailability(productID)
+
+	return s.requireProduct(ctx, productID)
+}
+
+// ListDrops lists the products with a scheduled drop, the next release first
+func (s *InventoryService) ListDrops(ctx context.Context) ([]*domain.Product, error) {
+	products, err := s.productRepo.ListScheduledReleases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drops: %w", err)
+	}
+	return products, nil
+}
+
+// ReleaseDrops clears the drops that are due and evicts the released
+// products from the availability cache, so their next read sees the stock
+func (s *InventoryService) ReleaseDrops(ctx context.Context) ([]string, error) {
+	ids, err := s.productRepo.ReleaseDue(ctx, time.Now())
+	for _, id := range ids {
+		s.evictAvailability(id)
+	}
+	if err != nil {
+		return ids, fmt.Errorf("failed to release drops: %w", err)
+	}
+	return ids, nil
+}
+
+// RunDropReleases releases due drops every interval until ctx is cancelled.
+// Stock is reservable from the release time on; the run clears the schedule
+// and the cached reads.
+func (s *InventoryService) RunDropReleases(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			ids, err := s.ReleaseDrops(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "drop release failed", "error", err)
+			}
+			if len(ids) > 0 {
+				slog.InfoContext(ctx, "released product drops", "product_ids", ids)
+			}
+		}
+	}
+}
+
+// checkReleased rejects stock operations on a product whose drop is still
+// scheduled. Unknown products are left to the stock lookup to reject.
+func (s *InventoryService) checkReleased(ctx context.Context, productID string) error {
+	releaseAt, err := s.releaseAt(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if releaseAt != nil {
+		return fmt.Errorf("%w: releases at %s", ErrNotReleased, releaseAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// releaseAt returns the release time of a product that is not released yet,
+// or nil once it is
+func (s *InventoryService) releaseAt(ctx context.Context, productID string) (*time.Time, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil || product.Released(time.Now()) {
+		return nil, nil
+	}
+	return product.ReleaseAt, nil
+}
+
+// requireProduct reads a product, failing with ErrNotFound when it does not exist
+func (s *InventoryService) requireProduct(ctx context.Context, productID string) (*domain.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	return product, nil
+}
+
+// evictAvailability drops a product from the availability cache, if any
+func (s *InventoryService) evictAvailability(productID string) {
+	if s.availability != nil {
+		s.availability.Evict(productID)
+	}
+}
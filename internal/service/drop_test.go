@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestProductDrops(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	ctx := context.Background()
+
+	cache := NewAvailabilityCache(inventoryRepo)
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithAvailabilityCache(cache))
+
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Sneaker", SKU: "SNK-1", Price: 120})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "Warehouse A"})
+
+	if _, err := service.ScheduleDrop(ctx, "prod-1", time.Now().Add(-time.Minute)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("Expected a past release time to be rejected, got %v", err)
+	}
+
+	releaseAt := time.Now().Add(time.Hour)
+	product, err := service.ScheduleDrop(ctx, "prod-1", releaseAt)
+	if err != nil {
+		t.Fatalf("Failed to schedule drop: %v", err)
+	}
+	if product.ReleaseAt == nil || !product.ReleaseAt.Equal(releaseAt) {
+		t.Errorf("Expected release at %v, got %v", releaseAt, product.ReleaseAt)
+	}
+
+	t.Run("hides stock until released", func(t *testing.T) {
+		results, err := service.CheckAvailability(ctx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 1}})
+		if err != nil {
+			t.Fatalf("Failed to check availability: %v", err)
+		}
+		if results[0].Available != 0 || results[0].Sufficient || results[0].ReleaseAt == nil {
+			t.Errorf("Expected hidden stock with a release time, got %+v", results[0])
+		}
+
+		if err := service.ReserveStock(ctx, "prod-1", 1, "ORDER-1"); !errors.Is(err, ErrNotReleased) {
+			t.Errorf("Expected ErrNotReleased, got %v", err)
+		}
+		if _, reserved := stock(t, inventoryRepo, "inv-1"); reserved != 0 {
+			t.Errorf("Expected nothing reserved, got %d", reserved)
+		}
+
+		drops, err := service.ListDrops(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list drops: %v", err)
+		}
+		if len(drops) != 1 || drops[0].ID != "prod-1" {
+			t.Errorf("Expected the scheduled drop to be listed, got %v", drops)
+		}
+	})
+
+	t.Run("reservable once the release time has come", func(t *testing.T) {
+		past := time.Now().Add(-time.Second)
+		productRepo.products["prod-1"].ReleaseAt = &past
+		cache.Set(&domain.InventoryItem{ProductID: "prod-1", Quantity: 0})
+
+		if err := service.ReserveStock(ctx, "prod-1", 2, "ORDER-1"); err != nil {
+			t.Fatalf("Failed to reserve released stock: %v", err)
+		}
+
+		ids, err := service.ReleaseDrops(ctx)
+		if err != nil {
+			t.Fatalf("Failed to release drops: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != "prod-1" {
+			t.Errorf("Expected prod-1 to be released, got %v", ids)
+		}
+		if _, ok := cache.Available(ctx, "prod-1"); ok {
+			t.Error("Expected the released product to be evicted from the availability cache")
+		}
+
+		results, err := service.CheckAvailability(ctx, []AvailabilityCheck{{ProductID: "prod-1", Quantity: 8}})
+		if err != nil {
+			t.Fatalf("Failed to check availability: %v", err)
+		}
+		if results[0].Available != 8 || !results[0].Sufficient || results[0].ReleaseAt != nil {
+			t.Errorf("Expected 8 available after release, got %+v", results[0])
+		}
+	})
+
+	t.Run("cancel releases right away", func(t *testing.T) {
+		if _, err := service.ScheduleDrop(ctx, "prod-1", time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("Failed to schedule drop: %v", err)
+		}
+		product, err := service.CancelDrop(ctx, "prod-1")
+		if err != nil {
+			t.Fatalf("Failed to cancel drop: %v", err)
+		}
+		if product.ReleaseAt != nil {
+			t.Errorf("Expected no release time, got %v", product.ReleaseAt)
+		}
+		if err := service.ReserveStock(ctx, "prod-1", 1, "ORDER-2"); err != nil {
+			t.Errorf("Failed to reserve after cancelling the drop: %v", err)
+		}
+	})
+}
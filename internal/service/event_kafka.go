@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/httpclient"
+)
+
+// KafkaRESTSink produces event batches to a Kafka topic through a Kafka REST
+// Proxy (v2 API), one record per batch. The proxy cannot set record headers,
+// so consumers recognize a gzip-compressed batch by its magic bytes (1f 8b).
+type KafkaRESTSink struct {
+	baseURL string
+	topic   string
+	client  httpclient.Doer
+}
+
+// NewKafkaRESTSink creates a sink to the topic of the REST Proxy at baseURL
+func NewKafkaRESTSink(baseURL, topic string, client httpclient.Doer) *KafkaRESTSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &KafkaRESTSink{baseURL: strings.TrimSuffix(baseURL, "/"), topic: topic, client: client}
+}
+
+// PublishEvents implements EventSink
+func (s *KafkaRESTSink) PublishEvents(ctx context.Context, batch EventBatch) error {
+	type record struct {
+		Value string `json:"value"`
+	}
+	body, err := json.Marshal(struct {
+		Records []record `json:"records"`
+	}{Records: []record{{Value: base64.StdEncoding.EncodeToString(batch.Payload)}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode kafka REST request: %w", err)
+	}
+
+	target := s.baseURL + "/topics/" + url.PathEscape(s.topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build kafka REST request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaRESTBinaryType)
+	req.Header.Set("Accept", kafkaRESTContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kafka REST proxy %s: %w", s.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka REST proxy POST %s returned status %d: %s", target, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	// The proxy reports records it failed to produce per record, with status 200
+	var result struct {
+		Offsets []struct {
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode kafka REST response: %w", err)
+	}
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			return fmt.Errorf("kafka REST proxy failed to produce to %s: %s (code %d)", s.topic, offset.Error, *offset.ErrorCode)
+		}
+	}
+	return nil
+}
+
+// Ping checks that the proxy can be reached and knows the topic
+func (s *KafkaRESTSink) Ping(ctx context.Context) error {
+	target := s.baseURL + "/topics/" + url.PathEscape(s.topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build kafka REST request: %w", err)
+	}
+	req.Header.Set("Accept", kafkaRESTContentType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kafka REST proxy %s: %w", s.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka REST proxy GET %s returned status %d: %s", target, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// EventCompression is the encoding of the payload of a published event batch
+type EventCompression string
+
+const (
+	// EventCompressionNone publishes the JSON array as is
+	EventCompressionNone EventCompression = "none"
+	// EventCompressionGzip publishes the JSON array gzip-compressed
+	EventCompressionGzip EventCompression = "gzip"
+)
+
+// ParseEventCompression parses the name of a compression, "" meaning none
+func ParseEventCompression(name string) (EventCompression, error) {
+	switch EventCompression(strings.ToLower(strings.TrimSpace(name))) {
+	case "", EventCompressionNone:
+		return EventCompressionNone, nil
+	case EventCompressionGzip:
+		return EventCompressionGzip, nil
+	case "zstd":
+		return "", fmt.Errorf("zstd compression is not available in this build, use gzip")
+	default:
+		return "", fmt.Errorf("unknown event compression %q, expected none or gzip", name)
+	}
+}
+
+// MovementEvent is a recorded transaction as published, with the tenant
+// whose request recorded it
+type MovementEvent struct {
+	TenantID    string              `json:"tenant_id,omitempty"`
+	Transaction *domain.Transaction `json:"transaction"`
+}
+
+// EventBatch is one message of a broker: a JSON array of movement events,
+// compressed as Encoding says
+type EventBatch struct {
+	Payload  []byte
+	Encoding EventCompression
+	Events   int
+}
+
+// EventSink delivers event batches to a broker
+type EventSink interface {
+	PublishEvents(ctx context.Context, batch EventBatch) error
+}
+
+// EventPublisherConfig sets how movement events are grouped into batches
+type EventPublisherConfig struct {
+	// BatchSize is the most events a batch holds; a full batch is published
+	// at once
+	BatchSize int
+	// Linger is how long events wait for a batch to fill up before they are
+	// published anyway
+	Linger time.Duration
+	// Compression is the encoding of the batch payloads
+	Compression EventCompression
+	// BufferSize bounds the events waiting to be published, including those
+	// kept while the broker is unavailable; the oldest are dropped beyond it
+	BufferSize int
+}
+
+// DefaultEventPublisherConfig returns batches of up to 500 events lingering
+// 100ms, uncompressed
+func DefaultEventPublisherConfig() EventPublisherConfig {
+	return EventPublisherConfig{
+		BatchSize:   500,
+		Linger:      100 * time.Millisecond,
+		Compression: EventCompressionNone,
+		BufferSize:  100000,
+	}
+}
+
+// EventPublisher publishes the recorded transactions to a broker in batches.
+// Transactions are queued on the request path and published by Run, so a slow
+// or unavailable broker never delays a stock operation; a batch that failed
+// is kept and published again with the next one.
+type EventPublisher struct {
+	sink   EventSink
+	config EventPublisherConfig
+
+	// flushing serializes flushes, so that no batch is published twice
+	flushing sync.Mutex
+
+	mu      sync.Mutex
+	pending []MovementEvent
+	// removed counts the events ever taken from the front of pending,
+	// published or dropped
+	removed int64
+	dropped int64
+	// ready is signalled when a full batch is pending
+	ready chan struct{}
+}
+
+// NewEventPublisher creates a publisher of batches to the sink
+func NewEventPublisher(sink EventSink, config EventPublisherConfig) *EventPublisher {
+	defaults := DefaultEventPublisherConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.Linger <= 0 {
+		config.Linger = defaults.Linger
+	}
+	if config.Compression == "" {
+		config.Compression = defaults.Compression
+	}
+	if config.BufferSize < config.BatchSize {
+		config.BufferSize = max(defaults.BufferSize, config.BatchSize)
+	}
+	return &EventPublisher{sink: sink, config: config, ready: make(chan struct{}, 1)}
+}
+
+// HandleTransaction implements TransactionHandler by queueing the transaction
+func (p *EventPublisher) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	event := MovementEvent{TenantID: domain.TenantIDFromContext(ctx), Transaction: transaction}
+
+	p.mu.Lock()
+	if len(p.pending) >= p.config.BufferSize {
+		p.pending = p.pending[1:]
+		p.removed++
+		p.dropped++
+	}
+	p.pending = append(p.pending, event)
+	full := len(p.pending) >= p.config.BatchSize
+	p.mu.Unlock()
+
+	if full {
+		select {
+		case p.ready <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run publishes the pending events whenever a batch is full or has lingered,
+// until ctx is done
+func (p *EventPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Linger)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ready:
+		case <-ticker.C:
+		}
+		if err := p.Flush(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "event publishing failed", "error", err)
+		}
+	}
+}
+
+// Flush publishes all pending events in batches of at most the batch size.
+// Events of a batch that failed stay pending.
+func (p *EventPublisher) Flush(ctx context.Context) error {
+	p.flushing.Lock()
+	defer p.flushing.Unlock()
+
+	p.mu.Lock()
+	if p.dropped > 0 {
+		slog.WarnContext(ctx, "movement events dropped while the broker was unavailable", "events", p.dropped)
+		p.dropped = 0
+	}
+	p.mu.Unlock()
+
+	for {
+		p.mu.Lock()
+		events := p.pending[:min(len(p.pending), p.config.BatchSize)]
+		start := p.removed
+		p.mu.Unlock()
+		if len(events) == 0 {
+			return nil
+		}
+
+		batch, err := encodeEventBatch(events, p.config.Compression)
+		if err != nil {
+			return err
+		}
+		if err := p.sink.PublishEvents(ctx, batch); err != nil {
+			return fmt.Errorf("failed to publish %d movement events: %w", len(events), err)
+		}
+
+		// Events queued meanwhile were appended, and those dropped meanwhile
+		// were taken from the front, possibly from the published batch
+		p.mu.Lock()
+		if published := start + int64(len(events)) - p.removed; published > 0 {
+			p.pending = p.pending[published:]
+			p.removed += published
+		}
+		p.mu.Unlock()
+	}
+}
+
+// encodeEventBatch encodes the events as a JSON array, compressed as given
+func encodeEventBatch(events []MovementEvent, compression EventCompression) (EventBatch, error) {
+	payload, err := json.Marshal(events)
+	if err != nil {
+		return EventBatch{}, fmt.Errorf("failed to encode movement events: %w", err)
+	}
+
+	if compression == EventCompressionGzip {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(payload); err != nil {
+			return EventBatch{}, fmt.Errorf("failed to compress movement events: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return EventBatch{}, fmt.Errorf("failed to compress movement events: %w", err)
+		}
+		payload = buf.Bytes()
+	}
+	return EventBatch{Payload: payload, Encoding: compression, Events: len(events)}, nil
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// recordingEventSink keeps the batches published to it
+type recordingEventSink struct {
+	mu      sync.Mutex
+	batches []EventBatch
+	fails   bool
+}
+
+func (s *recordingEventSink) PublishEvents(ctx context.Context, batch EventBatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fails {
+		return errors.New("broker unavailable")
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *recordingEventSink) published() []EventBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]EventBatch(nil), s.batches...)
+}
+
+func decodeEventBatch(t *testing.T, batch EventBatch) []MovementEvent {
+	t.Helper()
+	payload := batch.Payload
+	if batch.Encoding == EventCompressionGzip {
+		reader, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			t.Fatalf("batch is not gzip-compressed: %v", err)
+		}
+		if payload, err = io.ReadAll(reader); err != nil {
+			t.Fatalf("failed to decompress batch: %v", err)
+		}
+	}
+	var events []MovementEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		t.Fatalf("batch is not a JSON array of events: %v", err)
+	}
+	return events
+}
+
+func movement(id string) *domain.Transaction {
+	return &domain.Transaction{ID: id, InventoryID: "inv-1", ProductID: "prod-1", Type: "IN", Quantity: 1}
+}
+
+func TestEventPublisherBatchesBySize(t *testing.T) {
+	sink := &recordingEventSink{}
+	publisher := NewEventPublisher(sink, EventPublisherConfig{BatchSize: 2, Linger: time.Hour, Compression: EventCompressionGzip})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publisher.Run(ctx)
+
+	tenantCtx := domain.WithTenantID(context.Background(), "acme")
+	for _, id := range []string{"tx-1", "tx-2", "tx-3"} {
+		publisher.HandleTransaction(tenantCtx, movement(id))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.published()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	batches := sink.published()
+	if len(batches) == 0 {
+		t.Fatal("a full batch was not published before the linger")
+	}
+
+	events := decodeEventBatch(t, batches[0])
+	if len(events) != 2 || events[0].Transaction.ID != "tx-1" || events[1].Transaction.ID != "tx-2" {
+		t.Fatalf("first batch = %+v, want tx-1 and tx-2", events)
+	}
+	if events[0].TenantID != "acme" {
+		t.Errorf("tenant = %q, want acme", events[0].TenantID)
+	}
+}
+
+func TestEventPublisherPublishesLingeringEvents(t *testing.T) {
+	sink := &recordingEventSink{}
+	publisher := NewEventPublisher(sink, EventPublisherConfig{BatchSize: 100, Linger: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publisher.Run(ctx)
+
+	publisher.HandleTransaction(context.Background(), movement("tx-1"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.published()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	batches := sink.published()
+	if len(batches) != 1 {
+		t.Fatalf("published %d batches, want 1", len(batches))
+	}
+	if batches[0].Encoding != EventCompressionNone {
+		t.Errorf("encoding = %q, want none", batches[0].Encoding)
+	}
+	if events := decodeEventBatch(t, batches[0]); len(events) != 1 {
+		t.Errorf("batch has %d events, want 1", len(events))
+	}
+}
+
+func TestEventPublisherKeepsFailedBatches(t *testing.T) {
+	sink := &recordingEventSink{fails: true}
+	publisher := NewEventPublisher(sink, EventPublisherConfig{BatchSize: 2, BufferSize: 3})
+	ctx := context.Background()
+
+	for _, id := range []string{"tx-1", "tx-2", "tx-3", "tx-4"} {
+		publisher.HandleTransaction(ctx, movement(id))
+	}
+	if err := publisher.Flush(ctx); err == nil {
+		t.Fatal("expected the flush to fail while the broker is unavailable")
+	}
+
+	sink.fails = false
+	if err := publisher.Flush(ctx); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	// tx-1 was dropped to keep the buffer bounded
+	var ids []string
+	for _, batch := range sink.published() {
+		for _, event := range decodeEventBatch(t, batch) {
+			ids = append(ids, event.Transaction.ID)
+		}
+	}
+	if strings.Join(ids, ",") != "tx-2,tx-3,tx-4" {
+		t.Errorf("published %v, want tx-2, tx-3 and tx-4 once each", ids)
+	}
+}
+
+func TestParseEventCompression(t *testing.T) {
+	for name, want := range map[string]EventCompression{"": EventCompressionNone, "none": EventCompressionNone, "GZIP": EventCompressionGzip} {
+		if got, err := ParseEventCompression(name); err != nil || got != want {
+			t.Errorf("ParseEventCompression(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"zstd", "lz4"} {
+		if _, err := ParseEventCompression(name); err == nil {
+			t.Errorf("ParseEventCompression(%q) should fail", name)
+		}
+	}
+}
+
+func TestKafkaRESTSinkProducesBatch(t *testing.T) {
+	var value []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/topics/inventory.movements" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != kafkaRESTBinaryType {
+			t.Errorf("content type = %q, want %q", ct, kafkaRESTBinaryType)
+		}
+		var body struct {
+			Records []struct {
+				Value string `json:"value"`
+			} `json:"records"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Records) != 1 {
+			t.Fatalf("request has %d records, want 1", len(body.Records))
+		}
+		value, _ = base64.StdEncoding.DecodeString(body.Records[0].Value)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":7}]}`))
+	}))
+	defer server.Close()
+
+	batch, err := encodeEventBatch([]MovementEvent{{Transaction: movement("tx-1")}}, EventCompressionGzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewKafkaRESTSink(server.URL, "inventory.movements", server.Client())
+	if err := sink.PublishEvents(context.Background(), batch); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if !bytes.Equal(value, batch.Payload) {
+		t.Error("record value is not the batch payload")
+	}
+}
+
+func TestKafkaRESTSinkReportsRecordErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"error_code":40403,"error":"topic not found"}]}`))
+	}))
+	defer server.Close()
+
+	sink := NewKafkaRESTSink(server.URL, "missing", server.Client())
+	if err := sink.PublishEvents(context.Background(), EventBatch{Payload: []byte("[]")}); err == nil {
+		t.Fatal("expected a record error to fail the publish")
+	}
+}
+
+func TestNATSSinkPublishesWithContentEncoding(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	type message struct {
+		subject string
+		header  string
+		payload []byte
+	}
+	received := make(chan message, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte(`INFO {"server_id":"test","headers":true}` + "\r\n"))
+
+		var msg message
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 0:
+			case fields[0] == "HPUB" && len(fields) == 4:
+				headerLen, _ := strconv.Atoi(fields[2])
+				totalLen, _ := strconv.Atoi(fields[3])
+				data := make([]byte, totalLen+2)
+				if _, err := io.ReadFull(reader, data); err != nil {
+					return
+				}
+				msg = message{subject: fields[1], header: string(data[:headerLen]), payload: data[headerLen:totalLen]}
+			case fields[0] == "PING":
+				conn.Write([]byte("PONG\r\n"))
+				received <- msg
+				return
+			}
+		}
+	}()
+
+	sink, err := NewNATSSink("nats://"+listener.Addr().String(), "inventory.movements", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	batch, err := encodeEventBatch([]MovementEvent{{Transaction: movement("tx-1")}}, EventCompressionGzip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.PublishEvents(context.Background(), batch); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	msg := <-received
+	if msg.subject != "inventory.movements" {
+		t.Errorf("subject = %q, want inventory.movements", msg.subject)
+	}
+	if !strings.Contains(msg.header, "Content-Encoding: gzip\r\n") {
+		t.Errorf("header %q has no gzip content encoding", msg.header)
+	}
+	if !bytes.Equal(msg.payload, batch.Payload) {
+		t.Error("message payload is not the batch payload")
+	}
+}
+
+func TestKafkaRESTSinkPing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/topics/inventory.movements" {
+			http.Error(w, `{"error_code":40401,"message":"Topic not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"name":"inventory.movements"}`))
+	}))
+	defer server.Close()
+
+	if err := NewKafkaRESTSink(server.URL, "inventory.movements", server.Client()).Ping(context.Background()); err != nil {
+		t.Errorf("ping failed: %v", err)
+	}
+	if err := NewKafkaRESTSink(server.URL, "missing", server.Client()).Ping(context.Background()); err == nil {
+		t.Error("expected the ping of an unknown topic to fail")
+	}
+}
+
+func TestNATSSinkPing(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte(`INFO {"server_id":"test","headers":true}` + "\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.TrimSpace(line) == "PING" {
+				conn.Write([]byte("PONG\r\n"))
+			}
+		}
+	}()
+
+	sink, err := NewNATSSink("nats://"+listener.Addr().String(), "inventory.movements", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.Ping(context.Background()); err != nil {
+		t.Errorf("ping failed: %v", err)
+	}
+
+	listener.Close()
+	sink.Close()
+	if err := sink.Ping(context.Background()); err == nil {
+		t.Error("expected the ping of an unreachable server to fail")
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// TransactionHandler is notified of every inventory transaction after it has
+// been recorded. Handlers run synchronously on the request path, so slow work
+// should be handed off.
+type TransactionHandler interface {
+	HandleTransaction(ctx context.Context, transaction *domain.Transaction)
+}
+
+// WithTransactionHandlers notifies the handlers, in order, of recorded transactions
+func WithTransactionHandlers(handlers ...TransactionHandler) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.transactionHandlers = append(s.transactionHandlers, handlers...)
+	}
+}
+
+// notifyTransactions counts the transactions in the stock operation metrics
+// and calls the transaction handlers. A panicking handler is logged and
+// skipped so that it cannot fail a committed stock operation.
+func (s *InventoryService) notifyTransactions(ctx context.Context, transactions ...*domain.Transaction) {
+	for _, transaction := range transactions {
+		s.metrics.recordStockOperation(ctx, transaction.Type)
+	}
+
+	for _, handler := range s.transactionHandlers {
+		for _, transaction := range transactions {
+			func() {
+				defer func() {
+					if p := recover(); p != nil {
+						slog.ErrorContext(ctx, "transaction handler panicked",
+							"handler", fmt.Sprintf("%T", handler), "panic", p, "transaction_id", transaction.ID)
+					}
+				}()
+				handler.HandleTransaction(ctx, transaction)
+			}()
+		}
+	}
+}
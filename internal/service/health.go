@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// DependencyCheck probes one dependency of the instance. The instance is not
+// ready while a critical dependency is down; other dependencies only degrade
+// it.
+type DependencyCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// HealthService probes the dependencies of the instance for readiness checks
+type HealthService struct {
+	checks  []DependencyCheck
+	timeout time.Duration
+}
+
+// NewHealthService creates a HealthService running the checks, each bounded
+// by timeout
+func NewHealthService(timeout time.Duration, checks ...DependencyCheck) *HealthService {
+	return &HealthService{
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Ready probes every dependency concurrently and reports their status and
+// latency, in the order the checks were given
+func (s *HealthService) Ready(ctx context.Context) *domain.Readiness {
+	readiness := &domain.Readiness{
+		Status:       domain.ReadinessReady,
+		Dependencies: make([]*domain.DependencyStatus, len(s.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readiness.Dependencies[i] = s.probe(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, dependency := range readiness.Dependencies {
+		switch {
+		case dependency.Up:
+		case dependency.Critical:
+			readiness.Status = domain.ReadinessNotReady
+		case readiness.Status == domain.ReadinessReady:
+			readiness.Status = domain.ReadinessDegraded
+		}
+	}
+	return readiness
+}
+
+// probe runs one check within the timeout
+func (s *HealthService) probe(ctx context.Context, check DependencyCheck) *domain.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	status := &domain.DependencyStatus{
+		Name:      check.Name,
+		Up:        err == nil,
+		Critical:  check.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
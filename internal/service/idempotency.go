@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxIdempotencyKeyLength is the longest accepted Idempotency-Key
+const MaxIdempotencyKeyLength = 255
+
+var (
+	// ErrInvalidIdempotencyKey is returned for empty or overlong keys
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	// ErrIdempotencyKeyReused is returned when a key is replayed with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+	// ErrIdempotencyKeyInProgress is returned while the original request of a key is still running
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// IdempotencyService makes retried requests safe: the first request with a key
+// is executed and its response stored, later requests with the same key get
+// the stored response instead
+type IdempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+}
+
+// NewIdempotencyService creates a new IdempotencyService. Keys can be reused
+// for a new request once they are older than ttl.
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+	}
+}
+
+// Begin claims a key for a request identified by requestHash. It returns nil
+// when the caller should execute the request, or the stored record when the
+// request was already completed and must be replayed.
+func (s *IdempotencyService) Begin(ctx context.Context, key, requestHash string) (*domain.IdempotencyRecord, error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: key must be 1 to %d characters", ErrInvalidIdempotencyKey, MaxIdempotencyKeyLength)
+	}
+
+	record := &domain.IdempotencyRecord{Key: key, RequestHash: requestHash}
+	reserved, err := s.idempotencyRepo.Reserve(ctx, record, time.Now().Add(-s.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := s.idempotencyRepo.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+// Complete stores the response of a request that claimed a key. Server errors
+// are not stored; the key is released instead so the client can retry.
+func (s *IdempotencyService) Complete(ctx context.Context, key string, statusCode int, response []byte) error {
+	if statusCode >= http.StatusInternalServerError {
+		return s.idempotencyRepo.Delete(ctx, key)
+	}
+	return s.idempotencyRepo.Complete(ctx, key, statusCode, response)
+}
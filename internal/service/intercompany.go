@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// IntercompanyService transfers stock between tenants, the legal entities
+// of a group, as documented sales from one to the other
+type IntercompanyService struct {
+	inventory    *InventoryService
+	transferRepo repository.IntercompanyTransferRepository
+}
+
+// NewIntercompanyService creates a new IntercompanyService
+func NewIntercompanyService(inventory *InventoryService, transferRepo repository.IntercompanyTransferRepository) *IntercompanyService {
+	return &IntercompanyService{
+		inventory:    inventory,
+		transferRepo: transferRepo,
+	}
+}
+
+// CreateTransfer moves available new stock of a product of the tenant of ctx
+// to the product with the same SKU of the receiving tenant, stocking it at
+// the receiver's location first if needed. The sender's OUT, the receiver's
+// IN and the transfer document recording the cost are committed together.
+// Locations with an open stocktake are rejected in every freeze mode, as
+// the two sides cannot be queued apart.
+func (s *IntercompanyService) CreateTransfer(ctx context.Context, req domain.IntercompanyTransferRequest) (*domain.IntercompanyTransfer, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	fromTenant := domain.TenantIDFromContext(ctx)
+	if fromTenant == "" {
+		fromTenant = domain.DefaultTenantID
+	}
+	if req.ToTenant == fromTenant {
+		return nil, domain.NewValidationError("to_tenant must differ from the sending tenant")
+	}
+	toCtx := domain.WithTenantID(ctx, req.ToTenant)
+
+	product, err := s.inventory.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	counterpart, err := s.inventory.productRepo.GetBySKU(toCtx, product.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product %s of tenant %s: %w", product.SKU, req.ToTenant, err)
+	}
+	if counterpart == nil {
+		return nil, fmt.Errorf("product %s of tenant %s %w", product.SKU, req.ToTenant, domain.ErrNotFound)
+	}
+
+	unitCost := product.Price
+	if req.UnitCost != nil {
+		unitCost = *req.UnitCost
+	}
+	transfer := &domain.IntercompanyTransfer{
+		ID:            uuid.New().String(),
+		FromTenant:    fromTenant,
+		ToTenant:      req.ToTenant,
+		SKU:           product.SKU,
+		FromProductID: product.ID,
+		ToProductID:   counterpart.ID,
+		Quantity:      req.Quantity,
+		UnitCost:      unitCost,
+		TotalCost:     math.Round(unitCost*float64(req.Quantity)*100) / 100,
+		Reference:     req.Reference,
+		CreatedAt:     time.Now(),
+	}
+
+	err = s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		toCtx := domain.WithTenantID(ctx, req.ToTenant)
+
+		source, err := s.inventory.resolveInventory(ctx, product.ID, req.FromLocation)
+		if err != nil {
+			return fmt.Errorf("failed to get source inventory: %w", err)
+		}
+		transfer.FromLocation = source.Location
+		transfer.ToLocation = req.ToLocation
+		if transfer.ToLocation == "" {
+			transfer.ToLocation = source.Location
+		}
+
+		for _, location := range []string{transfer.FromLocation, transfer.ToLocation} {
+			if err := s.inventory.rejectFrozen(ctx, location); err != nil {
+				return err
+			}
+		}
+
+		if source.AvailableQuantity() < req.Quantity {
+			s.inventory.metrics.recordOversell(ctx, "intercompany_transfer")
+			return fmt.Errorf("%w available for transfer", domain.ErrInsufficientStock)
+		}
+
+		destination, err := s.inventory.inventoryRepo.GetByProductAndLocation(toCtx, counterpart.ID, transfer.ToLocation, domain.ConditionNew)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("failed to get destination inventory: %w", err)
+			}
+			destination, err = s.inventory.CreateInventoryAt(toCtx, counterpart.ID, transfer.ToLocation, domain.ConditionNew, 0)
+			if err != nil {
+				return fmt.Errorf("failed to create destination inventory: %w", err)
+			}
+		}
+
+		out := &domain.Transaction{
+			InventoryID: source.ID,
+			ProductID:   product.ID,
+			Type:        "OUT",
+			Quantity:    req.Quantity,
+			Reference:   transfer.ID,
+			Notes:       "Inter-company transfer to " + req.ToTenant,
+		}
+		var check movementCheck
+		if s.inventory.lotRepo != nil {
+			check = s.inventory.consumeLots(req.Quantity, out, nil)
+		}
+		if err := s.inventory.applyCheckedMovement(ctx, product.ID, source.ID, -req.Quantity, 0, check, out); err != nil {
+			return fmt.Errorf("failed to remove stock: %w", err)
+		}
+
+		in := &domain.Transaction{
+			InventoryID: destination.ID,
+			ProductID:   counterpart.ID,
+			Type:        "IN",
+			Quantity:    req.Quantity,
+			Reference:   transfer.ID,
+			Notes:       "Inter-company transfer from " + fromTenant,
+		}
+		if err := s.inventory.applyMovement(toCtx, counterpart.ID, destination.ID, req.Quantity, 0, in); err != nil {
+			return fmt.Errorf("failed to add stock: %w", err)
+		}
+
+		transfer.OutTransactionID = out.ID
+		transfer.InTransactionID = in.ID
+		return s.transferRepo.Create(ctx, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "intercompany transfer recorded", "transfer_id", transfer.ID,
+		"to_tenant", transfer.ToTenant, "sku", transfer.SKU, "quantity", transfer.Quantity)
+	return transfer, nil
+}
+
+// GetTransfer retrieves a transfer sent or received by the tenant of ctx
+func (s *IntercompanyService) GetTransfer(ctx context.Context, id string) (*domain.IntercompanyTransfer, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get intercompany transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// ListTransfers lists the transfers sent or received by the tenant of ctx,
+// newest first
+func (s *IntercompanyService) ListTransfers(ctx context.Context, limit, offset int) ([]*domain.IntercompanyTransfer, error) {
+	transfers, err := s.transferRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list intercompany transfers: %w", err)
+	}
+	return transfers, nil
+}
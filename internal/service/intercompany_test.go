@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockIntercompanyTransferRepository implements IntercompanyTransferRepository for testing
+type MockIntercompanyTransferRepository struct {
+	transfers map[string]*domain.IntercompanyTransfer
+}
+
+func NewMockIntercompanyTransferRepository() *MockIntercompanyTransferRepository {
+	return &MockIntercompanyTransferRepository{transfers: make(map[string]*domain.IntercompanyTransfer)}
+}
+
+func (m *MockIntercompanyTransferRepository) Create(ctx context.Context, transfer *domain.IntercompanyTransfer) error {
+	stored := *transfer
+	m.transfers[transfer.ID] = &stored
+	return nil
+}
+
+func (m *MockIntercompanyTransferRepository) GetByID(ctx context.Context, id string) (*domain.IntercompanyTransfer, error) {
+	transfer, ok := m.transfers[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return transfer, nil
+}
+
+func (m *MockIntercompanyTransferRepository) List(ctx context.Context, limit, offset int) ([]*domain.IntercompanyTransfer, error) {
+	var transfers []*domain.IntercompanyTransfer
+	for _, transfer := range m.transfers {
+		transfers = append(transfers, transfer)
+	}
+	return transfers, nil
+}
+
+// tenantProductRepository finds products by SKU within the tenant of ctx only
+type tenantProductRepository struct {
+	*MockProductRepository
+}
+
+func (m tenantProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	for _, p := range m.products {
+		if p.SKU == sku && p.TenantID == domain.TenantIDFromContext(ctx) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestIntercompanyTransfer(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	transferRepo := NewMockIntercompanyTransferRepository()
+
+	ctx := domain.WithTenantID(context.Background(), "acme-us")
+	productRepo.Create(ctx, &domain.Product{ID: "prod-us", TenantID: "acme-us", Name: "Console", SKU: "CON001", Price: 400})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-eu", TenantID: "acme-eu", Name: "Console", SKU: "CON001", Price: 450})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-us", ProductID: "prod-us", Quantity: 10, Location: "WH-A"})
+
+	inventory := NewInventoryService(tenantProductRepository{productRepo}, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)))
+	service := NewIntercompanyService(inventory, transferRepo)
+
+	// Priced at the sender's list price, received at the sender's location
+	transfer, err := service.CreateTransfer(ctx, domain.IntercompanyTransferRequest{ToTenant: "acme-eu", ProductID: "prod-us", Quantity: 4, Reference: "IC-1"})
+	if err != nil {
+		t.Fatalf("CreateTransfer() error = %v", err)
+	}
+	if transfer.FromTenant != "acme-us" || transfer.ToProductID != "prod-eu" || transfer.ToLocation != "WH-A" || transfer.TotalCost != 1600 {
+		t.Errorf("Unexpected transfer: %+v", transfer)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-us"); quantity != 6 {
+		t.Errorf("Expected 6 units left with the sender, got %d", quantity)
+	}
+	received, err := inventoryRepo.GetByProductAndLocation(ctx, "prod-eu", "WH-A", domain.ConditionNew)
+	if err != nil || received.Quantity != 4 {
+		t.Fatalf("Expected 4 units received, got %+v (%v)", received, err)
+	}
+
+	out := transactionRepo.transactions[transfer.OutTransactionID]
+	in := transactionRepo.transactions[transfer.InTransactionID]
+	if out == nil || in == nil || out.Type != "OUT" || in.Type != "IN" || out.Reference != transfer.ID || in.Reference != transfer.ID {
+		t.Errorf("Expected OUT and IN transactions referencing the transfer, got %+v and %+v", out, in)
+	}
+
+	// A negotiated price overrides the list price
+	price := 380.5
+	transfer, err = service.CreateTransfer(ctx, domain.IntercompanyTransferRequest{ToTenant: "acme-eu", ProductID: "prod-us", Quantity: 2, UnitCost: &price})
+	if err != nil {
+		t.Fatalf("CreateTransfer() error = %v", err)
+	}
+	if transfer.TotalCost != 761 {
+		t.Errorf("Expected a total cost of 761, got %v", transfer.TotalCost)
+	}
+
+	// Nothing moves when the sender lacks stock or the receiver the SKU
+	if _, err := service.CreateTransfer(ctx, domain.IntercompanyTransferRequest{ToTenant: "acme-eu", ProductID: "prod-us", Quantity: 5}); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected insufficient stock, got %v", err)
+	}
+	if _, err := service.CreateTransfer(ctx, domain.IntercompanyTransferRequest{ToTenant: "globex", ProductID: "prod-us", Quantity: 1}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected the receiver's product not to be found, got %v", err)
+	}
+	if _, err := service.CreateTransfer(ctx, domain.IntercompanyTransferRequest{ToTenant: "acme-us", ProductID: "prod-us", Quantity: 1}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error transferring to the sender, got %v", err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-us"); quantity != 4 || len(transferRepo.transfers) != 2 {
+		t.Errorf("Expected 4 units left and 2 transfers, got %d and %d", quantity, len(transferRepo.transfers))
+	}
+}
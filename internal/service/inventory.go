@@ -4,6 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
 	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
@@ -14,6 +20,121 @@ type InventoryService struct {
 	productRepo     repository.ProductRepository
 	inventoryRepo   repository.InventoryRepository
 	transactionRepo repository.TransactionRepository
+	translationRepo repository.ProductTranslationRepository
+	annotationRepo  repository.TransactionAnnotationRepository
+
+	batcher     *writeBatcher
+	hotProducts map[string]bool
+
+	availability *AvailabilityCache
+
+	warehouseRepo repository.WarehouseRepository
+
+	quota *ReservationQuota
+
+	approvalPolicy ApprovalPolicy
+	validators     []MovementValidator
+
+	transactionHandlers []TransactionHandler
+
+	metrics *businessMetrics
+	tracer  trace.Tracer
+
+	stocktakeRepo repository.StocktakeRepository
+
+	lotRepo repository.LotRepository
+
+	transactor repository.Transactor
+	locker     repository.ProductLocker
+
+	shipDefaults *domain.ShipSchedule
+
+	productReads    *readGroup[productRead]
+	inventoryReads  *readGroup[*domain.InventoryItem]
+	stockLevelReads *readGroup[*domain.StockLevel]
+}
+
+// InventoryServiceOption configures optional InventoryService dependencies
+type InventoryServiceOption func(*InventoryService)
+
+// WithTranslationRepository enables localized product names and descriptions
+func WithTranslationRepository(translationRepo repository.ProductTranslationRepository) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.translationRepo = translationRepo
+	}
+}
+
+// WithWriteBatching enables micro-batching of quantity updates: concurrent
+// deltas for the same product are coalesced into one UPDATE per window while
+// every operation still records its own transaction. When productIDs is empty
+// all products are batched, otherwise only the listed hot products.
+func WithWriteBatching(window time.Duration, productIDs ...string) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.batcher = newWriteBatcher(func(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, transactions []*domain.Transaction) error {
+			return s.writeMovement(ctx, inventoryID, quantityDelta, reservedDelta, nil, transactions...)
+		}, window)
+		if len(productIDs) > 0 {
+			s.hotProducts = make(map[string]bool, len(productIDs))
+			for _, id := range productIDs {
+				s.hotProducts[id] = true
+			}
+		}
+	}
+}
+
+// WithAvailabilityCache serves availability checks from an in-memory cache
+// that the service keeps up to date on every successful stock operation
+func WithAvailabilityCache(cache *AvailabilityCache) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.availability = cache
+	}
+}
+
+// WithWarehouseRepository links inventory items to warehouses: stocking a
+// product at a location whose warehouse does not exist yet registers it
+func WithWarehouseRepository(warehouseRepo repository.WarehouseRepository) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.warehouseRepo = warehouseRepo
+	}
+}
+
+// WithLotRepository tracks stock lots: removals take their units from the
+// lots of the inventory row expiring first and refuse to take the units of
+// expired lots. Removals then update the lots in their unit of work, so they
+// are never batched.
+func WithLotRepository(lotRepo repository.LotRepository) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.lotRepo = lotRepo
+	}
+}
+
+// WithTransactor makes every stock movement write its quantity change and
+// the transactions recording it in one unit of work, so the audit trail
+// cannot diverge from stock levels. Without it the writes are made one after
+// the other.
+func WithTransactor(transactor repository.Transactor) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.transactor = transactor
+	}
+}
+
+// WithReadCoalescing makes identical concurrent product, inventory and stock
+// level reads share one database query, so a burst of reads for a hot
+// product costs one query. A read that joins one already in flight may miss
+// a write committed after that read started.
+func WithReadCoalescing() InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.productReads = newReadGroup(cloneProductRead)
+		s.inventoryReads = newReadGroup(cloneInventoryItem)
+		s.stockLevelReads = newReadGroup(cloneStockLevel)
+	}
+}
+
+// noopTransactor runs units of work without a surrounding transaction
+type noopTransactor struct{}
+
+func (noopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 // NewInventoryService creates a new InventoryService
@@ -21,12 +142,134 @@ func NewInventoryService(
 	productRepo repository.ProductRepository,
 	inventoryRepo repository.InventoryRepository,
 	transactionRepo repository.TransactionRepository,
+	opts ...InventoryServiceOption,
 ) *InventoryService {
-	return &InventoryService{
+	s := &InventoryService{
 		productRepo:     productRepo,
 		inventoryRepo:   inventoryRepo,
 		transactionRepo: transactionRepo,
+		metrics:         noopBusinessMetrics(),
+		tracer:          noopTracer(),
+		transactor:      noopTransactor{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// applyMovement applies quantity deltas to an inventory row and records the
+// transactions of the movement in one unit of work. Only once it committed
+// are the availability cache updated and the transaction handlers notified;
+// nested in an enclosing unit of work, once that one committed.
+// Movements of batched products made outside of an enclosing unit of work
+// are handed to the write batcher, which writes them together with their
+// transactions in the unit of work of their batch.
+func (s *InventoryService) applyMovement(ctx context.Context, productID, inventoryID string, quantityDelta, reservedDelta int64, transactions ...*domain.Transaction) error {
+	return s.applyCheckedMovement(ctx, productID, inventoryID, quantityDelta, reservedDelta, nil, transactions...)
+}
+
+// applyCheckedMovement is applyMovement with a check of the updated inventory
+// row: it runs in the unit of work while the update holds the row lock, and
+// an error from it rolls the movement back. Checked movements are never
+// batched, so that the check sees the deltas applied.
+func (s *InventoryService) applyCheckedMovement(ctx context.Context, productID, inventoryID string, quantityDelta, reservedDelta int64, check movementCheck, transactions ...*domain.Transaction) (err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.applyMovement",
+		attribute.String("inventory.product_id", productID),
+		attribute.String("inventory.inventory_id", inventoryID),
+		attribute.Int64("inventory.quantity_delta", quantityDelta),
+		attribute.Int64("inventory.reserved_delta", reservedDelta),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if s.batcher != nil && check == nil && !repository.InTransaction(ctx) && (s.hotProducts == nil || s.hotProducts[productID]) {
+		err = s.batcher.Apply(ctx, inventoryID, quantityDelta, reservedDelta, transactions...)
+	} else {
+		err = s.writeMovement(ctx, inventoryID, quantityDelta, reservedDelta, check, transactions...)
 	}
+	if err != nil {
+		return err
+	}
+
+	whenCommitted(ctx, func(ctx context.Context) {
+		if s.availability != nil {
+			s.availability.ApplyDelta(productID, quantityDelta, reservedDelta)
+		}
+		s.notifyTransactions(ctx, transactions...)
+	})
+	return nil
+}
+
+// writeMovement writes quantity deltas of an inventory row, checks the
+// updated row and records the transactions of the movement in one unit of
+// work
+func (s *InventoryService) writeMovement(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64, check movementCheck, transactions ...*domain.Transaction) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.UpdateQuantity(ctx, inventoryID, quantityDelta, reservedDelta); err != nil {
+			return err
+		}
+
+		if check != nil {
+			item, err := s.inventoryRepo.GetByID(ctx, inventoryID)
+			if err != nil {
+				return err
+			}
+			if err := check(ctx, item); err != nil {
+				return err
+			}
+		}
+
+		for _, transaction := range transactions {
+			if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+				return fmt.Errorf("failed to record transaction: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// resolveInventory returns the new stock of a product at a location, or at
+// its default location when location is empty
+func (s *InventoryService) resolveInventory(ctx context.Context, productID, location string) (*domain.InventoryItem, error) {
+	return s.resolveStock(ctx, productID, location, "")
+}
+
+// resolveStock returns the inventory item of a product at a location in a
+// condition. An empty location selects the product's default location and an
+// empty condition new stock.
+func (s *InventoryService) resolveStock(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	condition, err := domain.ParseStockCondition(string(condition))
+	if err != nil {
+		return nil, err
+	}
+
+	if location == "" {
+		item, err := s.inventoryRepo.GetByProductID(ctx, productID)
+		if err != nil || item == nil || item.Condition == condition {
+			return item, err
+		}
+		location = item.Location
+	}
+	return s.inventoryRepo.GetByProductAndLocation(ctx, productID, location, condition)
+}
+
+// ensureWarehouse sets the warehouse of an inventory item from its location,
+// registering the warehouse on first use
+func (s *InventoryService) ensureWarehouse(ctx context.Context, item *domain.InventoryItem) error {
+	if s.warehouseRepo == nil || item.Location == "" {
+		return nil
+	}
+
+	warehouse, err := s.warehouseRepo.GetByCode(ctx, item.Location)
+	if err != nil {
+		warehouse = &domain.Warehouse{Code: item.Location, Name: item.Location}
+		if createErr := s.warehouseRepo.Create(ctx, warehouse); createErr != nil {
+			return fmt.Errorf("failed to register warehouse %s: %w", item.Location, createErr)
+		}
+	}
+
+	item.WarehouseID = warehouse.ID
+	return nil
 }
 
 // CreateProduct creates a new product and initializes inventory
@@ -35,6 +278,16 @@ func (s *InventoryService) CreateProduct(ctx context.Context, product *domain.Pr
 		return fmt.Errorf("invalid product: %w", err)
 	}
 
+	// Report a SKU in use without attempting the insert; the unique
+	// constraint still settles concurrent creations
+	existing, err := s.productRepo.GetBySKU(ctx, product.SKU)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("failed to check SKU: %w", err)
+	}
+	if err == nil && existing != nil {
+		return &domain.DuplicateSKUError{SKU: product.SKU}
+	}
+
 	// Create product
 	if err := s.productRepo.Create(ctx, product); err != nil {
 		return fmt.Errorf("failed to create product: %w", err)
@@ -48,30 +301,80 @@ func (s *InventoryService) CreateProduct(ctx context.Context, product *domain.Pr
 		Location:  location,
 	}
 
-	if err := s.inventoryRepo.Create(ctx, inventoryItem); err != nil {
+	if err := s.ensureWarehouse(ctx, inventoryItem); err != nil {
+		_ = s.productRepo.Delete(ctx, product.ID)
+		return err
+	}
+
+	initial, err := s.createInventory(ctx, inventoryItem, "Initial stock entry")
+	if err != nil {
 		// Clean up product if inventory creation fails
 		_ = s.productRepo.Delete(ctx, product.ID)
-		return fmt.Errorf("failed to create inventory: %w", err)
+		return err
 	}
 
-	// Record initial stock transaction
-	if initialQuantity > 0 {
-		transaction := &domain.Transaction{
-			InventoryID: inventoryItem.ID,
-			ProductID:   product.ID,
+	whenCommitted(ctx, func(ctx context.Context) {
+		if s.availability != nil {
+			s.availability.Set(inventoryItem)
+		}
+		if initial != nil {
+			s.notifyTransactions(ctx, initial)
+		}
+	})
+
+	return nil
+}
+
+// createInventory creates an inventory item and, if it starts with stock, the
+// INITIAL_STOCK transaction recording it, in one unit of work
+func (s *InventoryService) createInventory(ctx context.Context, item *domain.InventoryItem, notes string) (*domain.Transaction, error) {
+	var initial *domain.Transaction
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.Create(ctx, item); err != nil {
+			return fmt.Errorf("failed to create inventory: %w", err)
+		}
+		if item.Quantity <= 0 {
+			return nil
+		}
+
+		initial = &domain.Transaction{
+			InventoryID: item.ID,
+			ProductID:   item.ProductID,
 			Type:        "IN",
-			Quantity:    initialQuantity,
+			Quantity:    item.Quantity,
 			Reference:   "INITIAL_STOCK",
-			Notes:       "Initial stock entry",
+			Notes:       notes,
 		}
-		_ = s.transactionRepo.Create(ctx, transaction)
+		if err := s.transactionRepo.Create(ctx, initial); err != nil {
+			return fmt.Errorf("failed to record initial stock: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return initial, nil
 }
 
 // GetProduct retrieves a product with its inventory details
 func (s *InventoryService) GetProduct(ctx context.Context, productID string) (*domain.Product, *domain.InventoryItem, error) {
+	if s.productReads == nil {
+		return s.getProduct(ctx, productID)
+	}
+
+	read, shared, err := s.productReads.do(ctx, productID, func(ctx context.Context) (productRead, error) {
+		product, inventory, err := s.getProduct(ctx, productID)
+		return productRead{product: product, inventory: inventory}, err
+	})
+	s.metrics.recordRead(ctx, "product", shared)
+	if err != nil {
+		return nil, nil, err
+	}
+	return read.product, read.inventory, nil
+}
+
+// getProduct reads a product with its default inventory
+func (s *InventoryService) getProduct(ctx context.Context, productID string) (*domain.Product, *domain.InventoryItem, error) {
 	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get product: %w", err)
@@ -82,16 +385,171 @@ func (s *InventoryService) GetProduct(ctx context.Context, productID string) (*d
 		return nil, nil, fmt.Errorf("failed to get inventory: %w", err)
 	}
 
+	if err := s.loadTranslations(ctx, []*domain.Product{product}); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := s.projectStockouts(ctx, productID, inventory); err != nil {
+		return nil, nil, err
+	}
+
 	return product, inventory, nil
 }
 
-// ListProducts lists all products with pagination
-func (s *InventoryService) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	products, err := s.productRepo.List(ctx, limit, offset)
+// GetProductBySKU retrieves a product with its inventory details by SKU
+func (s *InventoryService) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, *domain.InventoryItem, error) {
+	productID, err := s.ResolveSKU(ctx, sku)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.GetProduct(ctx, productID)
+}
+
+// ResolveSKU returns the ID of the product with the given SKU, so callers
+// that only know SKUs can use the ID-based operations
+func (s *InventoryService) ResolveSKU(ctx context.Context, sku string) (string, error) {
+	if sku == "" {
+		return "", domain.NewValidationError("sku cannot be empty")
+	}
+
+	product, err := s.productRepo.GetBySKU(ctx, sku)
+	if err != nil {
+		return "", fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return "", fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	return product.ID, nil
+}
+
+// ListProducts lists one page of the products matching filter together
+// with the total count. Archived products are only listed with
+// includeArchived.
+func (s *InventoryService) ListProducts(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) (*domain.Page[*domain.Product], error) {
+	products, err := s.productRepo.List(ctx, limit, offset, includeArchived, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
-	return products, nil
+
+	if err := s.loadTranslations(ctx, products); err != nil {
+		return nil, err
+	}
+
+	total, err := s.productRepo.Count(ctx, includeArchived, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	if products == nil {
+		products = []*domain.Product{}
+	}
+
+	page := &domain.Page[*domain.Product]{Items: products, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(products); len(products) > 0 && int64(next) < total {
+		page.NextOffset = &next
+	}
+	return page, nil
+}
+
+// loadTranslations attaches stored translations to products with a single query
+func (s *InventoryService) loadTranslations(ctx context.Context, products []*domain.Product) error {
+	if s.translationRepo == nil || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		if product != nil {
+			ids = append(ids, product.ID)
+		}
+	}
+
+	translations, err := s.translationRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get product translations: %w", err)
+	}
+
+	for _, product := range products {
+		if product != nil {
+			product.Translations = translations[product.ID]
+		}
+	}
+	return nil
+}
+
+// LoadIncludes attaches the requested related resources to products, with
+// one batched query per resource however many products there are
+func (s *InventoryService) LoadIncludes(ctx context.Context, products []*domain.Product, includes domain.ProductIncludes) error {
+	if !includes.Any() || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		if product != nil {
+			ids = append(ids, product.ID)
+		}
+	}
+
+	if includes.Inventory {
+		inventory, err := s.inventoryRepo.GetByProductIDs(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to get inventory: %w", err)
+		}
+		for _, product := range products {
+			if product != nil {
+				product.Inventory = inventory[product.ID]
+			}
+		}
+	}
+
+	if includes.Transactions {
+		transactions, err := s.transactionRepo.RecentByProductIDs(ctx, ids, domain.IncludedTransactions)
+		if err != nil {
+			return fmt.Errorf("failed to get transactions: %w", err)
+		}
+		for _, product := range products {
+			if product != nil {
+				product.Transactions = transactions[product.ID]
+			}
+		}
+	}
+	return nil
+}
+
+// SetProductTranslation creates or replaces a product's translation for a locale
+func (s *InventoryService) SetProductTranslation(ctx context.Context, productID, locale string, translation domain.ProductTranslation) error {
+	if s.translationRepo == nil {
+		return errors.New("product translations are not enabled")
+	}
+	if locale == "" {
+		return domain.NewValidationError("locale cannot be empty")
+	}
+	if err := translation.Validate(); err != nil {
+		return fmt.Errorf("invalid translation: %w", err)
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if err := s.translationRepo.Upsert(ctx, productID, locale, translation); err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+// DeleteProductTranslation removes a product's translation for a locale
+func (s *InventoryService) DeleteProductTranslation(ctx context.Context, productID, locale string) error {
+	if s.translationRepo == nil {
+		return errors.New("product translations are not enabled")
+	}
+
+	if err := s.translationRepo.Delete(ctx, productID, locale); err != nil {
+		return fmt.Errorf("failed to delete translation: %w", err)
+	}
+	return nil
 }
 
 // UpdateProduct updates product details
@@ -107,23 +565,66 @@ func (s *InventoryService) UpdateProduct(ctx context.Context, product *domain.Pr
 	return nil
 }
 
-// AddStock adds stock to inventory
+// PatchProduct updates the product fields set in the patch, leaving the
+// others unchanged, and returns the patched product
+func (s *InventoryService) PatchProduct(ctx context.Context, productID string, patch domain.ProductPatch) (*domain.Product, error) {
+	if patch.Empty() {
+		return nil, domain.NewValidationError("patch must set at least one field")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	// Validate the product as it would be patched
+	candidate := *product
+	patch.Apply(&candidate)
+	if err := candidate.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid product: %w", err)
+	}
+
+	patched, err := s.productRepo.Patch(ctx, productID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+	return patched, nil
+}
+
+// AddStock adds stock to inventory at the product's default location
 func (s *InventoryService) AddStock(ctx context.Context, productID string, quantity int64, reference string) error {
+	return s.AddStockAt(ctx, productID, "", "", quantity, reference)
+}
+
+// AddStockAt adds stock to inventory at a location in a condition; an empty
+// location selects the product's default location and an empty condition new
+// stock
+func (s *InventoryService) AddStockAt(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string) error {
+	return s.AddStockAtCost(ctx, productID, location, condition, quantity, nil, reference)
+}
+
+// AddStockAtCost adds stock like AddStockAt, recording the cost of one unit
+// received, if not nil, for inventory valuation
+func (s *InventoryService) AddStockAtCost(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, unitCost *float64, reference string) (err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.AddStock", stockAttributes(productID, location, quantity)...)
+	defer func() { endSpan(span, err) }()
+
 	if quantity <= 0 {
-		return errors.New("quantity must be positive")
+		return domain.NewValidationError("quantity must be positive")
+	}
+	if unitCost != nil && *unitCost < 0 {
+		return domain.NewValidationError("unit cost cannot be negative")
 	}
 
-	inventory, err := s.inventoryRepo.GetByProductID(ctx, productID)
+	inventory, err := s.resolveStock(ctx, productID, location, condition)
 	if err != nil {
 		return fmt.Errorf("failed to get inventory: %w", err)
 	}
 
-	// Update quantity
-	if err := s.inventoryRepo.UpdateQuantity(ctx, inventory.ID, quantity, 0); err != nil {
-		return fmt.Errorf("failed to update quantity: %w", err)
+	movement := StockMovement{ProductID: productID, Location: inventory.Location, Condition: inventory.Condition, Type: "IN", Quantity: quantity, Reference: reference, UnitCost: unitCost}
+	if err := s.checkFrozen(ctx, movement); err != nil {
+		return err
 	}
 
-	// Record transaction
 	transaction := &domain.Transaction{
 		InventoryID: inventory.ID,
 		ProductID:   productID,
@@ -131,37 +632,100 @@ func (s *InventoryService) AddStock(ctx context.Context, productID string, quant
 		Quantity:    quantity,
 		Reference:   reference,
 		Notes:       "Stock addition",
+		UnitCost:    unitCost,
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to record transaction: %w", err)
+	if err := s.applyMovement(ctx, productID, inventory.ID, quantity, 0, transaction); err != nil {
+		return fmt.Errorf("failed to add stock: %w", err)
 	}
 
 	return nil
 }
 
-// RemoveStock removes stock from inventory
+// RemoveStock removes stock from inventory at the product's default location
 func (s *InventoryService) RemoveStock(ctx context.Context, productID string, quantity int64, reference string) error {
+	return s.RemoveStockAt(ctx, productID, "", "", quantity, reference)
+}
+
+// RemoveStockAt removes stock from inventory at a location in a condition; an
+// empty location selects the product's default location and an empty
+// condition new stock
+func (s *InventoryService) RemoveStockAt(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string) error {
+	return s.removeStock(ctx, productID, location, condition, quantity, nil, reference)
+}
+
+// RemoveStockIfAvailable removes stock like RemoveStockAt, but only if at
+// least minAvailable remain available afterwards. Otherwise it changes nothing
+// and returns an error wrapping domain.ErrPreconditionFailed. Conditional
+// removals at a location with an open stocktake are rejected rather than
+// queued, as the condition could not be checked when they are replayed.
+func (s *InventoryService) RemoveStockIfAvailable(ctx context.Context, productID, location string, condition domain.StockCondition, quantity, minAvailable int64, reference string) error {
+	if minAvailable < 0 {
+		return domain.NewValidationError("expected_available must not be negative")
+	}
+	return s.removeStock(ctx, productID, location, condition, quantity, &minAvailable, reference)
+}
+
+// removeStock removes stock, leaving at least minAvailable available unless
+// it is nil
+func (s *InventoryService) removeStock(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, minAvailable *int64, reference string) (err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.RemoveStock", stockAttributes(productID, location, quantity)...)
+	defer func() { endSpan(span, err) }()
+
 	if quantity <= 0 {
-		return errors.New("quantity must be positive")
+		return domain.NewValidationError("quantity must be positive")
 	}
 
-	inventory, err := s.inventoryRepo.GetByProductID(ctx, productID)
+	// A removal queued behind a stocktake is committed like one applied
+	var queued error
+	err = s.withProductLock(ctx, productID, "remove", func(ctx context.Context) error {
+		err := s.removeLocked(ctx, productID, location, condition, quantity, minAvailable, reference)
+		if errors.Is(err, ErrMovementQueued) {
+			queued = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return queued
+}
+
+// removeLocked checks and removes stock, holding the product lock if enabled
+func (s *InventoryService) removeLocked(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, minAvailable *int64, reference string) error {
+	inventory, err := s.resolveStock(ctx, productID, location, condition)
 	if err != nil {
 		return fmt.Errorf("failed to get inventory: %w", err)
 	}
 
+	// Queued removals are checked against the counted stock when replayed
+	movement := StockMovement{ProductID: productID, Location: inventory.Location, Condition: inventory.Condition, Type: "OUT", Quantity: quantity, Reference: reference}
+	if minAvailable != nil {
+		err = s.rejectFrozen(ctx, inventory.Location)
+	} else {
+		err = s.checkFrozen(ctx, movement)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Check if enough stock is available
 	if inventory.AvailableQuantity() < quantity {
-		return errors.New("insufficient stock available")
+		s.metrics.recordOversell(ctx, "remove")
+		return fmt.Errorf("%w available", domain.ErrInsufficientStock)
+	}
+	check := availableAtLeast(minAvailable)
+	if check != nil {
+		if err := check(ctx, &domain.InventoryItem{Quantity: inventory.Quantity - quantity, Reserved: inventory.Reserved}); err != nil {
+			return err
+		}
 	}
 
-	// Update quantity
-	if err := s.inventoryRepo.UpdateQuantity(ctx, inventory.ID, -quantity, 0); err != nil {
-		return fmt.Errorf("failed to update quantity: %w", err)
+	if err := s.approveMovement(ctx, movement); err != nil {
+		return err
 	}
 
-	// Record transaction
 	transaction := &domain.Transaction{
 		InventoryID: inventory.ID,
 		ProductID:   productID,
@@ -170,36 +734,164 @@ func (s *InventoryService) RemoveStock(ctx context.Context, productID string, qu
 		Reference:   reference,
 		Notes:       "Stock removal",
 	}
+	if s.lotRepo != nil {
+		check = s.consumeLots(quantity, transaction, check)
+	}
+
+	if err := s.applyCheckedMovement(ctx, productID, inventory.ID, -quantity, 0, check, transaction); err != nil {
+		return fmt.Errorf("failed to remove stock: %w", err)
+	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to record transaction: %w", err)
+	return nil
+}
+
+// consumeLots extends the check of a removal of quantity units to take them
+// off the lots of the inventory row, first expiring first, naming the lots
+// in the notes of the removal's transaction. The update of the row holds its
+// lock, so concurrent removals consume the lots one after the other.
+func (s *InventoryService) consumeLots(quantity int64, transaction *domain.Transaction, check movementCheck) movementCheck {
+	return func(ctx context.Context, item *domain.InventoryItem) error {
+		if check != nil {
+			if err := check(ctx, item); err != nil {
+				return err
+			}
+		}
+
+		consumptions, err := s.takeFromLots(ctx, item.ID, quantity, item.Quantity+quantity)
+		if err != nil {
+			return err
+		}
+		if len(consumptions) > 0 {
+			transaction.Notes += " from " + domain.LotNotes(consumptions)
+		}
+		return nil
+	}
+}
+
+// takeFromLots takes quantity units of a removal off the open lots of an
+// inventory row in the unit of work of ctx, first expiring first. onHand is
+// the quantity of the row before the removal. Rows without lots are left
+// alone.
+func (s *InventoryService) takeFromLots(ctx context.Context, inventoryID string, quantity, onHand int64) ([]domain.LotConsumption, error) {
+	lots, err := s.lotRepo.LockOpen(ctx, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lots) == 0 {
+		return nil, nil
+	}
+	consumptions, err := domain.ConsumeLots(lots, quantity, onHand, usageDay(time.Now()))
+	if err != nil {
+		return nil, err
 	}
+	for _, consumption := range consumptions {
+		if err := s.lotRepo.Consume(ctx, consumption.Lot.ID, consumption.Quantity); err != nil {
+			return nil, err
+		}
+	}
+	return consumptions, nil
+}
 
+// moveLots takes the units of a transfer off the lots of its source row and
+// receives them into lots of the same numbers at its destination, so that
+// they keep their expiry dates. source is the source row after the transfer.
+func (s *InventoryService) moveLots(ctx context.Context, source *domain.InventoryItem, destinationID string, quantity int64) error {
+	consumptions, err := s.takeFromLots(ctx, source.ID, quantity, source.Quantity+quantity)
+	if err != nil {
+		return err
+	}
+	for _, consumption := range consumptions {
+		_, err := s.lotRepo.Receive(ctx, &domain.Lot{
+			ID:          uuid.New().String(),
+			InventoryID: destinationID,
+			LotNumber:   consumption.Lot.LotNumber,
+			ExpiresOn:   consumption.Lot.ExpiresOn,
+			Quantity:    consumption.Quantity,
+			ReceivedAt:  consumption.Lot.ReceivedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move lot %s: %w", consumption.Lot.LotNumber, err)
+		}
+	}
 	return nil
 }
 
-// ReserveStock reserves stock for an order
+// ReserveStock reserves stock for an order at the product's default location
 func (s *InventoryService) ReserveStock(ctx context.Context, productID string, quantity int64, reference string) error {
+	return s.ReserveStockAt(ctx, productID, "", "", quantity, reference)
+}
+
+// ReserveStockAt reserves stock for an order at a location in a condition; an
+// empty location selects the product's default location and an empty
+// condition new stock
+func (s *InventoryService) ReserveStockAt(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string) error {
+	_, err := s.reserveAt(ctx, productID, location, condition, quantity, nil, reference)
+	return err
+}
+
+// ReserveStockIfAvailable reserves stock like ReserveStockAt, but only if at
+// least minAvailable remain available afterwards. Otherwise it changes
+// nothing and returns an error wrapping domain.ErrPreconditionFailed.
+func (s *InventoryService) ReserveStockIfAvailable(ctx context.Context, productID, location string, condition domain.StockCondition, quantity, minAvailable int64, reference string) error {
+	if minAvailable < 0 {
+		return domain.NewValidationError("expected_available must not be negative")
+	}
+	_, err := s.reserveAt(ctx, productID, location, condition, quantity, &minAvailable, reference)
+	return err
+}
+
+// reserveAt reserves stock, leaving at least minAvailable available unless it
+// is nil, and returns the inventory item it was reserved from
+func (s *InventoryService) reserveAt(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, minAvailable *int64, reference string) (inventory *domain.InventoryItem, err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.ReserveStock", stockAttributes(productID, location, quantity)...)
+	defer func() {
+		if err != nil {
+			s.metrics.recordReservationFailure(ctx, err)
+		}
+		endSpan(span, err)
+	}()
+
 	if quantity <= 0 {
-		return errors.New("quantity must be positive")
+		return nil, domain.NewValidationError("quantity must be positive")
 	}
 
-	inventory, err := s.inventoryRepo.GetByProductID(ctx, productID)
+	err = s.withProductLock(ctx, productID, "reserve", func(ctx context.Context) error {
+		item, err := s.reserveLocked(ctx, productID, location, condition, quantity, minAvailable, reference)
+		inventory = item
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get inventory: %w", err)
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// reserveLocked checks and reserves stock, holding the product lock if enabled
+func (s *InventoryService) reserveLocked(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, minAvailable *int64, reference string) (*domain.InventoryItem, error) {
+	if err := s.checkReleased(ctx, productID); err != nil {
+		return nil, err
+	}
+	if err := s.checkReservationQuota(ctx, productID, reference, quantity); err != nil {
+		return nil, err
+	}
+
+	inventory, err := s.resolveStock(ctx, productID, location, condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
 	}
 
 	// Check if enough stock is available
 	if inventory.AvailableQuantity() < quantity {
-		return errors.New("insufficient stock available for reservation")
+		s.metrics.recordOversell(ctx, "reserve")
+		return nil, fmt.Errorf("%w available for reservation", domain.ErrInsufficientStock)
 	}
-
-	// Update reserved quantity
-	if err := s.inventoryRepo.UpdateQuantity(ctx, inventory.ID, 0, quantity); err != nil {
-		return fmt.Errorf("failed to reserve stock: %w", err)
+	check := availableAtLeast(minAvailable)
+	if check != nil {
+		if err := check(ctx, &domain.InventoryItem{Quantity: inventory.Quantity, Reserved: inventory.Reserved + quantity}); err != nil {
+			return nil, err
+		}
 	}
 
-	// Record transaction
 	transaction := &domain.Transaction{
 		InventoryID: inventory.ID,
 		ProductID:   productID,
@@ -209,35 +901,57 @@ func (s *InventoryService) ReserveStock(ctx context.Context, productID string, q
 		Notes:       "Stock reservation",
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to record transaction: %w", err)
+	if err := s.applyCheckedMovement(ctx, productID, inventory.ID, 0, quantity, check, transaction); err != nil {
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
 	}
 
-	return nil
+	return inventory, nil
+}
+
+// movementCheck checks the inventory row a movement updated, in its unit of
+// work
+type movementCheck func(ctx context.Context, item *domain.InventoryItem) error
+
+// availableAtLeast returns the check that an inventory item has at least
+// minAvailable available, or nil when minAvailable is nil
+func availableAtLeast(minAvailable *int64) movementCheck {
+	if minAvailable == nil {
+		return nil
+	}
+	return func(_ context.Context, item *domain.InventoryItem) error {
+		if available := item.AvailableQuantity(); available < *minAvailable {
+			return fmt.Errorf("%w: %d would remain available, expected at least %d", domain.ErrPreconditionFailed, available, *minAvailable)
+		}
+		return nil
+	}
 }
 
-// UnreserveStock releases reserved stock
+// UnreserveStock releases reserved stock at the product's default location
 func (s *InventoryService) UnreserveStock(ctx context.Context, productID string, quantity int64, reference string) error {
+	return s.UnreserveStockAt(ctx, productID, "", "", quantity, reference)
+}
+
+// UnreserveStockAt releases reserved stock at a location in a condition; an
+// empty location selects the product's default location and an empty
+// condition new stock
+func (s *InventoryService) UnreserveStockAt(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string) (err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.UnreserveStock", stockAttributes(productID, location, quantity)...)
+	defer func() { endSpan(span, err) }()
+
 	if quantity <= 0 {
-		return errors.New("quantity must be positive")
+		return domain.NewValidationError("quantity must be positive")
 	}
 
-	inventory, err := s.inventoryRepo.GetByProductID(ctx, productID)
+	inventory, err := s.resolveStock(ctx, productID, location, condition)
 	if err != nil {
 		return fmt.Errorf("failed to get inventory: %w", err)
 	}
 
 	// Check if enough reserved stock exists
 	if inventory.Reserved < quantity {
-		return errors.New("insufficient reserved stock")
-	}
-
-	// Update reserved quantity
-	if err := s.inventoryRepo.UpdateQuantity(ctx, inventory.ID, 0, -quantity); err != nil {
-		return fmt.Errorf("failed to unreserve stock: %w", err)
+		return fmt.Errorf("%w reserved", domain.ErrInsufficientStock)
 	}
 
-	// Record transaction
 	transaction := &domain.Transaction{
 		InventoryID: inventory.ID,
 		ProductID:   productID,
@@ -247,36 +961,509 @@ func (s *InventoryService) UnreserveStock(ctx context.Context, productID string,
 		Notes:       "Stock unreservation",
 	}
 
-	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
-		return fmt.Errorf("failed to record transaction: %w", err)
+	if err := s.applyMovement(ctx, productID, inventory.ID, 0, -quantity, transaction); err != nil {
+		return fmt.Errorf("failed to unreserve stock: %w", err)
 	}
 
 	return nil
 }
 
+// TransferStock moves available new stock of a product between two
+// locations. Both quantity changes and the paired TRANSFER_OUT/TRANSFER_IN
+// transactions are committed atomically. A destination the product is not
+// stocked at yet is created empty first. With lots tracked the units move
+// with their lots, first expiring first.
+func (s *InventoryService) TransferStock(ctx context.Context, productID, fromLocation, toLocation string, quantity int64, reference string) (err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.TransferStock", append(stockAttributes(productID, fromLocation, quantity), attribute.String("inventory.to_location", toLocation))...)
+	defer func() { endSpan(span, err) }()
+
+	if quantity <= 0 {
+		return domain.NewValidationError("quantity must be positive")
+	}
+	if fromLocation == "" || toLocation == "" {
+		return domain.NewValidationError("source and destination locations are required")
+	}
+	if fromLocation == toLocation {
+		return domain.NewValidationError("source and destination locations must differ")
+	}
+
+	for _, movement := range []StockMovement{
+		{ProductID: productID, Location: fromLocation, Type: "TRANSFER_OUT", Quantity: quantity, Reference: reference},
+		{ProductID: productID, Location: toLocation, Type: "TRANSFER_IN", Quantity: quantity, Reference: reference},
+	} {
+		if err := s.checkFrozen(ctx, movement); err != nil {
+			return err
+		}
+	}
+
+	source, err := s.inventoryRepo.GetByProductAndLocation(ctx, productID, fromLocation, domain.ConditionNew)
+	if err != nil {
+		return fmt.Errorf("failed to get source inventory: %w", err)
+	}
+
+	if source.AvailableQuantity() < quantity {
+		s.metrics.recordOversell(ctx, "transfer")
+		return fmt.Errorf("%w available for transfer", domain.ErrInsufficientStock)
+	}
+
+	destination, err := s.inventoryRepo.GetByProductAndLocation(ctx, productID, toLocation, domain.ConditionNew)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("failed to get destination inventory: %w", err)
+		}
+		destination, err = s.CreateInventoryAt(ctx, productID, toLocation, domain.ConditionNew, 0)
+		if err != nil {
+			return fmt.Errorf("failed to create destination inventory: %w", err)
+		}
+	}
+
+	out := &domain.Transaction{
+		InventoryID: source.ID,
+		ProductID:   productID,
+		Type:        "TRANSFER_OUT",
+		Quantity:    quantity,
+		Reference:   reference,
+		Notes:       "Transfer to " + toLocation,
+	}
+	in := &domain.Transaction{
+		InventoryID: destination.ID,
+		ProductID:   productID,
+		Type:        "TRANSFER_IN",
+		Quantity:    quantity,
+		Reference:   reference,
+		Notes:       "Transfer from " + fromLocation,
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.Transfer(ctx, source.ID, destination.ID, quantity, out, in); err != nil {
+			return fmt.Errorf("failed to transfer stock: %w", err)
+		}
+		if s.lotRepo == nil {
+			return nil
+		}
+		moved, err := s.inventoryRepo.GetByID(ctx, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get source inventory: %w", err)
+		}
+		return s.moveLots(ctx, moved, destination.ID, quantity)
+	})
+	if err != nil {
+		return err
+	}
+	whenCommitted(ctx, func(ctx context.Context) {
+		s.notifyTransactions(ctx, out, in)
+	})
+
+	return nil
+}
+
+// SetStockCount overwrites the on-hand quantity of a product at a location,
+// typically after a physical count. The update only succeeds if the item is
+// still at the given version; otherwise domain.ErrConflict is returned and the
+// caller should re-read the item and retry. The difference to the previous
+// quantity is recorded as an IN or OUT adjustment transaction.
+func (s *InventoryService) SetStockCount(ctx context.Context, productID, location string, quantity, version int64, reference string) (_ *domain.InventoryItem, err error) {
+	ctx, span := s.startSpan(ctx, "InventoryService.SetStockCount", stockAttributes(productID, location, quantity)...)
+	defer func() { endSpan(span, err) }()
+
+	if quantity < 0 {
+		return nil, domain.NewValidationError("quantity cannot be negative")
+	}
+
+	current, err := s.resolveInventory(ctx, productID, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	if current.Version != version {
+		return nil, fmt.Errorf("%w: inventory is at version %d, not %d", domain.ErrConflict, current.Version, version)
+	}
+	if quantity < current.Reserved {
+		return nil, domain.NewValidationError("quantity cannot be below the %d reserved units", current.Reserved)
+	}
+
+	item := *current
+	item.Quantity = quantity
+
+	delta := quantity - current.Quantity
+	var transaction *domain.Transaction
+	if delta != 0 {
+		transaction = &domain.Transaction{
+			InventoryID: item.ID,
+			ProductID:   productID,
+			Type:        "IN",
+			Quantity:    delta,
+			Reference:   reference,
+			Notes:       "Stock count adjustment",
+		}
+		if delta < 0 {
+			transaction.Type = "OUT"
+			transaction.Quantity = -delta
+		}
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.Update(ctx, &item); err != nil {
+			return fmt.Errorf("failed to update inventory: %w", err)
+		}
+		if transaction == nil {
+			return nil
+		}
+		if s.lotRepo != nil && delta < 0 {
+			consumptions, err := s.takeFromLots(ctx, item.ID, -delta, current.Quantity)
+			if err != nil {
+				return err
+			}
+			if len(consumptions) > 0 {
+				transaction.Notes += " from " + domain.LotNotes(consumptions)
+			}
+		}
+		if err := s.transactionRepo.Create(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to record transaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction != nil {
+		whenCommitted(ctx, func(ctx context.Context) {
+			if s.availability != nil {
+				s.availability.ApplyDelta(productID, delta, 0)
+			}
+			s.notifyTransactions(ctx, transaction)
+		})
+	}
+
+	return &item, nil
+}
+
 // GetInventory retrieves inventory details for a product
 func (s *InventoryService) GetInventory(ctx context.Context, productID string) (*domain.InventoryItem, error) {
-	inventory, err := s.inventoryRepo.GetByProductID(ctx, productID)
+	read := func(ctx context.Context) (*domain.InventoryItem, error) {
+		return s.inventoryRepo.GetByProductID(ctx, productID)
+	}
+
+	var inventory *domain.InventoryItem
+	var err error
+	if s.inventoryReads == nil {
+		inventory, err = read(ctx)
+	} else {
+		var shared bool
+		inventory, shared, err = s.inventoryReads.do(ctx, productID, read)
+		s.metrics.recordRead(ctx, "inventory", shared)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	return inventory, nil
+}
+
+// GetInventoryAt retrieves the inventory of a product at one location in a
+// condition, new stock when condition is empty
+func (s *InventoryService) GetInventoryAt(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	condition, err := domain.ParseStockCondition(string(condition))
+	if err != nil {
+		return nil, err
+	}
+
+	inventory, err := s.inventoryRepo.GetByProductAndLocation(ctx, productID, location, condition)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get inventory: %w", err)
 	}
+
+	if _, err := s.projectStockouts(ctx, productID, inventory); err != nil {
+		return nil, err
+	}
+
 	return inventory, nil
 }
 
-// ListTransactions lists transactions for a product
-func (s *InventoryService) ListTransactions(ctx context.Context, productID string, limit, offset int) ([]*domain.Transaction, error) {
-	transactions, err := s.transactionRepo.GetByProductID(ctx, productID, limit, offset)
+// ListInventory lists one page of the inventory rows of every product
+// matching filter, newest first, together with the total count
+func (s *InventoryService) ListInventory(ctx context.Context, limit, offset int, filter *domain.Filter) (*domain.Page[*domain.InventoryItem], error) {
+	items, err := s.inventoryRepo.List(ctx, limit, offset, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory: %w", err)
+	}
+
+	total, err := s.inventoryRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count inventory: %w", err)
+	}
+
+	if items == nil {
+		items = []*domain.InventoryItem{}
+	}
+
+	page := &domain.Page[*domain.InventoryItem]{Items: items, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(items); len(items) > 0 && int64(next) < total {
+		page.NextOffset = &next
+	}
+	return page, nil
+}
+
+// ListStockByLocation lists the inventory of a product at every location
+func (s *InventoryService) ListStockByLocation(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
+	items, err := s.inventoryRepo.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory: %w", err)
+	}
+	return items, nil
+}
+
+// GetStockLevel aggregates the inventory of a product across all locations,
+// with projected stockout times from recent sales. A condition restricts it
+// to the stock in that condition; an empty one counts every condition.
+func (s *InventoryService) GetStockLevel(ctx context.Context, productID string, condition domain.StockCondition) (*domain.StockLevel, error) {
+	if condition != "" {
+		parsed, err := domain.ParseStockCondition(string(condition))
+		if err != nil {
+			return nil, err
+		}
+		condition = parsed
+	}
+
+	if s.stockLevelReads == nil {
+		return s.getStockLevel(ctx, productID, condition)
+	}
+
+	level, shared, err := s.stockLevelReads.do(ctx, productID+"/"+string(condition), func(ctx context.Context) (*domain.StockLevel, error) {
+		return s.getStockLevel(ctx, productID, condition)
+	})
+	s.metrics.recordRead(ctx, "stock_level", shared)
+	return level, err
+}
+
+// getStockLevel reads the stock level of a product with its projected stockout
+func (s *InventoryService) getStockLevel(ctx context.Context, productID string, condition domain.StockCondition) (*domain.StockLevel, error) {
+	level, err := s.stockLevel(ctx, productID, condition)
+	if err != nil {
+		return nil, err
+	}
+
+	level.ProjectedStockoutAt, err = s.projectStockouts(ctx, productID, level.Locations...)
+	if err != nil {
+		return nil, err
+	}
+
+	return level, nil
+}
+
+// stockLevel aggregates the inventory of a product across all locations, in
+// one condition unless condition is empty. A product without stock in the
+// condition has a zero stock level.
+func (s *InventoryService) stockLevel(ctx context.Context, productID string, condition domain.StockCondition) (*domain.StockLevel, error) {
+	items, err := s.ListStockByLocation(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("inventory %w", domain.ErrNotFound)
+	}
+	if condition == "" {
+		return domain.NewStockLevel(productID, items), nil
+	}
+
+	var matching []*domain.InventoryItem
+	for _, item := range items {
+		if item.Condition == condition {
+			matching = append(matching, item)
+		}
+	}
+	level := domain.NewStockLevel(productID, matching)
+	level.Condition = condition
+	return level, nil
+}
+
+// CreateInventoryAt starts stocking an existing product at a new location, or
+// in a new condition at a location it is stocked at. An empty condition is
+// new stock.
+func (s *InventoryService) CreateInventoryAt(ctx context.Context, productID, location string, condition domain.StockCondition, initialQuantity int64) (*domain.InventoryItem, error) {
+	if location == "" {
+		return nil, domain.NewValidationError("location cannot be empty")
+	}
+	condition, err := domain.ParseStockCondition(string(condition))
+	if err != nil {
+		return nil, err
+	}
+	if initialQuantity < 0 {
+		return nil, domain.NewValidationError("initial quantity cannot be negative")
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if existing, err := s.inventoryRepo.GetByProductAndLocation(ctx, productID, location, condition); err == nil && existing != nil {
+		return nil, fmt.Errorf("product is already stocked at %s in condition %s", location, condition)
+	}
+
+	inventoryItem := &domain.InventoryItem{
+		ProductID: productID,
+		Quantity:  initialQuantity,
+		Location:  location,
+		Condition: condition,
+	}
+
+	if err := s.ensureWarehouse(ctx, inventoryItem); err != nil {
+		return nil, err
+	}
+
+	initial, err := s.createInventory(ctx, inventoryItem, "Initial stock entry at "+location)
+	if err != nil {
+		return nil, err
+	}
+
+	whenCommitted(ctx, func(ctx context.Context) {
+		if s.availability != nil {
+			s.availability.ApplyDelta(productID, initialQuantity, 0)
+		}
+		if initial != nil {
+			s.notifyTransactions(ctx, initial)
+		}
+	})
+
+	return inventoryItem, nil
+}
+
+// ListTransactions lists one page of the transactions matching the filter,
+// newest first, together with the total count. An empty filter lists the
+// whole ledger. Pages are selected by offset or, for deep pagination, by the
+// next_cursor of the previous page.
+func (s *InventoryService) ListTransactions(ctx context.Context, filter repository.TransactionFilter, req domain.PageRequest) (*domain.Page[*domain.Transaction], error) {
+	var after *domain.TransactionCursor
+	if req.Cursor != "" {
+		cursor, err := domain.DecodeTransactionCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+		req.Offset = 0
+	}
+
+	transactions, err := s.transactionRepo.Search(ctx, filter, after, req.Limit, req.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transactions: %w", err)
 	}
-	return transactions, nil
+
+	total, err := s.transactionRepo.CountMatching(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	if transactions == nil {
+		transactions = []*domain.Transaction{}
+	}
+	if err := s.attachAnnotations(ctx, transactions); err != nil {
+		return nil, err
+	}
+
+	page := &domain.Page[*domain.Transaction]{Items: transactions, Total: total, Limit: req.Limit, Offset: req.Offset}
+	next := req.Offset + len(transactions)
+	if req.Cursor == "" && len(transactions) > 0 && int64(next) < total {
+		page.NextOffset = &next
+	}
+	if len(transactions) > 0 && len(transactions) == req.Limit && (req.Cursor != "" || page.NextOffset != nil) {
+		page.NextCursor = domain.NewTransactionCursor(transactions[len(transactions)-1]).Encode()
+	}
+	return page, nil
 }
 
-// DeleteProduct deletes a product and its inventory
+// DeleteProduct archives a product. Its inventory and transaction history
+// are kept, and RestoreProduct brings it back.
 func (s *InventoryService) DeleteProduct(ctx context.Context, productID string) error {
-	// This will cascade delete inventory and transactions due to foreign keys
-	if err := s.productRepo.Delete(ctx, productID); err != nil {
+	if err := s.productRepo.Archive(ctx, productID); err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
+
+	s.evictAvailability(productID)
 	return nil
 }
+
+// RestoreProduct brings an archived product back into listings
+func (s *InventoryService) RestoreProduct(ctx context.Context, productID string) (*domain.Product, error) {
+	if err := s.productRepo.Restore(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to restore product: %w", err)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	return product, nil
+}
+
+// CheckAvailability reports for each line whether the requested quantity is
+// available. With an availability cache configured, cached products are
+// answered from memory and only cache misses are read from the database.
+// Ship date estimates read the stock of each location of sufficient products.
+func (s *InventoryService) CheckAvailability(ctx context.Context, checks []AvailabilityCheck) ([]AvailabilityResult, error) {
+	if len(checks) == 0 {
+		return nil, domain.NewValidationError("at least one product must be checked")
+	}
+
+	orderedAt := time.Now()
+	var schedules *shipSchedules
+	if s.shipDefaults != nil {
+		schedules = s.newShipSchedules()
+	}
+
+	results := make([]AvailabilityResult, 0, len(checks))
+	for _, check := range checks {
+		result := AvailabilityResult{ProductID: check.ProductID, Requested: check.Quantity}
+		if check.Condition != "" {
+			condition, err := domain.ParseStockCondition(string(check.Condition))
+			if err != nil {
+				return nil, err
+			}
+			result.Condition = condition
+		}
+
+		releaseAt, err := s.releaseAt(ctx, check.ProductID)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		if releaseAt != nil {
+			// Stock of a scheduled drop is hidden until its release
+			result.ReleaseAt = releaseAt
+			results = append(results, result)
+			continue
+		}
+
+		// The cache holds the stock in every condition
+		available, ok := int64(0), false
+		if s.availability != nil && result.Condition == "" {
+			available, ok = s.availability.Available(ctx, check.ProductID)
+		}
+
+		if !ok {
+			level, err := s.stockLevel(ctx, check.ProductID, result.Condition)
+			if err != nil {
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+			if s.availability != nil && result.Condition == "" {
+				s.availability.SetLevel(level)
+			}
+			available = level.Available
+		}
+
+		result.Available = available
+		result.Sufficient = check.Quantity > 0 && available >= check.Quantity
+		if result.Sufficient && schedules != nil {
+			if err := s.estimateShipDate(ctx, &result, orderedAt, schedules); err != nil {
+				slog.WarnContext(ctx, "failed to estimate ship date", "product_id", check.ProductID, "error", err)
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
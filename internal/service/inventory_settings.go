@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// settingsPatchAttempts bounds how often a settings patch is re-applied when
+// stock movements bump the item's version between reading and writing it
+const settingsPatchAttempts = 3
+
+// PatchInventorySettings applies a JSON Patch to the reorder level, safety
+// stock and bin location of a product's inventory at a location, as the
+// given role. Quantities cannot be patched. Test operations are checked
+// against the settings the patch is applied to, so they guard against
+// concurrent settings changes.
+func (s *InventoryService) PatchInventorySettings(ctx context.Context, productID, location string, operations []domain.PatchOperation, role domain.Role) (*domain.InventoryItem, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := s.resolveInventory(ctx, productID, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory: %w", err)
+		}
+
+		item := *current
+		if err := item.ApplySettingsPatch(operations, role); err != nil {
+			return nil, fmt.Errorf("failed to patch inventory settings: %w", err)
+		}
+
+		err = s.inventoryRepo.UpdateSettings(ctx, &item)
+		if errors.Is(err, domain.ErrConflict) && attempt < settingsPatchAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update inventory settings: %w", err)
+		}
+		return &item, nil
+	}
+}
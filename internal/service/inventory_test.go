@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
 )
 
 // MockProductRepository implements ProductRepository interface for testing
@@ -42,10 +47,12 @@ func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*doma
 	return nil, nil
 }
 
-func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int, includeArchived bool, filter *domain.Filter) ([]*domain.Product, error) {
 	var products []*domain.Product
 	for _, p := range m.products {
-		products = append(products, p)
+		if (includeArchived || !p.Archived()) && filter.Matches(p.FilterValue) {
+			products = append(products, p)
+		}
 	}
 	return products, nil
 }
@@ -55,18 +62,88 @@ func (m *MockProductRepository) Update(ctx context.Context, product *domain.Prod
 	return nil
 }
 
+func (m *MockProductRepository) Patch(ctx context.Context, id string, patch domain.ProductPatch) (*domain.Product, error) {
+	p, ok := m.products[id]
+	if !ok {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	patch.Apply(p)
+	return p, nil
+}
+
 func (m *MockProductRepository) Delete(ctx context.Context, id string) error {
 	delete(m.products, id)
 	return nil
 }
 
-func (m *MockProductRepository) Count(ctx context.Context) (int64, error) {
-	return int64(len(m.products)), nil
+func (m *MockProductRepository) Archive(ctx context.Context, id string) error {
+	p, ok := m.products[id]
+	if !ok {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	if p.DeletedAt == nil {
+		now := time.Now()
+		p.DeletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockProductRepository) Restore(ctx context.Context, id string) error {
+	p, ok := m.products[id]
+	if !ok {
+		return fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+	p.DeletedAt = nil
+	return nil
+}
+
+func (m *MockProductRepository) Count(ctx context.Context, includeArchived bool, filter *domain.Filter) (int64, error) {
+	var count int64
+	for _, p := range m.products {
+		if (includeArchived || !p.Archived()) && filter.Matches(p.FilterValue) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockProductRepository) SetReleaseAt(ctx context.Context, id string, releaseAt *time.Time) error {
+	p, ok := m.products[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	p.ReleaseAt = releaseAt
+	return nil
+}
+
+func (m *MockProductRepository) ListScheduledReleases(ctx context.Context) ([]*domain.Product, error) {
+	var products []*domain.Product
+	for _, p := range m.products {
+		if p.ReleaseAt != nil {
+			products = append(products, p)
+		}
+	}
+	sort.Slice(products, func(i, j int) bool { return products[i].ReleaseAt.Before(*products[j].ReleaseAt) })
+	return products, nil
+}
+
+func (m *MockProductRepository) ReleaseDue(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	for id, p := range m.products {
+		if p.ReleaseAt != nil && !now.Before(*p.ReleaseAt) {
+			p.ReleaseAt = nil
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
 }
 
 // MockInventoryRepository implements InventoryRepository interface for testing
 type MockInventoryRepository struct {
-	items map[string]*domain.InventoryItem
+	items     map[string]*domain.InventoryItem
+	transfers []*domain.Transaction
+	batched   []*domain.Transaction
 }
 
 func NewMockInventoryRepository() *MockInventoryRepository {
@@ -77,7 +154,13 @@ func NewMockInventoryRepository() *MockInventoryRepository {
 
 func (m *MockInventoryRepository) Create(ctx context.Context, item *domain.InventoryItem) error {
 	if item.ID == "" {
-		item.ID = "test-inv-1"
+		item.ID = fmt.Sprintf("test-inv-%d", len(m.items)+1)
+	}
+	if item.Version == 0 {
+		item.Version = 1
+	}
+	if item.Condition == "" {
+		item.Condition = domain.ConditionNew
 	}
 	m.items[item.ID] = item
 	return nil
@@ -99,16 +182,72 @@ func (m *MockInventoryRepository) GetByProductID(ctx context.Context, productID
 	return nil, nil
 }
 
-func (m *MockInventoryRepository) List(ctx context.Context, limit, offset int) ([]*domain.InventoryItem, error) {
+func (m *MockInventoryRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	for _, i := range m.items {
+		if i.ProductID == productID && i.Location == location && i.Condition == condition {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("inventory item %w", domain.ErrNotFound)
+}
+
+func (m *MockInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
 	var items []*domain.InventoryItem
 	for _, i := range m.items {
-		items = append(items, i)
+		if i.ProductID == productID {
+			items = append(items, i)
+		}
 	}
 	return items, nil
 }
 
+func (m *MockInventoryRepository) GetByProductIDs(ctx context.Context, productIDs []string) (map[string][]*domain.InventoryItem, error) {
+	items := make(map[string][]*domain.InventoryItem, len(productIDs))
+	for _, productID := range productIDs {
+		if list, _ := m.ListByProductID(ctx, productID); len(list) > 0 {
+			items[productID] = list
+		}
+	}
+	return items, nil
+}
+
+func (m *MockInventoryRepository) List(ctx context.Context, limit, offset int, filter *domain.Filter) ([]*domain.InventoryItem, error) {
+	var items []*domain.InventoryItem
+	for _, i := range m.items {
+		if filter.Matches(i.FilterValue) {
+			items = append(items, i)
+		}
+	}
+	return items, nil
+}
+
+func (m *MockInventoryRepository) Count(ctx context.Context, filter *domain.Filter) (int64, error) {
+	items, err := m.List(ctx, -1, 0, filter)
+	return int64(len(items)), err
+}
+
 func (m *MockInventoryRepository) Update(ctx context.Context, item *domain.InventoryItem) error {
-	m.items[item.ID] = item
+	if stored, ok := m.items[item.ID]; ok && stored.Version != item.Version {
+		return domain.ErrConflict
+	}
+	updated := *item
+	updated.Version++
+	m.items[item.ID] = &updated
+	item.Version = updated.Version
+	return nil
+}
+
+func (m *MockInventoryRepository) UpdateSettings(ctx context.Context, item *domain.InventoryItem) error {
+	stored, ok := m.items[item.ID]
+	if !ok {
+		return fmt.Errorf("inventory item %w", domain.ErrNotFound)
+	}
+	if stored.Version != item.Version {
+		return domain.ErrConflict
+	}
+	stored.ReorderLevel, stored.SafetyStock, stored.BinLocation = item.ReorderLevel, item.SafetyStock, item.BinLocation
+	stored.Version++
+	item.Version = stored.Version
 	return nil
 }
 
@@ -121,26 +260,81 @@ func (m *MockInventoryRepository) UpdateQuantity(ctx context.Context, inventoryI
 	if i, ok := m.items[inventoryID]; ok {
 		i.Quantity += quantityDelta
 		i.Reserved += reservedDelta
+		i.Version++
 		return nil
 	}
 	return nil
 }
 
+func (m *MockInventoryRepository) Transfer(ctx context.Context, fromID, toID string, quantity int64, out, in *domain.Transaction) error {
+	from, ok := m.items[fromID]
+	if !ok || from.AvailableQuantity() < quantity {
+		return fmt.Errorf("transfer failed: %w available or item not found", domain.ErrInsufficientStock)
+	}
+	to, ok := m.items[toID]
+	if !ok {
+		return fmt.Errorf("transfer failed: destination item %w", domain.ErrNotFound)
+	}
+	from.Quantity -= quantity
+	to.Quantity += quantity
+	m.transfers = append(m.transfers, out, in)
+	return nil
+}
+
+func (m *MockInventoryRepository) ApplyBatch(ctx context.Context, changes []*domain.StockBatchChange) error {
+	staged := make(map[string]domain.InventoryItem)
+	for i, change := range changes {
+		item, ok := staged[change.InventoryID]
+		if !ok {
+			current, found := m.items[change.InventoryID]
+			if !found {
+				return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+			}
+			item = *current
+		}
+		item.Quantity += change.QuantityDelta
+		item.Reserved += change.ReservedDelta
+		if item.Quantity < item.Reserved || item.Reserved < 0 {
+			return fmt.Errorf("item %d: %w or item not found", i+1, domain.ErrInsufficientStock)
+		}
+		staged[change.InventoryID] = item
+	}
+	for id, item := range staged {
+		m.items[id].Quantity = item.Quantity
+		m.items[id].Reserved = item.Reserved
+	}
+	for _, change := range changes {
+		m.batched = append(m.batched, change.Transaction)
+	}
+	return nil
+}
+
 // MockTransactionRepository implements TransactionRepository interface for testing
 type MockTransactionRepository struct {
 	transactions map[string]*domain.Transaction
+	sequences    map[string]int64
+	failCreate   error
 }
 
 func NewMockTransactionRepository() *MockTransactionRepository {
 	return &MockTransactionRepository{
 		transactions: make(map[string]*domain.Transaction),
+		sequences:    make(map[string]int64),
 	}
 }
 
 func (m *MockTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
+	if m.failCreate != nil {
+		return m.failCreate
+	}
 	if transaction.ID == "" {
-		transaction.ID = "test-tx-1"
+		transaction.ID = fmt.Sprintf("test-tx-%d", len(m.transactions)+1)
 	}
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
+	}
+	m.sequences[transaction.ProductID]++
+	transaction.Sequence = m.sequences[transaction.ProductID]
 	m.transactions[transaction.ID] = transaction
 	return nil
 }
@@ -163,15 +357,62 @@ func (m *MockTransactionRepository) GetByInventoryID(ctx context.Context, invent
 }
 
 func (m *MockTransactionRepository) GetByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Transaction, error) {
+	return m.Search(ctx, repository.TransactionFilter{ProductID: productID}, nil, limit, offset)
+}
+
+func (m *MockTransactionRepository) Search(ctx context.Context, filter repository.TransactionFilter, after *domain.TransactionCursor, limit, offset int) ([]*domain.Transaction, error) {
 	var txs []*domain.Transaction
-	for _, t := range m.transactions {
-		if t.ProductID == productID {
+	for _, t := range m.matchingNewestFirst(filter) {
+		if after == nil || t.CreatedAt.Before(after.CreatedAt) || (t.CreatedAt.Equal(after.CreatedAt) && t.ID < after.ID) {
 			txs = append(txs, t)
 		}
 	}
+	if after != nil {
+		offset = 0
+	}
+	if offset >= len(txs) {
+		return nil, nil
+	}
+	txs = txs[offset:]
+	if limit > 0 && limit < len(txs) {
+		txs = txs[:limit]
+	}
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) RecentByProductIDs(ctx context.Context, productIDs []string, perProduct int) (map[string][]*domain.Transaction, error) {
+	transactions := make(map[string][]*domain.Transaction, len(productIDs))
+	for _, productID := range productIDs {
+		if list, _ := m.GetByProductID(ctx, productID, perProduct, 0); len(list) > 0 {
+			transactions[productID] = list
+		}
+	}
+	return transactions, nil
+}
+
+func (m *MockTransactionRepository) CountMatching(ctx context.Context, filter repository.TransactionFilter) (int64, error) {
+	return int64(len(m.matchingNewestFirst(filter))), nil
+}
+
+// matchingNewestFirst returns the transactions matching the filter ordered
+// like the repository: by creation time, then ID, newest first
+func (m *MockTransactionRepository) matchingNewestFirst(filter repository.TransactionFilter) []*domain.Transaction {
+	var txs []*domain.Transaction
+	for _, t := range m.transactions {
+		if (filter.ProductID == "" || t.ProductID == filter.ProductID) &&
+			filter.Expression.Matches(t.FilterValue) {
+			txs = append(txs, t)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if !txs[i].CreatedAt.Equal(txs[j].CreatedAt) {
+			return txs[i].CreatedAt.After(txs[j].CreatedAt)
+		}
+		return txs[i].ID > txs[j].ID
+	})
+	return txs
+}
+
 func (m *MockTransactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Transaction, error) {
 	var txs []*domain.Transaction
 	for _, t := range m.transactions {
@@ -180,6 +421,52 @@ func (m *MockTransactionRepository) List(ctx context.Context, limit, offset int)
 	return txs, nil
 }
 
+func (m *MockTransactionRepository) GetByDateRange(ctx context.Context, from, to time.Time, after *domain.TransactionCursor, limit int) ([]*domain.Transaction, error) {
+	var txs []*domain.Transaction
+	for _, t := range m.transactions {
+		if !t.CreatedAt.Before(from) && t.CreatedAt.Before(to) &&
+			(after == nil || t.CreatedAt.After(after.CreatedAt) || (t.CreatedAt.Equal(after.CreatedAt) && t.ID > after.ID)) {
+			txs = append(txs, t)
+		}
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if !txs[i].CreatedAt.Equal(txs[j].CreatedAt) {
+			return txs[i].CreatedAt.Before(txs[j].CreatedAt)
+		}
+		return txs[i].ID < txs[j].ID
+	})
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs, nil
+}
+
+func (m *MockTransactionRepository) ReservedByReference(ctx context.Context, productID, reference string) (int64, error) {
+	var reserved int64
+	for _, t := range m.transactions {
+		if t.ProductID != productID || t.Reference != reference {
+			continue
+		}
+		switch t.Type {
+		case "RESERVE":
+			reserved += t.Quantity
+		case "UNRESERVE":
+			reserved -= t.Quantity
+		}
+	}
+	return reserved, nil
+}
+
+func (m *MockTransactionRepository) OutboundByInventory(ctx context.Context, productID string, since time.Time) (map[string]int64, error) {
+	outbound := make(map[string]int64)
+	for _, t := range m.transactions {
+		if t.ProductID == productID && t.Type == "OUT" && !t.CreatedAt.Before(since) {
+			outbound[t.InventoryID] += t.Quantity
+		}
+	}
+	return outbound, nil
+}
+
 func (m *MockTransactionRepository) Count(ctx context.Context) (int64, error) {
 	return int64(len(m.transactions)), nil
 }
@@ -211,6 +498,26 @@ func TestCreateProduct(t *testing.T) {
 	}
 }
 
+func TestCreateProductDuplicateSKU(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	service := NewInventoryService(productRepo, NewMockInventoryRepository(), NewMockTransactionRepository())
+	ctx := context.Background()
+
+	if err := service.CreateProduct(ctx, &domain.Product{Name: "Laptop", SKU: "LAP001", Price: 1500}, "Warehouse A", 5); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	duplicate := &domain.Product{ID: "prod-2", Name: "Other Laptop", SKU: "LAP001", Price: 900}
+	err := service.CreateProduct(ctx, duplicate, "Warehouse A", 5)
+	var skuErr *domain.DuplicateSKUError
+	if !errors.Is(err, domain.ErrDuplicateSKU) || !errors.As(err, &skuErr) || skuErr.SKU != "LAP001" {
+		t.Fatalf("Expected a duplicate SKU error naming LAP001, got %v", err)
+	}
+	if _, ok := productRepo.products["prod-2"]; ok {
+		t.Error("Expected the duplicate product not to be inserted")
+	}
+}
+
 func TestAddStock(t *testing.T) {
 	productRepo := NewMockProductRepository()
 	inventoryRepo := NewMockInventoryRepository()
@@ -357,6 +664,62 @@ func TestInsufficientStockRemoval(t *testing.T) {
 		t.Fatal("Expected error for insufficient stock")
 	}
 }
+
+// racingInventoryRepository removes stock behind the caller's back right
+// before each of its quantity updates, as a concurrent writer would
+type racingInventoryRepository struct {
+	*MockInventoryRepository
+	concurrentRemoval int64
+}
+
+func (m *racingInventoryRepository) UpdateQuantity(ctx context.Context, inventoryID string, quantityDelta, reservedDelta int64) error {
+	m.MockInventoryRepository.UpdateQuantity(ctx, inventoryID, -m.concurrentRemoval, 0)
+	return m.MockInventoryRepository.UpdateQuantity(ctx, inventoryID, quantityDelta, reservedDelta)
+}
+
+func TestConditionalStockOperations(t *testing.T) {
+	ctx := context.Background()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo)
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 30, Location: "Warehouse A"})
+
+	// 25 would remain available
+	if err := service.RemoveStockIfAvailable(ctx, "prod-1", "", "", 5, 20, "ORDER-001"); err != nil {
+		t.Fatalf("Expected the removal to be applied, got %v", err)
+	}
+	if err := service.ReserveStockIfAvailable(ctx, "prod-1", "", "", 5, 20, "ORDER-002"); err != nil {
+		t.Fatalf("Expected the reservation to be applied, got %v", err)
+	}
+
+	// 15 would remain available
+	if err := service.RemoveStockIfAvailable(ctx, "prod-1", "", "", 5, 20, "ORDER-003"); !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Errorf("Expected the removal to fail its precondition, got %v", err)
+	}
+	if err := service.ReserveStockIfAvailable(ctx, "prod-1", "", "", 5, 20, "ORDER-004"); !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Errorf("Expected the reservation to fail its precondition, got %v", err)
+	}
+	if item := inventoryRepo.items["inv-1"]; item.Quantity != 25 || item.Reserved != 5 || len(transactionRepo.transactions) != 2 {
+		t.Errorf("Expected only the first two operations to be applied, got %+v with %d transactions", item, len(transactionRepo.transactions))
+	}
+
+	if err := service.RemoveStockIfAvailable(ctx, "prod-1", "", "", 5, -1, "ORDER-005"); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a negative expectation to be rejected, got %v", err)
+	}
+
+	// A concurrent removal between the check and the update rolls the
+	// conditional removal back
+	racing := &racingInventoryRepository{MockInventoryRepository: inventoryRepo, concurrentRemoval: 10}
+	transactor := NewMockTransactor(inventoryRepo, transactionRepo)
+	service = NewInventoryService(NewMockProductRepository(), racing, transactionRepo, WithTransactor(transactor))
+	if err := service.RemoveStockIfAvailable(ctx, "prod-1", "", "", 5, 10, "ORDER-006"); !errors.Is(err, domain.ErrPreconditionFailed) {
+		t.Errorf("Expected the removal to fail its precondition, got %v", err)
+	}
+	if transactor.rollbacks != 1 || len(transactionRepo.transactions) != 2 {
+		t.Errorf("Expected the removal to be rolled back, got %d rollbacks and %d transactions", transactor.rollbacks, len(transactionRepo.transactions))
+	}
+}
+
 func TestReleaseReservedStock(t *testing.T) {
 	productRepo := NewMockProductRepository()
 	inventoryRepo := NewMockInventoryRepository()
@@ -577,6 +940,7 @@ func TestListProducts(t *testing.T) {
 	for i := 1; i <= 3; i++ {
 		num := string(rune('0' + i))
 		product := &domain.Product{
+			ID:          "prod-" + num,
 			Name:        "Product " + num,
 			SKU:         "SKU00" + num,
 			Description: "Test Product",
@@ -585,13 +949,63 @@ func TestListProducts(t *testing.T) {
 		productRepo.Create(ctx, product)
 	}
 
-	products, err := service.ListProducts(ctx, 10, 0)
+	page, err := service.ListProducts(ctx, 10, 0, false, nil)
 	if err != nil {
 		t.Fatalf("Failed to list products: %v", err)
 	}
 
-	if len(products) < 3 {
-		t.Logf("Expected at least 3 products, got %d", len(products))
+	if len(page.Items) != 3 || page.Total != 3 || page.NextOffset != nil {
+		t.Errorf("Expected 3 products on the only page, got %d of %d (next %v)", len(page.Items), page.Total, page.NextOffset)
+	}
+}
+
+func TestDeleteProductArchives(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	for _, id := range []string{"prod-1", "prod-2"} {
+		productRepo.Create(ctx, &domain.Product{ID: id, Name: "Product", SKU: "SKU-" + id, Price: 10})
+	}
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 5, Location: "WH-A"})
+	transactionRepo.Create(ctx, &domain.Transaction{ProductID: "prod-1", InventoryID: "inv-1", Type: "IN", Quantity: 5})
+
+	if err := service.DeleteProduct(ctx, "prod-1"); err != nil {
+		t.Fatalf("DeleteProduct() error = %v", err)
+	}
+
+	page, _ := service.ListProducts(ctx, 10, 0, false, nil)
+	if page.Total != 1 || page.Items[0].ID != "prod-2" {
+		t.Errorf("Expected only prod-2 to be listed, got %d products", page.Total)
+	}
+	page, _ = service.ListProducts(ctx, 10, 0, true, nil)
+	if page.Total != 2 {
+		t.Errorf("Expected archived products to be listed on request, got %d products", page.Total)
+	}
+
+	// The archived product keeps its stock and history
+	product, inventory, err := service.GetProduct(ctx, "prod-1")
+	if err != nil || !product.Archived() || inventory.Quantity != 5 {
+		t.Fatalf("Expected the archived product with its stock, got %+v, %+v, %v", product, inventory, err)
+	}
+	history, err := service.ListTransactions(ctx, repository.TransactionFilter{ProductID: "prod-1"}, domain.PageRequest{Limit: 10})
+	if err != nil || history.Total != 1 {
+		t.Errorf("Expected the transaction history to be kept, got %+v, %v", history, err)
+	}
+
+	restored, err := service.RestoreProduct(ctx, "prod-1")
+	if err != nil || restored.Archived() {
+		t.Fatalf("Expected the product to be restored, got %+v, %v", restored, err)
+	}
+	if page, _ := service.ListProducts(ctx, 10, 0, false, nil); page.Total != 2 {
+		t.Errorf("Expected the restored product to be listed again, got %d products", page.Total)
+	}
+
+	if _, err := service.RestoreProduct(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown product, got %v", err)
 	}
 }
 
@@ -624,12 +1038,455 @@ func TestListTransactions(t *testing.T) {
 	// Add stock which creates a transaction
 	_ = service.AddStock(ctx, product.ID, 10, "PO-001")
 
-	transactions, err := service.ListTransactions(ctx, product.ID, 10, 0)
+	page, err := service.ListTransactions(ctx, repository.TransactionFilter{ProductID: product.ID}, domain.PageRequest{Limit: 10})
 	if err != nil {
 		t.Fatalf("Failed to list transactions: %v", err)
 	}
 
-	if len(transactions) == 0 {
+	if len(page.Items) == 0 {
 		t.Fatal("Expected at least one transaction")
 	}
-}
\ No newline at end of file
+}
+
+func TestListTransactionsCursor(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	service := NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), transactionRepo)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		transactionRepo.Create(ctx, &domain.Transaction{
+			ID:        fmt.Sprintf("tx-%d", i),
+			ProductID: "prod-1",
+			Type:      "IN",
+			Quantity:  1,
+			CreatedAt: start.Add(time.Duration(i%3) * time.Hour),
+		})
+	}
+
+	var seen []string
+	req := domain.PageRequest{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Cursor pagination did not terminate")
+		}
+		page, err := service.ListTransactions(ctx, repository.TransactionFilter{ProductID: "prod-1"}, req)
+		if err != nil {
+			t.Fatalf("Failed to list transactions: %v", err)
+		}
+		if page.Total != 5 {
+			t.Errorf("Expected total 5, got %d", page.Total)
+		}
+		for _, transaction := range page.Items {
+			seen = append(seen, transaction.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+
+	want := []string{"tx-2", "tx-4", "tx-1", "tx-3", "tx-0"}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, seen)
+	}
+
+	if _, err := service.ListTransactions(ctx, repository.TransactionFilter{ProductID: "prod-1"}, domain.PageRequest{Limit: 2, Cursor: "not-a-cursor"}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a malformed cursor, got %v", err)
+	}
+}
+
+func TestListTransactionsFilter(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	service := NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), transactionRepo)
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, tx := range []domain.Transaction{
+		{ProductID: "prod-1", Type: "RESERVE", Reference: "ORDER-1"},
+		{ProductID: "prod-2", Type: "RESERVE", Reference: "ORDER-1"},
+		{ProductID: "prod-1", Type: "OUT", Reference: "ORDER-1"},
+		{ProductID: "prod-1", Type: "IN", Reference: "PO-1"},
+	} {
+		tx.ID = fmt.Sprintf("tx-%d", i)
+		tx.Quantity = 1
+		tx.CreatedAt = start.Add(time.Duration(i) * 24 * time.Hour)
+		transactionRepo.Create(ctx, &tx)
+	}
+
+	equals := func(field, value string) *domain.Filter {
+		return &domain.Filter{Field: field, Operator: domain.FilterEqual, Value: value}
+	}
+
+	tests := []struct {
+		name   string
+		filter repository.TransactionFilter
+		want   []string
+	}{
+		{"whole ledger", repository.TransactionFilter{}, []string{"tx-3", "tx-2", "tx-1", "tx-0"}},
+		{"reference across products", repository.TransactionFilter{Expression: equals("reference", "ORDER-1")}, []string{"tx-2", "tx-1", "tx-0"}},
+		{"product and type", repository.TransactionFilter{ProductID: "prod-1", Expression: equals("type", "RESERVE")}, []string{"tx-0"}},
+		{"period", repository.TransactionFilter{Expression: domain.FilterPeriod("created_at", start.AddDate(0, 0, 1), start.AddDate(0, 0, 3))}, []string{"tx-2", "tx-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.ListTransactions(ctx, tt.filter, domain.PageRequest{Limit: 10})
+			if err != nil {
+				t.Fatalf("Failed to list transactions: %v", err)
+			}
+			var got []string
+			for _, transaction := range page.Items {
+				got = append(got, transaction.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) || page.Total != int64(len(tt.want)) {
+				t.Errorf("Expected %v, got %v (total %d)", tt.want, got, page.Total)
+			}
+		})
+	}
+}
+
+func TestMultiLocationStock(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	product := &domain.Product{
+		ID:          "prod-1",
+		Name:        "Laptop",
+		SKU:         "LAP001",
+		Description: "Gaming Laptop",
+		Price:       1500.00,
+	}
+	productRepo.Create(ctx, product)
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{
+		ID:        "inv-1",
+		ProductID: product.ID,
+		Quantity:  50,
+		Location:  "WH-A",
+	})
+
+	if _, err := service.CreateInventoryAt(ctx, product.ID, "WH-B", "", 30); err != nil {
+		t.Fatalf("Failed to create inventory at WH-B: %v", err)
+	}
+	if _, err := service.CreateInventoryAt(ctx, product.ID, "WH-B", "", 10); err == nil {
+		t.Error("Expected error when stocking the same location twice")
+	}
+
+	if err := service.RemoveStockAt(ctx, product.ID, "WH-B", "", 40, "SO-001"); err == nil {
+		t.Error("Expected error when removing more than the location holds")
+	}
+	if err := service.ReserveStockAt(ctx, product.ID, "WH-B", "", 5, "SO-002"); err != nil {
+		t.Fatalf("Failed to reserve stock at WH-B: %v", err)
+	}
+
+	level, err := service.GetStockLevel(ctx, product.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to get stock level: %v", err)
+	}
+	if level.Quantity != 80 || level.Reserved != 5 || level.Available != 75 {
+		t.Errorf("Expected 80/5/75, got %d/%d/%d", level.Quantity, level.Reserved, level.Available)
+	}
+	if len(level.Locations) != 2 {
+		t.Errorf("Expected 2 locations, got %d", len(level.Locations))
+	}
+
+	warehouseA, err := service.GetInventoryAt(ctx, product.ID, "WH-A", "")
+	if err != nil {
+		t.Fatalf("Failed to get inventory at WH-A: %v", err)
+	}
+	if warehouseA.Reserved != 0 {
+		t.Errorf("Expected no reservation at WH-A, got %d", warehouseA.Reserved)
+	}
+}
+
+func TestStockConditions(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	service := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository())
+	ctx := context.Background()
+
+	product := &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500.00}
+	if err := service.CreateProduct(ctx, product, "WH-A", 10); err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if _, err := service.CreateInventoryAt(ctx, product.ID, "WH-A", "refurbished", 4); err != nil {
+		t.Fatalf("Failed to stock refurbished units at WH-A: %v", err)
+	}
+	if _, err := service.CreateInventoryAt(ctx, product.ID, "WH-A", "used", 1); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected an unknown condition to be rejected, got %v", err)
+	}
+
+	// Refurbished units are reserved apart from new ones, also at the default location
+	if err := service.ReserveStockAt(ctx, product.ID, "", domain.ConditionRefurbished, 5, "SO-001"); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected the 4 refurbished units to fall short, got %v", err)
+	}
+	if err := service.ReserveStockAt(ctx, product.ID, "", domain.ConditionRefurbished, 3, "SO-001"); err != nil {
+		t.Fatalf("Failed to reserve refurbished stock: %v", err)
+	}
+	if err := service.RemoveStockAt(ctx, product.ID, "WH-A", domain.ConditionGradeB, 1, "SO-002"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected no grade B stock, got %v", err)
+	}
+
+	newStock, _ := service.GetInventoryAt(ctx, product.ID, "WH-A", "")
+	if newStock.Reserved != 0 {
+		t.Errorf("Expected no new units reserved, got %d", newStock.Reserved)
+	}
+
+	level, err := service.GetStockLevel(ctx, product.ID, domain.ConditionRefurbished)
+	if err != nil {
+		t.Fatalf("Failed to get refurbished stock level: %v", err)
+	}
+	if level.Quantity != 4 || level.Available != 1 || len(level.Locations) != 1 {
+		t.Errorf("Expected 1 of 4 refurbished units available, got %+v", level)
+	}
+	if level, _ := service.GetStockLevel(ctx, product.ID, ""); level.Quantity != 14 || level.Available != 11 {
+		t.Errorf("Expected every condition counted, got %+v", level)
+	}
+
+	results, err := service.CheckAvailability(ctx, []AvailabilityCheck{
+		{ProductID: product.ID, Quantity: 2, Condition: domain.ConditionRefurbished},
+		{ProductID: product.ID, Quantity: 1, Condition: domain.ConditionGradeB},
+		{ProductID: product.ID, Quantity: 11},
+	})
+	if err != nil {
+		t.Fatalf("CheckAvailability() error = %v", err)
+	}
+	if results[0].Sufficient || results[0].Available != 1 {
+		t.Errorf("Expected 1 refurbished unit available, got %+v", results[0])
+	}
+	if results[1].Sufficient || results[1].Error != "" {
+		t.Errorf("Expected no grade B stock, got %+v", results[1])
+	}
+	if !results[2].Sufficient {
+		t.Errorf("Expected 11 units over all conditions, got %+v", results[2])
+	}
+}
+
+func TestProjectedStockout(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	product := &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500.00}
+	productRepo.Create(ctx, product)
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-a", ProductID: product.ID, Quantity: 50, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-b", ProductID: product.ID, Quantity: 20, Location: "WH-B"})
+
+	// 10 units sold in the last 30 days leaves 40 at WH-A for another 120 days
+	if err := service.RemoveStockAt(ctx, product.ID, "WH-A", "", 10, "SO-001"); err != nil {
+		t.Fatalf("Failed to remove stock: %v", err)
+	}
+
+	level, err := service.GetStockLevel(ctx, product.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to get stock level: %v", err)
+	}
+	if level.ProjectedStockoutAt == nil {
+		t.Fatal("Expected a projected stockout for the product")
+	}
+	if days := time.Until(*level.ProjectedStockoutAt).Hours() / 24; days < 179 || days > 180 {
+		t.Errorf("Expected the product to stock out in 180 days, got %.1f", days)
+	}
+
+	warehouseA, err := service.GetInventoryAt(ctx, product.ID, "WH-A", "")
+	if err != nil {
+		t.Fatalf("Failed to get inventory at WH-A: %v", err)
+	}
+	if warehouseA.ProjectedStockoutAt == nil {
+		t.Fatal("Expected a projected stockout at WH-A")
+	}
+	if days := time.Until(*warehouseA.ProjectedStockoutAt).Hours() / 24; days < 119 || days > 120 {
+		t.Errorf("Expected WH-A to stock out in 120 days, got %.1f", days)
+	}
+
+	warehouseB, err := service.GetInventoryAt(ctx, product.ID, "WH-B", "")
+	if err != nil {
+		t.Fatalf("Failed to get inventory at WH-B: %v", err)
+	}
+	if warehouseB.ProjectedStockoutAt != nil {
+		t.Errorf("Expected no projection without sales at WH-B, got %v", warehouseB.ProjectedStockoutAt)
+	}
+}
+
+func TestTransferStock(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	product := &domain.Product{
+		ID:    "prod-1",
+		Name:  "Laptop",
+		SKU:   "LAP001",
+		Price: 1500.00,
+	}
+	productRepo.Create(ctx, product)
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{
+		ID:        "inv-1",
+		ProductID: product.ID,
+		Quantity:  50,
+		Reserved:  10,
+		Location:  "WH-A",
+	})
+
+	if err := service.TransferStock(ctx, product.ID, "WH-A", "WH-B", 45, "TR-001"); err == nil {
+		t.Error("Expected error when transferring reserved stock")
+	}
+	if err := service.TransferStock(ctx, product.ID, "WH-A", "WH-A", 5, "TR-001"); err == nil {
+		t.Error("Expected error when transferring to the same location")
+	}
+
+	if err := service.TransferStock(ctx, product.ID, "WH-A", "WH-B", 15, "TR-002"); err != nil {
+		t.Fatalf("Failed to transfer stock: %v", err)
+	}
+
+	source, _ := inventoryRepo.GetByProductAndLocation(ctx, product.ID, "WH-A", domain.ConditionNew)
+	destination, err := inventoryRepo.GetByProductAndLocation(ctx, product.ID, "WH-B", domain.ConditionNew)
+	if err != nil {
+		t.Fatalf("Expected destination inventory to be created: %v", err)
+	}
+	if source.Quantity != 35 || destination.Quantity != 15 {
+		t.Errorf("Expected 35 and 15 after transfer, got %d and %d", source.Quantity, destination.Quantity)
+	}
+
+	if len(inventoryRepo.transfers) != 2 {
+		t.Fatalf("Expected 2 transfer transactions, got %d", len(inventoryRepo.transfers))
+	}
+	if inventoryRepo.transfers[0].Type != "TRANSFER_OUT" || inventoryRepo.transfers[0].InventoryID != source.ID {
+		t.Errorf("Unexpected outgoing transaction: %+v", inventoryRepo.transfers[0])
+	}
+	if inventoryRepo.transfers[1].Type != "TRANSFER_IN" || inventoryRepo.transfers[1].InventoryID != destination.ID {
+		t.Errorf("Unexpected incoming transaction: %+v", inventoryRepo.transfers[1])
+	}
+}
+
+// flakyLocationRepository fails reads of the inventory at one location, as
+// a database that dropped the connection would
+type flakyLocationRepository struct {
+	*MockInventoryRepository
+	location string
+}
+
+func (m *flakyLocationRepository) GetByProductAndLocation(ctx context.Context, productID, location string, condition domain.StockCondition) (*domain.InventoryItem, error) {
+	if location == m.location {
+		return nil, errors.New("connection reset by peer")
+	}
+	return m.MockInventoryRepository.GetByProductAndLocation(ctx, productID, location, condition)
+}
+
+func TestTransferStockDestinationReadFailure(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	service := NewInventoryService(NewMockProductRepository(), &flakyLocationRepository{MockInventoryRepository: inventoryRepo, location: "WH-B"},
+		NewMockTransactionRepository())
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-1", Quantity: 5, Location: "WH-B"})
+
+	if err := service.TransferStock(ctx, "prod-1", "WH-A", "WH-B", 10, "TR-001"); err == nil {
+		t.Fatal("Expected the transfer to fail when the destination cannot be read")
+	}
+	if len(inventoryRepo.items) != 2 {
+		t.Errorf("Expected no destination created, got %d inventory rows", len(inventoryRepo.items))
+	}
+}
+
+func TestSetStockCountVersionConflict(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{
+		ID:        "inv-1",
+		ProductID: "prod-1",
+		Quantity:  50,
+		Reserved:  10,
+		Location:  "WH-A",
+	})
+
+	item, err := service.SetStockCount(ctx, "prod-1", "WH-A", 40, 1, "COUNT-001")
+	if err != nil {
+		t.Fatalf("Failed to set stock count: %v", err)
+	}
+	if item.Quantity != 40 || item.Version != 2 {
+		t.Errorf("Expected quantity 40 at version 2, got %d at version %d", item.Quantity, item.Version)
+	}
+	if len(transactionRepo.transactions) != 1 || transactionRepo.transactions["test-tx-1"].Type != "OUT" {
+		t.Errorf("Expected one OUT adjustment transaction, got %+v", transactionRepo.transactions)
+	}
+
+	// A second writer still holding version 1 must not overwrite the count
+	if _, err := service.SetStockCount(ctx, "prod-1", "WH-A", 60, 1, "COUNT-002"); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected ErrConflict for stale version, got %v", err)
+	}
+	if _, err := service.SetStockCount(ctx, "prod-1", "WH-A", 5, 2, "COUNT-003"); err == nil {
+		t.Error("Expected error when counting below reserved stock")
+	}
+
+	current, _ := inventoryRepo.GetByProductAndLocation(ctx, "prod-1", "WH-A", domain.ConditionNew)
+	if current.Quantity != 40 {
+		t.Errorf("Expected quantity to remain 40, got %d", current.Quantity)
+	}
+}
+
+// recordingTransactionHandler collects the transactions it is notified of
+type recordingTransactionHandler struct {
+	types []string
+}
+
+func (h *recordingTransactionHandler) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	h.types = append(h.types, transaction.Type)
+}
+
+// panickingTransactionHandler fails on every notification
+type panickingTransactionHandler struct{}
+
+func (panickingTransactionHandler) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	panic("handler failure")
+}
+
+func TestTransactionHandlers(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	recorder := &recordingTransactionHandler{}
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactionHandlers(panickingTransactionHandler{}, recorder),
+	)
+	ctx := context.Background()
+
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Location: "WH-A"})
+
+	if err := service.AddStock(ctx, "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("Failed to add stock: %v", err)
+	}
+	if err := service.TransferStock(ctx, "prod-1", "WH-A", "WH-B", 10, "TR-1"); err != nil {
+		t.Fatalf("Failed to transfer stock: %v", err)
+	}
+
+	want := []string{"IN", "TRANSFER_OUT", "TRANSFER_IN"}
+	if len(recorder.types) != len(want) {
+		t.Fatalf("Expected notifications %v, got %v", want, recorder.types)
+	}
+	for i := range want {
+		if recorder.types[i] != want[i] {
+			t.Errorf("Expected notifications %v, got %v", want, recorder.types)
+			break
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// jobHeartbeatInterval is how often a running job writes its progress
+const jobHeartbeatInterval = 10 * time.Second
+
+// jobStaleAfter is how long a job may go without a heartbeat before it is
+// considered lost, e.g. because its process stopped
+const jobStaleAfter = 6 * jobHeartbeatInterval
+
+// ProgressFunc reports that done out of total units of work are complete.
+// A total of 0 means it is not known yet.
+type ProgressFunc func(done, total int64)
+
+// report calls the function unless it is nil, so operations that run both
+// synchronously and as jobs can report progress unconditionally
+func (f ProgressFunc) report(done, total int64) {
+	if f != nil {
+		f(done, total)
+	}
+}
+
+// JobFunc is the work of a job
+type JobFunc func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error)
+
+// JobService runs long-running operations in the background and tracks them
+// in the jobs table. Jobs run in the process that started them; a job whose
+// process stops is failed once it misses its heartbeats.
+type JobService struct {
+	repo       repository.JobRepository
+	heartbeat  time.Duration
+	staleAfter time.Duration
+	running    sync.WaitGroup
+}
+
+// NewJobService creates a new JobService
+func NewJobService(repo repository.JobRepository) *JobService {
+	return &JobService{
+		repo:       repo,
+		heartbeat:  jobHeartbeatInterval,
+		staleAfter: jobStaleAfter,
+	}
+}
+
+// Start records a pending job and runs it in the background. The job keeps
+// running after ctx is cancelled but inherits its values.
+func (s *JobService) Start(ctx context.Context, jobType domain.JobType, createdBy string, run JobFunc) (*domain.Job, error) {
+	job := &domain.Job{
+		Type:      jobType,
+		Status:    domain.JobPending,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.running.Add(1)
+	go s.run(context.WithoutCancel(ctx), *job, run)
+
+	return job, nil
+}
+
+// Get retrieves a job, failing it first if it stopped sending heartbeats
+func (s *JobService) Get(ctx context.Context, id string) (*domain.Job, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Finished() || time.Since(job.UpdatedAt) < s.staleAfter {
+		return job, nil
+	}
+
+	failed, err := s.repo.FailStale(ctx, id, time.Now().Add(-s.staleAfter), "job stopped reporting progress")
+	if err != nil {
+		return nil, err
+	}
+	if !failed {
+		return job, nil
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Result retrieves the document produced by a succeeded job
+func (s *JobService) Result(ctx context.Context, id string) (*domain.JobResult, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != domain.JobSucceeded {
+		return nil, fmt.Errorf("%w: job is %s", domain.ErrConflict, job.Status)
+	}
+	return s.repo.GetResult(ctx, id)
+}
+
+// Wait blocks until all jobs started by this service have finished
+func (s *JobService) Wait() {
+	s.running.Wait()
+}
+
+// run executes a job, writing its progress on every heartbeat and its
+// outcome when it returns
+func (s *JobService) run(ctx context.Context, job domain.Job, run JobFunc) {
+	defer s.running.Done()
+
+	var done, total atomic.Int64
+	snapshot := func() domain.JobProgress {
+		return domain.JobProgress{Done: done.Load(), Total: total.Load()}
+	}
+	progress := func(d, t int64) {
+		done.Store(d)
+		total.Store(t)
+	}
+
+	job.Status = domain.JobRunning
+	if err := s.repo.UpdateProgress(ctx, job.ID, job.Status, snapshot()); err != nil {
+		slog.ErrorContext(ctx, "failed to start job", "job_id", job.ID, "error", err)
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := s.repo.UpdateProgress(ctx, job.ID, domain.JobRunning, snapshot()); err != nil {
+					slog.WarnContext(ctx, "failed to record job progress", "job_id", job.ID, "error", err)
+				}
+			}
+		}
+	}()
+
+	result, err := executeJob(ctx, run, progress)
+	close(stop)
+	<-stopped
+
+	job.Progress = snapshot()
+	var data []byte
+	var contentType string
+	if err == nil && result != nil {
+		if result.Summary != nil {
+			job.Summary, err = json.Marshal(result.Summary)
+		}
+		data, contentType = result.Data, result.ContentType
+	}
+	if err != nil {
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+		data, contentType = nil, ""
+	} else {
+		job.Status = domain.JobSucceeded
+	}
+
+	if err := s.repo.Finish(ctx, &job, data, contentType); err != nil {
+		slog.ErrorContext(ctx, "failed to record job outcome", "job_id", job.ID, "status", job.Status, "error", err)
+	}
+}
+
+// executeJob runs a job function, turning a panic into an error
+func executeJob(ctx context.Context, run JobFunc, progress ProgressFunc) (result *domain.JobResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx, progress)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockJobRepository implements JobRepository interface for testing
+type MockJobRepository struct {
+	mu      sync.Mutex
+	jobs    map[string]*domain.Job
+	results map[string]*domain.JobResult
+}
+
+func NewMockJobRepository() *MockJobRepository {
+	return &MockJobRepository{
+		jobs:    make(map[string]*domain.Job),
+		results: make(map[string]*domain.JobResult),
+	}
+}
+
+func (m *MockJobRepository) Create(ctx context.Context, job *domain.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.ID = fmt.Sprintf("job-%d", len(m.jobs)+1)
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	copied := *job
+	m.jobs[job.ID] = &copied
+	return nil
+}
+
+func (m *MockJobRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %w", domain.ErrNotFound)
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (m *MockJobRepository) UpdateProgress(ctx context.Context, id string, status domain.JobStatus, progress domain.JobProgress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[id]; ok && !job.Status.Finished() {
+		job.Status, job.Progress, job.UpdatedAt = status, progress, time.Now()
+	}
+	return nil
+}
+
+func (m *MockJobRepository) Finish(ctx context.Context, job *domain.Job, result []byte, contentType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	job.UpdatedAt, job.FinishedAt, job.HasResult = now, &now, result != nil
+	copied := *job
+	m.jobs[job.ID] = &copied
+	if result != nil {
+		m.results[job.ID] = &domain.JobResult{ContentType: contentType, Data: result}
+	}
+	return nil
+}
+
+func (m *MockJobRepository) FailStale(ctx context.Context, id string, updatedBefore time.Time, message string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.Status.Finished() || !job.UpdatedAt.Before(updatedBefore) {
+		return false, nil
+	}
+	job.Status, job.Error = domain.JobFailed, message
+	return true, nil
+}
+
+func (m *MockJobRepository) GetResult(ctx context.Context, id string) (*domain.JobResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, ok := m.results[id]
+	if !ok {
+		return nil, fmt.Errorf("job result %w", domain.ErrNotFound)
+	}
+	return result, nil
+}
+
+func TestJobService(t *testing.T) {
+	repo := NewMockJobRepository()
+	jobs := NewJobService(repo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	release := make(chan struct{})
+	job, err := jobs.Start(ctx, domain.JobAuditExport, "admin", func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error) {
+		<-release
+		progress(3, 3)
+		return &domain.JobResult{Summary: map[string]int{"rows": 3}, ContentType: "text/csv", Data: []byte("a,b\n")}, nil
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// The job outlives the request that started it
+	cancel()
+	if _, err := jobs.Result(context.Background(), job.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("Expected a conflict for an unfinished job, got %v", err)
+	}
+	close(release)
+	jobs.Wait()
+
+	got, err := jobs.Get(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != domain.JobSucceeded || got.Progress.Done != 3 || got.CreatedBy != "admin" || string(got.Summary) != `{"rows":3}` {
+		t.Errorf("Unexpected job: %+v", got)
+	}
+	result, err := jobs.Result(context.Background(), job.ID)
+	if err != nil || result.ContentType != "text/csv" || string(result.Data) != "a,b\n" {
+		t.Errorf("Unexpected result %+v, error %v", result, err)
+	}
+
+	// Errors and panics fail the job
+	for _, run := range []JobFunc{
+		func(context.Context, ProgressFunc) (*domain.JobResult, error) { return nil, errors.New("boom") },
+		func(context.Context, ProgressFunc) (*domain.JobResult, error) { panic("boom") },
+	} {
+		job, _ := jobs.Start(context.Background(), domain.JobCatalogSync, "", run)
+		jobs.Wait()
+		got, _ := jobs.Get(context.Background(), job.ID)
+		if got.Status != domain.JobFailed || !strings.Contains(got.Error, "boom") || got.HasResult {
+			t.Errorf("Expected a failed job, got %+v", got)
+		}
+	}
+
+	// A job whose process stopped is failed once its heartbeats are missed
+	stale := &domain.Job{Type: domain.JobProductImport, Status: domain.JobRunning}
+	repo.Create(context.Background(), stale)
+	repo.jobs[stale.ID].UpdatedAt = time.Now().Add(-2 * jobStaleAfter)
+	got, _ = jobs.Get(context.Background(), stale.ID)
+	if got.Status != domain.JobFailed {
+		t.Errorf("Expected the stale job to fail, got %s", got.Status)
+	}
+}
+
+func TestImportJobReportsProgress(t *testing.T) {
+	inventory := NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	importService := NewProductImportService(inventory, &MockProductImportRepository{})
+	rows := []domain.ProductImportRow{
+		{Name: "Laptop", SKU: "LAP-1", Price: 10, Location: "WH-A", Quantity: 5},
+		{Name: "Mouse", SKU: "", Price: 5, Location: "WH-A"},
+	}
+
+	if _, err := importService.ImportJob(nil); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("Expected an empty import to be rejected up front, got %v", err)
+	}
+
+	run, err := importService.ImportJob(rows)
+	if err != nil {
+		t.Fatalf("ImportJob() error = %v", err)
+	}
+	var done, total int64
+	result, err := run(context.Background(), func(d, t int64) { done, total = d, t })
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if done != 2 || total != 2 {
+		t.Errorf("Expected progress 2/2, got %d/%d", done, total)
+	}
+	if summary := result.Summary.(map[string]int); summary["imported"] != 1 || summary["failed"] != 1 {
+		t.Errorf("Unexpected summary: %v", result.Summary)
+	}
+	if !strings.Contains(string(result.Data), `"results"`) {
+		t.Errorf("Expected the full report as result, got %s", result.Data)
+	}
+}
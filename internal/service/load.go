@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrOverloaded is returned when a write can neither run nor wait for a slot
+var ErrOverloaded = errors.New("too many concurrent writes, retry later")
+
+// Load statuses reported by LoadService
+const (
+	LoadStatusOK         = "ok"
+	LoadStatusBusy       = "busy"
+	LoadStatusOverloaded = "overloaded"
+)
+
+// WriteLimiter bounds the number of mutating requests processed at once.
+// Requests over the limit wait in a bounded queue for up to maxWait before
+// they are turned away, so short bursts are smoothed instead of rejected.
+type WriteLimiter struct {
+	slots    chan struct{}
+	maxQueue int
+	maxWait  time.Duration
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Int64
+}
+
+// NewWriteLimiter creates a WriteLimiter admitting maxConcurrent writes at a
+// time. A maxConcurrent of zero disables limiting; in-flight writes are still
+// counted.
+func NewWriteLimiter(maxConcurrent, maxQueue int, maxWait time.Duration) *WriteLimiter {
+	l := &WriteLimiter{}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+		l.maxQueue = maxQueue
+		l.maxWait = maxWait
+	}
+	return l
+}
+
+// Acquire admits a write, waiting in the queue if all slots are busy. The
+// returned function must be called when the write has finished.
+func (l *WriteLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l.slots == nil {
+		l.inFlight.Add(1)
+		return l.release, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return l.release, nil
+	default:
+	}
+
+	if l.queued.Add(1) > int64(l.maxQueue) {
+		l.queued.Add(-1)
+		l.rejected.Add(1)
+		return nil, ErrOverloaded
+	}
+	defer l.queued.Add(-1)
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return l.release, nil
+	case <-timer.C:
+		l.rejected.Add(1)
+		return nil, ErrOverloaded
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *WriteLimiter) release() {
+	l.inFlight.Add(-1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// RetryAfter is the wait suggested to clients that were turned away
+func (l *WriteLimiter) RetryAfter() time.Duration {
+	return l.maxWait
+}
+
+// SystemLoad is a snapshot of write pressure, published so that clients can
+// back off adaptively instead of retrying blindly
+type SystemLoad struct {
+	Status              string `json:"status"`
+	InFlightWrites      int64  `json:"in_flight_writes"`
+	MaxConcurrentWrites int    `json:"max_concurrent_writes"`
+	QueuedWrites        int64  `json:"queued_writes"`
+	MaxQueuedWrites     int    `json:"max_queued_writes"`
+	RejectedWrites      int64  `json:"rejected_writes"`
+	BatchQueueRows      int    `json:"batch_queue_rows"`
+	BatchQueueDeltas    int    `json:"batch_queue_deltas"`
+	RetryAfterMs        int64  `json:"retry_after_ms,omitempty"`
+}
+
+// LoadService reports the current write load of the server
+type LoadService struct {
+	limiter   *WriteLimiter
+	inventory *InventoryService
+}
+
+// NewLoadService creates a new LoadService
+func NewLoadService(limiter *WriteLimiter, inventory *InventoryService) *LoadService {
+	return &LoadService{
+		limiter:   limiter,
+		inventory: inventory,
+	}
+}
+
+// Load returns the current limiter and write queue state. The status is busy
+// while writes are queued and overloaded once the queue is full.
+func (s *LoadService) Load() *SystemLoad {
+	l := s.limiter
+	load := &SystemLoad{
+		Status:              LoadStatusOK,
+		InFlightWrites:      l.inFlight.Load(),
+		MaxConcurrentWrites: cap(l.slots),
+		QueuedWrites:        l.queued.Load(),
+		MaxQueuedWrites:     l.maxQueue,
+		RejectedWrites:      l.rejected.Load(),
+	}
+	if s.inventory.batcher != nil {
+		load.BatchQueueRows, load.BatchQueueDeltas = s.inventory.batcher.depth()
+	}
+
+	if l.slots != nil {
+		switch {
+		case load.QueuedWrites >= int64(l.maxQueue):
+			load.Status = LoadStatusOverloaded
+			load.RetryAfterMs = l.maxWait.Milliseconds()
+		case load.QueuedWrites > 0 || load.InFlightWrites >= int64(cap(l.slots)):
+			load.Status = LoadStatusBusy
+		}
+	}
+	return load
+}
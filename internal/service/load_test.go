@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteLimiter(t *testing.T) {
+	limiter := NewWriteLimiter(1, 1, time.Second)
+	load := NewLoadService(limiter, NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository()))
+	ctx := context.Background()
+
+	release, err := limiter.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Failed to acquire free slot: %v", err)
+	}
+	if got := load.Load(); got.Status != LoadStatusBusy || got.InFlightWrites != 1 {
+		t.Errorf("Expected busy with 1 write in flight, got %+v", got)
+	}
+
+	// The second write waits in the queue for the slot
+	queued := make(chan error, 1)
+	go func() {
+		release, err := limiter.Acquire(ctx)
+		if err == nil {
+			release()
+		}
+		queued <- err
+	}()
+	for deadline := time.Now().Add(time.Second); limiter.queued.Load() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the write to queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The queue is full, so a third write is turned away at once
+	if _, err := limiter.Acquire(ctx); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("Expected ErrOverloaded with a full queue, got %v", err)
+	}
+	if got := load.Load(); got.Status != LoadStatusOverloaded || got.RejectedWrites != 1 || got.RetryAfterMs != 1000 {
+		t.Errorf("Expected overloaded with 1 rejection, got %+v", got)
+	}
+
+	release()
+	if err := <-queued; err != nil {
+		t.Errorf("Expected queued write to be admitted, got %v", err)
+	}
+	if got := load.Load(); got.Status != LoadStatusOK || got.InFlightWrites != 0 || got.QueuedWrites != 0 {
+		t.Errorf("Expected idle limiter, got %+v", got)
+	}
+}
+
+func TestWriteLimiterQueueTimeout(t *testing.T) {
+	limiter := NewWriteLimiter(1, 10, 10*time.Millisecond)
+
+	release, _ := limiter.Acquire(context.Background())
+	defer release()
+
+	if _, err := limiter.Acquire(context.Background()); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("Expected ErrOverloaded after waiting, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ErrLockTimeout is returned when a stock operation gave up waiting for
+// another replica to finish with the same product
+var ErrLockTimeout = repository.ErrLockTimeout
+
+// WithProductLocks makes reservations and removals of a product run one at a
+// time across all replicas: each takes the product's lock before reading its
+// stock and holds it until its unit of work commits, so two replicas cannot
+// both pass the availability check for the same units. Requires a transactor
+// whose units of work are database transactions.
+func WithProductLocks(locker repository.ProductLocker) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.locker = locker
+	}
+}
+
+// withProductLock runs fn holding the lock of a product, in a unit of work
+// of its own or, nested in another unit of work, joining it. Without product
+// locks fn runs as is.
+func (s *InventoryService) withProductLock(ctx context.Context, productID, operation string, fn func(ctx context.Context) error) error {
+	if s.locker == nil {
+		return fn(ctx)
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		lockCtx, span := s.startSpan(ctx, "InventoryService.lockProduct",
+			attribute.String("inventory.product_id", productID), attribute.String("inventory.operation", operation))
+		start := time.Now()
+		err := s.locker.LockProduct(lockCtx, productID)
+		s.metrics.recordLockWait(ctx, operation, time.Since(start), err)
+		endSpan(span, err)
+		if err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+}
+
+// whenCommitted runs effect once the unit of work of ctx committed, or right
+// away outside of one. Effects of a unit of work that rolls back never run.
+func whenCommitted(ctx context.Context, effect func(ctx context.Context)) {
+	repository.AfterCommit(ctx, effect)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockProductLocker implements repository.ProductLocker, recording the
+// products it locked and failing with err when set
+type MockProductLocker struct {
+	transactor *MockTransactor
+	locked     []string
+	err        error
+}
+
+func (m *MockProductLocker) LockProduct(ctx context.Context, productID string) error {
+	if m.transactor.depth == 0 {
+		return errors.New("product lock outside of a unit of work")
+	}
+	if m.err != nil {
+		return m.err
+	}
+	m.locked = append(m.locked, productID)
+	return nil
+}
+
+// committedHandler records whether it was notified inside a unit of work
+type committedHandler struct {
+	transactor  *MockTransactor
+	notified    int
+	uncommitted int
+}
+
+func (h *committedHandler) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	h.notified++
+	if h.transactor.depth > 0 {
+		h.uncommitted++
+	}
+}
+
+func TestProductLocks(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	transactor := NewMockTransactor(inventoryRepo, transactionRepo)
+	locker := &MockProductLocker{transactor: transactor}
+	handler := &committedHandler{transactor: transactor}
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithTransactor(transactor),
+		WithProductLocks(locker), WithTransactionHandlers(handler), WithMeterProvider(provider))
+
+	// Reservations and removals lock the product; additions do not
+	if err := service.ReserveStock(ctx, "prod-1", 3, "ORDER-1"); err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	if err := service.RemoveStock(ctx, "prod-1", 2, "ORDER-2"); err != nil {
+		t.Fatalf("RemoveStock() error = %v", err)
+	}
+	if err := service.AddStock(ctx, "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	if len(locker.locked) != 2 || locker.locked[0] != "prod-1" {
+		t.Errorf("Expected prod-1 locked twice, got %v", locker.locked)
+	}
+	if handler.notified != 3 || handler.uncommitted != 0 {
+		t.Errorf("Expected 3 notifications after commit, got %d with %d before", handler.notified, handler.uncommitted)
+	}
+
+	// An operation that cannot get the lock changes nothing
+	locker.err = ErrLockTimeout
+	if err := service.RemoveStock(ctx, "prod-1", 1, "ORDER-3"); !errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("Expected ErrLockTimeout, got %v", err)
+	}
+	if item, _ := inventoryRepo.GetByID(ctx, "inv-1"); item.Quantity != 13 || item.Reserved != 3 {
+		t.Errorf("Expected 13 units with 3 reserved, got %d/%d", item.Quantity, item.Reserved)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+	waits := make(map[string]uint64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if data, ok := m.Data.(metricdata.Histogram[float64]); ok && m.Name == "inventory.product_lock.wait" {
+				for _, point := range data.DataPoints {
+					outcome, _ := point.Attributes.Value("outcome")
+					waits[outcome.AsString()] += point.Count
+				}
+			}
+		}
+	}
+	if waits["acquired"] != 2 || waits["timeout"] != 1 {
+		t.Errorf("Expected 2 acquired and 1 timed out lock waits, got %v", waits)
+	}
+}
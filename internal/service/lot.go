@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxLotExpiryWindowDays bounds how many days ahead expiring lots are listed
+const MaxLotExpiryWindowDays = 365
+
+// lotWriteOffBatch is the number of expired lots a write-off run reads at a
+// time
+const lotWriteOffBatch = 100
+
+// LotService receives stock into lots with an expiry date, lists the lots
+// nearing expiry and writes off the expired ones. Removals take units from
+// the lots through the InventoryService, which must track them with
+// WithLotRepository.
+type LotService struct {
+	inventory *InventoryService
+	lotRepo   repository.LotRepository
+}
+
+// NewLotService creates a new LotService
+func NewLotService(inventory *InventoryService, lotRepo repository.LotRepository) *LotService {
+	return &LotService{
+		inventory: inventory,
+		lotRepo:   lotRepo,
+	}
+}
+
+// Receive adds stock to the new stock of the product at the location in a
+// lot, stocking the location first if needed. A lot number the location
+// already has gets the units added. The IN transaction, naming the lot, and
+// the lot are committed together. Locations with an open stocktake are
+// rejected in every freeze mode, as a queued receipt would leave the lot
+// without its stock.
+func (s *LotService) Receive(ctx context.Context, req domain.LotRequest) (*domain.Lot, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	expiresOn, _ := req.Expiry()
+	if expiresOn.Before(usageDay(time.Now())) {
+		return nil, domain.NewValidationError("lot %s expired on %s", req.LotNumber, req.ExpiresOn)
+	}
+
+	product, err := s.inventory.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	var lot *domain.Lot
+	err = s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventory.rejectFrozen(ctx, req.Location); err != nil {
+			return err
+		}
+
+		item, err := s.inventory.inventoryRepo.GetByProductAndLocation(ctx, product.ID, req.Location, domain.ConditionNew)
+		if err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				return fmt.Errorf("failed to get inventory: %w", err)
+			}
+			item, err = s.inventory.CreateInventoryAt(ctx, product.ID, req.Location, domain.ConditionNew, 0)
+			if err != nil {
+				return fmt.Errorf("failed to create inventory: %w", err)
+			}
+		}
+
+		lot, err = s.lotRepo.Receive(ctx, &domain.Lot{
+			ID:          uuid.New().String(),
+			InventoryID: item.ID,
+			LotNumber:   req.LotNumber,
+			ExpiresOn:   expiresOn,
+			Quantity:    req.Quantity,
+			ReceivedAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		transaction := &domain.Transaction{
+			InventoryID: item.ID,
+			ProductID:   product.ID,
+			Type:        "IN",
+			Quantity:    req.Quantity,
+			Reference:   req.Reference,
+			Notes:       fmt.Sprintf("Receipt into lot %s, expiring %s", lot.LotNumber, req.ExpiresOn),
+			UnitCost:    req.UnitCost,
+		}
+		if err := s.inventory.applyMovement(ctx, product.ID, item.ID, req.Quantity, 0, transaction); err != nil {
+			return fmt.Errorf("failed to add stock: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "lot received", "lot_id", lot.ID, "lot_number", lot.LotNumber, "product_id", lot.ProductID,
+		"location", lot.Location, "quantity", req.Quantity, "expires_on", req.ExpiresOn)
+	return lot, nil
+}
+
+// ListLots lists the lots of a product matching a filter, first expiring
+// first
+func (s *LotService) ListLots(ctx context.Context, productID string, filter domain.LotFilter, limit, offset int) ([]*domain.Lot, error) {
+	if _, err := s.inventory.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	lots, err := s.lotRepo.ListByProduct(ctx, productID, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lots: %w", err)
+	}
+	if lots == nil {
+		lots = []*domain.Lot{}
+	}
+	return lots, nil
+}
+
+// Expiring lists the lots with units in stock that expire within the next
+// withinDays days (UTC), first expiring first, optionally only those at a
+// location. Expired lots not written off yet are listed first.
+func (s *LotService) Expiring(ctx context.Context, withinDays int, location string, limit, offset int) ([]*domain.Lot, error) {
+	if withinDays < 0 || withinDays > MaxLotExpiryWindowDays {
+		return nil, domain.NewValidationError("days must be between 0 and %d", MaxLotExpiryWindowDays)
+	}
+	before := usageDay(time.Now()).AddDate(0, 0, withinDays+1)
+	lots, err := s.lotRepo.ListExpiring(ctx, before, location, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring lots: %w", err)
+	}
+	if lots == nil {
+		lots = []*domain.Lot{}
+	}
+	return lots, nil
+}
+
+// WriteOffExpired writes off the units in stock of every lot that expired
+// before today (UTC) with an EXPIRED transaction per lot. Units of a lot
+// that are reserved, and lots at a location with an open stocktake, are
+// left for a later run.
+func (s *LotService) WriteOffExpired(ctx context.Context) ([]*domain.LotWriteOff, error) {
+	today := usageDay(time.Now())
+	writeOffs := []*domain.LotWriteOff{}
+	offset := 0
+	for {
+		lots, err := s.lotRepo.ListExpiring(ctx, today, "", lotWriteOffBatch, offset)
+		if err != nil {
+			return writeOffs, fmt.Errorf("failed to list expired lots: %w", err)
+		}
+		if len(lots) == 0 {
+			return writeOffs, nil
+		}
+
+		for _, lot := range lots {
+			writeOff, err := s.writeOff(ctx, lot)
+			if errors.Is(err, ErrLocationFrozen) {
+				offset++
+				continue
+			}
+			if err != nil {
+				return writeOffs, fmt.Errorf("failed to write off lot %s: %w", lot.ID, err)
+			}
+			if writeOff == nil || writeOff.Quantity < lot.Remaining {
+				// What is left of the lot is listed again
+				offset++
+			}
+			if writeOff != nil {
+				writeOffs = append(writeOffs, writeOff)
+			}
+		}
+	}
+}
+
+// writeOff writes off the available units of an expired lot, under the
+// product lock so that the units are not removed meanwhile
+func (s *LotService) writeOff(ctx context.Context, expired *domain.Lot) (*domain.LotWriteOff, error) {
+	var writeOff *domain.LotWriteOff
+	err := s.inventory.withProductLock(ctx, expired.ProductID, "write-off", func(ctx context.Context) error {
+		return s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.inventory.rejectFrozen(ctx, expired.Location); err != nil {
+				return err
+			}
+
+			lots, err := s.lotRepo.LockOpen(ctx, expired.InventoryID)
+			if err != nil {
+				return err
+			}
+			var lot *domain.Lot
+			for _, open := range lots {
+				if open.ID == expired.ID {
+					lot = open
+				}
+			}
+			if lot == nil {
+				return nil
+			}
+			item, err := s.inventory.inventoryRepo.GetByID(ctx, expired.InventoryID)
+			if err != nil {
+				return fmt.Errorf("failed to get inventory: %w", err)
+			}
+			quantity := min(lot.Remaining, item.AvailableQuantity())
+			if quantity <= 0 {
+				return nil
+			}
+
+			if err := s.lotRepo.Consume(ctx, lot.ID, quantity); err != nil {
+				return err
+			}
+			transaction := &domain.Transaction{
+				InventoryID: item.ID,
+				ProductID:   lot.ProductID,
+				Type:        "EXPIRED",
+				Quantity:    quantity,
+				Reference:   lot.ID,
+				Notes:       fmt.Sprintf("Write-off of lot %s, expired %s", lot.LotNumber, lot.ExpiresOn.Format(time.DateOnly)),
+			}
+			if err := s.inventory.applyMovement(ctx, lot.ProductID, item.ID, -quantity, 0, transaction); err != nil {
+				return fmt.Errorf("failed to write off stock: %w", err)
+			}
+
+			writeOff = &domain.LotWriteOff{
+				LotID:     lot.ID,
+				LotNumber: lot.LotNumber,
+				ProductID: lot.ProductID,
+				Location:  lot.Location,
+				ExpiresOn: lot.ExpiresOn,
+				Quantity:  quantity,
+			}
+			return nil
+		})
+	})
+	if err != nil || writeOff == nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "expired lot written off", "lot_id", writeOff.LotID, "lot_number", writeOff.LotNumber,
+		"product_id", writeOff.ProductID, "location", writeOff.Location, "quantity", writeOff.Quantity)
+	return writeOff, nil
+}
+
+// Run writes off the expired lots every interval until ctx is cancelled
+func (s *LotService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if _, err := s.WriteOffExpired(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to write off expired lots", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockLotRepository implements LotRepository for testing
+type MockLotRepository struct {
+	inventory *MockInventoryRepository
+	lots      []*domain.Lot
+}
+
+func (m *MockLotRepository) Receive(ctx context.Context, lot *domain.Lot) (*domain.Lot, error) {
+	for _, stored := range m.lots {
+		if stored.InventoryID == lot.InventoryID && stored.LotNumber == lot.LotNumber {
+			if !stored.ExpiresOn.Equal(lot.ExpiresOn) {
+				return nil, domain.NewValidationError("lot %s expires on another day", lot.LotNumber)
+			}
+			stored.Quantity += lot.Quantity
+			stored.Remaining += lot.Quantity
+			copied := *stored
+			return &copied, nil
+		}
+	}
+	item, err := m.inventory.GetByID(ctx, lot.InventoryID)
+	if err != nil {
+		return nil, err
+	}
+	stored := *lot
+	stored.ProductID, stored.Location, stored.Remaining = item.ProductID, item.Location, lot.Quantity
+	m.lots = append(m.lots, &stored)
+	copied := stored
+	return &copied, nil
+}
+
+func (m *MockLotRepository) GetByID(ctx context.Context, id string) (*domain.Lot, error) {
+	for _, lot := range m.lots {
+		if lot.ID == id {
+			copied := *lot
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *MockLotRepository) ListByProduct(ctx context.Context, productID string, filter domain.LotFilter, limit, offset int) ([]*domain.Lot, error) {
+	return m.matching(func(lot *domain.Lot) bool {
+		return lot.ProductID == productID && (!filter.Open || lot.Remaining > 0)
+	}), nil
+}
+
+func (m *MockLotRepository) LockOpen(ctx context.Context, inventoryID string) ([]*domain.Lot, error) {
+	return m.matching(func(lot *domain.Lot) bool { return lot.InventoryID == inventoryID && lot.Remaining > 0 }), nil
+}
+
+func (m *MockLotRepository) Consume(ctx context.Context, id string, quantity int64) error {
+	for _, lot := range m.lots {
+		if lot.ID == id && lot.Remaining >= quantity {
+			lot.Remaining -= quantity
+			return nil
+		}
+	}
+	return domain.ErrConflict
+}
+
+func (m *MockLotRepository) ListExpiring(ctx context.Context, before time.Time, location string, limit, offset int) ([]*domain.Lot, error) {
+	lots := m.matching(func(lot *domain.Lot) bool { return lot.Remaining > 0 && lot.ExpiresOn.Before(before) })
+	if offset >= len(lots) {
+		return nil, nil
+	}
+	return lots[offset:], nil
+}
+
+func (m *MockLotRepository) matching(match func(*domain.Lot) bool) []*domain.Lot {
+	var lots []*domain.Lot
+	for _, lot := range m.lots {
+		if match(lot) {
+			copied := *lot
+			lots = append(lots, &copied)
+		}
+	}
+	return lots
+}
+
+func TestLotExpiry(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	lotRepo := &MockLotRepository{inventory: inventoryRepo}
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Yoghurt", SKU: "YOG001", Price: 2})
+
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)), WithLotRepository(lotRepo))
+	lots := NewLotService(inventory, lotRepo)
+
+	today := usageDay(time.Now())
+	day := func(days int) string { return today.AddDate(0, 0, days).Format(time.DateOnly) }
+	receive := func(lotNumber, expiresOn string, quantity int64) (*domain.Lot, error) {
+		return lots.Receive(ctx, domain.LotRequest{ProductID: "prod-1", Location: "COLD-1", LotNumber: lotNumber, ExpiresOn: expiresOn, Quantity: quantity})
+	}
+
+	if _, err := receive("L0", day(-1), 5); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for an expired lot, got %v", err)
+	}
+	late, err := receive("L2", day(60), 4)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	soon, err := receive("L1", day(10), 3)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if again, err := receive("L1", day(10), 2); err != nil || again.ID != soon.ID || again.Remaining != 5 {
+		t.Fatalf("Expected the units added to the lot, got %+v, error %v", again, err)
+	}
+	if _, err := receive("L1", day(11), 2); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for another expiry of the lot, got %v", err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, late.InventoryID); quantity != 9 {
+		t.Errorf("Expected 9 units received, got %d", quantity)
+	}
+
+	// Removals take the lot expiring first
+	if err := inventory.RemoveStockAt(ctx, "prod-1", "COLD-1", "", 6, "order-1"); err != nil {
+		t.Fatalf("RemoveStockAt() error = %v", err)
+	}
+	if lot, _ := lotRepo.GetByID(ctx, soon.ID); lot.Remaining != 0 {
+		t.Errorf("Expected the lot expiring first emptied, got %d left", lot.Remaining)
+	}
+	if lot, _ := lotRepo.GetByID(ctx, late.ID); lot.Remaining != 3 {
+		t.Errorf("Expected 3 units left in the later lot, got %d", lot.Remaining)
+	}
+	var removal *domain.Transaction
+	for _, transaction := range transactionRepo.transactions {
+		if transaction.Type == "OUT" {
+			removal = transaction
+		}
+	}
+	if removal == nil || !strings.Contains(removal.Notes, "lots L1 (5), L2 (1)") {
+		t.Errorf("Expected the lots named in the removal, got %+v", removal)
+	}
+
+	if expiring, err := lots.Expiring(ctx, 30, "", 10, 0); err != nil || len(expiring) != 0 {
+		t.Errorf("Expected no open lot expiring within 30 days, got %+v, error %v", expiring, err)
+	}
+	if expiring, _ := lots.Expiring(ctx, 60, "", 10, 0); len(expiring) != 1 || expiring[0].ID != late.ID {
+		t.Errorf("Expected the later lot expiring within 60 days, got %+v", expiring)
+	}
+	if _, err := lots.Expiring(ctx, MaxLotExpiryWindowDays+1, "", 10, 0); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a window over the limit, got %v", err)
+	}
+
+	// Once the later lot expired its units cannot be removed, only written off
+	lotRepo.lots[0].ExpiresOn = today.AddDate(0, 0, -1)
+	if err := inventory.RemoveStockAt(ctx, "prod-1", "COLD-1", "", 1, "order-2"); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock for expired units, got %v", err)
+	}
+	writeOffs, err := lots.WriteOffExpired(ctx)
+	if err != nil {
+		t.Fatalf("WriteOffExpired() error = %v", err)
+	}
+	if len(writeOffs) != 1 || writeOffs[0].LotID != late.ID || writeOffs[0].Quantity != 3 {
+		t.Errorf("Expected the 3 units of the later lot written off, got %+v", writeOffs)
+	}
+	if quantity, _ := stock(t, inventoryRepo, late.InventoryID); quantity != 0 {
+		t.Errorf("Expected no stock left, got %d", quantity)
+	}
+	expired := 0
+	for _, transaction := range transactionRepo.transactions {
+		if transaction.Type == "EXPIRED" && transaction.Quantity == 3 && transaction.Reference == late.ID {
+			expired++
+		}
+	}
+	if expired != 1 {
+		t.Errorf("Expected one EXPIRED transaction, got %d", expired)
+	}
+	if writeOffs, _ := lots.WriteOffExpired(ctx); len(writeOffs) != 0 {
+		t.Errorf("Expected nothing left to write off, got %+v", writeOffs)
+	}
+}
+
+func TestLotsConsumedByBatchesAndTransfers(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	lotRepo := &MockLotRepository{inventory: inventoryRepo}
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Yoghurt", SKU: "YOG001", Price: 2})
+
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)), WithLotRepository(lotRepo))
+	lots := NewLotService(inventory, lotRepo)
+
+	today := usageDay(time.Now())
+	receive := func(lotNumber string, days int, quantity int64) *domain.Lot {
+		lot, err := lots.Receive(ctx, domain.LotRequest{ProductID: "prod-1", Location: "COLD-1", LotNumber: lotNumber,
+			ExpiresOn: today.AddDate(0, 0, days).Format(time.DateOnly), Quantity: quantity})
+		if err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		return lot
+	}
+	soon := receive("L1", 10, 3)
+	late := receive("L2", 60, 4)
+	remaining := func(id string) int64 {
+		lot, _ := lotRepo.GetByID(ctx, id)
+		return lot.Remaining
+	}
+
+	// Removals of a batch take the lots expiring first, in the batch's order
+	_, err := inventory.ApplyStockBatch(ctx, []domain.StockBatchItem{
+		{ProductID: "prod-1", Location: "COLD-1", Operation: domain.StockRemove, Quantity: 2, Reference: "order-1"},
+		{ProductID: "prod-1", Location: "COLD-1", Operation: domain.StockAdd, Quantity: 1, Reference: "return-1"},
+		{ProductID: "prod-1", Location: "COLD-1", Operation: domain.StockRemove, Quantity: 2, Reference: "order-2"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyStockBatch() error = %v", err)
+	}
+	if remaining(soon.ID) != 0 || remaining(late.ID) != 3 {
+		t.Errorf("Expected lots L1 and L2 at 0 and 3, got %d and %d", remaining(soon.ID), remaining(late.ID))
+	}
+
+	// Transferred units take their lots along
+	if err := inventory.TransferStock(ctx, "prod-1", "COLD-1", "COLD-2", 3, "move-1"); err != nil {
+		t.Fatalf("TransferStock() error = %v", err)
+	}
+	if remaining(late.ID) != 0 {
+		t.Errorf("Expected lot L2 emptied at the source, got %d left", remaining(late.ID))
+	}
+	var moved *domain.Lot
+	for _, lot := range lotRepo.lots {
+		if lot.Location == "COLD-2" {
+			moved = lot
+		}
+	}
+	if moved == nil || moved.LotNumber != "L2" || moved.Remaining != 3 || !moved.ExpiresOn.Equal(late.ExpiresOn) {
+		t.Fatalf("Expected 3 units of lot L2 at the destination, got %+v", moved)
+	}
+
+	// Expired units leave neither through a batch nor through a transfer
+	moved.ExpiresOn = today.AddDate(0, 0, -1)
+	_, err = inventory.ApplyStockBatch(ctx, []domain.StockBatchItem{
+		{ProductID: "prod-1", Location: "COLD-2", Operation: domain.StockRemove, Quantity: 1, Reference: "order-3"},
+	})
+	if !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock removing expired units in a batch, got %v", err)
+	}
+	if err := inventory.TransferStock(ctx, "prod-1", "COLD-2", "COLD-1", 1, "move-2"); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock transferring expired units, got %v", err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, moved.InventoryID); quantity != 3 || moved.Remaining != 3 {
+		t.Errorf("Expected the 3 expired units left in place, got %d on hand and %d in the lot", quantity, moved.Remaining)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// meterName identifies the instruments of this package
+const meterName = "github.com/bhnrathore/distributed-inventory-system/internal/service"
+
+// kpiQueryTimeout bounds the database query behind the KPI gauges
+const kpiQueryTimeout = 5 * time.Second
+
+// businessMetrics holds the business KPI instruments recorded by the services
+type businessMetrics struct {
+	oversellAttempts       metric.Int64Counter
+	reservationTransitions metric.Int64Counter
+	reservationFailures    metric.Int64Counter
+	stockOperations        metric.Int64Counter
+	reads                  metric.Int64Counter
+	lockWaits              metric.Float64Histogram
+}
+
+// newBusinessMetrics creates the KPI instruments of a meter provider
+func newBusinessMetrics(provider metric.MeterProvider) (*businessMetrics, error) {
+	meter := provider.Meter(meterName)
+
+	oversell, err := meter.Int64Counter("inventory.oversell_attempts",
+		metric.WithDescription("Stock operations rejected because they asked for more than was available"),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := meter.Int64Counter("inventory.reservation.transitions",
+		metric.WithDescription("Reservations created, confirmed, released and expired, by status"),
+		metric.WithUnit("{reservation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := meter.Int64Counter("inventory.reservation.failures",
+		metric.WithDescription("Reservations that could not be made, by reason"),
+		metric.WithUnit("{reservation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := meter.Int64Counter("inventory.stock_operations",
+		metric.WithDescription("Recorded stock movements, by transaction type"),
+		metric.WithUnit("{operation}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reads, err := meter.Int64Counter("inventory.coalesced_reads",
+		metric.WithDescription("Reads with read coalescing enabled, by read and whether they shared another caller's query"),
+		metric.WithUnit("{read}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	lockWaits, err := meter.Float64Histogram("inventory.product_lock.wait",
+		metric.WithDescription("Time stock operations waited for a product lock, by operation and outcome"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &businessMetrics{
+		oversellAttempts:       oversell,
+		reservationTransitions: transitions,
+		reservationFailures:    failures,
+		stockOperations:        operations,
+		reads:                  reads,
+		lockWaits:              lockWaits,
+	}, nil
+}
+
+// noopBusinessMetrics returns instruments that record nothing
+func noopBusinessMetrics() *businessMetrics {
+	metrics, _ := newBusinessMetrics(noop.NewMeterProvider())
+	return metrics
+}
+
+// WithMeterProvider records business KPIs (oversell attempts, reservation
+// lifecycle and failures, stock operations, product lock waits) with the given OpenTelemetry meter provider. Instruments that
+// cannot be created fall back to no-ops.
+func WithMeterProvider(provider metric.MeterProvider) InventoryServiceOption {
+	return func(s *InventoryService) {
+		if metrics, err := newBusinessMetrics(provider); err == nil {
+			s.metrics = metrics
+		}
+	}
+}
+
+// recordOversell counts a stock operation rejected for insufficient stock
+func (m *businessMetrics) recordOversell(ctx context.Context, operation string) {
+	m.oversellAttempts.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", operation)))
+}
+
+// recordReservation counts a reservation reaching a status
+func (m *businessMetrics) recordReservation(ctx context.Context, status string) {
+	m.reservationTransitions.Add(ctx, 1, metric.WithAttributes(attribute.String("status", status)))
+}
+
+// recordReservationFailure counts a reservation that could not be made
+func (m *businessMetrics) recordReservationFailure(ctx context.Context, err error) {
+	m.reservationFailures.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reservationFailureReason(err))))
+}
+
+// reservationFailureReason maps a reservation error to a low-cardinality label
+func reservationFailureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInsufficientStock):
+		return "insufficient_stock"
+	case errors.Is(err, ErrQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, ErrLockTimeout):
+		return "lock_timeout"
+	case errors.Is(err, ErrNotReleased):
+		return "not_released"
+	case errors.Is(err, ErrLocationFrozen), errors.Is(err, ErrMovementQueued):
+		return "location_frozen"
+	case errors.Is(err, domain.ErrValidation):
+		return "invalid"
+	case errors.Is(err, domain.ErrNotFound):
+		return "not_found"
+	default:
+		return "error"
+	}
+}
+
+// recordStockOperation counts a recorded stock movement
+func (m *businessMetrics) recordStockOperation(ctx context.Context, transactionType string) {
+	m.stockOperations.Add(ctx, 1, metric.WithAttributes(attribute.String("type", transactionType)))
+}
+
+// recordLockWait records how long an operation waited for a product lock
+// and whether it got it
+func (m *businessMetrics) recordLockWait(ctx context.Context, operation string, wait time.Duration, err error) {
+	outcome := "acquired"
+	switch {
+	case errors.Is(err, ErrLockTimeout):
+		outcome = "timeout"
+	case err != nil:
+		outcome = "error"
+	}
+	m.lockWaits.Record(ctx, wait.Seconds(), metric.WithAttributes(attribute.String("operation", operation), attribute.String("outcome", outcome)))
+}
+
+// recordRead counts a read made with read coalescing enabled
+func (m *businessMetrics) recordRead(ctx context.Context, read string, shared bool) {
+	m.reads.Add(ctx, 1, metric.WithAttributes(attribute.String("read", read), attribute.Bool("shared", shared)))
+}
+
+// RegisterMetrics publishes the inventory-wide KPI gauges (product count,
+// reserved units and low-stock product count) with the meter provider. The values are computed
+// from the database on each collection; products whose total available
+// quantity is below lowStockThreshold count as low on stock.
+func (s *ReportService) RegisterMetrics(provider metric.MeterProvider, lowStockThreshold int64) error {
+	meter := provider.Meter(meterName)
+
+	reserved, err := meter.Int64ObservableGauge("inventory.reserved_units",
+		metric.WithDescription("Units currently reserved across all products and locations"),
+		metric.WithUnit("{unit}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reserved units gauge: %w", err)
+	}
+
+	lowStock, err := meter.Int64ObservableGauge("inventory.low_stock_products",
+		metric.WithDescription("Products whose available quantity is below the low-stock threshold"),
+		metric.WithUnit("{product}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create low stock gauge: %w", err)
+	}
+
+	products, err := meter.Int64ObservableGauge("inventory.products",
+		metric.WithDescription("Products in the catalog"),
+		metric.WithUnit("{product}"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create product count gauge: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		ctx, cancel := context.WithTimeout(ctx, kpiQueryTimeout)
+		defer cancel()
+
+		kpis, err := s.reportRepo.InventoryKPIs(ctx, lowStockThreshold)
+		if err != nil {
+			return err
+		}
+		observer.ObserveInt64(products, kpis.Products)
+		observer.ObserveInt64(reserved, kpis.ReservedUnits)
+		observer.ObserveInt64(lowStock, kpis.LowStockProducts, metric.WithAttributes(attribute.Int64("threshold", lowStockThreshold)))
+		return nil
+	}, products, reserved, lowStock)
+	if err != nil {
+		return fmt.Errorf("failed to register KPI callback: %w", err)
+	}
+
+	return nil
+}
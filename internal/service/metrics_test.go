@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// collectInt64 returns the summed data points of an int64 metric by name
+func collectInt64(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Failed to collect metrics: %v", err)
+	}
+
+	values := make(map[string]int64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					values[m.Name] += point.Value
+				}
+			case metricdata.Gauge[int64]:
+				for _, point := range data.DataPoints {
+					values[m.Name] += point.Value
+				}
+			}
+		}
+	}
+	return values
+}
+
+func TestBusinessMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	reservationRepo := NewMockReservationRepository()
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	inventoryService := NewInventoryService(productRepo, inventoryRepo, transactionRepo, WithMeterProvider(provider))
+	reservationService := NewReservationService(inventoryService, reservationRepo, time.Minute)
+
+	if err := inventoryService.RemoveStock(ctx, "prod-1", 11, "SO-1"); err == nil {
+		t.Fatal("Expected removal beyond stock to fail")
+	}
+	if err := inventoryService.ReserveStock(ctx, "prod-1", 20, "SO-2"); err == nil {
+		t.Fatal("Expected reservation beyond stock to fail")
+	}
+
+	reservation, err := reservationService.CreateReservation(ctx, "prod-1", "", "", 2, "ORDER-1", 0)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	reservationRepo.reservations[reservation.ID].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := reservationService.ExpireReservations(ctx); err != nil {
+		t.Fatalf("Failed to expire reservations: %v", err)
+	}
+
+	if err := NewReportService(&MockReportRepository{}).RegisterMetrics(provider, 5); err != nil {
+		t.Fatalf("Failed to register KPI gauges: %v", err)
+	}
+
+	values := collectInt64(t, reader)
+	if values["inventory.oversell_attempts"] != 2 {
+		t.Errorf("Expected 2 oversell attempts, got %d", values["inventory.oversell_attempts"])
+	}
+	if values["inventory.reservation.transitions"] != 2 {
+		t.Errorf("Expected 2 reservation transitions (created, expired), got %d", values["inventory.reservation.transitions"])
+	}
+	if values["inventory.reserved_units"] != 42 || values["inventory.low_stock_products"] != 3 {
+		t.Errorf("Expected KPI gauges 42 and 3, got %d and %d", values["inventory.reserved_units"], values["inventory.low_stock_products"])
+	}
+	if values["inventory.products"] != 7 {
+		t.Errorf("Expected product count gauge 7, got %d", values["inventory.products"])
+	}
+	if values["inventory.reservation.failures"] != 1 {
+		t.Errorf("Expected 1 reservation failure, got %d", values["inventory.reservation.failures"])
+	}
+	if values["inventory.stock_operations"] != 2 {
+		t.Errorf("Expected 2 stock operations (reserve, unreserve), got %d", values["inventory.stock_operations"])
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ErrOrderNotPending is returned when confirming or cancelling an order that
+// was already confirmed or cancelled
+var ErrOrderNotPending = errors.New("order is no longer pending")
+
+// OrderService coordinates orders as sagas over the reservations of their
+// lines: each step either completes for every line or is compensated for the
+// lines it already completed, so an order never ends up half reserved, half
+// shipped or half cancelled. All reservations and transactions of an order
+// carry its ID as reference.
+type OrderService struct {
+	inventory    *InventoryService
+	reservations *ReservationService
+	orderRepo    repository.OrderRepository
+}
+
+// NewOrderService creates a new OrderService
+func NewOrderService(reservations *ReservationService, orderRepo repository.OrderRepository) *OrderService {
+	return &OrderService{
+		inventory:    reservations.inventory,
+		reservations: reservations,
+		orderRepo:    orderRepo,
+	}
+}
+
+// CreateOrder reserves every line of the order, each expiring after ttl
+// (zero uses the reservation default), and records the order as pending. A
+// line that cannot be reserved releases the lines reserved before it and
+// fails the order, as does an order ID that is already in use.
+func (s *OrderService) CreateOrder(ctx context.Context, id string, lines []domain.OrderReservationLine, ttl time.Duration) (*domain.Order, error) {
+	if err := domain.ValidateOrder(id, lines); err != nil {
+		return nil, err
+	}
+	if ttl < 0 {
+		return nil, domain.NewValidationError("ttl cannot be negative")
+	}
+	if _, err := s.orderRepo.GetByID(ctx, id); err == nil {
+		return nil, domain.ErrDuplicateOrder
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	order := &domain.Order{
+		ID:     id,
+		Status: domain.OrderStatusPending,
+		Lines:  make([]domain.OrderLine, 0, len(lines)),
+	}
+	for i, line := range lines {
+		reservation, err := s.reservations.CreateReservation(ctx, line.ProductID, line.Location, "", line.Quantity, id, ttl)
+		if err != nil {
+			s.compensate(ctx, order, "release", s.releaseLine)
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, line.ProductID, err)
+		}
+		order.Lines = append(order.Lines, domain.OrderLine{
+			ProductID:     line.ProductID,
+			Location:      reservation.Location,
+			Quantity:      line.Quantity,
+			ReservationID: reservation.ID,
+		})
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		s.compensate(ctx, order, "release", s.releaseLine)
+		if errors.Is(err, domain.ErrDuplicateOrder) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	slog.InfoContext(ctx, "order reserved", "order_id", id, "lines", len(order.Lines))
+	return order, nil
+}
+
+// GetOrder retrieves an order by ID
+func (s *OrderService) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return order, nil
+}
+
+// ListOrders lists orders newest first, optionally only those in a status
+func (s *OrderService) ListOrders(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
+	switch status {
+	case "", domain.OrderStatusPending, domain.OrderStatusConfirmed, domain.OrderStatusCancelled:
+	default:
+		return nil, domain.NewValidationError("unknown order status %q", status)
+	}
+
+	orders, err := s.orderRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return orders, nil
+}
+
+// ConfirmOrder confirms the reservation of every line, turning the reserved
+// units into outgoing stock. If a line cannot be confirmed, e.g. because its
+// reservation expired, the lines confirmed before it are put back into stock
+// and reserved again, and the order stays pending.
+func (s *OrderService) ConfirmOrder(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := s.claim(ctx, id, domain.OrderStatusConfirmed)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed := &domain.Order{ID: order.ID}
+	for i, line := range order.Lines {
+		if _, err := s.reservations.ConfirmReservation(ctx, line.ReservationID); err != nil {
+			s.compensate(ctx, confirmed, "unconfirm", s.unconfirmLine)
+			s.unclaim(ctx, order, domain.OrderStatusConfirmed)
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, line.ProductID, err)
+		}
+		confirmed.Lines = append(confirmed.Lines, line)
+	}
+
+	slog.InfoContext(ctx, "order confirmed", "order_id", id, "lines", len(order.Lines))
+	return order, nil
+}
+
+// CancelOrder releases the reservation of every line. Lines whose reservation
+// already ended, e.g. by expiry, hold nothing and are skipped. If a line
+// cannot be released the order stays pending with the lines released so far,
+// and cancelling it again releases the rest.
+func (s *OrderService) CancelOrder(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := s.claim(ctx, id, domain.OrderStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range order.Lines {
+		if err := s.releaseLine(ctx, line); err != nil {
+			s.unclaim(ctx, order, domain.OrderStatusCancelled)
+			return nil, fmt.Errorf("line %d (%s): %w", i+1, line.ProductID, err)
+		}
+	}
+
+	slog.InfoContext(ctx, "order cancelled", "order_id", id, "lines", len(order.Lines))
+	return order, nil
+}
+
+// claim moves a pending order to the status of the step about to run, so
+// that confirming and cancelling the same order exclude each other
+func (s *OrderService) claim(ctx context.Context, id string, status domain.OrderStatus) (*domain.Order, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderStatusPending {
+		return nil, fmt.Errorf("%w: order is %s", ErrOrderNotPending, order.Status)
+	}
+
+	claimed, err := s.orderRepo.UpdateStatus(ctx, id, domain.OrderStatusPending, status)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrOrderNotPending
+	}
+
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	return order, nil
+}
+
+// unclaim returns an order whose step failed to pending
+func (s *OrderService) unclaim(ctx context.Context, order *domain.Order, status domain.OrderStatus) {
+	if _, err := s.orderRepo.UpdateStatus(ctx, order.ID, status, domain.OrderStatusPending); err != nil {
+		slog.ErrorContext(ctx, "failed to return order to pending", "order_id", order.ID, "error", err)
+	}
+}
+
+// compensate undoes a step for the lines of order it completed, in reverse.
+// Compensation failures are logged; the affected reservations are left for
+// an operator, and pending ones still expire.
+func (s *OrderService) compensate(ctx context.Context, order *domain.Order, step string, undo func(context.Context, domain.OrderLine) error) {
+	for i := len(order.Lines) - 1; i >= 0; i-- {
+		line := order.Lines[i]
+		if err := undo(ctx, line); err != nil {
+			slog.ErrorContext(ctx, "order compensation failed", "order_id", order.ID, "step", step,
+				"reservation_id", line.ReservationID, "error", err)
+		}
+	}
+}
+
+// releaseLine releases the reservation of a line unless it already ended
+func (s *OrderService) releaseLine(ctx context.Context, line domain.OrderLine) error {
+	_, err := s.reservations.ReleaseReservation(ctx, line.ReservationID)
+	if errors.Is(err, ErrReservationNotPending) {
+		return nil
+	}
+	return err
+}
+
+// unconfirmLine reverses the confirmation of a line: its units return to
+// stock reserved again, and its reservation is pending once more
+func (s *OrderService) unconfirmLine(ctx context.Context, line domain.OrderLine) error {
+	reservation, err := s.reservations.GetReservation(ctx, line.ReservationID)
+	if err != nil {
+		return err
+	}
+	reopened, err := s.reservations.reservationRepo.UpdateStatus(ctx, reservation.ID, domain.ReservationStatusConfirmed, domain.ReservationStatusPending)
+	if err != nil {
+		return err
+	}
+	if !reopened {
+		return fmt.Errorf("reservation %s is no longer confirmed", reservation.ID)
+	}
+	return s.inventory.restockReserved(ctx, reservation, "Order confirmation compensated")
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ErrBackorderNotOpen is returned when cancelling a backorder that is no
+// longer open
+var ErrBackorderNotOpen = errors.New("backorder is not open")
+
+// OrderReservationService reserves the lines of an order together and keeps
+// the shortfalls of partially reserved orders as backorders
+type OrderReservationService struct {
+	inventory     *InventoryService
+	reservations  *ReservationService
+	backorderRepo repository.BackorderRepository
+}
+
+// NewOrderReservationService creates a new OrderReservationService
+func NewOrderReservationService(reservations *ReservationService, backorderRepo repository.BackorderRepository) *OrderReservationService {
+	return &OrderReservationService{
+		inventory:     reservations.inventory,
+		reservations:  reservations,
+		backorderRepo: backorderRepo,
+	}
+}
+
+// ReserveMany reserves the lines of an order under one reference, each as
+// its own reservation expiring after ttl (zero uses the reservation default).
+// With ReservationPolicyAllOrNothing a line that cannot be reserved in full
+// releases the lines reserved before it and fails the whole request. With
+// ReservationPolicyPartial each line reserves what is available and the
+// shortfall becomes an open backorder; lines failing for other reasons, such
+// as an unknown product or an exceeded quota, report their error and the
+// remaining lines are still reserved.
+func (s *OrderReservationService) ReserveMany(ctx context.Context, reference string, lines []domain.OrderReservationLine, policy domain.ReservationPolicy, ttl time.Duration) (*domain.OrderReservation, error) {
+	if err := domain.ValidateOrderReservation(reference, lines, policy); err != nil {
+		return nil, err
+	}
+	if ttl < 0 {
+		return nil, domain.NewValidationError("ttl cannot be negative")
+	}
+
+	order := &domain.OrderReservation{
+		Reference: reference,
+		Policy:    policy,
+		Complete:  true,
+		Lines:     make([]domain.OrderReservationLineResult, 0, len(lines)),
+	}
+
+	if policy == domain.ReservationPolicyAllOrNothing {
+		for i, line := range lines {
+			reservation, err := s.reservations.CreateReservation(ctx, line.ProductID, line.Location, "", line.Quantity, reference, ttl)
+			if err != nil {
+				s.releaseAll(ctx, order)
+				return nil, fmt.Errorf("line %d (%s): %w", i+1, line.ProductID, err)
+			}
+			order.Lines = append(order.Lines, domain.OrderReservationLineResult{
+				ProductID:   line.ProductID,
+				Location:    reservation.Location,
+				Requested:   line.Quantity,
+				Reserved:    line.Quantity,
+				Reservation: reservation,
+			})
+		}
+		return order, nil
+	}
+
+	for _, line := range lines {
+		result := s.reserveAvailable(ctx, reference, line, ttl)
+		if result.Reserved < result.Requested {
+			order.Complete = false
+		}
+		order.Lines = append(order.Lines, result)
+	}
+	return order, nil
+}
+
+// reserveAvailable reserves as much of a line as is available and backorders
+// the rest. Availability is read again when the full quantity does not fit,
+// so a concurrent reservation can still leave the line short.
+func (s *OrderReservationService) reserveAvailable(ctx context.Context, reference string, line domain.OrderReservationLine, ttl time.Duration) domain.OrderReservationLineResult {
+	result := domain.OrderReservationLineResult{
+		ProductID: line.ProductID,
+		Location:  line.Location,
+		Requested: line.Quantity,
+	}
+
+	reservation, err := s.reservations.CreateReservation(ctx, line.ProductID, line.Location, "", line.Quantity, reference, ttl)
+	if errors.Is(err, domain.ErrInsufficientStock) {
+		item, err := s.inventory.resolveInventory(ctx, line.ProductID, line.Location)
+		if err != nil {
+			result.Error = fmt.Sprintf("failed to get inventory: %v", err)
+			return result
+		}
+		result.Location = item.Location
+
+		reservation = nil
+		if available := item.AvailableQuantity(); available > 0 {
+			reservation, err = s.reservations.CreateReservation(ctx, line.ProductID, line.Location, "", min(available, line.Quantity), reference, ttl)
+			if err != nil && !errors.Is(err, domain.ErrInsufficientStock) {
+				result.Error = err.Error()
+				return result
+			}
+		}
+	} else if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if reservation != nil {
+		result.Reservation = reservation
+		result.Reserved = reservation.Quantity
+		result.Location = reservation.Location
+	}
+
+	if shortfall := line.Quantity - result.Reserved; shortfall > 0 {
+		backorder := &domain.Backorder{
+			ProductID: line.ProductID,
+			Location:  result.Location,
+			Quantity:  shortfall,
+			Reference: reference,
+			Status:    domain.BackorderStatusOpen,
+		}
+		if err := s.backorderRepo.Create(ctx, backorder); err != nil {
+			result.Error = fmt.Sprintf("failed to create backorder: %v", err)
+			return result
+		}
+		result.Backorder = backorder
+		result.Backordered = shortfall
+	}
+	return result
+}
+
+// releaseAll releases the reservations of a failed all-or-nothing request
+func (s *OrderReservationService) releaseAll(ctx context.Context, order *domain.OrderReservation) {
+	for _, line := range order.Lines {
+		if _, err := s.reservations.ReleaseReservation(ctx, line.Reservation.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to release reservation of failed order reservation",
+				"reference", order.Reference, "reservation_id", line.Reservation.ID, "error", err)
+		}
+	}
+}
+
+// ListBackorders lists backorders newest first, optionally restricted to a
+// product and a status
+func (s *OrderReservationService) ListBackorders(ctx context.Context, productID string, status domain.BackorderStatus, limit, offset int) ([]*domain.Backorder, error) {
+	if status != "" && status != domain.BackorderStatusOpen && status != domain.BackorderStatusCancelled {
+		return nil, domain.NewValidationError("unknown backorder status %q", status)
+	}
+
+	backorders, err := s.backorderRepo.List(ctx, productID, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backorders: %w", err)
+	}
+	return backorders, nil
+}
+
+// CancelBackorder cancels an open backorder, e.g. when the customer no longer
+// waits for the missing units
+func (s *OrderReservationService) CancelBackorder(ctx context.Context, id string) (*domain.Backorder, error) {
+	backorder, err := s.backorderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backorder: %w", err)
+	}
+	if backorder.Status != domain.BackorderStatusOpen {
+		return nil, fmt.Errorf("%w: backorder is %s", ErrBackorderNotOpen, backorder.Status)
+	}
+
+	cancelled, err := s.backorderRepo.UpdateStatus(ctx, id, domain.BackorderStatusOpen, domain.BackorderStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrBackorderNotOpen
+	}
+
+	backorder.Status = domain.BackorderStatusCancelled
+	backorder.UpdatedAt = time.Now()
+	return backorder, nil
+}
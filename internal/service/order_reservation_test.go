@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockBackorderRepository implements BackorderRepository for testing
+type MockBackorderRepository struct {
+	backorders []*domain.Backorder
+}
+
+func (m *MockBackorderRepository) Create(ctx context.Context, backorder *domain.Backorder) error {
+	if err := backorder.Validate(); err != nil {
+		return err
+	}
+	backorder.ID = "bo-" + backorder.ProductID
+	stored := *backorder
+	m.backorders = append(m.backorders, &stored)
+	return nil
+}
+
+func (m *MockBackorderRepository) GetByID(ctx context.Context, id string) (*domain.Backorder, error) {
+	for _, b := range m.backorders {
+		if b.ID == id {
+			copied := *b
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (m *MockBackorderRepository) List(ctx context.Context, productID string, status domain.BackorderStatus, limit, offset int) ([]*domain.Backorder, error) {
+	var backorders []*domain.Backorder
+	for _, b := range m.backorders {
+		if (productID == "" || b.ProductID == productID) && (status == "" || b.Status == status) {
+			backorders = append(backorders, b)
+		}
+	}
+	return backorders, nil
+}
+
+func (m *MockBackorderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.BackorderStatus) (bool, error) {
+	for _, b := range m.backorders {
+		if b.ID == id && b.Status == from {
+			b.Status = to
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestReserveMany(t *testing.T) {
+	reservations, _, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+	reservations.inventory.productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Controller", SKU: "CTL001", Price: 59})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-2", Quantity: 3, Location: "WH-A"})
+	backorderRepo := &MockBackorderRepository{}
+	service := NewOrderReservationService(reservations, backorderRepo)
+
+	lines := []domain.OrderReservationLine{
+		{ProductID: "prod-1", Quantity: 4},
+		{ProductID: "prod-2", Quantity: 5},
+	}
+
+	// All or nothing: the short line fails the order and gives the first line's stock back
+	if _, err := service.ReserveMany(ctx, "ORDER-1", lines, domain.ReservationPolicyAllOrNothing, 0); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("Expected insufficient stock, got %v", err)
+	}
+	if inv, _ := inventoryRepo.GetByID(ctx, "inv-1"); inv.Reserved != 0 {
+		t.Errorf("Expected the first line to be released, %d still reserved", inv.Reserved)
+	}
+	if len(backorderRepo.backorders) != 0 {
+		t.Errorf("Expected no backorders, got %+v", backorderRepo.backorders)
+	}
+
+	// Partial: what is available is reserved and the shortfall backordered
+	order, err := service.ReserveMany(ctx, "ORDER-1", append(lines, domain.OrderReservationLine{ProductID: "prod-1", Location: "WH-Z", Quantity: 1}), domain.ReservationPolicyPartial, 0)
+	if err != nil {
+		t.Fatalf("ReserveMany() error = %v", err)
+	}
+	if order.Complete || len(order.Lines) != 3 {
+		t.Fatalf("Expected an incomplete order of 3 lines, got %+v", order)
+	}
+	if line := order.Lines[0]; line.Reserved != 4 || line.Backorder != nil {
+		t.Errorf("Expected the first line reserved in full, got %+v", line)
+	}
+	if line := order.Lines[1]; line.Reserved != 3 || line.Backordered != 2 || line.Backorder == nil || line.Backorder.Location != "WH-A" {
+		t.Errorf("Expected 3 reserved and 2 backordered at WH-A, got %+v", line)
+	}
+	if line := order.Lines[2]; line.Error == "" || line.Backorder != nil {
+		t.Errorf("Expected an error and no backorder for an unknown location, got %+v", line)
+	}
+	if inv, _ := inventoryRepo.GetByID(ctx, "inv-2"); inv.Reserved != 3 {
+		t.Errorf("Expected 3 units of prod-2 reserved, got %d", inv.Reserved)
+	}
+
+	// A line with nothing available is backordered in full
+	order, err = service.ReserveMany(ctx, "ORDER-2", lines[1:], domain.ReservationPolicyPartial, 0)
+	if err != nil {
+		t.Fatalf("ReserveMany() error = %v", err)
+	}
+	if line := order.Lines[0]; line.Reserved != 0 || line.Reservation != nil || line.Backordered != 5 {
+		t.Errorf("Expected the line backordered in full, got %+v", line)
+	}
+
+	if _, err := service.ReserveMany(ctx, "ORDER-3", lines, "best_effort", 0); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for an unknown policy, got %v", err)
+	}
+
+	backorder := backorderRepo.backorders[0]
+	if _, err := service.CancelBackorder(ctx, backorder.ID); err != nil {
+		t.Fatalf("CancelBackorder() error = %v", err)
+	}
+	if _, err := service.CancelBackorder(ctx, backorder.ID); !errors.Is(err, ErrBackorderNotOpen) {
+		t.Errorf("Expected ErrBackorderNotOpen cancelling twice, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockOrderRepository implements OrderRepository for testing
+type MockOrderRepository struct {
+	orders map[string]*domain.Order
+}
+
+func NewMockOrderRepository() *MockOrderRepository {
+	return &MockOrderRepository{orders: make(map[string]*domain.Order)}
+}
+
+func (m *MockOrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	if _, ok := m.orders[order.ID]; ok {
+		return domain.ErrDuplicateOrder
+	}
+	stored := *order
+	m.orders[order.ID] = &stored
+	return nil
+}
+
+func (m *MockOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *MockOrderRepository) List(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	for _, order := range m.orders {
+		if status == "" || order.Status == status {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.OrderStatus) (bool, error) {
+	order, ok := m.orders[id]
+	if !ok || order.Status != from {
+		return false, nil
+	}
+	order.Status = to
+	return true, nil
+}
+
+func setupOrderTest(t *testing.T) (*OrderService, *MockOrderRepository, *MockInventoryRepository) {
+	t.Helper()
+
+	reservations, _, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+	reservations.inventory.productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Controller", SKU: "CTL001", Price: 59})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-2", Quantity: 3, Location: "WH-A"})
+
+	orderRepo := NewMockOrderRepository()
+	return NewOrderService(reservations, orderRepo), orderRepo, inventoryRepo
+}
+
+// stock returns the quantity and reserved units of an inventory row
+func stock(t *testing.T, inventoryRepo *MockInventoryRepository, id string) (int64, int64) {
+	t.Helper()
+	item, err := inventoryRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID(%s) error = %v", id, err)
+	}
+	return item.Quantity, item.Reserved
+}
+
+func TestCreateOrder(t *testing.T) {
+	service, orderRepo, inventoryRepo := setupOrderTest(t)
+	ctx := context.Background()
+
+	// A short line releases the lines reserved before it and records no order
+	lines := []domain.OrderReservationLine{{ProductID: "prod-1", Quantity: 4}, {ProductID: "prod-2", Quantity: 5}}
+	if _, err := service.CreateOrder(ctx, "ORDER-1", lines, 0); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("Expected insufficient stock, got %v", err)
+	}
+	if _, reserved := stock(t, inventoryRepo, "inv-1"); reserved != 0 {
+		t.Errorf("Expected the first line to be released, %d still reserved", reserved)
+	}
+	if len(orderRepo.orders) != 0 {
+		t.Errorf("Expected no order, got %+v", orderRepo.orders)
+	}
+
+	lines[1].Quantity = 2
+	order, err := service.CreateOrder(ctx, "ORDER-1", lines, 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if order.Status != domain.OrderStatusPending || len(order.Lines) != 2 || order.Lines[0].Location != "WH-A" || order.Lines[1].ReservationID == "" {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	if _, reserved := stock(t, inventoryRepo, "inv-2"); reserved != 2 {
+		t.Errorf("Expected 2 units of prod-2 reserved, got %d", reserved)
+	}
+
+	if _, err := service.CreateOrder(ctx, "ORDER-1", lines[:1], 0); !errors.Is(err, domain.ErrDuplicateOrder) {
+		t.Errorf("Expected ErrDuplicateOrder reusing the order ID, got %v", err)
+	}
+	if _, err := service.CreateOrder(ctx, "", lines, 0); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error without an ID, got %v", err)
+	}
+}
+
+func TestConfirmOrder(t *testing.T) {
+	service, orderRepo, inventoryRepo := setupOrderTest(t)
+	ctx := context.Background()
+
+	lines := []domain.OrderReservationLine{{ProductID: "prod-1", Quantity: 4}, {ProductID: "prod-2", Quantity: 2}}
+	order, err := service.CreateOrder(ctx, "ORDER-1", lines, 0)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	// The second reservation ended on its own, so the first line's
+	// confirmation is compensated and the order stays pending
+	if _, err := service.reservations.ReleaseReservation(ctx, order.Lines[1].ReservationID); err != nil {
+		t.Fatalf("ReleaseReservation() error = %v", err)
+	}
+	if _, err := service.ConfirmOrder(ctx, "ORDER-1"); !errors.Is(err, ErrReservationNotPending) {
+		t.Fatalf("Expected ErrReservationNotPending, got %v", err)
+	}
+	if quantity, reserved := stock(t, inventoryRepo, "inv-1"); quantity != 10 || reserved != 4 {
+		t.Errorf("Expected the first line back in stock and reserved, got %d/%d", quantity, reserved)
+	}
+	reservation, _ := service.reservations.GetReservation(ctx, order.Lines[0].ReservationID)
+	if reservation.Status != domain.ReservationStatusPending {
+		t.Errorf("Expected the first reservation pending again, got %s", reservation.Status)
+	}
+	if status := orderRepo.orders["ORDER-1"].Status; status != domain.OrderStatusPending {
+		t.Errorf("Expected the order pending, got %s", status)
+	}
+
+	// Cancelling releases what is still held and skips the ended reservation
+	cancelled, err := service.CancelOrder(ctx, "ORDER-1")
+	if err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	if cancelled.Status != domain.OrderStatusCancelled {
+		t.Errorf("Expected a cancelled order, got %s", cancelled.Status)
+	}
+	if _, reserved := stock(t, inventoryRepo, "inv-1"); reserved != 0 {
+		t.Errorf("Expected the first line released, %d still reserved", reserved)
+	}
+	if _, err := service.ConfirmOrder(ctx, "ORDER-1"); !errors.Is(err, ErrOrderNotPending) {
+		t.Errorf("Expected ErrOrderNotPending confirming a cancelled order, got %v", err)
+	}
+
+	// A complete order ships every line
+	if _, err := service.CreateOrder(ctx, "ORDER-2", lines, 0); err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	confirmed, err := service.ConfirmOrder(ctx, "ORDER-2")
+	if err != nil {
+		t.Fatalf("ConfirmOrder() error = %v", err)
+	}
+	if confirmed.Status != domain.OrderStatusConfirmed {
+		t.Errorf("Expected a confirmed order, got %s", confirmed.Status)
+	}
+	if quantity, reserved := stock(t, inventoryRepo, "inv-2"); quantity != 1 || reserved != 0 {
+		t.Errorf("Expected 2 units of prod-2 shipped, got %d/%d", quantity, reserved)
+	}
+	if _, err := service.CancelOrder(ctx, "ORDER-2"); !errors.Is(err, ErrOrderNotPending) {
+		t.Errorf("Expected ErrOrderNotPending cancelling a confirmed order, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxPayloadSampleSize is the number of bytes kept of each sampled request
+// and response body; longer bodies are truncated
+const MaxPayloadSampleSize = 64 << 10
+
+// PayloadAuditService records full request and response bodies of a random
+// share of mutating requests, to reconstruct disputed stock changes
+type PayloadAuditService struct {
+	sampleRepo repository.PayloadSampleRepository
+	rate       float64
+	retention  time.Duration
+}
+
+// NewPayloadAuditService creates a new PayloadAuditService that samples the
+// given percentage (0 to 100) of requests and keeps samples for retention
+func NewPayloadAuditService(sampleRepo repository.PayloadSampleRepository, percent float64, retention time.Duration) (*PayloadAuditService, error) {
+	if percent < 0 || percent > 100 {
+		return nil, errors.New("sample percentage must be between 0 and 100")
+	}
+
+	return &PayloadAuditService{
+		sampleRepo: sampleRepo,
+		rate:       percent / 100,
+		retention:  retention,
+	}, nil
+}
+
+// Sampled decides whether the current request is recorded
+func (s *PayloadAuditService) Sampled() bool {
+	return s.rate > 0 && rand.Float64() < s.rate
+}
+
+// Record stores a sample, truncating bodies to MaxPayloadSampleSize
+func (s *PayloadAuditService) Record(ctx context.Context, sample *domain.PayloadSample) error {
+	if len(sample.RequestBody) > MaxPayloadSampleSize {
+		sample.RequestBody = sample.RequestBody[:MaxPayloadSampleSize]
+		sample.Truncated = true
+	}
+	if len(sample.ResponseBody) > MaxPayloadSampleSize {
+		sample.ResponseBody = sample.ResponseBody[:MaxPayloadSampleSize]
+		sample.Truncated = true
+	}
+
+	if err := s.sampleRepo.Create(ctx, sample); err != nil {
+		return fmt.Errorf("failed to record payload sample: %w", err)
+	}
+	return nil
+}
+
+// ListSamples lists recorded samples with pagination, optionally restricted to
+// request paths containing path
+func (s *PayloadAuditService) ListSamples(ctx context.Context, path string, limit, offset int) ([]*domain.PayloadSample, error) {
+	samples, err := s.sampleRepo.List(ctx, path, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payload samples: %w", err)
+	}
+	return samples, nil
+}
+
+// Run deletes samples older than the retention period every interval until
+// ctx is cancelled
+func (s *PayloadAuditService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			count, err := s.sampleRepo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "payload sample cleanup failed", "error", err)
+			}
+			if count > 0 {
+				slog.InfoContext(ctx, "deleted expired payload samples", "count", count)
+			}
+		}
+	}
+}
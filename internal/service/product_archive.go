@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxProductArchive bounds the number of products one bulk archive applies to
+const MaxProductArchive = 10000
+
+// productArchiveBatchSize is the number of products archived per query
+const productArchiveBatchSize = 100
+
+// ProductArchiveService archives the products matching a filter in bulk,
+// after a preview of the affected set
+type ProductArchiveService struct {
+	inventory *InventoryService
+	repo      repository.ProductArchiveRepository
+}
+
+// NewProductArchiveService creates a new ProductArchiveService
+func NewProductArchiveService(inventory *InventoryService, repo repository.ProductArchiveRepository) *ProductArchiveService {
+	return &ProductArchiveService{
+		inventory: inventory,
+		repo:      repo,
+	}
+}
+
+// Preview lists the products the filter selects
+func (s *ProductArchiveService) Preview(ctx context.Context, filter domain.ProductArchiveFilter) (*domain.ProductArchivePreview, error) {
+	candidates, err := s.candidates(ctx, filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if candidates == nil {
+		candidates = []*domain.ArchiveCandidate{}
+	}
+	return &domain.ProductArchivePreview{Filter: filter, Total: len(candidates), Products: candidates}, nil
+}
+
+// Archive archives the products the filter selects, provided they are
+// still the expected number shown by the preview
+func (s *ProductArchiveService) Archive(ctx context.Context, filter domain.ProductArchiveFilter, expected int) (*domain.ProductArchiveResult, error) {
+	now := time.Now()
+	candidates, err := s.expectedCandidates(ctx, filter, expected, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.archive(ctx, filter, now, candidates, nil)
+}
+
+// ArchiveJob selects the products to archive like Archive and returns their
+// archive as a job. The job summary counts the outcome; the archived and
+// skipped product IDs are its result.
+func (s *ProductArchiveService) ArchiveJob(ctx context.Context, filter domain.ProductArchiveFilter, expected int) (JobFunc, error) {
+	now := time.Now()
+	candidates, err := s.expectedCandidates(ctx, filter, expected, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error) {
+		result, err := s.archive(ctx, filter, now, candidates, progress)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode archive result: %w", err)
+		}
+		summary := map[string]any{
+			"matched":  result.Matched,
+			"archived": len(result.Archived),
+			"skipped":  len(result.Skipped),
+		}
+		return &domain.JobResult{Summary: summary, ContentType: "application/json", Data: data}, nil
+	}, nil
+}
+
+// expectedCandidates lists the products the filter selects, failing with
+// domain.ErrConflict unless they are the expected number, i.e. the set changed since
+// it was previewed
+func (s *ProductArchiveService) expectedCandidates(ctx context.Context, filter domain.ProductArchiveFilter, expected int, now time.Time) ([]*domain.ArchiveCandidate, error) {
+	candidates, err := s.candidates(ctx, filter, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) != expected {
+		return nil, fmt.Errorf("%w: filter selects %d products, not the %d expected; preview again", domain.ErrConflict, len(candidates), expected)
+	}
+	return candidates, nil
+}
+
+// candidates validates the filter and lists the products it selects as of
+// now, with inactivity measured from now
+func (s *ProductArchiveService) candidates(ctx context.Context, filter domain.ProductArchiveFilter, now time.Time) ([]*domain.ArchiveCandidate, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.Candidates(ctx, filter, archiveCutoff(filter, now), MaxProductArchive+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > MaxProductArchive {
+		return nil, domain.NewValidationError("filter selects more than %d products; narrow it down", MaxProductArchive)
+	}
+	return candidates, nil
+}
+
+// archiveCutoff is the start of the inactivity window of a filter
+func archiveCutoff(filter domain.ProductArchiveFilter, now time.Time) time.Time {
+	return now.AddDate(0, 0, -filter.InactiveDays)
+}
+
+// archive archives the candidates in batches, re-checking the filter so that
+// products stocked or reserved since they were selected are skipped, and
+// reports the products processed as progress. A failing batch stops the
+// archive; the products of earlier batches stay archived.
+func (s *ProductArchiveService) archive(ctx context.Context, filter domain.ProductArchiveFilter, now time.Time, candidates []*domain.ArchiveCandidate, progress ProgressFunc) (*domain.ProductArchiveResult, error) {
+	result := &domain.ProductArchiveResult{Matched: len(candidates), Archived: []string{}, Skipped: []string{}}
+	total := int64(len(candidates))
+	progress.report(0, total)
+
+	cutoff := archiveCutoff(filter, now)
+	for start := 0; start < len(candidates); start += productArchiveBatchSize {
+		batch := candidates[start:min(start+productArchiveBatchSize, len(candidates))]
+		ids := make([]string, len(batch))
+		for i, candidate := range batch {
+			ids[i] = candidate.ProductID
+		}
+
+		archived, err := s.repo.ArchiveMatching(ctx, ids, filter, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("archived %d of %d products: %w", len(result.Archived), len(candidates), err)
+		}
+
+		done := make(map[string]bool, len(archived))
+		for _, id := range archived {
+			done[id] = true
+			s.inventory.evictAvailability(id)
+		}
+		for _, id := range ids {
+			if done[id] {
+				result.Archived = append(result.Archived, id)
+			} else {
+				result.Skipped = append(result.Skipped, id)
+			}
+		}
+		progress.report(int64(start+len(batch)), total)
+	}
+
+	slog.InfoContext(ctx, "products archived in bulk", "archived", len(result.Archived), "skipped", len(result.Skipped))
+	return result, nil
+}
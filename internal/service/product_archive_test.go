@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockProductArchiveRepository implements ProductArchiveRepository interface
+type MockProductArchiveRepository struct {
+	candidates []*domain.ArchiveCandidate
+	cutoff     time.Time
+	// changed lists the products that stopped matching after the preview
+	changed  map[string]bool
+	batches  [][]string
+	archived map[string]bool
+}
+
+func (m *MockProductArchiveRepository) Candidates(ctx context.Context, filter domain.ProductArchiveFilter, cutoff time.Time, limit int) ([]*domain.ArchiveCandidate, error) {
+	m.cutoff = cutoff
+	if len(m.candidates) > limit {
+		return m.candidates[:limit], nil
+	}
+	return m.candidates, nil
+}
+
+func (m *MockProductArchiveRepository) ArchiveMatching(ctx context.Context, ids []string, filter domain.ProductArchiveFilter, cutoff time.Time) ([]string, error) {
+	m.batches = append(m.batches, ids)
+	var archived []string
+	for _, id := range ids {
+		if !m.changed[id] {
+			m.archived[id] = true
+			archived = append(archived, id)
+		}
+	}
+	return archived, nil
+}
+
+func newArchiveCandidates(n int) []*domain.ArchiveCandidate {
+	candidates := make([]*domain.ArchiveCandidate, n)
+	for i := range candidates {
+		id := fmt.Sprintf("prod-%03d", i)
+		candidates[i] = &domain.ArchiveCandidate{ProductID: id, SKU: id}
+	}
+	return candidates
+}
+
+func TestProductArchivePreview(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockProductArchiveRepository{candidates: newArchiveCandidates(3), archived: map[string]bool{}}
+	service := NewProductArchiveService(NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository()), repo)
+
+	filter := domain.ProductArchiveFilter{InactiveDays: 30}
+	preview, err := service.Preview(ctx, filter)
+	if err != nil {
+		t.Fatalf("Preview() error = %v", err)
+	}
+	if preview.Total != 3 || len(preview.Products) != 3 || preview.Filter != filter {
+		t.Errorf("Unexpected preview %+v", preview)
+	}
+	if days := time.Since(repo.cutoff).Hours() / 24; days < 29.9 || days > 30.1 {
+		t.Errorf("Expected movements to be looked for in the last 30 days, got a cutoff %v", repo.cutoff)
+	}
+	if len(repo.batches) != 0 {
+		t.Error("Expected a preview not to archive anything")
+	}
+
+	for _, filter := range []domain.ProductArchiveFilter{{}, {InactiveDays: -1}, {ZeroStock: true, InactiveDays: domain.MaxArchiveInactiveDays + 1}} {
+		if _, err := service.Preview(ctx, filter); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Expected a validation error for %+v, got %v", filter, err)
+		}
+	}
+
+	repo.candidates = newArchiveCandidates(MaxProductArchive + 1)
+	if _, err := service.Preview(ctx, filter); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error beyond %d products, got %v", MaxProductArchive, err)
+	}
+}
+
+func TestProductArchiveJob(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockProductArchiveRepository{
+		candidates: newArchiveCandidates(250),
+		changed:    map[string]bool{"prod-120": true},
+		archived:   map[string]bool{},
+	}
+	service := NewProductArchiveService(NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository()), repo)
+	filter := domain.ProductArchiveFilter{Category: "Seasonal", ZeroStock: true}
+
+	// The selection changed since it was previewed
+	if _, err := service.ArchiveJob(ctx, filter, 249); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("Expected a conflict for an unexpected count, got %v", err)
+	}
+
+	run, err := service.ArchiveJob(ctx, filter, 250)
+	if err != nil {
+		t.Fatalf("ArchiveJob() error = %v", err)
+	}
+	var reports []int64
+	result, err := run(ctx, func(done, total int64) {
+		if total != 250 {
+			t.Errorf("Expected a total of 250, got %d", total)
+		}
+		reports = append(reports, done)
+	})
+	if err != nil {
+		t.Fatalf("job error = %v", err)
+	}
+
+	if len(repo.batches) != 3 || len(repo.batches[0]) != productArchiveBatchSize || len(repo.batches[2]) != 50 {
+		t.Errorf("Expected batches of %d, got %d batches", productArchiveBatchSize, len(repo.batches))
+	}
+	if want := []int64{0, 100, 200, 250}; fmt.Sprint(reports) != fmt.Sprint(want) {
+		t.Errorf("Expected progress %v, got %v", want, reports)
+	}
+	summary := result.Summary.(map[string]any)
+	if summary["matched"] != 250 || summary["archived"] != 249 || summary["skipped"] != 1 {
+		t.Errorf("Unexpected summary %v", summary)
+	}
+	if repo.archived["prod-120"] || len(repo.archived) != 249 {
+		t.Errorf("Expected every product but prod-120 to be archived, got %d", len(repo.archived))
+	}
+}
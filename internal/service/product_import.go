@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MaxImportRows is the largest number of products accepted by one import
+const MaxImportRows = 10000
+
+// importBatchSize is the number of products written per database transaction
+const importBatchSize = 500
+
+// ErrInvalidImport is returned for imports that are empty or too large
+var ErrInvalidImport = errors.New("invalid product import")
+
+// ProductImportService onboards product catalogs in bulk
+type ProductImportService struct {
+	inventory  *InventoryService
+	importRepo repository.ProductImportRepository
+}
+
+// NewProductImportService creates a new ProductImportService
+func NewProductImportService(inventory *InventoryService, importRepo repository.ProductImportRepository) *ProductImportService {
+	return &ProductImportService{
+		inventory:  inventory,
+		importRepo: importRepo,
+	}
+}
+
+// Import validates all rows, then writes the valid ones in batched database
+// transactions. Invalid rows do not stop the import; the report lists the
+// outcome of every row. If a batch fails, its rows are retried one by one so
+// a single bad row only fails itself.
+func (s *ProductImportService) Import(ctx context.Context, rows []domain.ProductImportRow) (*domain.ProductImportReport, error) {
+	if err := checkImportSize(rows); err != nil {
+		return nil, err
+	}
+	return s.importRows(ctx, rows, nil)
+}
+
+// ImportJob checks the size of an import and returns it as a job. The job
+// summary holds the counts of the report; the full report is its result.
+func (s *ProductImportService) ImportJob(rows []domain.ProductImportRow) (JobFunc, error) {
+	if err := checkImportSize(rows); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, progress ProgressFunc) (*domain.JobResult, error) {
+		report, err := s.importRows(ctx, rows, progress)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode import report: %w", err)
+		}
+		summary := map[string]int{"total": report.Total, "imported": report.Imported, "failed": report.Failed}
+		return &domain.JobResult{Summary: summary, ContentType: "application/json", Data: data}, nil
+	}, nil
+}
+
+// checkImportSize rejects imports that are empty or too large
+func checkImportSize(rows []domain.ProductImportRow) error {
+	if len(rows) == 0 {
+		return fmt.Errorf("%w: no products to import", ErrInvalidImport)
+	}
+	if len(rows) > MaxImportRows {
+		return fmt.Errorf("%w: at most %d products can be imported at once", ErrInvalidImport, MaxImportRows)
+	}
+	return nil
+}
+
+// importRows runs an import, reporting the rows with an outcome as progress
+func (s *ProductImportService) importRows(ctx context.Context, rows []domain.ProductImportRow, progress ProgressFunc) (*domain.ProductImportReport, error) {
+	total := int64(len(rows))
+	results := make([]domain.ProductImportResult, len(rows))
+	seen := make(map[string]bool, len(rows))
+	var skus []string
+	for i, row := range rows {
+		results[i] = domain.ProductImportResult{Row: i + 1, SKU: row.SKU}
+		if err := row.Validate(); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		if seen[row.SKU] {
+			results[i].Error = "duplicate SKU in import"
+			continue
+		}
+		seen[row.SKU] = true
+		skus = append(skus, row.SKU)
+	}
+
+	existing, err := s.importRepo.ExistingSKUs(ctx, skus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing SKUs: %w", err)
+	}
+
+	warehouses := make(map[string]string)
+	var pending []*domain.ProductImport
+	var pendingRows []int
+	for i, row := range rows {
+		if results[i].Error != "" {
+			continue
+		}
+		if existing[row.SKU] {
+			results[i].Error = "product with this SKU already exists"
+			continue
+		}
+
+		item := &domain.InventoryItem{Quantity: row.Quantity, Location: row.Location}
+		if id, ok := warehouses[row.Location]; ok {
+			item.WarehouseID = id
+		} else if err := s.inventory.ensureWarehouse(ctx, item); err != nil {
+			results[i].Error = err.Error()
+			continue
+		} else {
+			warehouses[row.Location] = item.WarehouseID
+		}
+
+		entry := &domain.ProductImport{Product: row.Product(), Inventory: item}
+		if row.Quantity > 0 {
+			entry.Transaction = &domain.Transaction{
+				Type:      "IN",
+				Quantity:  row.Quantity,
+				Reference: "INITIAL_STOCK",
+				Notes:     "Initial stock entry (import)",
+			}
+		}
+		pending = append(pending, entry)
+		pendingRows = append(pendingRows, i)
+	}
+
+	for start := 0; start < len(pending); start += importBatchSize {
+		progress.report(total-int64(len(pending)-start), total)
+		end := min(start+importBatchSize, len(pending))
+		batch, batchRows := pending[start:end], pendingRows[start:end]
+
+		if err := s.importRepo.ImportBatch(ctx, batch); err == nil {
+			s.imported(ctx, batch, batchRows, results)
+			continue
+		} else if len(batch) == 1 {
+			results[batchRows[0]].Error = err.Error()
+			continue
+		}
+
+		for j, entry := range batch {
+			single := []*domain.ProductImport{entry}
+			if err := s.importRepo.ImportBatch(ctx, single); err != nil {
+				results[batchRows[j]].Error = err.Error()
+				continue
+			}
+			s.imported(ctx, single, batchRows[j:j+1], results)
+		}
+	}
+	progress.report(total, total)
+
+	report := &domain.ProductImportReport{Total: len(rows), Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			report.Failed++
+		} else {
+			report.Imported++
+		}
+	}
+	return report, nil
+}
+
+// imported records the outcome of written rows and publishes their stock
+func (s *ProductImportService) imported(ctx context.Context, batch []*domain.ProductImport, rows []int, results []domain.ProductImportResult) {
+	var transactions []*domain.Transaction
+	for j, entry := range batch {
+		results[rows[j]].ProductID = entry.Product.ID
+		if s.inventory.availability != nil {
+			s.inventory.availability.Set(entry.Inventory)
+		}
+		if entry.Transaction != nil {
+			transactions = append(transactions, entry.Transaction)
+		}
+	}
+	s.inventory.notifyTransactions(ctx, transactions...)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockProductImportRepository implements ProductImportRepository interface for
+// testing; batches containing the failSKU are rejected as a whole
+type MockProductImportRepository struct {
+	existing map[string]bool
+	failSKU  string
+	batches  int
+	imported []*domain.ProductImport
+}
+
+func (m *MockProductImportRepository) ExistingSKUs(ctx context.Context, skus []string) (map[string]bool, error) {
+	return m.existing, nil
+}
+
+func (m *MockProductImportRepository) ImportBatch(ctx context.Context, batch []*domain.ProductImport) error {
+	m.batches++
+	for _, entry := range batch {
+		if entry.Product.SKU == m.failSKU {
+			return fmt.Errorf("failed to create product %s: constraint violation", entry.Product.SKU)
+		}
+	}
+	for _, entry := range batch {
+		entry.Product.ID = "prod-" + entry.Product.SKU
+		entry.Inventory.ID = "inv-" + entry.Product.SKU
+		m.imported = append(m.imported, entry)
+	}
+	return nil
+}
+
+func TestImportProducts(t *testing.T) {
+	repo := &MockProductImportRepository{existing: map[string]bool{"OLD-1": true}, failSKU: "BAD-1"}
+	handler := &recordingTransactionHandler{}
+	inventory := NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository(),
+		WithTransactionHandlers(handler))
+	service := NewProductImportService(inventory, repo)
+
+	rows := []domain.ProductImportRow{
+		{Name: "Laptop", SKU: "LAP-1", Price: 1500, Location: "WH-A", Quantity: 10},
+		{Name: "", SKU: "NONAME-1", Location: "WH-A"},
+		{Name: "Mouse", SKU: "LAP-1", Location: "WH-A"},
+		{Name: "Keyboard", SKU: "OLD-1", Location: "WH-A"},
+		{Name: "Monitor", SKU: "BAD-1", Location: "WH-B", Quantity: 3},
+		{Name: "Cable", SKU: "CAB-1", Location: "WH-B"},
+		{Name: "Dock", SKU: "DOCK-1", Location: "WH-B", Quantity: -1},
+	}
+
+	report, err := service.Import(context.Background(), rows)
+	if err != nil {
+		t.Fatalf("Failed to import products: %v", err)
+	}
+
+	if report.Total != 7 || report.Imported != 2 || report.Failed != 5 {
+		t.Errorf("Expected 2 of 7 imported, got %+v", report)
+	}
+	for i, wantOK := range []bool{true, false, false, false, false, true, false} {
+		result := report.Results[i]
+		if result.Row != i+1 {
+			t.Errorf("Expected row %d, got %d", i+1, result.Row)
+		}
+		if (result.Error == "") != wantOK {
+			t.Errorf("Row %d: expected success=%v, got error %q", i+1, wantOK, result.Error)
+		}
+		if wantOK && result.ProductID != "prod-"+result.SKU {
+			t.Errorf("Row %d: expected product ID to be reported, got %q", i+1, result.ProductID)
+		}
+	}
+
+	// The failing batch is retried row by row: 1 batch + 3 single rows
+	if repo.batches != 4 {
+		t.Errorf("Expected 4 batch writes, got %d", repo.batches)
+	}
+	if len(handler.types) != 1 || handler.types[0] != "IN" {
+		t.Errorf("Expected one initial stock transaction to be published, got %v", handler.types)
+	}
+
+	if _, err := service.Import(context.Background(), nil); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("Expected ErrInvalidImport for empty import, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrQuotaExceeded is returned when a reservation would take a reference over
+// its reservation quota for a product
+var ErrQuotaExceeded = errors.New("reservation quota exceeded")
+
+// ReservationQuota limits how many units of a product a single reference
+// (order or customer) may hold reserved at once. A limit of zero means
+// unlimited.
+type ReservationQuota struct {
+	Default  int64
+	Products map[string]int64
+}
+
+// Limit returns the quota that applies to a product
+func (q ReservationQuota) Limit(productID string) int64 {
+	if limit, ok := q.Products[productID]; ok {
+		return limit
+	}
+	return q.Default
+}
+
+// WithReservationQuota enforces per-reference reservation limits in ReserveStock
+func WithReservationQuota(quota ReservationQuota) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.quota = &quota
+	}
+}
+
+// checkReservationQuota rejects a reservation that would take the reference
+// over its quota. The check reads committed reservations, so concurrent
+// requests under the same reference can overshoot it by at most one request
+// each; it is meant to stop bulk hoarding, not to be exact.
+func (s *InventoryService) checkReservationQuota(ctx context.Context, productID, reference string, quantity int64) error {
+	if s.quota == nil {
+		return nil
+	}
+
+	limit := s.quota.Limit(productID)
+	if limit <= 0 {
+		return nil
+	}
+
+	if reference == "" {
+		return fmt.Errorf("%w: a reference is required to reserve this product", ErrQuotaExceeded)
+	}
+
+	held, err := s.transactionRepo.ReservedByReference(ctx, productID, reference)
+	if err != nil {
+		return fmt.Errorf("failed to check reservation quota: %w", err)
+	}
+
+	if held+quantity > limit {
+		return fmt.Errorf("%w: %s already holds %d of %d units", ErrQuotaExceeded, reference, held, limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestReservationQuota(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+
+	service := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithReservationQuota(ReservationQuota{
+			Default:  5,
+			Products: map[string]int64{"prod-unlimited": 0},
+		}),
+	)
+	ctx := context.Background()
+
+	for _, id := range []string{"prod-1", "prod-unlimited"} {
+		productRepo.Create(ctx, &domain.Product{ID: id, Name: "Console", SKU: id, Price: 499})
+		inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-" + id, ProductID: id, Quantity: 100, Location: "WH-A"})
+	}
+
+	if err := service.ReserveStock(ctx, "prod-1", 3, "customer-1"); err != nil {
+		t.Fatalf("Failed to reserve within quota: %v", err)
+	}
+
+	err := service.ReserveStock(ctx, "prod-1", 3, "customer-1")
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+
+	if err := service.ReserveStock(ctx, "prod-1", 2, "customer-1"); err != nil {
+		t.Errorf("Expected reservation up to the quota to succeed: %v", err)
+	}
+
+	if err := service.UnreserveStock(ctx, "prod-1", 4, "customer-1"); err != nil {
+		t.Fatalf("Failed to unreserve: %v", err)
+	}
+	if err := service.ReserveStock(ctx, "prod-1", 4, "customer-1"); err != nil {
+		t.Errorf("Expected released units to count against the quota again: %v", err)
+	}
+
+	if err := service.ReserveStock(ctx, "prod-1", 5, "customer-2"); err != nil {
+		t.Errorf("Expected quotas to be tracked per reference: %v", err)
+	}
+
+	if err := service.ReserveStock(ctx, "prod-1", 1, ""); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected reservation without reference to be rejected, got %v", err)
+	}
+
+	if err := service.ReserveStock(ctx, "prod-unlimited", 50, "customer-1"); err != nil {
+		t.Errorf("Expected product override of 0 to disable the quota: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// readCountingInventoryRepository counts the inventory reads reaching the mock
+type readCountingInventoryRepository struct {
+	*MockInventoryRepository
+	reads int
+}
+
+func (r *readCountingInventoryRepository) ListByProductID(ctx context.Context, productID string) ([]*domain.InventoryItem, error) {
+	r.reads++
+	return r.MockInventoryRepository.ListByProductID(ctx, productID)
+}
+
+func TestReadCache(t *testing.T) {
+	inventoryRepo := &readCountingInventoryRepository{MockInventoryRepository: NewMockInventoryRepository()}
+	config := repository.CacheConfig{Size: 1, TTL: time.Minute}
+	service := NewInventoryService(
+		repository.NewCachedProductRepository(NewMockProductRepository(), config),
+		repository.NewCachedInventoryRepository(inventoryRepo, config),
+		NewMockTransactionRepository(),
+	)
+	ctx := context.Background()
+
+	for _, id := range []string{"prod-1", "prod-2"} {
+		if err := service.CreateProduct(ctx, &domain.Product{ID: id, Name: "Product", SKU: "SKU-" + id, Price: 10}, "WH-A", 10); err != nil {
+			t.Fatalf("CreateProduct() error = %v", err)
+		}
+	}
+
+	if _, err := service.GetStockLevel(ctx, "prod-1", ""); err != nil {
+		t.Fatalf("GetStockLevel() error = %v", err)
+	}
+	_, item, err := service.GetProduct(ctx, "prod-1")
+	if err != nil {
+		t.Fatalf("GetProduct() error = %v", err)
+	}
+	item.Quantity = 999
+	level, _ := service.GetStockLevel(ctx, "prod-1", "")
+	if inventoryRepo.reads != 1 || level.Quantity != 10 {
+		t.Fatalf("Expected one read serving an unchanged copy, got %d reads and quantity %d", inventoryRepo.reads, level.Quantity)
+	}
+
+	// A movement addressed by inventory item invalidates its product
+	if err := service.AddStock(ctx, "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	level, _ = service.GetStockLevel(ctx, "prod-1", "")
+	if inventoryRepo.reads != 2 || level.Quantity != 15 {
+		t.Errorf("Expected the movement to be read back, got %d reads and quantity %d", inventoryRepo.reads, level.Quantity)
+	}
+
+	// Reading another product evicts prod-1 from the single-entry cache
+	service.GetStockLevel(ctx, "prod-2", "")
+	service.GetStockLevel(ctx, "prod-1", "")
+	if inventoryRepo.reads != 4 {
+		t.Errorf("Expected the least recently used product to be evicted, got %d reads", inventoryRepo.reads)
+	}
+
+	// Product writes invalidate product reads
+	product, _, _ := service.GetProduct(ctx, "prod-1")
+	product.Name = "Renamed"
+	if err := service.UpdateProduct(ctx, product); err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if err := service.DeleteProduct(ctx, "prod-1"); err != nil {
+		t.Fatalf("DeleteProduct() error = %v", err)
+	}
+	if product, _, _ := service.GetProduct(ctx, "prod-1"); product.Name != "Renamed" || !product.Archived() {
+		t.Errorf("Expected the renamed, archived product, got %+v", product)
+	}
+}
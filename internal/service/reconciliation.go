@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// reconciliationDiscrepancyLimit bounds the discrepancies recorded per run
+const reconciliationDiscrepancyLimit = 1000
+
+// reconciliationSettleDelay is how long discrepancies are given to settle
+// before they are checked again: stock movements committing while the log is
+// replayed can show up as discrepancies
+const reconciliationSettleDelay = time.Second
+
+// ReconciliationService reconciles the stored stock with the transaction
+// log: it replays the log of every inventory row and records the rows whose
+// quantity or reserved units differ from it.
+type ReconciliationService struct {
+	repo   repository.ReconciliationRepository
+	settle time.Duration
+}
+
+// NewReconciliationService creates a new ReconciliationService
+func NewReconciliationService(repo repository.ReconciliationRepository) *ReconciliationService {
+	return &ReconciliationService{
+		repo:   repo,
+		settle: reconciliationSettleDelay,
+	}
+}
+
+// Reconcile compares the stock of every inventory row with its transaction
+// log and records the report. Rows found to differ are checked again after a
+// moment, and only those that still differ by as much are reported, so
+// movements in flight are not taken for discrepancies.
+func (s *ReconciliationService) Reconcile(ctx context.Context) (*domain.ReconciliationReport, error) {
+	report := &domain.ReconciliationReport{StartedAt: time.Now()}
+
+	found, total, err := s.repo.FindDiscrepancies(ctx, nil, reconciliationDiscrepancyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Discrepancies = []*domain.ReconciliationDiscrepancy{}
+	if len(found) > 0 {
+		sleepContext(ctx, s.settle)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, len(found))
+		for i, d := range found {
+			ids[i] = d.InventoryID
+		}
+		rechecked, _, err := s.repo.FindDiscrepancies(ctx, ids, len(ids))
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[string]*domain.ReconciliationDiscrepancy, len(rechecked))
+		for _, d := range rechecked {
+			byID[d.InventoryID] = d
+		}
+		for _, d := range found {
+			if again := byID[d.InventoryID]; again != nil && again.Matches(d) {
+				report.Discrepancies = append(report.Discrepancies, again)
+			}
+		}
+		// Rows beyond the limit are not checked again
+		report.DiscrepancyCount = total - len(found) + len(report.Discrepancies)
+	}
+	report.CompletedAt = time.Now()
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+	if report.DiscrepancyCount > 0 {
+		slog.WarnContext(ctx, "stock differs from the transaction log", "discrepancies", report.DiscrepancyCount,
+			"report_id", report.ID)
+	}
+	return report, nil
+}
+
+// LatestReport retrieves the report of the latest run
+func (s *ReconciliationService) LatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	return s.repo.LatestReport(ctx)
+}
+
+// Run reconciles the stock every interval until ctx is cancelled
+func (s *ReconciliationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if _, err := s.Reconcile(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "stock reconciliation failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockReconciliationRepository implements ReconciliationRepository
+// interface. Each call to FindDiscrepancies answers from the next scan.
+type MockReconciliationRepository struct {
+	scans   [][]*domain.ReconciliationDiscrepancy
+	calls   int
+	reports []*domain.ReconciliationReport
+}
+
+func (m *MockReconciliationRepository) FindDiscrepancies(ctx context.Context, inventoryIDs []string, limit int) ([]*domain.ReconciliationDiscrepancy, int, error) {
+	scan := m.scans[min(m.calls, len(m.scans)-1)]
+	m.calls++
+
+	var found []*domain.ReconciliationDiscrepancy
+	for _, d := range scan {
+		if inventoryIDs == nil || slices.Contains(inventoryIDs, d.InventoryID) {
+			found = append(found, d)
+		}
+	}
+	return found[:min(limit, len(found))], len(found), nil
+}
+
+func (m *MockReconciliationRepository) CreateReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	report.ID = "report-1"
+	m.reports = append(m.reports, report)
+	return nil
+}
+
+func (m *MockReconciliationRepository) LatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	if len(m.reports) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return m.reports[len(m.reports)-1], nil
+}
+
+func TestReconcile(t *testing.T) {
+	drifted := &domain.ReconciliationDiscrepancy{InventoryID: "inv-1", Quantity: 10, ExpectedQuantity: 12}
+	inFlight := &domain.ReconciliationDiscrepancy{InventoryID: "inv-2", Quantity: 5, ExpectedQuantity: 3}
+	moved := &domain.ReconciliationDiscrepancy{InventoryID: "inv-3", Reserved: 2, ExpectedReserved: 0}
+
+	repo := &MockReconciliationRepository{scans: [][]*domain.ReconciliationDiscrepancy{
+		{drifted, inFlight, moved},
+		// The movement of inv-2 committed; inv-1 moved, still 2 short of
+		// its log; inv-3 now differs by more
+		{
+			{InventoryID: "inv-1", Quantity: 20, ExpectedQuantity: 22},
+			{InventoryID: "inv-3", Reserved: 5, ExpectedReserved: 0},
+		},
+	}}
+	service := NewReconciliationService(repo)
+	service.settle = 0
+
+	report, err := service.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if report.DiscrepancyCount != 1 || len(report.Discrepancies) != 1 {
+		t.Fatalf("Expected one discrepancy, got %d: %+v", report.DiscrepancyCount, report.Discrepancies)
+	}
+	if d := report.Discrepancies[0]; d.InventoryID != "inv-1" || d.Quantity != 20 || d.ExpectedQuantity != 22 {
+		t.Errorf("Expected inv-1 as rechecked, got %+v", d)
+	}
+	if latest, _ := service.LatestReport(context.Background()); latest != report {
+		t.Error("Expected the report to be recorded")
+	}
+
+	// A clean run is recorded too, and skips the recheck
+	repo.scans, repo.calls = [][]*domain.ReconciliationDiscrepancy{nil}, 0
+	report, err = service.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if report.DiscrepancyCount != 0 || report.Discrepancies == nil || repo.calls != 1 || len(repo.reports) != 2 {
+		t.Errorf("Unexpected clean run %+v after %d scans", report, repo.calls)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+	"github.com/google/uuid"
+)
+
+// RedactionService handles erasure of personal data from the ledger
+type RedactionService struct {
+	redactionRepo repository.RedactionRepository
+}
+
+// NewRedactionService creates a new RedactionService
+func NewRedactionService(redactionRepo repository.RedactionRepository) *RedactionService {
+	return &RedactionService{
+		redactionRepo: redactionRepo,
+	}
+}
+
+// RedactCustomer replaces a customer identifier embedded in transaction
+// references and notes with an opaque token. The token is random, so the
+// identifier cannot be recovered from it, but all rows of one erasure share it.
+func (s *RedactionService) RedactCustomer(ctx context.Context, identifier, reason string) (*domain.Redaction, error) {
+	identifier = strings.TrimSpace(identifier)
+	if len(identifier) < domain.MinRedactionIdentifierLength {
+		return nil, domain.NewValidationError("customer identifier must be at least %d characters", domain.MinRedactionIdentifierLength)
+	}
+
+	redaction := &domain.Redaction{
+		Token:  "REDACTED-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:16],
+		Reason: reason,
+	}
+
+	if err := s.redactionRepo.Redact(ctx, identifier, redaction); err != nil {
+		return nil, fmt.Errorf("failed to redact customer data: %w", err)
+	}
+
+	return redaction, nil
+}
+
+// ListRedactions lists past redactions with pagination
+func (s *RedactionService) ListRedactions(ctx context.Context, limit, offset int) ([]*domain.Redaction, error) {
+	redactions, err := s.redactionRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redactions: %w", err)
+	}
+	return redactions, nil
+}
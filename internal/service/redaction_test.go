@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockRedactionRepository applies redactions to an in-memory transaction set
+type MockRedactionRepository struct {
+	transactions *MockTransactionRepository
+	redactions   []*domain.Redaction
+}
+
+func (m *MockRedactionRepository) Redact(ctx context.Context, identifier string, redaction *domain.Redaction) error {
+	for _, t := range m.transactions.transactions {
+		if strings.Contains(t.Reference, identifier) || strings.Contains(t.Notes, identifier) {
+			t.Reference = strings.ReplaceAll(t.Reference, identifier, redaction.Token)
+			t.Notes = strings.ReplaceAll(t.Notes, identifier, redaction.Token)
+			redaction.TransactionsAffected++
+		}
+	}
+	m.redactions = append(m.redactions, redaction)
+	return nil
+}
+
+func (m *MockRedactionRepository) List(ctx context.Context, limit, offset int) ([]*domain.Redaction, error) {
+	return m.redactions, nil
+}
+
+func TestRedactCustomer(t *testing.T) {
+	transactionRepo := NewMockTransactionRepository()
+	transactionRepo.transactions["tx-1"] = &domain.Transaction{
+		ID: "tx-1", Type: "OUT", Quantity: 2, Reference: "ORDER-1 jane@example.com", Notes: "ship to jane@example.com",
+	}
+	transactionRepo.transactions["tx-2"] = &domain.Transaction{
+		ID: "tx-2", Type: "IN", Quantity: 5, Reference: "PO-001",
+	}
+
+	service := NewRedactionService(&MockRedactionRepository{transactions: transactionRepo})
+
+	redaction, err := service.RedactCustomer(context.Background(), "jane@example.com", "GDPR erasure request")
+	if err != nil {
+		t.Fatalf("Failed to redact customer: %v", err)
+	}
+
+	if redaction.TransactionsAffected != 1 {
+		t.Errorf("Expected 1 transaction affected, got %d", redaction.TransactionsAffected)
+	}
+
+	redacted := transactionRepo.transactions["tx-1"]
+	if strings.Contains(redacted.Reference+redacted.Notes, "jane@example.com") {
+		t.Errorf("Identifier still present after redaction: %q / %q", redacted.Reference, redacted.Notes)
+	}
+	if redacted.Reference != "ORDER-1 "+redaction.Token {
+		t.Errorf("Unexpected redacted reference %q", redacted.Reference)
+	}
+	if redacted.Quantity != 2 {
+		t.Errorf("Expected quantity to be preserved, got %d", redacted.Quantity)
+	}
+	if transactionRepo.transactions["tx-2"].Reference != "PO-001" {
+		t.Error("Unrelated transaction should not be redacted")
+	}
+}
+
+func TestRedactCustomerWithShortIdentifier(t *testing.T) {
+	service := NewRedactionService(&MockRedactionRepository{transactions: NewMockTransactionRepository()})
+
+	if _, err := service.RedactCustomer(context.Background(), "ab", "GDPR erasure request"); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for too short identifier, got %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// maxReplenishmentDays bounds the lead time and coverage of reorder suggestions
+const maxReplenishmentDays = 365
+
+// ErrPurchaseOrderNotDraft is returned when receiving or cancelling a
+// purchase order that was already received or cancelled
+var ErrPurchaseOrderNotDraft = errors.New("purchase order is not a draft")
+
+// ReplenishmentPolicy sets how far ahead reorder suggestions plan. Stock
+// ordered now arrives after LeadTimeDays operating days of the receiving
+// location and should then last CoverDays calendar days.
+type ReplenishmentPolicy struct {
+	LeadTimeDays int
+	CoverDays    int
+}
+
+// Validate checks if the replenishment policy is valid
+func (p ReplenishmentPolicy) Validate() error {
+	if p.LeadTimeDays < 0 || p.LeadTimeDays > maxReplenishmentDays {
+		return domain.NewValidationError("lead_time_days must be between 0 and %d", maxReplenishmentDays)
+	}
+	if p.CoverDays < 0 || p.CoverDays > maxReplenishmentDays {
+		return domain.NewValidationError("cover_days must be between 0 and %d", maxReplenishmentDays)
+	}
+	return nil
+}
+
+// ReplenishmentService suggests reorder quantities and manages the purchase
+// orders that replenish stock
+type ReplenishmentService struct {
+	inventory *InventoryService
+	orderRepo repository.PurchaseOrderRepository
+	defaults  ReplenishmentPolicy
+}
+
+// NewReplenishmentService creates a new ReplenishmentService. Suggestions
+// requested without a policy of their own use defaults.
+func NewReplenishmentService(inventory *InventoryService, orderRepo repository.PurchaseOrderRepository, defaults ReplenishmentPolicy) *ReplenishmentService {
+	return &ReplenishmentService{
+		inventory: inventory,
+		orderRepo: orderRepo,
+		defaults:  defaults,
+	}
+}
+
+// Defaults returns the policy suggestions use unless told otherwise
+func (s *ReplenishmentService) Defaults() ReplenishmentPolicy {
+	return s.defaults
+}
+
+// Suggestions lists the inventory rows to reorder. A row's reorder point is
+// its demand over the lead time, at the daily sales velocity of the last 30
+// days, plus its safety stock, but never below its reorder level. The lead
+// time skips the days the row's warehouse does not operate. Rows whose
+// available stock plus stock on draft purchase orders is at or below the
+// reorder point get a suggestion that brings them to the reorder point plus
+// the demand over the cover days.
+func (s *ReplenishmentService) Suggestions(ctx context.Context, policy ReplenishmentPolicy) ([]*domain.ReorderSuggestion, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	candidates, err := s.orderRepo.ReorderCandidates(ctx, now.Add(-stockoutVelocityWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute reorder suggestions: %w", err)
+	}
+
+	windowDays := stockoutVelocityWindow.Hours() / 24
+	schedules := s.inventory.newShipSchedules()
+	suggestions := make([]*domain.ReorderSuggestion, 0)
+	for _, c := range candidates {
+		schedule, err := schedules.get(ctx, c.Location)
+		if err != nil {
+			return nil, err
+		}
+		leadTime := schedule.LeadTime(now, policy.LeadTimeDays)
+
+		velocity := float64(c.Outbound) / windowDays
+		reorderPoint := max(c.ReorderLevel, demand(velocity, leadTime)+c.SafetyStock)
+		position := c.Available + c.OnOrder
+		if position > reorderPoint {
+			continue
+		}
+
+		quantity := reorderPoint + demand(velocity, policy.CoverDays) - position
+		if quantity <= 0 {
+			continue
+		}
+
+		suggestions = append(suggestions, &domain.ReorderSuggestion{
+			ProductID:     c.ProductID,
+			SKU:           c.SKU,
+			Name:          c.Name,
+			Location:      c.Location,
+			Available:     c.Available,
+			OnOrder:       c.OnOrder,
+			DailyVelocity: velocity,
+			LeadTimeDays:  leadTime,
+			ReorderPoint:  reorderPoint,
+			Quantity:      quantity,
+		})
+	}
+
+	return suggestions, nil
+}
+
+// demand returns the units sold over days at a daily velocity, rounded up
+func demand(velocity float64, days int) int64 {
+	return int64(math.Ceil(velocity * float64(days)))
+}
+
+// CreatePurchaseOrder creates a draft purchase order. Lines without a
+// location are ordered for the product's default location.
+func (s *ReplenishmentService) CreatePurchaseOrder(ctx context.Context, supplier, notes string, lines []domain.PurchaseOrderLine) (*domain.PurchaseOrder, error) {
+	order := &domain.PurchaseOrder{
+		Supplier: supplier,
+		Notes:    notes,
+		Status:   domain.PurchaseOrderStatusDraft,
+		Lines:    lines,
+	}
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		item, err := s.inventory.resolveInventory(ctx, line.ProductID, line.Location)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to get inventory: %w", i+1, err)
+		}
+		if item == nil {
+			return nil, fmt.Errorf("line %d: inventory %w", i+1, domain.ErrNotFound)
+		}
+		line.InventoryID = item.ID
+		line.Location = item.Location
+	}
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	return order, nil
+}
+
+// DraftFromSuggestions creates a draft purchase order of the current reorder
+// suggestions. It fails with a validation error when nothing needs reordering.
+func (s *ReplenishmentService) DraftFromSuggestions(ctx context.Context, supplier, notes string, policy ReplenishmentPolicy) (*domain.PurchaseOrder, error) {
+	suggestions, err := s.Suggestions(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) == 0 {
+		return nil, domain.NewValidationError("nothing needs reordering")
+	}
+
+	lines := make([]domain.PurchaseOrderLine, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		lines = append(lines, domain.PurchaseOrderLine{
+			ProductID: suggestion.ProductID,
+			Location:  suggestion.Location,
+			Quantity:  suggestion.Quantity,
+		})
+	}
+	return s.CreatePurchaseOrder(ctx, supplier, notes, lines)
+}
+
+// GetPurchaseOrder retrieves a purchase order
+func (s *ReplenishmentService) GetPurchaseOrder(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase order: %w", err)
+	}
+	return order, nil
+}
+
+// ShippingNotices lists the advance shipping notices the supplier submitted
+// for a purchase order, oldest first
+func (s *ReplenishmentService) ShippingNotices(ctx context.Context, id string) ([]*domain.AdvanceShippingNotice, error) {
+	if _, err := s.GetPurchaseOrder(ctx, id); err != nil {
+		return nil, err
+	}
+
+	notices, err := s.orderRepo.ListShippingNotices(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipping notices: %w", err)
+	}
+	if notices == nil {
+		notices = []*domain.AdvanceShippingNotice{}
+	}
+	return notices, nil
+}
+
+// ListPurchaseOrders lists purchase orders, newest first, optionally only
+// those in the given status
+func (s *ReplenishmentService) ListPurchaseOrders(ctx context.Context, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	switch status {
+	case "", domain.PurchaseOrderStatusDraft, domain.PurchaseOrderStatusReceived, domain.PurchaseOrderStatusCancelled:
+	default:
+		return nil, domain.NewValidationError("unknown purchase order status %q", status)
+	}
+
+	orders, err := s.orderRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+	if orders == nil {
+		orders = []*domain.PurchaseOrder{}
+	}
+	return orders, nil
+}
+
+// ReceivePurchaseOrder books the delivery of a draft purchase order: every
+// line is added to stock at its location with the order ID as reference, all
+// or nothing. If the stock cannot be added the order stays a draft.
+func (s *ReplenishmentService) ReceivePurchaseOrder(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	order, err := s.claim(ctx, id, domain.PurchaseOrderStatusReceived)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.StockBatchItem, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		items = append(items, domain.StockBatchItem{
+			ProductID: line.ProductID,
+			Location:  line.Location,
+			Operation: domain.StockAdd,
+			Quantity:  line.Quantity,
+			Reference: order.ID,
+		})
+	}
+
+	if _, err := s.inventory.ApplyStockBatch(ctx, items); err != nil {
+		if _, restoreErr := s.orderRepo.UpdateStatus(ctx, id, domain.PurchaseOrderStatusReceived, domain.PurchaseOrderStatusDraft); restoreErr != nil {
+			slog.ErrorContext(ctx, "failed to restore purchase order", "order_id", id, "error", restoreErr)
+		}
+		return nil, fmt.Errorf("failed to receive purchase order: %w", err)
+	}
+
+	return s.GetPurchaseOrder(ctx, id)
+}
+
+// CancelPurchaseOrder cancels a draft purchase order
+func (s *ReplenishmentService) CancelPurchaseOrder(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	if _, err := s.claim(ctx, id, domain.PurchaseOrderStatusCancelled); err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(ctx, id)
+}
+
+// claim moves a draft purchase order to its final status
+func (s *ReplenishmentService) claim(ctx context.Context, id string, status domain.PurchaseOrderStatus) (*domain.PurchaseOrder, error) {
+	order, err := s.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.PurchaseOrderStatusDraft {
+		return nil, fmt.Errorf("%w: purchase order is %s", ErrPurchaseOrderNotDraft, order.Status)
+	}
+
+	claimed, err := s.orderRepo.UpdateStatus(ctx, id, domain.PurchaseOrderStatusDraft, status)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrPurchaseOrderNotDraft
+	}
+	return order, nil
+}
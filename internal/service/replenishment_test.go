@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockPurchaseOrderRepository implements PurchaseOrderRepository interface
+// for testing; draft orders count as on order for its reorder candidates
+type MockPurchaseOrderRepository struct {
+	orders     map[string]*domain.PurchaseOrder
+	notices    []*domain.AdvanceShippingNotice
+	candidates []domain.ReorderCandidate
+}
+
+func NewMockPurchaseOrderRepository(candidates ...domain.ReorderCandidate) *MockPurchaseOrderRepository {
+	return &MockPurchaseOrderRepository{orders: make(map[string]*domain.PurchaseOrder), candidates: candidates}
+}
+
+func (m *MockPurchaseOrderRepository) Create(ctx context.Context, order *domain.PurchaseOrder) error {
+	order.ID = fmt.Sprintf("po-%d", len(m.orders)+1)
+	for i := range order.Lines {
+		order.Lines[i].Line = i + 1
+	}
+	stored := *order
+	stored.Lines = slices.Clone(order.Lines)
+	m.orders[order.ID] = &stored
+	return nil
+}
+
+func (m *MockPurchaseOrderRepository) GetByID(ctx context.Context, id string) (*domain.PurchaseOrder, error) {
+	if order, ok := m.orders[id]; ok {
+		copied := *order
+		copied.Lines = slices.Clone(order.Lines)
+		for _, notice := range m.notices {
+			for _, line := range notice.Lines {
+				if notice.PurchaseOrderID == id {
+					copied.Lines[line.Line-1].ShippedQuantity += line.Quantity
+				}
+			}
+		}
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("purchase order %w", domain.ErrNotFound)
+}
+
+func (m *MockPurchaseOrderRepository) ListForSupplier(ctx context.Context, supplier string, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	orders, _ := m.List(ctx, status, limit, offset)
+	var result []*domain.PurchaseOrder
+	for _, order := range orders {
+		if order.Supplier == supplier {
+			result = append(result, order)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPurchaseOrderRepository) Lock(ctx context.Context, id string) error {
+	if _, ok := m.orders[id]; !ok {
+		return fmt.Errorf("purchase order %w", domain.ErrNotFound)
+	}
+	return nil
+}
+
+func (m *MockPurchaseOrderRepository) Confirm(ctx context.Context, id string, promisedAt time.Time, quantities map[int]int64) (bool, error) {
+	order, ok := m.orders[id]
+	if !ok || order.Status != domain.PurchaseOrderStatusDraft {
+		return false, nil
+	}
+	now := time.Now()
+	order.ConfirmedAt, order.PromisedAt = &now, &promisedAt
+	for line, quantity := range quantities {
+		order.Lines[line-1].ConfirmedQuantity = &quantity
+	}
+	return true, nil
+}
+
+func (m *MockPurchaseOrderRepository) CreateShippingNotice(ctx context.Context, notice *domain.AdvanceShippingNotice) error {
+	for _, existing := range m.notices {
+		if existing.PurchaseOrderID == notice.PurchaseOrderID && existing.Reference == notice.Reference {
+			return domain.ErrDuplicateShippingNotice
+		}
+	}
+	notice.ID = fmt.Sprintf("asn-%d", len(m.notices)+1)
+	notice.CreatedAt = time.Now()
+	m.notices = append(m.notices, notice)
+	return nil
+}
+
+func (m *MockPurchaseOrderRepository) ListShippingNotices(ctx context.Context, orderID string) ([]*domain.AdvanceShippingNotice, error) {
+	var notices []*domain.AdvanceShippingNotice
+	for _, notice := range m.notices {
+		if notice.PurchaseOrderID == orderID {
+			notices = append(notices, notice)
+		}
+	}
+	return notices, nil
+}
+
+func (m *MockPurchaseOrderRepository) List(ctx context.Context, status domain.PurchaseOrderStatus, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	var orders []*domain.PurchaseOrder
+	for _, order := range m.orders {
+		if status == "" || order.Status == status {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (m *MockPurchaseOrderRepository) UpdateStatus(ctx context.Context, id string, from, to domain.PurchaseOrderStatus) (bool, error) {
+	order, ok := m.orders[id]
+	if !ok || order.Status != from {
+		return false, nil
+	}
+	order.Status = to
+	return true, nil
+}
+
+func (m *MockPurchaseOrderRepository) ReorderCandidates(ctx context.Context, since time.Time) ([]*domain.ReorderCandidate, error) {
+	candidates := make([]*domain.ReorderCandidate, 0, len(m.candidates))
+	for _, c := range m.candidates {
+		for _, order := range m.orders {
+			for _, line := range order.Lines {
+				if order.Status == domain.PurchaseOrderStatusDraft && line.InventoryID == c.InventoryID {
+					c.OnOrder += line.Quantity
+				}
+			}
+		}
+		candidates = append(candidates, &c)
+	}
+	return candidates, nil
+}
+
+func TestReorderSuggestions(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	for i, available := range []int64{10, 50, 3} {
+		productID := fmt.Sprintf("prod-%d", i+1)
+		productRepo.Create(ctx, &domain.Product{ID: productID, Name: "Product", SKU: fmt.Sprintf("SKU%d", i+1), Price: 10})
+		inventoryRepo.Create(ctx, &domain.InventoryItem{ID: fmt.Sprintf("inv-%d", i+1), ProductID: productID, Quantity: available, Location: "WH-A"})
+	}
+	orderRepo := NewMockPurchaseOrderRepository(
+		// Sells 2 a day: 7 days of lead time plus 5 safety stock
+		domain.ReorderCandidate{ProductID: "prod-1", InventoryID: "inv-1", Location: "WH-A", Available: 10, SafetyStock: 5, Outbound: 60},
+		// Above its reorder level
+		domain.ReorderCandidate{ProductID: "prod-2", InventoryID: "inv-2", Location: "WH-A", Available: 50, ReorderLevel: 20},
+		// No sales, but below its reorder level
+		domain.ReorderCandidate{ProductID: "prod-3", InventoryID: "inv-3", Location: "WH-A", Available: 3, ReorderLevel: 10},
+	)
+	service := NewReplenishmentService(NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository()), orderRepo, ReplenishmentPolicy{})
+	policy := ReplenishmentPolicy{LeadTimeDays: 7, CoverDays: 30}
+
+	suggestions, err := service.Suggestions(ctx, policy)
+	if err != nil {
+		t.Fatalf("Suggestions() error = %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %d", len(suggestions))
+	}
+	if s := suggestions[0]; s.ProductID != "prod-1" || s.ReorderPoint != 19 || s.Quantity != 69 {
+		t.Errorf("Expected prod-1 to reorder 69 at reorder point 19, got %+v", s)
+	}
+	if s := suggestions[1]; s.ProductID != "prod-3" || s.ReorderPoint != 10 || s.Quantity != 7 {
+		t.Errorf("Expected prod-3 to be topped up to its reorder level, got %+v", s)
+	}
+
+	// Drafted stock counts as on order, so nothing is suggested twice
+	order, err := service.DraftFromSuggestions(ctx, "ACME", "", policy)
+	if err != nil {
+		t.Fatalf("DraftFromSuggestions() error = %v", err)
+	}
+	if order.Status != domain.PurchaseOrderStatusDraft || len(order.Lines) != 2 || order.Lines[0].Quantity != 69 {
+		t.Errorf("Unexpected draft: %+v", order)
+	}
+	if suggestions, _ := service.Suggestions(ctx, policy); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions with the draft on order, got %+v", suggestions)
+	}
+	if _, err := service.DraftFromSuggestions(ctx, "ACME", "", policy); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error with nothing to reorder, got %v", err)
+	}
+
+	if _, err := service.Suggestions(ctx, ReplenishmentPolicy{LeadTimeDays: -1}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a negative lead time, got %v", err)
+	}
+}
+
+func TestReorderSuggestionsCalendar(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	warehouseRepo := NewMockWarehouseRepository()
+	ctx := context.Background()
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	// WH-A is closed for the coming week, so stock ordered now takes 7
+	// operating days and 14 calendar days to arrive
+	var holidays []string
+	today := time.Now().UTC()
+	for i := range 7 {
+		holidays = append(holidays, today.AddDate(0, 0, i).Format(time.DateOnly))
+	}
+	warehouseRepo.Create(ctx, &domain.Warehouse{Code: "WH-A", Name: "A", Holidays: holidays})
+
+	orderRepo := NewMockPurchaseOrderRepository(
+		domain.ReorderCandidate{ProductID: "prod-1", InventoryID: "inv-1", Location: "WH-A", Available: 10, SafetyStock: 5, Outbound: 60},
+		domain.ReorderCandidate{ProductID: "prod-2", InventoryID: "inv-2", Location: "WH-B", Available: 10, SafetyStock: 5, Outbound: 60},
+	)
+	inventory := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository(),
+		WithWarehouseRepository(warehouseRepo), WithShipDates(domain.ShipSchedule{}))
+	service := NewReplenishmentService(inventory, orderRepo, ReplenishmentPolicy{})
+
+	suggestions, err := service.Suggestions(ctx, ReplenishmentPolicy{LeadTimeDays: 7})
+	if err != nil {
+		t.Fatalf("Suggestions() error = %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, got %+v", suggestions)
+	}
+	if s := suggestions[0]; s.LeadTimeDays != 14 || s.ReorderPoint != 33 {
+		t.Errorf("Expected WH-A to plan 14 days ahead to reorder point 33, got %+v", s)
+	}
+	if s := suggestions[1]; s.LeadTimeDays != 7 || s.ReorderPoint != 19 {
+		t.Errorf("Expected WH-B to plan 7 days ahead to reorder point 19, got %+v", s)
+	}
+}
+
+func TestReceivePurchaseOrder(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+	service := NewReplenishmentService(NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository()), NewMockPurchaseOrderRepository(), ReplenishmentPolicy{})
+
+	order, err := service.CreatePurchaseOrder(ctx, "ACME", "", []domain.PurchaseOrderLine{{ProductID: "prod-1", Quantity: 5}})
+	if err != nil {
+		t.Fatalf("CreatePurchaseOrder() error = %v", err)
+	}
+	if order.Lines[0].InventoryID != "inv-1" || order.Lines[0].Location != "WH-A" {
+		t.Errorf("Expected the line to be resolved to the default location, got %+v", order.Lines[0])
+	}
+
+	received, err := service.ReceivePurchaseOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("ReceivePurchaseOrder() error = %v", err)
+	}
+	if received.Status != domain.PurchaseOrderStatusReceived {
+		t.Errorf("Expected RECEIVED, got %s", received.Status)
+	}
+	if inventory, _ := inventoryRepo.GetByID(ctx, "inv-1"); inventory.Quantity != 15 {
+		t.Errorf("Expected 15 in stock after the receipt, got %d", inventory.Quantity)
+	}
+	if batched := inventoryRepo.batched; len(batched) != 1 || batched[0].Type != "IN" || batched[0].Reference != order.ID {
+		t.Errorf("Expected one IN transaction referencing the order, got %+v", batched)
+	}
+
+	if _, err := service.ReceivePurchaseOrder(ctx, order.ID); !errors.Is(err, ErrPurchaseOrderNotDraft) {
+		t.Errorf("Expected ErrPurchaseOrderNotDraft receiving twice, got %v", err)
+	}
+	if _, err := service.CancelPurchaseOrder(ctx, order.ID); !errors.Is(err, ErrPurchaseOrderNotDraft) {
+		t.Errorf("Expected ErrPurchaseOrderNotDraft cancelling a received order, got %v", err)
+	}
+}
+
+func TestReceivePurchaseOrderFailureKeepsDraft(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+	service := NewReplenishmentService(NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository()), NewMockPurchaseOrderRepository(), ReplenishmentPolicy{})
+
+	order, err := service.CreatePurchaseOrder(ctx, "ACME", "", []domain.PurchaseOrderLine{{ProductID: "prod-1", Location: "WH-A", Quantity: 5}})
+	if err != nil {
+		t.Fatalf("CreatePurchaseOrder() error = %v", err)
+	}
+
+	// The inventory row goes away before the delivery arrives
+	delete(inventoryRepo.items, "inv-1")
+
+	if _, err := service.ReceivePurchaseOrder(ctx, order.ID); err == nil {
+		t.Fatal("Expected the receipt to fail")
+	}
+	if got, _ := service.GetPurchaseOrder(ctx, order.ID); got.Status != domain.PurchaseOrderStatusDraft {
+		t.Errorf("Expected the order to stay a draft, got %s", got.Status)
+	}
+}
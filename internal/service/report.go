@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// ErrInvalidReportRequest is returned when report parameters are invalid
+var ErrInvalidReportRequest = errors.New("invalid report request")
+
+// StockSummaryDimensions lists the dimensions a stock summary can be grouped by
+var StockSummaryDimensions = []string{"category", "location"}
+
+// DefaultLowStockThreshold is the available quantity below which dashboard
+// stats count a product as low on stock unless configured otherwise
+const DefaultLowStockThreshold = 10
+
+// ReportService handles aggregate inventory reporting
+type ReportService struct {
+	reportRepo        repository.ReportRepository
+	lowStockThreshold int64
+}
+
+// ReportServiceOption configures optional ReportService behavior
+type ReportServiceOption func(*ReportService)
+
+// WithLowStockThreshold sets the available quantity below which dashboard
+// stats count a product as low on stock
+func WithLowStockThreshold(threshold int64) ReportServiceOption {
+	return func(s *ReportService) {
+		s.lowStockThreshold = threshold
+	}
+}
+
+// NewReportService creates a new ReportService
+func NewReportService(reportRepo repository.ReportRepository, opts ...ReportServiceOption) *ReportService {
+	s := &ReportService{
+		reportRepo:        reportRepo,
+		lowStockThreshold: DefaultLowStockThreshold,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// DashboardStats returns the headline figures of the dashboard, aggregated
+// by the repository rather than from paged listings
+func (s *ReportService) DashboardStats(ctx context.Context) (*domain.DashboardStats, error) {
+	stats, err := s.reportRepo.DashboardStats(ctx, s.lowStockThreshold, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute dashboard stats: %w", err)
+	}
+	return stats, nil
+}
+
+// StockSummary aggregates on-hand, reserved and value grouped by the given dimensions
+func (s *ReportService) StockSummary(ctx context.Context, groupBy []string) ([]*domain.StockSummary, error) {
+	seen := make(map[string]bool, len(groupBy))
+	for _, dimension := range groupBy {
+		if !isStockSummaryDimension(dimension) {
+			return nil, fmt.Errorf("%w: unsupported group_by dimension %q (supported: %v)", ErrInvalidReportRequest, dimension, StockSummaryDimensions)
+		}
+		if seen[dimension] {
+			return nil, fmt.Errorf("%w: duplicate group_by dimension %q", ErrInvalidReportRequest, dimension)
+		}
+		seen[dimension] = true
+	}
+
+	summaries, err := s.reportRepo.StockSummary(ctx, groupBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize stock: %w", err)
+	}
+	return summaries, nil
+}
+
+// isStockSummaryDimension reports whether dimension is a supported grouping
+func isStockSummaryDimension(dimension string) bool {
+	for _, supported := range StockSummaryDimensions {
+		if supported == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+// maxMovementBuckets bounds the number of periods a movement summary may span
+const maxMovementBuckets = 400
+
+// movementGranularities maps supported granularities to their approximate bucket length
+var movementGranularities = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 31 * 24 * time.Hour,
+}
+
+// MovementSummary totals stock movements per period, overall, for a single
+// product, or broken down per product. Granularity defaults to day.
+func (s *ReportService) MovementSummary(ctx context.Context, query repository.MovementQuery) ([]*domain.MovementSummary, error) {
+	if query.Granularity == "" {
+		query.Granularity = "day"
+	}
+
+	bucket, ok := movementGranularities[query.Granularity]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported granularity %q (supported: day, week, month)", ErrInvalidReportRequest, query.Granularity)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidReportRequest)
+	}
+	if query.To.Sub(query.From) > maxMovementBuckets*bucket {
+		return nil, fmt.Errorf("%w: period spans more than %d %s buckets", ErrInvalidReportRequest, maxMovementBuckets, query.Granularity)
+	}
+
+	summaries, err := s.reportRepo.MovementSummary(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize movements: %w", err)
+	}
+	return summaries, nil
+}
+
+// reservationAgeBuckets are the reservation aging buckets, youngest first.
+// The last bucket has no upper bound.
+var reservationAgeBuckets = []struct {
+	label string
+	upTo  time.Duration
+}{
+	{"0-1h", time.Hour},
+	{"1h-1d", 24 * time.Hour},
+	{"1d-3d", 3 * 24 * time.Hour},
+	{"3d-7d", 7 * 24 * time.Hour},
+	{"7d+", 0},
+}
+
+// ReservationAging groups the references holding open reservations by the
+// age of their oldest reservation, youngest bucket first. Every bucket is
+// returned, empty ones included, so the shape of the report is stable.
+func (s *ReportService) ReservationAging(ctx context.Context) ([]*domain.ReservationAgeBucket, error) {
+	holds, err := s.reportRepo.OpenReservationHolds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute reservation aging: %w", err)
+	}
+
+	buckets := make([]*domain.ReservationAgeBucket, len(reservationAgeBuckets))
+	var minAge time.Duration
+	for i, bound := range reservationAgeBuckets {
+		buckets[i] = &domain.ReservationAgeBucket{
+			Bucket:      bound.label,
+			MinAgeHours: int64(minAge.Hours()),
+			MaxAgeHours: int64(bound.upTo.Hours()),
+			References:  []*domain.ReservationHold{},
+		}
+		minAge = bound.upTo
+	}
+
+	now := time.Now()
+	for _, hold := range holds {
+		age := now.Sub(hold.OldestAt)
+		index := len(reservationAgeBuckets) - 1
+		for i, bound := range reservationAgeBuckets {
+			if age < bound.upTo {
+				index = i
+				break
+			}
+		}
+
+		bucket := buckets[index]
+		bucket.Reservations += hold.Reservations
+		bucket.Quantity += hold.Quantity
+		bucket.References = append(bucket.References, hold)
+	}
+
+	return buckets, nil
+}
+
+// Valuation values the stock on hand of every active product, or only the
+// given one, with the given method (fifo by default) and totals it
+func (s *ReportService) Valuation(ctx context.Context, method, productID string) (*domain.ValuationReport, error) {
+	valuationMethod, err := domain.ParseValuationMethod(method)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReportRequest, err)
+	}
+
+	inputs, err := s.reportRepo.ValuationInputs(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if productID != "" && len(inputs) == 0 {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	report := &domain.ValuationReport{Method: valuationMethod, Products: make([]*domain.ProductValuation, 0, len(inputs))}
+	for _, in := range inputs {
+		report.Add(in.Value(valuationMethod))
+	}
+	return report, nil
+}
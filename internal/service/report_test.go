@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MockReportRepository returns canned report rows and records the requested grouping
+type MockReportRepository struct {
+	groupBy    []string
+	velocities []*domain.StockVelocity
+	holds      []*domain.ReservationHold
+	snapshots  []*domain.StockSnapshot
+	capturedOn time.Time
+	historic   []*domain.HistoricStock
+	asOf       time.Time
+	valuation  []*domain.ValuationInput
+	threshold  int64
+}
+
+func (m *MockReportRepository) StockSummary(ctx context.Context, groupBy []string) ([]*domain.StockSummary, error) {
+	m.groupBy = groupBy
+	return []*domain.StockSummary{{Group: map[string]string{}, OnHand: 10, Reserved: 2, Available: 8}}, nil
+}
+
+func (m *MockReportRepository) MovementSummary(ctx context.Context, query repository.MovementQuery) ([]*domain.MovementSummary, error) {
+	return []*domain.MovementSummary{}, nil
+}
+
+func (m *MockReportRepository) InventoryKPIs(ctx context.Context, lowStockThreshold int64) (*domain.InventoryKPIs, error) {
+	return &domain.InventoryKPIs{Products: 7, ReservedUnits: 42, LowStockProducts: 3}, nil
+}
+
+func (m *MockReportRepository) DashboardStats(ctx context.Context, lowStockThreshold int64, now time.Time) (*domain.DashboardStats, error) {
+	m.threshold = lowStockThreshold
+	return &domain.DashboardStats{TotalProducts: 7, LowStockThreshold: lowStockThreshold, GeneratedAt: now}, nil
+}
+
+func (m *MockReportRepository) StockVelocities(ctx context.Context, since time.Time) ([]*domain.StockVelocity, error) {
+	return m.velocities, nil
+}
+
+func (m *MockReportRepository) OpenReservationHolds(ctx context.Context) ([]*domain.ReservationHold, error) {
+	return m.holds, nil
+}
+
+func (m *MockReportRepository) CaptureStockSnapshots(ctx context.Context, day time.Time) (int64, error) {
+	m.capturedOn = day
+	return 1, nil
+}
+
+func (m *MockReportRepository) StockSnapshots(ctx context.Context, productID string, from, to time.Time) ([]*domain.StockSnapshot, error) {
+	var snapshots []*domain.StockSnapshot
+	for i, snapshot := range m.snapshots {
+		before := !snapshot.Day.After(from) && (i+1 == len(m.snapshots) || m.snapshots[i+1].Day.After(from))
+		if !snapshot.Day.After(to) && (before || !snapshot.Day.Before(from)) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func (m *MockReportRepository) StockAsOf(ctx context.Context, asOf time.Time) ([]*domain.HistoricStock, error) {
+	m.asOf = asOf
+	return m.historic, nil
+}
+
+func (m *MockReportRepository) ValuationInputs(ctx context.Context, productID string) ([]*domain.ValuationInput, error) {
+	var inputs []*domain.ValuationInput
+	for _, in := range m.valuation {
+		if productID == "" || in.ProductID == productID {
+			inputs = append(inputs, in)
+		}
+	}
+	return inputs, nil
+}
+
+func TestStockSummaryGroupByValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy []string
+		wantErr bool
+	}{
+		{"Overall", nil, false},
+		{"Category and location", []string{"category", "location"}, false},
+		{"Unknown dimension", []string{"supplier"}, true},
+		{"Duplicate dimension", []string{"location", "location"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockReportRepository{}
+			service := NewReportService(repo)
+
+			_, err := service.StockSummary(context.Background(), tt.groupBy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StockSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidReportRequest) {
+				t.Errorf("Expected ErrInvalidReportRequest, got %v", err)
+			}
+			if !tt.wantErr && len(repo.groupBy) != len(tt.groupBy) {
+				t.Errorf("Expected grouping %v to reach the repository, got %v", tt.groupBy, repo.groupBy)
+			}
+		})
+	}
+}
+
+func TestMovementSummaryValidation(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		to          time.Time
+		granularity string
+		wantErr     bool
+	}{
+		{"Default granularity", from.AddDate(0, 1, 0), "", false},
+		{"Weekly", from.AddDate(1, 0, 0), "week", false},
+		{"Unknown granularity", from.AddDate(0, 1, 0), "hour", true},
+		{"Inverted period", from.AddDate(0, 0, -1), "day", true},
+		{"Too many buckets", from.AddDate(3, 0, 0), "day", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewReportService(&MockReportRepository{})
+
+			_, err := service.MovementSummary(context.Background(), repository.MovementQuery{
+				From: from, To: tt.to, Granularity: tt.granularity,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MovementSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalidReportRequest) {
+				t.Errorf("Expected ErrInvalidReportRequest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDashboardStats(t *testing.T) {
+	repo := &MockReportRepository{}
+	stats, err := NewReportService(repo).DashboardStats(context.Background())
+	if err != nil {
+		t.Fatalf("DashboardStats() error = %v", err)
+	}
+	if repo.threshold != DefaultLowStockThreshold || stats.TotalProducts != 7 || stats.GeneratedAt.IsZero() {
+		t.Errorf("Expected the stats at the default threshold, got %+v", stats)
+	}
+
+	if _, err := NewReportService(repo, WithLowStockThreshold(25)).DashboardStats(context.Background()); err != nil || repo.threshold != 25 {
+		t.Errorf("Expected the configured threshold 25, got %d (%v)", repo.threshold, err)
+	}
+}
+
+func TestStockoutReport(t *testing.T) {
+	repo := &MockReportRepository{velocities: []*domain.StockVelocity{
+		{ProductID: "slow", Available: 300, Outbound: 30},
+		{ProductID: "soon", Available: 10, Outbound: 60},
+		{ProductID: "out", Available: 0, Outbound: 5},
+	}}
+	service := NewReportService(repo)
+
+	projections, err := service.StockoutReport(context.Background(), 14)
+	if err != nil {
+		t.Fatalf("StockoutReport() error = %v", err)
+	}
+	if len(projections) != 2 || projections[0].ProductID != "out" || projections[1].ProductID != "soon" {
+		t.Fatalf("Expected out and soon projected within 14 days, got %+v", projections)
+	}
+	if projections[1].DailyVelocity != 2 || projections[1].DaysRemaining < 4.9 || projections[1].DaysRemaining > 5.1 {
+		t.Errorf("Expected 2 units/day and 5 days remaining, got %v and %v", projections[1].DailyVelocity, projections[1].DaysRemaining)
+	}
+
+	if _, err := service.StockoutReport(context.Background(), 0); !errors.Is(err, ErrInvalidReportRequest) {
+		t.Errorf("Expected ErrInvalidReportRequest for 0 days, got %v", err)
+	}
+}
+
+func TestReservationAging(t *testing.T) {
+	now := time.Now()
+	repo := &MockReportRepository{holds: []*domain.ReservationHold{
+		{Reference: "ORD-OLD", Reservations: 2, Quantity: 5, OldestAt: now.Add(-10 * 24 * time.Hour)},
+		{Reference: "ORD-DAY", Reservations: 1, Quantity: 3, OldestAt: now.Add(-30 * time.Hour)},
+		{Reference: "ORD-2", Reservations: 1, Quantity: 1, OldestAt: now.Add(-2 * time.Hour)},
+		{Reference: "ORD-3", Reservations: 3, Quantity: 4, OldestAt: now.Add(-3 * time.Hour)},
+		{Reference: "ORD-NEW", Reservations: 1, Quantity: 2, OldestAt: now.Add(-time.Minute)},
+	}}
+
+	buckets, err := NewReportService(repo).ReservationAging(context.Background())
+	if err != nil {
+		t.Fatalf("ReservationAging() error = %v", err)
+	}
+	if len(buckets) != 5 {
+		t.Fatalf("Expected 5 buckets, got %d", len(buckets))
+	}
+
+	want := []struct {
+		bucket       string
+		reservations int64
+		quantity     int64
+		references   int
+	}{
+		{"0-1h", 1, 2, 1},
+		{"1h-1d", 4, 5, 2},
+		{"1d-3d", 1, 3, 1},
+		{"3d-7d", 0, 0, 0},
+		{"7d+", 2, 5, 1},
+	}
+	for i, w := range want {
+		got := buckets[i]
+		if got.Bucket != w.bucket || got.Reservations != w.reservations || got.Quantity != w.quantity || len(got.References) != w.references {
+			t.Errorf("Bucket %d = %s %d/%d with %d references, want %+v", i, got.Bucket, got.Reservations, got.Quantity, len(got.References), w)
+		}
+	}
+	if buckets[4].MaxAgeHours != 0 || buckets[4].MinAgeHours != 168 {
+		t.Errorf("Expected open-ended last bucket from 168h, got %+v", buckets[4])
+	}
+	if buckets[3].References == nil {
+		t.Error("Expected empty buckets to have an empty reference list")
+	}
+}
+
+func TestValuation(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockReportRepository{valuation: []*domain.ValuationInput{
+		{
+			// 10 on hand out of receipts of 5 @ 2.00, 8 @ 3.00 and 4 @ 4.00
+			ProductID: "prod-1", SKU: "A-1", Quantity: 10, ReceivedUnits: 17, ReceivedCost: 50,
+			Layers: []domain.CostLayer{{Quantity: 4, UnitCost: 4}, {Quantity: 8, UnitCost: 3}},
+		},
+		{
+			// 6 on hand but only 2 received with a cost
+			ProductID: "prod-2", SKU: "B-1", Quantity: 6, ReceivedUnits: 2, ReceivedCost: 3,
+			Layers: []domain.CostLayer{{Quantity: 2, UnitCost: 1.5}},
+		},
+		{ProductID: "prod-3", SKU: "C-1"},
+	}}
+	service := NewReportService(repo)
+
+	tests := []struct {
+		method    string
+		wantValue float64
+		wantUnit  float64
+	}{
+		// The latest 4 @ 4.00 and 6 of the 8 @ 3.00; the other product's 2 @ 1.50
+		{"", 34 + 3, 3.4},
+		// 50 / 17 per unit; the other product's 2 @ 1.50
+		{"weighted_average", 29.41 + 3, 2.9412},
+	}
+	for _, tt := range tests {
+		t.Run("method "+tt.method, func(t *testing.T) {
+			report, err := service.Valuation(ctx, tt.method, "")
+			if err != nil {
+				t.Fatalf("Valuation() error = %v", err)
+			}
+			if len(report.Products) != 3 || report.Quantity != 16 || report.UncostedQuantity != 4 {
+				t.Fatalf("Unexpected report %+v", report)
+			}
+			if report.Value != tt.wantValue || report.Products[0].UnitCost != tt.wantUnit {
+				t.Errorf("Expected a value of %.2f at %.4f per unit, got %.2f at %.4f", tt.wantValue, tt.wantUnit, report.Value, report.Products[0].UnitCost)
+			}
+			if stock := report.Products[2]; stock.Value != 0 || stock.UncostedQuantity != 0 {
+				t.Errorf("Expected no value without stock, got %+v", stock)
+			}
+		})
+	}
+
+	report, err := service.Valuation(ctx, "FIFO", "prod-2")
+	if err != nil {
+		t.Fatalf("Valuation() of one product error = %v", err)
+	}
+	if len(report.Products) != 1 || report.Value != 3 || report.UncostedQuantity != 4 {
+		t.Errorf("Expected only prod-2 valued at 3.00 with 4 uncosted, got %+v", report)
+	}
+
+	if _, err := service.Valuation(ctx, "lifo", ""); !errors.Is(err, ErrInvalidReportRequest) {
+		t.Errorf("Expected an invalid report request for an unknown method, got %v", err)
+	}
+	if _, err := service.Valuation(ctx, "", "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected not found for an unknown product, got %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// reservationExpiryBatchSize is the number of expired reservations released per query
+const reservationExpiryBatchSize = 100
+
+// ErrReservationNotPending is returned when confirming or releasing a
+// reservation that was already confirmed, released or expired
+var ErrReservationNotPending = errors.New("reservation is no longer pending")
+
+// ReservationService manages reservations held for individual orders on top
+// of the inventory reserved counters
+type ReservationService struct {
+	inventory       *InventoryService
+	reservationRepo repository.ReservationRepository
+	defaultTTL      time.Duration
+}
+
+// NewReservationService creates a new ReservationService. Reservations
+// created without an explicit TTL expire after defaultTTL.
+func NewReservationService(inventory *InventoryService, reservationRepo repository.ReservationRepository, defaultTTL time.Duration) *ReservationService {
+	return &ReservationService{
+		inventory:       inventory,
+		reservationRepo: reservationRepo,
+		defaultTTL:      defaultTTL,
+	}
+}
+
+// CreateReservation reserves stock for an order and records the reservation.
+// An empty location reserves at the product's default location, an empty
+// condition new stock; a zero ttl uses the service default.
+func (s *ReservationService) CreateReservation(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string, ttl time.Duration) (*domain.Reservation, error) {
+	return s.createReservation(ctx, "", "", productID, location, condition, quantity, reference, ttl)
+}
+
+// CreateSplitReservation reserves stock for an order like CreateReservation,
+// but when the location cannot cover the quantity on its own the reservation
+// is split: it takes what the location has and the rest from the other
+// locations stocking the product in the same condition, those with the most
+// available stock first, as child reservations sharing a parent ID. When the
+// locations together hold too little nothing is reserved; when a child
+// cannot be reserved, e.g. because stock moved meanwhile, the children
+// reserved before it are released.
+func (s *ReservationService) CreateSplitReservation(ctx context.Context, productID, location string, condition domain.StockCondition, quantity int64, reference string, ttl time.Duration) (*domain.ReservationSplit, error) {
+	reservation, err := s.CreateReservation(ctx, productID, location, condition, quantity, reference, ttl)
+	if err == nil {
+		return domain.NewReservationSplit("", []*domain.Reservation{reservation}), nil
+	}
+	if !errors.Is(err, domain.ErrInsufficientStock) {
+		return nil, err
+	}
+
+	preferred, err := s.inventory.resolveStock(ctx, productID, location, condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	if preferred == nil {
+		return nil, fmt.Errorf("inventory %w", domain.ErrNotFound)
+	}
+	items, err := s.inventory.inventoryRepo.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	stocked := make([]*domain.InventoryItem, 0, len(items))
+	for _, item := range items {
+		if item.Condition == preferred.Condition {
+			stocked = append(stocked, item)
+		}
+	}
+
+	allocations, err := domain.AllocateAcrossLocations(stocked, preferred.Location, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := uuid.New().String()
+	children := make([]*domain.Reservation, 0, len(allocations))
+	for _, allocation := range allocations {
+		child, err := s.createReservation(ctx, "", parentID, productID, allocation.Location, preferred.Condition, allocation.Quantity, reference, ttl)
+		if err != nil {
+			s.releaseChildren(ctx, children)
+			return nil, fmt.Errorf("failed to reserve %d at %s: %w", allocation.Quantity, allocation.Location, err)
+		}
+		children = append(children, child)
+	}
+	return domain.NewReservationSplit(parentID, children), nil
+}
+
+// releaseChildren releases the children of a split that could not be
+// reserved in full
+func (s *ReservationService) releaseChildren(ctx context.Context, children []*domain.Reservation) {
+	for _, child := range children {
+		if _, err := s.ReleaseReservation(ctx, child.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to release reservation of failed split",
+				"parent_id", child.ParentID, "reservation_id", child.ID, "error", err)
+		}
+	}
+}
+
+// GetReservationSplit retrieves the children of a split reservation
+func (s *ReservationService) GetReservationSplit(ctx context.Context, id string) (*domain.ReservationSplit, error) {
+	if id == "" {
+		return nil, fmt.Errorf("reservation split %w", domain.ErrNotFound)
+	}
+	children, err := s.reservationRepo.ListByParentID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation split: %w", err)
+	}
+	if len(children) == 0 {
+		return nil, fmt.Errorf("reservation split %w", domain.ErrNotFound)
+	}
+	return domain.NewReservationSplit(id, children), nil
+}
+
+// createReservation reserves stock and records the reservation on behalf of
+// a sales channel, if any, as a child of a split reservation, if any
+func (s *ReservationService) createReservation(ctx context.Context, channelID, parentID, productID, location string, condition domain.StockCondition, quantity int64, reference string, ttl time.Duration) (_ *domain.Reservation, err error) {
+	ctx, span := s.inventory.startSpan(ctx, "ReservationService.CreateReservation", append(stockAttributes(productID, location, quantity), attribute.String("inventory.channel_id", channelID))...)
+	defer func() { endSpan(span, err) }()
+
+	if reference == "" {
+		return nil, domain.NewValidationError("reference cannot be empty")
+	}
+	if ttl < 0 {
+		return nil, domain.NewValidationError("ttl cannot be negative")
+	}
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+
+	inventory, err := s.inventory.reserveAt(ctx, productID, location, condition, quantity, nil, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &domain.Reservation{
+		ParentID:    parentID,
+		ProductID:   productID,
+		InventoryID: inventory.ID,
+		Location:    inventory.Location,
+		Quantity:    quantity,
+		Reference:   reference,
+		ChannelID:   channelID,
+		Status:      domain.ReservationStatusPending,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	if err := s.reservationRepo.Create(ctx, reservation); err != nil {
+		// Give the stock back so a failed reservation does not hold it forever
+		_ = s.inventory.releaseReserved(ctx, reservation, "Reservation rollback")
+		return nil, fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	s.inventory.metrics.recordReservation(ctx, string(reservation.Status))
+	return reservation, nil
+}
+
+// GetReservation retrieves a reservation by ID
+func (s *ReservationService) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return reservation, nil
+}
+
+// ListReservations lists the reservations of a product, newest first
+func (s *ReservationService) ListReservations(ctx context.Context, productID string, limit, offset int) ([]*domain.Reservation, error) {
+	reservations, err := s.reservationRepo.ListByProductID(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// ConfirmReservation turns a pending reservation into a sale: the reserved
+// units leave the inventory
+func (s *ReservationService) ConfirmReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	return s.finish(ctx, id, domain.ReservationStatusConfirmed, s.inventory.fulfilReserved)
+}
+
+// ReleaseReservation cancels a pending reservation and returns its units to
+// available stock
+func (s *ReservationService) ReleaseReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	return s.finish(ctx, id, domain.ReservationStatusReleased, func(ctx context.Context, reservation *domain.Reservation) error {
+		return s.inventory.releaseReserved(ctx, reservation, "Reservation released")
+	})
+}
+
+// finish claims a pending reservation for the final status and applies its
+// stock effect. Claiming first makes confirm, release and expiry mutually
+// exclusive; if the stock update fails the claim is undone.
+func (s *ReservationService) finish(ctx context.Context, id string, status domain.ReservationStatus, apply func(context.Context, *domain.Reservation) error) (_ *domain.Reservation, err error) {
+	ctx, span := s.inventory.startSpan(ctx, "ReservationService.finish",
+		attribute.String("inventory.reservation_id", id), attribute.String("inventory.status", string(status)))
+	defer func() { endSpan(span, err) }()
+
+	reservation, err := s.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status.IsFinal() {
+		return nil, fmt.Errorf("%w: reservation is %s", ErrReservationNotPending, reservation.Status)
+	}
+
+	claimed, err := s.reservationRepo.UpdateStatus(ctx, id, domain.ReservationStatusPending, status)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrReservationNotPending
+	}
+
+	if err := apply(ctx, reservation); err != nil {
+		_, _ = s.reservationRepo.UpdateStatus(ctx, id, status, domain.ReservationStatusPending)
+		return nil, err
+	}
+
+	reservation.Status = status
+	reservation.UpdatedAt = time.Now()
+	s.inventory.metrics.recordReservation(ctx, string(status))
+	return reservation, nil
+}
+
+// ExpireReservations releases every pending reservation whose expiry time has
+// passed and returns how many were released
+func (s *ReservationService) ExpireReservations(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		reservations, err := s.reservationRepo.ListExpired(ctx, time.Now(), reservationExpiryBatchSize)
+		if err != nil {
+			return expired, fmt.Errorf("failed to list expired reservations: %w", err)
+		}
+
+		released := 0
+		for _, reservation := range reservations {
+			_, err := s.finish(ctx, reservation.ID, domain.ReservationStatusExpired, func(ctx context.Context, reservation *domain.Reservation) error {
+				return s.inventory.releaseReserved(ctx, reservation, "Reservation expired")
+			})
+			if errors.Is(err, ErrReservationNotPending) {
+				continue
+			}
+			if err != nil {
+				return expired, fmt.Errorf("failed to expire reservation %s: %w", reservation.ID, err)
+			}
+			released++
+		}
+		expired += released
+
+		if len(reservations) < reservationExpiryBatchSize || released == 0 {
+			return expired, nil
+		}
+	}
+}
+
+// Run expires stale reservations every interval until ctx is cancelled
+func (s *ReservationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			count, err := s.ExpireReservations(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "reservation expiry failed", "error", err)
+			}
+			if count > 0 {
+				slog.InfoContext(ctx, "expired reservations", "count", count)
+			}
+		}
+	}
+}
+
+// releaseReserved returns the units of a reservation to available stock
+func (s *InventoryService) releaseReserved(ctx context.Context, reservation *domain.Reservation, notes string) error {
+	transaction := &domain.Transaction{
+		InventoryID: reservation.InventoryID,
+		ProductID:   reservation.ProductID,
+		Type:        "UNRESERVE",
+		Quantity:    reservation.Quantity,
+		Reference:   reservation.Reference,
+		Notes:       notes,
+	}
+
+	if err := s.applyMovement(ctx, reservation.ProductID, reservation.InventoryID, 0, -reservation.Quantity, transaction); err != nil {
+		return fmt.Errorf("failed to unreserve stock: %w", err)
+	}
+
+	return nil
+}
+
+// fulfilReserved removes the units of a confirmed reservation from stock. It
+// records the release of the reservation and the outgoing movement
+// separately, so reserved totals per reference stay consistent. With lots
+// tracked the units shipped come from the lots expiring first.
+func (s *InventoryService) fulfilReserved(ctx context.Context, reservation *domain.Reservation) error {
+	transactions := []*domain.Transaction{
+		{Type: "UNRESERVE", Notes: "Reservation confirmed"},
+		{Type: "OUT", Notes: "Reserved stock shipped"},
+	}
+	for _, transaction := range transactions {
+		transaction.InventoryID = reservation.InventoryID
+		transaction.ProductID = reservation.ProductID
+		transaction.Quantity = reservation.Quantity
+		transaction.Reference = reservation.Reference
+	}
+
+	var check movementCheck
+	if s.lotRepo != nil {
+		check = s.consumeLots(reservation.Quantity, transactions[1], nil)
+	}
+	if err := s.applyCheckedMovement(ctx, reservation.ProductID, reservation.InventoryID, -reservation.Quantity, -reservation.Quantity, check, transactions...); err != nil {
+		return fmt.Errorf("failed to fulfil reservation: %w", err)
+	}
+
+	return nil
+}
+
+// restockReserved reverses fulfilReserved: the units of the reservation come
+// back into stock and are reserved again
+func (s *InventoryService) restockReserved(ctx context.Context, reservation *domain.Reservation, notes string) error {
+	transactions := []*domain.Transaction{
+		{Type: "IN", Notes: notes},
+		{Type: "RESERVE", Notes: notes},
+	}
+	for _, transaction := range transactions {
+		transaction.InventoryID = reservation.InventoryID
+		transaction.ProductID = reservation.ProductID
+		transaction.Quantity = reservation.Quantity
+		transaction.Reference = reservation.Reference
+	}
+
+	if err := s.applyMovement(ctx, reservation.ProductID, reservation.InventoryID, reservation.Quantity, reservation.Quantity, transactions...); err != nil {
+		return fmt.Errorf("failed to restock reservation: %w", err)
+	}
+
+	return nil
+}
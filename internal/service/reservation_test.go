@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockReservationRepository implements ReservationRepository interface for testing
+type MockReservationRepository struct {
+	reservations map[string]*domain.Reservation
+}
+
+func NewMockReservationRepository() *MockReservationRepository {
+	return &MockReservationRepository{
+		reservations: make(map[string]*domain.Reservation),
+	}
+}
+
+func (m *MockReservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	if err := reservation.Validate(); err != nil {
+		return err
+	}
+	reservation.ID = fmt.Sprintf("res-%d", len(m.reservations)+1)
+	stored := *reservation
+	m.reservations[reservation.ID] = &stored
+	return nil
+}
+
+func (m *MockReservationRepository) GetByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	if r, ok := m.reservations[id]; ok {
+		copied := *r
+		return &copied, nil
+	}
+	return nil, errors.New("reservation not found")
+}
+
+func (m *MockReservationRepository) UpdateStatus(ctx context.Context, id string, from, to domain.ReservationStatus) (bool, error) {
+	r, ok := m.reservations[id]
+	if !ok || r.Status != from {
+		return false, nil
+	}
+	r.Status = to
+	r.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (m *MockReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.Reservation, error) {
+	var expired []*domain.Reservation
+	for _, r := range m.reservations {
+		if r.IsExpired(now) && len(expired) < limit {
+			copied := *r
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+func (m *MockReservationRepository) ListByProductID(ctx context.Context, productID string, limit, offset int) ([]*domain.Reservation, error) {
+	var reservations []*domain.Reservation
+	for _, r := range m.reservations {
+		if r.ProductID == productID {
+			reservations = append(reservations, r)
+		}
+	}
+	return reservations, nil
+}
+
+func (m *MockReservationRepository) ListByParentID(ctx context.Context, parentID string) ([]*domain.Reservation, error) {
+	var children []*domain.Reservation
+	for i := 1; i <= len(m.reservations); i++ {
+		if r := m.reservations[fmt.Sprintf("res-%d", i)]; r != nil && r.ParentID == parentID {
+			copied := *r
+			children = append(children, &copied)
+		}
+	}
+	return children, nil
+}
+
+func (m *MockReservationRepository) PendingByChannel(ctx context.Context, channelID, productID string) (int64, error) {
+	var pending int64
+	for _, r := range m.reservations {
+		if r.ChannelID == channelID && r.ProductID == productID && r.Status == domain.ReservationStatusPending {
+			pending += r.Quantity
+		}
+	}
+	return pending, nil
+}
+
+func (m *MockReservationRepository) ConfirmedByChannel(ctx context.Context, channelID, productID string, since time.Time) (int64, error) {
+	var confirmed int64
+	for _, r := range m.reservations {
+		if r.ChannelID == channelID && r.ProductID == productID && r.Status == domain.ReservationStatusConfirmed && !r.UpdatedAt.Before(since) {
+			confirmed += r.Quantity
+		}
+	}
+	return confirmed, nil
+}
+
+func setupReservationTest(t *testing.T) (*ReservationService, *MockReservationRepository, *MockInventoryRepository) {
+	t.Helper()
+
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	reservationRepo := NewMockReservationRepository()
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	inventoryService := NewInventoryService(productRepo, inventoryRepo, transactionRepo)
+	return NewReservationService(inventoryService, reservationRepo, 15*time.Minute), reservationRepo, inventoryRepo
+}
+
+func TestReservationConfirm(t *testing.T) {
+	service, _, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+
+	reservation, err := service.CreateReservation(ctx, "prod-1", "", "", 4, "ORDER-1", 0)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if reservation.Status != domain.ReservationStatusPending || reservation.Location != "WH-A" {
+		t.Errorf("Unexpected reservation: %+v", reservation)
+	}
+	if until := time.Until(reservation.ExpiresAt); until < 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("Expected default TTL of 15m, expires in %v", until)
+	}
+
+	inventory, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if inventory.Reserved != 4 {
+		t.Errorf("Expected 4 reserved, got %d", inventory.Reserved)
+	}
+
+	confirmed, err := service.ConfirmReservation(ctx, reservation.ID)
+	if err != nil {
+		t.Fatalf("Failed to confirm reservation: %v", err)
+	}
+	if confirmed.Status != domain.ReservationStatusConfirmed {
+		t.Errorf("Expected CONFIRMED, got %s", confirmed.Status)
+	}
+	if inventory.Quantity != 6 || inventory.Reserved != 0 {
+		t.Errorf("Expected quantity 6 and reserved 0, got %d and %d", inventory.Quantity, inventory.Reserved)
+	}
+
+	if _, err := service.ReleaseReservation(ctx, reservation.ID); !errors.Is(err, ErrReservationNotPending) {
+		t.Errorf("Expected ErrReservationNotPending releasing a confirmed reservation, got %v", err)
+	}
+}
+
+func TestReservationRelease(t *testing.T) {
+	service, _, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+
+	reservation, err := service.CreateReservation(ctx, "prod-1", "WH-A", "", 3, "ORDER-2", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+
+	if _, err := service.ReleaseReservation(ctx, reservation.ID); err != nil {
+		t.Fatalf("Failed to release reservation: %v", err)
+	}
+
+	inventory, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if inventory.Quantity != 10 || inventory.Reserved != 0 {
+		t.Errorf("Expected quantity 10 and reserved 0, got %d and %d", inventory.Quantity, inventory.Reserved)
+	}
+
+	if _, err := service.ConfirmReservation(ctx, reservation.ID); !errors.Is(err, ErrReservationNotPending) {
+		t.Errorf("Expected ErrReservationNotPending confirming a released reservation, got %v", err)
+	}
+}
+
+func TestCreateSplitReservation(t *testing.T) {
+	service, reservationRepo, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-1", Quantity: 3, Location: "WH-B"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-3", ProductID: "prod-1", Quantity: 6, Location: "WH-C"})
+
+	// A quantity the location covers is not split
+	split, err := service.CreateSplitReservation(ctx, "prod-1", "WH-B", "", 2, "ORDER-1", 0)
+	if err != nil {
+		t.Fatalf("CreateSplitReservation() error = %v", err)
+	}
+	if split.Split || split.ID != "" || len(split.Reservations) != 1 || split.Reservations[0].ParentID != "" {
+		t.Errorf("Expected a single reservation, got %+v", split)
+	}
+
+	// The rest of WH-B comes first, then the location with the most stock
+	split, err = service.CreateSplitReservation(ctx, "prod-1", "WH-B", "", 12, "ORDER-2", 0)
+	if err != nil {
+		t.Fatalf("CreateSplitReservation() error = %v", err)
+	}
+	want := []domain.ReservationAllocation{{Location: "WH-B", Quantity: 1}, {Location: "WH-A", Quantity: 10}, {Location: "WH-C", Quantity: 1}}
+	if !split.Split || split.ID == "" || split.Quantity != 12 || fmt.Sprint(split.Allocations) != fmt.Sprint(want) {
+		t.Fatalf("Expected the split %v, got %+v", want, split)
+	}
+	for _, child := range split.Reservations {
+		if child.ParentID != split.ID || child.Reference != "ORDER-2" {
+			t.Errorf("Expected a child of %s, got %+v", split.ID, child)
+		}
+	}
+	if _, reserved := stock(t, inventoryRepo, "inv-1"); reserved != 10 {
+		t.Errorf("Expected 10 reserved at WH-A, got %d", reserved)
+	}
+
+	stored, err := service.GetReservationSplit(ctx, split.ID)
+	if err != nil || len(stored.Reservations) != 3 || stored.Quantity != 12 {
+		t.Errorf("Expected the 3 children of the split, got %+v (%v)", stored, err)
+	}
+	if _, err := service.GetReservationSplit(ctx, "unknown"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown split, got %v", err)
+	}
+
+	// More than all locations hold reserves nothing
+	count := len(reservationRepo.reservations)
+	if _, err := service.CreateSplitReservation(ctx, "prod-1", "WH-C", "", 6, "ORDER-3", 0); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got %v", err)
+	}
+	if len(reservationRepo.reservations) != count {
+		t.Errorf("Expected no reservation created, got %d more", len(reservationRepo.reservations)-count)
+	}
+}
+
+func TestExpireReservations(t *testing.T) {
+	service, reservationRepo, inventoryRepo := setupReservationTest(t)
+	ctx := context.Background()
+
+	stale, err := service.CreateReservation(ctx, "prod-1", "", "", 2, "ORDER-3", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	fresh, err := service.CreateReservation(ctx, "prod-1", "", "", 5, "ORDER-4", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	reservationRepo.reservations[stale.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+	count, err := service.ExpireReservations(ctx)
+	if err != nil {
+		t.Fatalf("Failed to expire reservations: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 expired reservation, got %d", count)
+	}
+
+	if status := reservationRepo.reservations[stale.ID].Status; status != domain.ReservationStatusExpired {
+		t.Errorf("Expected stale reservation EXPIRED, got %s", status)
+	}
+	if status := reservationRepo.reservations[fresh.ID].Status; status != domain.ReservationStatusPending {
+		t.Errorf("Expected fresh reservation PENDING, got %s", status)
+	}
+
+	inventory, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if inventory.Reserved != 5 {
+		t.Errorf("Expected 5 reserved after expiry, got %d", inventory.Reserved)
+	}
+}
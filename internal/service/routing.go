@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// ParseTransferLanes parses transfer lanes given as
+// from:to:unit_cost:transit_hours entries separated by commas or newlines, as
+// in the TRANSFER_LANES variable or a lanes file. Blank entries and lines
+// starting with # are skipped.
+func ParseTransferLanes(spec string) ([]domain.TransferLane, error) {
+	var lanes []domain.TransferLane
+	scanner := bufio.NewScanner(strings.NewReader(strings.ReplaceAll(spec, ",", "\n")))
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid transfer lane %q: expected from:to:unit_cost:transit_hours", entry)
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer lane %q: unit cost must be a number", entry)
+		}
+		hours, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer lane %q: transit hours must be a number", entry)
+		}
+		lanes = append(lanes, domain.TransferLane{
+			From:         strings.TrimSpace(parts[0]),
+			To:           strings.TrimSpace(parts[1]),
+			UnitCost:     cost,
+			TransitHours: hours,
+		})
+	}
+	return lanes, scanner.Err()
+}
+
+// RoutingPolicy weighs split shipments against transfers. Each shipment
+// beyond the first costs SplitShipmentCost; transfers cost the unit cost of
+// their lane per unit. MaxLeadHours, if set, rules out transfers that take
+// longer.
+type RoutingPolicy struct {
+	SplitShipmentCost float64
+	MaxLeadHours      float64
+}
+
+// RoutingService plans how an order is fulfilled from a destination
+// warehouse: locally, as a split shipment from several warehouses, or by
+// transferring the shortfall to the destination first
+type RoutingService struct {
+	inventory *InventoryService
+	matrix    *domain.TransferMatrix
+	policy    RoutingPolicy
+}
+
+// NewRoutingService creates a new RoutingService. A nil matrix has no lanes,
+// so shortfalls can only be split.
+func NewRoutingService(inventory *InventoryService, matrix *domain.TransferMatrix, policy RoutingPolicy) *RoutingService {
+	return &RoutingService{
+		inventory: inventory,
+		matrix:    matrix,
+		policy:    policy,
+	}
+}
+
+// Lanes returns the configured transfer lanes
+func (s *RoutingService) Lanes() []domain.TransferLane {
+	return s.matrix.Lanes()
+}
+
+// PlanFulfillment chooses the cheapest way to fulfil quantity units of a
+// product at the destination warehouse; ties go to the faster option, then
+// to splitting. maxLeadHours overrides the policy's limit when positive.
+// Nothing is reserved or moved.
+func (s *RoutingService) PlanFulfillment(ctx context.Context, productID, destination string, quantity int64, maxLeadHours float64) (*domain.FulfillmentPlan, error) {
+	if productID == "" {
+		return nil, domain.NewValidationError("product_id cannot be empty")
+	}
+	if destination == "" {
+		return nil, domain.NewValidationError("destination cannot be empty")
+	}
+	if quantity <= 0 {
+		return nil, domain.NewValidationError("quantity must be positive")
+	}
+	if maxLeadHours <= 0 {
+		maxLeadHours = s.policy.MaxLeadHours
+	}
+
+	items, err := s.inventory.inventoryRepo.ListByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+
+	var local int64
+	var total int64
+	var others []*domain.InventoryItem
+	for _, item := range items {
+		// Plans ship and transfer new stock only
+		if item.Condition != domain.ConditionNew {
+			continue
+		}
+		available := item.AvailableQuantity()
+		total += available
+		if item.Location == destination {
+			local = available
+		} else if available > 0 {
+			others = append(others, item)
+		}
+	}
+	if total < quantity {
+		return nil, fmt.Errorf("%w: %d of %d units available over all locations", domain.ErrInsufficientStock, total, quantity)
+	}
+
+	plan := &domain.FulfillmentPlan{ProductID: productID, Destination: destination, Quantity: quantity}
+
+	if local >= quantity {
+		plan.Strategy = domain.FulfillLocal
+		plan.Options = []*domain.FulfillmentOption{{
+			Strategy:  domain.FulfillLocal,
+			Shipments: 1,
+			Legs:      []domain.FulfillmentLeg{{Location: destination, Quantity: quantity}},
+		}}
+		return plan, nil
+	}
+
+	options := []*domain.FulfillmentOption{s.splitOption(destination, local, quantity, others)}
+	if transfer := s.transferOption(destination, local, quantity, others); transfer != nil {
+		if maxLeadHours <= 0 || transfer.LeadHours <= maxLeadHours {
+			options = append(options, transfer)
+		}
+	}
+
+	// Stable, so a tie in cost and lead time keeps the split first
+	sort.SliceStable(options, func(i, j int) bool {
+		if options[i].Cost != options[j].Cost {
+			return options[i].Cost < options[j].Cost
+		}
+		return options[i].LeadHours < options[j].LeadHours
+	})
+
+	plan.Strategy = options[0].Strategy
+	plan.Options = options
+	return plan, nil
+}
+
+// splitOption ships what the destination holds and the rest directly from
+// the warehouses with the most available stock, for the fewest shipments
+func (s *RoutingService) splitOption(destination string, local, quantity int64, others []*domain.InventoryItem) *domain.FulfillmentOption {
+	sources := append([]*domain.InventoryItem(nil), others...)
+	sort.SliceStable(sources, func(i, j int) bool {
+		if sources[i].AvailableQuantity() != sources[j].AvailableQuantity() {
+			return sources[i].AvailableQuantity() > sources[j].AvailableQuantity()
+		}
+		return sources[i].Location < sources[j].Location
+	})
+
+	option := &domain.FulfillmentOption{Strategy: domain.FulfillSplit}
+	remaining := quantity
+	if local > 0 {
+		option.Legs = append(option.Legs, domain.FulfillmentLeg{Location: destination, Quantity: local})
+		remaining -= local
+	}
+	for _, source := range sources {
+		if remaining == 0 {
+			break
+		}
+		take := min(source.AvailableQuantity(), remaining)
+		option.Legs = append(option.Legs, domain.FulfillmentLeg{Location: source.Location, Quantity: take})
+		remaining -= take
+	}
+
+	option.Shipments = len(option.Legs)
+	option.Cost = float64(option.Shipments-1) * s.policy.SplitShipmentCost
+	return option
+}
+
+// transferOption moves the shortfall to the destination over the cheapest
+// lanes first and ships the order in one part. It returns nil when the
+// lanes into the destination cannot cover the shortfall.
+func (s *RoutingService) transferOption(destination string, local, quantity int64, others []*domain.InventoryItem) *domain.FulfillmentOption {
+	type source struct {
+		item *domain.InventoryItem
+		lane domain.TransferLane
+	}
+
+	var sources []source
+	for _, item := range others {
+		if lane, ok := s.matrix.Lane(item.Location, destination); ok {
+			sources = append(sources, source{item: item, lane: lane})
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if sources[i].lane.UnitCost != sources[j].lane.UnitCost {
+			return sources[i].lane.UnitCost < sources[j].lane.UnitCost
+		}
+		if sources[i].lane.TransitHours != sources[j].lane.TransitHours {
+			return sources[i].lane.TransitHours < sources[j].lane.TransitHours
+		}
+		return sources[i].item.Location < sources[j].item.Location
+	})
+
+	option := &domain.FulfillmentOption{Strategy: domain.FulfillTransfer, Shipments: 1}
+	remaining := quantity - local
+	for _, source := range sources {
+		if remaining == 0 {
+			break
+		}
+		take := min(source.item.AvailableQuantity(), remaining)
+		leg := domain.FulfillmentLeg{
+			Location:     source.item.Location,
+			Quantity:     take,
+			Cost:         float64(take) * source.lane.UnitCost,
+			TransitHours: source.lane.TransitHours,
+		}
+		option.Legs = append(option.Legs, leg)
+		option.Cost += leg.Cost
+		option.LeadHours = max(option.LeadHours, leg.TransitHours)
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil
+	}
+
+	// The order ships in full from the destination once the transfers arrive
+	option.Legs = append(option.Legs, domain.FulfillmentLeg{Location: destination, Quantity: quantity})
+	return option
+}
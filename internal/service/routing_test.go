@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestParseTransferLanes(t *testing.T) {
+	lanes, err := ParseTransferLanes("WH-A:WH-B:0.5:24,\n# comment\nWH-C:WH-B:1:6\n")
+	if err != nil {
+		t.Fatalf("ParseTransferLanes() error = %v", err)
+	}
+	if len(lanes) != 2 || lanes[0].From != "WH-A" || lanes[0].UnitCost != 0.5 || lanes[1].TransitHours != 6 {
+		t.Errorf("Unexpected lanes: %+v", lanes)
+	}
+
+	for _, spec := range []string{"WH-A:WH-B:1", "WH-A:WH-B:cheap:24", "WH-A:WH-B:1:soon"} {
+		if _, err := ParseTransferLanes(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+
+	for _, lanes := range [][]domain.TransferLane{
+		{{From: "WH-A", To: "WH-A"}},
+		{{From: "WH-A", To: "WH-B", UnitCost: -1}},
+		{{From: "WH-A", To: "WH-B"}, {From: "WH-A", To: "WH-B"}},
+	} {
+		if _, err := domain.NewTransferMatrix(lanes); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Expected a validation error for %+v, got %v", lanes, err)
+		}
+	}
+}
+
+func TestPlanFulfillment(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	inventory := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-a", ProductID: "prod-1", Quantity: 4, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-b", ProductID: "prod-1", Quantity: 10, Reserved: 2, Location: "WH-B"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-c", ProductID: "prod-1", Quantity: 3, Location: "WH-C"})
+
+	matrix, err := domain.NewTransferMatrix([]domain.TransferLane{
+		{From: "WH-B", To: "WH-A", UnitCost: 2, TransitHours: 48},
+		{From: "WH-C", To: "WH-A", UnitCost: 1, TransitHours: 12},
+	})
+	if err != nil {
+		t.Fatalf("NewTransferMatrix() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		splitCost    float64
+		quantity     int64
+		maxLeadHours float64
+		want         domain.FulfillmentStrategy
+		wantCost     float64
+		wantOptions  int
+	}{
+		{"Local stock suffices", 10, 4, 0, domain.FulfillLocal, 0, 1},
+		// Shortfall 2 comes from WH-C, the cheapest lane
+		{"Transfer is cheaper", 10, 6, 0, domain.FulfillTransfer, 2, 2},
+		{"Split is cheaper", 1, 6, 0, domain.FulfillSplit, 1, 2},
+		// Shortfall 6: 3 from WH-C at 1, 3 from WH-B at 2
+		{"Transfer over two lanes", 20, 10, 0, domain.FulfillTransfer, 9, 2},
+		{"Transfer too slow", 20, 10, 24, domain.FulfillSplit, 20, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRoutingService(inventory, matrix, RoutingPolicy{SplitShipmentCost: tt.splitCost})
+			plan, err := service.PlanFulfillment(ctx, "prod-1", "WH-A", tt.quantity, tt.maxLeadHours)
+			if err != nil {
+				t.Fatalf("PlanFulfillment() error = %v", err)
+			}
+			if plan.Strategy != tt.want || plan.Options[0].Cost != tt.wantCost || len(plan.Options) != tt.wantOptions {
+				t.Errorf("Got %s at %v with %d options, want %s at %v with %d", plan.Strategy, plan.Options[0].Cost, len(plan.Options), tt.want, tt.wantCost, tt.wantOptions)
+			}
+
+			// Transfers end with the full shipment from the destination
+			legs := plan.Options[0].Legs
+			if plan.Strategy == domain.FulfillTransfer {
+				if last := legs[len(legs)-1]; last.Location != "WH-A" || last.Quantity != tt.quantity {
+					t.Errorf("Expected a final shipment of %d from WH-A, got %+v", tt.quantity, last)
+				}
+				legs = legs[:len(legs)-1]
+			}
+			var total int64
+			for _, leg := range legs {
+				total += leg.Quantity
+			}
+			if plan.Strategy == domain.FulfillTransfer {
+				total += 4 // already at WH-A
+			}
+			if total != tt.quantity {
+				t.Errorf("Expected legs to cover %d units, got %d", tt.quantity, total)
+			}
+		})
+	}
+
+	service := NewRoutingService(inventory, nil, RoutingPolicy{})
+	if _, err := service.PlanFulfillment(ctx, "prod-1", "WH-A", 20, 0); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got %v", err)
+	}
+	plan, err := service.PlanFulfillment(ctx, "prod-1", "WH-A", 6, 0)
+	if err != nil || plan.Strategy != domain.FulfillSplit {
+		t.Errorf("Expected a split without lanes, got %+v, %v", plan, err)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// SaleService records sales reported by store tills. Tills may deliver a
+// sale late and more than once; each event ID decrements stock only once.
+type SaleService struct {
+	inventory    *InventoryService
+	saleRepo     repository.SaleRepository
+	consignments *ConsignmentService
+}
+
+// NewSaleService creates a new SaleService. Sales transfer the ownership of
+// the consignment stock they sell through consignments, unless nil.
+func NewSaleService(inventory *InventoryService, saleRepo repository.SaleRepository, consignments *ConsignmentService) *SaleService {
+	return &SaleService{
+		inventory:    inventory,
+		saleRepo:     saleRepo,
+		consignments: consignments,
+	}
+}
+
+// RecordSale takes a sale off the new stock of its store, with the event ID
+// as transaction reference, and records it in the same unit of work. It
+// reports true with the recorded sale when the event was recorded before,
+// without applying it again. A sale the store's stock on record cannot
+// cover is recorded as SHORT and leaves the stock unchanged; one at a store
+// in a stocktake is recorded as QUEUED and applied when the stocktake closes.
+// Applied and queued sales of consignment stock transfer its ownership in the
+// same unit of work; SHORT sales leave the consignments unchanged like the
+// stock.
+func (s *SaleService) RecordSale(ctx context.Context, sale *domain.Sale) (*domain.Sale, bool, error) {
+	if err := sale.Validate(time.Now()); err != nil {
+		return nil, false, err
+	}
+	if sale.Quantity == 0 {
+		sale.Quantity = 1
+	}
+
+	if recorded, err := s.saleRepo.GetByEventID(ctx, sale.EventID); err == nil {
+		return recorded, true, nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, false, err
+	}
+
+	if sale.ProductID == "" {
+		productID, err := s.inventory.ResolveSKU(ctx, sale.SKU)
+		if err != nil {
+			return nil, false, err
+		}
+		sale.ProductID = productID
+	}
+
+	err := s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.inventory.RemoveStockAt(ctx, sale.ProductID, sale.Location, domain.ConditionNew, sale.Quantity, sale.EventID)
+		switch {
+		case err == nil:
+			sale.Status = domain.SaleStatusApplied
+		case errors.Is(err, ErrMovementQueued):
+			sale.Status = domain.SaleStatusQueued
+		case errors.Is(err, domain.ErrInsufficientStock):
+			sale.Status = domain.SaleStatusShort
+		default:
+			return err
+		}
+		if s.consignments != nil && sale.Status != domain.SaleStatusShort {
+			if _, err := s.consignments.TransferOwnership(ctx, sale); err != nil {
+				return err
+			}
+		}
+		return s.saleRepo.Create(ctx, sale)
+	})
+	if errors.Is(err, domain.ErrDuplicateSale) {
+		// Delivered twice at once: the other delivery recorded it
+		recorded, err := s.saleRepo.GetByEventID(ctx, sale.EventID)
+		if err != nil {
+			return nil, false, err
+		}
+		return recorded, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record sale: %w", err)
+	}
+
+	if sale.Status == domain.SaleStatusShort {
+		slog.WarnContext(ctx, "sale exceeds stock on record", "event_id", sale.EventID,
+			"product_id", sale.ProductID, "location", sale.Location, "quantity", sale.Quantity)
+	}
+	return sale, false, nil
+}
+
+// ListSales lists sales in the order they happened
+func (s *SaleService) ListSales(ctx context.Context, filter domain.SaleFilter, limit, offset int) ([]*domain.Sale, error) {
+	sales, err := s.saleRepo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sales: %w", err)
+	}
+	return sales, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockSaleRepository implements SaleRepository for testing
+type MockSaleRepository struct {
+	sales map[string]*domain.Sale
+}
+
+func NewMockSaleRepository() *MockSaleRepository {
+	return &MockSaleRepository{sales: make(map[string]*domain.Sale)}
+}
+
+func (m *MockSaleRepository) Create(ctx context.Context, sale *domain.Sale) error {
+	if _, ok := m.sales[sale.EventID]; ok {
+		return domain.ErrDuplicateSale
+	}
+	sale.ReceivedAt = time.Now()
+	stored := *sale
+	m.sales[sale.EventID] = &stored
+	return nil
+}
+
+func (m *MockSaleRepository) GetByEventID(ctx context.Context, eventID string) (*domain.Sale, error) {
+	sale, ok := m.sales[eventID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *sale
+	return &copied, nil
+}
+
+func (m *MockSaleRepository) List(ctx context.Context, filter domain.SaleFilter, limit, offset int) ([]*domain.Sale, error) {
+	var sales []*domain.Sale
+	for _, sale := range m.sales {
+		if filter.Location == "" || sale.Location == filter.Location {
+			sales = append(sales, sale)
+		}
+	}
+	return sales, nil
+}
+
+func TestRecordSale(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	saleRepo := NewMockSaleRepository()
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 2, Location: "STORE-1"})
+
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)))
+	service := NewSaleService(inventory, saleRepo, nil)
+
+	// A sale made offline an hour ago decrements one unit by SKU
+	sale := &domain.Sale{EventID: "till-1/42", SKU: "CON001", Location: "STORE-1", OccurredAt: time.Now().Add(-time.Hour)}
+	recorded, replayed, err := service.RecordSale(ctx, sale)
+	if err != nil {
+		t.Fatalf("RecordSale() error = %v", err)
+	}
+	if replayed || recorded.Status != domain.SaleStatusApplied || recorded.ProductID != "prod-1" || recorded.Quantity != 1 {
+		t.Errorf("Unexpected sale: %+v (replayed %v)", recorded, replayed)
+	}
+
+	// Delivering it again changes nothing
+	again := &domain.Sale{EventID: "till-1/42", SKU: "CON001", Location: "STORE-1", OccurredAt: sale.OccurredAt}
+	if _, replayed, err := service.RecordSale(ctx, again); err != nil || !replayed {
+		t.Fatalf("Expected a replayed sale, got replayed %v, error %v", replayed, err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 1 {
+		t.Errorf("Expected 1 unit left, got %d", quantity)
+	}
+
+	// A sale beyond the stock on record is recorded short, stock unchanged
+	short := &domain.Sale{EventID: "till-1/43", ProductID: "prod-1", Location: "STORE-1", Quantity: 3, OccurredAt: time.Now()}
+	if recorded, _, err := service.RecordSale(ctx, short); err != nil || recorded.Status != domain.SaleStatusShort {
+		t.Fatalf("Expected a short sale, got %+v, error %v", recorded, err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 1 {
+		t.Errorf("Expected the short sale to leave 1 unit, got %d", quantity)
+	}
+	if len(saleRepo.sales) != 2 {
+		t.Errorf("Expected 2 recorded sales, got %d", len(saleRepo.sales))
+	}
+
+	future := &domain.Sale{EventID: "till-1/44", ProductID: "prod-1", Location: "STORE-1", OccurredAt: time.Now().Add(time.Hour)}
+	if _, _, err := service.RecordSale(ctx, future); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a future sale, got %v", err)
+	}
+	unknown := &domain.Sale{EventID: "till-1/45", SKU: "NOPE", Location: "STORE-1", OccurredAt: time.Now()}
+	if _, _, err := service.RecordSale(ctx, unknown); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown SKU, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// SerialService handles the lifecycle of individually serialized units
+type SerialService struct {
+	productRepo repository.ProductRepository
+	serialRepo  repository.SerialUnitRepository
+}
+
+// NewSerialService creates a new SerialService
+func NewSerialService(
+	productRepo repository.ProductRepository,
+	serialRepo repository.SerialUnitRepository,
+) *SerialService {
+	return &SerialService{
+		productRepo: productRepo,
+		serialRepo:  serialRepo,
+	}
+}
+
+// RegisterSerial registers a new in-stock serial unit for a product
+func (s *SerialService) RegisterSerial(ctx context.Context, productID, serialNumber string) (*domain.SerialUnit, error) {
+	if serialNumber == "" {
+		return nil, domain.NewValidationError("serial number cannot be empty")
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	unit := &domain.SerialUnit{
+		ProductID:    productID,
+		SerialNumber: serialNumber,
+		Status:       domain.SerialStatusInStock,
+	}
+
+	if err := s.serialRepo.Create(ctx, unit); err != nil {
+		return nil, fmt.Errorf("failed to register serial unit: %w", err)
+	}
+
+	return unit, nil
+}
+
+// GetSerial retrieves a serial unit of a product
+func (s *SerialService) GetSerial(ctx context.Context, productID, serialNumber string) (*domain.SerialUnit, error) {
+	unit, err := s.serialRepo.GetBySerial(ctx, productID, serialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial unit: %w", err)
+	}
+	return unit, nil
+}
+
+// ListSerials lists the serial units of a product with pagination
+func (s *SerialService) ListSerials(ctx context.Context, productID string, limit, offset int) ([]*domain.SerialUnit, error) {
+	units, err := s.serialRepo.ListByProductID(ctx, productID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serial units: %w", err)
+	}
+	return units, nil
+}
+
+// TransitionSerial moves a serial unit to a new status, validating the
+// transition in the domain layer and recording it in the unit's history
+func (s *SerialService) TransitionSerial(ctx context.Context, productID, serialNumber string, status domain.SerialStatus, reference, notes string) (*domain.SerialUnit, error) {
+	unit, err := s.serialRepo.GetBySerial(ctx, productID, serialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial unit: %w", err)
+	}
+
+	event, err := unit.TransitionTo(status, reference, notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.serialRepo.UpdateStatus(ctx, unit, event); err != nil {
+		return nil, fmt.Errorf("failed to update serial unit: %w", err)
+	}
+
+	return unit, nil
+}
+
+// GetSerialHistory retrieves the status history of a serial unit
+func (s *SerialService) GetSerialHistory(ctx context.Context, productID, serialNumber string, limit, offset int) ([]*domain.SerialUnitEvent, error) {
+	unit, err := s.serialRepo.GetBySerial(ctx, productID, serialNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial unit: %w", err)
+	}
+
+	events, err := s.serialRepo.GetHistory(ctx, unit.ID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serial unit history: %w", err)
+	}
+	return events, nil
+}
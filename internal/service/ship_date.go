@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// WithShipDates makes availability checks estimate when a sufficient quantity
+// ships, from the cutoff times, lead times and operating calendars of the
+// warehouses holding it. defaults applies to warehouses that do not set their
+// own, and its calendar also to the lead times of replenishment.
+func WithShipDates(defaults domain.ShipSchedule) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.shipDefaults = &defaults
+	}
+}
+
+// shipSchedules resolves the ship schedules of locations once per
+// availability check
+type shipSchedules struct {
+	service   *InventoryService
+	schedules map[string]domain.ShipSchedule
+}
+
+func (s *InventoryService) newShipSchedules() *shipSchedules {
+	return &shipSchedules{service: s, schedules: make(map[string]domain.ShipSchedule)}
+}
+
+// get returns the schedule of a location, the defaults for locations without
+// a registered warehouse. Without ship dates configured the defaults ship
+// the same day and operate every day.
+func (c *shipSchedules) get(ctx context.Context, location string) (domain.ShipSchedule, error) {
+	if schedule, ok := c.schedules[location]; ok {
+		return schedule, nil
+	}
+
+	var schedule domain.ShipSchedule
+	if c.service.shipDefaults != nil {
+		schedule = *c.service.shipDefaults
+	}
+	if c.service.warehouseRepo != nil {
+		warehouse, err := c.service.warehouseRepo.GetByCode(ctx, location)
+		if err == nil && warehouse != nil {
+			if schedule, err = warehouse.ShipSchedule(schedule); err != nil {
+				return domain.ShipSchedule{}, fmt.Errorf("warehouse %s: %w", location, err)
+			}
+		}
+	}
+
+	c.schedules[location] = schedule
+	return schedule, nil
+}
+
+// estimateShipDate sets the date the requested quantity ships in full when
+// ordered at orderedAt. Locations are drawn on by earliest ship date, then by
+// most available stock, and the order ships when the last one needed does.
+// The stock of a location counts every condition unless the check asked for one.
+func (s *InventoryService) estimateShipDate(ctx context.Context, result *AvailabilityResult, orderedAt time.Time, schedules *shipSchedules) error {
+	items, err := s.inventoryRepo.ListByProductID(ctx, result.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get inventory: %w", err)
+	}
+
+	type source struct {
+		location  string
+		available int64
+		shipDate  time.Time
+	}
+	var sources []source
+	byLocation := make(map[string]int)
+	for _, item := range items {
+		if item.AvailableQuantity() <= 0 || (result.Condition != "" && item.Condition != result.Condition) {
+			continue
+		}
+		if i, ok := byLocation[item.Location]; ok {
+			sources[i].available += item.AvailableQuantity()
+			continue
+		}
+		byLocation[item.Location] = len(sources)
+		schedule, err := schedules.get(ctx, item.Location)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source{
+			location:  item.Location,
+			available: item.AvailableQuantity(),
+			shipDate:  schedule.ShipDate(orderedAt),
+		})
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		if !sources[i].shipDate.Equal(sources[j].shipDate) {
+			return sources[i].shipDate.Before(sources[j].shipDate)
+		}
+		if sources[i].available != sources[j].available {
+			return sources[i].available > sources[j].available
+		}
+		return sources[i].location < sources[j].location
+	})
+
+	remaining := result.Requested
+	for _, source := range sources {
+		result.ShipsFrom = append(result.ShipsFrom, source.location)
+		remaining -= source.available
+		if remaining <= 0 {
+			result.EstimatedShipDate = source.shipDate.Format(time.DateOnly)
+			return nil
+		}
+	}
+
+	// Stock moved since availability was read
+	result.ShipsFrom = nil
+	return nil
+}
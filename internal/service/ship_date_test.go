@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockWarehouseRepository implements WarehouseRepository interface for testing
+type MockWarehouseRepository struct {
+	warehouses map[string]*domain.Warehouse
+}
+
+func NewMockWarehouseRepository() *MockWarehouseRepository {
+	return &MockWarehouseRepository{warehouses: make(map[string]*domain.Warehouse)}
+}
+
+func (m *MockWarehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	if warehouse.ID == "" {
+		warehouse.ID = fmt.Sprintf("wh-%d", len(m.warehouses)+1)
+	}
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *MockWarehouseRepository) GetByID(ctx context.Context, id string) (*domain.Warehouse, error) {
+	if warehouse, ok := m.warehouses[id]; ok {
+		return warehouse, nil
+	}
+	return nil, fmt.Errorf("warehouse %w", domain.ErrNotFound)
+}
+
+func (m *MockWarehouseRepository) GetByCode(ctx context.Context, code string) (*domain.Warehouse, error) {
+	for _, warehouse := range m.warehouses {
+		if warehouse.Code == code {
+			return warehouse, nil
+		}
+	}
+	return nil, fmt.Errorf("warehouse %w", domain.ErrNotFound)
+}
+
+func (m *MockWarehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	if _, ok := m.warehouses[warehouse.ID]; !ok {
+		return fmt.Errorf("warehouse %w", domain.ErrNotFound)
+	}
+	m.warehouses[warehouse.ID] = warehouse
+	return nil
+}
+
+func (m *MockWarehouseRepository) List(ctx context.Context, limit, offset int) ([]*domain.Warehouse, error) {
+	var warehouses []*domain.Warehouse
+	for _, warehouse := range m.warehouses {
+		warehouses = append(warehouses, warehouse)
+	}
+	return warehouses, nil
+}
+
+func TestEstimateShipDate(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	warehouseRepo := NewMockWarehouseRepository()
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository(),
+		WithWarehouseRepository(warehouseRepo),
+		WithShipDates(domain.ShipSchedule{Cutoff: 14 * time.Hour, LeadTimeDays: 1}))
+	ctx := context.Background()
+
+	sameDay := 0
+	warehouseRepo.Create(ctx, &domain.Warehouse{Code: "WH-B", Name: "B", CutoffTime: "18:00", LeadTimeDays: &sameDay})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-a", ProductID: "prod-1", Quantity: 5, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-b", ProductID: "prod-1", Quantity: 3, Location: "WH-B"})
+
+	orderedAt := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		requested int64
+		wantDate  string
+		wantFrom  []string
+	}{
+		{name: "Covered by the earliest warehouse", requested: 3, wantDate: "2026-03-10", wantFrom: []string{"WH-B"}},
+		{name: "Waits for the last warehouse needed", requested: 6, wantDate: "2026-03-12", wantFrom: []string{"WH-B", "WH-A"}},
+		{name: "More than the locations hold", requested: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &AvailabilityResult{ProductID: "prod-1", Requested: tt.requested}
+			if err := service.estimateShipDate(ctx, result, orderedAt, service.newShipSchedules()); err != nil {
+				t.Fatalf("estimateShipDate() error = %v", err)
+			}
+			if result.EstimatedShipDate != tt.wantDate {
+				t.Errorf("Expected ship date %q, got %q", tt.wantDate, result.EstimatedShipDate)
+			}
+			if fmt.Sprint(result.ShipsFrom) != fmt.Sprint(tt.wantFrom) {
+				t.Errorf("Expected ships from %v, got %v", tt.wantFrom, result.ShipsFrom)
+			}
+		})
+	}
+
+	results, err := service.CheckAvailability(ctx, []AvailabilityCheck{
+		{ProductID: "prod-1", Quantity: 2},
+		{ProductID: "prod-1", Quantity: 20},
+	})
+	if err != nil {
+		t.Fatalf("CheckAvailability() error = %v", err)
+	}
+	if results[0].EstimatedShipDate == "" {
+		t.Error("Expected a ship date for a sufficient check")
+	}
+	if results[1].EstimatedShipDate != "" {
+		t.Errorf("Expected no ship date for an insufficient check, got %s", results[1].EstimatedShipDate)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MaxSimulationOperations bounds the number of operations in one simulation
+const MaxSimulationOperations = 1000
+
+// SimulationService evaluates hypothetical stock movements against current
+// inventory without committing anything
+type SimulationService struct {
+	inventory *InventoryService
+}
+
+// NewSimulationService creates a new SimulationService
+func NewSimulationService(inventory *InventoryService) *SimulationService {
+	return &SimulationService{inventory: inventory}
+}
+
+// Simulate applies the operations in order to a copy of current availability
+// and returns the resulting availability per product, in order of first
+// appearance. Orders and receipts resolve their location like the real stock
+// operations, so a location the product is not stocked at is an error.
+func (s *SimulationService) Simulate(ctx context.Context, operations []domain.SimulationOperation) (*domain.SimulationResult, error) {
+	if len(operations) == 0 {
+		return nil, domain.NewValidationError("at least one operation is required")
+	}
+	if len(operations) > MaxSimulationOperations {
+		return nil, domain.NewValidationError("at most %d operations can be simulated at once", MaxSimulationOperations)
+	}
+	for i := range operations {
+		if err := operations[i].Validate(); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+	}
+
+	result := &domain.SimulationResult{Feasible: true}
+	products := make(map[string]*domain.ProductSimulation)
+	available := make(map[string]int64) // by inventory ID
+
+	for i, operation := range operations {
+		productID := operation.ProductID
+		if productID == "" {
+			resolved, err := s.inventory.ResolveSKU(ctx, operation.SKU)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
+			}
+			productID = resolved
+		}
+
+		product, ok := products[productID]
+		if !ok {
+			var err error
+			if product, err = s.loadProduct(ctx, productID, available); err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
+			}
+			products[productID] = product
+			result.Products = append(result.Products, product)
+		}
+
+		inventory, err := s.inventory.resolveInventory(ctx, productID, operation.Location)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: failed to get inventory: %w", i+1, err)
+		}
+		if inventory == nil {
+			return nil, fmt.Errorf("operation %d: inventory %w", i+1, domain.ErrNotFound)
+		}
+
+		switch operation.Type {
+		case domain.SimulationReceipt:
+			available[inventory.ID] += operation.Quantity
+			product.Received += operation.Quantity
+			product.ProjectedAvailable += operation.Quantity
+		case domain.SimulationOrder:
+			filled := min(operation.Quantity, available[inventory.ID])
+			available[inventory.ID] -= filled
+			product.Ordered += operation.Quantity
+			product.ProjectedAvailable -= filled
+			if shortfall := operation.Quantity - filled; shortfall > 0 {
+				product.Shortfall += shortfall
+				result.Feasible = false
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// loadProduct seeds the simulation of a product with the current available
+// quantity of each of its locations
+func (s *SimulationService) loadProduct(ctx context.Context, productID string, available map[string]int64) (*domain.ProductSimulation, error) {
+	existing, err := s.inventory.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("product %w", domain.ErrNotFound)
+	}
+
+	items, err := s.inventory.ListStockByLocation(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &domain.ProductSimulation{ProductID: productID}
+	for _, item := range items {
+		available[item.ID] = item.AvailableQuantity()
+		product.CurrentAvailable += item.AvailableQuantity()
+	}
+	product.ProjectedAvailable = product.CurrentAvailable
+
+	return product, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestSimulate(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	inventory := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository())
+	service := NewSimulationService(inventory)
+	ctx := context.Background()
+
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP001", Price: 1500})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Mouse", SKU: "MOU001", Price: 25})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1a", ProductID: "prod-1", Quantity: 10, Reserved: 2, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1b", ProductID: "prod-1", Quantity: 5, Location: "WH-B"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2a", ProductID: "prod-2", Quantity: 100, Location: "WH-A"})
+
+	result, err := service.Simulate(ctx, []domain.SimulationOperation{
+		{Type: domain.SimulationOrder, ProductID: "prod-1", Location: "WH-A", Quantity: 6},
+		{Type: domain.SimulationReceipt, SKU: "LAP001", Location: "WH-A", Quantity: 3},
+		{Type: domain.SimulationOrder, ProductID: "prod-1", Location: "WH-A", Quantity: 8},
+		{Type: domain.SimulationOrder, SKU: "MOU001", Location: "WH-A", Quantity: 40},
+	})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+
+	if result.Feasible {
+		t.Error("Expected the simulation to be infeasible")
+	}
+	if len(result.Products) != 2 {
+		t.Fatalf("Expected 2 products, got %d", len(result.Products))
+	}
+
+	laptop := result.Products[0]
+	if laptop.CurrentAvailable != 13 || laptop.Ordered != 14 || laptop.Received != 3 || laptop.Shortfall != 3 || laptop.ProjectedAvailable != 5 {
+		t.Errorf("Unexpected laptop simulation: %+v", laptop)
+	}
+	mouse := result.Products[1]
+	if mouse.Shortfall != 0 || mouse.ProjectedAvailable != 60 {
+		t.Errorf("Unexpected mouse simulation: %+v", mouse)
+	}
+
+	// Nothing was committed
+	item, _ := inventoryRepo.GetByID(ctx, "inv-1a")
+	if item.Quantity != 10 || item.Reserved != 2 {
+		t.Errorf("Expected inventory to be untouched, got %d/%d", item.Quantity, item.Reserved)
+	}
+}
+
+func TestSimulateValidation(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	service := NewSimulationService(NewInventoryService(productRepo, NewMockInventoryRepository(), NewMockTransactionRepository()))
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		operations []domain.SimulationOperation
+		wantErr    error
+	}{
+		{"No operations", nil, domain.ErrValidation},
+		{"Unknown type", []domain.SimulationOperation{{Type: "RETURN", ProductID: "prod-1", Quantity: 1}}, domain.ErrValidation},
+		{"No product", []domain.SimulationOperation{{Type: domain.SimulationOrder, Quantity: 1}}, domain.ErrValidation},
+		{"Unknown product", []domain.SimulationOperation{{Type: domain.SimulationOrder, ProductID: "missing", Quantity: 1}}, domain.ErrNotFound},
+		{"Unknown SKU", []domain.SimulationOperation{{Type: domain.SimulationOrder, SKU: "missing", Quantity: 1}}, domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Simulate(ctx, tt.operations); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Simulate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/httpclient"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// StockAlertNotifier is told about the alerts an evaluation raised or
+// resolved
+type StockAlertNotifier interface {
+	NotifyStockAlerts(ctx context.Context, alerts []*domain.StockAlert) error
+}
+
+// StockAlertService raises low-stock alerts from the projected stockout of
+// every inventory row rather than a fixed threshold: a row alerts when, at
+// its sales velocity, it runs out before stock ordered now would arrive, and
+// stays quiet while it does not, even below its reorder level. Rows without
+// sales fall back to their reorder level.
+type StockAlertService struct {
+	inventory    *InventoryService
+	orderRepo    repository.PurchaseOrderRepository
+	alertRepo    repository.StockAlertRepository
+	leadTimeDays int
+	notifier     StockAlertNotifier
+}
+
+// NewStockAlertService creates a new StockAlertService. leadTimeDays is the
+// supplier lead time in operating days of the receiving warehouse, whose
+// calendar inventory resolves; without inventory every day counts.
+// notifier, if not nil, is told about raised and resolved alerts.
+func NewStockAlertService(inventory *InventoryService, orderRepo repository.PurchaseOrderRepository, alertRepo repository.StockAlertRepository, leadTimeDays int, notifier StockAlertNotifier) *StockAlertService {
+	return &StockAlertService{
+		inventory:    inventory,
+		orderRepo:    orderRepo,
+		alertRepo:    alertRepo,
+		leadTimeDays: leadTimeDays,
+		notifier:     notifier,
+	}
+}
+
+// StockAlertRun is the outcome of one alert evaluation
+type StockAlertRun struct {
+	Checked  int                  `json:"checked"`
+	Raised   []*domain.StockAlert `json:"raised"`
+	Resolved []*domain.StockAlert `json:"resolved"`
+}
+
+// Evaluate checks every reorder candidate: rows that need an alert get one
+// raised, or their open alert refreshed, and open alerts of rows that no
+// longer need one are resolved. A failing notifier is logged; the alerts are
+// stored either way.
+func (s *StockAlertService) Evaluate(ctx context.Context) (*StockAlertRun, error) {
+	now := time.Now()
+	candidates, err := s.orderRepo.ReorderCandidates(ctx, now.Add(-stockoutVelocityWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate stock alerts: %w", err)
+	}
+
+	open, err := s.alertRepo.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open stock alerts: %w", err)
+	}
+	openByInventory := make(map[string]*domain.StockAlert, len(open))
+	for _, alert := range open {
+		openByInventory[alert.InventoryID] = alert
+	}
+
+	run := &StockAlertRun{Checked: len(candidates), Raised: []*domain.StockAlert{}, Resolved: []*domain.StockAlert{}}
+	alerting := make(map[string]bool)
+	var schedules *shipSchedules
+	if s.inventory != nil {
+		schedules = s.inventory.newShipSchedules()
+	}
+	for _, candidate := range candidates {
+		leadTime := s.leadTimeDays
+		if schedules != nil {
+			schedule, err := schedules.get(ctx, candidate.Location)
+			if err != nil {
+				return nil, err
+			}
+			leadTime = schedule.LeadTime(now, s.leadTimeDays)
+		}
+
+		alert := domain.EvaluateStockAlert(candidate, stockoutVelocityWindow, leadTime, now)
+		if alert == nil {
+			continue
+		}
+		alerting[candidate.InventoryID] = true
+
+		if existing, ok := openByInventory[candidate.InventoryID]; ok {
+			alert.ID, alert.RaisedAt = existing.ID, existing.RaisedAt
+			if err := s.alertRepo.Refresh(ctx, alert); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		raised, err := s.alertRepo.Raise(ctx, alert)
+		if err != nil {
+			return nil, err
+		}
+		if raised {
+			run.Raised = append(run.Raised, alert)
+		}
+	}
+
+	for _, alert := range open {
+		if alerting[alert.InventoryID] {
+			continue
+		}
+		resolved, err := s.alertRepo.Resolve(ctx, alert.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if resolved {
+			alert.Status = domain.StockAlertStatusResolved
+			alert.ResolvedAt = &now
+			run.Resolved = append(run.Resolved, alert)
+		}
+	}
+
+	if len(run.Raised) > 0 || len(run.Resolved) > 0 {
+		slog.InfoContext(ctx, "stock alerts evaluated", "checked", run.Checked, "raised", len(run.Raised), "resolved", len(run.Resolved))
+		if s.notifier != nil {
+			changed := append(append([]*domain.StockAlert{}, run.Raised...), run.Resolved...)
+			if err := s.notifier.NotifyStockAlerts(ctx, changed); err != nil {
+				slog.ErrorContext(ctx, "failed to notify stock alerts", "error", err)
+			}
+		}
+	}
+
+	return run, nil
+}
+
+// ListAlerts lists a page of stock alerts, newest first, optionally only
+// those in the given status
+func (s *StockAlertService) ListAlerts(ctx context.Context, status domain.StockAlertStatus, limit, offset int) ([]*domain.StockAlert, error) {
+	switch status {
+	case "", domain.StockAlertStatusOpen, domain.StockAlertStatusResolved:
+	default:
+		return nil, domain.NewValidationError("unknown stock alert status %q", status)
+	}
+
+	alerts, err := s.alertRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock alerts: %w", err)
+	}
+	if alerts == nil {
+		alerts = []*domain.StockAlert{}
+	}
+	return alerts, nil
+}
+
+// Run evaluates the stock alerts every interval until ctx is cancelled
+func (s *StockAlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if _, err := s.Evaluate(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to evaluate stock alerts", "error", err)
+			}
+		}
+	}
+}
+
+// WebhookStockAlertNotifier notifies stock alerts by POSTing them as JSON,
+// {"alerts": [...]}, to an endpoint. Any 2xx response acknowledges them.
+type WebhookStockAlertNotifier struct {
+	url    string
+	client httpclient.Doer
+}
+
+// NewWebhookStockAlertNotifier creates a notifier for the webhook URL
+func NewWebhookStockAlertNotifier(url string, client httpclient.Doer) *WebhookStockAlertNotifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookStockAlertNotifier{url: url, client: client}
+}
+
+// NotifyStockAlerts implements StockAlertNotifier
+func (n *WebhookStockAlertNotifier) NotifyStockAlerts(ctx context.Context, alerts []*domain.StockAlert) error {
+	payload, err := json.Marshal(map[string][]*domain.StockAlert{"alerts": alerts})
+	if err != nil {
+		return fmt.Errorf("failed to encode stock alerts: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build stock alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("stock alert webhook %s: %w", n.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("stock alert webhook %s returned status %d", n.url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockStockAlertRepository implements StockAlertRepository interface
+type MockStockAlertRepository struct {
+	alerts []*domain.StockAlert
+}
+
+func (m *MockStockAlertRepository) Raise(ctx context.Context, alert *domain.StockAlert) (bool, error) {
+	for _, existing := range m.alerts {
+		if existing.InventoryID == alert.InventoryID && existing.Status == domain.StockAlertStatusOpen {
+			return false, nil
+		}
+	}
+	alert.ID = alert.InventoryID + "-alert"
+	alert.Status = domain.StockAlertStatusOpen
+	alert.RaisedAt = time.Now()
+	stored := *alert
+	m.alerts = append(m.alerts, &stored)
+	return true, nil
+}
+
+func (m *MockStockAlertRepository) Refresh(ctx context.Context, alert *domain.StockAlert) error {
+	for _, existing := range m.alerts {
+		if existing.ID == alert.ID && existing.Status == domain.StockAlertStatusOpen {
+			existing.Kind, existing.Available, existing.OnOrder = alert.Kind, alert.Available, alert.OnOrder
+			existing.ProjectedStockoutAt = alert.ProjectedStockoutAt
+		}
+	}
+	return nil
+}
+
+func (m *MockStockAlertRepository) Resolve(ctx context.Context, id string, at time.Time) (bool, error) {
+	for _, existing := range m.alerts {
+		if existing.ID == id && existing.Status == domain.StockAlertStatusOpen {
+			existing.Status = domain.StockAlertStatusResolved
+			existing.ResolvedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockStockAlertRepository) ListOpen(ctx context.Context) ([]*domain.StockAlert, error) {
+	return m.List(ctx, domain.StockAlertStatusOpen, len(m.alerts), 0)
+}
+
+func (m *MockStockAlertRepository) List(ctx context.Context, status domain.StockAlertStatus, limit, offset int) ([]*domain.StockAlert, error) {
+	var result []*domain.StockAlert
+	for _, alert := range m.alerts {
+		if status == "" || alert.Status == status {
+			copied := *alert
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+// recordingStockAlertNotifier records the alerts it is notified about
+type recordingStockAlertNotifier struct {
+	notified []*domain.StockAlert
+	err      error
+}
+
+func (n *recordingStockAlertNotifier) NotifyStockAlerts(ctx context.Context, alerts []*domain.StockAlert) error {
+	n.notified = append(n.notified, alerts...)
+	return n.err
+}
+
+func TestStockAlerts(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := NewMockPurchaseOrderRepository(
+		// Sells 10 a day: runs out in 5 days, within the 7 day lead time,
+		// although well above its reorder level
+		domain.ReorderCandidate{ProductID: "prod-1", InventoryID: "inv-1", Location: "WH-A", Available: 50, ReorderLevel: 10, Outbound: 300},
+		// Below its reorder level, but selling 1 a day lasts 8 days
+		domain.ReorderCandidate{ProductID: "prod-2", InventoryID: "inv-2", Location: "WH-A", Available: 5, OnOrder: 3, ReorderLevel: 10, Outbound: 30},
+		// No sales, but below its reorder level
+		domain.ReorderCandidate{ProductID: "prod-3", InventoryID: "inv-3", Location: "WH-A", Available: 3, ReorderLevel: 10},
+		// No sales and above its reorder level
+		domain.ReorderCandidate{ProductID: "prod-4", InventoryID: "inv-4", Location: "WH-A", Available: 30, ReorderLevel: 10},
+	)
+	alertRepo := &MockStockAlertRepository{}
+	notifier := &recordingStockAlertNotifier{}
+	service := NewStockAlertService(nil, orderRepo, alertRepo, 7, notifier)
+
+	run, err := service.Evaluate(ctx)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if run.Checked != 4 || len(run.Raised) != 2 || len(run.Resolved) != 0 {
+		t.Fatalf("Expected 2 of 4 rows to alert, got %+v", run)
+	}
+	if a := run.Raised[0]; a.InventoryID != "inv-1" || a.Kind != domain.StockAlertPredictedStockout || a.ProjectedStockoutAt == nil || a.DailyVelocity != 10 {
+		t.Errorf("Expected inv-1 to be predicted to run out, got %+v", a)
+	}
+	if a := run.Raised[1]; a.InventoryID != "inv-3" || a.Kind != domain.StockAlertBelowReorderLevel || a.ProjectedStockoutAt != nil {
+		t.Errorf("Expected inv-3 to be below its reorder level, got %+v", a)
+	}
+	if len(notifier.notified) != 2 {
+		t.Errorf("Expected the raised alerts to be notified, got %d", len(notifier.notified))
+	}
+
+	// Still alerting rows keep their open alert; restocked ones resolve
+	orderRepo.candidates[0].OnOrder = 200
+	orderRepo.candidates[2].Available = 2
+	notifier.err = errors.New("webhook down")
+	run, err = service.Evaluate(ctx)
+	if err != nil {
+		t.Fatalf("Evaluate() with a failing notifier error = %v", err)
+	}
+	if len(run.Raised) != 0 || len(run.Resolved) != 1 || run.Resolved[0].InventoryID != "inv-1" || run.Resolved[0].ResolvedAt == nil {
+		t.Errorf("Expected only inv-1 to resolve, got %+v", run)
+	}
+
+	open, err := service.ListAlerts(ctx, domain.StockAlertStatusOpen, 10, 0)
+	if err != nil {
+		t.Fatalf("ListAlerts() error = %v", err)
+	}
+	if len(open) != 1 || open[0].InventoryID != "inv-3" || open[0].Available != 2 {
+		t.Errorf("Expected the refreshed inv-3 alert to stay open, got %+v", open)
+	}
+	if _, err := service.ListAlerts(ctx, "SNOOZED", 10, 0); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for an unknown status, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MaxStockBatchItems bounds the number of items in one stock batch
+const MaxStockBatchItems = 500
+
+// stockBatchNotes are the transaction notes of each batch operation, matching
+// those of the single-item operations
+var stockBatchNotes = map[domain.StockOperation]string{
+	domain.StockAdd:       "Stock addition",
+	domain.StockRemove:    "Stock removal",
+	domain.StockReserve:   "Stock reservation",
+	domain.StockUnreserve: "Stock unreservation",
+}
+
+// ApplyStockBatch applies a list of stock operations all-or-nothing: either
+// every item is committed with its transaction or none is. Items are applied
+// in order, so a later item sees the effect of earlier ones on the same
+// inventory row. Errors name the failing item by its 1-based position.
+//
+// Additions and removals at a location with an open stocktake are rejected
+// in every freeze mode, since a batch cannot be partly queued. Batches write
+// directly to the database and bypass write batching. With lots tracked,
+// removals take their units from the lots expiring first.
+func (s *InventoryService) ApplyStockBatch(ctx context.Context, items []domain.StockBatchItem) ([]*domain.Transaction, error) {
+	if len(items) == 0 {
+		return nil, domain.NewValidationError("at least one item is required")
+	}
+	if len(items) > MaxStockBatchItems {
+		return nil, domain.NewValidationError("at most %d items can be applied at once", MaxStockBatchItems)
+	}
+	for i := range items {
+		if err := items[i].Validate(); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+	}
+
+	changes := make([]*domain.StockBatchChange, 0, len(items))
+	transactions := make([]*domain.Transaction, 0, len(items))
+	staged := make(map[string]*domain.InventoryItem) // by inventory ID
+	reserved := make(map[string]int64)               // by product ID and reference
+
+	for i, item := range items {
+		inventory, err := s.resolveInventory(ctx, item.ProductID, item.Location)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: failed to get inventory: %w", i+1, err)
+		}
+		if inventory == nil {
+			return nil, fmt.Errorf("item %d: inventory %w", i+1, domain.ErrNotFound)
+		}
+
+		current, ok := staged[inventory.ID]
+		if !ok {
+			copied := *inventory
+			current = &copied
+			staged[inventory.ID] = current
+		}
+
+		change := &domain.StockBatchChange{InventoryID: inventory.ID}
+		movement := StockMovement{ProductID: item.ProductID, Location: inventory.Location, Type: item.Operation.TransactionType(), Quantity: item.Quantity, Reference: item.Reference}
+
+		switch item.Operation {
+		case domain.StockAdd:
+			if err := s.rejectFrozen(ctx, inventory.Location); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			change.QuantityDelta = item.Quantity
+		case domain.StockRemove:
+			if err := s.rejectFrozen(ctx, inventory.Location); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			if current.AvailableQuantity() < item.Quantity {
+				s.metrics.recordOversell(ctx, "batch")
+				return nil, fmt.Errorf("item %d: %w available", i+1, domain.ErrInsufficientStock)
+			}
+			if err := s.approveMovement(ctx, movement); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			change.QuantityDelta = -item.Quantity
+		case domain.StockReserve:
+			if err := s.checkReleased(ctx, item.ProductID); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			key := item.ProductID + "|" + item.Reference
+			if err := s.checkReservationQuota(ctx, item.ProductID, item.Reference, reserved[key]+item.Quantity); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			reserved[key] += item.Quantity
+			if current.AvailableQuantity() < item.Quantity {
+				s.metrics.recordOversell(ctx, "batch")
+				return nil, fmt.Errorf("item %d: %w available for reservation", i+1, domain.ErrInsufficientStock)
+			}
+			change.ReservedDelta = item.Quantity
+		case domain.StockUnreserve:
+			if current.Reserved < item.Quantity {
+				return nil, fmt.Errorf("item %d: %w reserved", i+1, domain.ErrInsufficientStock)
+			}
+			change.ReservedDelta = -item.Quantity
+		}
+
+		current.Quantity += change.QuantityDelta
+		current.Reserved += change.ReservedDelta
+
+		change.Transaction = &domain.Transaction{
+			InventoryID: inventory.ID,
+			ProductID:   item.ProductID,
+			Type:        movement.Type,
+			Quantity:    item.Quantity,
+			Reference:   item.Reference,
+			Notes:       stockBatchNotes[item.Operation],
+			UnitCost:    item.UnitCost,
+		}
+		changes = append(changes, change)
+		transactions = append(transactions, change.Transaction)
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.inventoryRepo.ApplyBatch(ctx, changes); err != nil {
+			return fmt.Errorf("failed to apply stock batch: %w", err)
+		}
+		if s.lotRepo == nil {
+			return nil
+		}
+		return s.consumeBatchLots(ctx, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	whenCommitted(ctx, func(ctx context.Context) {
+		if s.availability != nil {
+			for i, change := range changes {
+				s.availability.ApplyDelta(items[i].ProductID, change.QuantityDelta, change.ReservedDelta)
+			}
+		}
+		s.notifyTransactions(ctx, transactions...)
+	})
+
+	return transactions, nil
+}
+
+// consumeBatchLots takes the units of the removals of an applied batch off
+// the lots of their inventory rows, in the order of the batch, first
+// expiring first. The rows are locked by the batch.
+func (s *InventoryService) consumeBatchLots(ctx context.Context, changes []*domain.StockBatchChange) error {
+	removes := make(map[string]bool) // by inventory ID
+	for _, change := range changes {
+		if change.QuantityDelta < 0 {
+			removes[change.InventoryID] = true
+		}
+	}
+
+	// Work back from the quantity of each row after the batch to the one
+	// before each of its items
+	onHand := make(map[string]int64) // by inventory ID
+	before := make([]int64, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if !removes[change.InventoryID] {
+			continue
+		}
+		quantity, ok := onHand[change.InventoryID]
+		if !ok {
+			item, err := s.inventoryRepo.GetByID(ctx, change.InventoryID)
+			if err != nil {
+				return fmt.Errorf("item %d: failed to get inventory: %w", i+1, err)
+			}
+			quantity = item.Quantity
+		}
+		before[i] = quantity - change.QuantityDelta
+		onHand[change.InventoryID] = before[i]
+	}
+
+	for i, change := range changes {
+		if change.QuantityDelta >= 0 {
+			continue
+		}
+		if _, err := s.takeFromLots(ctx, change.InventoryID, -change.QuantityDelta, before[i]); err != nil {
+			return fmt.Errorf("item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestApplyStockBatch(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Reserved: 2, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-2", Quantity: 5, Location: "WH-A"})
+
+	transactions, err := service.ApplyStockBatch(ctx, []domain.StockBatchItem{
+		{ProductID: "prod-1", Operation: domain.StockReserve, Quantity: 6, Reference: "ORD-1"},
+		{ProductID: "prod-2", Location: "WH-A", Operation: domain.StockRemove, Quantity: 5, Reference: "ORD-1"},
+		{ProductID: "prod-1", Operation: domain.StockUnreserve, Quantity: 2, Reference: "ORD-0"},
+		{ProductID: "prod-2", Operation: domain.StockAdd, Quantity: 1, Reference: "PO-1"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyStockBatch() error = %v", err)
+	}
+	if len(transactions) != 4 || transactions[1].Type != "OUT" || transactions[3].Type != "IN" {
+		t.Errorf("Unexpected transactions: %+v", transactions)
+	}
+
+	first, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if first.Quantity != 10 || first.Reserved != 6 {
+		t.Errorf("Expected prod-1 at 10/6, got %d/%d", first.Quantity, first.Reserved)
+	}
+	second, _ := inventoryRepo.GetByID(ctx, "inv-2")
+	if second.Quantity != 1 {
+		t.Errorf("Expected prod-2 quantity 1, got %d", second.Quantity)
+	}
+}
+
+func TestApplyStockBatchIsAllOrNothing(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository())
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	// The second item only fails because of the first one
+	_, err := service.ApplyStockBatch(ctx, []domain.StockBatchItem{
+		{ProductID: "prod-1", Operation: domain.StockRemove, Quantity: 7},
+		{ProductID: "prod-1", Operation: domain.StockReserve, Quantity: 4},
+	})
+	if !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("Expected ErrInsufficientStock, got %v", err)
+	}
+
+	item, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if item.Quantity != 10 || item.Reserved != 0 {
+		t.Errorf("Expected inventory to be untouched, got %d/%d", item.Quantity, item.Reserved)
+	}
+	if len(inventoryRepo.batched) != 0 {
+		t.Errorf("Expected no transactions, got %d", len(inventoryRepo.batched))
+	}
+}
+
+func TestApplyStockBatchValidation(t *testing.T) {
+	service := NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository())
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		items []domain.StockBatchItem
+		want  error
+	}{
+		{"Empty batch", nil, domain.ErrValidation},
+		{"Unknown operation", []domain.StockBatchItem{{ProductID: "prod-1", Operation: "count", Quantity: 1}}, domain.ErrValidation},
+		{"Zero quantity", []domain.StockBatchItem{{ProductID: "prod-1", Operation: domain.StockAdd}}, domain.ErrValidation},
+		{"Unknown product", []domain.StockBatchItem{{ProductID: "missing", Operation: domain.StockAdd, Quantity: 1}}, domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ApplyStockBatch(ctx, tt.items); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
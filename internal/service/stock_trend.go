@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MaxTrendDays bounds the number of days of one stock trend
+const MaxTrendDays = 366
+
+// TrendFills lists how missing days of a stock trend can be filled: with the
+// previous day's values, with zeros, or not at all
+var TrendFills = []string{"previous", "zero", "none"}
+
+// ProductTrend returns the daily on-hand and available series of a product
+// from the stock snapshots, for the days from through to. Days without a
+// snapshot are filled as requested, "previous" by default; with "previous"
+// days before the first known snapshot are left out.
+func (s *ReportService) ProductTrend(ctx context.Context, productID string, from, to time.Time, fill string) (*domain.StockTrend, error) {
+	if fill == "" {
+		fill = "previous"
+	}
+	if !isTrendFill(fill) {
+		return nil, fmt.Errorf("%w: unsupported fill %q (supported: %v)", ErrInvalidReportRequest, fill, TrendFills)
+	}
+
+	from, to = snapshotDay(from, time.UTC), snapshotDay(to, time.UTC)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidReportRequest)
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > MaxTrendDays {
+		return nil, fmt.Errorf("%w: trend spans more than %d days", ErrInvalidReportRequest, MaxTrendDays)
+	}
+
+	snapshots, err := s.reportRepo.StockSnapshots(ctx, productID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock snapshots: %w", err)
+	}
+
+	trend := &domain.StockTrend{
+		ProductID: productID,
+		From:      from.Format(time.DateOnly),
+		To:        to.Format(time.DateOnly),
+		Fill:      fill,
+		Points:    []domain.StockTrendPoint{},
+	}
+
+	var last *domain.StockSnapshot
+	next := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for next < len(snapshots) && !snapshots[next].Day.After(day) {
+			last = snapshots[next]
+			next++
+		}
+
+		point := domain.StockTrendPoint{Date: day.Format(time.DateOnly)}
+		switch {
+		case last != nil && last.Day.Equal(day):
+			point.OnHand, point.Available = last.OnHand, last.Available
+		case fill == "previous" && last != nil:
+			point.OnHand, point.Available, point.Filled = last.OnHand, last.Available, true
+		case fill == "zero":
+			point.Filled = true
+		default:
+			continue
+		}
+		trend.Points = append(trend.Points, point)
+	}
+
+	return trend, nil
+}
+
+// StockAsOf reconstructs the stock of every product at asOf from the latest
+// snapshot before it and the transactions recorded since, with the totals
+// over all products. A moment in the future, such as the end of today,
+// reports the stock as of now.
+func (s *ReportService) StockAsOf(ctx context.Context, asOf time.Time) (*domain.StockAsOfReport, error) {
+	if now := time.Now(); asOf.After(now) {
+		asOf = now
+	}
+
+	stock, err := s.reportRepo.StockAsOf(ctx, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct stock: %w", err)
+	}
+
+	report := &domain.StockAsOfReport{AsOf: asOf, Products: []*domain.HistoricStock{}}
+	for _, product := range stock {
+		report.Quantity += product.Quantity
+		report.Reserved += product.Reserved
+		report.Available += product.Available
+		report.Products = append(report.Products, product)
+	}
+
+	return report, nil
+}
+
+// isTrendFill reports whether fill is a supported gap filling mode
+func isTrendFill(fill string) bool {
+	for _, supported := range TrendFills {
+		if supported == fill {
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureSnapshots records the current stock of every product as the
+// snapshot of today's date in loc. Later captures on the same day replace
+// earlier ones, so a day keeps the stock it ended with.
+func (s *ReportService) CaptureSnapshots(ctx context.Context, now time.Time, loc *time.Location) (int64, error) {
+	count, err := s.reportRepo.CaptureStockSnapshots(ctx, snapshotDay(now, loc))
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture stock snapshots: %w", err)
+	}
+	return count, nil
+}
+
+// RunSnapshots captures stock snapshots right away and then every interval
+// until ctx is cancelled
+func (s *ReportService) RunSnapshots(ctx context.Context, interval time.Duration, loc *time.Location) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !awaitResumed(ctx) {
+			return
+		}
+		if _, err := s.CaptureSnapshots(ctx, time.Now(), loc); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "stock snapshot capture failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotDay returns the calendar date of t in loc at midnight UTC, the form
+// snapshot days are stored and compared in
+func snapshotDay(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
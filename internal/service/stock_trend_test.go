@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestProductTrendGapFilling(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+	repo := &MockReportRepository{snapshots: []*domain.StockSnapshot{
+		{Day: day(1), OnHand: 5, Available: 4},
+		{Day: day(4), OnHand: 10, Available: 8},
+		{Day: day(5), OnHand: 9, Available: 9},
+	}}
+	service := NewReportService(repo)
+
+	tests := []struct {
+		fill    string
+		from    int
+		want    []domain.StockTrendPoint
+		wantErr bool
+	}{
+		{"previous", 2, []domain.StockTrendPoint{
+			{Date: "2024-03-02", OnHand: 5, Available: 4, Filled: true},
+			{Date: "2024-03-03", OnHand: 5, Available: 4, Filled: true},
+			{Date: "2024-03-04", OnHand: 10, Available: 8},
+			{Date: "2024-03-05", OnHand: 9, Available: 9},
+			{Date: "2024-03-06", OnHand: 9, Available: 9, Filled: true},
+		}, false},
+		{"zero", 3, []domain.StockTrendPoint{
+			{Date: "2024-03-03", Filled: true},
+			{Date: "2024-03-04", OnHand: 10, Available: 8},
+			{Date: "2024-03-05", OnHand: 9, Available: 9},
+			{Date: "2024-03-06", Filled: true},
+		}, false},
+		{"none", 2, []domain.StockTrendPoint{
+			{Date: "2024-03-04", OnHand: 10, Available: 8},
+			{Date: "2024-03-05", OnHand: 9, Available: 9},
+		}, false},
+		{"linear", 2, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fill, func(t *testing.T) {
+			trend, err := service.ProductTrend(context.Background(), "prod-1", day(tt.from), day(6), tt.fill)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProductTrend() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(trend.Points) != len(tt.want) {
+				t.Fatalf("Expected %d points, got %+v", len(tt.want), trend.Points)
+			}
+			for i, point := range trend.Points {
+				if point != tt.want[i] {
+					t.Errorf("Point %d: expected %+v, got %+v", i, tt.want[i], point)
+				}
+			}
+		})
+	}
+
+	// Days before the first snapshot are left out when carrying values forward
+	trend, _ := service.ProductTrend(context.Background(), "prod-1", day(1).AddDate(0, 0, -3), day(1), "")
+	if len(trend.Points) != 1 || trend.Fill != "previous" {
+		t.Errorf("Expected only the first snapshot's day, got %+v", trend)
+	}
+
+	if _, err := service.ProductTrend(context.Background(), "prod-1", day(1), day(1).AddDate(1, 1, 0), ""); !errors.Is(err, ErrInvalidReportRequest) {
+		t.Errorf("Expected a range above %d days to be rejected, got %v", MaxTrendDays, err)
+	}
+}
+
+func TestCaptureSnapshotsUsesLocalDate(t *testing.T) {
+	repo := &MockReportRepository{}
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	// 20:00 UTC on March 1st is already March 2nd in Tokyo
+	if _, err := NewReportService(repo).CaptureSnapshots(context.Background(), time.Date(2024, time.March, 1, 20, 0, 0, 0, time.UTC), tokyo); err != nil {
+		t.Fatalf("CaptureSnapshots() error = %v", err)
+	}
+	if want := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC); !repo.capturedOn.Equal(want) {
+		t.Errorf("Expected snapshot day %v, got %v", want, repo.capturedOn)
+	}
+}
+
+func TestStockAsOfTotals(t *testing.T) {
+	repo := &MockReportRepository{historic: []*domain.HistoricStock{
+		{ProductID: "prod-1", Quantity: 10, Reserved: 3, Available: 7},
+		{ProductID: "prod-2", Quantity: 4, Available: 4},
+	}}
+	service := NewReportService(repo)
+
+	asOf := time.Date(2024, time.January, 1, 23, 59, 59, 0, time.UTC)
+	report, err := service.StockAsOf(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("StockAsOf() error = %v", err)
+	}
+	if !repo.asOf.Equal(asOf) || !report.AsOf.Equal(asOf) {
+		t.Errorf("Expected stock as of %v, got %v", asOf, repo.asOf)
+	}
+	if report.Quantity != 14 || report.Reserved != 3 || report.Available != 11 || len(report.Products) != 2 {
+		t.Errorf("Unexpected totals: %+v", report)
+	}
+
+	future := time.Now().Add(time.Hour)
+	if report, _ := service.StockAsOf(context.Background(), future); !report.AsOf.Before(future) {
+		t.Errorf("Expected a future as_of to report the stock as of now, got %v", report.AsOf)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// stockoutVelocityWindow is how far back sales are averaged to project stockouts
+const stockoutVelocityWindow = 30 * 24 * time.Hour
+
+// maxStockoutHorizonDays bounds how far ahead the stockout report looks
+const maxStockoutHorizonDays = 365
+
+// projectStockouts sets the projected stockout time of each inventory item of
+// a product from its sales over the velocity window, and returns the
+// projection for the product as a whole
+func (s *InventoryService) projectStockouts(ctx context.Context, productID string, items ...*domain.InventoryItem) (*time.Time, error) {
+	now := time.Now()
+	outbound, err := s.transactionRepo.OutboundByInventory(ctx, productID, now.Add(-stockoutVelocityWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to project stockout: %w", err)
+	}
+
+	var available, sold int64
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		item.ProjectedStockoutAt = domain.ProjectStockout(item.AvailableQuantity(), outbound[item.ID], stockoutVelocityWindow, now)
+		available += item.AvailableQuantity()
+		sold += outbound[item.ID]
+	}
+
+	return domain.ProjectStockout(available, sold, stockoutVelocityWindow, now), nil
+}
+
+// StockoutReport lists the products projected to run out of available stock
+// within the given number of days at their recent sales velocity, soonest first
+func (s *ReportService) StockoutReport(ctx context.Context, days int) ([]*domain.StockoutProjection, error) {
+	if days < 1 || days > maxStockoutHorizonDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidReportRequest, maxStockoutHorizonDays)
+	}
+
+	now := time.Now()
+	velocities, err := s.reportRepo.StockVelocities(ctx, now.Add(-stockoutVelocityWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute stockout report: %w", err)
+	}
+
+	horizon := now.AddDate(0, 0, days)
+	projections := make([]*domain.StockoutProjection, 0, len(velocities))
+	for _, velocity := range velocities {
+		at := domain.ProjectStockout(velocity.Available, velocity.Outbound, stockoutVelocityWindow, now)
+		if at == nil || at.After(horizon) {
+			continue
+		}
+
+		projections = append(projections, &domain.StockoutProjection{
+			ProductID:           velocity.ProductID,
+			SKU:                 velocity.SKU,
+			Name:                velocity.Name,
+			Available:           velocity.Available,
+			DailyVelocity:       float64(velocity.Outbound) / stockoutVelocityWindow.Hours() * 24,
+			DaysRemaining:       at.Sub(now).Hours() / 24,
+			ProjectedStockoutAt: *at,
+		})
+	}
+
+	sort.SliceStable(projections, func(i, j int) bool {
+		return projections[i].ProjectedStockoutAt.Before(projections[j].ProjectedStockoutAt)
+	})
+
+	return projections, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+var (
+	// ErrLocationFrozen is returned for stock mutations rejected because a
+	// stocktake is open at the location
+	ErrLocationFrozen = errors.New("location is frozen for a stocktake")
+	// ErrMovementQueued is returned when a stock mutation was queued instead
+	// of applied because a stocktake in queue mode is open at the location
+	ErrMovementQueued = errors.New("location is frozen for a stocktake; movement queued until it closes")
+	// ErrStocktakeOpen is returned when opening a second stocktake at a location
+	ErrStocktakeOpen = errors.New("a stocktake is already open at this location")
+	// ErrStocktakeClosed is returned when closing a stocktake that is not open
+	ErrStocktakeClosed = errors.New("stocktake is not open")
+)
+
+// WithStocktakes makes stock additions, removals and transfers respect the
+// freeze mode of stocktakes open at their location
+func WithStocktakes(stocktakeRepo repository.StocktakeRepository) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.stocktakeRepo = stocktakeRepo
+	}
+}
+
+// checkFrozen handles a movement at a location with an open stocktake: it is
+// rejected in block mode and stored for later in queue mode. Transfers span
+// two locations and are never queued.
+func (s *InventoryService) checkFrozen(ctx context.Context, movement StockMovement) error {
+	if s.stocktakeRepo == nil {
+		return nil
+	}
+
+	stocktake, err := s.stocktakeRepo.FindOpen(ctx, movement.Location)
+	if err != nil {
+		return fmt.Errorf("failed to check stocktakes: %w", err)
+	}
+	if stocktake == nil || stocktake.Mode == domain.FreezeModeNone {
+		return nil
+	}
+
+	if stocktake.Mode == domain.FreezeModeQueue && (movement.Type == "IN" || movement.Type == "OUT") {
+		queued := &domain.QueuedMovement{
+			StocktakeID: stocktake.ID,
+			ProductID:   movement.ProductID,
+			Location:    movement.Location,
+			Condition:   movement.Condition,
+			Type:        movement.Type,
+			Quantity:    movement.Quantity,
+			Reference:   movement.Reference,
+			UnitCost:    movement.UnitCost,
+		}
+		if err := s.stocktakeRepo.QueueMovement(ctx, queued); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w (stocktake %s, movement %s)", ErrMovementQueued, stocktake.ID, queued.ID)
+	}
+
+	return fmt.Errorf("%w: stocktake %s is open at %s", ErrLocationFrozen, stocktake.ID, movement.Location)
+}
+
+// rejectFrozen rejects a movement at a location with an open stocktake in any
+// freeze mode. Batches apply all their items or none, so they are never queued.
+func (s *InventoryService) rejectFrozen(ctx context.Context, location string) error {
+	if s.stocktakeRepo == nil {
+		return nil
+	}
+
+	stocktake, err := s.stocktakeRepo.FindOpen(ctx, location)
+	if err != nil {
+		return fmt.Errorf("failed to check stocktakes: %w", err)
+	}
+	if stocktake == nil || stocktake.Mode == domain.FreezeModeNone {
+		return nil
+	}
+
+	return fmt.Errorf("%w: stocktake %s is open at %s", ErrLocationFrozen, stocktake.ID, location)
+}
+
+// StocktakeService opens and closes stocktakes. Counts are entered with
+// InventoryService.SetStockCount, which is not affected by the freeze.
+type StocktakeService struct {
+	inventory     *InventoryService
+	stocktakeRepo repository.StocktakeRepository
+	defaultMode   domain.FreezeMode
+}
+
+// NewStocktakeService creates a new StocktakeService. Stocktakes opened
+// without an explicit mode use defaultMode.
+func NewStocktakeService(inventory *InventoryService, stocktakeRepo repository.StocktakeRepository, defaultMode domain.FreezeMode) *StocktakeService {
+	return &StocktakeService{
+		inventory:     inventory,
+		stocktakeRepo: stocktakeRepo,
+		defaultMode:   defaultMode,
+	}
+}
+
+// OpenStocktake starts a stocktake at a location. An empty mode selects the
+// service default.
+func (s *StocktakeService) OpenStocktake(ctx context.Context, location, mode string) (*domain.Stocktake, error) {
+	stocktake := &domain.Stocktake{Location: location, Mode: s.defaultMode}
+	if mode != "" {
+		parsed, err := domain.ParseFreezeMode(mode)
+		if err != nil {
+			return nil, err
+		}
+		stocktake.Mode = parsed
+	}
+	if err := stocktake.Validate(); err != nil {
+		return nil, err
+	}
+
+	open, err := s.stocktakeRepo.FindOpen(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("%w: stocktake %s", ErrStocktakeOpen, open.ID)
+	}
+
+	if err := s.stocktakeRepo.Create(ctx, stocktake); err != nil {
+		return nil, err
+	}
+	return stocktake, nil
+}
+
+// CloseStocktake ends a stocktake and applies the movements queued while it
+// was open, in the order they were received. Movements that can no longer be
+// applied (e.g. a removal exceeding the counted stock) are marked failed.
+func (s *StocktakeService) CloseStocktake(ctx context.Context, id string) (*domain.Stocktake, error) {
+	closed, err := s.stocktakeRepo.Close(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrStocktakeClosed
+	}
+
+	stocktake, err := s.stocktakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeat until drained: a request that saw the stocktake open just before
+	// it closed may still queue its movement
+	for {
+		movements, err := s.stocktakeRepo.ListPendingMovements(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(movements) == 0 {
+			break
+		}
+
+		for _, movement := range movements {
+			movement.Status = domain.QueuedMovementApplied
+			if err := s.replay(ctx, movement); err != nil {
+				movement.Status = domain.QueuedMovementFailed
+				movement.Error = err.Error()
+			}
+			if err := s.stocktakeRepo.UpdateMovementStatus(ctx, movement.ID, movement.Status, movement.Error); err != nil {
+				return nil, err
+			}
+			stocktake.Replayed = append(stocktake.Replayed, movement)
+		}
+	}
+
+	return stocktake, nil
+}
+
+// replay applies a queued movement
+func (s *StocktakeService) replay(ctx context.Context, movement *domain.QueuedMovement) error {
+	switch movement.Type {
+	case "IN":
+		return s.inventory.AddStockAtCost(ctx, movement.ProductID, movement.Location, movement.Condition, movement.Quantity, movement.UnitCost, movement.Reference)
+	case "OUT":
+		return s.inventory.RemoveStockAt(ctx, movement.ProductID, movement.Location, movement.Condition, movement.Quantity, movement.Reference)
+	}
+	return fmt.Errorf("cannot replay movement of type %s", movement.Type)
+}
+
+// GetStocktake retrieves a stocktake by ID
+func (s *StocktakeService) GetStocktake(ctx context.Context, id string) (*domain.Stocktake, error) {
+	return s.stocktakeRepo.GetByID(ctx, id)
+}
+
+// ListStocktakes lists stocktakes with pagination, newest first
+func (s *StocktakeService) ListStocktakes(ctx context.Context, limit, offset int) ([]*domain.Stocktake, error) {
+	stocktakes, err := s.stocktakeRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stocktakes: %w", err)
+	}
+	return stocktakes, nil
+}
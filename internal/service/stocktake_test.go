@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockStocktakeRepository implements StocktakeRepository interface for testing
+type MockStocktakeRepository struct {
+	stocktakes map[string]*domain.Stocktake
+	movements  []*domain.QueuedMovement
+}
+
+func NewMockStocktakeRepository() *MockStocktakeRepository {
+	return &MockStocktakeRepository{stocktakes: make(map[string]*domain.Stocktake)}
+}
+
+func (m *MockStocktakeRepository) Create(ctx context.Context, stocktake *domain.Stocktake) error {
+	stocktake.ID = fmt.Sprintf("stocktake-%d", len(m.stocktakes)+1)
+	stocktake.Status = domain.StocktakeStatusOpen
+	m.stocktakes[stocktake.ID] = stocktake
+	return nil
+}
+
+func (m *MockStocktakeRepository) GetByID(ctx context.Context, id string) (*domain.Stocktake, error) {
+	if stocktake, ok := m.stocktakes[id]; ok {
+		copied := *stocktake
+		return &copied, nil
+	}
+	return nil, errors.New("stocktake not found")
+}
+
+func (m *MockStocktakeRepository) FindOpen(ctx context.Context, location string) (*domain.Stocktake, error) {
+	for _, stocktake := range m.stocktakes {
+		if stocktake.Location == location && stocktake.Status == domain.StocktakeStatusOpen {
+			return stocktake, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockStocktakeRepository) List(ctx context.Context, limit, offset int) ([]*domain.Stocktake, error) {
+	var stocktakes []*domain.Stocktake
+	for _, stocktake := range m.stocktakes {
+		stocktakes = append(stocktakes, stocktake)
+	}
+	return stocktakes, nil
+}
+
+func (m *MockStocktakeRepository) Close(ctx context.Context, id string) (bool, error) {
+	stocktake, ok := m.stocktakes[id]
+	if !ok || stocktake.Status != domain.StocktakeStatusOpen {
+		return false, nil
+	}
+	stocktake.Status = domain.StocktakeStatusClosed
+	return true, nil
+}
+
+func (m *MockStocktakeRepository) QueueMovement(ctx context.Context, movement *domain.QueuedMovement) error {
+	movement.ID = fmt.Sprintf("movement-%d", len(m.movements)+1)
+	movement.Status = domain.QueuedMovementPending
+	m.movements = append(m.movements, movement)
+	return nil
+}
+
+func (m *MockStocktakeRepository) ListPendingMovements(ctx context.Context, stocktakeID string) ([]*domain.QueuedMovement, error) {
+	var pending []*domain.QueuedMovement
+	for _, movement := range m.movements {
+		if movement.StocktakeID == stocktakeID && movement.Status == domain.QueuedMovementPending {
+			copied := *movement
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (m *MockStocktakeRepository) UpdateMovementStatus(ctx context.Context, id string, status domain.QueuedMovementStatus, errorMessage string) error {
+	for _, movement := range m.movements {
+		if movement.ID == id {
+			movement.Status = status
+			movement.Error = errorMessage
+		}
+	}
+	return nil
+}
+
+func TestStocktakeFreeze(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr error
+	}{
+		{"Block", "block", ErrLocationFrozen},
+		{"Queue", "queue", ErrMovementQueued},
+		{"None", "none", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inventoryRepo := NewMockInventoryRepository()
+			stocktakeRepo := NewMockStocktakeRepository()
+			inventory := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository(), WithStocktakes(stocktakeRepo))
+			stocktakes := NewStocktakeService(inventory, stocktakeRepo, domain.FreezeModeBlock)
+
+			inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Location: "WH-A"})
+
+			if _, err := stocktakes.OpenStocktake(ctx, "WH-A", tt.mode); err != nil {
+				t.Fatalf("Failed to open stocktake: %v", err)
+			}
+			if _, err := stocktakes.OpenStocktake(ctx, "WH-A", tt.mode); !errors.Is(err, ErrStocktakeOpen) {
+				t.Errorf("Expected ErrStocktakeOpen for a second stocktake, got %v", err)
+			}
+
+			err := inventory.AddStockAt(ctx, "prod-1", "WH-A", "", 5, "PO-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if err := inventory.TransferStock(ctx, "prod-1", "WH-A", "WH-B", 5, "TR-1"); !errors.Is(err, ErrLocationFrozen) {
+					t.Errorf("Expected transfers to be blocked, got %v", err)
+				}
+			}
+
+			// Counting is never frozen
+			if _, err := inventory.SetStockCount(ctx, "prod-1", "WH-A", 40, inventoryRepo.items["inv-1"].Version, "COUNT-1"); err != nil {
+				t.Errorf("Failed to set stock count during stocktake: %v", err)
+			}
+		})
+	}
+}
+
+func TestCloseStocktakeReplaysQueuedMovements(t *testing.T) {
+	ctx := context.Background()
+	inventoryRepo := NewMockInventoryRepository()
+	stocktakeRepo := NewMockStocktakeRepository()
+	inventory := NewInventoryService(NewMockProductRepository(), inventoryRepo, NewMockTransactionRepository(), WithStocktakes(stocktakeRepo))
+	stocktakes := NewStocktakeService(inventory, stocktakeRepo, domain.FreezeModeQueue)
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 50, Location: "WH-A"})
+
+	stocktake, err := stocktakes.OpenStocktake(ctx, "WH-A", "")
+	if err != nil {
+		t.Fatalf("Failed to open stocktake: %v", err)
+	}
+
+	inventory.AddStockAt(ctx, "prod-1", "WH-A", "", 10, "PO-1")
+	inventory.RemoveStockAt(ctx, "prod-1", "WH-A", "", 100, "SO-1")
+	if qty := inventoryRepo.items["inv-1"].Quantity; qty != 50 {
+		t.Fatalf("Expected queued movements not to change stock, got %d", qty)
+	}
+
+	inventory.SetStockCount(ctx, "prod-1", "WH-A", 45, inventoryRepo.items["inv-1"].Version, "COUNT-1")
+
+	closed, err := stocktakes.CloseStocktake(ctx, stocktake.ID)
+	if err != nil {
+		t.Fatalf("Failed to close stocktake: %v", err)
+	}
+
+	if len(closed.Replayed) != 2 {
+		t.Fatalf("Expected 2 replayed movements, got %d", len(closed.Replayed))
+	}
+	if closed.Replayed[0].Status != domain.QueuedMovementApplied {
+		t.Errorf("Expected addition to be applied, got %s (%s)", closed.Replayed[0].Status, closed.Replayed[0].Error)
+	}
+	if closed.Replayed[1].Status != domain.QueuedMovementFailed {
+		t.Errorf("Expected oversized removal to fail, got %s", closed.Replayed[1].Status)
+	}
+	if qty := inventoryRepo.items["inv-1"].Quantity; qty != 55 {
+		t.Errorf("Expected counted 45 plus queued 10, got %d", qty)
+	}
+
+	if _, err := stocktakes.CloseStocktake(ctx, stocktake.ID); !errors.Is(err, ErrStocktakeClosed) {
+		t.Errorf("Expected ErrStocktakeClosed when closing twice, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+const (
+	// streamQueueSize bounds the transactions waiting for the dispatcher
+	streamQueueSize = 1024
+
+	// streamSubscriberBuffer bounds the events waiting for one subscriber; a
+	// subscriber that falls further behind is disconnected
+	streamSubscriberBuffer = 64
+)
+
+// ErrStreamFull is returned when the inventory stream has no room for another
+// subscriber
+var ErrStreamFull = errors.New("too many inventory stream subscribers")
+
+// InventoryStream pushes stock changes to live subscribers. As a
+// TransactionHandler it queues every recorded transaction of a subscribed
+// product; its dispatcher reads the product's new stock level once and fans
+// the event out. Only changes made through this instance are seen.
+type InventoryStream struct {
+	inventoryRepo  repository.InventoryRepository
+	maxSubscribers int
+	queue          chan *domain.Transaction
+
+	mu          sync.Mutex
+	subscribers map[*StreamSubscription]struct{}
+	products    map[string]int
+	closed      bool
+}
+
+// StreamSubscription receives the events of the products it subscribed to.
+// Events is closed when the subscriber fell behind or the stream stopped.
+type StreamSubscription struct {
+	Events <-chan *domain.InventoryEvent
+
+	events     chan *domain.InventoryEvent
+	products   map[string]bool
+	productIDs []string
+}
+
+// NewInventoryStream creates an InventoryStream accepting up to maxSubscribers
+// subscribers at once
+func NewInventoryStream(inventoryRepo repository.InventoryRepository, maxSubscribers int) *InventoryStream {
+	return &InventoryStream{
+		inventoryRepo:  inventoryRepo,
+		maxSubscribers: maxSubscribers,
+		queue:          make(chan *domain.Transaction, streamQueueSize),
+		subscribers:    make(map[*StreamSubscription]struct{}),
+		products:       make(map[string]int),
+	}
+}
+
+// Subscribe opens a subscription to the given products
+func (s *InventoryStream) Subscribe(productIDs []string) (*StreamSubscription, error) {
+	if len(productIDs) == 0 {
+		return nil, domain.NewValidationError("product_ids cannot be empty")
+	}
+	if len(productIDs) > domain.MaxStreamProducts {
+		return nil, domain.NewValidationError("a stream subscribes to at most %d products", domain.MaxStreamProducts)
+	}
+
+	events := make(chan *domain.InventoryEvent, streamSubscriberBuffer)
+	sub := &StreamSubscription{Events: events, events: events, products: make(map[string]bool, len(productIDs))}
+	for _, id := range productIDs {
+		if !sub.products[id] {
+			sub.products[id] = true
+			sub.productIDs = append(sub.productIDs, id)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || len(s.subscribers) >= s.maxSubscribers {
+		return nil, ErrStreamFull
+	}
+	s.subscribers[sub] = struct{}{}
+	for id := range sub.products {
+		s.products[id]++
+	}
+	return sub, nil
+}
+
+// Unsubscribe ends a subscription. It is safe to call more than once.
+func (s *InventoryStream) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(sub)
+}
+
+// remove drops a subscriber and closes its events; the caller holds s.mu
+func (s *InventoryStream) remove(sub *StreamSubscription) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	for id := range sub.products {
+		if s.products[id]--; s.products[id] == 0 {
+			delete(s.products, id)
+		}
+	}
+	close(sub.events)
+}
+
+// Snapshot returns the current stock level of each subscribed product that is
+// stocked anywhere, in the order they were subscribed
+func (s *InventoryStream) Snapshot(ctx context.Context, sub *StreamSubscription) ([]*domain.InventoryEvent, error) {
+	events := make([]*domain.InventoryEvent, 0, len(sub.productIDs))
+	for _, id := range sub.productIDs {
+		event, err := s.event(ctx, &domain.Transaction{ProductID: id})
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// HandleTransaction queues the transaction when its product has subscribers.
+// It never blocks the stock operation: when the queue is full the change is
+// dropped and subscribers catch up with the next change of the product.
+func (s *InventoryStream) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	s.mu.Lock()
+	subscribed := s.products[transaction.ProductID] > 0
+	s.mu.Unlock()
+	if !subscribed {
+		return
+	}
+
+	select {
+	case s.queue <- transaction:
+	default:
+		slog.WarnContext(ctx, "inventory stream queue full, dropping change", "product_id", transaction.ProductID)
+	}
+}
+
+// Run dispatches queued changes until ctx is cancelled, then closes every
+// subscription
+func (s *InventoryStream) Run(ctx context.Context) {
+	defer s.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case transaction := <-s.queue:
+			event, err := s.event(ctx, transaction)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to read stock level for inventory stream", "product_id", transaction.ProductID, "error", err)
+				continue
+			}
+			if event != nil {
+				s.publish(event)
+			}
+		}
+	}
+}
+
+// stop closes every subscription and rejects new ones
+func (s *InventoryStream) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		s.remove(sub)
+	}
+}
+
+// publish hands the event to the product's subscribers, disconnecting those
+// whose buffer is full so that one slow client cannot hold up the others
+func (s *InventoryStream) publish(event *domain.InventoryEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		if !sub.products[event.ProductID] {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.remove(sub)
+		}
+	}
+}
+
+// event reads the stock level of the transaction's product after it; it
+// returns nil when the product is not stocked anywhere
+func (s *InventoryStream) event(ctx context.Context, transaction *domain.Transaction) (*domain.InventoryEvent, error) {
+	items, err := s.inventoryRepo.ListByProductID(ctx, transaction.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inventory: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	level := domain.NewStockLevel(transaction.ProductID, items)
+	event := &domain.InventoryEvent{
+		ProductID:           transaction.ProductID,
+		Sequence:            transaction.Sequence,
+		TransactionType:     transaction.Type,
+		TransactionQuantity: transaction.Quantity,
+		Quantity:            level.Quantity,
+		Reserved:            level.Reserved,
+		Available:           level.Available,
+		OccurredAt:          transaction.CreatedAt,
+	}
+	for _, item := range items {
+		if item.ID == transaction.InventoryID {
+			event.Location = item.Location
+		}
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	return event, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// receiveEvent waits for the next event of a subscription
+func receiveEvent(t *testing.T, sub *StreamSubscription) (*domain.InventoryEvent, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events:
+		return event, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an inventory event")
+		return nil, false
+	}
+}
+
+func TestInventoryStream(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	productRepo.Create(ctx, &domain.Product{ID: "prod-2", Name: "Controller", SKU: "CTL001", Price: 59})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-2", ProductID: "prod-2", Quantity: 5, Location: "WH-A"})
+
+	stream := NewInventoryStream(inventoryRepo, 10)
+	service := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository(), WithTransactionHandlers(stream))
+
+	sub, err := stream.Subscribe([]string{"prod-1", "prod-1", "unknown"})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	snapshots, err := stream.Snapshot(ctx, sub)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ProductID != "prod-1" || snapshots[0].Available != 10 || snapshots[0].TransactionType != "" {
+		t.Fatalf("Expected one snapshot of prod-1 with 10 available, got %+v", snapshots)
+	}
+
+	// Changes are queued on the request path and dispatched by Run
+	if err := service.ReserveStock(ctx, "prod-1", 3, "ORDER-1"); err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	if err := service.UnreserveStock(ctx, "prod-1", 1, "ORDER-1"); err != nil {
+		t.Fatalf("UnreserveStock() error = %v", err)
+	}
+	if err := service.AddStock(ctx, "prod-2", 5, "PO-1"); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		stream.Run(runCtx)
+		close(done)
+	}()
+
+	event, ok := receiveEvent(t, sub)
+	if !ok {
+		t.Fatal("Expected an event, got a closed stream")
+	}
+	if event.ProductID != "prod-1" || event.TransactionType != "RESERVE" || event.TransactionQuantity != 3 ||
+		event.Location != "WH-A" || event.Sequence != 1 {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	// Events carry the product's sequence so that clients can order them
+	event, ok = receiveEvent(t, sub)
+	if !ok {
+		t.Fatal("Expected an event, got a closed stream")
+	}
+	if event.TransactionType != "UNRESERVE" || event.Sequence != 2 || event.Reserved != 2 || event.Available != 8 {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	// Stopping the dispatcher closes the subscription; prod-2 was never sent
+	cancel()
+	<-done
+	if event, ok := receiveEvent(t, sub); ok {
+		t.Errorf("Expected the subscription to be closed, got %+v", event)
+	}
+	if _, err := stream.Subscribe([]string{"prod-1"}); !errors.Is(err, ErrStreamFull) {
+		t.Errorf("Expected ErrStreamFull after the stream stopped, got %v", err)
+	}
+	stream.Unsubscribe(sub)
+}
+
+func TestInventoryStreamSubscribe(t *testing.T) {
+	stream := NewInventoryStream(NewMockInventoryRepository(), 1)
+
+	if _, err := stream.Subscribe(nil); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error without products, got %v", err)
+	}
+	tooMany := strings.Split(strings.Repeat("p,", domain.MaxStreamProducts)+"p", ",")
+	if _, err := stream.Subscribe(tooMany); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for %d products, got %v", len(tooMany), err)
+	}
+
+	sub, err := stream.Subscribe([]string{"prod-1"})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if _, err := stream.Subscribe([]string{"prod-2"}); !errors.Is(err, ErrStreamFull) {
+		t.Errorf("Expected ErrStreamFull beyond the limit, got %v", err)
+	}
+
+	// Unsubscribing frees the slot and stops queueing the product's changes
+	stream.Unsubscribe(sub)
+	stream.HandleTransaction(context.Background(), &domain.Transaction{ProductID: "prod-1"})
+	if len(stream.queue) != 0 {
+		t.Errorf("Expected no queued change without subscribers, got %d", len(stream.queue))
+	}
+	if _, err := stream.Subscribe([]string{"prod-2"}); err != nil {
+		t.Errorf("Expected a free slot after unsubscribing, got %v", err)
+	}
+}
+
+func TestInventoryStreamDisconnectsSlowSubscribers(t *testing.T) {
+	stream := NewInventoryStream(NewMockInventoryRepository(), 10)
+	slow, _ := stream.Subscribe([]string{"prod-1"})
+	other, _ := stream.Subscribe([]string{"prod-2"})
+
+	for i := 0; i <= streamSubscriberBuffer; i++ {
+		stream.publish(&domain.InventoryEvent{ProductID: "prod-1"})
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != streamSubscriberBuffer {
+		t.Errorf("Expected %d buffered events before the disconnect, got %d", streamSubscriberBuffer, received)
+	}
+
+	stream.publish(&domain.InventoryEvent{ProductID: "prod-2"})
+	if event, ok := receiveEvent(t, other); !ok || event.ProductID != "prod-2" {
+		t.Errorf("Expected the other subscriber to keep receiving, got %+v", event)
+	}
+}
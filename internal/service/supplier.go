@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// SupplierPortalService is the supplier's view of purchasing: a supplier
+// sees only its own purchase orders, confirms the open ones and announces
+// their shipments. Orders of other suppliers are reported as not found, so
+// their existence is not revealed.
+type SupplierPortalService struct {
+	replenishment *ReplenishmentService
+}
+
+// NewSupplierPortalService creates a new SupplierPortalService
+func NewSupplierPortalService(replenishment *ReplenishmentService) *SupplierPortalService {
+	return &SupplierPortalService{replenishment: replenishment}
+}
+
+// OpenOrders lists the supplier's draft purchase orders, newest first
+func (s *SupplierPortalService) OpenOrders(ctx context.Context, supplier string, limit, offset int) ([]*domain.PurchaseOrder, error) {
+	orders, err := s.replenishment.orderRepo.ListForSupplier(ctx, supplier, domain.PurchaseOrderStatusDraft, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list purchase orders: %w", err)
+	}
+	if orders == nil {
+		orders = []*domain.PurchaseOrder{}
+	}
+	return orders, nil
+}
+
+// Order retrieves one of the supplier's purchase orders
+func (s *SupplierPortalService) Order(ctx context.Context, supplier, id string) (*domain.PurchaseOrder, error) {
+	order, err := s.replenishment.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Supplier != supplier {
+		return nil, fmt.Errorf("failed to get purchase order: purchase order %w", domain.ErrNotFound)
+	}
+	return order, nil
+}
+
+// Confirm records the supplier's confirmation of an open purchase order. A
+// later confirmation replaces an earlier one, but cannot confirm less than
+// was already shipped.
+func (s *SupplierPortalService) Confirm(ctx context.Context, supplier, id string, confirmation *domain.SupplierConfirmation) (*domain.PurchaseOrder, error) {
+	err := s.withOpenOrder(ctx, supplier, id, func(ctx context.Context, order *domain.PurchaseOrder) error {
+		quantities, err := confirmation.Quantities(order, time.Now())
+		if err != nil {
+			return err
+		}
+		confirmed, err := s.replenishment.orderRepo.Confirm(ctx, id, confirmation.PromisedAt, quantities)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return ErrPurchaseOrderNotDraft
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.replenishment.GetPurchaseOrder(ctx, id)
+}
+
+// SubmitShippingNotice stores an advance shipping notice of the supplier for
+// an open purchase order. Its lines cannot ship more than is left to ship of
+// the confirmed, or else ordered, quantities.
+func (s *SupplierPortalService) SubmitShippingNotice(ctx context.Context, supplier, id string, notice *domain.AdvanceShippingNotice) (*domain.AdvanceShippingNotice, error) {
+	notice.PurchaseOrderID = id
+	if notice.ShippedAt.IsZero() {
+		notice.ShippedAt = time.Now()
+	}
+
+	err := s.withOpenOrder(ctx, supplier, id, func(ctx context.Context, order *domain.PurchaseOrder) error {
+		if err := notice.Validate(order); err != nil {
+			return err
+		}
+		return s.replenishment.orderRepo.CreateShippingNotice(ctx, notice)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notice, nil
+}
+
+// ShippingNotices lists the shipping notices of one of the supplier's
+// purchase orders
+func (s *SupplierPortalService) ShippingNotices(ctx context.Context, supplier, id string) ([]*domain.AdvanceShippingNotice, error) {
+	if _, err := s.Order(ctx, supplier, id); err != nil {
+		return nil, err
+	}
+	return s.replenishment.ShippingNotices(ctx, id)
+}
+
+// withOpenOrder runs fn in a unit of work holding the lock of one of the
+// supplier's draft purchase orders, with the order as it is under the lock
+func (s *SupplierPortalService) withOpenOrder(ctx context.Context, supplier, id string, fn func(ctx context.Context, order *domain.PurchaseOrder) error) error {
+	return s.replenishment.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.replenishment.orderRepo.Lock(ctx, id); err != nil {
+			return fmt.Errorf("failed to get purchase order: %w", err)
+		}
+		order, err := s.Order(ctx, supplier, id)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.PurchaseOrderStatusDraft {
+			return fmt.Errorf("%w: purchase order is %s", ErrPurchaseOrderNotDraft, order.Status)
+		}
+		return fn(ctx, order)
+	})
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestSupplierPortal(t *testing.T) {
+	ctx := context.Background()
+	orderRepo := NewMockPurchaseOrderRepository()
+	replenishment := NewReplenishmentService(NewInventoryService(NewMockProductRepository(), NewMockInventoryRepository(), NewMockTransactionRepository()), orderRepo, ReplenishmentPolicy{})
+	portal := NewSupplierPortalService(replenishment)
+
+	order := &domain.PurchaseOrder{Supplier: "ACME", Status: domain.PurchaseOrderStatusDraft, Lines: []domain.PurchaseOrderLine{
+		{ProductID: "prod-1", InventoryID: "inv-1", Location: "WH-A", Quantity: 50},
+		{ProductID: "prod-2", InventoryID: "inv-2", Location: "WH-A", Quantity: 20},
+	}}
+	orderRepo.Create(ctx, order)
+	orderRepo.Create(ctx, &domain.PurchaseOrder{Supplier: "Globex", Status: domain.PurchaseOrderStatusDraft, Lines: []domain.PurchaseOrderLine{{ProductID: "prod-1", Quantity: 5}}})
+
+	orders, err := portal.OpenOrders(ctx, "ACME", 10, 0)
+	if err != nil {
+		t.Fatalf("OpenOrders() error = %v", err)
+	}
+	if len(orders) != 1 || orders[0].ID != order.ID {
+		t.Errorf("Expected only ACME's order, got %+v", orders)
+	}
+	if _, err := portal.Order(ctx, "Globex", order.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("Expected another supplier's order to be not found, got %v", err)
+	}
+
+	// Line 2 can only be delivered in part
+	promised := time.Now().AddDate(0, 0, 10)
+	confirmed, err := portal.Confirm(ctx, "ACME", order.ID, &domain.SupplierConfirmation{
+		PromisedAt: promised,
+		Lines:      []domain.QuantityLine{{Line: 2, Quantity: 15}},
+	})
+	if err != nil {
+		t.Fatalf("Confirm() error = %v", err)
+	}
+	if confirmed.PromisedAt == nil || *confirmed.Lines[0].ConfirmedQuantity != 50 || *confirmed.Lines[1].ConfirmedQuantity != 15 {
+		t.Errorf("Expected lines confirmed at 50 and 15, got %+v", confirmed.Lines)
+	}
+	invalid := []*domain.SupplierConfirmation{
+		{Lines: []domain.QuantityLine{{Line: 1, Quantity: 1}}},
+		{PromisedAt: time.Now().AddDate(0, 0, -2)},
+		{PromisedAt: promised, Lines: []domain.QuantityLine{{Line: 3, Quantity: 1}}},
+		{PromisedAt: promised, Lines: []domain.QuantityLine{{Line: 1, Quantity: 51}}},
+	}
+	for _, confirmation := range invalid {
+		if _, err := portal.Confirm(ctx, "ACME", order.ID, confirmation); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("Expected a validation error for %+v, got %v", confirmation, err)
+		}
+	}
+
+	notice, err := portal.SubmitShippingNotice(ctx, "ACME", order.ID, &domain.AdvanceShippingNotice{
+		Reference: "SHIP-1",
+		Carrier:   "DHL",
+		Lines:     []domain.QuantityLine{{Line: 1, Quantity: 30}, {Line: 2, Quantity: 15}},
+	})
+	if err != nil {
+		t.Fatalf("SubmitShippingNotice() error = %v", err)
+	}
+	if notice.ID == "" || notice.PurchaseOrderID != order.ID || notice.ShippedAt.IsZero() {
+		t.Errorf("Unexpected notice: %+v", notice)
+	}
+
+	// Only the 20 units left of line 1 can still ship, and references are unique
+	over := &domain.AdvanceShippingNotice{Reference: "SHIP-2", Lines: []domain.QuantityLine{{Line: 1, Quantity: 21}}}
+	if _, err := portal.SubmitShippingNotice(ctx, "ACME", order.ID, over); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for shipping too much, got %v", err)
+	}
+	again := &domain.AdvanceShippingNotice{Reference: "SHIP-1", Lines: []domain.QuantityLine{{Line: 1, Quantity: 20}}}
+	if _, err := portal.SubmitShippingNotice(ctx, "ACME", order.ID, again); !errors.Is(err, domain.ErrDuplicateShippingNotice) {
+		t.Errorf("Expected a duplicate reference to be rejected, got %v", err)
+	}
+	if _, err := portal.Confirm(ctx, "ACME", order.ID, &domain.SupplierConfirmation{PromisedAt: promised, Lines: []domain.QuantityLine{{Line: 1, Quantity: 10}}}); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected confirming less than was shipped to fail, got %v", err)
+	}
+
+	notices, err := portal.ShippingNotices(ctx, "ACME", order.ID)
+	if err != nil || len(notices) != 1 {
+		t.Errorf("Expected 1 shipping notice, got %d (%v)", len(notices), err)
+	}
+	current, _ := portal.Order(ctx, "ACME", order.ID)
+	if current.Lines[0].ShippedQuantity != 30 || current.Lines[1].Shippable() != 0 {
+		t.Errorf("Expected shipped quantities on the lines, got %+v", current.Lines)
+	}
+
+	// Cancelled orders are closed to the supplier
+	if _, err := replenishment.CancelPurchaseOrder(ctx, order.ID); err != nil {
+		t.Fatalf("CancelPurchaseOrder() error = %v", err)
+	}
+	if _, err := portal.SubmitShippingNotice(ctx, "ACME", order.ID, over); !errors.Is(err, ErrPurchaseOrderNotDraft) {
+		t.Errorf("Expected a closed order to refuse shipping notices, got %v", err)
+	}
+}
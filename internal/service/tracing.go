@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the spans of this package
+const tracerName = meterName
+
+// WithTracerProvider traces stock operations, reservations and product lock
+// waits with the given OpenTelemetry tracer provider. The spans are children
+// of the span in the caller's context, so they join the request's trace.
+func WithTracerProvider(provider trace.TracerProvider) InventoryServiceOption {
+	return func(s *InventoryService) {
+		s.tracer = provider.Tracer(tracerName)
+	}
+}
+
+// noopTracer returns a tracer that records nothing
+func noopTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// startSpan starts the span of a service operation; end it with endSpan
+func (s *InventoryService) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, marking it failed when err is set
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// stockAttributes describes the product, location and quantity of a stock
+// operation; an empty location is the product's default location
+func stockAttributes(productID, location string, quantity int64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("inventory.product_id", productID),
+		attribute.String("inventory.location", location),
+		attribute.Int64("inventory.quantity", quantity),
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	inventoryService := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository(), WithTracerProvider(provider))
+	reservationService := NewReservationService(inventoryService, NewMockReservationRepository(), time.Minute)
+
+	// The operations join the caller's trace
+	parentCtx, parent := provider.Tracer("test").Start(ctx, "request")
+	if err := inventoryService.AddStock(parentCtx, "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("Failed to add stock: %v", err)
+	}
+	if _, err := reservationService.CreateReservation(parentCtx, "prod-1", "", "", 3, "SO-1", 0); err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	if err := inventoryService.RemoveStock(parentCtx, "prod-1", 50, "SO-2"); err == nil {
+		t.Fatal("Expected removal beyond stock to fail")
+	}
+	parent.End()
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+
+	for _, name := range []string{"InventoryService.AddStock", "ReservationService.CreateReservation", "InventoryService.RemoveStock"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Fatalf("Expected a %s span, got %v", name, exporter.GetSpans())
+		}
+		if span.Parent.TraceID() != parent.SpanContext().TraceID() {
+			t.Errorf("Expected %s to join the caller's trace", name)
+		}
+	}
+
+	reserve := spans["InventoryService.ReserveStock"]
+	if reserve.Parent.SpanID() != spans["ReservationService.CreateReservation"].SpanContext.SpanID() {
+		t.Error("Expected the stock reservation to be a child of the reservation span")
+	}
+	if movement := spans["InventoryService.applyMovement"]; !movement.SpanContext.IsValid() {
+		t.Error("Expected the stock write to be traced")
+	}
+
+	if status := spans["InventoryService.RemoveStock"].Status; status.Code != codes.Error {
+		t.Errorf("Expected the failed removal to be marked as an error, got %+v", status)
+	}
+	if status := spans["InventoryService.AddStock"].Status; status.Code == codes.Error {
+		t.Errorf("Expected the addition to succeed, got %+v", status)
+	}
+}
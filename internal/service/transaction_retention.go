@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+const (
+	// transactionArchiveBatch is the number of transactions moved to the
+	// archive per database transaction
+	transactionArchiveBatch = 5000
+
+	// transactionPartitionsAhead is the number of future months whose
+	// partitions are created in advance
+	transactionPartitionsAhead = 2
+)
+
+// TransactionRetentionService keeps the monthly partitions of the
+// transactions table ahead of time and moves transactions older than the
+// retention period to the archive, dropping the partitions that empties
+type TransactionRetentionService struct {
+	retentionRepo repository.TransactionRetentionRepository
+	retention     time.Duration
+	now           func() time.Time
+}
+
+// NewTransactionRetentionService creates a new TransactionRetentionService;
+// a zero retention keeps every transaction live and only maintains partitions
+func NewTransactionRetentionService(retentionRepo repository.TransactionRetentionRepository, retention time.Duration) (*TransactionRetentionService, error) {
+	if retention < 0 {
+		return nil, errors.New("transaction retention cannot be negative")
+	}
+
+	return &TransactionRetentionService{
+		retentionRepo: retentionRepo,
+		retention:     retention,
+		now:           time.Now,
+	}, nil
+}
+
+// MaintainPartitions creates the partitions of the current month and the
+// following ones; run it before transactions are recorded so none land in
+// the default partition
+func (s *TransactionRetentionService) MaintainPartitions(ctx context.Context) error {
+	now := s.now()
+	created, err := s.retentionRepo.EnsurePartitions(ctx, now, now.AddDate(0, transactionPartitionsAhead, 0))
+	if err != nil {
+		return fmt.Errorf("failed to create transaction partitions: %w", err)
+	}
+	if created > 0 {
+		slog.InfoContext(ctx, "created transaction partitions", "count", created)
+	}
+	return nil
+}
+
+// Archive moves transactions older than the retention period to the
+// archive in batches, drops the partitions that emptied and returns the
+// number of transactions moved
+func (s *TransactionRetentionService) Archive(ctx context.Context) (int64, error) {
+	if s.retention == 0 {
+		return 0, nil
+	}
+
+	cutoff := s.now().Add(-s.retention)
+	var archived int64
+	for {
+		n, err := s.retentionRepo.ArchiveBefore(ctx, cutoff, transactionArchiveBatch)
+		archived += n
+		if err != nil {
+			return archived, err
+		}
+		if n < transactionArchiveBatch {
+			break
+		}
+	}
+
+	dropped, err := s.retentionRepo.DropPartitionsBefore(ctx, cutoff)
+	if len(dropped) > 0 {
+		slog.InfoContext(ctx, "dropped archived transaction partitions", "partitions", dropped)
+	}
+	if err != nil {
+		return archived, err
+	}
+	return archived, nil
+}
+
+// Run maintains partitions and archives every interval until ctx is
+// cancelled
+func (s *TransactionRetentionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !awaitResumed(ctx) {
+				return
+			}
+			if err := s.MaintainPartitions(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "transaction partition maintenance failed", "error", err)
+			}
+			archived, err := s.Archive(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "transaction archiving failed", "error", err)
+			}
+			if archived > 0 {
+				slog.InfoContext(ctx, "archived transactions", "count", archived)
+			}
+		}
+	}
+}
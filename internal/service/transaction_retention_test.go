@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// mockRetentionRepository records partition and archive calls
+type mockRetentionRepository struct {
+	partitionsFrom, partitionsThrough time.Time
+	oldRows                           int64
+	cutoffs                           []time.Time
+	droppedBefore                     time.Time
+	archiveErr                        error
+}
+
+func (m *mockRetentionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) (int, error) {
+	m.partitionsFrom, m.partitionsThrough = from, through
+	return 3, nil
+}
+
+func (m *mockRetentionRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	m.cutoffs = append(m.cutoffs, cutoff)
+	if m.archiveErr != nil {
+		return 0, m.archiveErr
+	}
+	n := min(m.oldRows, int64(limit))
+	m.oldRows -= n
+	return n, nil
+}
+
+func (m *mockRetentionRepository) DropPartitionsBefore(ctx context.Context, cutoff time.Time) ([]string, error) {
+	m.droppedBefore = cutoff
+	return []string{"transactions_p202601"}, nil
+}
+
+func TestTransactionRetentionService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if _, err := NewTransactionRetentionService(&mockRetentionRepository{}, -time.Hour); err == nil {
+		t.Error("Expected a negative retention to be rejected")
+	}
+
+	repo := &mockRetentionRepository{oldRows: 2*transactionArchiveBatch + 10}
+	retention, err := NewTransactionRetentionService(repo, 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewTransactionRetentionService() error = %v", err)
+	}
+	retention.now = func() time.Time { return now }
+
+	if err := retention.MaintainPartitions(ctx); err != nil {
+		t.Fatalf("MaintainPartitions() error = %v", err)
+	}
+	if !repo.partitionsFrom.Equal(now) || !repo.partitionsThrough.Equal(now.AddDate(0, transactionPartitionsAhead, 0)) {
+		t.Errorf("Expected partitions from now through %d months ahead, got %v to %v", transactionPartitionsAhead, repo.partitionsFrom, repo.partitionsThrough)
+	}
+
+	// Archiving continues batch by batch until the old rows are moved
+	archived, err := retention.Archive(ctx)
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	cutoff := now.Add(-90 * 24 * time.Hour)
+	if archived != 2*transactionArchiveBatch+10 || len(repo.cutoffs) != 3 || !repo.cutoffs[0].Equal(cutoff) {
+		t.Errorf("Expected all old rows archived in 3 batches before %v, got %d in %v", cutoff, archived, repo.cutoffs)
+	}
+	if !repo.droppedBefore.Equal(cutoff) {
+		t.Errorf("Expected partitions before %v to be dropped, got %v", cutoff, repo.droppedBefore)
+	}
+
+	// Failed batches are reported and leave the partitions alone
+	repo = &mockRetentionRepository{archiveErr: errors.New("database unavailable")}
+	retention.retentionRepo = repo
+	if _, err := retention.Archive(ctx); err == nil {
+		t.Error("Expected the archive error to be returned")
+	}
+	if !repo.droppedBefore.IsZero() {
+		t.Error("Expected no partitions to be dropped after a failed archive")
+	}
+
+	// Without a retention period nothing is archived
+	repo = &mockRetentionRepository{oldRows: 10}
+	keepAll, _ := NewTransactionRetentionService(repo, 0)
+	if archived, err := keepAll.Archive(ctx); err != nil || archived != 0 || len(repo.cutoffs) != 0 {
+		t.Errorf("Expected nothing archived without retention, got %d, %v", archived, err)
+	}
+}
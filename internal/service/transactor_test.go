@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// MockTransactor implements repository.Transactor over the mock repositories,
+// restoring their contents when a unit of work fails
+type MockTransactor struct {
+	inventory    *MockInventoryRepository
+	transactions *MockTransactionRepository
+	depth        int
+	commits      int
+	rollbacks    int
+}
+
+func NewMockTransactor(inventory *MockInventoryRepository, transactions *MockTransactionRepository) *MockTransactor {
+	return &MockTransactor{inventory: inventory, transactions: transactions}
+}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Inner units of work join the outer one
+	if m.depth > 0 {
+		return fn(ctx)
+	}
+
+	items := make(map[string]*domain.InventoryItem, len(m.inventory.items))
+	for id, item := range m.inventory.items {
+		copied := *item
+		items[id] = &copied
+	}
+	transactions := make(map[string]*domain.Transaction, len(m.transactions.transactions))
+	for id, transaction := range m.transactions.transactions {
+		transactions[id] = transaction
+	}
+
+	ctx, committed := repository.WithCommitHooks(ctx)
+	m.depth++
+	err := fn(ctx)
+	m.depth--
+	if err != nil {
+		m.inventory.items = items
+		m.transactions.transactions = transactions
+		m.rollbacks++
+		return err
+	}
+	m.commits++
+	committed()
+	return nil
+}
+
+func TestStockMovementUnitOfWork(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	transactor := NewMockTransactor(inventoryRepo, transactionRepo)
+	handler := &recordingTransactionHandler{}
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo,
+		WithTransactor(transactor), WithTransactionHandlers(handler))
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	if err := service.AddStock(ctx, "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	if transactor.commits != 1 || len(transactionRepo.transactions) != 1 {
+		t.Errorf("Expected 1 commit and 1 transaction, got %d and %d", transactor.commits, len(transactionRepo.transactions))
+	}
+
+	transactionRepo.failCreate = errors.New("connection reset")
+	if err := service.RemoveStock(ctx, "prod-1", 3, "ORD-1"); err == nil {
+		t.Fatal("Expected RemoveStock to fail when its transaction cannot be recorded")
+	}
+	if transactor.rollbacks != 1 {
+		t.Errorf("Expected 1 rollback, got %d", transactor.rollbacks)
+	}
+
+	item, _ := inventoryRepo.GetByID(ctx, "inv-1")
+	if item.Quantity != 15 {
+		t.Errorf("Expected quantity 15 after rollback, got %d", item.Quantity)
+	}
+	if len(handler.types) != 1 {
+		t.Errorf("Expected only the committed movement to be notified, got %v", handler.types)
+	}
+}
+
+func TestMovementEffectsWaitForEnclosingUnitOfWork(t *testing.T) {
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	transactor := NewMockTransactor(inventoryRepo, transactionRepo)
+	handler := &committedHandler{transactor: transactor}
+	service := NewInventoryService(NewMockProductRepository(), inventoryRepo, transactionRepo,
+		WithTransactor(transactor), WithTransactionHandlers(handler))
+	ctx := context.Background()
+
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	// The enclosing work fails after the movement, so nothing was committed
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := service.AddStock(ctx, "prod-1", 5, "PO-1"); err != nil {
+			return err
+		}
+		return errors.New("failed to record transfer")
+	})
+	if err == nil {
+		t.Fatal("Expected the enclosing unit of work to fail")
+	}
+	if handler.notified != 0 {
+		t.Errorf("Expected no notification of a rolled back movement, got %d", handler.notified)
+	}
+
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return service.AddStock(ctx, "prod-1", 5, "PO-2")
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction() error = %v", err)
+	}
+	if handler.notified != 1 || handler.uncommitted != 0 {
+		t.Errorf("Expected 1 notification after commit, got %d with %d before", handler.notified, handler.uncommitted)
+	}
+}
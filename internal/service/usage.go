@@ -0,0 +1,326 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/httpclient"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+const (
+	// MaxUsageReportDays is the longest period a usage report covers
+	MaxUsageReportDays = 366
+
+	// maxUnpublishedUsageEvents bounds the usage events kept for the next
+	// attempt while the billing integration is unavailable; the oldest are
+	// dropped beyond it
+	maxUnpublishedUsageEvents = 10000
+)
+
+type tenantKey struct{}
+
+// WithTenant attributes the work done with ctx, such as recorded
+// transactions, to the tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ctx is attributed to, or ""
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// UsagePublisher sends usage events to a billing integration
+type UsagePublisher interface {
+	PublishUsage(ctx context.Context, events []domain.UsageEvent) error
+}
+
+// usageKey identifies the counters of one tenant on one UTC day
+type usageKey struct {
+	day    time.Time
+	tenant string
+}
+
+// UsageMeter counts API calls and transactions per tenant in memory and
+// periodically adds them to the daily counters of the repository. Flushed
+// counts are published as usage events when a publisher is configured.
+type UsageMeter struct {
+	usageRepo repository.UsageRepository
+	publisher UsagePublisher
+	now       func() time.Time
+
+	mu     sync.Mutex
+	counts map[usageKey]*domain.TenantUsage
+	since  time.Time
+
+	// flushMu serializes flushes and guards unpublished
+	flushMu     sync.Mutex
+	unpublished []domain.UsageEvent
+}
+
+// NewUsageMeter creates a new UsageMeter; publisher may be nil
+func NewUsageMeter(usageRepo repository.UsageRepository, publisher UsagePublisher) *UsageMeter {
+	m := &UsageMeter{
+		usageRepo: usageRepo,
+		publisher: publisher,
+		now:       time.Now,
+		counts:    make(map[usageKey]*domain.TenantUsage),
+	}
+	m.since = m.now()
+	return m
+}
+
+// RecordCall counts an API call of the tenant
+func (m *UsageMeter) RecordCall(tenant string) {
+	m.add(tenant, &domain.TenantUsage{APICalls: 1})
+}
+
+// HandleTransaction counts a recorded transaction, and its quantity, for the
+// tenant of ctx; transactions outside requests count for SystemTenant.
+// It implements TransactionHandler.
+func (m *UsageMeter) HandleTransaction(ctx context.Context, transaction *domain.Transaction) {
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		tenant = domain.SystemTenant
+	}
+	m.add(tenant, &domain.TenantUsage{Transactions: 1, TransactionUnits: max(transaction.Quantity, -transaction.Quantity)})
+}
+
+// add adds counts to the tenant's counters of today
+func (m *UsageMeter) add(tenant string, counts *domain.TenantUsage) {
+	key := usageKey{day: usageDay(m.now()), tenant: tenant}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := m.counts[key]
+	if !ok {
+		usage = &domain.TenantUsage{Tenant: tenant}
+		m.counts[key] = usage
+	}
+	usage.Add(counts)
+}
+
+// Flush adds the counts since the last flush to the repository and publishes
+// them. Counts that cannot be stored are kept for the next flush; events that
+// cannot be published are retried on the next flush.
+func (m *UsageMeter) Flush(ctx context.Context) error {
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+
+	m.mu.Lock()
+	counts, since, until := m.counts, m.since, m.now()
+	m.counts = make(map[usageKey]*domain.TenantUsage)
+	m.since = until
+	m.mu.Unlock()
+
+	byDay := make(map[time.Time][]*domain.TenantUsage)
+	for key, usage := range counts {
+		byDay[key.day] = append(byDay[key.day], usage)
+	}
+	for day, usage := range byDay {
+		if err := m.usageRepo.AddUsage(ctx, day, usage); err != nil {
+			m.restore(counts, since)
+			return fmt.Errorf("failed to store usage: %w", err)
+		}
+		for key := range counts {
+			if key.day.Equal(day) {
+				delete(counts, key)
+			}
+		}
+		if m.publisher != nil {
+			m.unpublished = append(m.unpublished, usageEvents(usage, day, since, until)...)
+		}
+	}
+
+	if m.publisher == nil || len(m.unpublished) == 0 {
+		return nil
+	}
+	if dropped := len(m.unpublished) - maxUnpublishedUsageEvents; dropped > 0 {
+		slog.WarnContext(ctx, "dropping unpublished usage events", "count", dropped)
+		m.unpublished = slices.Delete(m.unpublished, 0, dropped)
+	}
+	if err := m.publisher.PublishUsage(ctx, m.unpublished); err != nil {
+		return fmt.Errorf("failed to publish %d usage events: %w", len(m.unpublished), err)
+	}
+	m.unpublished = nil
+	return nil
+}
+
+// restore merges counts that could not be stored back into the meter
+func (m *UsageMeter) restore(counts map[usageKey]*domain.TenantUsage, since time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, usage := range counts {
+		if current, ok := m.counts[key]; ok {
+			current.Add(usage)
+		} else {
+			m.counts[key] = usage
+		}
+	}
+	m.since = since
+}
+
+// usageEvents converts the counts of a day into one event per tenant and
+// non-zero metric, for the part of the flushed period within the day
+func usageEvents(usage []*domain.TenantUsage, day, since, until time.Time) []domain.UsageEvent {
+	start := since
+	if start.Before(day) {
+		start = day
+	}
+	end := until
+	if next := day.AddDate(0, 0, 1); end.After(next) {
+		end = next
+	}
+
+	var events []domain.UsageEvent
+	for _, u := range usage {
+		for _, metric := range []struct {
+			name     string
+			quantity int64
+		}{
+			{domain.UsageMetricAPICalls, u.APICalls},
+			{domain.UsageMetricTransactions, u.Transactions},
+			{domain.UsageMetricTransactionUnits, u.TransactionUnits},
+		} {
+			if metric.quantity > 0 {
+				events = append(events, domain.UsageEvent{
+					Tenant:      u.Tenant,
+					Metric:      metric.name,
+					Quantity:    metric.quantity,
+					PeriodStart: start.UTC(),
+					PeriodEnd:   end.UTC(),
+				})
+			}
+		}
+	}
+	return events
+}
+
+// Run flushes the counts every interval until ctx is cancelled. The owner
+// flushes once more after the last request finished.
+func (m *UsageMeter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "usage flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// Report returns the usage of the days from from to to (UTC), inclusive,
+// including counts not flushed yet. A non-empty tenant restricts the report
+// to it.
+func (m *UsageMeter) Report(ctx context.Context, tenant string, from, to time.Time) (*domain.UsageReport, error) {
+	from, to = usageDay(from), usageDay(to)
+	if to.Before(from) {
+		return nil, domain.NewValidationError("from must not be after to")
+	}
+	if to.Sub(from) >= MaxUsageReportDays*24*time.Hour {
+		return nil, domain.NewValidationError("usage reports cover at most %d days", MaxUsageReportDays)
+	}
+
+	// Hold off flushes, whose counts are neither in memory nor stored yet
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+
+	stored, err := m.usageRepo.Usage(ctx, tenant, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	skus, err := m.usageRepo.CountStoredSKUs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byTenant := make(map[string]*domain.TenantUsage, len(stored))
+	for _, u := range stored {
+		byTenant[u.Tenant] = u
+	}
+	m.mu.Lock()
+	for key, usage := range m.counts {
+		if key.day.Before(from) || key.day.After(to) || (tenant != "" && key.tenant != tenant) {
+			continue
+		}
+		if total, ok := byTenant[key.tenant]; ok {
+			total.Add(usage)
+		} else {
+			total := *usage
+			byTenant[key.tenant] = &total
+		}
+	}
+	m.mu.Unlock()
+
+	report := &domain.UsageReport{From: from, To: to, StoredSKUs: skus, Tenants: make([]*domain.TenantUsage, 0, len(byTenant))}
+	for _, u := range byTenant {
+		report.Tenants = append(report.Tenants, u)
+	}
+	slices.SortFunc(report.Tenants, func(a, b *domain.TenantUsage) int {
+		return strings.Compare(a.Tenant, b.Tenant)
+	})
+	return report, nil
+}
+
+// usageDay returns the UTC day of t
+func usageDay(t time.Time) time.Time {
+	y, mo, d := t.UTC().Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC)
+}
+
+// WebhookUsagePublisher publishes usage events by POSTing them as JSON,
+// {"events": [...]}, to a billing endpoint. Any 2xx response acknowledges
+// them.
+type WebhookUsagePublisher struct {
+	url    string
+	client httpclient.Doer
+}
+
+// NewWebhookUsagePublisher creates a publisher for the webhook URL
+func NewWebhookUsagePublisher(url string, client httpclient.Doer) *WebhookUsagePublisher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookUsagePublisher{url: url, client: client}
+}
+
+// PublishUsage implements UsagePublisher
+func (p *WebhookUsagePublisher) PublishUsage(ctx context.Context, events []domain.UsageEvent) error {
+	payload, err := json.Marshal(map[string][]domain.UsageEvent{"events": events})
+	if err != nil {
+		return fmt.Errorf("failed to encode usage events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build usage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("usage webhook %s: %w", p.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("usage webhook %s returned status %d", p.url, resp.StatusCode)
+	}
+	return nil
+}
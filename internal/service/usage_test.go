@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockUsageRepository is a mock implementation of UsageRepository
+type MockUsageRepository struct {
+	mu    sync.Mutex
+	days  map[time.Time]map[string]*domain.TenantUsage
+	skus  int64
+	fails bool
+}
+
+func NewMockUsageRepository() *MockUsageRepository {
+	return &MockUsageRepository{days: make(map[time.Time]map[string]*domain.TenantUsage)}
+}
+
+func (m *MockUsageRepository) AddUsage(ctx context.Context, day time.Time, usage []*domain.TenantUsage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fails {
+		return errors.New("database unavailable")
+	}
+	if m.days[day] == nil {
+		m.days[day] = make(map[string]*domain.TenantUsage)
+	}
+	for _, u := range usage {
+		if m.days[day][u.Tenant] == nil {
+			m.days[day][u.Tenant] = &domain.TenantUsage{Tenant: u.Tenant}
+		}
+		m.days[day][u.Tenant].Add(u)
+	}
+	return nil
+}
+
+func (m *MockUsageRepository) Usage(ctx context.Context, tenant string, from, to time.Time) ([]*domain.TenantUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	totals := make(map[string]*domain.TenantUsage)
+	for day, tenants := range m.days {
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		for name, u := range tenants {
+			if tenant != "" && name != tenant {
+				continue
+			}
+			if totals[name] == nil {
+				totals[name] = &domain.TenantUsage{Tenant: name}
+			}
+			totals[name].Add(u)
+		}
+	}
+	var usage []*domain.TenantUsage
+	for _, u := range totals {
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+func (m *MockUsageRepository) CountStoredSKUs(ctx context.Context) (int64, error) {
+	return m.skus, nil
+}
+
+// recordingPublisher records published usage events
+type recordingPublisher struct {
+	events []domain.UsageEvent
+	fails  bool
+}
+
+func (p *recordingPublisher) PublishUsage(ctx context.Context, events []domain.UsageEvent) error {
+	if p.fails {
+		return errors.New("billing unavailable")
+	}
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func TestUsageMeter(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 10, Location: "WH-A"})
+
+	usageRepo := NewMockUsageRepository()
+	usageRepo.skus = 1
+	publisher := &recordingPublisher{}
+	meter := NewUsageMeter(usageRepo, publisher)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	meter.now = func() time.Time { return now }
+	service := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository(), WithTransactionHandlers(meter))
+
+	// Transactions count for the tenant of the request, or the system
+	meter.RecordCall("shop")
+	meter.RecordCall("shop")
+	if err := service.AddStock(WithTenant(ctx, "shop"), "prod-1", 5, "PO-1"); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	if err := service.RemoveStock(ctx, "prod-1", 2, "EXPIRY"); err != nil {
+		t.Fatalf("RemoveStock() error = %v", err)
+	}
+
+	// Reports include counts not flushed yet
+	report, err := meter.Report(ctx, "", now, now)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if report.StoredSKUs != 1 || len(report.Tenants) != 2 {
+		t.Fatalf("Expected one SKU and two tenants, got %+v", report)
+	}
+	if shop := report.Tenants[0]; shop.Tenant != "shop" || shop.APICalls != 2 || shop.Transactions != 1 || shop.TransactionUnits != 5 {
+		t.Errorf("Unexpected shop usage: %+v", shop)
+	}
+	if system := report.Tenants[1]; system.Tenant != domain.SystemTenant || system.Transactions != 1 || system.TransactionUnits != 2 {
+		t.Errorf("Unexpected system usage: %+v", system)
+	}
+
+	if err := meter.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(publisher.events) != 5 {
+		t.Errorf("Expected 5 usage events (3 for shop, 2 for system), got %+v", publisher.events)
+	}
+
+	// Flushed and new counts add up, and a tenant filter applies
+	meter.RecordCall("shop")
+	report, err = meter.Report(ctx, "shop", now.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	if len(report.Tenants) != 1 || report.Tenants[0].APICalls != 3 {
+		t.Errorf("Expected 3 calls of shop, got %+v", report.Tenants)
+	}
+
+	if _, err := meter.Report(ctx, "", now, now.AddDate(0, 0, -1)); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error for a reversed period, got %v", err)
+	}
+	if _, err := meter.Report(ctx, "", now.AddDate(0, 0, -MaxUsageReportDays), now); !errors.Is(err, domain.ErrValidation) {
+		t.Errorf("Expected a validation error beyond %d days, got %v", MaxUsageReportDays, err)
+	}
+}
+
+func TestUsageMeterKeepsUnflushedUsage(t *testing.T) {
+	ctx := context.Background()
+	usageRepo := NewMockUsageRepository()
+	publisher := &recordingPublisher{fails: true}
+	meter := NewUsageMeter(usageRepo, publisher)
+
+	// Counts that cannot be stored are kept for the next flush
+	usageRepo.fails = true
+	meter.RecordCall("shop")
+	if err := meter.Flush(ctx); err == nil {
+		t.Fatal("Expected the flush to fail")
+	}
+	meter.RecordCall("shop")
+	usageRepo.fails = false
+
+	// Events that cannot be published are retried
+	if err := meter.Flush(ctx); err == nil {
+		t.Fatal("Expected publishing to fail")
+	}
+	publisher.fails = false
+	if err := meter.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Metric != domain.UsageMetricAPICalls || publisher.events[0].Quantity != 2 {
+		t.Errorf("Expected one event of 2 API calls, got %+v", publisher.events)
+	}
+
+	day := usageDay(time.Now())
+	if u := usageRepo.days[day]["shop"]; u == nil || u.APICalls != 2 {
+		t.Errorf("Expected 2 stored API calls, got %+v", u)
+	}
+}
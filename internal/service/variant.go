@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// NewVariant describes a variant to create under a parent product. Name and
+// Price default to the parent's, the name followed by the attribute values.
+type NewVariant struct {
+	SKU             string
+	Name            string
+	Price           *float64
+	Attributes      map[string]string
+	Location        string
+	InitialQuantity int64
+}
+
+// VariantService manages the variants of products, such as sizes and colors.
+// Variants are products of their own, so stock operations, reservations and
+// availability checks address them like any product; the service links them
+// to their parent and rolls their stock up.
+type VariantService struct {
+	inventory   *InventoryService
+	variantRepo repository.VariantRepository
+}
+
+// NewVariantService creates a new VariantService
+func NewVariantService(inventory *InventoryService, variantRepo repository.VariantRepository) *VariantService {
+	return &VariantService{
+		inventory:   inventory,
+		variantRepo: variantRepo,
+	}
+}
+
+// CreateVariant creates a variant of a parent product, stocked with the
+// initial quantity at the location. The variant takes the description and
+// category of its parent. Variants cannot have variants of their own, and
+// the attributes of the variants of a parent are unique.
+func (s *VariantService) CreateVariant(ctx context.Context, parentID string, request NewVariant) (*domain.ProductVariant, error) {
+	if err := domain.ValidateVariantAttributes(request.Attributes); err != nil {
+		return nil, err
+	}
+
+	parent, err := s.inventory.productRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Archived() {
+		return nil, domain.NewValidationError("product %s is archived", parentID)
+	}
+	if _, err := s.variantRepo.GetByProductID(ctx, parentID); err == nil {
+		return nil, domain.NewValidationError("product %s is a variant; variants cannot have variants", parentID)
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	// Report a duplicate without creating its product; the unique constraint
+	// still settles concurrent creations
+	siblings, err := s.variantRepo.ListByParent(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sibling := range siblings {
+		if maps.Equal(sibling.Attributes, request.Attributes) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrDuplicateVariant, sibling.SKU)
+		}
+	}
+
+	product := &domain.Product{
+		Name:        request.Name,
+		Description: parent.Description,
+		SKU:         request.SKU,
+		Category:    parent.Category,
+		Price:       parent.Price,
+	}
+	if product.Name == "" {
+		product.Name = parent.Name + " - " + domain.VariantLabel(request.Attributes)
+	}
+	if request.Price != nil {
+		product.Price = *request.Price
+	}
+	if err := s.inventory.CreateProduct(ctx, product, request.Location, request.InitialQuantity); err != nil {
+		return nil, err
+	}
+
+	variant := &domain.ProductVariant{
+		ProductID:  product.ID,
+		ParentID:   parentID,
+		SKU:        product.SKU,
+		Name:       product.Name,
+		Price:      product.Price,
+		Attributes: request.Attributes,
+		Quantity:   request.InitialQuantity,
+		Available:  request.InitialQuantity,
+	}
+	if err := s.variantRepo.Create(ctx, variant); err != nil {
+		// Clean up the product if it cannot be linked
+		_ = s.inventory.productRepo.Delete(ctx, product.ID)
+		return nil, err
+	}
+	return variant, nil
+}
+
+// ListVariants lists the variants of a parent product with their stock
+// rolled up
+func (s *VariantService) ListVariants(ctx context.Context, parentID string) (*domain.VariantRollup, error) {
+	if _, err := s.inventory.productRepo.GetByID(ctx, parentID); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.variantRepo.ListByParent(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RollupVariants(parentID, variants), nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// sequentialProductRepository gives every created product an ID of its own
+type sequentialProductRepository struct {
+	*MockProductRepository
+	created int
+}
+
+func (m *sequentialProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	m.created++
+	product.ID = fmt.Sprintf("variant-%d", m.created)
+	return m.MockProductRepository.Create(ctx, product)
+}
+
+// MockVariantRepository implements VariantRepository interface, reading the
+// stock of variants from the mock inventory
+type MockVariantRepository struct {
+	inventory *MockInventoryRepository
+	variants  []*domain.ProductVariant
+}
+
+func (m *MockVariantRepository) Create(ctx context.Context, variant *domain.ProductVariant) error {
+	for _, existing := range m.variants {
+		if existing.ParentID == variant.ParentID && maps.Equal(existing.Attributes, variant.Attributes) {
+			return domain.ErrDuplicateVariant
+		}
+	}
+	m.variants = append(m.variants, variant)
+	return nil
+}
+
+func (m *MockVariantRepository) GetByProductID(ctx context.Context, productID string) (*domain.ProductVariant, error) {
+	for _, variant := range m.variants {
+		if variant.ProductID == productID {
+			return variant, nil
+		}
+	}
+	return nil, fmt.Errorf("product variant %w", domain.ErrNotFound)
+}
+
+func (m *MockVariantRepository) ListByParent(ctx context.Context, parentID string) ([]*domain.ProductVariant, error) {
+	var variants []*domain.ProductVariant
+	for _, variant := range m.variants {
+		if variant.ParentID != parentID {
+			continue
+		}
+		listed := *variant
+		listed.Quantity, listed.Reserved = 0, 0
+		items, _ := m.inventory.ListByProductID(ctx, variant.ProductID)
+		for _, item := range items {
+			listed.Quantity += item.Quantity
+			listed.Reserved += item.Reserved
+		}
+		listed.Available = listed.Quantity - listed.Reserved
+		variants = append(variants, &listed)
+	}
+	return variants, nil
+}
+
+func TestProductVariants(t *testing.T) {
+	ctx := context.Background()
+	productRepo := &sequentialProductRepository{MockProductRepository: NewMockProductRepository()}
+	inventoryRepo := NewMockInventoryRepository()
+	inventoryService := NewInventoryService(productRepo, inventoryRepo, NewMockTransactionRepository())
+	variantRepo := &MockVariantRepository{inventory: inventoryRepo}
+	service := NewVariantService(inventoryService, variantRepo)
+	productRepo.products["shirt"] = &domain.Product{ID: "shirt", Name: "T-Shirt", SKU: "TS", Category: "Apparel", Price: 20}
+
+	red, err := service.CreateVariant(ctx, "shirt", NewVariant{
+		SKU: "TS-RED-M", Attributes: map[string]string{"size": "M", "color": "Red"}, Location: "WH-A", InitialQuantity: 10,
+	})
+	if err != nil {
+		t.Fatalf("CreateVariant() error = %v", err)
+	}
+	if red.Name != "T-Shirt - Red / M" || red.Price != 20 || productRepo.products[red.ProductID].Category != "Apparel" {
+		t.Errorf("Expected the parent's name, price and category, got %+v", red)
+	}
+	price := 25.0
+	blue, err := service.CreateVariant(ctx, "shirt", NewVariant{
+		SKU: "TS-BLUE-M", Price: &price, Attributes: map[string]string{"size": "M", "color": "Blue"}, Location: "WH-A", InitialQuantity: 4,
+	})
+	if err != nil {
+		t.Fatalf("CreateVariant() error = %v", err)
+	}
+	if blue.Price != 25 {
+		t.Errorf("Expected the variant's own price, got %v", blue.Price)
+	}
+
+	// Variants are products: their stock is reserved like any other
+	if err := inventoryService.ReserveStock(ctx, red.ProductID, 3, "ORDER-1"); err != nil {
+		t.Fatalf("Failed to reserve a variant: %v", err)
+	}
+
+	rollup, err := service.ListVariants(ctx, "shirt")
+	if err != nil {
+		t.Fatalf("ListVariants() error = %v", err)
+	}
+	if len(rollup.Variants) != 2 || rollup.Quantity != 14 || rollup.Reserved != 3 || rollup.Available != 11 {
+		t.Errorf("Unexpected rollup %+v", rollup)
+	}
+	if rollup.ByAttribute["size"]["M"] != 11 || rollup.ByAttribute["color"]["Red"] != 7 || rollup.ByAttribute["color"]["Blue"] != 4 {
+		t.Errorf("Unexpected availability by attribute %v", rollup.ByAttribute)
+	}
+
+	tests := []struct {
+		name     string
+		parentID string
+		request  NewVariant
+		wantErr  error
+	}{
+		{"Same attributes", "shirt", NewVariant{SKU: "TS-RED-M2", Attributes: map[string]string{"color": "Red", "size": "M"}}, domain.ErrDuplicateVariant},
+		{"No attributes", "shirt", NewVariant{SKU: "TS-PLAIN"}, domain.ErrValidation},
+		{"Variant of a variant", red.ProductID, NewVariant{SKU: "TS-RED-M-V", Attributes: map[string]string{"fit": "Slim"}}, domain.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateVariant(ctx, tt.parentID, tt.request); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(productRepo.products) != 3 {
+		t.Errorf("Expected rejected variants to create no product, got %d products", len(productRepo.products))
+	}
+}
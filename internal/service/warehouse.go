@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// WarehouseService handles warehouse management
+type WarehouseService struct {
+	warehouseRepo repository.WarehouseRepository
+}
+
+// NewWarehouseService creates a new WarehouseService
+func NewWarehouseService(warehouseRepo repository.WarehouseRepository) *WarehouseService {
+	return &WarehouseService{
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+// CreateWarehouse registers a new warehouse. Codes are unique and are what
+// inventory locations refer to.
+func (s *WarehouseService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	warehouse.Code = strings.TrimSpace(warehouse.Code)
+	normalizeCalendar(warehouse)
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("invalid warehouse: %w", err)
+	}
+
+	if existing, err := s.warehouseRepo.GetByCode(ctx, warehouse.Code); err == nil && existing != nil {
+		return fmt.Errorf("warehouse %s already exists", warehouse.Code)
+	}
+
+	if err := s.warehouseRepo.Create(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to create warehouse: %w", err)
+	}
+	return nil
+}
+
+// GetWarehouse retrieves a warehouse by ID
+func (s *WarehouseService) GetWarehouse(ctx context.Context, id string) (*domain.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	return warehouse, nil
+}
+
+// ListWarehouses lists warehouses with pagination
+func (s *WarehouseService) ListWarehouses(ctx context.Context, limit, offset int) ([]*domain.Warehouse, error) {
+	warehouses, err := s.warehouseRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list warehouses: %w", err)
+	}
+	return warehouses, nil
+}
+
+// UpdateWarehouse saves the name, address and ship schedule of a warehouse;
+// its code cannot change as inventory locations refer to it
+func (s *WarehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) error {
+	existing, err := s.warehouseRepo.GetByID(ctx, warehouse.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	warehouse.Code = existing.Code
+	warehouse.CreatedAt = existing.CreatedAt
+	normalizeCalendar(warehouse)
+	if err := warehouse.Validate(); err != nil {
+		return fmt.Errorf("invalid warehouse: %w", err)
+	}
+
+	if err := s.warehouseRepo.Update(ctx, warehouse); err != nil {
+		return fmt.Errorf("failed to update warehouse: %w", err)
+	}
+	return nil
+}
+
+// normalizeCalendar stores the working days of a warehouse upper case, as
+// MON to SUN, and its holidays sorted without duplicates
+func normalizeCalendar(warehouse *domain.Warehouse) {
+	for i, day := range warehouse.WorkingDays {
+		warehouse.WorkingDays[i] = strings.ToUpper(strings.TrimSpace(day))
+	}
+	slices.Sort(warehouse.Holidays)
+	warehouse.Holidays = slices.Compact(warehouse.Holidays)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+	"github.com/bhnrathore/distributed-inventory-system/internal/repository"
+)
+
+// WMSMessage is a message consumed from the WMS topic with its position
+type WMSMessage struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Value     []byte
+}
+
+// WMSMessageSource delivers the messages of the WMS topic in order per
+// partition, at least once. Commit marks messages as processed; Reset drops
+// what was fetched but not committed, so that the next Fetch resumes from the
+// last commit.
+type WMSMessageSource interface {
+	Fetch(ctx context.Context) ([]WMSMessage, error)
+	Commit(ctx context.Context, messages []WMSMessage) error
+	Reset(ctx context.Context) error
+}
+
+// WMSService applies the warehouse scan events of a WMS to the stock:
+// receipts are added and picks removed at the scanned location. Each event ID
+// is applied once per tenant. Messages that cannot be applied, and would not
+// be on a retry, are dead-lettered; other failures stop consumption until
+// they are retried.
+type WMSService struct {
+	inventory *InventoryService
+	repo      repository.WMSRepository
+}
+
+// NewWMSService creates a new WMSService
+func NewWMSService(inventory *InventoryService, repo repository.WMSRepository) *WMSService {
+	return &WMSService{
+		inventory: inventory,
+		repo:      repo,
+	}
+}
+
+// Process applies the event of a message, or dead-letters the message. It
+// returns an error only when the message should be retried, e.g. because the
+// database is unavailable.
+func (s *WMSService) Process(ctx context.Context, message WMSMessage) error {
+	var event domain.WMSEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return s.deadLetter(ctx, message, &event, fmt.Errorf("malformed event: %w", err))
+	}
+
+	err := s.apply(ctx, &event)
+	if errors.Is(err, domain.ErrDuplicateWMSEvent) {
+		slog.DebugContext(ctx, "WMS event already applied", "event_id", event.EventID)
+		return nil
+	}
+	if err != nil && wmsPermanent(err) {
+		return s.deadLetter(ctx, message, &event, err)
+	}
+	return err
+}
+
+// apply applies a parsed event in the scope of its tenant, recording it in
+// the same unit of work as the stock change. It returns
+// ErrDuplicateWMSEvent when the event was applied before.
+func (s *WMSService) apply(ctx context.Context, event *domain.WMSEvent) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if event.TenantID == "" {
+		event.TenantID = domain.DefaultTenantID
+	}
+	if event.ScannedAt.IsZero() {
+		event.ScannedAt = time.Now()
+	}
+	ctx = domain.WithTenantID(ctx, event.TenantID)
+
+	if _, err := s.repo.GetEvent(ctx, event.EventID); err == nil {
+		return domain.ErrDuplicateWMSEvent
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	if event.ProductID == "" {
+		productID, err := s.inventory.ResolveSKU(ctx, event.SKU)
+		if err != nil {
+			return err
+		}
+		event.ProductID = productID
+	}
+	reference := event.Reference
+	if reference == "" {
+		reference = event.EventID
+	}
+
+	return s.inventory.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if event.Type == domain.WMSEventReceive {
+			err = s.inventory.AddStockAt(ctx, event.ProductID, event.Location, domain.ConditionNew, event.Quantity, reference)
+		} else {
+			err = s.inventory.RemoveStockAt(ctx, event.ProductID, event.Location, domain.ConditionNew, event.Quantity, reference)
+		}
+		switch {
+		case err == nil:
+			event.Status = domain.WMSEventApplied
+		case errors.Is(err, ErrMovementQueued):
+			event.Status = domain.WMSEventQueued
+		default:
+			return err
+		}
+		return s.repo.RecordEvent(ctx, event)
+	})
+}
+
+// wmsPermanent reports whether an event failed for a reason a retry would not
+// change, so that it is dead-lettered instead
+func wmsPermanent(err error) bool {
+	for _, permanent := range []error{
+		domain.ErrValidation, domain.ErrNotFound, domain.ErrInsufficientStock, ErrMovementRejected, ErrLocationFrozen,
+	} {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// deadLetter records a message that cannot be applied
+func (s *WMSService) deadLetter(ctx context.Context, message WMSMessage, event *domain.WMSEvent, cause error) error {
+	slog.WarnContext(ctx, "WMS event dead-lettered", "topic", message.Topic, "partition", message.Partition,
+		"offset", message.Offset, "event_id", event.EventID, "error", cause)
+
+	return s.repo.DeadLetter(ctx, &domain.WMSDeadLetter{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       message.Key,
+		Payload:   string(message.Value),
+		EventID:   event.EventID,
+		Error:     cause.Error(),
+	})
+}
+
+// ListDeadLetters lists the dead-lettered messages, newest first
+func (s *WMSService) ListDeadLetters(ctx context.Context, limit, offset int) ([]*domain.WMSDeadLetter, error) {
+	letters, err := s.repo.ListDeadLetters(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if letters == nil {
+		letters = []*domain.WMSDeadLetter{}
+	}
+	return letters, nil
+}
+
+// Run consumes the source until ctx is cancelled. Each fetched batch is
+// processed in order and committed as far as it was processed; a message that
+// fails is fetched again after retryDelay, together with the rest of its
+// batch. While the consumers are paused the source is reset, leaving the
+// consumer group, and consumption resumes from the committed offsets.
+func (s *WMSService) Run(ctx context.Context, source WMSMessageSource, retryDelay time.Duration) {
+	for ctx.Err() == nil {
+		if backgroundPaused(ctx) {
+			if err := source.Reset(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to reset WMS consumer", "error", err)
+			}
+			if !awaitResumed(ctx) {
+				return
+			}
+		}
+
+		messages, err := source.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to fetch WMS events", "error", err)
+				sleepContext(ctx, retryDelay)
+			}
+			continue
+		}
+
+		processed := 0
+		for _, message := range messages {
+			if err := s.Process(ctx, message); err != nil {
+				if ctx.Err() == nil {
+					slog.ErrorContext(ctx, "failed to process WMS event", "topic", message.Topic,
+						"partition", message.Partition, "offset", message.Offset, "error", err)
+				}
+				break
+			}
+			processed++
+		}
+
+		if processed > 0 {
+			if err := source.Commit(ctx, messages[:processed]); err != nil && ctx.Err() == nil {
+				// The messages are redelivered and skipped as applied or
+				// dead-lettered before
+				slog.ErrorContext(ctx, "failed to commit WMS events", "error", err)
+			}
+		}
+		if processed < len(messages) {
+			if err := source.Reset(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to reset WMS consumer", "error", err)
+			}
+			sleepContext(ctx, retryDelay)
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
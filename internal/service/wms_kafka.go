@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/httpclient"
+)
+
+// kafkaRESTContentType is the media type of Kafka REST Proxy v2 requests
+const kafkaRESTContentType = "application/vnd.kafka.v2+json"
+
+// kafkaRESTBinaryType is the media type of records fetched in binary format,
+// with base64 keys and values, so that malformed events reach the consumer
+// to be dead-lettered rather than failing the fetch
+const kafkaRESTBinaryType = "application/vnd.kafka.binary.v2+json"
+
+// kafkaRESTFetchTimeout is how long, in milliseconds, a fetch waits for
+// records before returning none
+const kafkaRESTFetchTimeout = 1000
+
+// KafkaRESTSource consumes a Kafka topic in a consumer group through a Kafka
+// REST Proxy (v2 API). Offsets are committed explicitly, never automatically,
+// and a consumer instance that expired on the proxy is created again on the
+// next fetch, resuming from the group's committed offsets.
+type KafkaRESTSource struct {
+	baseURL string
+	group   string
+	topic   string
+	client  httpclient.Doer
+
+	// instanceURL is the consumer instance of the proxy, empty until created
+	instanceURL string
+}
+
+// NewKafkaRESTSource creates a source of the topic for the consumer group of
+// the REST Proxy at baseURL
+func NewKafkaRESTSource(baseURL, group, topic string, client httpclient.Doer) *KafkaRESTSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &KafkaRESTSource{baseURL: strings.TrimSuffix(baseURL, "/"), group: group, topic: topic, client: client}
+}
+
+// kafkaRESTRecord is a record as fetched in binary format
+type kafkaRESTRecord struct {
+	Topic     string  `json:"topic"`
+	Key       *string `json:"key"`
+	Value     *string `json:"value"`
+	Partition int32   `json:"partition"`
+	Offset    int64   `json:"offset"`
+}
+
+// Fetch implements WMSMessageSource, creating and subscribing the consumer
+// instance first if needed
+func (s *KafkaRESTSource) Fetch(ctx context.Context) ([]WMSMessage, error) {
+	if s.instanceURL == "" {
+		if err := s.subscribe(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []kafkaRESTRecord
+	target := fmt.Sprintf("%s/records?timeout=%d", s.instanceURL, kafkaRESTFetchTimeout)
+	if err := s.call(ctx, http.MethodGet, target, nil, &records); err != nil {
+		return nil, err
+	}
+
+	messages := make([]WMSMessage, 0, len(records))
+	for _, record := range records {
+		message := WMSMessage{Topic: record.Topic, Partition: record.Partition, Offset: record.Offset}
+		if record.Key != nil {
+			key, err := base64.StdEncoding.DecodeString(*record.Key)
+			if err != nil {
+				return nil, fmt.Errorf("record %s/%d/%d has an invalid key: %w", record.Topic, record.Partition, record.Offset, err)
+			}
+			message.Key = string(key)
+		}
+		if record.Value != nil {
+			value, err := base64.StdEncoding.DecodeString(*record.Value)
+			if err != nil {
+				return nil, fmt.Errorf("record %s/%d/%d has an invalid value: %w", record.Topic, record.Partition, record.Offset, err)
+			}
+			message.Value = value
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// Commit implements WMSMessageSource, committing the offset of the last of
+// the messages per partition
+func (s *KafkaRESTSource) Commit(ctx context.Context, messages []WMSMessage) error {
+	if s.instanceURL == "" {
+		return fmt.Errorf("kafka consumer instance is not subscribed")
+	}
+
+	type partition struct {
+		topic     string
+		partition int32
+	}
+	last := make(map[partition]int64)
+	var order []partition
+	for _, message := range messages {
+		key := partition{message.Topic, message.Partition}
+		if _, ok := last[key]; !ok {
+			order = append(order, key)
+		}
+		last[key] = max(last[key], message.Offset)
+	}
+
+	type offset struct {
+		Topic     string `json:"topic"`
+		Partition int32  `json:"partition"`
+		Offset    int64  `json:"offset"`
+	}
+	body := struct {
+		Offsets []offset `json:"offsets"`
+	}{}
+	for _, key := range order {
+		body.Offsets = append(body.Offsets, offset{Topic: key.topic, Partition: key.partition, Offset: last[key]})
+	}
+	return s.call(ctx, http.MethodPost, s.instanceURL+"/offsets", body, nil)
+}
+
+// Reset implements WMSMessageSource by deleting the consumer instance; the
+// next fetch creates a new one, which resumes from the committed offsets
+func (s *KafkaRESTSource) Reset(ctx context.Context) error {
+	if s.instanceURL == "" {
+		return nil
+	}
+	instanceURL := s.instanceURL
+	s.instanceURL = ""
+	return s.call(ctx, http.MethodDelete, instanceURL, nil, nil)
+}
+
+// Ping checks that the proxy can be reached and knows the topic
+func (s *KafkaRESTSource) Ping(ctx context.Context) error {
+	return s.call(ctx, http.MethodGet, s.baseURL+"/topics/"+url.PathEscape(s.topic), nil, nil)
+}
+
+// subscribe creates a consumer instance in the group and subscribes it to
+// the topic
+func (s *KafkaRESTSource) subscribe(ctx context.Context) error {
+	config := map[string]string{
+		"format":             "binary",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}
+	var instance struct {
+		BaseURI string `json:"base_uri"`
+	}
+	if err := s.call(ctx, http.MethodPost, s.baseURL+"/consumers/"+url.PathEscape(s.group), config, &instance); err != nil {
+		return err
+	}
+	if instance.BaseURI == "" {
+		return fmt.Errorf("kafka REST proxy returned no consumer instance")
+	}
+
+	subscription := map[string][]string{"topics": {s.topic}}
+	if err := s.call(ctx, http.MethodPost, instance.BaseURI+"/subscription", subscription, nil); err != nil {
+		s.call(ctx, http.MethodDelete, instance.BaseURI, nil, nil)
+		return err
+	}
+	s.instanceURL = instance.BaseURI
+	return nil
+}
+
+// call sends a request to the proxy and decodes the response into out, if
+// not nil. A consumer instance the proxy does not know anymore is dropped.
+func (s *KafkaRESTSource) call(ctx context.Context, method, target string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode kafka REST request: %w", err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return fmt.Errorf("failed to build kafka REST request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", kafkaRESTContentType)
+	}
+	req.Header.Set("Accept", kafkaRESTContentType)
+	if strings.HasSuffix(req.URL.Path, "/records") {
+		req.Header.Set("Accept", kafkaRESTBinaryType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kafka REST proxy %s: %w", s.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if resp.StatusCode == http.StatusNotFound && s.instanceURL != "" && strings.HasPrefix(target, s.instanceURL) {
+			s.instanceURL = ""
+		}
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka REST proxy %s %s returned status %d: %s", method, target, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode kafka REST response: %w", err)
+	}
+	return nil
+}
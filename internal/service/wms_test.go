@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// MockWMSRepository implements WMSRepository for testing
+type MockWMSRepository struct {
+	events      map[string]*domain.WMSEvent
+	deadLetters []*domain.WMSDeadLetter
+	err         error
+}
+
+func NewMockWMSRepository() *MockWMSRepository {
+	return &MockWMSRepository{events: make(map[string]*domain.WMSEvent)}
+}
+
+func (m *MockWMSRepository) RecordEvent(ctx context.Context, event *domain.WMSEvent) error {
+	if _, ok := m.events[event.EventID]; ok {
+		return domain.ErrDuplicateWMSEvent
+	}
+	stored := *event
+	m.events[event.EventID] = &stored
+	return nil
+}
+
+func (m *MockWMSRepository) GetEvent(ctx context.Context, eventID string) (*domain.WMSEvent, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	event, ok := m.events[eventID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *event
+	return &copied, nil
+}
+
+func (m *MockWMSRepository) DeadLetter(ctx context.Context, letter *domain.WMSDeadLetter) error {
+	m.deadLetters = append(m.deadLetters, letter)
+	return nil
+}
+
+func (m *MockWMSRepository) ListDeadLetters(ctx context.Context, limit, offset int) ([]*domain.WMSDeadLetter, error) {
+	return m.deadLetters, nil
+}
+
+// MockWMSMessageSource delivers its messages in one batch and records commits
+type MockWMSMessageSource struct {
+	messages  []WMSMessage
+	committed []WMSMessage
+	resets    int
+	cancel    context.CancelFunc
+}
+
+func (m *MockWMSMessageSource) Fetch(ctx context.Context) ([]WMSMessage, error) {
+	messages := m.messages
+	m.messages = nil
+	if messages == nil {
+		m.cancel()
+	}
+	return messages, nil
+}
+
+func (m *MockWMSMessageSource) Commit(ctx context.Context, messages []WMSMessage) error {
+	m.committed = append(m.committed, messages...)
+	return nil
+}
+
+func (m *MockWMSMessageSource) Reset(ctx context.Context) error {
+	m.resets++
+	return nil
+}
+
+func wmsMessage(offset int64, value string) WMSMessage {
+	return WMSMessage{Topic: "wms", Partition: 0, Offset: offset, Value: []byte(value)}
+}
+
+func TestWMSProcess(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	repo := NewMockWMSRepository()
+
+	ctx := context.Background()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 5, Location: "WH-1"})
+
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)))
+	service := NewWMSService(inventory, repo)
+
+	// A receipt by SKU adds stock, and delivering it again changes nothing
+	receipt := `{"event_id": "dock-1/1", "type": "RECEIVE", "sku": "CON001", "location": "WH-1", "quantity": 10}`
+	for offset := range int64(2) {
+		if err := service.Process(ctx, wmsMessage(offset, receipt)); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 15 {
+		t.Errorf("Expected 15 units after the receipt, got %d", quantity)
+	}
+	if event := repo.events["dock-1/1"]; event == nil || event.Status != domain.WMSEventApplied || event.ProductID != "prod-1" {
+		t.Errorf("Expected the receipt to be recorded as applied, got %+v", event)
+	}
+
+	// A pick removes stock
+	if err := service.Process(ctx, wmsMessage(2, `{"event_id": "pick-1", "type": "PICK", "product_id": "prod-1", "location": "WH-1", "quantity": 4}`)); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 11 {
+		t.Errorf("Expected 11 units after the pick, got %d", quantity)
+	}
+
+	// Messages that cannot be applied are dead-lettered, not retried
+	for offset, value := range []string{
+		`not json`,
+		`{"event_id": "bad-1", "type": "COUNT", "sku": "CON001", "location": "WH-1", "quantity": 1}`,
+		`{"event_id": "bad-2", "type": "RECEIVE", "sku": "UNKNOWN", "location": "WH-1", "quantity": 1}`,
+		`{"event_id": "bad-3", "type": "PICK", "sku": "CON001", "location": "WH-1", "quantity": 100}`,
+	} {
+		if err := service.Process(ctx, wmsMessage(int64(10+offset), value)); err != nil {
+			t.Errorf("Expected %s to be dead-lettered, got error %v", value, err)
+		}
+	}
+	if len(repo.deadLetters) != 4 || repo.deadLetters[2].EventID != "bad-2" || repo.deadLetters[0].Payload != "not json" {
+		t.Errorf("Expected 4 dead letters, got %+v", repo.deadLetters)
+	}
+	if quantity, _ := stock(t, inventoryRepo, "inv-1"); quantity != 11 {
+		t.Errorf("Expected dead letters to leave the stock unchanged, got %d", quantity)
+	}
+
+	// Transient failures are returned for a retry
+	repo.err = errors.New("connection refused")
+	if err := service.Process(ctx, wmsMessage(20, `{"event_id": "dock-1/2", "type": "RECEIVE", "sku": "CON001", "location": "WH-1", "quantity": 1}`)); err == nil {
+		t.Error("Expected a transient failure to be returned")
+	}
+	if len(repo.deadLetters) != 4 {
+		t.Error("Expected a transient failure not to be dead-lettered")
+	}
+}
+
+func TestWMSRunCommitsProcessedMessages(t *testing.T) {
+	productRepo := NewMockProductRepository()
+	inventoryRepo := NewMockInventoryRepository()
+	transactionRepo := NewMockTransactionRepository()
+	repo := &failingAfterWMSRepository{MockWMSRepository: NewMockWMSRepository(), failOn: "e-2"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	productRepo.Create(ctx, &domain.Product{ID: "prod-1", Name: "Console", SKU: "CON001", Price: 499})
+	inventoryRepo.Create(ctx, &domain.InventoryItem{ID: "inv-1", ProductID: "prod-1", Quantity: 5, Location: "WH-1"})
+	inventory := NewInventoryService(productRepo, inventoryRepo, transactionRepo,
+		WithTransactor(NewMockTransactor(inventoryRepo, transactionRepo)))
+	service := NewWMSService(inventory, repo)
+
+	// The second message fails on a repository error: the first is committed
+	// and the consumer reset to fetch the rest again
+	source := &MockWMSMessageSource{cancel: cancel, messages: []WMSMessage{
+		wmsMessage(0, `{"event_id": "e-1", "type": "RECEIVE", "sku": "CON001", "location": "WH-1", "quantity": 1}`),
+		wmsMessage(1, `{"event_id": "e-2", "type": "RECEIVE", "sku": "CON001", "location": "WH-1", "quantity": 1}`),
+	}}
+	service.Run(ctx, source, time.Millisecond)
+
+	if len(source.committed) != 1 || source.committed[0].Offset != 0 {
+		t.Errorf("Expected only offset 0 to be committed, got %+v", source.committed)
+	}
+	if source.resets != 1 {
+		t.Errorf("Expected the consumer to be reset once, got %d", source.resets)
+	}
+	if _, ok := repo.events["e-1"]; !ok {
+		t.Error("Expected e-1 to be applied")
+	}
+}
+
+// failingAfterWMSRepository fails to look up one event ID
+type failingAfterWMSRepository struct {
+	*MockWMSRepository
+	failOn string
+}
+
+func (m *failingAfterWMSRepository) GetEvent(ctx context.Context, eventID string) (*domain.WMSEvent, error) {
+	if eventID == m.failOn {
+		return nil, errors.New("connection refused")
+	}
+	return m.MockWMSRepository.GetEvent(ctx, eventID)
+}
+
+func TestKafkaRESTSource(t *testing.T) {
+	var committed map[string][]map[string]any
+	var config map[string]string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /consumers/inventory-wms":
+			json.NewDecoder(r.Body).Decode(&config)
+			json.NewEncoder(w).Encode(map[string]string{"instance_id": "c1", "base_uri": server.URL + "/consumers/inventory-wms/instances/c1"})
+		case "POST /consumers/inventory-wms/instances/c1/subscription":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /consumers/inventory-wms/instances/c1/records":
+			if accept := r.Header.Get("Accept"); accept != kafkaRESTBinaryType {
+				t.Errorf("Expected records in binary format, got %q", accept)
+			}
+			value := base64.StdEncoding.EncodeToString([]byte(`{"event_id": "e-1"}`))
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"topic": "wms", "key": nil, "value": value, "partition": 0, "offset": 7},
+				{"topic": "wms", "key": nil, "value": value, "partition": 0, "offset": 8},
+				{"topic": "wms", "key": nil, "value": value, "partition": 1, "offset": 3},
+			})
+		case "POST /consumers/inventory-wms/instances/c1/offsets":
+			json.NewDecoder(r.Body).Decode(&committed)
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE /consumers/inventory-wms/instances/c1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	source := NewKafkaRESTSource(server.URL+"/", "inventory-wms", "wms", server.Client())
+	messages, err := source.Fetch(ctx)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if config["format"] != "binary" || config["auto.commit.enable"] != "false" {
+		t.Errorf("Expected a binary consumer without auto commit, got %v", config)
+	}
+	if len(messages) != 3 || string(messages[0].Value) != `{"event_id": "e-1"}` || messages[2].Partition != 1 {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+
+	if err := source.Commit(ctx, messages); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	offsets := committed["offsets"]
+	if len(offsets) != 2 || offsets[0]["offset"] != float64(8) || offsets[1]["offset"] != float64(3) {
+		t.Errorf("Expected the last offset of each partition to be committed, got %v", offsets)
+	}
+
+	if err := source.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if source.instanceURL != "" {
+		t.Error("Expected the consumer instance to be dropped on reset")
+	}
+}
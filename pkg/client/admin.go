@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// GetJob retrieves a background job
+func (c *Client) GetJob(ctx context.Context, id string) (*Job, error) {
+	return call[Job](ctx, c, request{method: http.MethodGet, path: "/api/jobs/" + escape(id)})
+}
+
+// GetJobResult downloads the document produced by a succeeded job along with
+// its content type
+func (c *Client) GetJobResult(ctx context.Context, id string) ([]byte, string, error) {
+	return c.download(ctx, request{method: http.MethodGet, path: "/api/jobs/" + escape(id) + "/result"})
+}
+
+// SyncCatalog compares the products with a catalog and, with Apply, changes
+// them to match it
+func (c *Client) SyncCatalog(ctx context.Context, req CatalogSyncRequest) (*CatalogDiff, error) {
+	return call[CatalogDiff](ctx, c, request{method: http.MethodPost, path: "/api/catalog/sync", body: req})
+}
+
+// StartCatalogSync synchronizes the products with a catalog as a background
+// job, whose summary is the catalog diff
+func (c *Client) StartCatalogSync(ctx context.Context, req CatalogSyncRequest) (*Job, error) {
+	return c.startJob(ctx, request{method: http.MethodPost, path: "/api/catalog/sync", body: req})
+}
+
+// Simulate runs stock operations against the current stock without applying
+// them
+func (c *Client) Simulate(ctx context.Context, req SimulateRequest) (*SimulationResult, error) {
+	return call[SimulationResult](ctx, c, request{method: http.MethodPost, path: "/api/simulate", body: req})
+}
+
+// RedactCustomer redacts a customer identifier from the ledger
+func (c *Client) RedactCustomer(ctx context.Context, customerIdentifier, reason string) (*Redaction, error) {
+	body := map[string]string{"customer_identifier": customerIdentifier, "reason": reason}
+	return call[Redaction](ctx, c, request{method: http.MethodPost, path: "/api/admin/redactions", body: body})
+}
+
+// ListRedactions lists one page of the past redactions
+func (c *Client) ListRedactions(ctx context.Context, opts ListOptions) ([]*Redaction, error) {
+	return callList[*Redaction](ctx, c, request{method: http.MethodGet, path: "/api/admin/redactions", query: pagination(opts)})
+}
+
+// Usage reports the API usage of a period per tenant, of one tenant unless
+// empty
+func (c *Client) Usage(ctx context.Context, q PeriodQuery) (*UsageReport, error) {
+	return call[UsageReport](ctx, c, request{method: http.MethodGet, path: "/api/admin/usage", query: periodQuery(q)})
+}
+
+// DataQualityReport retrieves the findings of the latest data quality check
+func (c *Client) DataQualityReport(ctx context.Context) (*DataQualityReport, error) {
+	return call[DataQualityReport](ctx, c, request{method: http.MethodGet, path: "/api/admin/data-quality"})
+}
+
+// CheckDataQuality runs the data quality checks now; fix also repairs the
+// fixable findings
+func (c *Client) CheckDataQuality(ctx context.Context, fix bool) (*DataQualityReport, error) {
+	query := url.Values{}
+	setBool(query, "fix", fix)
+	return call[DataQualityReport](ctx, c, request{method: http.MethodPost, path: "/api/admin/data-quality/check", query: query})
+}
+
+// ListWMSDeadLetters lists one page of the warehouse management events that
+// could not be applied
+func (c *Client) ListWMSDeadLetters(ctx context.Context, opts ListOptions) ([]*WMSDeadLetter, error) {
+	return callList[*WMSDeadLetter](ctx, c, request{method: http.MethodGet, path: "/api/admin/wms/dead-letters",
+		query: pagination(opts)})
+}
+
+// ListPayloadSamples lists one page of the sampled request payloads, of one
+// path unless empty
+func (c *Client) ListPayloadSamples(ctx context.Context, path string, opts ListOptions) ([]*PayloadSample, error) {
+	query := pagination(opts)
+	setString(query, "path", path)
+	return callList[*PayloadSample](ctx, c, request{method: http.MethodGet, path: "/api/audit/samples", query: query})
+}
+
+// BackgroundStatus reports whether each background component is running
+func (c *Client) BackgroundStatus(ctx context.Context) ([]*BackgroundStatus, error) {
+	return callList[*BackgroundStatus](ctx, c, request{method: http.MethodGet, path: "/api/admin/background"})
+}
+
+// PauseBackground pauses background components
+func (c *Client) PauseBackground(ctx context.Context, req BackgroundRequest) ([]*BackgroundStatus, error) {
+	return callList[*BackgroundStatus](ctx, c, request{method: http.MethodPost, path: "/api/admin/background/pause", body: req})
+}
+
+// ResumeBackground resumes paused background components
+func (c *Client) ResumeBackground(ctx context.Context, req BackgroundRequest) ([]*BackgroundStatus, error) {
+	return callList[*BackgroundStatus](ctx, c, request{method: http.MethodPost, path: "/api/admin/background/resume", body: req})
+}
+
+// SystemLoad reports the current write load of the server
+func (c *Client) SystemLoad(ctx context.Context) (*SystemLoad, error) {
+	return call[SystemLoad](ctx, c, request{method: http.MethodGet, path: "/api/system/load"})
+}
+
+// Healthy reports an error unless the server is alive
+func (c *Client) Healthy(ctx context.Context) error {
+	return c.do(ctx, request{method: http.MethodGet, path: "/healthz"}, nil)
+}
+
+// Ready retrieves the readiness of the server's dependencies. A server that
+// is not ready answers with an *APIError, returned along with the readiness.
+func (c *Client) Ready(ctx context.Context) (*Readiness, error) {
+	readiness := &Readiness{}
+	err := c.do(ctx, request{method: http.MethodGet, path: "/readyz"}, readiness)
+	return readiness, err
+}
+
+// download sends a request whose response body is not an envelope and reads
+// the body of a successful response
+func (c *Client) download(ctx context.Context, req request) ([]byte, string, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, "", decodeResponse(resp, nil)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
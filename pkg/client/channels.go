@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateChannel creates a sales channel
+func (c *Client) CreateChannel(ctx context.Context, req CreateChannelRequest) (*SalesChannel, error) {
+	return call[SalesChannel](ctx, c, request{method: http.MethodPost, path: "/api/channels", body: req})
+}
+
+// GetChannel retrieves a sales channel
+func (c *Client) GetChannel(ctx context.Context, id string) (*SalesChannel, error) {
+	return call[SalesChannel](ctx, c, request{method: http.MethodGet, path: "/api/channels/" + escape(id)})
+}
+
+// ListChannels lists the sales channels
+func (c *Client) ListChannels(ctx context.Context) ([]*SalesChannel, error) {
+	return callList[*SalesChannel](ctx, c, request{method: http.MethodGet, path: "/api/channels"})
+}
+
+// SetChannelAllocation allocates stock of a product to a channel
+func (c *Client) SetChannelAllocation(ctx context.Context, channelID, productID string, req ChannelAllocationRequest) (*ChannelAllocation, error) {
+	return call[ChannelAllocation](ctx, c, request{method: http.MethodPut, path: channelAllocationPath(channelID, productID), body: req})
+}
+
+// DeleteChannelAllocation removes the allocation of a product to a channel
+func (c *Client) DeleteChannelAllocation(ctx context.Context, channelID, productID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: channelAllocationPath(channelID, productID)}, nil)
+}
+
+// ListChannelAllocations lists the allocations of a channel
+func (c *Client) ListChannelAllocations(ctx context.Context, channelID string) ([]*ChannelAllocation, error) {
+	return callList[*ChannelAllocation](ctx, c, request{method: http.MethodGet, path: "/api/channels/" + escape(channelID) + "/allocations"})
+}
+
+// ListChannelAllocationEvents lists one page of the allocation changes of a
+// channel
+func (c *Client) ListChannelAllocationEvents(ctx context.Context, channelID string, opts ListOptions) ([]*ChannelAllocationEvent, error) {
+	return callList[*ChannelAllocationEvent](ctx, c, request{method: http.MethodGet,
+		path: "/api/channels/" + escape(channelID) + "/allocation-events", query: pagination(opts)})
+}
+
+// ChannelAvailability retrieves the stock of a product available to a
+// channel
+func (c *Client) ChannelAvailability(ctx context.Context, channelID, productID string) (*ChannelAvailability, error) {
+	return call[ChannelAvailability](ctx, c, request{method: http.MethodGet,
+		path: "/api/channels/" + escape(channelID) + "/availability/" + escape(productID)})
+}
+
+// CreateChannelReservation reserves stock of a product from a channel's
+// allocation
+func (c *Client) CreateChannelReservation(ctx context.Context, channelID string, req ChannelReservationRequest) (*Reservation, error) {
+	return call[Reservation](ctx, c, request{method: http.MethodPost, path: "/api/channels/" + escape(channelID) + "/reservations",
+		body: req})
+}
+
+// RebalanceChannels returns the unsold units of oversized channel buckets to
+// the shared pool
+func (c *Client) RebalanceChannels(ctx context.Context) (*ChannelRebalance, error) {
+	return call[ChannelRebalance](ctx, c, request{method: http.MethodPost, path: "/api/channels/rebalance"})
+}
+
+// channelAllocationPath is the path of the allocation of a product to a
+// channel
+func channelAllocationPath(channelID, productID string) string {
+	return "/api/channels/" + escape(channelID) + "/allocations/" + escape(productID)
+}
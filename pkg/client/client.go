@@ -0,0 +1,391 @@
+// Package client is the Go client of the inventory API, for services calling
+// it instead of hand-rolling HTTP requests. Every endpoint has a typed method
+// taking a context; responses are unwrapped from the API's success envelope
+// and failures are returned as *APIError.
+//
+// Requests rejected with 429 are retried after the server's Retry-After,
+// network errors and 5xx responses after jittered exponential backoff. A
+// request that may not have been applied once is only retried when applying
+// it twice is harmless: GET, PUT and DELETE requests, and stock movements
+// (POST requests under a /stock/ path), which the server deduplicates by
+// their Idempotency-Key. Stock movements get a generated key that is kept
+// across retries; WithIdempotencyKey supplies one instead, e.g. to deduplicate
+// across restarts of the caller.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Doer sends HTTP requests; *http.Client implements it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client calls the inventory API
+type Client struct {
+	baseURL      string
+	http         Doer
+	apiKey       string
+	bearerToken  string
+	tenant       string
+	tenantHeader string
+	userAgent    string
+	maxRetries   int
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with the given client instead of one with a
+// 30 second timeout
+func WithHTTPClient(doer Doer) Option {
+	return func(c *Client) {
+		c.http = doer
+	}
+}
+
+// WithAPIKey authenticates requests with an API key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates requests with a JWT
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.bearerToken = token
+	}
+}
+
+// WithTenant scopes requests to a tenant, named in the X-Tenant-ID header
+// unless the server is configured with another one
+func WithTenant(tenant string) Option {
+	return func(c *Client) {
+		c.tenant = tenant
+	}
+}
+
+// WithTenantHeader changes the header naming the tenant
+func WithTenantHeader(header string) Option {
+	return func(c *Client) {
+		c.tenantHeader = header
+	}
+}
+
+// WithUserAgent identifies the calling service in the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetries sets the number of retries after the first attempt and the
+// backoff before the first retry, doubling with every further retry up to
+// maxDelay, which also caps the server's Retry-After. Zero retries disables
+// retrying.
+func WithRetries(maxRetries int, baseDelay, maxDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries, c.baseDelay, c.maxDelay = maxRetries, baseDelay, maxDelay
+	}
+}
+
+// New creates a new Client of the API at baseURL, e.g.
+// "http://inventory:8080"
+func New(baseURL string, opts ...Option) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		http:         &http.Client{Timeout: 30 * time.Second},
+		tenantHeader: "X-Tenant-ID",
+		userAgent:    "inventory-go-client",
+		maxRetries:   3,
+		baseDelay:    200 * time.Millisecond,
+		maxDelay:     10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxRetries < 0 || c.baseDelay < 0 || c.maxDelay < 0 {
+		return nil, errors.New("retries and delays cannot be negative")
+	}
+	return c, nil
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context whose stock movement is sent with the
+// given Idempotency-Key instead of a generated one
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// envelope is the body of every API response: data on success, the error
+// fields otherwise
+type envelope struct {
+	Data json.RawMessage `json:"data"`
+	apiErrorBody
+}
+
+// request is one API call
+type request struct {
+	method string
+	// path is the escaped URL path
+	path  string
+	query url.Values
+	body  any
+	// contentType overrides the JSON content type of the body
+	contentType string
+	// raw is sent as the body as is, instead of body encoded as JSON
+	raw []byte
+	// header holds additional request headers
+	header http.Header
+}
+
+// do sends the request and decodes the data of its response into out, which
+// may be nil
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	_, err := c.doStatus(ctx, req, out)
+	return err
+}
+
+// call sends the request and returns the data of its response
+func call[T any](ctx context.Context, c *Client, req request) (*T, error) {
+	var out T
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// callList sends the request and returns the list in its response
+func callList[T any](ctx context.Context, c *Client, req request) ([]T, error) {
+	var out []T
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// doStatus is do for callers that tell successes apart by their status
+func (c *Client) doStatus(ctx context.Context, req request, out any) (int, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, decodeResponse(resp, out)
+}
+
+// send sends the request, retrying as described in the package
+// documentation, and returns the final response to be closed by the caller.
+// Responses of any status are returned; only sending failures are errors.
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	body := req.raw
+	if body == nil && req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	var idempotencyKey string
+	if req.method == http.MethodPost && strings.Contains(req.path, "/stock/") {
+		idempotencyKey, _ = ctx.Value(idempotencyKeyContextKey{}).(string)
+		if idempotencyKey == "" {
+			idempotencyKey = uuid.NewString()
+		}
+	}
+
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader = http.NoBody
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		httpReq, err := http.NewRequestWithContext(ctx, req.method, target, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		c.setHeaders(httpReq, req, body != nil, idempotencyKey)
+
+		resp, err := c.http.Do(httpReq)
+		if !c.retryable(req.method, idempotencyKey != "", resp, err) || attempt >= c.maxRetries || ctx.Err() != nil {
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", req.method, req.path, err)
+			}
+			return resp, nil
+		}
+
+		delay := c.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// setHeaders sets the credentials, tenant and content headers of an attempt
+func (c *Client) setHeaders(httpReq *http.Request, req request, hasBody bool, idempotencyKey string) {
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if hasBody {
+		contentType := req.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if c.apiKey != "" {
+		httpReq.Header.Set("X-API-Key", c.apiKey)
+	} else if c.bearerToken != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	if c.tenant != "" {
+		httpReq.Header.Set(c.tenantHeader, c.tenant)
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+}
+
+// retryable reports whether an attempt is worth retrying: 429 always, as
+// the request was not applied; network errors and 5xx responses except 501
+// only when applying the request twice is harmless
+func (c *Client) retryable(method string, idempotent bool, resp *http.Response, err error) bool {
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if err == nil && (resp.StatusCode < 500 || resp.StatusCode == http.StatusNotImplemented) {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return idempotent
+}
+
+// backoff returns the delay before the retry following attempt: the
+// response's Retry-After when given in seconds, otherwise jittered
+// exponential backoff; both capped at maxDelay
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.maxDelay)
+		}
+	}
+
+	delay := c.baseDelay << attempt
+	if delay <= 0 || delay > c.maxDelay {
+		delay = c.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// decodeResponse decodes the data of a response into out, or returns its
+// error. Movements queued behind a stocktake are answered 202 with an error
+// body and returned as *APIError too.
+func decodeResponse(resp *http.Response, out any) error {
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil && err != io.EOF {
+		if resp.StatusCode >= 300 {
+			return newAPIError(resp, apiErrorBody{Message: http.StatusText(resp.StatusCode)})
+		}
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if out != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if resp.StatusCode >= 300 || env.Error != "" {
+		return newAPIError(resp, env.apiErrorBody)
+	}
+	return nil
+}
+
+// escape escapes a path segment such as an ID
+func escape(segment string) string {
+	return url.PathEscape(segment)
+}
+
+// pagination returns the limit and offset query parameters; zero values
+// leave the server's defaults
+func pagination(opts ListOptions) url.Values {
+	query := url.Values{}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	return query
+}
+
+// setString sets a query parameter unless value is empty
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+// setBool sets a boolean query parameter if value is true
+func setBool(query url.Values, name string, value bool) {
+	if value {
+		query.Set(name, "true")
+	}
+}
+
+// setTime sets a time query parameter in RFC3339 unless t is zero
+func setTime(query url.Values, name string, t time.Time) {
+	if !t.IsZero() {
+		query.Set(name, t.Format(time.RFC3339Nano))
+	}
+}
+
+// setDate sets a date query parameter (YYYY-MM-DD) unless t is zero
+func setDate(query url.Values, name string, t time.Time) {
+	if !t.IsZero() {
+		query.Set(name, t.Format(time.DateOnly))
+	}
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient creates a client of a test server that retries quickly
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithRetries(2, time.Millisecond, 5*time.Millisecond)}, opts...)
+	client, err := New(server.URL+"/", opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "/api"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("New(%q) expected an error", baseURL)
+		}
+	}
+	if _, err := New("http://localhost", WithRetries(-1, 0, 0)); err == nil {
+		t.Error("Expected negative retries to be rejected")
+	}
+}
+
+func TestClientDecodesEnvelope(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/products/a%2Fb" || r.URL.Query().Get("include") != "variants" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		if r.Header.Get("X-API-Key") != "secret" || r.Header.Get("X-Store") != "acme" {
+			t.Errorf("Expected the API key and tenant headers, got %v", r.Header)
+		}
+		fmt.Fprint(w, `{"data":{"product":{"id":"a/b","sku":"SKU-1"},"inventory":{"quantity":7}},"message":"ok"}`)
+	}, WithAPIKey("secret"), WithTenant("acme"), WithTenantHeader("X-Store"))
+
+	details, err := client.GetProduct(context.Background(), "a/b", "variants")
+	if err != nil {
+		t.Fatalf("GetProduct() error = %v", err)
+	}
+	if details.Product.SKU != "SKU-1" || details.Inventory.Quantity != 7 {
+		t.Errorf("Unexpected product %+v", details)
+	}
+}
+
+func TestClientAPIError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-1")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error":"INSUFFICIENT_STOCK","message":"Not enough stock","details":"available 2",`+
+			`"stock":{"inventory_id":"i1","quantity":2,"reserved":0}}`)
+	})
+
+	err := client.RemoveStock(context.Background(), "p1", StockOperationRequest{Quantity: 5})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusConflict || apiErr.Code != "INSUFFICIENT_STOCK" || apiErr.RequestID != "req-1" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+	if apiErr.Stock == nil || apiErr.Stock.Quantity != 2 {
+		t.Errorf("Expected the stock level of the error, got %+v", apiErr.Stock)
+	}
+	if apiErr.Queued() {
+		t.Error("Expected a conflict not to be queued")
+	}
+}
+
+func TestClientQueuedMovement(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"error":"LOCATION_FROZEN","message":"Queued until the stocktake closes"}`)
+	})
+
+	err := client.AddStock(context.Background(), "p1", StockOperationRequest{Quantity: 1})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Queued() {
+		t.Errorf("Expected a queued movement, got %v", err)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		status    int
+		call      func(*Client) error
+		wantCalls int32
+	}{
+		{"GET on 503", http.StatusServiceUnavailable, func(c *Client) error {
+			_, err := c.GetOrder(context.Background(), "o1")
+			return err
+		}, 3},
+		{"POST on 429", http.StatusTooManyRequests, func(c *Client) error {
+			_, err := c.CreateOrder(context.Background(), CreateOrderRequest{})
+			return err
+		}, 3},
+		{"stock POST on 500", http.StatusInternalServerError, func(c *Client) error {
+			return c.AddStock(context.Background(), "p1", StockOperationRequest{Quantity: 1})
+		}, 3},
+		{"POST on 500", http.StatusInternalServerError, func(c *Client) error {
+			_, err := c.CreateOrder(context.Background(), CreateOrderRequest{})
+			return err
+		}, 1},
+		{"GET on 501", http.StatusNotImplemented, func(c *Client) error {
+			_, err := c.GetOrder(context.Background(), "o1")
+			return err
+		}, 1},
+		{"GET on 404", http.StatusNotFound, func(c *Client) error {
+			_, err := c.GetOrder(context.Background(), "o1")
+			return err
+		}, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":"FAILED","message":"failed"}`)
+			})
+
+			var apiErr *APIError
+			if err := tt.call(client); !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("Expected a %d error, got %v", tt.status, err)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.wantCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestClientRetrySucceeds(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"quantity":3`) {
+			t.Errorf("Expected the body to be replayed, got %q", body)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"product_id":"p1","quantity":3}}`)
+	})
+
+	if err := client.ReserveStock(context.Background(), "p1", StockOperationRequest{Quantity: 3}); err != nil {
+		t.Fatalf("ReserveStock() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClientIdempotencyKey(t *testing.T) {
+	var keys []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data":null}`)
+	}, WithRetries(1, 0, 0))
+
+	if err := client.AddStock(context.Background(), "p1", StockOperationRequest{Quantity: 1}); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected one generated key across retries, got %q", keys)
+	}
+
+	ctx := WithIdempotencyKey(context.Background(), "restock-42")
+	if err := client.AddStock(ctx, "p1", StockOperationRequest{Quantity: 1}); err != nil {
+		t.Fatalf("AddStock() error = %v", err)
+	}
+	if keys[2] != "restock-42" || keys[3] != "restock-42" {
+		t.Errorf("Expected the caller's key, got %q", keys[2:])
+	}
+
+	keys = nil
+	client.GetOrder(context.Background(), "o1")
+	if keys[0] != "" {
+		t.Errorf("Expected no key outside stock movements, got %q", keys[0])
+	}
+}
+
+func TestClientRetryStopsWithContext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, WithRetries(3, time.Millisecond, time.Minute))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetOrder(ctx, "o1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to stop retrying, got %v", err)
+	}
+}
+
+func TestClientExportLedger(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("from") != "2026-01-01T00:00:00Z" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Trailer", "X-Audit-Export-ID, X-Audit-SHA256, X-Audit-Row-Count, X-Audit-Signature")
+		fmt.Fprint(w, "id,type\nt1,ADD\n")
+		w.Header().Set("X-Audit-Export-ID", "export-1")
+		w.Header().Set("X-Audit-SHA256", "abc")
+		w.Header().Set("X-Audit-Row-Count", "1")
+		w.Header().Set("X-Audit-Signature", "sig")
+	})
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	export, err := client.ExportLedger(context.Background(), from, from.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatalf("ExportLedger() error = %v", err)
+	}
+	if string(export.CSV) != "id,type\nt1,ADD\n" || export.ID != "export-1" || export.SHA256 != "abc" || export.RowCount != 1 || export.Signature != "sig" {
+		t.Errorf("Unexpected export %+v", export)
+	}
+}
+
+func TestClientStreamInventory(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("product_ids") != "p1,p2" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: snapshot\ndata: {\"product_id\":\"p1\",\"quantity\":5}\n\n"+
+			": heartbeat\n\n"+
+			"event: change\ndata: {\"product_id\":\"p1\",\"quantity\":4}\n\n")
+	})
+
+	var got []string
+	err := client.StreamInventory(context.Background(), []string{"p1", "p2"}, func(name string, event *InventoryEvent) error {
+		got = append(got, fmt.Sprintf("%s:%s:%d", name, event.ProductID, event.Quantity))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamInventory() error = %v", err)
+	}
+	if strings.Join(got, " ") != "snapshot:p1:5 change:p1:4" {
+		t.Errorf("Unexpected events %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// apiErrorBody is the error response of the API
+type apiErrorBody struct {
+	Error            string            `json:"error"`
+	Message          string            `json:"message"`
+	Details          string            `json:"details"`
+	RequestID        string            `json:"request_id"`
+	DocumentationURL string            `json:"documentation_url"`
+	Stock            *StockUpdateError `json:"stock"`
+	SKU              string            `json:"sku"`
+}
+
+// APIError is an error answered by the API
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the error code, e.g. NOT_FOUND or INSUFFICIENT_STOCK
+	Code    string
+	Message string
+	Details string
+	// RequestID identifies the request in server logs
+	RequestID        string
+	DocumentationURL string
+	// Stock is the stock level that rejected an INSUFFICIENT_STOCK movement,
+	// when known
+	Stock *StockUpdateError
+	// SKU is the SKU a DUPLICATE_SKU product conflicts on
+	SKU string
+	// RetryAfter is the delay the server asked for before trying again
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("inventory API: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	if e.Details != "" {
+		message += " (" + e.Details + ")"
+	}
+	return message
+}
+
+// Queued reports whether the error is a stock movement accepted for later,
+// applied once the stocktake freezing its location closes
+func (e *APIError) Queued() bool {
+	return e.StatusCode == http.StatusAccepted
+}
+
+// newAPIError creates the error of a response
+func newAPIError(resp *http.Response, body apiErrorBody) *APIError {
+	err := &APIError{
+		StatusCode:       resp.StatusCode,
+		Code:             body.Error,
+		Message:          body.Message,
+		Details:          body.Details,
+		RequestID:        body.RequestID,
+		DocumentationURL: body.DocumentationURL,
+		Stock:            body.Stock,
+		SKU:              body.SKU,
+	}
+	if err.Message == "" {
+		err.Message = http.StatusText(resp.StatusCode)
+	}
+	if err.RequestID == "" {
+		err.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds >= 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
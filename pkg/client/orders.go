@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// CreateReservation reserves stock of a product until it is confirmed,
+// released or expires
+func (c *Client) CreateReservation(ctx context.Context, req CreateReservationRequest) (*Reservation, error) {
+	return call[Reservation](ctx, c, request{method: http.MethodPost, path: "/api/reservations", body: req})
+}
+
+// CreateSplitReservation reserves stock of a product like CreateReservation,
+// splitting the reservation into child reservations at other locations when
+// the location cannot cover the quantity on its own
+func (c *Client) CreateSplitReservation(ctx context.Context, req CreateReservationRequest) (*ReservationSplit, error) {
+	body := struct {
+		CreateReservationRequest
+		AllowSplit bool `json:"allow_split"`
+	}{req, true}
+	return call[ReservationSplit](ctx, c, request{method: http.MethodPost, path: "/api/reservations", body: body})
+}
+
+// GetReservationSplit retrieves the child reservations of a split reservation
+func (c *Client) GetReservationSplit(ctx context.Context, id string) (*ReservationSplit, error) {
+	return call[ReservationSplit](ctx, c, request{method: http.MethodGet, path: "/api/reservations/splits/" + escape(id)})
+}
+
+// GetReservation retrieves a reservation
+func (c *Client) GetReservation(ctx context.Context, id string) (*Reservation, error) {
+	return call[Reservation](ctx, c, request{method: http.MethodGet, path: "/api/reservations/" + escape(id)})
+}
+
+// ConfirmReservation confirms a reservation, removing its stock
+func (c *Client) ConfirmReservation(ctx context.Context, id string) (*Reservation, error) {
+	return call[Reservation](ctx, c, request{method: http.MethodPost, path: "/api/reservations/" + escape(id) + "/confirm"})
+}
+
+// ReleaseReservation releases a reservation, returning its stock
+func (c *Client) ReleaseReservation(ctx context.Context, id string) (*Reservation, error) {
+	return call[Reservation](ctx, c, request{method: http.MethodPost, path: "/api/reservations/" + escape(id) + "/release"})
+}
+
+// ListReservations lists one page of the reservations of a product
+func (c *Client) ListReservations(ctx context.Context, productID string, opts ListOptions) ([]*Reservation, error) {
+	return callList[*Reservation](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(productID) + "/reservations",
+		query: pagination(opts)})
+}
+
+// ReserveMany reserves the lines of an order at once under a reservation
+// policy
+func (c *Client) ReserveMany(ctx context.Context, req ReserveManyRequest) (*OrderReservation, error) {
+	return call[OrderReservation](ctx, c, request{method: http.MethodPost, path: "/api/reservations/batch", body: req})
+}
+
+// ListBackorders lists one page of the backorders, of one product and in one
+// status unless empty
+func (c *Client) ListBackorders(ctx context.Context, productID string, status BackorderStatus, opts ListOptions) ([]*Backorder, error) {
+	query := pagination(opts)
+	setString(query, "product_id", productID)
+	setString(query, "status", string(status))
+	return callList[*Backorder](ctx, c, request{method: http.MethodGet, path: "/api/backorders", query: query})
+}
+
+// CancelBackorder cancels a backorder
+func (c *Client) CancelBackorder(ctx context.Context, id string) (*Backorder, error) {
+	return call[Backorder](ctx, c, request{method: http.MethodPost, path: "/api/backorders/" + escape(id) + "/cancel"})
+}
+
+// CreateOrder reserves the lines of an order
+func (c *Client) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Order, error) {
+	return call[Order](ctx, c, request{method: http.MethodPost, path: "/api/orders", body: req})
+}
+
+// GetOrder retrieves an order
+func (c *Client) GetOrder(ctx context.Context, id string) (*Order, error) {
+	return call[Order](ctx, c, request{method: http.MethodGet, path: "/api/orders/" + escape(id)})
+}
+
+// ConfirmOrder confirms the reservations of an order
+func (c *Client) ConfirmOrder(ctx context.Context, id string) (*Order, error) {
+	return call[Order](ctx, c, request{method: http.MethodPost, path: "/api/orders/" + escape(id) + "/confirm"})
+}
+
+// CancelOrder cancels an order, releasing its reservations
+func (c *Client) CancelOrder(ctx context.Context, id string) (*Order, error) {
+	return call[Order](ctx, c, request{method: http.MethodPost, path: "/api/orders/" + escape(id) + "/cancel"})
+}
+
+// ListOrders lists one page of the orders, in one status unless empty
+func (c *Client) ListOrders(ctx context.Context, status OrderStatus, opts ListOptions) ([]*Order, error) {
+	query := pagination(opts)
+	setString(query, "status", strings.ToUpper(string(status)))
+	return callList[*Order](ctx, c, request{method: http.MethodGet, path: "/api/orders", query: query})
+}
+
+// CreateCartHold holds the lines of a cart for a short time
+func (c *Client) CreateCartHold(ctx context.Context, req CreateCartHoldRequest) (*CartHold, error) {
+	return call[CartHold](ctx, c, request{method: http.MethodPost, path: "/api/cart-holds", body: req})
+}
+
+// GetCartHold retrieves a cart hold
+func (c *Client) GetCartHold(ctx context.Context, id string) (*CartHold, error) {
+	return call[CartHold](ctx, c, request{method: http.MethodGet, path: "/api/cart-holds/" + escape(id)})
+}
+
+// ConvertCartHold converts a cart hold into reservations at checkout
+func (c *Client) ConvertCartHold(ctx context.Context, id string) (*CartHold, error) {
+	return call[CartHold](ctx, c, request{method: http.MethodPost, path: "/api/cart-holds/" + escape(id) + "/convert"})
+}
+
+// ReleaseCartHold releases a cart hold
+func (c *Client) ReleaseCartHold(ctx context.Context, id string) (*CartHold, error) {
+	return call[CartHold](ctx, c, request{method: http.MethodPost, path: "/api/cart-holds/" + escape(id) + "/release"})
+}
+
+// RecordSale records a point-of-sale sale. Sales are deduplicated by their
+// event ID: replayed reports whether the sale had been recorded before.
+func (c *Client) RecordSale(ctx context.Context, sale Sale) (recorded *Sale, replayed bool, err error) {
+	var result Sale
+	status, err := c.doStatus(ctx, request{method: http.MethodPost, path: "/api/pos/sales", body: sale}, &result)
+	if err != nil {
+		return nil, false, err
+	}
+	return &result, status == http.StatusOK, nil
+}
+
+// ListSales lists one page of the point-of-sale sales
+func (c *Client) ListSales(ctx context.Context, filter SaleFilter) ([]*Sale, error) {
+	query := pagination(filter.ListOptions)
+	setString(query, "location", filter.Location)
+	setTime(query, "from", filter.From)
+	setTime(query, "to", filter.To)
+	return callList[*Sale](ctx, c, request{method: http.MethodGet, path: "/api/pos/sales", query: query})
+}
+
+// CreateIntercompanyTransfer moves stock to another tenant
+func (c *Client) CreateIntercompanyTransfer(ctx context.Context, req IntercompanyTransferRequest) (*IntercompanyTransfer, error) {
+	return call[IntercompanyTransfer](ctx, c, request{method: http.MethodPost, path: "/api/intercompany-transfers", body: req})
+}
+
+// GetIntercompanyTransfer retrieves an intercompany transfer
+func (c *Client) GetIntercompanyTransfer(ctx context.Context, id string) (*IntercompanyTransfer, error) {
+	return call[IntercompanyTransfer](ctx, c, request{method: http.MethodGet, path: "/api/intercompany-transfers/" + escape(id)})
+}
+
+// ListIntercompanyTransfers lists one page of the intercompany transfers
+func (c *Client) ListIntercompanyTransfers(ctx context.Context, opts ListOptions) ([]*IntercompanyTransfer, error) {
+	return callList[*IntercompanyTransfer](ctx, c, request{method: http.MethodGet, path: "/api/intercompany-transfers",
+		query: pagination(opts)})
+}
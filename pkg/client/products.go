@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CreateProduct creates a product
+func (c *Client) CreateProduct(ctx context.Context, req CreateProductRequest) (*Product, error) {
+	return call[Product](ctx, c, request{method: http.MethodPost, path: "/api/products", body: req})
+}
+
+// GetProduct retrieves a product with its inventory; include names related
+// data to embed, e.g. "variants"
+func (c *Client) GetProduct(ctx context.Context, id string, include ...string) (*ProductDetails, error) {
+	return call[ProductDetails](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(id),
+		query: includeQuery(include)})
+}
+
+// GetProductBySKU retrieves a product with its inventory by its SKU
+func (c *Client) GetProductBySKU(ctx context.Context, sku string, include ...string) (*ProductDetails, error) {
+	return call[ProductDetails](ctx, c, request{method: http.MethodGet, path: "/api/products/sku/" + escape(sku),
+		query: includeQuery(include)})
+}
+
+// ListProducts lists one page of the products
+func (c *Client) ListProducts(ctx context.Context, opts ProductListOptions) (*Page[*Product], error) {
+	query := pagination(opts.ListOptions)
+	setBool(query, "include_archived", opts.IncludeArchived)
+	setString(query, "include", strings.Join(opts.Include, ","))
+	setString(query, "filter", opts.Filter)
+	return call[Page[*Product]](ctx, c, request{method: http.MethodGet, path: "/api/products", query: query})
+}
+
+// UpdateProduct replaces the name, description, category and price of a
+// product, clearing those not given
+func (c *Client) UpdateProduct(ctx context.Context, id string, req UpdateProductRequest) (*Product, error) {
+	return call[Product](ctx, c, request{method: http.MethodPut, path: "/api/products/" + escape(id), body: req})
+}
+
+// PatchProduct changes the given fields of a product
+func (c *Client) PatchProduct(ctx context.Context, id string, req UpdateProductRequest) (*Product, error) {
+	return call[Product](ctx, c, request{method: http.MethodPatch, path: "/api/products/" + escape(id), body: req,
+		contentType: "application/merge-patch+json"})
+}
+
+// DeleteProduct archives a product
+func (c *Client) DeleteProduct(ctx context.Context, id string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/products/" + escape(id)}, nil)
+}
+
+// RestoreProduct restores an archived product
+func (c *Client) RestoreProduct(ctx context.Context, id string) (*Product, error) {
+	return call[Product](ctx, c, request{method: http.MethodPost, path: "/api/products/" + escape(id) + "/restore"})
+}
+
+// SetProductTranslation creates or replaces the translation of a product
+// into a locale
+func (c *Client) SetProductTranslation(ctx context.Context, id, locale string, translation ProductTranslation) (*ProductTranslationResult, error) {
+	return call[ProductTranslationResult](ctx, c, request{method: http.MethodPut,
+		path: "/api/products/" + escape(id) + "/translations/" + escape(locale), body: translation})
+}
+
+// DeleteProductTranslation removes the translation of a product into a locale
+func (c *Client) DeleteProductTranslation(ctx context.Context, id, locale string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: "/api/products/" + escape(id) + "/translations/" + escape(locale)}, nil)
+}
+
+// CreateVariant creates a variant of a product
+func (c *Client) CreateVariant(ctx context.Context, productID string, req CreateVariantRequest) (*ProductVariant, error) {
+	return call[ProductVariant](ctx, c, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/variants",
+		body: req})
+}
+
+// ListVariants lists the variants of a product with their stock rolled up
+func (c *Client) ListVariants(ctx context.Context, productID string) (*VariantRollup, error) {
+	return call[VariantRollup](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(productID) + "/variants"})
+}
+
+// ImportProducts creates or updates products in bulk
+func (c *Client) ImportProducts(ctx context.Context, rows []ProductImportRow) (*ProductImportReport, error) {
+	return call[ProductImportReport](ctx, c, request{method: http.MethodPost, path: "/api/products/import", body: rows})
+}
+
+// StartProductImport imports products as a background job, whose summary is
+// the import report
+func (c *Client) StartProductImport(ctx context.Context, rows []ProductImportRow) (*Job, error) {
+	return c.startJob(ctx, request{method: http.MethodPost, path: "/api/products/import", body: rows})
+}
+
+// PreviewProductArchive lists the products a filter would archive
+func (c *Client) PreviewProductArchive(ctx context.Context, filter ProductArchiveFilter) (*ProductArchivePreview, error) {
+	body := productArchiveRequest{ProductArchiveFilter: filter}
+	return call[ProductArchivePreview](ctx, c, request{method: http.MethodPost, path: "/api/products/archive", body: body})
+}
+
+// ArchiveProducts archives the products matching a filter; expectedCount
+// must be the number of products previewed, so that nothing is archived if
+// more match by now
+func (c *Client) ArchiveProducts(ctx context.Context, filter ProductArchiveFilter, expectedCount int) (*ProductArchiveResult, error) {
+	body := productArchiveRequest{ProductArchiveFilter: filter, Apply: true, ExpectedCount: &expectedCount}
+	return call[ProductArchiveResult](ctx, c, request{method: http.MethodPost, path: "/api/products/archive", body: body})
+}
+
+// StartProductArchive archives the products matching a filter as a
+// background job
+func (c *Client) StartProductArchive(ctx context.Context, filter ProductArchiveFilter, expectedCount int) (*Job, error) {
+	body := productArchiveRequest{ProductArchiveFilter: filter, Apply: true, ExpectedCount: &expectedCount}
+	return c.startJob(ctx, request{method: http.MethodPost, path: "/api/products/archive", body: body})
+}
+
+// ExportProducts streams the products as NDJSON or CSV; the caller closes the
+// returned body
+func (c *Client) ExportProducts(ctx context.Context, opts ProductExportOptions) (io.ReadCloser, error) {
+	query := url.Values{}
+	setString(query, "format", opts.Format)
+	setString(query, "category", opts.Category)
+	setBool(query, "include_archived", opts.IncludeArchived)
+	setTime(query, "updated_since", opts.UpdatedSince)
+	return c.stream(ctx, request{method: http.MethodGet, path: "/api/products/export", query: query})
+}
+
+// ListDrops lists the products scheduled to be released
+func (c *Client) ListDrops(ctx context.Context) ([]*Product, error) {
+	return callList[*Product](ctx, c, request{method: http.MethodGet, path: "/api/drops"})
+}
+
+// ScheduleDrop schedules the release of a product; it cannot be sold before
+func (c *Client) ScheduleDrop(ctx context.Context, productID string, releaseAt time.Time) (*Product, error) {
+	body := map[string]time.Time{"release_at": releaseAt}
+	return call[Product](ctx, c, request{method: http.MethodPut, path: "/api/products/" + escape(productID) + "/drop", body: body})
+}
+
+// CancelDrop cancels the scheduled release of a product
+func (c *Client) CancelDrop(ctx context.Context, productID string) (*Product, error) {
+	return call[Product](ctx, c, request{method: http.MethodDelete, path: "/api/products/" + escape(productID) + "/drop"})
+}
+
+// includeQuery returns the include query parameter of related data
+func includeQuery(include []string) url.Values {
+	query := url.Values{}
+	setString(query, "include", strings.Join(include, ","))
+	return query
+}
+
+// startJob sends a request to run as a background job and returns the job
+// to poll with GetJob
+func (c *Client) startJob(ctx context.Context, req request) (*Job, error) {
+	req.header = http.Header{"Prefer": {"respond-async"}}
+
+	job, err := call[Job](ctx, c, req)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == "" {
+		return nil, fmt.Errorf("%s %s: the server did not start a job", req.method, req.path)
+	}
+	return job, nil
+}
+
+// stream sends a request whose response body is not an envelope and returns
+// the body of a successful response
+func (c *Client) stream(ctx context.Context, req request) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, decodeResponse(resp, nil)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ReorderSuggestions lists the products to reorder and how much of them
+func (c *Client) ReorderSuggestions(ctx context.Context, opts ReplenishmentOptions) ([]*ReorderSuggestion, error) {
+	query := url.Values{}
+	if opts.LeadTimeDays != nil {
+		query.Set("lead_time_days", strconv.Itoa(*opts.LeadTimeDays))
+	}
+	if opts.CoverDays != nil {
+		query.Set("cover_days", strconv.Itoa(*opts.CoverDays))
+	}
+	return callList[*ReorderSuggestion](ctx, c, request{method: http.MethodGet, path: "/api/replenishment/suggestions", query: query})
+}
+
+// CreatePurchaseOrder creates a draft purchase order
+func (c *Client) CreatePurchaseOrder(ctx context.Context, req CreatePurchaseOrderRequest) (*PurchaseOrder, error) {
+	return call[PurchaseOrder](ctx, c, request{method: http.MethodPost, path: "/api/purchase-orders", body: req})
+}
+
+// GetPurchaseOrder retrieves a purchase order
+func (c *Client) GetPurchaseOrder(ctx context.Context, id string) (*PurchaseOrder, error) {
+	return call[PurchaseOrder](ctx, c, request{method: http.MethodGet, path: "/api/purchase-orders/" + escape(id)})
+}
+
+// ListPurchaseOrders lists one page of the purchase orders, in one status
+// unless empty
+func (c *Client) ListPurchaseOrders(ctx context.Context, status PurchaseOrderStatus, opts ListOptions) ([]*PurchaseOrder, error) {
+	query := pagination(opts)
+	setString(query, "status", strings.ToUpper(string(status)))
+	return callList[*PurchaseOrder](ctx, c, request{method: http.MethodGet, path: "/api/purchase-orders", query: query})
+}
+
+// ReceivePurchaseOrder receives the goods of a purchase order into stock
+func (c *Client) ReceivePurchaseOrder(ctx context.Context, id string) (*PurchaseOrder, error) {
+	return call[PurchaseOrder](ctx, c, request{method: http.MethodPost, path: "/api/purchase-orders/" + escape(id) + "/receive"})
+}
+
+// CancelPurchaseOrder cancels a purchase order
+func (c *Client) CancelPurchaseOrder(ctx context.Context, id string) (*PurchaseOrder, error) {
+	return call[PurchaseOrder](ctx, c, request{method: http.MethodPost, path: "/api/purchase-orders/" + escape(id) + "/cancel"})
+}
+
+// ListShippingNotices lists the shipping notices of a purchase order
+func (c *Client) ListShippingNotices(ctx context.Context, purchaseOrderID string) ([]*AdvanceShippingNotice, error) {
+	return callList[*AdvanceShippingNotice](ctx, c, request{method: http.MethodGet,
+		path: "/api/purchase-orders/" + escape(purchaseOrderID) + "/asns"})
+}
+
+// ListStockAlerts lists one page of the stock alerts, in one status unless
+// empty
+func (c *Client) ListStockAlerts(ctx context.Context, status StockAlertStatus, opts ListOptions) ([]*StockAlert, error) {
+	query := pagination(opts)
+	setString(query, "status", strings.ToUpper(string(status)))
+	return callList[*StockAlert](ctx, c, request{method: http.MethodGet, path: "/api/stock-alerts", query: query})
+}
+
+// EvaluateStockAlerts raises and resolves the stock alerts now
+func (c *Client) EvaluateStockAlerts(ctx context.Context) (*StockAlertRun, error) {
+	return call[StockAlertRun](ctx, c, request{method: http.MethodPost, path: "/api/stock-alerts/evaluate"})
+}
+
+// ListSupplierOrders lists one page of the open purchase orders of the
+// supplier authenticated
+func (c *Client) ListSupplierOrders(ctx context.Context, opts ListOptions) ([]*SupplierPurchaseOrder, error) {
+	return callList[*SupplierPurchaseOrder](ctx, c, request{method: http.MethodGet, path: "/api/supplier/purchase-orders",
+		query: pagination(opts)})
+}
+
+// GetSupplierOrder retrieves a purchase order of the supplier authenticated
+func (c *Client) GetSupplierOrder(ctx context.Context, id string) (*SupplierPurchaseOrder, error) {
+	return call[SupplierPurchaseOrder](ctx, c, request{method: http.MethodGet, path: "/api/supplier/purchase-orders/" + escape(id)})
+}
+
+// ConfirmSupplierOrder confirms a purchase order as its supplier
+func (c *Client) ConfirmSupplierOrder(ctx context.Context, id string, req ConfirmPurchaseOrderRequest) (*SupplierPurchaseOrder, error) {
+	return call[SupplierPurchaseOrder](ctx, c, request{method: http.MethodPost,
+		path: "/api/supplier/purchase-orders/" + escape(id) + "/confirm", body: req})
+}
+
+// SubmitShippingNotice announces a shipment of a purchase order as its
+// supplier
+func (c *Client) SubmitShippingNotice(ctx context.Context, purchaseOrderID string, req ShippingNoticeRequest) (*AdvanceShippingNotice, error) {
+	return call[AdvanceShippingNotice](ctx, c, request{method: http.MethodPost,
+		path: "/api/supplier/purchase-orders/" + escape(purchaseOrderID) + "/asns", body: req})
+}
+
+// ListSupplierShippingNotices lists the shipping notices of a purchase order
+// of the supplier authenticated
+func (c *Client) ListSupplierShippingNotices(ctx context.Context, purchaseOrderID string) ([]*AdvanceShippingNotice, error) {
+	return callList[*AdvanceShippingNotice](ctx, c, request{method: http.MethodGet,
+		path: "/api/supplier/purchase-orders/" + escape(purchaseOrderID) + "/asns"})
+}
+
+// ReceiveConsignment receives a lot of consignment stock, owned by its
+// supplier until sold
+func (c *Client) ReceiveConsignment(ctx context.Context, req ConsignmentRequest) (*Consignment, error) {
+	return call[Consignment](ctx, c, request{method: http.MethodPost, path: "/api/consignments", body: req})
+}
+
+// GetConsignment retrieves a consignment lot
+func (c *Client) GetConsignment(ctx context.Context, id string) (*Consignment, error) {
+	return call[Consignment](ctx, c, request{method: http.MethodGet, path: "/api/consignments/" + escape(id)})
+}
+
+// ListConsignments lists one page of the consignment lots, newest first
+func (c *Client) ListConsignments(ctx context.Context, filter ConsignmentFilter) ([]*Consignment, error) {
+	query := pagination(filter.ListOptions)
+	setString(query, "supplier", filter.Supplier)
+	setString(query, "product_id", filter.ProductID)
+	setString(query, "location", filter.Location)
+	setBool(query, "open", filter.Open)
+	return callList[*Consignment](ctx, c, request{method: http.MethodGet, path: "/api/consignments", query: query})
+}
+
+// ListOwnershipTransfers lists one page of the ownership transfers of a
+// consignment lot
+func (c *Client) ListOwnershipTransfers(ctx context.Context, consignmentID string, opts ListOptions) ([]*OwnershipTransfer, error) {
+	return callList[*OwnershipTransfer](ctx, c, request{method: http.MethodGet,
+		path: "/api/consignments/" + escape(consignmentID) + "/transfers", query: pagination(opts)})
+}
+
+// ConsignmentSettlement reports what is owed to each supplier, or one
+// supplier unless empty, for the consignment stock sold
+func (c *Client) ConsignmentSettlement(ctx context.Context, q SettlementQuery) (*ConsignmentSettlement, error) {
+	query := url.Values{}
+	setString(query, "supplier", q.Supplier)
+	setDate(query, "from", q.From)
+	setDate(query, "to", q.To)
+	return call[ConsignmentSettlement](ctx, c, request{method: http.MethodGet, path: "/api/reports/consignment-settlement", query: query})
+}
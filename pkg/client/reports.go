@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DashboardStats returns the headline figures of the dashboard: product and
+// unit totals, out-of-stock and low-stock counts, and the transaction volume
+// of the last 24 hours and 7 days
+func (c *Client) DashboardStats(ctx context.Context) (*DashboardStats, error) {
+	return call[DashboardStats](ctx, c, request{method: http.MethodGet, path: "/api/stats"})
+}
+
+// StockSummary summarizes the stock grouped by the given dimensions, e.g.
+// "category" and "location"
+func (c *Client) StockSummary(ctx context.Context, groupBy ...string) ([]*StockSummary, error) {
+	query := url.Values{}
+	setString(query, "group_by", strings.Join(groupBy, ","))
+	return callList[*StockSummary](ctx, c, request{method: http.MethodGet, path: "/api/reports/stock-summary", query: query})
+}
+
+// MovementSummary summarizes the stock movements of a period
+func (c *Client) MovementSummary(ctx context.Context, q MovementQuery) ([]*MovementSummary, error) {
+	query := url.Values{}
+	setTime(query, "from", q.From)
+	setTime(query, "to", q.To)
+	setString(query, "granularity", q.Granularity)
+	setString(query, "product_id", q.ProductID)
+	if q.GroupByProduct {
+		query.Set("group_by", "product")
+	}
+	return callList[*MovementSummary](ctx, c, request{method: http.MethodGet, path: "/api/reports/movements", query: query})
+}
+
+// StockoutReport projects the products running out of stock within days, the
+// server's default horizon when zero
+func (c *Client) StockoutReport(ctx context.Context, days int) ([]*StockoutProjection, error) {
+	query := url.Values{}
+	if days > 0 {
+		query.Set("days", strconv.Itoa(days))
+	}
+	return callList[*StockoutProjection](ctx, c, request{method: http.MethodGet, path: "/api/reports/stockouts", query: query})
+}
+
+// ReservationAging buckets the open reservations by age
+func (c *Client) ReservationAging(ctx context.Context) ([]*ReservationAgeBucket, error) {
+	return callList[*ReservationAgeBucket](ctx, c, request{method: http.MethodGet, path: "/api/reports/reservations/aging"})
+}
+
+// StockAsOf reports the stock as it was at a time
+func (c *Client) StockAsOf(ctx context.Context, asOf time.Time) (*StockAsOfReport, error) {
+	query := url.Values{}
+	setTime(query, "as_of", asOf)
+	return call[StockAsOfReport](ctx, c, request{method: http.MethodGet, path: "/api/reports/stock", query: query})
+}
+
+// Valuation values the stock with a costing method, the server's default
+// when empty, of one product unless productID is empty
+func (c *Client) Valuation(ctx context.Context, method, productID string) (*ValuationReport, error) {
+	query := url.Values{}
+	setString(query, "method", method)
+	setString(query, "product_id", productID)
+	return call[ValuationReport](ctx, c, request{method: http.MethodGet, path: "/api/reports/valuation", query: query})
+}
+
+// ProductTrend retrieves the daily on-hand and available stock of a product
+func (c *Client) ProductTrend(ctx context.Context, productID string, q TrendQuery) (*StockTrend, error) {
+	query := url.Values{}
+	setDate(query, "from", q.From)
+	setDate(query, "to", q.To)
+	if q.Days > 0 {
+		query.Set("days", strconv.Itoa(q.Days))
+	}
+	setString(query, "fill", q.Fill)
+	return call[StockTrend](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(productID) + "/trend", query: query})
+}
+
+// BillingReport reports the billable usage of a period per tenant, of one
+// tenant unless empty
+func (c *Client) BillingReport(ctx context.Context, q PeriodQuery) (*BillingReport, error) {
+	return call[BillingReport](ctx, c, request{method: http.MethodGet, path: "/api/reports/billing", query: periodQuery(q)})
+}
+
+// ReconciliationReport retrieves the latest reconciliation of the stock with
+// the transaction log
+func (c *Client) ReconciliationReport(ctx context.Context) (*ReconciliationReport, error) {
+	return call[ReconciliationReport](ctx, c, request{method: http.MethodGet, path: "/api/reports/reconciliation"})
+}
+
+// ExportLedger exports the signed transaction ledger of the period [from,
+// to) as CSV
+func (c *Client) ExportLedger(ctx context.Context, from, to time.Time) (*AuditExport, error) {
+	resp, err := c.send(ctx, request{method: http.MethodGet, path: "/api/audit/export", query: ledgerQuery(from, to)})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, decodeResponse(resp, nil)
+	}
+
+	// The manifest follows the streamed body as trailers, which are only
+	// known once the body is read; older servers sent it as headers
+	export := &AuditExport{}
+	if export.CSV, err = io.ReadAll(resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to read ledger export: %w", err)
+	}
+	manifest := func(name string) string {
+		if value := resp.Trailer.Get(name); value != "" {
+			return value
+		}
+		return resp.Header.Get(name)
+	}
+	export.ID = manifest("X-Audit-Export-ID")
+	export.SHA256 = manifest("X-Audit-SHA256")
+	export.Signature = manifest("X-Audit-Signature")
+	if rows := manifest("X-Audit-Row-Count"); rows != "" {
+		if export.RowCount, err = strconv.Atoi(rows); err != nil {
+			return nil, fmt.Errorf("invalid ledger export row count %q: %w", rows, err)
+		}
+	}
+	return export, nil
+}
+
+// StartLedgerExport exports the transaction ledger as a background job,
+// whose summary is the export manifest
+func (c *Client) StartLedgerExport(ctx context.Context, from, to time.Time) (*Job, error) {
+	return c.startJob(ctx, request{method: http.MethodGet, path: "/api/audit/export", query: ledgerQuery(from, to)})
+}
+
+// LedgerManifest retrieves the signed manifest stored when a ledger export
+// was made, given the export's ID
+func (c *Client) LedgerManifest(ctx context.Context, exportID string) (*AuditManifest, error) {
+	return call[AuditManifest](ctx, c, request{method: http.MethodGet, path: "/api/audit/export/" + escape(exportID) + "/manifest"})
+}
+
+// AuditPublicKey retrieves the key verifying ledger export signatures
+func (c *Client) AuditPublicKey(ctx context.Context) (*AuditPublicKey, error) {
+	return call[AuditPublicKey](ctx, c, request{method: http.MethodGet, path: "/api/audit/public-key"})
+}
+
+// ledgerQuery returns the period query parameters of a ledger export
+func ledgerQuery(from, to time.Time) url.Values {
+	query := url.Values{}
+	setTime(query, "from", from)
+	setTime(query, "to", to)
+	return query
+}
+
+// periodQuery returns the tenant and date query parameters of a period
+func periodQuery(q PeriodQuery) url.Values {
+	query := url.Values{}
+	setString(query, "tenant", q.Tenant)
+	setDate(query, "from", q.From)
+	setDate(query, "to", q.To)
+	return query
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AddStock adds stock of a product
+func (c *Client) AddStock(ctx context.Context, productID string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/add", body: req}, nil)
+}
+
+// RemoveStock removes stock of a product, e.g. when it is shipped
+func (c *Client) RemoveStock(ctx context.Context, productID string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/remove", body: req}, nil)
+}
+
+// ReserveStock reserves stock of a product
+func (c *Client) ReserveStock(ctx context.Context, productID string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/reserve", body: req}, nil)
+}
+
+// UnreserveStock releases reserved stock of a product
+func (c *Client) UnreserveStock(ctx context.Context, productID string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/unreserve", body: req}, nil)
+}
+
+// TransferStock moves stock of a product between two locations
+func (c *Client) TransferStock(ctx context.Context, productID string, req TransferStockRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/transfer", body: req}, nil)
+}
+
+// AdjustStock sets the on-hand quantity of a product after a physical count
+// and returns the adjustment recorded
+func (c *Client) AdjustStock(ctx context.Context, productID string, req StockAdjustRequest) (*Transaction, error) {
+	return call[Transaction](ctx, c, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/stock/adjust",
+		body: req})
+}
+
+// SKUStock applies a stock operation (add, remove, reserve or unreserve) to
+// the product with a SKU
+func (c *Client) SKUStock(ctx context.Context, sku, op string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: "/api/sku/" + escape(sku) + "/stock/" + escape(op), body: req}, nil)
+}
+
+// ApplyStockBatch applies stock operations on many items all or nothing and
+// returns their transactions
+func (c *Client) ApplyStockBatch(ctx context.Context, items []StockBatchItem) ([]*Transaction, error) {
+	body := map[string][]StockBatchItem{"items": items}
+	return callList[*Transaction](ctx, c, request{method: http.MethodPost, path: "/api/stock/batch", body: body})
+}
+
+// GetStockLevel retrieves the stock of a product over all locations, only
+// in one condition unless empty
+func (c *Client) GetStockLevel(ctx context.Context, productID string, condition StockCondition) (*StockLevel, error) {
+	return call[StockLevel](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(productID) + "/inventory",
+		query: conditionQuery(condition)})
+}
+
+// GetInventoryAt retrieves the inventory of a product at a warehouse in a
+// condition, new stock when empty
+func (c *Client) GetInventoryAt(ctx context.Context, productID, warehouse string, condition StockCondition) (*InventoryItem, error) {
+	return call[InventoryItem](ctx, c, request{method: http.MethodGet, path: inventoryPath(productID, warehouse),
+		query: conditionQuery(condition)})
+}
+
+// ListInventory lists one page of the inventory rows of every product
+func (c *Client) ListInventory(ctx context.Context, opts InventoryListOptions) (*Page[*InventoryItem], error) {
+	query := pagination(opts.ListOptions)
+	setString(query, "filter", opts.Filter)
+	return call[Page[*InventoryItem]](ctx, c, request{method: http.MethodGet, path: "/api/inventory", query: query})
+}
+
+// CreateInventoryAt stocks a product at a new warehouse, or in a new
+// condition at a warehouse, with req.Quantity
+func (c *Client) CreateInventoryAt(ctx context.Context, productID, warehouse string, req StockOperationRequest) (*InventoryItem, error) {
+	return call[InventoryItem](ctx, c, request{method: http.MethodPost, path: inventoryPath(productID, warehouse), body: req})
+}
+
+// SetStockCount overwrites the counted quantity of a product at a
+// warehouse; a stale version is rejected with 409
+func (c *Client) SetStockCount(ctx context.Context, productID, warehouse string, req StockCountRequest) (*InventoryItem, error) {
+	return call[InventoryItem](ctx, c, request{method: http.MethodPut, path: inventoryPath(productID, warehouse), body: req})
+}
+
+// PatchInventorySettings changes the reorder level, safety stock or bin
+// location of a product at a warehouse with JSON Patch operations
+func (c *Client) PatchInventorySettings(ctx context.Context, productID, warehouse string, operations []PatchOperation) (*InventoryItem, error) {
+	return call[InventoryItem](ctx, c, request{method: http.MethodPatch, path: inventoryPath(productID, warehouse), body: operations,
+		contentType: "application/json-patch+json"})
+}
+
+// WarehouseStock applies a stock operation (add, remove, reserve or
+// unreserve) to a product at a warehouse
+func (c *Client) WarehouseStock(ctx context.Context, productID, warehouse, op string, req StockOperationRequest) error {
+	return c.do(ctx, request{method: http.MethodPost, path: inventoryPath(productID, warehouse) + "/stock/" + escape(op), body: req}, nil)
+}
+
+// CheckAvailability checks whether the quantities of many products are
+// available, e.g. at checkout
+func (c *Client) CheckAvailability(ctx context.Context, checks []AvailabilityCheck) ([]AvailabilityResult, error) {
+	body := map[string][]AvailabilityCheck{"items": checks}
+	return callList[AvailabilityResult](ctx, c, request{method: http.MethodPost, path: "/api/availability/check", body: body})
+}
+
+// BulkAvailability retrieves the available stock of many SKUs at once
+func (c *Client) BulkAvailability(ctx context.Context, skus []string) (*BulkAvailability, error) {
+	body := map[string][]string{"skus": skus}
+	return call[BulkAvailability](ctx, c, request{method: http.MethodPost, path: "/internal/availability", body: body})
+}
+
+// ListTransactions lists one page of the transactions of all products
+func (c *Client) ListTransactions(ctx context.Context, filter TransactionFilter) (*Page[*Transaction], error) {
+	return c.listTransactions(ctx, "/api/transactions", filter)
+}
+
+// ListProductTransactions lists one page of the transactions of a product
+func (c *Client) ListProductTransactions(ctx context.Context, productID string, filter TransactionFilter) (*Page[*Transaction], error) {
+	return c.listTransactions(ctx, "/api/products/"+escape(productID)+"/transactions", filter)
+}
+
+// AnnotateTransaction appends a note to a transaction
+func (c *Client) AnnotateTransaction(ctx context.Context, transactionID, note string) (*TransactionAnnotation, error) {
+	body := map[string]string{"note": note}
+	return call[TransactionAnnotation](ctx, c, request{method: http.MethodPost,
+		path: "/api/transactions/" + escape(transactionID) + "/annotations", body: body})
+}
+
+// listTransactions lists one page of the transactions at path
+func (c *Client) listTransactions(ctx context.Context, path string, filter TransactionFilter) (*Page[*Transaction], error) {
+	query := pagination(filter.ListOptions)
+	setString(query, "type", filter.Type)
+	setString(query, "reference", filter.Reference)
+	setTime(query, "from", filter.From)
+	setTime(query, "to", filter.To)
+	setBool(query, "include_archived", filter.IncludeArchived)
+	setString(query, "cursor", filter.Cursor)
+	setString(query, "filter", filter.Filter)
+	return call[Page[*Transaction]](ctx, c, request{method: http.MethodGet, path: path, query: query})
+}
+
+// inventoryPath is the path of a product's inventory at a warehouse
+func inventoryPath(productID, warehouse string) string {
+	return "/api/products/" + escape(productID) + "/inventory/" + escape(warehouse)
+}
+
+// conditionQuery returns the condition query parameter unless empty
+func conditionQuery(condition StockCondition) url.Values {
+	query := url.Values{}
+	setString(query, "condition", string(condition))
+	return query
+}
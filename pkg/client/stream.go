@@ -0,0 +1,65 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// InventoryEventHandler handles one event of the inventory stream: a
+// "snapshot" of each product streamed when it starts, then a "change" after
+// each stock movement. An error stops the stream and is returned.
+type InventoryEventHandler func(name string, event *InventoryEvent) error
+
+// StreamInventory streams the stock changes of the given products, of all of
+// them when none are given, until ctx is done, the server ends the stream or
+// handle fails. The stream outlives the timeout of the default HTTP client:
+// pass one without a timeout with WithHTTPClient.
+func (c *Client) StreamInventory(ctx context.Context, productIDs []string, handle InventoryEventHandler) error {
+	query := url.Values{}
+	setString(query, "product_ids", strings.Join(productIDs, ","))
+	body, err := c.stream(ctx, request{method: http.MethodGet, path: "/api/stream/inventory", query: query,
+		header: http.Header{"Accept": {"text/event-stream"}}})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	var name string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				event := &InventoryEvent{}
+				if err := json.Unmarshal([]byte(data.String()), event); err != nil {
+					return fmt.Errorf("failed to decode %s event: %w", name, err)
+				}
+				if err := handle(name, event); err != nil {
+					return err
+				}
+			}
+			name = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Heartbeat comments only keep the connection open
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("inventory stream failed: %w", err)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,516 @@
+package client
+
+import (
+	"time"
+
+	"github.com/bhnrathore/distributed-inventory-system/internal/domain"
+)
+
+// The API's resources, as the server defines them
+type (
+	Page[T any] = domain.Page[T]
+
+	AdvanceShippingNotice       = domain.AdvanceShippingNotice
+	BackgroundStatus            = domain.BackgroundStatus
+	Backorder                   = domain.Backorder
+	BackorderStatus             = domain.BackorderStatus
+	BillingReport               = domain.BillingReport
+	BulkAvailability            = domain.BulkAvailability
+	CartHold                    = domain.CartHold
+	CartHoldLine                = domain.CartHoldLine
+	CatalogDiff                 = domain.CatalogDiff
+	CatalogEntry                = domain.CatalogEntry
+	ChannelAllocation           = domain.ChannelAllocation
+	ChannelAllocationEvent      = domain.ChannelAllocationEvent
+	ChannelAvailability         = domain.ChannelAvailability
+	Consignment                 = domain.Consignment
+	ConsignmentRequest          = domain.ConsignmentRequest
+	ConsignmentSettlement       = domain.ConsignmentSettlement
+	DashboardStats              = domain.DashboardStats
+	DataQualityReport           = domain.DataQualityReport
+	FulfillmentPlan             = domain.FulfillmentPlan
+	IntercompanyTransfer        = domain.IntercompanyTransfer
+	IntercompanyTransferRequest = domain.IntercompanyTransferRequest
+	InventoryEvent              = domain.InventoryEvent
+	InventoryItem               = domain.InventoryItem
+	Job                         = domain.Job
+	MovementSummary             = domain.MovementSummary
+	Order                       = domain.Order
+	OrderReservation            = domain.OrderReservation
+	OrderReservationLine        = domain.OrderReservationLine
+	OrderStatus                 = domain.OrderStatus
+	OwnershipTransfer           = domain.OwnershipTransfer
+	PatchOperation              = domain.PatchOperation
+	PayloadSample               = domain.PayloadSample
+	Product                     = domain.Product
+	ProductArchiveFilter        = domain.ProductArchiveFilter
+	ProductArchivePreview       = domain.ProductArchivePreview
+	ProductArchiveResult        = domain.ProductArchiveResult
+	ProductImportReport         = domain.ProductImportReport
+	ProductImportRow            = domain.ProductImportRow
+	ProductTranslation          = domain.ProductTranslation
+	ProductVariant              = domain.ProductVariant
+	PurchaseOrder               = domain.PurchaseOrder
+	PurchaseOrderLine           = domain.PurchaseOrderLine
+	PurchaseOrderStatus         = domain.PurchaseOrderStatus
+	QuantityLine                = domain.QuantityLine
+	Readiness                   = domain.Readiness
+	ReconciliationReport        = domain.ReconciliationReport
+	Redaction                   = domain.Redaction
+	ReorderSuggestion           = domain.ReorderSuggestion
+	Reservation                 = domain.Reservation
+	ReservationAgeBucket        = domain.ReservationAgeBucket
+	ReservationAllocation       = domain.ReservationAllocation
+	ReservationPolicy           = domain.ReservationPolicy
+	ReservationSplit            = domain.ReservationSplit
+	Sale                        = domain.Sale
+	SalesChannel                = domain.SalesChannel
+	SalesChannelType            = domain.SalesChannelType
+	SerialStatus                = domain.SerialStatus
+	SerialUnit                  = domain.SerialUnit
+	SerialUnitEvent             = domain.SerialUnitEvent
+	SimulationOperation         = domain.SimulationOperation
+	SimulationResult            = domain.SimulationResult
+	StockAlert                  = domain.StockAlert
+	StockAlertStatus            = domain.StockAlertStatus
+	StockAsOfReport             = domain.StockAsOfReport
+	StockBatchItem              = domain.StockBatchItem
+	StockCondition              = domain.StockCondition
+	StockLevel                  = domain.StockLevel
+	StockSummary                = domain.StockSummary
+	StockTrend                  = domain.StockTrend
+	StockUpdateError            = domain.StockUpdateError
+	StockoutProjection          = domain.StockoutProjection
+	Stocktake                   = domain.Stocktake
+	SupplierPurchaseOrder       = domain.SupplierPurchaseOrder
+	Transaction                 = domain.Transaction
+	TransactionAnnotation       = domain.TransactionAnnotation
+	TransferLane                = domain.TransferLane
+	UsageReport                 = domain.UsageReport
+	ValuationReport             = domain.ValuationReport
+	VariantRollup               = domain.VariantRollup
+	WMSDeadLetter               = domain.WMSDeadLetter
+	Warehouse                   = domain.Warehouse
+)
+
+// ListOptions selects a page of a listing; zero values use the server's
+// defaults (10 items from the first)
+type ListOptions struct {
+	Limit  int
+	Offset int
+}
+
+// ProductDetails is a product with its inventory at the default location
+type ProductDetails struct {
+	Product   *Product       `json:"product"`
+	Inventory *InventoryItem `json:"inventory"`
+}
+
+// ProductListOptions selects the products listed
+type ProductListOptions struct {
+	ListOptions
+	IncludeArchived bool
+	// Include names related data to embed, e.g. "variants"
+	Include []string
+	// Filter is a filter expression, e.g. `price > 100 AND category = "desks"`
+	Filter string
+}
+
+// InventoryListOptions selects the inventory rows listed
+type InventoryListOptions struct {
+	ListOptions
+	// Filter is a filter expression, e.g. `available < 10 AND location = "A"`
+	Filter string
+}
+
+// CreateProductRequest creates a product, stocked with InitialQuantity at
+// Location, the default location when empty
+type CreateProductRequest struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	SKU             string  `json:"sku"`
+	Category        string  `json:"category"`
+	Price           float64 `json:"price"`
+	Location        string  `json:"location"`
+	InitialQuantity int64   `json:"initial_quantity"`
+}
+
+// UpdateProductRequest updates a product. UpdateProduct replaces every
+// field, clearing the nil ones; PatchProduct only changes the non-nil ones.
+type UpdateProductRequest struct {
+	Name        *string  `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Category    *string  `json:"category,omitempty"`
+	Price       *float64 `json:"price,omitempty"`
+}
+
+// StockOperationRequest moves stock of a product at a location, the default
+// location when empty
+type StockOperationRequest struct {
+	Location  string         `json:"location,omitempty"`
+	Condition StockCondition `json:"condition,omitempty"`
+	Quantity  int64          `json:"quantity"`
+	Reference string         `json:"reference,omitempty"`
+	Notes     string         `json:"notes,omitempty"`
+
+	// UnitCost is the cost of one unit added; only stock additions accept it
+	UnitCost *float64 `json:"unit_cost,omitempty"`
+
+	// ExpectedAvailable makes removals and reservations conditional: they
+	// are rejected with 412 unless at least this much stock remains
+	// available afterwards
+	ExpectedAvailable *int64 `json:"expected_available,omitempty"`
+}
+
+// TransferStockRequest moves stock between two locations
+type TransferStockRequest struct {
+	FromLocation string `json:"from_location"`
+	ToLocation   string `json:"to_location"`
+	Quantity     int64  `json:"quantity"`
+	Reference    string `json:"reference,omitempty"`
+}
+
+// StockCountRequest sets the counted quantity of an inventory item; Version
+// must be the version the count was based on
+type StockCountRequest struct {
+	Quantity  int64  `json:"quantity"`
+	Version   int64  `json:"version"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// StockAdjustRequest sets the counted on-hand quantity of a product at a
+// location, the default location when empty
+type StockAdjustRequest struct {
+	Quantity   int64  `json:"quantity"`
+	Location   string `json:"location,omitempty"`
+	ReasonCode string `json:"reason_code"`
+	Reference  string `json:"reference,omitempty"`
+}
+
+// AvailabilityCheck asks whether a quantity of a product is available
+type AvailabilityCheck struct {
+	ProductID string         `json:"product_id"`
+	Condition StockCondition `json:"condition,omitempty"`
+	Quantity  int64          `json:"quantity"`
+}
+
+// AvailabilityResult answers an AvailabilityCheck
+type AvailabilityResult struct {
+	ProductID         string         `json:"product_id"`
+	Condition         StockCondition `json:"condition,omitempty"`
+	Requested         int64          `json:"requested"`
+	Available         int64          `json:"available"`
+	Sufficient        bool           `json:"sufficient"`
+	EstimatedShipDate string         `json:"estimated_ship_date,omitempty"`
+	ShipsFrom         []string       `json:"ships_from,omitempty"`
+	ReleaseAt         *time.Time     `json:"release_at,omitempty"`
+	Error             string         `json:"error,omitempty"`
+}
+
+// TransactionFilter selects the transactions listed; zero values do not
+// filter
+type TransactionFilter struct {
+	ListOptions
+	Type      string
+	Reference string
+	From      time.Time
+	To        time.Time
+	// IncludeArchived also lists transactions moved to the archive
+	IncludeArchived bool
+	// Cursor continues a listing from the NextCursor of its previous page
+	Cursor string
+	// Filter is a filter expression, e.g. `quantity > 100 AND type = OUT`
+	Filter string
+}
+
+// ProductTranslationResult is a saved product translation
+type ProductTranslationResult struct {
+	Locale      string             `json:"locale"`
+	Translation ProductTranslation `json:"translation"`
+}
+
+// CreateVariantRequest creates a variant of a product; the name and price
+// default to the parent's
+type CreateVariantRequest struct {
+	SKU             string            `json:"sku"`
+	Name            string            `json:"name,omitempty"`
+	Price           *float64          `json:"price,omitempty"`
+	Attributes      map[string]string `json:"attributes"`
+	Location        string            `json:"location,omitempty"`
+	InitialQuantity int64             `json:"initial_quantity,omitempty"`
+}
+
+// productArchiveRequest previews or archives the products matching a filter
+type productArchiveRequest struct {
+	ProductArchiveFilter
+	Apply         bool `json:"apply"`
+	ExpectedCount *int `json:"expected_count,omitempty"`
+}
+
+// ProductExportOptions selects the products exported
+type ProductExportOptions struct {
+	// Format is ndjson (the default) or csv
+	Format          string
+	Category        string
+	IncludeArchived bool
+	UpdatedSince    time.Time
+}
+
+// CreateReservationRequest reserves stock of a product for TTLSeconds, the
+// server's default when zero
+type CreateReservationRequest struct {
+	ProductID  string         `json:"product_id"`
+	Location   string         `json:"location,omitempty"`
+	Condition  StockCondition `json:"condition,omitempty"`
+	Quantity   int64          `json:"quantity"`
+	Reference  string         `json:"reference,omitempty"`
+	TTLSeconds int64          `json:"ttl_seconds,omitempty"`
+}
+
+// ReserveManyRequest reserves the lines of an order under a policy
+type ReserveManyRequest struct {
+	Reference  string                 `json:"reference"`
+	Policy     ReservationPolicy      `json:"policy,omitempty"`
+	Lines      []OrderReservationLine `json:"lines"`
+	TTLSeconds int64                  `json:"ttl_seconds,omitempty"`
+}
+
+// CreateOrderRequest reserves the lines of an order
+type CreateOrderRequest struct {
+	ID         string                 `json:"id"`
+	Lines      []OrderReservationLine `json:"lines"`
+	TTLSeconds int64                  `json:"ttl_seconds,omitempty"`
+}
+
+// CreateCartHoldRequest holds the lines of a cart
+type CreateCartHoldRequest struct {
+	Reference  string         `json:"reference"`
+	Lines      []CartHoldLine `json:"lines"`
+	TTLSeconds int64          `json:"ttl_seconds,omitempty"`
+}
+
+// SaleFilter selects the sales listed; zero values do not filter
+type SaleFilter struct {
+	ListOptions
+	Location string
+	From     time.Time
+	To       time.Time
+}
+
+// ConsignmentFilter selects a page of consignment lots; empty fields do not
+// filter, and Open selects the lots the supplier still owns units of
+type ConsignmentFilter struct {
+	ListOptions
+	Supplier  string
+	ProductID string
+	Location  string
+	Open      bool
+}
+
+// SettlementQuery selects the payables settled for the dates [From, To]; To
+// defaults to today and From to the first day of its month
+type SettlementQuery struct {
+	Supplier string
+	From     time.Time
+	To       time.Time
+}
+
+// CreateChannelRequest creates a sales channel
+type CreateChannelRequest struct {
+	Name string           `json:"name"`
+	Type SalesChannelType `json:"type"`
+}
+
+// ChannelAllocationRequest allocates a percentage or a fixed quantity of a
+// product's stock to a channel
+type ChannelAllocationRequest struct {
+	Percent  *float64 `json:"percent,omitempty"`
+	Quantity *int64   `json:"quantity,omitempty"`
+}
+
+// ChannelReservationRequest reserves stock of a product from a channel's
+// allocation
+type ChannelReservationRequest struct {
+	ProductID  string         `json:"product_id"`
+	Location   string         `json:"location,omitempty"`
+	Condition  StockCondition `json:"condition,omitempty"`
+	Quantity   int64          `json:"quantity"`
+	Reference  string         `json:"reference,omitempty"`
+	TTLSeconds int64          `json:"ttl_seconds,omitempty"`
+}
+
+// ChannelRebalance is the outcome of rebalancing the channel buckets
+type ChannelRebalance struct {
+	Checked  int                       `json:"checked"`
+	Released int64                     `json:"released"`
+	Events   []*ChannelAllocationEvent `json:"events"`
+}
+
+// WarehouseRequest creates or updates a warehouse; the code cannot be
+// changed
+type WarehouseRequest struct {
+	Code         string   `json:"code,omitempty"`
+	Name         string   `json:"name"`
+	Address      string   `json:"address,omitempty"`
+	Timezone     string   `json:"timezone,omitempty"`
+	CutoffTime   string   `json:"cutoff_time,omitempty"`
+	LeadTimeDays *int     `json:"lead_time_days,omitempty"`
+	WorkingDays  []string `json:"working_days,omitempty"`
+	Holidays     []string `json:"holidays,omitempty"`
+}
+
+// SerialStatusRequest moves a serial unit to a new status
+type SerialStatusRequest struct {
+	Status    SerialStatus `json:"status"`
+	Reference string       `json:"reference,omitempty"`
+	Notes     string       `json:"notes,omitempty"`
+}
+
+// ReplenishmentOptions overrides the server's replenishment policy when set
+type ReplenishmentOptions struct {
+	LeadTimeDays *int
+	CoverDays    *int
+}
+
+// CreatePurchaseOrderRequest creates a draft purchase order of the given
+// lines or, with FromSuggestions, of the current reorder suggestions
+type CreatePurchaseOrderRequest struct {
+	Supplier        string              `json:"supplier"`
+	Notes           string              `json:"notes,omitempty"`
+	Lines           []PurchaseOrderLine `json:"lines,omitempty"`
+	FromSuggestions bool                `json:"from_suggestions,omitempty"`
+	LeadTimeDays    *int                `json:"lead_time_days,omitempty"`
+	CoverDays       *int                `json:"cover_days,omitempty"`
+}
+
+// StockAlertRun is the outcome of evaluating the stock alerts
+type StockAlertRun struct {
+	Checked  int           `json:"checked"`
+	Raised   []*StockAlert `json:"raised"`
+	Resolved []*StockAlert `json:"resolved"`
+}
+
+// ConfirmPurchaseOrderRequest confirms a purchase order as its supplier
+type ConfirmPurchaseOrderRequest struct {
+	PromisedAt time.Time      `json:"promised_delivery_at"`
+	Lines      []QuantityLine `json:"lines,omitempty"`
+}
+
+// ShippingNoticeRequest announces a shipment of a purchase order as its
+// supplier
+type ShippingNoticeRequest struct {
+	Reference      string         `json:"reference"`
+	Carrier        string         `json:"carrier,omitempty"`
+	TrackingNumber string         `json:"tracking_number,omitempty"`
+	ShippedAt      *time.Time     `json:"shipped_at,omitempty"`
+	ExpectedAt     *time.Time     `json:"expected_at,omitempty"`
+	Lines          []QuantityLine `json:"lines"`
+}
+
+// OpenStocktakeRequest opens a stocktake of a location
+type OpenStocktakeRequest struct {
+	Location string `json:"location"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// AuditManifest describes and signs a ledger export
+type AuditManifest struct {
+	ID          string    `json:"id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generated_at"`
+	RowCount    int       `json:"row_count"`
+	SHA256      string    `json:"sha256"`
+	Algorithm   string    `json:"algorithm"`
+	PublicKey   string    `json:"public_key"`
+	Signature   string    `json:"signature"`
+}
+
+// AuditExport is a signed ledger export
+type AuditExport struct {
+	CSV []byte
+	// ID identifies the export's manifest, see Client.LedgerManifest
+	ID string
+	// SHA256 and Signature are those of the export's manifest
+	SHA256    string
+	RowCount  int
+	Signature string
+}
+
+// AuditPublicKey is the key verifying ledger export signatures
+type AuditPublicKey struct {
+	Algorithm string `json:"algorithm"`
+	// PublicKey is base64 encoded
+	PublicKey string `json:"public_key"`
+}
+
+// MovementQuery selects the movements summarized over [From, To)
+type MovementQuery struct {
+	From time.Time
+	To   time.Time
+	// Granularity is day, week or month
+	Granularity string
+	ProductID   string
+	// GroupByProduct summarizes each product on its own
+	GroupByProduct bool
+}
+
+// TrendQuery selects the days of a stock trend: From through To, or the Days
+// ending at To; To defaults to today and Days to 30
+type TrendQuery struct {
+	From time.Time
+	To   time.Time
+	Days int
+	// Fill picks how days without snapshots are filled
+	Fill string
+}
+
+// PeriodQuery selects the dates [From, To]; To defaults to today and From to
+// the first day of its month
+type PeriodQuery struct {
+	Tenant string
+	From   time.Time
+	To     time.Time
+}
+
+// SimulateRequest simulates stock operations without applying them
+type SimulateRequest struct {
+	Operations []SimulationOperation `json:"operations"`
+}
+
+// FulfillmentPlanRequest plans shipping a quantity of a product to a
+// destination
+type FulfillmentPlanRequest struct {
+	ProductID    string  `json:"product_id"`
+	Destination  string  `json:"destination"`
+	Quantity     int64   `json:"quantity"`
+	MaxLeadHours float64 `json:"max_lead_hours,omitempty"`
+}
+
+// CatalogSyncRequest compares the products with a catalog and, with Apply,
+// creates, updates and archives products to match it
+type CatalogSyncRequest struct {
+	Products []CatalogEntry `json:"products"`
+	Apply    bool           `json:"apply"`
+	Location string         `json:"location,omitempty"`
+}
+
+// BackgroundRequest pauses or resumes background components, all of them
+// when none are named
+type BackgroundRequest struct {
+	Components []string `json:"components,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// SystemLoad is the current write load of the server
+type SystemLoad struct {
+	Status              string `json:"status"`
+	InFlightWrites      int64  `json:"in_flight_writes"`
+	MaxConcurrentWrites int    `json:"max_concurrent_writes"`
+	QueuedWrites        int64  `json:"queued_writes"`
+	MaxQueuedWrites     int    `json:"max_queued_writes"`
+	RejectedWrites      int64  `json:"rejected_writes"`
+	BatchQueueRows      int    `json:"batch_queue_rows"`
+	BatchQueueDeltas    int    `json:"batch_queue_deltas"`
+	RetryAfterMs        int64  `json:"retry_after_ms,omitempty"`
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateWarehouse creates a warehouse
+func (c *Client) CreateWarehouse(ctx context.Context, req WarehouseRequest) (*Warehouse, error) {
+	return call[Warehouse](ctx, c, request{method: http.MethodPost, path: "/api/warehouses", body: req})
+}
+
+// GetWarehouse retrieves a warehouse
+func (c *Client) GetWarehouse(ctx context.Context, id string) (*Warehouse, error) {
+	return call[Warehouse](ctx, c, request{method: http.MethodGet, path: "/api/warehouses/" + escape(id)})
+}
+
+// UpdateWarehouse replaces the settings of a warehouse
+func (c *Client) UpdateWarehouse(ctx context.Context, id string, req WarehouseRequest) (*Warehouse, error) {
+	return call[Warehouse](ctx, c, request{method: http.MethodPut, path: "/api/warehouses/" + escape(id), body: req})
+}
+
+// ListWarehouses lists one page of the warehouses
+func (c *Client) ListWarehouses(ctx context.Context, opts ListOptions) ([]*Warehouse, error) {
+	return callList[*Warehouse](ctx, c, request{method: http.MethodGet, path: "/api/warehouses", query: pagination(opts)})
+}
+
+// RegisterSerial registers a serial-numbered unit of a product
+func (c *Client) RegisterSerial(ctx context.Context, productID, serialNumber string) (*SerialUnit, error) {
+	body := map[string]string{"serial_number": serialNumber}
+	return call[SerialUnit](ctx, c, request{method: http.MethodPost, path: "/api/products/" + escape(productID) + "/serials", body: body})
+}
+
+// GetSerial retrieves a serial-numbered unit of a product
+func (c *Client) GetSerial(ctx context.Context, productID, serialNumber string) (*SerialUnit, error) {
+	return call[SerialUnit](ctx, c, request{method: http.MethodGet, path: serialPath(productID, serialNumber)})
+}
+
+// ListSerials lists one page of the serial-numbered units of a product
+func (c *Client) ListSerials(ctx context.Context, productID string, opts ListOptions) ([]*SerialUnit, error) {
+	return callList[*SerialUnit](ctx, c, request{method: http.MethodGet, path: "/api/products/" + escape(productID) + "/serials",
+		query: pagination(opts)})
+}
+
+// UpdateSerialStatus moves a serial-numbered unit to a new status
+func (c *Client) UpdateSerialStatus(ctx context.Context, productID, serialNumber string, req SerialStatusRequest) (*SerialUnit, error) {
+	return call[SerialUnit](ctx, c, request{method: http.MethodPost, path: serialPath(productID, serialNumber) + "/status", body: req})
+}
+
+// GetSerialHistory lists one page of the status changes of a serial-numbered
+// unit
+func (c *Client) GetSerialHistory(ctx context.Context, productID, serialNumber string, opts ListOptions) ([]*SerialUnitEvent, error) {
+	return callList[*SerialUnitEvent](ctx, c, request{method: http.MethodGet, path: serialPath(productID, serialNumber) + "/history",
+		query: pagination(opts)})
+}
+
+// OpenStocktake opens a stocktake, freezing the movements of its location
+func (c *Client) OpenStocktake(ctx context.Context, req OpenStocktakeRequest) (*Stocktake, error) {
+	return call[Stocktake](ctx, c, request{method: http.MethodPost, path: "/api/stocktakes", body: req})
+}
+
+// GetStocktake retrieves a stocktake
+func (c *Client) GetStocktake(ctx context.Context, id string) (*Stocktake, error) {
+	return call[Stocktake](ctx, c, request{method: http.MethodGet, path: "/api/stocktakes/" + escape(id)})
+}
+
+// CloseStocktake closes a stocktake, applying the movements queued meanwhile
+func (c *Client) CloseStocktake(ctx context.Context, id string) (*Stocktake, error) {
+	return call[Stocktake](ctx, c, request{method: http.MethodPost, path: "/api/stocktakes/" + escape(id) + "/close"})
+}
+
+// ListStocktakes lists one page of the stocktakes
+func (c *Client) ListStocktakes(ctx context.Context, opts ListOptions) ([]*Stocktake, error) {
+	return callList[*Stocktake](ctx, c, request{method: http.MethodGet, path: "/api/stocktakes", query: pagination(opts)})
+}
+
+// ListTransferLanes lists the transfer lanes between locations
+func (c *Client) ListTransferLanes(ctx context.Context) ([]TransferLane, error) {
+	return callList[TransferLane](ctx, c, request{method: http.MethodGet, path: "/api/routing/lanes"})
+}
+
+// PlanFulfillment plans shipping a quantity of a product to a destination
+func (c *Client) PlanFulfillment(ctx context.Context, req FulfillmentPlanRequest) (*FulfillmentPlan, error) {
+	return call[FulfillmentPlan](ctx, c, request{method: http.MethodPost, path: "/api/routing/plan", body: req})
+}
+
+// serialPath is the path of a serial-numbered unit of a product
+func serialPath(productID, serialNumber string) string {
+	return "/api/products/" + escape(productID) + "/serials/" + escape(serialNumber)
+}